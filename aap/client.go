@@ -0,0 +1,205 @@
+package aap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// TokenKey is the key of the OAuth token in the credentials Secret
+	TokenKey = "token"
+	// URLKey is the key of the URL of the automation controller the token is meant for in the
+	// credentials Secret
+	URLKey = "url"
+
+	jobTemplatesPath = "/api/v2/job_templates"
+	jobsPath         = "/api/v2/jobs"
+)
+
+// statuses of AAP jobs
+const (
+	JobStatusSuccessful = "successful"
+	JobStatusFailed     = "failed"
+	JobStatusError      = "error"
+	JobStatusCanceled   = "canceled"
+)
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// ErrNotFound is returned when the AAP API responds by 404
+var ErrNotFound = errors.New("not found")
+
+// Client of the automation controller API of Ansible Automation Platform
+type Client struct {
+	url   string
+	token string
+}
+
+type Job struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+	// Explanation of the status, ie why the job could not be started
+	JobExplanation string `json:"job_explanation,omitempty"`
+	// When the job finished, not set while it runs
+	FinishedAt *time.Time `json:"finished,omitempty"`
+}
+
+type launchRequest struct {
+	ExtraVars map[string]interface{} `json:"extra_vars,omitempty"`
+}
+
+type launchResponse struct {
+	Job int `json:"job"`
+}
+
+type jobsResponse struct {
+	Results []Job `json:"results"`
+}
+
+type cancelResponse struct {
+	CanCancel bool `json:"can_cancel"`
+}
+
+type apiError struct {
+	Detail string `json:"detail"`
+}
+
+// NewClient returns client of the AAP API authenticated by the token read from a Secret. The
+// token is sent only to the URL the credentials are meant for.
+func NewClient(apiURL string, credentials map[string][]byte) (*Client, error) {
+	token := credentials[TokenKey]
+	if len(token) == 0 {
+		return nil, fmt.Errorf("credentials have no '%s'", TokenKey)
+	}
+	apiURL = strings.TrimSuffix(apiURL, "/")
+	if strings.TrimSuffix(string(credentials[URLKey]), "/") != apiURL {
+		return nil, fmt.Errorf("credentials are not meant for %s", apiURL)
+	}
+	return &Client{
+		url:   apiURL,
+		token: string(token),
+	}, nil
+}
+
+// LaunchJobTemplate launches the job template of the ID and returns ID of the new job. Extra
+// variables are ignored by AAP unless the job template prompts for them on launch.
+func (c *Client) LaunchJobTemplate(
+	ctx context.Context,
+	id int,
+	extraVars map[string]interface{},
+) (int, error) {
+	resp := launchResponse{}
+	path := fmt.Sprintf("%s/%d/launch/", jobTemplatesPath, id)
+	req := launchRequest{ExtraVars: extraVars}
+	if err := c.do(ctx, http.MethodPost, path, req, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Job, nil
+}
+
+// FindJob returns the latest job of the job template whose extra variables contain the value, nil
+// if there is none
+func (c *Client) FindJob(ctx context.Context, templateID int, value string) (*Job, error) {
+	query := url.Values{}
+	query.Set("extra_vars__contains", value)
+	query.Set("order_by", "-id")
+	query.Set("page_size", "1")
+	path := fmt.Sprintf("%s/%d/jobs/?%s", jobTemplatesPath, templateID, query.Encode())
+	resp := jobsResponse{}
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Results) == 0 {
+		return nil, nil
+	}
+	return &resp.Results[0], nil
+}
+
+// GetJob returns the job of the ID
+func (c *Client) GetJob(ctx context.Context, id int) (*Job, error) {
+	job := &Job{}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%d/", jobsPath, id), nil, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// CancelJob cancels the job of the ID, nothing is done if the job can not be canceled because it
+// finished already
+func (c *Client) CancelJob(ctx context.Context, id int) error {
+	path := fmt.Sprintf("%s/%d/cancel/", jobsPath, id)
+	resp := cancelResponse{}
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return err
+	}
+	if !resp.CanCancel {
+		return nil
+	}
+	return c.do(ctx, http.MethodPost, path, nil, nil)
+}
+
+// Finished returns true if the job does not run anymore
+func (j *Job) Finished() bool {
+	switch j.Status {
+	case JobStatusSuccessful, JobStatusFailed, JobStatusError, JobStatusCanceled:
+		return true
+	}
+	return false
+}
+
+func (c *Client) do(
+	ctx context.Context,
+	method string,
+	path string,
+	body interface{},
+	result interface{},
+) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %s - %w", method, path, ErrNotFound)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := apiError{}
+		err := fmt.Errorf("%s %s responded with status code %d", method, path, resp.StatusCode)
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Detail != "" {
+			return fmt.Errorf("%w - %s", err, apiErr.Detail)
+		}
+		return err
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
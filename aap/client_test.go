@@ -0,0 +1,126 @@
+package aap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AAP client", func() {
+	var server *httptest.Server
+	var launched launchRequest
+	var canceled bool
+
+	BeforeEach(func() {
+		launched = launchRequest{}
+		canceled = false
+		mux := http.NewServeMux()
+		mux.HandleFunc(jobTemplatesPath+"/7/launch/", func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).Should(Equal(http.MethodPost))
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"detail":"Authentication credentials were not provided."}`))
+				return
+			}
+			Expect(json.NewDecoder(r.Body).Decode(&launched)).Should(Succeed())
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"job":42,"id":42,"status":"pending"}`))
+		})
+		mux.HandleFunc(jobTemplatesPath+"/7/jobs/", func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).Should(Equal(http.MethodGet))
+			if r.URL.Query().Get("extra_vars__contains") != "launch-1" {
+				_, _ = w.Write([]byte(`{"count":0,"results":[]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"count":1,"results":[{"id":42,"status":"running"}]}`))
+		})
+		mux.HandleFunc(jobsPath+"/42/", func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).Should(Equal(http.MethodGet))
+			_, _ = w.Write([]byte(`{"id":42,"status":"failed","job_explanation":"Playbook failed"}`))
+		})
+		mux.HandleFunc(jobsPath+"/42/cancel/", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`{"can_cancel":true}`))
+				return
+			}
+			Expect(r.Method).Should(Equal(http.MethodPost))
+			canceled = true
+			w.WriteHeader(http.StatusAccepted)
+		})
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newClient := func(token string) *Client {
+		client, err := NewClient(server.URL+"/", map[string][]byte{
+			TokenKey: []byte(token),
+			URLKey:   []byte(server.URL),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		return client
+	}
+
+	It("Requires token", func() {
+		_, err := NewClient(server.URL, map[string][]byte{})
+		Expect(err).Should(MatchError("credentials have no 'token'"))
+	})
+
+	It("Requires credentials meant for the URL", func() {
+		_, err := NewClient("https://aap.example.com", map[string][]byte{
+			TokenKey: []byte("token"),
+			URLKey:   []byte(server.URL),
+		})
+		Expect(err).Should(MatchError("credentials are not meant for https://aap.example.com"))
+	})
+
+	It("Finds jobs by extra variables", func() {
+		client := newClient("token")
+		job, err := client.FindJob(context.TODO(), 7, "launch-1")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(job.ID).Should(Equal(42))
+
+		job, err = client.FindJob(context.TODO(), 7, "launch-2")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(job).Should(BeNil())
+	})
+
+	It("Launches job templates", func() {
+		client := newClient("token")
+		id, err := client.LaunchJobTemplate(
+			context.TODO(),
+			7,
+			map[string]interface{}{"cluster_kubeconfig": "kubeconfig"},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(id).Should(Equal(42))
+		Expect(launched.ExtraVars).Should(HaveKeyWithValue("cluster_kubeconfig", "kubeconfig"))
+
+		job, err := client.GetJob(context.TODO(), 42)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(job.Status).Should(Equal(JobStatusFailed))
+		Expect(job.JobExplanation).Should(Equal("Playbook failed"))
+		Expect(job.Finished()).Should(BeTrue())
+
+		_, err = client.GetJob(context.TODO(), 43)
+		Expect(errors.Is(err, ErrNotFound)).Should(BeTrue())
+
+		Expect(client.CancelJob(context.TODO(), 42)).Should(Succeed())
+		Expect(canceled).Should(BeTrue())
+	})
+
+	It("Reports errors", func() {
+		_, err := newClient("wrong").LaunchJobTemplate(context.TODO(), 7, nil)
+		Expect(err).Should(MatchError(
+			"POST " + jobTemplatesPath + "/7/launch/ responded with status code 401 - " +
+				"Authentication credentials were not provided.",
+		))
+	})
+})
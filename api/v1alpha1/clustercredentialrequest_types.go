@@ -0,0 +1,84 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterCredentialRequestSpec defines the desired state of ClusterCredentialRequest
+type ClusterCredentialRequestSpec struct {
+	// Name of the ClusterTemplateInstance (in the same namespace) whose credentials are requested
+	ClusterTemplateInstanceRef string `json:"clusterTemplateInstanceRef"`
+	// +optional
+	// How long the requester can read the credentials, ie '8h'. The copy of the credentials and the access to it are deleted once it elapses. Defaults to 1 hour
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// ClusterCredentialRequestStatus defines the observed state of ClusterCredentialRequest
+type ClusterCredentialRequestStatus struct {
+	// +optional
+	// Secret with the kubeconfig (key "kubeconfig") and admin credentials (keys "username" and
+	// "password") of the cluster, readable by the requester only
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Credentials *corev1.LocalObjectReference `json:"credentials,omitempty"`
+	// +optional
+	// Time when the credentials were issued
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	IssueTime *metav1.Time `json:"issueTime,omitempty"`
+	// +optional
+	// Time when the copy of the credentials and the access to it are deleted
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+	// +optional
+	// Reason why the credentials are not issued yet or were revoked
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=clustercredentialrequests,shortName=ccr;ccrs,scope=Namespaced
+//+kubebuilder:printcolumn:name="Instance",type="string",JSONPath=".spec.clusterTemplateInstanceRef",description="ClusterTemplateInstance"
+//+kubebuilder:printcolumn:name="Requester",type="string",JSONPath=".metadata.annotations.clustertemplates\\.openshift\\.io/requester",description="Requester"
+//+kubebuilder:printcolumn:name="Credentials",type="string",JSONPath=".status.credentials.name",description="Credentials Secret"
+//+kubebuilder:printcolumn:name="Expiration",type="string",JSONPath=".status.expirationTime",description="Expiration of the credentials"
+//+operator-sdk:csv:customresourcedefinitions:displayName="Cluster credential request",resources={{Secret, v1, ""}}
+
+// Requests credentials of a cluster. The request is recorded in the access log of the
+// ClusterTemplateInstance and the credentials are copied to a Secret readable by the requester only
+type ClusterCredentialRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterCredentialRequestSpec   `json:"spec"`
+	Status ClusterCredentialRequestStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterCredentialRequestList contains a list of ClusterCredentialRequest
+type ClusterCredentialRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterCredentialRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterCredentialRequest{}, &ClusterCredentialRequestList{})
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var clustercredentialrequestlog = logf.Log.WithName("clustercredentialrequest-resource")
+
+func (r *ClusterCredentialRequest) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(ccrWebhook).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-clustertemplate-openshift-io-v1alpha1-clustercredentialrequest,mutating=true,failurePolicy=fail,sideEffects=None,groups=clustertemplate.openshift.io,resources=clustercredentialrequests,verbs=create;update,versions=v1alpha1,name=mclustercredentialrequest.kb.io,admissionReviewVersions=v1
+
+var ccrWebhook webhook.CustomDefaulter = &ClusterCredentialRequest{}
+
+// Default records the requester, so it cannot be set by the user. The requester annotation
+// is overwritten on update too, the credentials are issued to the last user who modified it.
+func (r *ClusterCredentialRequest) Default(ctx context.Context, obj runtime.Object) error {
+	ccr := obj.(*ClusterCredentialRequest)
+	clustercredentialrequestlog.Info("default", "name", ccr.Name)
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if ccr.Annotations == nil {
+		ccr.Annotations = map[string]string{}
+	}
+	ccr.Annotations[CTIRequesterAnnotation] = req.UserInfo.Username
+	return nil
+}
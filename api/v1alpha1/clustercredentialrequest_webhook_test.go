@@ -0,0 +1,35 @@
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("ClusterCredentialRequest mutating webhook", func() {
+	It("Overwrites requester", func() {
+		ccr := &ClusterCredentialRequest{
+			ObjectMeta: v1.ObjectMeta{
+				Namespace: "foo",
+				Annotations: map[string]string{
+					CTIRequesterAnnotation: "admin",
+				},
+			},
+		}
+		webhookCtx := admission.NewContextWithRequest(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{
+					Username: "foo",
+				},
+			},
+		})
+		err := ccr.Default(webhookCtx, ccr)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ccr.Annotations[CTIRequesterAnnotation]).Should(Equal("foo"))
+	})
+})
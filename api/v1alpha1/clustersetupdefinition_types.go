@@ -0,0 +1,64 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ClusterSetupDefinitionSpec struct {
+	// +optional
+	// Description of the cluster setup
+	Description string `json:"description,omitempty"`
+	// ArgoCD application spec which is used for setting up the cluster
+	Setup argo.ApplicationSpec `json:"setup"`
+}
+
+// ClusterSetupDefinitionStatus defines the observed state of ClusterSetupDefinition
+type ClusterSetupDefinitionStatus struct {
+	// Names of ClusterTemplates which use this cluster setup definition
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ClusterTemplates []string `json:"clusterTemplates,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=clustersetupdefinitions,shortName=csd;csds,scope=Cluster
+//+operator-sdk:csv:customresourcedefinitions:displayName="Cluster setup definition",resources={{ClusterTemplate, v1alpha1, ""}}
+
+// Reusable post installation setup of a cluster which can be shared by multiple ClusterTemplates
+type ClusterSetupDefinition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterSetupDefinitionSpec   `json:"spec"`
+	Status ClusterSetupDefinitionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterSetupDefinitionList contains a list of ClusterSetupDefinition
+type ClusterSetupDefinitionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterSetupDefinition `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterSetupDefinition{}, &ClusterSetupDefinitionList{})
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Limits of clusters of the size class and their weight in quota accounting
+type ClusterSizeClassSpec struct {
+	// +optional
+	// Description of the size class shown to users
+	Description string `json:"description,omitempty"`
+	//+kubebuilder:validation:Minimum=1
+	// +optional
+	// Maximum number of worker nodes of a cluster of the size class
+	MaxNodes int `json:"maxNodes,omitempty"`
+	//+kubebuilder:validation:Minimum=1
+	// +optional
+	// Maximum number of worker vCPUs of a cluster of the size class
+	MaxVCPU int `json:"maxVCPU,omitempty"`
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:default=1
+	// +optional
+	// Weight of the size class in quota accounting - cost of the template is multiplied by it
+	Weight int `json:"weight,omitempty"`
+	// +optional
+	// Limits of the hosted control plane of clusters of the size class
+	ControlPlaneQuota *ControlPlaneQuota `json:"controlPlaneQuota,omitempty"`
+}
+
+// Limits of the hosted control plane enforced in its namespace on the hosting cluster
+type ControlPlaneQuota struct {
+	// +optional
+	// Hard limits of the ResourceQuota of the control plane namespace (ie 'requests.cpu',
+	// 'limits.memory' or 'pods')
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+	// +optional
+	// Default limits of control plane containers which do not set their own, set by the
+	// LimitRange of the control plane namespace
+	DefaultLimits corev1.ResourceList `json:"defaultLimits,omitempty"`
+	// +optional
+	// Default requests of control plane containers which do not set their own, set by the
+	// LimitRange of the control plane namespace
+	DefaultRequests corev1.ResourceList `json:"defaultRequests,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:path=clustersizeclasses,shortName=csc;cscs,scope=Cluster
+//+kubebuilder:printcolumn:name="Max nodes",type="integer",JSONPath=".spec.maxNodes",description="Maximum number of worker nodes"
+//+kubebuilder:printcolumn:name="Max vCPU",type="integer",JSONPath=".spec.maxVCPU",description="Maximum number of worker vCPUs"
+//+kubebuilder:printcolumn:name="Weight",type="integer",JSONPath=".spec.weight",description="Weight in quota accounting"
+//+operator-sdk:csv:customresourcedefinitions:displayName="Cluster size class",resources={{ClusterTemplateInstance, v1alpha1, ""}}
+
+// Defines a size of clusters (ie small, medium, large) instances can select. Templates list the
+// size classes they can be instantiated with and quotas the size classes allowed in a namespace.
+type ClusterSizeClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterSizeClassSpec `json:"spec"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterSizeClassList contains a list of ClusterSizeClass
+type ClusterSizeClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterSizeClass `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterSizeClass{}, &ClusterSizeClassList{})
+}
+
+// GetWeight returns weight of the size class in quota accounting
+func (s *ClusterSizeClass) GetWeight() int {
+	if s == nil || s.Spec.Weight < 1 {
+		return 1
+	}
+	return s.Spec.Weight
+}
+
+// Validate checks the compute requested by an instance fits the limits of the size class
+func (s *ClusterSizeClass) Validate(nodes int, vcpu int) error {
+	if s.Spec.MaxNodes > 0 && nodes > s.Spec.MaxNodes {
+		return fmt.Errorf(
+			"%d worker nodes exceed %d nodes allowed by size class '%s'",
+			nodes,
+			s.Spec.MaxNodes,
+			s.Name,
+		)
+	}
+	if s.Spec.MaxVCPU > 0 && vcpu > s.Spec.MaxVCPU {
+		return fmt.Errorf(
+			"%d worker vCPUs exceed %d vCPUs allowed by size class '%s'",
+			vcpu,
+			s.Spec.MaxVCPU,
+			s.Name,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	Unsupported ConditionType = "Unsupported"
+	// The template passed its canary
+	TemplateReady ConditionType = "Ready"
+)
+
+type UnsupportedReason string
+
+const (
+	HubVersionSupported   UnsupportedReason = "HubVersionSupported"
+	HubVersionUnsupported UnsupportedReason = "HubVersionUnsupported"
+)
+
+type TemplateReadyReason string
+
+const (
+	NoCanary              TemplateReadyReason = "NoCanary"
+	CanaryInstanceRunning TemplateReadyReason = "CanaryRunning"
+	CanaryInstanceReady   TemplateReadyReason = "CanarySucceeded"
+	CanaryInstanceFailed  TemplateReadyReason = "CanaryFailed"
+)
+
+func (ct *ClusterTemplate) SetReadyCondition(
+	status metav1.ConditionStatus,
+	reason TemplateReadyReason,
+	message string,
+) {
+	meta.SetStatusCondition(&ct.Status.Conditions, metav1.Condition{
+		Type:               string(TemplateReady),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+func (ct *ClusterTemplate) SetUnsupportedCondition(
+	status metav1.ConditionStatus,
+	reason UnsupportedReason,
+	message string,
+) {
+	meta.SetStatusCondition(&ct.Status.Conditions, metav1.Condition{
+		Type:               string(Unsupported),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+// GetUnsupportedMessage returns reason why the template can not be used on the hub,
+// empty if the template is supported
+func (ct *ClusterTemplate) GetUnsupportedMessage() string {
+	condition := meta.FindStatusCondition(ct.Status.Conditions, string(Unsupported))
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		return ""
+	}
+	return condition.Message
+}
@@ -0,0 +1,60 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// prefix of OpenShift versions in OCM
+const ocmVersionPrefix = "openshift-v"
+
+// GetClusterProperties returns properties of the OCM cluster of the given name - the additional
+// properties of the template overridden by the properties set by the other fields
+func (o *OCMCluster) GetClusterProperties(
+	name string,
+	displayName string,
+) (map[string]interface{}, error) {
+	properties := map[string]interface{}{}
+	if o.Properties != "" {
+		if err := yaml.Unmarshal([]byte(o.Properties), &properties); err != nil {
+			return nil, fmt.Errorf("failed to parse OCM cluster properties - %q", err)
+		}
+	}
+	properties["name"] = name
+	if displayName != "" {
+		properties["display_name"] = displayName
+	}
+	properties["product"] = map[string]interface{}{"id": o.Product}
+	cloudProvider := o.CloudProvider
+	if cloudProvider == "" {
+		cloudProvider = "aws"
+	}
+	properties["cloud_provider"] = map[string]interface{}{"id": cloudProvider}
+	properties["region"] = map[string]interface{}{"id": o.Region}
+	if o.Version != "" {
+		version := o.Version
+		if !strings.HasPrefix(version, ocmVersionPrefix) {
+			version = ocmVersionPrefix + version
+		}
+		properties["version"] = map[string]interface{}{"id": version}
+	}
+	if o.MultiAZ {
+		properties["multi_az"] = true
+	}
+	if o.ComputeNodes > 0 || o.ComputeMachineType != "" {
+		nodes, _ := properties["nodes"].(map[string]interface{})
+		if nodes == nil {
+			nodes = map[string]interface{}{}
+		}
+		if o.ComputeNodes > 0 {
+			nodes["compute"] = o.ComputeNodes
+		}
+		if o.ComputeMachineType != "" {
+			nodes["compute_machine_type"] = map[string]interface{}{"id": o.ComputeMachineType}
+		}
+		properties["nodes"] = nodes
+	}
+	return properties, nil
+}
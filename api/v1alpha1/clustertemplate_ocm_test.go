@@ -0,0 +1,63 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ClusterTemplate OCM cluster", func() {
+	It("GetClusterProperties", func() {
+		ocmCluster := OCMCluster{
+			Product:            "rosa",
+			Region:             "us-east-1",
+			Version:            "4.14.1",
+			ComputeMachineType: "m5.xlarge",
+			ComputeNodes:       3,
+			Properties: `
+name: overridden
+nodes:
+  autoscale_compute:
+    min_replicas: 2
+aws:
+  sts:
+    role_arn: arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role
+`,
+		}
+		properties, err := ocmCluster.GetClusterProperties("claas-1", "default-foo")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(properties).Should(Equal(map[string]interface{}{
+			"name":           "claas-1",
+			"display_name":   "default-foo",
+			"product":        map[string]interface{}{"id": "rosa"},
+			"cloud_provider": map[string]interface{}{"id": "aws"},
+			"region":         map[string]interface{}{"id": "us-east-1"},
+			"version":        map[string]interface{}{"id": "openshift-v4.14.1"},
+			"nodes": map[string]interface{}{
+				"compute":              3,
+				"compute_machine_type": map[string]interface{}{"id": "m5.xlarge"},
+				"autoscale_compute": map[string]interface{}{
+					"min_replicas": float64(2),
+				},
+			},
+			"aws": map[string]interface{}{
+				"sts": map[string]interface{}{
+					"role_arn": "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role",
+				},
+			},
+		}))
+
+		ocmCluster.Properties = "- not an object"
+		_, err = ocmCluster.GetClusterProperties("claas-1", "")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("GetOCMClusterName", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", UID: "uid"},
+		}
+		Expect(cti.GetOCMClusterName()).Should(Equal("claas-uid"))
+		cti.Status.ClusterID = "0b7b1f0c-5c4e-4c55-9d67-2b9f1e6c7a10"
+		Expect(cti.GetOCMClusterName()).Should(Equal("claas-0b7b1f0c5"))
+	})
+})
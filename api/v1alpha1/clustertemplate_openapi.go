@@ -0,0 +1,350 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Suffix of the ConfigMap holding the OpenAPI document of the template parameters
+	OpenAPIConfigMapSuffix = "-openapi"
+	// Key of the OpenAPI document in the ConfigMap
+	OpenAPIDocumentKey = "openapi.json"
+	// Label of the OpenAPI ConfigMaps, the value is name of the template
+	CTOpenAPILabel = "clustertemplate.openshift.io/openapi"
+)
+
+const openAPIVersion = "3.0.3"
+
+// GetOpenAPIConfigMapName returns name of the ConfigMap holding the OpenAPI document of the
+// template
+func (ct *ClusterTemplate) GetOpenAPIConfigMapName() string {
+	return ct.Name + OpenAPIConfigMapSuffix
+}
+
+// openAPIParameter is a Helm parameter of the cluster definition or a cluster setup which can be
+// set by instance parameters
+// +kubebuilder:object:generate=false
+type openAPIParameter struct {
+	name         string
+	clusterSetup string
+	schema       map[string]interface{}
+}
+
+// GetOpenAPIDocument returns OpenAPI document describing creation of instances of the template.
+// Parameters of the instance are described by the values.schema.json of the Helm charts (or by
+// types of the values.yaml of charts without schema) as resolved in the template status. The
+// document is versioned by the version of the cluster definition chart and the generation of
+// the template.
+func (ct *ClusterTemplate) GetOpenAPIDocument() ([]byte, error) {
+	schemas := map[string]interface{}{}
+	parameters := []openAPIParameter{}
+
+	cdSchema, cdParams, err := getChartOpenAPISchema(
+		ct.Status.ClusterDefinition.Values,
+		ct.Status.ClusterDefinition.Schema,
+		"",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cluster definition - %w", err)
+	}
+	schemas["ClusterDefinitionValues"] = cdSchema
+	parameters = append(parameters, cdParams...)
+
+	setupNames := []interface{}{}
+	for _, setup := range ct.Status.ClusterSetup {
+		if setup.Name == "" {
+			continue
+		}
+		setupSchema, setupParams, err := getChartOpenAPISchema(setup.Values, setup.Schema, setup.Name)
+		if err != nil {
+			return nil, fmt.Errorf("cluster setup %s - %w", setup.Name, err)
+		}
+		schemas["ClusterSetupValues-"+setup.Name] = setupSchema
+		parameters = append(parameters, setupParams...)
+		setupNames = append(setupNames, setup.Name)
+	}
+
+	schemas["Parameter"] = getParameterOpenAPISchema(parameters, setupNames)
+	schemas["ClusterTemplateInstance"] = map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"apiVersion", "kind", "metadata", "spec"},
+		"properties": map[string]interface{}{
+			"apiVersion": map[string]interface{}{
+				"type": "string",
+				"enum": []interface{}{APIVersion},
+			},
+			"kind": map[string]interface{}{
+				"type": "string",
+				"enum": []interface{}{"ClusterTemplateInstance"},
+			},
+			"metadata": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"name"},
+				"properties": map[string]interface{}{
+					"name":      map[string]interface{}{"type": "string"},
+					"namespace": map[string]interface{}{"type": "string"},
+				},
+			},
+			"spec": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"clusterTemplateRef"},
+				"properties": map[string]interface{}{
+					"clusterTemplateRef": map[string]interface{}{
+						"type": "string",
+						"enum": []interface{}{ct.Name},
+					},
+					"parameters": map[string]interface{}{
+						"type":  "array",
+						"items": map[string]interface{}{"$ref": "#/components/schemas/Parameter"},
+					},
+				},
+			},
+		},
+	}
+
+	description := ""
+	if ct.Annotations != nil {
+		description = ct.Annotations[CTDescriptionLabel]
+	}
+	version := ct.Status.ClusterDefinition.Version
+	if version == "" {
+		version = "0.0.0"
+	}
+	instanceRef := map[string]interface{}{"$ref": "#/components/schemas/ClusterTemplateInstance"}
+	instancesPath := "/apis/" + APIVersion + "/namespaces/{namespace}/clustertemplateinstances"
+	doc := map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":                 ct.Name,
+			"description":           description,
+			"version":               version,
+			"x-template-generation": ct.Generation,
+		},
+		"paths": map[string]interface{}{
+			instancesPath: map[string]interface{}{
+				"post": map[string]interface{}{
+					"operationId": "create-" + ct.Name + "-instance",
+					"summary":     "Create instance of the " + ct.Name + " cluster template",
+					"parameters": []interface{}{
+						map[string]interface{}{
+							"name":     "namespace",
+							"in":       "path",
+							"required": true,
+							"schema":   map[string]interface{}{"type": "string"},
+						},
+					},
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{"schema": instanceRef},
+						},
+					},
+					"responses": map[string]interface{}{
+						"201": map[string]interface{}{
+							"description": "Instance created",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{"schema": instanceRef},
+							},
+						},
+					},
+				},
+			},
+		},
+		"components": map[string]interface{}{
+			"schemas": schemas,
+		},
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// getChartOpenAPISchema returns schema of the chart values and the parameters it allows. Schema
+// of charts without values.schema.json is inferred from types of the default values.
+func getChartOpenAPISchema(
+	chartValues string,
+	chartSchema string,
+	clusterSetup string,
+) (map[string]interface{}, []openAPIParameter, error) {
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(chartValues), &values); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse chart values - %q", err)
+	}
+	inferred := getValuesOpenAPISchema(values)
+
+	schema := inferred
+	if chartSchema != "" {
+		schema = map[string]interface{}{}
+		if err := json.Unmarshal([]byte(chartSchema), &schema); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse chart schema - %q", err)
+		}
+		// OpenAPI schema objects do not declare JSON schema dialect
+		delete(schema, "$schema")
+	}
+
+	found := map[string]map[string]interface{}{}
+	collectOpenAPIParameters(schema, "", found)
+	defaults := map[string]map[string]interface{}{}
+	collectOpenAPIParameters(inferred, "", defaults)
+	for name, param := range defaults {
+		existing, ok := found[name]
+		if !ok {
+			found[name] = param
+			continue
+		}
+		if _, hasDefault := existing["default"]; !hasDefault && param["default"] != nil {
+			existing["default"] = param["default"]
+		}
+	}
+
+	names := []string{}
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parameters := []openAPIParameter{}
+	for _, name := range names {
+		parameters = append(parameters, openAPIParameter{
+			name:         name,
+			clusterSetup: clusterSetup,
+			schema:       found[name],
+		})
+	}
+	return schema, parameters, nil
+}
+
+// getValuesOpenAPISchema infers schema from the type of the default value
+func getValuesOpenAPISchema(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties := map[string]interface{}{}
+		for key, child := range v {
+			properties[key] = getValuesOpenAPISchema(child)
+		}
+		return map[string]interface{}{"type": "object", "properties": properties}
+	case []interface{}:
+		schema := map[string]interface{}{"type": "array"}
+		if len(v) > 0 {
+			schema["items"] = getValuesOpenAPISchema(v[0])
+		}
+		return schema
+	case string:
+		return map[string]interface{}{"type": "string", "default": v}
+	case bool:
+		return map[string]interface{}{"type": "boolean", "default": v}
+	case float64:
+		if v == math.Trunc(v) {
+			return map[string]interface{}{"type": "integer", "default": v}
+		}
+		return map[string]interface{}{"type": "number", "default": v}
+	default:
+		// null default can be replaced by anything
+		return map[string]interface{}{}
+	}
+}
+
+// collectOpenAPIParameters collects leaf properties of the schema as Helm parameter names, ie
+// 'nodePool.replicas'. Objects without properties (ie labels) and arrays are leaves.
+func collectOpenAPIParameters(
+	schema map[string]interface{},
+	prefix string,
+	found map[string]map[string]interface{},
+) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		if prefix == "" {
+			return
+		}
+		param := map[string]interface{}{}
+		for _, key := range []string{"type", "description", "default", "enum", "deprecated"} {
+			if val, ok := schema[key]; ok {
+				param[key] = val
+			}
+		}
+		found[prefix] = param
+		return
+	}
+	for key, child := range properties {
+		childSchema, ok := child.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := strings.ReplaceAll(key, ".", "\\.")
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		collectOpenAPIParameters(childSchema, name, found)
+	}
+}
+
+// getParameterOpenAPISchema describes instance parameters. Values of instance parameters are
+// always strings, type, description and default of the Helm parameter are kept as metadata of
+// the value for form generators.
+func getParameterOpenAPISchema(
+	parameters []openAPIParameter,
+	setupNames []interface{},
+) map[string]interface{} {
+	clusterSetup := map[string]interface{}{
+		"type":        "string",
+		"description": "Name of the cluster setup, empty for cluster definition parameters",
+	}
+	if len(setupNames) > 0 {
+		clusterSetup["enum"] = setupNames
+	}
+	schema := map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"name", "value"},
+		"properties": map[string]interface{}{
+			"name":         map[string]interface{}{"type": "string"},
+			"value":        map[string]interface{}{"type": "string"},
+			"clusterSetup": clusterSetup,
+		},
+	}
+	if len(parameters) == 0 {
+		return schema
+	}
+	variants := []interface{}{}
+	for _, param := range parameters {
+		value := map[string]interface{}{"type": "string"}
+		if param.schema["description"] != nil {
+			value["description"] = param.schema["description"]
+		}
+		if param.schema["default"] != nil {
+			value["default"] = fmt.Sprint(param.schema["default"])
+		}
+		if param.schema["deprecated"] == true {
+			value["deprecated"] = true
+		}
+		if param.schema["type"] != nil {
+			value["x-helm-type"] = param.schema["type"]
+		}
+		if enum, ok := param.schema["enum"].([]interface{}); ok {
+			values := []interface{}{}
+			for _, e := range enum {
+				values = append(values, fmt.Sprint(e))
+			}
+			value["enum"] = values
+		}
+		properties := map[string]interface{}{
+			"name":  map[string]interface{}{"type": "string", "enum": []interface{}{param.name}},
+			"value": value,
+		}
+		if param.clusterSetup != "" {
+			properties["clusterSetup"] = map[string]interface{}{
+				"type": "string",
+				"enum": []interface{}{param.clusterSetup},
+			}
+		}
+		variants = append(variants, map[string]interface{}{
+			"type":       "object",
+			"required":   []interface{}{"name", "value"},
+			"properties": properties,
+		})
+	}
+	schema["oneOf"] = variants
+	return schema
+}
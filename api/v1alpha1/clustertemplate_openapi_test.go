@@ -0,0 +1,95 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ClusterTemplate OpenAPI document", func() {
+	It("Describes parameters of the template", func() {
+		ct := &ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "hypershift",
+				Generation:  3,
+				Annotations: map[string]string{CTDescriptionLabel: "Hosted cluster"},
+			},
+			Status: ClusterTemplateStatus{
+				ClusterDefinition: ClusterDefinitionSchema{
+					Version: "1.2.0",
+					Values:  "nodePool:\n  replicas: 2\nlabels: {}\n",
+					Schema: `{
+						"$schema": "http://json-schema.org/draft-07/schema#",
+						"properties": {
+							"nodePool": {
+								"properties": {
+									"replicas": {"type": "integer", "description": "Number of workers"}
+								}
+							},
+							"release": {"type": "string", "enum": ["4.11", "4.12"]}
+						}
+					}`,
+				},
+				ClusterSetup: []ClusterSetupSchema{
+					{
+						Name:   "day2",
+						Values: "operators:\n  logging: true\n",
+					},
+				},
+			},
+		}
+		Expect(ct.GetOpenAPIConfigMapName()).Should(Equal("hypershift-openapi"))
+		raw, err := ct.GetOpenAPIDocument()
+		Expect(err).ShouldNot(HaveOccurred())
+		doc := map[string]interface{}{}
+		Expect(json.Unmarshal(raw, &doc)).Should(Succeed())
+
+		info := doc["info"].(map[string]interface{})
+		Expect(info["title"]).Should(Equal("hypershift"))
+		Expect(info["description"]).Should(Equal("Hosted cluster"))
+		Expect(info["version"]).Should(Equal("1.2.0"))
+		Expect(info["x-template-generation"]).Should(BeEquivalentTo(3))
+
+		schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+		Expect(schemas["ClusterDefinitionValues"]).ShouldNot(HaveKey("$schema"))
+		Expect(schemas).Should(HaveKey("ClusterSetupValues-day2"))
+
+		variants := schemas["Parameter"].(map[string]interface{})["oneOf"].([]interface{})
+		params := map[string]map[string]interface{}{}
+		for _, v := range variants {
+			properties := v.(map[string]interface{})["properties"].(map[string]interface{})
+			name := properties["name"].(map[string]interface{})["enum"].([]interface{})[0].(string)
+			params[name] = properties
+		}
+		Expect(params).Should(HaveLen(4))
+		Expect(params["nodePool.replicas"]["value"]).Should(Equal(map[string]interface{}{
+			"type":        "string",
+			"description": "Number of workers",
+			"default":     "2",
+			"x-helm-type": "integer",
+		}))
+		Expect(params["release"]["value"]).Should(HaveKeyWithValue(
+			"enum",
+			[]interface{}{"4.11", "4.12"},
+		))
+		// free form objects are a single parameter
+		Expect(params["labels"]["value"]).Should(HaveKeyWithValue("x-helm-type", "object"))
+		Expect(params["operators.logging"]["clusterSetup"]).Should(Equal(map[string]interface{}{
+			"type": "string",
+			"enum": []interface{}{"day2"},
+		}))
+		Expect(params["operators.logging"]["value"]).Should(HaveKeyWithValue("default", "true"))
+	})
+
+	It("Fails on invalid chart schema", func() {
+		ct := &ClusterTemplate{
+			Status: ClusterTemplateStatus{
+				ClusterDefinition: ClusterDefinitionSchema{Schema: "{"},
+			},
+		}
+		_, err := ct.GetOpenAPIDocument()
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -18,31 +18,494 @@ package v1alpha1
 
 import (
 	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var (
 	CTDescriptionLabel = "clustertemplates.openshift.io/description"
+	// Label of canary instances, the value is name of the tested template
+	CTCanaryLabel = "clustertemplate.openshift.io/canary"
+	// Label of templates generated for clusters imported from existing HostedClusters
+	CTImportedLabel = "clustertemplate.openshift.io/imported"
 )
 
 type ClusterSetup struct {
 	// Name of the cluster setup
 	Name string `json:"name"`
-	// ArgoCD application spec which is used for setting up the cluster
-	Spec argo.ApplicationSpec `json:"spec"`
+	// +optional
+	// ArgoCD application spec which is used for setting up the cluster. Ignored if DefinitionRef is set
+	Spec argo.ApplicationSpec `json:"spec,omitempty"`
+	// +optional
+	// Name of the ClusterSetupDefinition which is used for setting up the cluster
+	DefinitionRef string `json:"definitionRef,omitempty"`
+	// +optional
+	// Path in a Git repository with manifests of the cluster setup. The operator creates an ArgoCD Application syncing them to the new cluster. Spec and DefinitionRef are not used if set
+	Git *SetupGitSource `json:"git,omitempty"`
+	// +optional
+	// Credentials of the new cluster passed to the cluster setup, ie for pipelines running on the hub
+	Identity *SetupIdentity `json:"identity,omitempty"`
+	// +optional
+	// Kubernetes Job which sets up the cluster instead of an ArgoCD Application, for hubs which do not manage the setup content by GitOps. Spec and DefinitionRef are not used if set
+	Job *SetupJob `json:"job,omitempty"`
+	// +optional
+	// Job template of Ansible Automation Platform launched to set up the cluster instead of an ArgoCD Application. Spec and DefinitionRef are not used if set
+	AnsibleJob *SetupAnsibleJob `json:"ansibleJob,omitempty"`
+	// +optional
+	// Names of cluster setups which have to succeed before this cluster setup is created, ie a setup installing an operator before the setup configuring it. Setups of add-ons can depend on setups of the template and of the same add-on
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// Git source of a cluster setup. The ArgoCD Application of the setup syncs the manifests of the
+// path to the new cluster automatically
+type SetupGitSource struct {
+	// URL of the Git repository
+	RepoURL string `json:"repoURL"`
+	// Path of the manifests in the repository
+	Path string `json:"path"`
+	// +kubebuilder:default=HEAD
+	// +optional
+	// Branch, tag or commit of the repository, defaults to HEAD
+	TargetRevision string `json:"targetRevision,omitempty"`
+	// +optional
+	// Namespace of the new cluster the manifests without namespace are created in
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Kubernetes Job of a cluster setup. The Job runs on the hub in the namespace of the instance, the
+// kubeconfig of the new cluster (of the setup identity if set) is mounted to it and KUBECONFIG
+// points to it
+type SetupJob struct {
+	// Container image the script runs in, it has to provide a shell
+	Image string `json:"image"`
+	// Shell script which sets up the cluster, run by '/bin/sh -c'
+	Script string `json:"script"`
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	// Number of retries before the cluster setup fails, defaults to 3
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+	// +optional
+	// Run the Job on the new cluster instead of the hub, in the 'cluster-setup' namespace. The kubeconfig is copied to a Secret of the same namespace
+	RunOnCluster bool `json:"runOnCluster,omitempty"`
+}
+
+// Job template of Ansible Automation Platform (AAP) of a cluster setup. The job is launched once,
+// with the kubeconfig of the new cluster (of the setup identity if set) in its extra variables
+type SetupAnsibleJob struct {
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// URL of the automation controller of AAP
+	URL string `json:"url"`
+	// Name of the Secret in the operator config namespace (cluster-aas-operator) with the OAuth 'token' of the AAP user launching the job and the 'url' of the automation controller the token is meant for
+	CredentialsSecret string `json:"credentialsSecret"`
+	// +kubebuilder:validation:Minimum=1
+	// ID of the job template, the template has to prompt for variables on launch
+	JobTemplateID int `json:"jobTemplateID"`
+	// +optional
+	// Extra variables of the job in YAML format. The kubeconfig is passed in 'cluster_kubeconfig', name and namespace of the instance in 'instance_name' and 'instance_namespace'
+	ExtraVars string `json:"extraVars,omitempty"`
+}
+
+// Helm parameter of the setup chart set to name of the Secret with credentials of the setup identity
+const SetupCredentialsParameter = "clusterCredentials.secretName"
+
+type SetupIdentityType string
+
+const (
+	// Admin kubeconfig of the new cluster
+	KubeconfigIdentity SetupIdentityType = "Kubeconfig"
+	// Kubeconfig of a ServiceAccount created on the new cluster with limited permissions
+	ServiceAccountIdentity SetupIdentityType = "ServiceAccount"
+)
+
+// Credentials of the new cluster passed to a cluster setup. The operator stores a kubeconfig in
+// a Secret in the namespace of the instance and passes its name to the setup Helm chart in the
+// 'clusterCredentials.secretName' parameter
+type SetupIdentity struct {
+	// +kubebuilder:validation:Enum=Kubeconfig;ServiceAccount
+	// 'Kubeconfig' passes the admin kubeconfig of the cluster, 'ServiceAccount' a kubeconfig of a ServiceAccount created on the cluster with the rules
+	Type SetupIdentityType `json:"type"`
+	// +optional
+	// Permissions of the ServiceAccount on the new cluster, required for 'ServiceAccount' type
+	Rules []rbacv1.PolicyRule `json:"rules,omitempty"`
+}
+
+type ComputeRule struct {
+	// +optional
+	// Name of the cluster definition Helm parameter which holds the value
+	Parameter string `json:"parameter,omitempty"`
+	//+kubebuilder:validation:Minimum=0
+	// +optional
+	// Value used when the parameter is set neither by the template nor by the instance
+	Default int `json:"default,omitempty"`
+}
+
+type ClusterCompute struct {
+	// +optional
+	// Rule to extract the number of worker nodes of the cluster
+	Nodes *ComputeRule `json:"nodes,omitempty"`
+	// +optional
+	// Rule to extract the number of vCPUs of a single worker node
+	VCPUPerNode *ComputeRule `json:"vcpuPerNode,omitempty"`
+}
+
+type ParameterMigration struct {
+	// Name of the parameter used by older versions of the chart
+	Name string `json:"name"`
+	// +optional
+	// New name of the parameter. If not set, the parameter is dropped
+	RenameTo string `json:"renameTo,omitempty"`
+	// +optional
+	// Name of the cluster setup the parameter belongs to. Not set for cluster definition parameters
+	ClusterSetup string `json:"clusterSetup,omitempty"`
+}
+
+type InstallOptions struct {
+	// +optional
+	// If true, the cluster definition application and its resources are deleted when the installation fails or times out
+	Atomic bool `json:"atomic,omitempty"`
+	// +optional
+	// Maximum duration of the cluster installation. The installation is considered failed when exceeded
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// How many times a rolled back installation is retried. Requires atomic to be set
+	Retries int `json:"retries,omitempty"`
+}
+
+type ProvisioningSLO struct {
+	// +optional
+	// Expected time from the creation of an instance until its cluster is installed, ie '45m'
+	ClusterInstall *metav1.Duration `json:"clusterInstall,omitempty"`
+	// +optional
+	// Expected time from the creation of an instance until it is Ready (the cluster is installed and all cluster setups succeeded), ie '1h'
+	Ready *metav1.Duration `json:"ready,omitempty"`
+}
+
+type BaseDomainFromHub struct {
+	// Name of the cluster definition Helm parameter which holds the base domain of the cluster
+	Parameter string `json:"parameter"`
+	// +optional
+	// Subdomain of the hub ingress domain used as the base domain, ie 'clusters' gives 'clusters.apps.hub.example.com'
+	Subdomain string `json:"subdomain,omitempty"`
+}
+
+// Hub default credentials injected into the cluster definition values
+type DefaultCredentials struct {
+	// +optional
+	// Key of the cluster definition values the name of the Secret with the hub default pull secret ('.dockerconfigjson') is passed in, ie 'pullSecret.name'. The Secret is created in the namespace the cluster definition is deployed to. The pull secret is not injected if empty
+	PullSecretValuesKey string `json:"pullSecretValuesKey,omitempty"`
+	// +optional
+	// Key of the cluster definition values the name of the Secret with the hub default public SSH key ('id_rsa.pub') is passed in, ie 'sshKey.name'. The Secret is created in the namespace the cluster definition is deployed to. The SSH key is not injected if empty
+	SSHKeyValuesKey string `json:"sshKeyValuesKey,omitempty"`
+}
+
+// CA bundle trusted by the hub injected into the cluster definition values
+type TrustedCABundle struct {
+	// Key of the cluster definition values the PEM encoded CA bundle is passed in, ie 'additionalTrustBundle'
+	ValuesKey string `json:"valuesKey"`
+}
+
+type ClusterName struct {
+	// Key of the cluster definition values the name of the cluster (status.clusterName of the instance) is passed in, ie 'clusterName'
+	ValuesKey string `json:"valuesKey"`
+	// +optional
+	// If true, a random suffix is appended to the name of the instance, the same way as for metadata.generateName, so instances re-created with the same name do not reuse the name (and infra ID) of a cluster which still exists
+	GenerateSuffix bool `json:"generateSuffix,omitempty"`
+}
+
+type ChartTests struct {
+	// +optional
+	// Maximum duration of the tests, tests which do not finish are considered failed. Defaults to 10 minutes
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+type HubRequirements struct {
+	// +optional
+	// Semver constraint of the hub OpenShift version, ie '>= 4.12'
+	OpenShiftVersion string `json:"openshiftVersion,omitempty"`
+	// +optional
+	// Semver constraint of the multicluster engine version, ie '>= 2.2'
+	MCEVersion string `json:"mceVersion,omitempty"`
+}
+
+// Kustomization applied to the manifests rendered from the cluster definition Helm chart
+type PostRenderer struct {
+	// Git repository containing the kustomization
+	RepoURL string `json:"repoURL"`
+	// Directory of kustomization.yaml in the repository. The kustomization has to list 'helm-output.yaml' (manifests rendered from the chart) in its resources
+	Path string `json:"path"`
+	// +optional
+	// Revision of the repository, defaults to HEAD
+	TargetRevision string `json:"targetRevision,omitempty"`
+}
+
+// Verification of the provenance files of the template Helm charts
+type ChartVerification struct {
+	// Name of the ConfigMap in the ArgoCD namespace which contains ASCII armored PGP public keys trusted to sign the charts under key 'publicKeys'
+	PublicKeysConfigMap string `json:"publicKeysConfigMap"`
+}
+
+// Packaged Helm chart stored in the cluster
+type EmbeddedChart struct {
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	// Kind of the resource holding the chart
+	Kind string `json:"kind"`
+	// Name of the ConfigMap or Secret in the ArgoCD namespace. The resource has to be labeled 'clustertemplate.openshift.io/embedded-chart=true' and hold the chart tarball under key 'chart.tgz'
+	Name string `json:"name"`
+}
+
+// Limits of the node pools composed by instances
+type NodePoolOptions struct {
+	// +optional
+	// Key of the cluster definition values the node pools are passed in, defaults to 'nodePools'. Every node pool is passed as a map with 'name', 'replicas', 'instanceType', 'labels' and 'taints' keys
+	ValuesKey string `json:"valuesKey,omitempty"`
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// Maximal number of node pools of an instance, unlimited if 0
+	MaxNodePools int `json:"maxNodePools,omitempty"`
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// Maximal number of replicas of a node pool, unlimited if 0
+	MaxReplicas int `json:"maxReplicas,omitempty"`
+	// +optional
+	// Instance types the node pools can use, any instance type is allowed if empty
+	InstanceTypes []string `json:"instanceTypes,omitempty"`
+}
+
+// Hive ClusterPool clusters of the template are claimed from
+type ClusterPoolRef struct {
+	// Name of the ClusterPool
+	Name string `json:"name"`
+	// Namespace of the ClusterPool, the ClusterClaims of the instances are created in it
+	Namespace string `json:"namespace"`
+}
+
+// Managed OpenShift cluster (OSD or ROSA) created through the OpenShift Cluster Manager API
+type OCMCluster struct {
+	// Name of the Secret in the ArgoCD namespace with the OCM credentials - either 'clientID' and 'clientSecret' of a service account or 'offlineToken' of a user. Optional keys 'awsAccountID', 'awsAccessKeyID' and 'awsSecretAccessKey' create the cluster in the AWS account of the customer (CCS)
+	CredentialsSecret string `json:"credentialsSecret"`
+	// +optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// URL of the OCM API, defaults to https://api.openshift.com
+	URL string `json:"url,omitempty"`
+	// +optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// URL of the SSO token endpoint the credentials are exchanged at, defaults to the Red Hat SSO
+	TokenURL string `json:"tokenURL,omitempty"`
+	// +kubebuilder:validation:Enum=osd;rosa
+	// Product of the cluster
+	Product string `json:"product"`
+	// +optional
+	// +kubebuilder:default=aws
+	// Cloud provider of the cluster, ie 'aws' or 'gcp'
+	CloudProvider string `json:"cloudProvider,omitempty"`
+	// Cloud region of the cluster, ie 'us-east-1'
+	Region string `json:"region"`
+	// +optional
+	// OpenShift version of the cluster, ie '4.14.1'. Defaults to the default version of OCM
+	Version string `json:"version,omitempty"`
+	// +optional
+	// Instance type of the compute nodes, ie 'm5.xlarge'
+	ComputeMachineType string `json:"computeMachineType,omitempty"`
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// Number of compute nodes
+	ComputeNodes int `json:"computeNodes,omitempty"`
+	// +optional
+	// If true, the cluster is deployed to multiple availability zones
+	MultiAZ bool `json:"multiAZ,omitempty"`
+	// +optional
+	// Additional properties of the OCM cluster in JSON or YAML format (ie 'aws.sts' of ROSA clusters), see the clusters_mgmt API of OCM. The properties set by other fields take precedence
+	Properties string `json:"properties,omitempty"`
+}
+
+// Remote cluster hosting the resources of the cluster definition
+type HostingCluster struct {
+	// Name of the Secret in the ArgoCD namespace which contains kubeconfig of the hosting cluster under key 'kubeconfig'
+	KubeconfigSecret string `json:"kubeconfigSecret"`
+}
+
+// External system which has to acknowledge decommissioning of a cluster before it is uninstalled,
+// ie billing or security inventory. Exactly one of url and holdAnnotation has to be set.
+type DeletionGate struct {
+	// Name of the gate, reported in the instance status while the gate holds the deletion
+	Name string `json:"name"`
+	// +optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// URL the instance is POSTed to (JSON with 'name', 'namespace', 'clusterTemplate' and 'apiServerURL' keys). The gate is open once the URL responds with status 200, the request is repeated until then
+	URL string `json:"url,omitempty"`
+	// +optional
+	// Annotation of the instance holding the deletion while present. The external system removes it once it acknowledged the decommissioning
+	HoldAnnotation string `json:"holdAnnotation,omitempty"`
+}
+
+// Short-lived instance provisioned whenever the template changes to test it
+type CanaryOptions struct {
+	// Namespace the canary instance is created in. A ClusterTemplateQuota of the namespace has to allow the template
+	Namespace string `json:"namespace"`
+	// +optional
+	// Parameters of the canary instance
+	Parameters []Parameter `json:"parameters,omitempty"`
+	// +optional
+	// How long the canary instance has to become ready within, defaults to 2 hours
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// Optional feature of the cluster (ie logging, service mesh, gpu) enabled by instances
+type AddOn struct {
+	// Name of the add-on, instances enable it in spec.addOns
+	Name string `json:"name"`
+	// +optional
+	// Human readable description of the add-on
+	Description string `json:"description,omitempty"`
+	// +optional
+	// Helm values (yaml) of the cluster definition chart set when the add-on is enabled. Override values of the template
+	Values string `json:"values,omitempty"`
+	// +optional
+	// Cluster setups created when the add-on is enabled, after the cluster setups of the template
+	ClusterSetup []ClusterSetup `json:"clusterSetup,omitempty"`
+}
+
+// Catalog fields of a ClusterTemplate, validated against ClusterTemplateTaxonomies
+type ClusterTemplateCatalog struct {
+	// +optional
+	// Infrastructure provider of the cluster, ie 'aws'
+	Provider string `json:"provider,omitempty"`
+	// +optional
+	// Size of the cluster, ie 'small'
+	Size string `json:"size,omitempty"`
+	// +optional
+	// Purpose of the cluster, ie 'development'
+	Purpose string `json:"purpose,omitempty"`
+	// +optional
+	// Compliance level of the cluster, ie 'pci-dss'
+	ComplianceLevel string `json:"complianceLevel,omitempty"`
+	// +optional
+	// Free form tags of the template
+	Tags []string `json:"tags,omitempty"`
 }
 
 type ClusterTemplateSpec struct {
-	// ArgoCD application spec which is used for installation of the cluster
+	// +optional
+	// ArgoCD application spec which is used for installation of the cluster. Required unless clusterPool or ocm is set
 	ClusterDefinition argo.ApplicationSpec `json:"clusterDefinition"`
+	// +optional
+	// Hive ClusterPool the clusters are claimed from instead of installing the cluster definition. Instances get a pre-provisioned cluster of the pool by a ClusterClaim
+	ClusterPool *ClusterPoolRef `json:"clusterPool,omitempty"`
+	// +optional
+	// Managed OpenShift cluster created through the OpenShift Cluster Manager (OCM) API instead of installing the cluster definition
+	OCM *OCMCluster `json:"ocm,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// URL of the cluster definition Helm chart tarball. If set, the chart is downloaded from the URL instead of the Helm repository of the cluster definition source and served to ArgoCD by the operator, repoURL of the source is replaced. Name and version of the chart have to match chart and targetRevision of the source
+	HelmChartURL string `json:"helmChartURL,omitempty"`
+
+	// +optional
+	// Cluster definition Helm chart stored in a ConfigMap or Secret, for hubs without any reachable Helm repository. The chart is served to ArgoCD by the operator, repoURL and targetRevision of the cluster definition source are replaced. The chart name has to match the chart of the source
+	EmbeddedChart *EmbeddedChart `json:"embeddedChart,omitempty"`
+
+	// +optional
+	// Mirrors of the Helm repositories of the template charts (cluster definition and cluster setups). When the index or a chart can not be fetched from the repository of the chart, mirrors are tried in order
+	RepositoryMirrors []string `json:"repositoryMirrors,omitempty"`
+
+	// +optional
+	// If set, the template Helm charts are verified against their provenance files ('.prov') in the Helm repository before the ArgoCD Applications are created. The Applications install the verified chart archives served by the operator
+	ChartVerification *ChartVerification `json:"chartVerification,omitempty"`
+
+	// +optional
+	// Kustomization patching the manifests of the cluster definition Helm chart (ie labels, tolerations), applied by ArgoCD config management plugin 'claas-helm-kustomize'
+	PostRenderer *PostRenderer `json:"postRenderer,omitempty"`
 
 	// +optional
 	// Array of ArgoCD application specs which are used for post installation setup of the cluster
 	ClusterSetup []ClusterSetup `json:"clusterSetup,omitempty"`
 
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// How many times a failed cluster setup run by a Job or an Ansible job is retried before the setup fails
+	SetupRetryLimit int `json:"setupRetryLimit,omitempty"`
+
+	// +optional
+	// Delay before the first retry of a failed cluster setup, doubled by every next retry. Defaults to 1 minute
+	SetupRetryBackoff *metav1.Duration `json:"setupRetryBackoff,omitempty"`
+
+	// +optional
+	// Maximum duration of the cluster setup since it was created, ie '1h'. Unfinished cluster setups fail once it elapses - setup Jobs are stopped and Ansible jobs cancelled
+	SetupTimeout *metav1.Duration `json:"setupTimeout,omitempty"`
+
 	//+kubebuilder:validation:Minimum=0
 	// Cost of the cluster, used for quotas
 	Cost int `json:"cost"`
+
+	// +optional
+	// Describes how to compute worker nodes and vCPUs requested by an instance, used for quotas
+	Compute *ClusterCompute `json:"compute,omitempty"`
+	// +optional
+	// Names of the ClusterSizeClasses instances of the template can select. If set, every instance
+	// has to select one of them in spec.sizeClass.
+	SizeClasses []string `json:"sizeClasses,omitempty"`
+	// +optional
+	// Migrations of instance parameters written for older versions of the charts
+	ParameterMigrations []ParameterMigration `json:"parameterMigrations,omitempty"`
+	// +optional
+	// If true, resources of the cluster definition which were changed or deleted outside of ArgoCD are re-applied
+	SelfHeal bool `json:"selfHeal,omitempty"`
+	// +optional
+	// If true, CRDs of the cluster definition Helm chart ('crds' directory) are not installed (like 'helm install --skip-crds'), ie CRDs which are managed by operators of the hub
+	SkipCRDs bool `json:"skipCRDs,omitempty"`
+	// +optional
+	// Namespace the cluster definition is deployed to (ie 'clusters'), overrides the destination namespace of the cluster definition. The namespace is created if it does not exist and deleted together with the last instance deployed to it, unless it existed before
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+	// +optional
+	// Remote cluster the cluster definition is installed to instead of the hub, ie a hosting service cluster running the hypershift operator
+	HostingCluster *HostingCluster `json:"hostingCluster,omitempty"`
+	// +optional
+	// If set, instances can compose node pools of the cluster in spec.nodePools within the limits
+	NodePools *NodePoolOptions `json:"nodePools,omitempty"`
+	// +optional
+	// External systems which have to acknowledge the deletion of an instance before the cluster is uninstalled
+	DeletionGates []DeletionGate `json:"deletionGates,omitempty"`
+	// +optional
+	// If set, a canary instance is provisioned whenever the template changes. The template is Ready once the canary instance becomes ready (the cluster is installed and all cluster setups succeeded), the canary instance is deleted then
+	Canary *CanaryOptions `json:"canary,omitempty"`
+	// +optional
+	// Options of the cluster installation
+	InstallOptions *InstallOptions `json:"installOptions,omitempty"`
+	// +optional
+	// Maximum lifetime of instances of the template, ie '168h'. Instances are deleted once it elapses since their creation, a warning is reported ahead of time
+	MaxLifetime *metav1.Duration `json:"maxLifetime,omitempty"`
+	// +optional
+	// Expected provisioning durations of instances of the template. Instances exceeding them are reported by the ProvisioningSLOMet condition and the clustertemplateinstance_provisioning_slo_violations_total metric
+	ProvisioningSLO *ProvisioningSLO `json:"provisioningSLO,omitempty"`
+	// +optional
+	// If set, the base domain of new clusters defaults to the ingress domain of the hub (ie apps.hub.example.com). Instances can override it by the parameter
+	BaseDomainFromHub *BaseDomainFromHub `json:"baseDomainFromHub,omitempty"`
+	// +optional
+	// If set, the default pull secret and SSH key of the hub (configured in the operator config) are copied into the namespace of the instance and injected into the cluster definition values, so users do not need to provide them
+	DefaultCredentials *DefaultCredentials `json:"defaultCredentials,omitempty"`
+	// +optional
+	// If set, the additional CA bundle trusted by the hub (trustedCA of the cluster-wide proxy) is copied into the namespace of the instance and injected into the cluster definition values, so new clusters trust the same internal registries and services as the hub
+	TrustedCABundle *TrustedCABundle `json:"trustedCABundle,omitempty"`
+	// +optional
+	// Key of the cluster definition values the stable identifier of the cluster (status.clusterID of the instance) is passed in, ie 'clusterID'. Charts can use it for cloud resource tags and DNS records which outlive the name of the instance. The identifier is not injected if empty
+	ClusterIDValuesKey string `json:"clusterIDValuesKey,omitempty"`
+	// +optional
+	// If set, the name of the cluster is passed in the cluster definition values. The Day1 application is not created while a HostedCluster of the same name exists
+	ClusterName *ClusterName `json:"clusterName,omitempty"`
+	// +optional
+	// If set, test hooks of the cluster definition Helm chart ('helm.sh/hook: test') are run once the cluster is installed and their results are reported in the instance status
+	ChartTests *ChartTests `json:"chartTests,omitempty"`
+	// +optional
+	// Versions of the hub components the template is supported on
+	HubRequirements *HubRequirements `json:"hubRequirements,omitempty"`
+	// +optional
+	// Categories and tags of the template used for searching the catalog
+	Catalog *ClusterTemplateCatalog `json:"catalog,omitempty"`
+	// +optional
+	// Optional add-ons of the cluster which instances can enable
+	AddOns []AddOn `json:"addOns,omitempty"`
 }
 
 type ClusterDefinitionSchema struct {
@@ -50,6 +513,11 @@ type ClusterDefinitionSchema struct {
 	Values string `json:"values,omitempty"`
 	// Content of helm chart values.schema.json
 	Schema string `json:"schema,omitempty"`
+	// Version of the helm chart, resolved if the template specifies a version constraint
+	Version string `json:"version,omitempty"`
+	// +optional
+	// Repository the chart was fetched from, differs from the repository of the template when a mirror was used
+	RepoURL string `json:"repoURL,omitempty"`
 	// Contain information about failure during fetching helm chart
 	// +optional
 	Error *string `json:"error,omitempty"`
@@ -62,6 +530,11 @@ type ClusterSetupSchema struct {
 	Values string `json:"values,omitempty"`
 	// Content of helm chart values.schema.json
 	Schema string `json:"schema,omitempty"`
+	// Version of the helm chart, resolved if the template specifies a version constraint
+	Version string `json:"version,omitempty"`
+	// +optional
+	// Repository the chart was fetched from, differs from the repository of the template when a mirror was used
+	RepoURL string `json:"repoURL,omitempty"`
 	// Contain information about failure during fetching helm chart
 	// +optional
 	Error *string `json:"error,omitempty"`
@@ -75,12 +548,50 @@ type ClusterTemplateStatus struct {
 	// Describes helm chart properties and schema for every cluster setup step
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	ClusterSetup []ClusterSetupSchema `json:"clusterSetup,omitempty"`
+	// Resource conditions
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// +optional
+	// Result of the canary instance of the latest template generation
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Canary *CanaryStatus `json:"canary,omitempty"`
+}
+
+type CanaryPhase string
+
+const (
+	CanaryRunning   CanaryPhase = "Running"
+	CanarySucceeded CanaryPhase = "Succeeded"
+	CanaryFailed    CanaryPhase = "Failed"
+)
+
+type CanaryStatus struct {
+	// Generation of the template tested by the canary instance
+	Generation int64 `json:"generation"`
+	// Namespace of the canary instance
+	Namespace string `json:"namespace"`
+	// Name of the canary instance
+	Name string `json:"name"`
+	// +kubebuilder:validation:Enum=Running;Succeeded;Failed
+	// Phase of the canary
+	Phase CanaryPhase `json:"phase"`
+	// +optional
+	// Details of the result
+	Message string `json:"message,omitempty"`
+	// When the canary instance was created
+	StartTime metav1.Time `json:"startTime"`
+	// +optional
+	// When the canary finished
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:resource:path=clustertemplates,shortName=ct;cts,scope=Cluster
 //+kubebuilder:printcolumn:name="Cost",type="integer",JSONPath=".spec.cost",description="Cluster cost"
+//+kubebuilder:printcolumn:name="Provider",type="string",JSONPath=".spec.catalog.provider",description="Infrastructure provider"
+//+kubebuilder:printcolumn:name="Size",type="string",JSONPath=".spec.catalog.size",description="Cluster size"
+//+kubebuilder:printcolumn:name="Purpose",type="string",JSONPath=".spec.catalog.purpose",description="Cluster purpose"
 //+operator-sdk:csv:customresourcedefinitions:displayName="Cluster template",resources={{Pod, v1, ""}}
 
 // Template of a cluster - both installation and post-install setup are defined as ArgoCD application spec. Any application source is supported - typically a Helm chart
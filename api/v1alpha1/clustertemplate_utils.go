@@ -0,0 +1,300 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MigrateParameters applies parameter migrations of the template to instance parameters.
+// Migrations are applied in order, so renames can be chained across chart versions.
+func (ctSpec *ClusterTemplateSpec) MigrateParameters(params []Parameter) []Parameter {
+	if len(ctSpec.ParameterMigrations) == 0 {
+		return params
+	}
+	migrated := []Parameter{}
+	for _, param := range params {
+		dropped := false
+		for _, migration := range ctSpec.ParameterMigrations {
+			if migration.Name != param.Name || migration.ClusterSetup != param.ClusterSetup {
+				continue
+			}
+			if migration.RenameTo == "" {
+				dropped = true
+				break
+			}
+			param.Name = migration.RenameTo
+		}
+		if !dropped {
+			migrated = append(migrated, param)
+		}
+	}
+	return migrated
+}
+
+// GetSpec returns ArgoCD application spec of the cluster setup. If the cluster setup
+// references a ClusterSetupDefinition, spec of the definition is returned. Spec of a setup with
+// Git source is built from the source
+func (setup ClusterSetup) GetSpec(
+	ctx context.Context,
+	k8sClient client.Client,
+) (argo.ApplicationSpec, error) {
+	if setup.Git != nil {
+		return setup.Git.GetApplicationSpec(), nil
+	}
+	if setup.DefinitionRef == "" {
+		return setup.Spec, nil
+	}
+	definition := &ClusterSetupDefinition{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: setup.DefinitionRef},
+		definition,
+	); err != nil {
+		return argo.ApplicationSpec{}, fmt.Errorf(
+			"failed to fetch ClusterSetupDefinition '%s' - %q",
+			setup.DefinitionRef,
+			err,
+		)
+	}
+	return *definition.Spec.Setup.DeepCopy(), nil
+}
+
+// UsesApplication returns false if the cluster setup is run by a Job or an Ansible job instead of
+// an ArgoCD Application
+func (setup ClusterSetup) UsesApplication() bool {
+	return setup.Job == nil && setup.AnsibleJob == nil
+}
+
+// GetApplicationSpec returns ArgoCD application spec which syncs the manifests of the Git source
+// to the new cluster
+func (source SetupGitSource) GetApplicationSpec() argo.ApplicationSpec {
+	targetRevision := source.TargetRevision
+	if targetRevision == "" {
+		targetRevision = "HEAD"
+	}
+	return argo.ApplicationSpec{
+		Source: argo.ApplicationSource{
+			RepoURL:        source.RepoURL,
+			Path:           source.Path,
+			TargetRevision: targetRevision,
+		},
+		Destination: argo.ApplicationDestination{
+			Server:    CTIClusterTargetVar,
+			Namespace: source.Namespace,
+		},
+		Project: "default",
+		SyncPolicy: &argo.SyncPolicy{
+			Automated: &argo.SyncPolicyAutomated{},
+		},
+	}
+}
+
+// ResolveClusterSetupDefinitions replaces spec of cluster setups which reference
+// a ClusterSetupDefinition with the spec of the definition
+func (ctSpec *ClusterTemplateSpec) ResolveClusterSetupDefinitions(
+	ctx context.Context,
+	k8sClient client.Client,
+) error {
+	for i, setup := range ctSpec.ClusterSetup {
+		spec, err := setup.GetSpec(ctx, k8sClient)
+		if err != nil {
+			return err
+		}
+		ctSpec.ClusterSetup[i].Spec = spec
+	}
+	return nil
+}
+
+// UsesClusterSetupDefinition returns true if any cluster setup of the template (or of its add-ons)
+// references the ClusterSetupDefinition of given name
+func (ct ClusterTemplate) UsesClusterSetupDefinition(name string) bool {
+	for _, setup := range ct.Spec.ClusterSetup {
+		if setup.DefinitionRef == name {
+			return true
+		}
+	}
+	for _, addOn := range ct.Spec.AddOns {
+		for _, setup := range addOn.ClusterSetup {
+			if setup.DefinitionRef == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ApplyAddOns composes the add-ons enabled by an instance into the template - values of the
+// add-ons are merged into the cluster definition values and their cluster setups are appended
+// to the cluster setups of the template. Add-ons are applied in the order of the template.
+func (ctSpec *ClusterTemplateSpec) ApplyAddOns(enabled map[string]bool) error {
+	for name, val := range enabled {
+		if val && ctSpec.GetAddOn(name) == nil {
+			return fmt.Errorf("add-on '%s' is not defined by the template", name)
+		}
+	}
+	for _, addOn := range ctSpec.AddOns {
+		if !enabled[addOn.Name] {
+			continue
+		}
+		if addOn.Values != "" {
+			if err := ctSpec.mergeClusterDefinitionValues(addOn.Values); err != nil {
+				return fmt.Errorf("failed to apply values of add-on '%s' - %q", addOn.Name, err)
+			}
+		}
+		ctSpec.ClusterSetup = append(ctSpec.ClusterSetup, addOn.ClusterSetup...)
+	}
+	return nil
+}
+
+// GetAddOn returns the add-on of given name or nil if the template does not define it
+func (ctSpec *ClusterTemplateSpec) GetAddOn(name string) *AddOn {
+	for i := range ctSpec.AddOns {
+		if ctSpec.AddOns[i].Name == name {
+			return &ctSpec.AddOns[i]
+		}
+	}
+	return nil
+}
+
+// mergeClusterDefinitionValues merges values (yaml) into helm values of the cluster definition,
+// values take precedence over the values already set
+func (ctSpec *ClusterTemplateSpec) mergeClusterDefinitionValues(values string) error {
+	addOnValues, err := chartutil.ReadValues([]byte(values))
+	if err != nil {
+		return err
+	}
+	source := &ctSpec.ClusterDefinition.Source
+	if source.Helm == nil {
+		source.Helm = &argo.ApplicationSourceHelm{}
+	}
+	templateValues, err := chartutil.ReadValues([]byte(source.Helm.Values))
+	if err != nil {
+		return err
+	}
+	merged := chartutil.Values(chartutil.CoalesceTables(addOnValues, templateValues))
+	yamlValues, err := merged.YAML()
+	if err != nil {
+		return err
+	}
+	source.Helm.Values = yamlValues
+	return nil
+}
+
+// SetRevisionHistoryLimit sets how many revisions are kept in history of the cluster definition
+// and cluster setup applications, unless set by the template. Nothing is set if limit is nil.
+func (ctSpec *ClusterTemplateSpec) SetRevisionHistoryLimit(limit *int64) {
+	if limit == nil {
+		return
+	}
+	if ctSpec.ClusterDefinition.RevisionHistoryLimit == nil {
+		val := *limit
+		ctSpec.ClusterDefinition.RevisionHistoryLimit = &val
+	}
+	for i := range ctSpec.ClusterSetup {
+		if ctSpec.ClusterSetup[i].Spec.RevisionHistoryLimit == nil {
+			val := *limit
+			ctSpec.ClusterSetup[i].Spec.RevisionHistoryLimit = &val
+		}
+	}
+}
+
+// GetHubBaseDomain returns the base domain of new clusters derived from the ingress domain of
+// the hub, empty if the template does not derive it or the ingress domain is not known
+func (ctSpec *ClusterTemplateSpec) GetHubBaseDomain(ingressDomain string) string {
+	if ctSpec.BaseDomainFromHub == nil || ingressDomain == "" {
+		return ""
+	}
+	if ctSpec.BaseDomainFromHub.Subdomain == "" {
+		return ingressDomain
+	}
+	return ctSpec.BaseDomainFromHub.Subdomain + "." + ingressDomain
+}
+
+// SetBaseDomain sets the base domain parameter of the cluster definition, unless set by
+// the template. Nothing is set if the template does not derive the base domain from the hub
+// or domain is empty.
+func (ctSpec *ClusterTemplateSpec) SetBaseDomain(domain string) {
+	if ctSpec.BaseDomainFromHub == nil || domain == "" {
+		return
+	}
+	if ctSpec.ClusterDefinition.Source.Helm == nil {
+		ctSpec.ClusterDefinition.Source.Helm = &argo.ApplicationSourceHelm{}
+	}
+	helmSource := ctSpec.ClusterDefinition.Source.Helm
+	for _, param := range helmSource.Parameters {
+		if param.Name == ctSpec.BaseDomainFromHub.Parameter {
+			return
+		}
+	}
+	helmSource.Parameters = append(helmSource.Parameters, argo.HelmParameter{
+		Name:  ctSpec.BaseDomainFromHub.Parameter,
+		Value: domain,
+	})
+}
+
+// GetBaseDomain returns the base domain parameter of the cluster definition, empty if
+// the template does not derive the base domain from the hub
+func (ctSpec *ClusterTemplateSpec) GetBaseDomain() string {
+	if ctSpec.BaseDomainFromHub == nil || ctSpec.ClusterDefinition.Source.Helm == nil {
+		return ""
+	}
+	for _, param := range ctSpec.ClusterDefinition.Source.Helm.Parameters {
+		if param.Name == ctSpec.BaseDomainFromHub.Parameter {
+			return param.Value
+		}
+	}
+	return ""
+}
+
+// GetHelmChartURLSecretName returns name of the Secret of the ArgoCD namespace holding the chart
+// downloaded from spec.helmChartURL
+func (ct *ClusterTemplate) GetHelmChartURLSecretName() string {
+	return ct.Name + "-helm-chart-url"
+}
+
+// GetRepositories returns the repository of the chart followed by the repository mirrors of
+// the template, in the order they are tried when fetching the chart
+func (ctSpec *ClusterTemplateSpec) GetRepositories(repoURL string) []string {
+	repos := []string{repoURL}
+	for _, mirror := range ctSpec.RepositoryMirrors {
+		if mirror != repoURL {
+			repos = append(repos, mirror)
+		}
+	}
+	return repos
+}
+
+// PinChartVersions replaces helm chart versions with the concrete versions resolved by
+// the ClusterTemplate controller, so version constraints are not re-evaluated later. Charts
+// fetched from a mirror are installed from the mirror too, charts of helmChartURL and embedded
+// charts from the repository of the operator serving them.
+func (ctSpec *ClusterTemplateSpec) PinChartVersions(ctStatus ClusterTemplateStatus) {
+	if ctSpec.ClusterDefinition.Source.Chart != "" {
+		if ctStatus.ClusterDefinition.Version != "" {
+			ctSpec.ClusterDefinition.Source.TargetRevision = ctStatus.ClusterDefinition.Version
+		}
+		if ctStatus.ClusterDefinition.RepoURL != "" {
+			ctSpec.ClusterDefinition.Source.RepoURL = ctStatus.ClusterDefinition.RepoURL
+		}
+	}
+	for i, setup := range ctSpec.ClusterSetup {
+		if setup.Spec.Source.Chart == "" {
+			continue
+		}
+		for _, setupStatus := range ctStatus.ClusterSetup {
+			if setupStatus.Name != setup.Name {
+				continue
+			}
+			if setupStatus.Version != "" {
+				ctSpec.ClusterSetup[i].Spec.Source.TargetRevision = setupStatus.Version
+			}
+			if setupStatus.RepoURL != "" {
+				ctSpec.ClusterSetup[i].Spec.Source.RepoURL = setupStatus.RepoURL
+			}
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package v1alpha1
+
+import (
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClusterTemplate utils", func() {
+	It("MigrateParameters", func() {
+		ctSpec := ClusterTemplateSpec{}
+		params := []Parameter{
+			{
+				Name:  "workers",
+				Value: "3",
+			},
+			{
+				Name:  "sshKey",
+				Value: "foo",
+			},
+			{
+				Name:         "workers",
+				Value:        "bar",
+				ClusterSetup: "day2",
+			},
+		}
+		Expect(ctSpec.MigrateParameters(params)).Should(Equal(params))
+
+		ctSpec.ParameterMigrations = []ParameterMigration{
+			{
+				Name:     "workers",
+				RenameTo: "nodeCount",
+			},
+			{
+				Name:     "nodeCount",
+				RenameTo: "nodePool.replicas",
+			},
+			{
+				Name: "sshKey",
+			},
+		}
+		Expect(ctSpec.MigrateParameters(params)).Should(Equal([]Parameter{
+			{
+				Name:  "nodePool.replicas",
+				Value: "3",
+			},
+			{
+				Name:         "workers",
+				Value:        "bar",
+				ClusterSetup: "day2",
+			},
+		}))
+	})
+
+	It("ResolveClusterSetupDefinitions", func() {
+		definition := &ClusterSetupDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "gitops",
+			},
+			Spec: ClusterSetupDefinitionSpec{
+				Setup: argo.ApplicationSpec{
+					Source: argo.ApplicationSource{
+						RepoURL: "http://foo.com",
+						Chart:   "gitops",
+					},
+				},
+			},
+		}
+		ctSpec := ClusterTemplateSpec{
+			ClusterSetup: []ClusterSetup{
+				{
+					Name: "inline",
+					Spec: argo.ApplicationSpec{
+						Source: argo.ApplicationSource{
+							RepoURL: "http://foo.com",
+							Chart:   "inline",
+						},
+					},
+				},
+				{
+					Name:          "shared",
+					DefinitionRef: "gitops",
+				},
+			},
+		}
+		ct := ClusterTemplate{Spec: ctSpec}
+		Expect(ct.UsesClusterSetupDefinition("gitops")).Should(BeTrue())
+		Expect(ct.UsesClusterSetupDefinition("foo")).Should(BeFalse())
+
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		err := ctSpec.DeepCopy().ResolveClusterSetupDefinitions(ctx, k8sClient)
+		Expect(err).Should(HaveOccurred())
+
+		k8sClient = fake.NewFakeClientWithScheme(scheme.Scheme, definition)
+		err = ctSpec.ResolveClusterSetupDefinitions(ctx, k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ctSpec.ClusterSetup[0].Spec.Source.Chart).Should(Equal("inline"))
+		Expect(ctSpec.ClusterSetup[1].Spec).Should(Equal(definition.Spec.Setup))
+	})
+
+	It("Resolves cluster setups with Git source", func() {
+		ctSpec := ClusterTemplateSpec{
+			ClusterSetup: []ClusterSetup{
+				{
+					Name: "gitops",
+					Git: &SetupGitSource{
+						RepoURL:   "https://github.com/foo/setup",
+						Path:      "clusters/base",
+						Namespace: "openshift-gitops",
+					},
+				},
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		Expect(ctSpec.ResolveClusterSetupDefinitions(ctx, k8sClient)).Should(Succeed())
+		Expect(ctSpec.ClusterSetup[0].Spec).Should(Equal(argo.ApplicationSpec{
+			Source: argo.ApplicationSource{
+				RepoURL:        "https://github.com/foo/setup",
+				Path:           "clusters/base",
+				TargetRevision: "HEAD",
+			},
+			Destination: argo.ApplicationDestination{
+				Server:    CTIClusterTargetVar,
+				Namespace: "openshift-gitops",
+			},
+			Project: "default",
+			SyncPolicy: &argo.SyncPolicy{
+				Automated: &argo.SyncPolicyAutomated{},
+			},
+		}))
+	})
+
+	It("PinChartVersions", func() {
+		ctSpec := ClusterTemplateSpec{
+			ClusterDefinition: argo.ApplicationSpec{
+				Source: argo.ApplicationSource{
+					Chart:          "hypershift-template",
+					TargetRevision: ">=1.2.0 <2.0.0",
+				},
+			},
+			ClusterSetup: []ClusterSetup{
+				{
+					Name: "day2",
+					Spec: argo.ApplicationSpec{
+						Source: argo.ApplicationSource{
+							Chart:          "day2",
+							TargetRevision: "~0.1",
+						},
+					},
+				},
+				{
+					Name: "git",
+					Spec: argo.ApplicationSpec{
+						Source: argo.ApplicationSource{
+							Path:           "setup",
+							TargetRevision: "main",
+						},
+					},
+				},
+			},
+		}
+		ctSpec.PinChartVersions(ClusterTemplateStatus{
+			ClusterDefinition: ClusterDefinitionSchema{
+				Version: "1.3.1",
+			},
+			ClusterSetup: []ClusterSetupSchema{
+				{
+					Name:    "day2",
+					Version: "0.1.4",
+				},
+				{
+					Name: "git",
+				},
+			},
+		})
+		Expect(ctSpec.ClusterDefinition.Source.TargetRevision).Should(Equal("1.3.1"))
+		Expect(ctSpec.ClusterSetup[0].Spec.Source.TargetRevision).Should(Equal("0.1.4"))
+		Expect(ctSpec.ClusterSetup[1].Spec.Source.TargetRevision).Should(Equal("main"))
+
+		ctSpec.PinChartVersions(ClusterTemplateStatus{
+			ClusterDefinition: ClusterDefinitionSchema{
+				Version: "1.3.1",
+				RepoURL: "https://mirror.example.com/charts",
+			},
+		})
+		Expect(ctSpec.ClusterDefinition.Source.RepoURL).
+			Should(Equal("https://mirror.example.com/charts"))
+
+		ctSpec.HelmChartURL = "https://foo.io/hypershift-template-1.3.1.tgz"
+		ctSpec.PinChartVersions(ClusterTemplateStatus{
+			ClusterDefinition: ClusterDefinitionSchema{
+				Version: "1.3.1",
+				RepoURL: "https://bridge.example.com/charts/secrets/foo-helm-chart-url",
+			},
+		})
+		Expect(ctSpec.ClusterDefinition.Source.RepoURL).
+			Should(Equal("https://bridge.example.com/charts/secrets/foo-helm-chart-url"))
+	})
+
+	It("GetRepositories", func() {
+		ctSpec := ClusterTemplateSpec{}
+		Expect(ctSpec.GetRepositories("https://foo.io")).Should(Equal([]string{"https://foo.io"}))
+
+		ctSpec.RepositoryMirrors = []string{"https://mirror1.io", "https://foo.io", "https://mirror2.io"}
+		Expect(ctSpec.GetRepositories("https://foo.io")).Should(Equal([]string{
+			"https://foo.io",
+			"https://mirror1.io",
+			"https://mirror2.io",
+		}))
+	})
+	It("SetRevisionHistoryLimit", func() {
+		templateLimit := int64(20)
+		ctSpec := ClusterTemplateSpec{
+			ClusterDefinition: argo.ApplicationSpec{
+				RevisionHistoryLimit: &templateLimit,
+			},
+			ClusterSetup: []ClusterSetup{
+				{
+					Name: "day2",
+				},
+			},
+		}
+		ctSpec.SetRevisionHistoryLimit(nil)
+		Expect(ctSpec.ClusterSetup[0].Spec.RevisionHistoryLimit).Should(BeNil())
+
+		limit := int64(3)
+		ctSpec.SetRevisionHistoryLimit(&limit)
+		Expect(*ctSpec.ClusterDefinition.RevisionHistoryLimit).Should(Equal(int64(20)))
+		Expect(*ctSpec.ClusterSetup[0].Spec.RevisionHistoryLimit).Should(Equal(int64(3)))
+	})
+	It("SetBaseDomain", func() {
+		ctSpec := ClusterTemplateSpec{}
+		Expect(ctSpec.GetHubBaseDomain("apps.hub.example.com")).Should(BeEmpty())
+		ctSpec.SetBaseDomain("apps.hub.example.com")
+		Expect(ctSpec.ClusterDefinition.Source.Helm).Should(BeNil())
+
+		ctSpec.BaseDomainFromHub = &BaseDomainFromHub{
+			Parameter: "baseDnsDomain",
+			Subdomain: "clusters",
+		}
+		domain := ctSpec.GetHubBaseDomain("apps.hub.example.com")
+		Expect(domain).Should(Equal("clusters.apps.hub.example.com"))
+		ctSpec.SetBaseDomain(domain)
+		Expect(ctSpec.GetBaseDomain()).Should(Equal("clusters.apps.hub.example.com"))
+
+		// parameter set by the template is kept
+		ctSpec.SetBaseDomain("apps.other.example.com")
+		Expect(ctSpec.GetBaseDomain()).Should(Equal("clusters.apps.hub.example.com"))
+		Expect(ctSpec.ClusterDefinition.Source.Helm.Parameters).Should(HaveLen(1))
+	})
+	It("ApplyAddOns", func() {
+		ctSpec := ClusterTemplateSpec{
+			ClusterDefinition: argo.ApplicationSpec{
+				Source: argo.ApplicationSource{
+					Chart: "hypershift-template",
+					Helm: &argo.ApplicationSourceHelm{
+						Values: "nodePool:\n  replicas: 2\n  instanceType: m5.large\n",
+					},
+				},
+			},
+			ClusterSetup: []ClusterSetup{
+				{
+					Name: "day2",
+				},
+			},
+			AddOns: []AddOn{
+				{
+					Name:   "gpu",
+					Values: "nodePool:\n  instanceType: g4dn.xlarge\n",
+				},
+				{
+					Name: "logging",
+					ClusterSetup: []ClusterSetup{
+						{
+							Name: "logging",
+						},
+					},
+				},
+			},
+		}
+
+		Expect(ctSpec.DeepCopy().ApplyAddOns(nil)).Should(Succeed())
+		Expect(ctSpec.ApplyAddOns(map[string]bool{"mesh": true})).Should(MatchError(
+			"add-on 'mesh' is not defined by the template",
+		))
+
+		disabled := ctSpec.DeepCopy()
+		Expect(disabled.ApplyAddOns(map[string]bool{"gpu": false})).Should(Succeed())
+		Expect(*disabled).Should(Equal(ctSpec))
+
+		Expect(ctSpec.ApplyAddOns(map[string]bool{"gpu": true, "logging": true})).Should(Succeed())
+		Expect(ctSpec.ClusterDefinition.Source.Helm.Values).Should(Equal(
+			"nodePool:\n  instanceType: g4dn.xlarge\n  replicas: 2\n",
+		))
+		Expect(ctSpec.ClusterSetup).Should(HaveLen(2))
+		Expect(ctSpec.ClusterSetup[1].Name).Should(Equal("logging"))
+	})
+})
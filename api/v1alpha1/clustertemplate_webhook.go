@@ -0,0 +1,575 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/chartutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+var clustertemplatelog = logf.Log.WithName("clustertemplate-resource")
+var templateControllerClient client.Client
+
+func (r *ClusterTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	templateControllerClient = mgr.GetClient()
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-clustertemplate-openshift-io-v1alpha1-clustertemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=clustertemplate.openshift.io,resources=clustertemplates,verbs=create;update,versions=v1alpha1,name=vclustertemplate.kb.io,admissionReviewVersions=v1
+//+kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplatetaxonomies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustersizeclasses,verbs=get;list;watch
+
+var _ webhook.Validator = &ClusterTemplate{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterTemplate) ValidateCreate() error {
+	clustertemplatelog.Info("validate create", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterTemplate) ValidateUpdate(old runtime.Object) error {
+	clustertemplatelog.Info("validate update", "name", r.Name)
+	return r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterTemplate) ValidateDelete() error {
+	return nil
+}
+
+// validate checks the template, created and updated templates are validated the same way
+func (r *ClusterTemplate) validate() error {
+	for _, validate := range []func() error{
+		r.validatePostRenderer,
+		r.validateAddOns,
+		r.validateChartVerification,
+		r.validateEmbeddedChart,
+		r.validateHelmChartURL,
+		r.validateBaseDomainFromHub,
+		r.validateChartTests,
+		r.validateHostingCluster,
+		r.validateClusterPool,
+		r.validateOCM,
+		r.validateDeletionGates,
+		r.validateSetupIdentities,
+		r.validateSetupJobs,
+		r.validateSetupGitSources,
+		r.validateSetupAnsibleJobs,
+		r.validateSetupDependencies,
+		r.validateSizeClasses,
+		r.validateMaxLifetime,
+		r.validateProvisioningSLO,
+		r.validateClusterName,
+		r.validateCatalog,
+	} {
+		if err := validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePostRenderer checks the post renderer is used with Helm chart cluster definition
+func (r *ClusterTemplate) validatePostRenderer() error {
+	if r.Spec.PostRenderer == nil {
+		return nil
+	}
+	if r.Spec.ClusterDefinition.Source.Chart == "" {
+		return fmt.Errorf("postRenderer requires clusterDefinition with Helm chart source")
+	}
+	return nil
+}
+
+// validateChartVerification checks charts are the source of the cluster definition and of the
+// cluster setups when the chart verification is required. Setups referencing ClusterSetupDefinitions
+// are checked when an instance is created.
+func (r *ClusterTemplate) validateChartVerification() error {
+	if r.Spec.ChartVerification == nil {
+		return nil
+	}
+	if r.Spec.ClusterDefinition.Source.Chart == "" {
+		return fmt.Errorf("chartVerification requires clusterDefinition with Helm chart source")
+	}
+	for _, setup := range r.Spec.ClusterSetup {
+		if setup.DefinitionRef == "" && setup.Spec.Source.Chart == "" {
+			return fmt.Errorf(
+				"chartVerification requires cluster setup '%s' with Helm chart source",
+				setup.Name,
+			)
+		}
+	}
+	return nil
+}
+
+// validateEmbeddedChart checks the embedded chart replaces Helm chart source of the cluster
+// definition and no other chart is set
+func (r *ClusterTemplate) validateEmbeddedChart() error {
+	if r.Spec.EmbeddedChart == nil {
+		return nil
+	}
+	if r.Spec.ClusterDefinition.Source.Chart == "" {
+		return fmt.Errorf("embeddedChart requires clusterDefinition with Helm chart source")
+	}
+	if r.Spec.HelmChartURL != "" {
+		return fmt.Errorf("embeddedChart and helmChartURL can not be set together")
+	}
+	if r.Spec.ChartVerification != nil {
+		return fmt.Errorf("chartVerification is not supported for embeddedChart")
+	}
+	return nil
+}
+
+// validateHelmChartURL checks the chart tarball is served for the Helm chart source of the cluster
+// definition with an exact version, ArgoCD installs the served chart by name and version.
+func (r *ClusterTemplate) validateHelmChartURL() error {
+	if r.Spec.HelmChartURL == "" {
+		return nil
+	}
+	source := r.Spec.ClusterDefinition.Source
+	if source.Chart == "" {
+		return fmt.Errorf("helmChartURL requires clusterDefinition with Helm chart source")
+	}
+	if _, err := semver.NewVersion(source.TargetRevision); err != nil {
+		return fmt.Errorf(
+			"helmChartURL requires exact chart version in clusterDefinition source targetRevision",
+		)
+	}
+	return nil
+}
+
+// validateBaseDomainFromHub checks the base domain is derived for Helm chart cluster definition
+func (r *ClusterTemplate) validateBaseDomainFromHub() error {
+	if r.Spec.BaseDomainFromHub == nil {
+		return nil
+	}
+	if r.Spec.ClusterDefinition.Source.Chart == "" && r.Spec.HelmChartURL == "" {
+		return fmt.Errorf("baseDomainFromHub requires clusterDefinition with Helm chart source")
+	}
+	return nil
+}
+
+// validateChartTests checks the chart tests are used with Helm chart cluster definition
+func (r *ClusterTemplate) validateChartTests() error {
+	if r.Spec.ChartTests == nil {
+		return nil
+	}
+	if r.Spec.ClusterDefinition.Source.Chart == "" && r.Spec.HelmChartURL == "" {
+		return fmt.Errorf("chartTests requires clusterDefinition with Helm chart source")
+	}
+	return nil
+}
+
+func (r *ClusterTemplate) validateMaxLifetime() error {
+	if r.Spec.MaxLifetime != nil && r.Spec.MaxLifetime.Duration <= 0 {
+		return fmt.Errorf("maxLifetime must be positive")
+	}
+	return nil
+}
+
+func (r *ClusterTemplate) validateProvisioningSLO() error {
+	slo := r.Spec.ProvisioningSLO
+	if slo == nil {
+		return nil
+	}
+	if slo.ClusterInstall != nil && slo.ClusterInstall.Duration <= 0 {
+		return fmt.Errorf("provisioningSLO.clusterInstall must be positive")
+	}
+	if slo.Ready != nil && slo.Ready.Duration <= 0 {
+		return fmt.Errorf("provisioningSLO.ready must be positive")
+	}
+	return nil
+}
+
+// validateClusterName checks the cluster name is passed to the values of the cluster definition
+func (r *ClusterTemplate) validateClusterName() error {
+	if r.Spec.ClusterName == nil {
+		return nil
+	}
+	if r.Spec.ClusterName.ValuesKey == "" {
+		return fmt.Errorf("clusterName.valuesKey must be set")
+	}
+	if r.Spec.ClusterPool != nil || r.Spec.OCM != nil {
+		return fmt.Errorf("clusterName requires clusterDefinition")
+	}
+	return nil
+}
+
+// validateHostingCluster checks features running on the hub next to the cluster definition are
+// not used with a remote hosting cluster
+func (r *ClusterTemplate) validateHostingCluster() error {
+	if r.Spec.HostingCluster == nil {
+		return nil
+	}
+	if r.Spec.ChartTests != nil {
+		return fmt.Errorf("chartTests are not supported with hostingCluster")
+	}
+	return nil
+}
+
+// validateClusterPool checks templates claiming clusters from a pool do not install the cluster
+// definition. Features of the cluster definition are already rejected for templates without
+// Helm chart.
+func (r *ClusterTemplate) validateClusterPool() error {
+	if r.Spec.ClusterPool == nil {
+		return nil
+	}
+	if r.Spec.ClusterDefinition.Source.RepoURL != "" {
+		return fmt.Errorf("clusterDefinition and clusterPool can not be set together")
+	}
+	if r.Spec.HostingCluster != nil {
+		return fmt.Errorf("hostingCluster is not supported with clusterPool")
+	}
+	if r.Spec.NodePools != nil {
+		return fmt.Errorf("nodePools are not supported with clusterPool")
+	}
+	return nil
+}
+
+// validateOCM checks the OCM cluster replaces the cluster definition and its additional
+// properties can be parsed
+func (r *ClusterTemplate) validateOCM() error {
+	if r.Spec.OCM == nil {
+		return nil
+	}
+	if r.Spec.ClusterDefinition.Source.RepoURL != "" {
+		return fmt.Errorf("clusterDefinition and ocm can not be set together")
+	}
+	if r.Spec.ClusterPool != nil {
+		return fmt.Errorf("clusterPool and ocm can not be set together")
+	}
+	if r.Spec.HostingCluster != nil {
+		return fmt.Errorf("hostingCluster is not supported with ocm")
+	}
+	if r.Spec.NodePools != nil {
+		return fmt.Errorf("nodePools are not supported with ocm")
+	}
+	_, err := r.Spec.OCM.GetClusterProperties("", "")
+	return err
+}
+
+// validateDeletionGates checks names of deletion gates are unique and every gate is either an URL
+// or a hold annotation
+func (r *ClusterTemplate) validateDeletionGates() error {
+	gates := map[string]bool{}
+	for _, gate := range r.Spec.DeletionGates {
+		if gates[gate.Name] {
+			return fmt.Errorf("deletion gate '%s' is defined more than once", gate.Name)
+		}
+		gates[gate.Name] = true
+		if (gate.URL == "") == (gate.HoldAnnotation == "") {
+			return fmt.Errorf(
+				"deletion gate '%s' has to set exactly one of url and holdAnnotation",
+				gate.Name,
+			)
+		}
+	}
+	return nil
+}
+
+// validateSetupIdentities checks ServiceAccount identities of cluster setups define their
+// permissions
+func (r *ClusterTemplate) validateSetupIdentities() error {
+	setups := append([]ClusterSetup{}, r.Spec.ClusterSetup...)
+	for _, addOn := range r.Spec.AddOns {
+		setups = append(setups, addOn.ClusterSetup...)
+	}
+	for _, setup := range setups {
+		if setup.Identity == nil {
+			continue
+		}
+		hasRules := len(setup.Identity.Rules) > 0
+		if setup.Identity.Type == ServiceAccountIdentity && !hasRules {
+			return fmt.Errorf(
+				"identity of cluster setup '%s' has to set rules of the ServiceAccount",
+				setup.Name,
+			)
+		}
+		if setup.Identity.Type == KubeconfigIdentity && hasRules {
+			return fmt.Errorf(
+				"identity of cluster setup '%s' sets rules, which are supported with ServiceAccount type only",
+				setup.Name,
+			)
+		}
+	}
+	return nil
+}
+
+// validateSetupJobs checks cluster setups run by Jobs do not define an ArgoCD Application
+func (r *ClusterTemplate) validateSetupJobs() error {
+	setups := append([]ClusterSetup{}, r.Spec.ClusterSetup...)
+	for _, addOn := range r.Spec.AddOns {
+		setups = append(setups, addOn.ClusterSetup...)
+	}
+	for _, setup := range setups {
+		if setup.Job == nil {
+			continue
+		}
+		if setup.DefinitionRef != "" || setup.Spec.Source.RepoURL != "" {
+			return fmt.Errorf(
+				"cluster setup '%s' sets job, it can not set spec or definitionRef",
+				setup.Name,
+			)
+		}
+	}
+	return nil
+}
+
+// validateSetupGitSources checks cluster setups with Git source do not define the ArgoCD
+// Application or a Job
+func (r *ClusterTemplate) validateSetupGitSources() error {
+	setups := append([]ClusterSetup{}, r.Spec.ClusterSetup...)
+	for _, addOn := range r.Spec.AddOns {
+		setups = append(setups, addOn.ClusterSetup...)
+	}
+	for _, setup := range setups {
+		if setup.Git == nil {
+			continue
+		}
+		if setup.DefinitionRef != "" || setup.Spec.Source.RepoURL != "" || setup.Job != nil {
+			return fmt.Errorf(
+				"cluster setup '%s' sets git, it can not set spec, definitionRef or job",
+				setup.Name,
+			)
+		}
+	}
+	return nil
+}
+
+// validateSetupAnsibleJobs checks cluster setups run by Ansible jobs do not define other kind of
+// setup and their extra variables can be parsed
+func (r *ClusterTemplate) validateSetupAnsibleJobs() error {
+	setups := append([]ClusterSetup{}, r.Spec.ClusterSetup...)
+	for _, addOn := range r.Spec.AddOns {
+		setups = append(setups, addOn.ClusterSetup...)
+	}
+	for _, setup := range setups {
+		if setup.AnsibleJob == nil {
+			continue
+		}
+		if setup.DefinitionRef != "" || setup.Spec.Source.RepoURL != "" || setup.Job != nil ||
+			setup.Git != nil {
+			return fmt.Errorf(
+				"cluster setup '%s' sets ansibleJob, it can not set spec, definitionRef, job or git",
+				setup.Name,
+			)
+		}
+		if _, err := chartutil.ReadValues([]byte(setup.AnsibleJob.ExtraVars)); err != nil {
+			return fmt.Errorf(
+				"failed to parse extra variables of cluster setup '%s' - %q",
+				setup.Name,
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+// validateSetupDependencies checks cluster setups depend on existing cluster setups without a
+// cycle. Setups of the template can depend on setups of the template only, setups of an add-on on
+// setups of the template and of the same add-on, which are always created together.
+func (r *ClusterTemplate) validateSetupDependencies() error {
+	dependencies := map[string][]string{}
+	for _, setup := range r.Spec.ClusterSetup {
+		dependencies[setup.Name] = setup.DependsOn
+	}
+	groups := [][]ClusterSetup{r.Spec.ClusterSetup}
+	for _, addOn := range r.Spec.AddOns {
+		groups = append(groups, addOn.ClusterSetup)
+		for _, setup := range addOn.ClusterSetup {
+			dependencies[setup.Name] = setup.DependsOn
+		}
+	}
+	for i, group := range groups {
+		allowed := map[string]bool{}
+		for _, setup := range r.Spec.ClusterSetup {
+			allowed[setup.Name] = true
+		}
+		for _, setup := range group {
+			allowed[setup.Name] = true
+		}
+		for _, setup := range group {
+			for _, dependency := range setup.DependsOn {
+				if !allowed[dependency] {
+					if i == 0 {
+						return fmt.Errorf(
+							"cluster setup '%s' depends on unknown cluster setup '%s'",
+							setup.Name,
+							dependency,
+						)
+					}
+					return fmt.Errorf(
+						"cluster setup '%s' of add-on '%s' depends on unknown cluster setup '%s'",
+						setup.Name,
+						r.Spec.AddOns[i-1].Name,
+						dependency,
+					)
+				}
+			}
+		}
+	}
+	// depth first search, setups on the current path are visiting
+	visiting := map[string]bool{}
+	visited := map[string]bool{}
+	var visit func(name string) error
+	visit = func(name string) error {
+		if visiting[name] {
+			return fmt.Errorf("dependencies of cluster setup '%s' form a cycle", name)
+		}
+		if visited[name] {
+			return nil
+		}
+		visiting[name] = true
+		for _, dependency := range dependencies[name] {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		visiting[name] = false
+		visited[name] = true
+		return nil
+	}
+	for _, group := range groups {
+		for _, setup := range group {
+			if err := visit(setup.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateAddOns checks names of add-ons and of their cluster setups are unique and values
+// of add-ons can be parsed
+func (r *ClusterTemplate) validateAddOns() error {
+	addOns := map[string]bool{}
+	setups := map[string]bool{}
+	for _, setup := range r.Spec.ClusterSetup {
+		setups[setup.Name] = true
+	}
+	for _, addOn := range r.Spec.AddOns {
+		if addOns[addOn.Name] {
+			return fmt.Errorf("add-on '%s' is defined more than once", addOn.Name)
+		}
+		addOns[addOn.Name] = true
+		if _, err := chartutil.ReadValues([]byte(addOn.Values)); err != nil {
+			return fmt.Errorf("failed to parse values of add-on '%s' - %q", addOn.Name, err)
+		}
+		for _, setup := range addOn.ClusterSetup {
+			if setups[setup.Name] {
+				return fmt.Errorf(
+					"cluster setup '%s' of add-on '%s' is already defined",
+					setup.Name,
+					addOn.Name,
+				)
+			}
+			setups[setup.Name] = true
+		}
+	}
+	return nil
+}
+
+// validateSizeClasses checks the size classes of the template are defined
+func (r *ClusterTemplate) validateSizeClasses() error {
+	for _, name := range r.Spec.SizeClasses {
+		if err := templateControllerClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: name},
+			&ClusterSizeClass{},
+		); err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("cluster size class '%s' not found", name)
+			}
+			return fmt.Errorf("failed to get cluster size class '%s' - %q", name, err)
+		}
+	}
+	return nil
+}
+
+func (r *ClusterTemplate) validateCatalog() error {
+	if r.Spec.Catalog == nil {
+		return nil
+	}
+	taxonomies := ClusterTemplateTaxonomyList{}
+	if err := templateControllerClient.List(context.TODO(), &taxonomies); err != nil {
+		return fmt.Errorf("failed to list cluster template taxonomies - %q", err)
+	}
+	// without a taxonomy, the catalog is not restricted
+	if len(taxonomies.Items) == 0 {
+		return nil
+	}
+	taxonomy := mergeTaxonomies(taxonomies.Items)
+	catalog := r.Spec.Catalog
+	if err := checkCategory("provider", catalog.Provider, taxonomy.Providers); err != nil {
+		return err
+	}
+	if err := checkCategory("size", catalog.Size, taxonomy.Sizes); err != nil {
+		return err
+	}
+	if err := checkCategory("purpose", catalog.Purpose, taxonomy.Purposes); err != nil {
+		return err
+	}
+	if err := checkCategory(
+		"complianceLevel",
+		catalog.ComplianceLevel,
+		taxonomy.ComplianceLevels,
+	); err != nil {
+		return err
+	}
+	for _, tag := range catalog.Tags {
+		if err := checkCategory("tag", tag, taxonomy.Tags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeTaxonomies returns the union of allowed values of all taxonomies
+func mergeTaxonomies(taxonomies []ClusterTemplateTaxonomy) ClusterTemplateTaxonomySpec {
+	merged := ClusterTemplateTaxonomySpec{}
+	for _, taxonomy := range taxonomies {
+		merged.Providers = append(merged.Providers, taxonomy.Spec.Providers...)
+		merged.Sizes = append(merged.Sizes, taxonomy.Spec.Sizes...)
+		merged.Purposes = append(merged.Purposes, taxonomy.Spec.Purposes...)
+		merged.ComplianceLevels = append(merged.ComplianceLevels, taxonomy.Spec.ComplianceLevels...)
+		merged.Tags = append(merged.Tags, taxonomy.Spec.Tags...)
+	}
+	return merged
+}
+
+func checkCategory(category string, value string, allowed []string) error {
+	if value == "" || len(allowed) == 0 {
+		return nil
+	}
+	for _, a := range allowed {
+		if a == value {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s '%s' is not defined in any taxonomy, allowed values are %v", category, value, allowed)
+}
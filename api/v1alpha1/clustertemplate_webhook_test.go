@@ -0,0 +1,458 @@
+package v1alpha1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClusterTemplate validating webhook", func() {
+	var scheme *runtime.Scheme
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(AddToScheme(scheme)).Should(Succeed())
+	})
+
+	getCT := func(catalog *ClusterTemplateCatalog) *ClusterTemplate {
+		return &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo",
+			},
+			Spec: ClusterTemplateSpec{
+				Catalog: catalog,
+			},
+		}
+	}
+
+	taxonomies := []runtime.Object{
+		&ClusterTemplateTaxonomy{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "providers",
+			},
+			Spec: ClusterTemplateTaxonomySpec{
+				Providers: []string{"aws", "azure"},
+				Tags:      []string{"gpu"},
+			},
+		},
+		&ClusterTemplateTaxonomy{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "sizes",
+			},
+			Spec: ClusterTemplateTaxonomySpec{
+				Sizes: []string{"small", "large"},
+			},
+		},
+	}
+
+	It("Allows any catalog without taxonomy", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(&ClusterTemplateCatalog{Provider: "foo"})
+		Expect(ct.ValidateCreate()).Should(Succeed())
+	})
+
+	It("Allows values defined in taxonomies", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme, taxonomies...)
+		ct := getCT(&ClusterTemplateCatalog{
+			Provider: "aws",
+			Size:     "small",
+			Purpose:  "development",
+			Tags:     []string{"gpu"},
+		})
+		Expect(ct.ValidateCreate()).Should(Succeed())
+	})
+
+	It("Rejects values not defined in taxonomies", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme, taxonomies...)
+		ct := getCT(&ClusterTemplateCatalog{Provider: "gcp"})
+		err := ct.ValidateCreate()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal(
+			"provider 'gcp' is not defined in any taxonomy, allowed values are [aws azure]",
+		))
+
+		ct = getCT(&ClusterTemplateCatalog{Tags: []string{"arm"}})
+		Expect(ct.ValidateUpdate(ct)).ShouldNot(Succeed())
+	})
+
+	It("Rejects post renderer without Helm chart", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.PostRenderer = &PostRenderer{RepoURL: "https://foo.io/patches.git", Path: "hub"}
+		err := ct.ValidateCreate()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal(
+			"postRenderer requires clusterDefinition with Helm chart source",
+		))
+
+		ct.Spec.ClusterDefinition.Source.Chart = "hypershift-template"
+		Expect(ct.ValidateUpdate(ct)).Should(Succeed())
+	})
+	It("Rejects chart verification without Helm charts", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.ChartVerification = &ChartVerification{PublicKeysConfigMap: "keys"}
+		err := ct.ValidateCreate()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal(
+			"chartVerification requires clusterDefinition with Helm chart source",
+		))
+
+		ct.Spec.ClusterDefinition.Source.Chart = "hypershift-template"
+		ct.Spec.ClusterSetup = []ClusterSetup{{Name: "day2"}, {Name: "logging", DefinitionRef: "logging"}}
+		err = ct.ValidateUpdate(ct)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal(
+			"chartVerification requires cluster setup 'day2' with Helm chart source",
+		))
+
+		ct.Spec.ClusterSetup[0].Spec.Source.Chart = "day2"
+		Expect(ct.ValidateUpdate(ct)).Should(Succeed())
+	})
+	It("Validates embedded chart", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.EmbeddedChart = &EmbeddedChart{Kind: "ConfigMap", Name: "hypershift-template"}
+		err := ct.ValidateCreate()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal(
+			"embeddedChart requires clusterDefinition with Helm chart source",
+		))
+
+		ct.Spec.ClusterDefinition.Source.Chart = "hypershift-template"
+		ct.Spec.HelmChartURL = "https://foo.io/hypershift-template-0.0.2.tgz"
+		err = ct.ValidateUpdate(ct)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("embeddedChart and helmChartURL can not be set together"))
+
+		ct.Spec.HelmChartURL = ""
+		Expect(ct.ValidateUpdate(ct)).Should(Succeed())
+	})
+	It("Validates chart tests", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.ChartTests = &ChartTests{}
+		err := ct.ValidateCreate()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal(
+			"chartTests requires clusterDefinition with Helm chart source",
+		))
+
+		ct.Spec.ClusterDefinition.Source.Chart = "hypershift-template"
+		Expect(ct.ValidateUpdate(ct)).Should(Succeed())
+	})
+	It("Validates helm chart URL", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.HelmChartURL = "https://foo.io/hypershift-template-0.0.2.tgz"
+		Expect(ct.ValidateCreate()).Should(MatchError(
+			"helmChartURL requires clusterDefinition with Helm chart source",
+		))
+
+		ct.Spec.ClusterDefinition.Source.Chart = "hypershift-template"
+		ct.Spec.ClusterDefinition.Source.TargetRevision = ">=0.0.1"
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(
+			"helmChartURL requires exact chart version in clusterDefinition source targetRevision",
+		))
+
+		ct.Spec.ClusterDefinition.Source.TargetRevision = "0.0.2"
+		Expect(ct.ValidateUpdate(ct)).Should(Succeed())
+	})
+	It("Validates maximum lifetime", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.MaxLifetime = &v1.Duration{}
+		Expect(ct.ValidateCreate()).Should(MatchError("maxLifetime must be positive"))
+
+		ct.Spec.MaxLifetime = &v1.Duration{Duration: 168 * time.Hour}
+		Expect(ct.ValidateUpdate(ct)).Should(Succeed())
+	})
+	It("Validates provisioning SLO", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.ProvisioningSLO = &ProvisioningSLO{ClusterInstall: &v1.Duration{}}
+		Expect(ct.ValidateCreate()).Should(
+			MatchError("provisioningSLO.clusterInstall must be positive"),
+		)
+
+		ct.Spec.ProvisioningSLO = &ProvisioningSLO{
+			ClusterInstall: &v1.Duration{Duration: 45 * time.Minute},
+			Ready:          &v1.Duration{Duration: -time.Hour},
+		}
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError("provisioningSLO.ready must be positive"))
+
+		ct.Spec.ProvisioningSLO.Ready = &v1.Duration{Duration: time.Hour}
+		Expect(ct.ValidateUpdate(ct)).Should(Succeed())
+	})
+	It("Validates cluster name", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.ClusterName = &ClusterName{}
+		Expect(ct.ValidateCreate()).Should(MatchError("clusterName.valuesKey must be set"))
+
+		ct.Spec.ClusterName.ValuesKey = "clusterName"
+		ct.Spec.ClusterPool = &ClusterPoolRef{Name: "aws-pool", Namespace: "pools"}
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError("clusterName requires clusterDefinition"))
+
+		ct.Spec.ClusterPool = nil
+		Expect(ct.ValidateUpdate(ct)).Should(Succeed())
+	})
+	It("Validates base domain from hub", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.BaseDomainFromHub = &BaseDomainFromHub{Parameter: "baseDnsDomain"}
+		Expect(ct.ValidateCreate()).Should(MatchError(
+			"baseDomainFromHub requires clusterDefinition with Helm chart source",
+		))
+
+		ct.Spec.ClusterDefinition.Source.Chart = "hypershift-template"
+		Expect(ct.ValidateUpdate(ct)).Should(Succeed())
+	})
+	It("Validates hosting cluster", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.ClusterDefinition.Source.Chart = "hypershift-template"
+		ct.Spec.HostingCluster = &HostingCluster{KubeconfigSecret: "hosting-kubeconfig"}
+		Expect(ct.ValidateCreate()).Should(Succeed())
+
+		ct.Spec.ChartTests = &ChartTests{}
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(
+			"chartTests are not supported with hostingCluster",
+		))
+	})
+	It("Validates cluster pool", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.ClusterPool = &ClusterPoolRef{Name: "aws-pool", Namespace: "pools"}
+		Expect(ct.ValidateCreate()).Should(Succeed())
+
+		ct.Spec.ClusterDefinition.Source.RepoURL = "https://charts.example.com"
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(
+			"clusterDefinition and clusterPool can not be set together",
+		))
+
+		ct.Spec.ClusterDefinition.Source.RepoURL = ""
+		ct.Spec.HostingCluster = &HostingCluster{KubeconfigSecret: "hosting-kubeconfig"}
+		Expect(ct.ValidateCreate()).Should(MatchError(
+			"hostingCluster is not supported with clusterPool",
+		))
+	})
+	It("Validates OCM cluster", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.OCM = &OCMCluster{
+			CredentialsSecret: "ocm-credentials",
+			Product:           "osd",
+			Region:            "us-east-1",
+		}
+		Expect(ct.ValidateCreate()).Should(Succeed())
+
+		ct.Spec.ClusterDefinition.Source.RepoURL = "https://charts.example.com"
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(
+			"clusterDefinition and ocm can not be set together",
+		))
+
+		ct.Spec.ClusterDefinition.Source.RepoURL = ""
+		ct.Spec.OCM.Properties = "- not an object"
+		Expect(ct.ValidateCreate()).Should(HaveOccurred())
+	})
+	It("Validates deletion gates", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.DeletionGates = []DeletionGate{
+			{Name: "billing", URL: "https://billing.example.com/decommission"},
+			{Name: "security", HoldAnnotation: "security.example.com/hold"},
+		}
+		Expect(ct.ValidateCreate()).Should(Succeed())
+
+		ct.Spec.DeletionGates[1].URL = "https://security.example.com"
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(
+			"deletion gate 'security' has to set exactly one of url and holdAnnotation",
+		))
+
+		ct.Spec.DeletionGates[1] = DeletionGate{Name: "billing", HoldAnnotation: "billing/hold"}
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(
+			"deletion gate 'billing' is defined more than once",
+		))
+	})
+	It("Validates identities of cluster setups", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.ClusterSetup = []ClusterSetup{
+			{Name: "admin", Identity: &SetupIdentity{Type: KubeconfigIdentity}},
+		}
+		ct.Spec.AddOns = []AddOn{
+			{
+				Name: "logging",
+				ClusterSetup: []ClusterSetup{
+					{
+						Name: "logging",
+						Identity: &SetupIdentity{
+							Type: ServiceAccountIdentity,
+							Rules: []rbacv1.PolicyRule{
+								{
+									APIGroups: []string{"logging.openshift.io"},
+									Resources: []string{"clusterloggings"},
+									Verbs:     []string{"*"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(ct.ValidateCreate()).Should(Succeed())
+
+		ct.Spec.AddOns[0].ClusterSetup[0].Identity.Rules = nil
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(
+			"identity of cluster setup 'logging' has to set rules of the ServiceAccount",
+		))
+
+		ct.Spec.AddOns = nil
+		ct.Spec.ClusterSetup[0].Identity.Rules = []rbacv1.PolicyRule{{Verbs: []string{"get"}}}
+		Expect(ct.ValidateCreate()).Should(MatchError(
+			"identity of cluster setup 'admin' sets rules, which are supported with ServiceAccount type only",
+		))
+	})
+	It("Validates Jobs of cluster setups", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.ClusterSetup = []ClusterSetup{
+			{
+				Name: "monitoring",
+				Job: &SetupJob{
+					Image:  "quay.io/openshift/origin-cli:latest",
+					Script: "oc apply -f https://example.com/monitoring.yaml",
+				},
+			},
+		}
+		Expect(ct.ValidateCreate()).Should(Succeed())
+
+		ct.Spec.ClusterSetup[0].DefinitionRef = "monitoring"
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(
+			"cluster setup 'monitoring' sets job, it can not set spec or definitionRef",
+		))
+	})
+	It("Validates Git sources of cluster setups", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.ClusterSetup = []ClusterSetup{
+			{
+				Name: "gitops",
+				Git: &SetupGitSource{
+					RepoURL: "https://github.com/foo/setup",
+					Path:    "clusters/base",
+				},
+			},
+		}
+		Expect(ct.ValidateCreate()).Should(Succeed())
+
+		ct.Spec.ClusterSetup[0].Spec.Source.RepoURL = "https://github.com/foo/other"
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(
+			"cluster setup 'gitops' sets git, it can not set spec, definitionRef or job",
+		))
+	})
+	It("Validates Ansible jobs of cluster setups", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.ClusterSetup = []ClusterSetup{
+			{
+				Name: "register",
+				AnsibleJob: &SetupAnsibleJob{
+					URL:               "https://aap.example.com",
+					CredentialsSecret: "aap-credentials",
+					JobTemplateID:     7,
+					ExtraVars:         "inventory: prod\n",
+				},
+			},
+		}
+		Expect(ct.ValidateCreate()).Should(Succeed())
+
+		ct.Spec.ClusterSetup[0].AnsibleJob.ExtraVars = "foo"
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(ContainSubstring(
+			"failed to parse extra variables of cluster setup 'register'",
+		)))
+
+		ct.Spec.ClusterSetup[0].AnsibleJob.ExtraVars = ""
+		ct.Spec.ClusterSetup[0].DefinitionRef = "register"
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(
+			"cluster setup 'register' sets ansibleJob, it can not set spec, definitionRef, job or git",
+		))
+	})
+	It("Validates dependencies of cluster setups", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.ClusterSetup = []ClusterSetup{
+			{Name: "install-operators"},
+			{Name: "configure-idp", DependsOn: []string{"install-operators"}},
+			{Name: "deploy-apps", DependsOn: []string{"install-operators", "configure-idp"}},
+		}
+		ct.Spec.AddOns = []AddOn{
+			{
+				Name: "logging",
+				ClusterSetup: []ClusterSetup{
+					{Name: "logging-operator", DependsOn: []string{"install-operators"}},
+					{Name: "logging", DependsOn: []string{"logging-operator"}},
+				},
+			},
+		}
+		Expect(ct.ValidateCreate()).Should(Succeed())
+
+		ct.Spec.ClusterSetup[0].DependsOn = []string{"logging"}
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(
+			"cluster setup 'install-operators' depends on unknown cluster setup 'logging'",
+		))
+
+		ct.Spec.ClusterSetup[0].DependsOn = []string{"deploy-apps"}
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(
+			"dependencies of cluster setup 'install-operators' form a cycle",
+		))
+
+		ct.Spec.ClusterSetup[0].DependsOn = nil
+		ct.Spec.AddOns[0].ClusterSetup[0].DependsOn = []string{"mesh"}
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(
+			"cluster setup 'logging-operator' of add-on 'logging' depends on unknown cluster " +
+				"setup 'mesh'",
+		))
+	})
+	It("Validates add-ons", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(scheme)
+		ct := getCT(nil)
+		ct.Spec.ClusterSetup = []ClusterSetup{{Name: "day2"}}
+		ct.Spec.AddOns = []AddOn{
+			{
+				Name:         "logging",
+				Values:       "logging:\n  enabled: true\n",
+				ClusterSetup: []ClusterSetup{{Name: "logging"}},
+			},
+		}
+		Expect(ct.ValidateCreate()).Should(Succeed())
+
+		ct.Spec.AddOns = append(ct.Spec.AddOns, AddOn{Name: "logging"})
+		Expect(ct.ValidateCreate()).Should(MatchError("add-on 'logging' is defined more than once"))
+
+		ct.Spec.AddOns[1] = AddOn{Name: "gpu", ClusterSetup: []ClusterSetup{{Name: "day2"}}}
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError(
+			"cluster setup 'day2' of add-on 'gpu' is already defined",
+		))
+
+		ct.Spec.AddOns[1] = AddOn{Name: "gpu", Values: "foo"}
+		Expect(ct.ValidateUpdate(ct)).ShouldNot(Succeed())
+	})
+	It("Validates size classes", func() {
+		templateControllerClient = fake.NewFakeClientWithScheme(
+			scheme,
+			&ClusterSizeClass{ObjectMeta: v1.ObjectMeta{Name: "small"}},
+		)
+		ct := getCT(nil)
+		ct.Spec.SizeClasses = []string{"small"}
+		Expect(ct.ValidateCreate()).Should(Succeed())
+
+		ct.Spec.SizeClasses = append(ct.Spec.SizeClasses, "large")
+		Expect(ct.ValidateUpdate(ct)).Should(MatchError("cluster size class 'large' not found"))
+	})
+})
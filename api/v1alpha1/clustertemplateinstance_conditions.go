@@ -13,7 +13,36 @@ const (
 	ArgoClusterAdded         ConditionType = "ArgoClusterAdded"
 	ClusterSetupCreated      ConditionType = "ClusterSetupCreated"
 	ClusterSetupSucceeded    ConditionType = "ClusterSetupSucceeded"
+	ClusterDefinitionDrifted ConditionType = "ClusterDefinitionDrifted"
+	ParametersValid          ConditionType = "ParametersValid"
+	DefaultsDrifted          ConditionType = "DefaultsDrifted"
+	ChartTestsSucceeded      ConditionType = "ChartTestsSucceeded"
+	ClusterUpgraded          ConditionType = "ClusterUpgraded"
+	Hibernated               ConditionType = "Hibernated"
+	SetupPaused              ConditionType = "SetupPaused"
+	Expiring                 ConditionType = "Expiring"
+	ProvisioningSLOMet       ConditionType = "ProvisioningSLOMet"
 	Ready                    ConditionType = "Ready"
+	// Reconciling and Stalled together with Ready follow kstatus conventions
+	// https://github.com/kubernetes-sigs/cli-utils/blob/master/pkg/kstatus/README.md
+	Reconciling ConditionType = "Reconciling"
+	Stalled     ConditionType = "Stalled"
+	// conditions of the HostedCluster mirrored by instances of hypershift clusters
+	HostedClusterAvailable           ConditionType = "HostedClusterAvailable"
+	HostedClusterDegraded            ConditionType = "HostedClusterDegraded"
+	HostedClusterEtcdAvailable       ConditionType = "HostedClusterEtcdAvailable"
+	HostedClusterInfrastructureReady ConditionType = "HostedClusterInfrastructureReady"
+	// conditions of the ClusterDeployment mirrored by instances of Hive clusters
+	ClusterDeploymentProvisioned      ConditionType = "ClusterDeploymentProvisioned"
+	ClusterDeploymentProvisionFailed  ConditionType = "ClusterDeploymentProvisionFailed"
+	ClusterDeploymentProvisionStopped ConditionType = "ClusterDeploymentProvisionStopped"
+)
+
+type ReadyReason string
+
+const (
+	ClusterReady    ReadyReason = "ClusterReady"
+	ClusterNotReady ReadyReason = "ClusterNotReady"
 )
 
 type ClusterDefinitionReason string
@@ -22,6 +51,14 @@ const (
 	ClusterDefinitionPending ClusterDefinitionReason = "ClusterDefinitionPending"
 	ClusterDefinitionFailed  ClusterDefinitionReason = "ClusterDefinitionFailed"
 	ApplicationCreated       ClusterDefinitionReason = "ApplicationCreated"
+	ApplicationReattached    ClusterDefinitionReason = "ApplicationReattached"
+	ClusterClaimCreated      ClusterDefinitionReason = "ClusterClaimCreated"
+	OCMClusterCreated        ClusterDefinitionReason = "OCMClusterCreated"
+	ValuesValidationFailed   ClusterDefinitionReason = "ValuesValidationFailed"
+	ChartVerificationFailed  ClusterDefinitionReason = "ChartVerificationFailed"
+	DefaultCredentialsFailed ClusterDefinitionReason = "DefaultCredentialsFailed"
+	TrustedCABundleFailed    ClusterDefinitionReason = "TrustedCABundleFailed"
+	ClusterNameConflict      ClusterDefinitionReason = "ClusterNameConflict"
 )
 
 type ClusterInstallReason string
@@ -30,11 +67,85 @@ const (
 	ApplicationFetchFailed         ClusterInstallReason = "ApplicationFetchFailed"
 	ApplicationDegraded            ClusterInstallReason = "ApplicationDegraded"
 	ApplicationError               ClusterInstallReason = "ApplicationError"
+	ApplicationSyncFailed          ClusterInstallReason = "ApplicationSyncFailed"
 	ClusterDefinitionNotCreated    ClusterInstallReason = "ClusterDefinitionNotCreated"
 	ClusterProviderDetectionFailed ClusterInstallReason = "ClusterProviderDetectionFailed"
 	ClusterStatusFailed            ClusterInstallReason = "ClusterStatusFailed"
 	ClusterInstalled               ClusterInstallReason = "ClusterInstalled"
 	ClusterInstalling              ClusterInstallReason = "ClusterInstalling"
+	ClusterInstallTimedOut         ClusterInstallReason = "ClusterInstallTimedOut"
+	ClusterInstallRolledBack       ClusterInstallReason = "ClusterInstallRolledBack"
+)
+
+type ClusterDefinitionDriftedReason string
+
+const (
+	NoDrift        ClusterDefinitionDriftedReason = "NoDrift"
+	DriftDetected  ClusterDefinitionDriftedReason = "DriftDetected"
+	DriftResyncing ClusterDefinitionDriftedReason = "DriftResyncing"
+)
+
+type ParametersValidReason string
+
+const (
+	ParametersKnown      ParametersValidReason = "ParametersKnown"
+	UnknownParameters    ParametersValidReason = "UnknownParameters"
+	DeprecatedParameters ParametersValidReason = "DeprecatedParameters"
+)
+
+type DefaultsDriftedReason string
+
+const (
+	DefaultsUnchanged   DefaultsDriftedReason = "DefaultsUnchanged"
+	DefaultsChanged     DefaultsDriftedReason = "DefaultsChanged"
+	DefaultsCheckFailed DefaultsDriftedReason = "DefaultsCheckFailed"
+)
+
+type ChartTestsSucceededReason string
+
+const (
+	ChartTestsInProgress ChartTestsSucceededReason = "ChartTestsRunning"
+	ChartTestsPassed     ChartTestsSucceededReason = "ChartTestsPassed"
+	ChartTestsNotPassed  ChartTestsSucceededReason = "ChartTestsFailed"
+	NoChartTests         ChartTestsSucceededReason = "NoChartTests"
+)
+
+type ClusterUpgradedReason string
+
+const (
+	UpgradeInProgress   ClusterUpgradedReason = "Upgrading"
+	UpgradeSucceeded    ClusterUpgradedReason = "Upgraded"
+	UpgradeNotSucceeded ClusterUpgradedReason = "UpgradeFailed"
+)
+
+type HibernatedReason string
+
+const (
+	Hibernating       HibernatedReason = "Hibernating"
+	ClusterHibernated HibernatedReason = "Hibernated"
+	Resuming          HibernatedReason = "Resuming"
+)
+
+type SetupPausedReason string
+
+const (
+	ClusterUpgrading SetupPausedReason = "ClusterUpgrading"
+)
+
+type ExpiringReason string
+
+const (
+	LifetimeRemaining ExpiringReason = "LifetimeRemaining"
+	LifetimeExpiring  ExpiringReason = "LifetimeExpiring"
+	LifetimeExpired   ExpiringReason = "LifetimeExpired"
+)
+
+type ProvisioningSLOMetReason string
+
+const (
+	ProvisioningWithinSLO   ProvisioningSLOMetReason = "ProvisioningWithinSLO"
+	ProvisionedWithinSLO    ProvisioningSLOMetReason = "ProvisionedWithinSLO"
+	ProvisioningSLOViolated ProvisioningSLOMetReason = "ProvisioningSLOViolated"
 )
 
 type ArgoClusterAddedReason string
@@ -48,10 +159,12 @@ const (
 type ClusterSetupCreatedReason string
 
 const (
-	ClusterNotInstalled        ClusterSetupCreatedReason = "ClusterNotInstalled"
-	ClusterSetupNotSpecified   ClusterSetupCreatedReason = "ClusterSetupNotSpecified"
-	ClusterSetupCreationFailed ClusterSetupCreatedReason = "ClusterSetupCreationFailed"
-	SetupCreated               ClusterSetupCreatedReason = "ClusterSetupCreated"
+	ClusterNotInstalled          ClusterSetupCreatedReason = "ClusterNotInstalled"
+	ClusterSetupNotSpecified     ClusterSetupCreatedReason = "ClusterSetupNotSpecified"
+	ClusterSetupCreationFailed   ClusterSetupCreatedReason = "ClusterSetupCreationFailed"
+	SetupCreated                 ClusterSetupCreatedReason = "ClusterSetupCreated"
+	SetupChartVerificationFailed ClusterSetupCreatedReason = "ChartVerificationFailed"
+	SetupIdentityFailed          ClusterSetupCreatedReason = "SetupIdentityFailed"
 )
 
 type ClusterSetupSucceededReason string
@@ -65,6 +178,7 @@ const (
 	ClusterSetupDegraded     ClusterSetupSucceededReason = "ClusterSetupDegraded"
 	ClusterSetupError        ClusterSetupSucceededReason = "ClusterSetupError"
 	ClusterSetupNotCreated   ClusterSetupSucceededReason = "ClusterSetupNotCreated"
+	ClusterSetupTimedOut     ClusterSetupSucceededReason = "ClusterSetupTimedOut"
 )
 
 func (clusterInstance *ClusterTemplateInstance) SetClusterDefinitionCreatedCondition(
@@ -81,6 +195,20 @@ func (clusterInstance *ClusterTemplateInstance) SetClusterDefinitionCreatedCondi
 	})
 }
 
+func (clusterInstance *ClusterTemplateInstance) SetParametersValidCondition(
+	status metav1.ConditionStatus,
+	reason ParametersValidReason,
+	message string,
+) {
+	meta.SetStatusCondition(&clusterInstance.Status.Conditions, metav1.Condition{
+		Type:               string(ParametersValid),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
 func (clusterInstance *ClusterTemplateInstance) SetClusterInstallCondition(
 	status metav1.ConditionStatus,
 	reason ClusterInstallReason,
@@ -136,3 +264,173 @@ func (clusterInstance *ClusterTemplateInstance) SetClusterSetupSucceededConditio
 		LastTransitionTime: metav1.Now(),
 	})
 }
+
+func (clusterInstance *ClusterTemplateInstance) SetClusterDefinitionDriftedCondition(
+	status metav1.ConditionStatus,
+	reason ClusterDefinitionDriftedReason,
+	message string,
+) {
+	meta.SetStatusCondition(&clusterInstance.Status.Conditions, metav1.Condition{
+		Type:               string(ClusterDefinitionDrifted),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+// SetReadinessConditions sets Ready, Reconciling and Stalled conditions based on the instance phase.
+// Reconciling and Stalled are removed when they do not apply, as kstatus expects.
+func (clusterInstance *ClusterTemplateInstance) SetReadinessConditions() {
+	phase := clusterInstance.Status.Phase
+	message := clusterInstance.Status.Message
+	if phase == ReadyPhase {
+		clusterInstance.setReadinessCondition(
+			Ready,
+			metav1.ConditionTrue,
+			string(ClusterReady),
+			message,
+		)
+		meta.RemoveStatusCondition(&clusterInstance.Status.Conditions, string(Reconciling))
+		meta.RemoveStatusCondition(&clusterInstance.Status.Conditions, string(Stalled))
+		return
+	}
+
+	clusterInstance.setReadinessCondition(
+		Ready,
+		metav1.ConditionFalse,
+		string(ClusterNotReady),
+		message,
+	)
+	if phase.IsFailed() {
+		clusterInstance.setReadinessCondition(
+			Stalled,
+			metav1.ConditionTrue,
+			string(phase),
+			message,
+		)
+		meta.RemoveStatusCondition(&clusterInstance.Status.Conditions, string(Reconciling))
+	} else {
+		clusterInstance.setReadinessCondition(
+			Reconciling,
+			metav1.ConditionTrue,
+			string(phase),
+			message,
+		)
+		meta.RemoveStatusCondition(&clusterInstance.Status.Conditions, string(Stalled))
+	}
+}
+
+func (clusterInstance *ClusterTemplateInstance) setReadinessCondition(
+	conditionType ConditionType,
+	status metav1.ConditionStatus,
+	reason string,
+	message string,
+) {
+	meta.SetStatusCondition(&clusterInstance.Status.Conditions, metav1.Condition{
+		Type:               string(conditionType),
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: clusterInstance.Generation,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+func (clusterInstance *ClusterTemplateInstance) SetDefaultsDriftedCondition(
+	status metav1.ConditionStatus,
+	reason DefaultsDriftedReason,
+	message string,
+) {
+	meta.SetStatusCondition(&clusterInstance.Status.Conditions, metav1.Condition{
+		Type:               string(DefaultsDrifted),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+func (clusterInstance *ClusterTemplateInstance) SetChartTestsSucceededCondition(
+	status metav1.ConditionStatus,
+	reason ChartTestsSucceededReason,
+	message string,
+) {
+	meta.SetStatusCondition(&clusterInstance.Status.Conditions, metav1.Condition{
+		Type:               string(ChartTestsSucceeded),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+func (clusterInstance *ClusterTemplateInstance) SetClusterUpgradedCondition(
+	status metav1.ConditionStatus,
+	reason ClusterUpgradedReason,
+	message string,
+) {
+	meta.SetStatusCondition(&clusterInstance.Status.Conditions, metav1.Condition{
+		Type:               string(ClusterUpgraded),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+func (clusterInstance *ClusterTemplateInstance) SetHibernatedCondition(
+	status metav1.ConditionStatus,
+	reason HibernatedReason,
+	message string,
+) {
+	meta.SetStatusCondition(&clusterInstance.Status.Conditions, metav1.Condition{
+		Type:               string(Hibernated),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+func (clusterInstance *ClusterTemplateInstance) SetSetupPausedCondition(
+	status metav1.ConditionStatus,
+	reason SetupPausedReason,
+	message string,
+) {
+	meta.SetStatusCondition(&clusterInstance.Status.Conditions, metav1.Condition{
+		Type:               string(SetupPaused),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+func (clusterInstance *ClusterTemplateInstance) SetExpiringCondition(
+	status metav1.ConditionStatus,
+	reason ExpiringReason,
+	message string,
+) {
+	meta.SetStatusCondition(&clusterInstance.Status.Conditions, metav1.Condition{
+		Type:               string(Expiring),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+func (clusterInstance *ClusterTemplateInstance) SetProvisioningSLOMetCondition(
+	status metav1.ConditionStatus,
+	reason ProvisioningSLOMetReason,
+	message string,
+) {
+	meta.SetStatusCondition(&clusterInstance.Status.Conditions, metav1.Condition{
+		Type:               string(ProvisioningSLOMet),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
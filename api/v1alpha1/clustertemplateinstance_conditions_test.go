@@ -0,0 +1,42 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ClusterTemplateInstance conditions", func() {
+	It("Sets readiness conditions", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Generation: 2,
+			},
+			Status: ClusterTemplateInstanceStatus{
+				Phase:   ClusterInstallingPhase,
+				Message: "Cluster is installing",
+			},
+		}
+		cti.SetReadinessConditions()
+		Expect(meta.IsStatusConditionFalse(cti.Status.Conditions, string(Ready))).Should(BeTrue())
+		reconciling := meta.FindStatusCondition(cti.Status.Conditions, string(Reconciling))
+		Expect(reconciling).ShouldNot(BeNil())
+		Expect(reconciling.Status).Should(Equal(metav1.ConditionTrue))
+		Expect(reconciling.Reason).Should(Equal(string(ClusterInstallingPhase)))
+		Expect(reconciling.ObservedGeneration).Should(Equal(int64(2)))
+		Expect(meta.FindStatusCondition(cti.Status.Conditions, string(Stalled))).Should(BeNil())
+
+		cti.Status.Phase = ClusterInstallFailedPhase
+		cti.SetReadinessConditions()
+		Expect(meta.IsStatusConditionFalse(cti.Status.Conditions, string(Ready))).Should(BeTrue())
+		Expect(meta.IsStatusConditionTrue(cti.Status.Conditions, string(Stalled))).Should(BeTrue())
+		Expect(meta.FindStatusCondition(cti.Status.Conditions, string(Reconciling))).Should(BeNil())
+
+		cti.Status.Phase = ReadyPhase
+		cti.SetReadinessConditions()
+		Expect(meta.IsStatusConditionTrue(cti.Status.Conditions, string(Ready))).Should(BeTrue())
+		Expect(meta.FindStatusCondition(cti.Status.Conditions, string(Reconciling))).Should(BeNil())
+		Expect(meta.FindStatusCondition(cti.Status.Conditions, string(Stalled))).Should(BeNil())
+	})
+})
@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"sort"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/strvals"
+)
+
+// GetDefaultsDrift compares default values set by the template (helm values and parameters of the
+// cluster definition and cluster setups) the instance was created with and the values set by
+// the current template. The current template spec has to be composed the same way as the spec of
+// the instance (add-ons applied, cluster setup definitions resolved). Returns sorted keys which
+// were added, removed or changed - keys of cluster setups are prefixed with the name of the
+// setup, ie 'day2/foo.bar'.
+func (i *ClusterTemplateInstance) GetDefaultsDrift(current ClusterTemplateSpec) ([]string, error) {
+	if i.Status.ClusterTemplateSpec == nil {
+		return nil, nil
+	}
+
+	installed, err := getTemplateDefaults(*i.Status.ClusterTemplateSpec)
+	if err != nil {
+		return nil, err
+	}
+	defaults, err := getTemplateDefaults(current)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := []string{}
+	for key, val := range installed {
+		if newVal, ok := defaults[key]; !ok || newVal != val {
+			changed = append(changed, key)
+		}
+	}
+	for key := range defaults {
+		if _, ok := installed[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// getTemplateDefaults returns flattened values set by the template for the cluster definition
+// and cluster setups
+func getTemplateDefaults(ctSpec ClusterTemplateSpec) (map[string]string, error) {
+	defaults := map[string]string{}
+	if err := addAppDefaults(defaults, "", ctSpec.ClusterDefinition); err != nil {
+		return nil, err
+	}
+	for _, setup := range ctSpec.ClusterSetup {
+		if err := addAppDefaults(defaults, setup.Name+"/", setup.Spec); err != nil {
+			return nil, err
+		}
+	}
+	return defaults, nil
+}
+
+func addAppDefaults(defaults map[string]string, prefix string, appSpec argo.ApplicationSpec) error {
+	helmSource := appSpec.Source.Helm
+	if helmSource == nil {
+		return nil
+	}
+	values, err := chartutil.ReadValues([]byte(helmSource.Values))
+	if err != nil {
+		return fmt.Errorf("failed to parse template values - %q", err)
+	}
+	for _, param := range helmSource.Parameters {
+		parse := strvals.ParseInto
+		if param.ForceString {
+			parse = strvals.ParseIntoString
+		}
+		if err := parse(fmt.Sprintf("%s=%s", param.Name, param.Value), values); err != nil {
+			return fmt.Errorf("failed to parse parameter '%s' - %q", param.Name, err)
+		}
+	}
+	flattenValues(defaults, prefix, values)
+	return nil
+}
+
+func flattenValues(flat map[string]string, prefix string, values map[string]interface{}) {
+	for key, val := range values {
+		if nested, ok := val.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenValues(flat, prefix+key+".", nested)
+			continue
+		}
+		flat[prefix+key] = fmt.Sprintf("%v", val)
+	}
+}
@@ -0,0 +1,77 @@
+package v1alpha1
+
+import (
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ClusterTemplateInstance defaults drift", func() {
+	getSpec := func(values string, params []argo.HelmParameter) ClusterTemplateSpec {
+		return ClusterTemplateSpec{
+			ClusterDefinition: argo.ApplicationSpec{
+				Source: argo.ApplicationSource{
+					Chart: "hypershift-template",
+					Helm: &argo.ApplicationSourceHelm{
+						Values:     values,
+						Parameters: params,
+					},
+				},
+			},
+			ClusterSetup: []ClusterSetup{
+				{
+					Name: "day2",
+					Spec: argo.ApplicationSpec{
+						Source: argo.ApplicationSource{
+							Helm: &argo.ApplicationSourceHelm{
+								Values: "logging: true",
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	It("Reports no drift of unchanged template", func() {
+		installed := getSpec("nodePool:\n  replicas: 2\n", nil)
+		cti := ClusterTemplateInstance{
+			Status: ClusterTemplateInstanceStatus{ClusterTemplateSpec: &installed},
+		}
+		changed, err := cti.GetDefaultsDrift(getSpec("nodePool: {replicas: 2}", nil))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(changed).Should(BeEmpty())
+	})
+
+	It("Reports changed keys", func() {
+		installed := getSpec(
+			"nodePool:\n  replicas: 2\n  instanceType: m5.large\n",
+			[]argo.HelmParameter{{Name: "ocpVersion", Value: "4.12.0"}},
+		)
+		cti := ClusterTemplateInstance{
+			Status: ClusterTemplateInstanceStatus{ClusterTemplateSpec: &installed},
+		}
+		current := getSpec(
+			"nodePool:\n  replicas: 3\n  autoRepair: true\n",
+			[]argo.HelmParameter{{Name: "ocpVersion", Value: "4.12.0"}},
+		)
+		current.ClusterSetup[0].Spec.Source.Helm.Values = "logging: false"
+		changed, err := cti.GetDefaultsDrift(current)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(changed).Should(Equal([]string{
+			"day2/logging",
+			"nodePool.autoRepair",
+			"nodePool.instanceType",
+			"nodePool.replicas",
+		}))
+	})
+
+	It("Fails on invalid template values", func() {
+		installed := getSpec("", nil)
+		cti := ClusterTemplateInstance{
+			Status: ClusterTemplateInstanceStatus{ClusterTemplateSpec: &installed},
+		}
+		_, err := cti.GetDefaultsDrift(getSpec("foo", nil))
+		Expect(err).Should(HaveOccurred())
+	})
+})
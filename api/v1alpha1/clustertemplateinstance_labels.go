@@ -0,0 +1,39 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// SetStatusLabels projects the phase, template, OpenShift version and cluster ID of the instance
+// onto labels of the object (the instance or its view), so the fleet can be queried by label
+// selectors, ie all failed instances of a template. Labels of empty values, or values which are
+// not valid label values, are removed. Returns true if the labels changed.
+func (i *ClusterTemplateInstance) SetStatusLabels(obj metav1.Object) bool {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	changed := false
+	for key, value := range map[string]string{
+		CTIPhaseLabel:     string(i.Status.Phase),
+		CTITemplateLabel:  i.Spec.ClusterTemplateRef,
+		CTIVersionLabel:   i.Status.OpenShiftVersion,
+		CTIClusterIDLabel: i.Status.ClusterID,
+	} {
+		current, found := labels[key]
+		if value == "" || len(validation.IsValidLabelValue(value)) > 0 {
+			if found {
+				delete(labels, key)
+				changed = true
+			}
+			continue
+		}
+		if !found || current != value {
+			labels[key] = value
+			changed = true
+		}
+	}
+	obj.SetLabels(labels)
+	return changed
+}
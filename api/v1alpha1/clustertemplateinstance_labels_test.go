@@ -0,0 +1,49 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ClusterTemplateInstance status labels", func() {
+	It("SetStatusLabels", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+				Labels:    map[string]string{"team": "a"},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "aws-small",
+			},
+			Status: ClusterTemplateInstanceStatus{
+				Phase: ClusterInstallFailedPhase,
+			},
+		}
+		Expect(cti.SetStatusLabels(&cti)).Should(BeTrue())
+		Expect(cti.Labels).Should(Equal(map[string]string{
+			"team":           "a",
+			CTIPhaseLabel:    "ClusterInstallFailed",
+			CTITemplateLabel: "aws-small",
+		}))
+		Expect(cti.SetStatusLabels(&cti)).Should(BeFalse())
+
+		cti.Status.Phase = ReadyPhase
+		cti.Status.OpenShiftVersion = "4.12.1"
+		cti.Status.ClusterID = "0b7b1f0c-5c4e-4c55-9d67-2b9f1e6c7a10"
+		view := &ClusterTemplateInstanceView{}
+		Expect(cti.SetStatusLabels(view)).Should(BeTrue())
+		Expect(view.Labels).Should(Equal(map[string]string{
+			CTIPhaseLabel:     "Ready",
+			CTITemplateLabel:  "aws-small",
+			CTIVersionLabel:   "4.12.1",
+			CTIClusterIDLabel: "0b7b1f0c-5c4e-4c55-9d67-2b9f1e6c7a10",
+		}))
+
+		// not a valid label value
+		cti.Status.OpenShiftVersion = "4.12.1+build"
+		Expect(cti.SetStatusLabels(view)).Should(BeTrue())
+		Expect(view.Labels).ShouldNot(HaveKey(CTIVersionLabel))
+	})
+})
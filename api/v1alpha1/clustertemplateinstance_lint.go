@@ -0,0 +1,159 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// path element of a list item, ie 'nodePools[0]'
+const indexPathElement = "[]"
+
+// LintClusterDefinitionParameters returns parameters of the cluster definition which are
+// neither in the chart values nor in values.schema.json (typically typos, which are silently
+// ignored by the chart) and parameters marked as deprecated in values.schema.json
+func (i *ClusterTemplateInstance) LintClusterDefinitionParameters(
+	chartValues string,
+	chartSchema string,
+) ([]string, []string, error) {
+	values, err := i.getClusterDefinitionValues(chartValues, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var schema map[string]interface{}
+	if chartSchema != "" {
+		if parseErr := json.Unmarshal([]byte(chartSchema), &schema); parseErr != nil {
+			return nil, nil, fmt.Errorf("failed to parse chart schema - %q", parseErr)
+		}
+	}
+	if len(values) == 0 && schema == nil {
+		return nil, nil, nil
+	}
+
+	unknown := []string{}
+	deprecated := []string{}
+	for _, param := range i.Status.ClusterTemplateSpec.MigrateParameters(i.Spec.Parameters) {
+		if param.ClusterSetup != "" {
+			continue
+		}
+		path := getParameterPath(param.Name)
+		inSchema, isDeprecated := schemaContains(schema, path)
+		if !inSchema && !valuesContain(values, path) {
+			unknown = append(unknown, param.Name)
+			continue
+		}
+		if isDeprecated {
+			deprecated = append(deprecated, param.Name)
+		}
+	}
+	return unknown, deprecated, nil
+}
+
+// getParameterPath splits helm parameter name (ie 'nodePools[0].replicas') into path elements
+func getParameterPath(name string) []string {
+	path := []string{}
+	key := ""
+	escaped := false
+	for _, c := range name {
+		switch {
+		case escaped:
+			key += string(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '.':
+			path = appendPathElement(path, key)
+			key = ""
+		default:
+			key += string(c)
+		}
+	}
+	return appendPathElement(path, key)
+}
+
+func appendPathElement(path []string, key string) []string {
+	index := strings.Index(key, "[")
+	if index < 0 {
+		return append(path, key)
+	}
+	if index > 0 {
+		path = append(path, key[:index])
+	}
+	for i := 0; i < strings.Count(key, "["); i++ {
+		path = append(path, indexPathElement)
+	}
+	return path
+}
+
+func valuesContain(values map[string]interface{}, path []string) bool {
+	var node interface{} = values
+	for _, key := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			// empty map in chart values is free form, ie labels
+			if len(n) == 0 {
+				return true
+			}
+			child, ok := n[key]
+			if !ok {
+				return false
+			}
+			node = child
+		case []interface{}:
+			if key != indexPathElement {
+				return false
+			}
+			if len(n) == 0 {
+				return true
+			}
+			node = n[0]
+		default:
+			// null default can be replaced by anything
+			return node == nil
+		}
+	}
+	return true
+}
+
+// schemaContains returns whether the schema allows the path and whether any of the properties
+// on the path is deprecated
+func schemaContains(schema map[string]interface{}, path []string) (bool, bool) {
+	if schema == nil {
+		return false, false
+	}
+	node := schema
+	deprecated := false
+	for _, key := range path {
+		if _, ok := node["$ref"]; ok {
+			return true, deprecated
+		}
+		if key == indexPathElement {
+			items, ok := node["items"].(map[string]interface{})
+			if !ok {
+				return true, deprecated
+			}
+			node = items
+			continue
+		}
+		properties, restricted := node["properties"].(map[string]interface{})
+		prop, ok := properties[key].(map[string]interface{})
+		if !ok {
+			return !restricted || allowsAdditionalProperties(node), deprecated
+		}
+		node = prop
+		if isDeprecated, _ := node["deprecated"].(bool); isDeprecated {
+			deprecated = true
+		}
+	}
+	return true, deprecated
+}
+
+func allowsAdditionalProperties(node map[string]interface{}) bool {
+	switch additional := node["additionalProperties"].(type) {
+	case bool:
+		return additional
+	case map[string]interface{}:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ClusterTemplateInstance parameters lint", func() {
+	chartValues := `
+nodePool:
+  replicas: 2
+labels: {}
+pullSecret:
+nodePools:
+  - name: default
+    replicas: 2
+`
+	chartSchema := `{
+  "type": "object",
+  "properties": {
+    "nodePool": {
+      "type": "object",
+      "properties": {
+        "replicas": {"type": "integer"},
+        "instanceType": {"type": "string"},
+        "nodeCount": {"type": "integer", "deprecated": true}
+      }
+    },
+    "annotations": {"type": "object", "additionalProperties": {"type": "string"}}
+  }
+}`
+
+	getCTI := func(params ...Parameter) ClusterTemplateInstance {
+		return ClusterTemplateInstance{
+			Spec: ClusterTemplateInstanceSpec{
+				Parameters: params,
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{},
+			},
+		}
+	}
+
+	It("Accepts parameters defined by values or schema", func() {
+		cti := getCTI(
+			Parameter{Name: "nodePool.replicas", Value: "3"},
+			Parameter{Name: "nodePool.instanceType", Value: "m5.xlarge"},
+			Parameter{Name: "labels.team", Value: "foo"},
+			Parameter{Name: "pullSecret.name", Value: "foo"},
+			Parameter{Name: "nodePools[0].replicas", Value: "3"},
+			Parameter{Name: "annotations.foo", Value: "bar"},
+			Parameter{Name: "nodecount", Value: "3", ClusterSetup: "day2"},
+		)
+		unknown, deprecated, err := cti.LintClusterDefinitionParameters(chartValues, chartSchema)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(unknown).Should(BeEmpty())
+		Expect(deprecated).Should(BeEmpty())
+	})
+
+	It("Reports unknown and deprecated parameters", func() {
+		cti := getCTI(
+			Parameter{Name: "nodepool.replicas", Value: "3"},
+			Parameter{Name: "nodePool.nodeCount", Value: "3"},
+			Parameter{Name: "nodePools[0].size", Value: "3"},
+		)
+		unknown, deprecated, err := cti.LintClusterDefinitionParameters(chartValues, chartSchema)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(unknown).Should(Equal([]string{"nodepool.replicas", "nodePools[0].size"}))
+		Expect(deprecated).Should(Equal([]string{"nodePool.nodeCount"}))
+	})
+
+	It("Checks values without schema", func() {
+		cti := getCTI(Parameter{Name: "nodePool.replica", Value: "3"})
+		unknown, _, err := cti.LintClusterDefinitionParameters(chartValues, "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(unknown).Should(Equal([]string{"nodePool.replica"}))
+	})
+
+	It("Skips charts without values and schema", func() {
+		cti := getCTI(Parameter{Name: "foo", Value: "bar"})
+		unknown, deprecated, err := cti.LintClusterDefinitionParameters("", "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(unknown).Should(BeEmpty())
+		Expect(deprecated).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,210 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// DefaultNodePoolsValuesKey is the key of the cluster definition values holding the node pools
+// unless set by the template
+const DefaultNodePoolsValuesKey = "nodePools"
+
+// ValidateNodePools checks the node pools composed by an instance are within the limits of the
+// template
+func (s *ClusterTemplateSpec) ValidateNodePools(nodePools []NodePool) error {
+	if len(nodePools) == 0 {
+		return nil
+	}
+	options := s.NodePools
+	if options == nil {
+		return fmt.Errorf("cluster template does not allow composing node pools")
+	}
+	if options.MaxNodePools > 0 && len(nodePools) > options.MaxNodePools {
+		return fmt.Errorf(
+			"%d node pools requested, cluster template allows at most %d",
+			len(nodePools),
+			options.MaxNodePools,
+		)
+	}
+	names := map[string]bool{}
+	for _, nodePool := range nodePools {
+		if names[nodePool.Name] {
+			return fmt.Errorf("node pool '%s' is defined more than once", nodePool.Name)
+		}
+		names[nodePool.Name] = true
+		if options.MaxReplicas > 0 && nodePool.Replicas > options.MaxReplicas {
+			return fmt.Errorf(
+				"node pool '%s' requests %d replicas, cluster template allows at most %d",
+				nodePool.Name,
+				nodePool.Replicas,
+				options.MaxReplicas,
+			)
+		}
+		// node pools without instance type use the default of the chart
+		allowed := nodePool.InstanceType == "" || len(options.InstanceTypes) == 0
+		for _, instanceType := range options.InstanceTypes {
+			if instanceType == nodePool.InstanceType {
+				allowed = true
+			}
+		}
+		if !allowed {
+			return fmt.Errorf(
+				"instance type '%s' of node pool '%s' is not allowed, allowed types are %s",
+				nodePool.InstanceType,
+				nodePool.Name,
+				strings.Join(options.InstanceTypes, ", "),
+			)
+		}
+	}
+	return nil
+}
+
+// ValidateNodePoolReplicas checks the replicas the NodePools of an instance are scaled to are
+// within the limits of the template
+func (s *ClusterTemplateSpec) ValidateNodePoolReplicas(replicas map[string]int32) error {
+	if len(replicas) > 0 {
+		if err := s.validateScalableNodePools(); err != nil {
+			return err
+		}
+	}
+	for name, count := range replicas {
+		if count < 0 {
+			return fmt.Errorf("replicas of node pool '%s' can not be negative", name)
+		}
+		if s.NodePools != nil && s.NodePools.MaxReplicas > 0 &&
+			int(count) > s.NodePools.MaxReplicas {
+			return fmt.Errorf(
+				"node pool '%s' requests %d replicas, cluster template allows at most %d",
+				name,
+				count,
+				s.NodePools.MaxReplicas,
+			)
+		}
+	}
+	return nil
+}
+
+// ValidateNodePoolAutoscaling checks the autoscaling bounds of the NodePools of an instance are
+// within the limits of the template. A NodePool can not be both scaled and autoscaled.
+func (s *ClusterTemplateSpec) ValidateNodePoolAutoscaling(
+	autoscaling map[string]NodePoolAutoscaling,
+	replicas map[string]int32,
+) error {
+	if len(autoscaling) > 0 {
+		if err := s.validateScalableNodePools(); err != nil {
+			return err
+		}
+	}
+	for name, bounds := range autoscaling {
+		if _, ok := replicas[name]; ok {
+			return fmt.Errorf("node pool '%s' can not be both scaled and autoscaled", name)
+		}
+		if bounds.Min < 1 || bounds.Min > bounds.Max {
+			return fmt.Errorf(
+				"autoscaling of node pool '%s' requires 1 <= min <= max, got min %d and max %d",
+				name,
+				bounds.Min,
+				bounds.Max,
+			)
+		}
+		if s.NodePools != nil && s.NodePools.MaxReplicas > 0 &&
+			int(bounds.Max) > s.NodePools.MaxReplicas {
+			return fmt.Errorf(
+				"node pool '%s' autoscales up to %d replicas, cluster template allows at most %d",
+				name,
+				bounds.Max,
+				s.NodePools.MaxReplicas,
+			)
+		}
+	}
+	return nil
+}
+
+// validateScalableNodePools checks the clusters of the template have NodePools the operator can
+// scale, clusters created through OCM or claimed from a cluster pool have none
+func (s *ClusterTemplateSpec) validateScalableNodePools() error {
+	switch {
+	case s.OCM != nil:
+		return fmt.Errorf("node pools of clusters created through OCM can not be scaled")
+	case s.ClusterPool != nil:
+		return fmt.Errorf("node pools of clusters claimed from a cluster pool can not be scaled")
+	}
+	return nil
+}
+
+// GetRequestedNodes returns the number of nodes of the node pools composed by the instance,
+// node pools scaled by spec.nodePoolReplicas count with the scaled replicas and node pools
+// autoscaled by spec.nodePoolAutoscaling with their maximum
+func (i *ClusterTemplateInstance) GetRequestedNodes() int {
+	nodes := i.getScaledNodes()
+	for _, nodePool := range i.Spec.NodePools {
+		_, scaled := i.Spec.NodePoolReplicas[nodePool.Name]
+		_, autoscaled := i.Spec.NodePoolAutoscaling[nodePool.Name]
+		if !scaled && !autoscaled {
+			nodes += nodePool.Replicas
+		}
+	}
+	return nodes
+}
+
+// getScaledNodes returns the number of nodes of the NodePools scaled by spec.nodePoolReplicas and
+// of the NodePools autoscaled by spec.nodePoolAutoscaling, which can grow up to their maximum
+func (i *ClusterTemplateInstance) getScaledNodes() int {
+	nodes := 0
+	for _, replicas := range i.Spec.NodePoolReplicas {
+		if replicas > 0 {
+			nodes += int(replicas)
+		}
+	}
+	for _, bounds := range i.Spec.NodePoolAutoscaling {
+		if bounds.Max > 0 {
+			nodes += int(bounds.Max)
+		}
+	}
+	return nodes
+}
+
+// setNodePoolValues sets the node pools of the instance in the cluster definition values, the
+// node pools replace the node pools of the template and of referenced values
+func (i *ClusterTemplateInstance) setNodePoolValues(values chartutil.Values) {
+	options := i.Status.ClusterTemplateSpec.NodePools
+	if options == nil || len(i.Spec.NodePools) == 0 {
+		return
+	}
+	key := options.ValuesKey
+	if key == "" {
+		key = DefaultNodePoolsValuesKey
+	}
+	nodePools := []interface{}{}
+	for _, nodePool := range i.Spec.NodePools {
+		value := map[string]interface{}{
+			"name":     nodePool.Name,
+			"replicas": nodePool.Replicas,
+		}
+		if nodePool.InstanceType != "" {
+			value["instanceType"] = nodePool.InstanceType
+		}
+		if len(nodePool.Labels) > 0 {
+			labels := map[string]interface{}{}
+			for k, v := range nodePool.Labels {
+				labels[k] = v
+			}
+			value["labels"] = labels
+		}
+		if len(nodePool.Taints) > 0 {
+			taints := []interface{}{}
+			for _, taint := range nodePool.Taints {
+				taints = append(taints, map[string]interface{}{
+					"key":    taint.Key,
+					"value":  taint.Value,
+					"effect": taint.Effect,
+				})
+			}
+			value["taints"] = taints
+		}
+		nodePools = append(nodePools, value)
+	}
+	values[key] = nodePools
+}
@@ -0,0 +1,158 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chartutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClusterTemplateInstance node pools", func() {
+	var cti ClusterTemplateInstance
+
+	BeforeEach(func() {
+		cti = ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				NodePools: []NodePool{
+					{Name: "workers", Replicas: 2, InstanceType: "m5.xlarge"},
+					{
+						Name:     "gpu",
+						Replicas: 1,
+						Labels:   map[string]string{"gpu": "true"},
+						Taints: []NodePoolTaint{
+							{Key: "gpu", Value: "true", Effect: "NoSchedule"},
+						},
+					},
+				},
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					NodePools: &NodePoolOptions{
+						MaxNodePools:  2,
+						MaxReplicas:   3,
+						InstanceTypes: []string{"m5.xlarge", "m5.2xlarge"},
+					},
+				},
+			},
+		}
+	})
+
+	It("Validates node pools against template limits", func() {
+		ctSpec := cti.Status.ClusterTemplateSpec
+		Expect(ctSpec.ValidateNodePools(cti.Spec.NodePools)).Should(Succeed())
+
+		Expect((&ClusterTemplateSpec{}).ValidateNodePools(cti.Spec.NodePools)).Should(MatchError(
+			"cluster template does not allow composing node pools",
+		))
+
+		nodePools := append(cti.Spec.NodePools, NodePool{Name: "extra", Replicas: 1})
+		Expect(ctSpec.ValidateNodePools(nodePools)).Should(MatchError(
+			"3 node pools requested, cluster template allows at most 2",
+		))
+
+		nodePools = []NodePool{{Name: "workers"}, {Name: "workers"}}
+		Expect(ctSpec.ValidateNodePools(nodePools)).Should(MatchError(
+			"node pool 'workers' is defined more than once",
+		))
+
+		nodePools = []NodePool{{Name: "workers", Replicas: 4}}
+		Expect(ctSpec.ValidateNodePools(nodePools)).Should(MatchError(
+			"node pool 'workers' requests 4 replicas, cluster template allows at most 3",
+		))
+
+		nodePools = []NodePool{{Name: "workers", Replicas: 1, InstanceType: "p3.2xlarge"}}
+		Expect(ctSpec.ValidateNodePools(nodePools)).Should(MatchError(
+			"instance type 'p3.2xlarge' of node pool 'workers' is not allowed, " +
+				"allowed types are m5.xlarge, m5.2xlarge",
+		))
+	})
+
+	It("Passes node pools in cluster definition values", func() {
+		cti.Status.ClusterTemplateSpec.NodePools.ValuesKey = "pools"
+		values, err := cti.GetValuesFrom(ctx, fake.NewFakeClientWithScheme(scheme.Scheme), "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(values).Should(Equal(chartutil.Values{
+			"pools": []interface{}{
+				map[string]interface{}{
+					"name":         "workers",
+					"replicas":     2,
+					"instanceType": "m5.xlarge",
+				},
+				map[string]interface{}{
+					"name":     "gpu",
+					"replicas": 1,
+					"labels":   map[string]interface{}{"gpu": "true"},
+					"taints": []interface{}{
+						map[string]interface{}{
+							"key":    "gpu",
+							"value":  "true",
+							"effect": "NoSchedule",
+						},
+					},
+				},
+			},
+		}))
+	})
+
+	It("Counts nodes of node pools", func() {
+		ctSpec := *cti.Status.ClusterTemplateSpec
+		ctSpec.Compute = &ClusterCompute{
+			Nodes:       &ComputeRule{Parameter: "nodeCount", Default: 5},
+			VCPUPerNode: &ComputeRule{Default: 4},
+		}
+		nodes, vcpu, err := cti.GetRequestedCompute(ctSpec)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nodes).Should(Equal(3))
+		Expect(vcpu).Should(Equal(12))
+	})
+
+	It("Counts nodes of scaled node pools", func() {
+		ctSpec := *cti.Status.ClusterTemplateSpec
+		ctSpec.Compute = &ClusterCompute{
+			Nodes:       &ComputeRule{Parameter: "nodeCount", Default: 5},
+			VCPUPerNode: &ComputeRule{Default: 4},
+		}
+		cti.Spec.NodePoolReplicas = map[string]int32{"workers": 3}
+		nodes, vcpu, err := cti.GetRequestedCompute(ctSpec)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nodes).Should(Equal(4))
+		Expect(vcpu).Should(Equal(16))
+
+		// node pools rendered by the chart
+		cti.Spec.NodePools = nil
+		nodes, _, err = cti.GetRequestedCompute(ctSpec)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nodes).Should(Equal(5))
+
+		cti.Spec.NodePoolReplicas = map[string]int32{"workers": 4, "infra": 3}
+		nodes, _, err = cti.GetRequestedCompute(ctSpec)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nodes).Should(Equal(7))
+	})
+
+	It("Counts autoscaled node pools with their maximum", func() {
+		ctSpec := *cti.Status.ClusterTemplateSpec
+		ctSpec.Compute = &ClusterCompute{
+			Nodes:       &ComputeRule{Parameter: "nodeCount", Default: 5},
+			VCPUPerNode: &ComputeRule{Default: 4},
+		}
+		cti.Spec.NodePoolAutoscaling = map[string]NodePoolAutoscaling{"workers": {Min: 1, Max: 3}}
+		nodes, vcpu, err := cti.GetRequestedCompute(ctSpec)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nodes).Should(Equal(4))
+		Expect(vcpu).Should(Equal(16))
+
+		// node pools rendered by the chart
+		cti.Spec.NodePools = nil
+		cti.Spec.NodePoolAutoscaling["infra"] = NodePoolAutoscaling{Min: 1, Max: 4}
+		nodes, _, err = cti.GetRequestedCompute(ctSpec)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nodes).Should(Equal(7))
+	})
+})
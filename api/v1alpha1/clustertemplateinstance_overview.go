@@ -0,0 +1,151 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/cluster-templates-operator/argocd"
+)
+
+// number of phases kept in the overview timeline, older are dropped
+const maxTimelineEntries = 20
+
+var failedInstallReasons = map[string]bool{
+	string(ApplicationFetchFailed):         true,
+	string(ApplicationDegraded):            true,
+	string(ApplicationError):               true,
+	string(ApplicationSyncFailed):          true,
+	string(ClusterProviderDetectionFailed): true,
+	string(ClusterStatusFailed):            true,
+	string(ClusterInstallTimedOut):         true,
+	string(ClusterInstallRolledBack):       true,
+}
+
+// UpdateOverview recomputes status.overview from the conditions, cluster setup statuses and
+// credentials of the instance. A timeline entry is added when the phase changed.
+func (clusterInstance *ClusterTemplateInstance) UpdateOverview() {
+	overview := clusterInstance.Status.Overview
+	if overview == nil {
+		overview = &InstanceOverview{}
+	}
+
+	overview.Steps = []OverviewStep{
+		clusterInstance.getInstallStep(),
+		clusterInstance.getArgoClusterStep(),
+	}
+	if clusterInstance.Status.ClusterTemplateSpec != nil {
+		setupStatuses := map[string]ClusterSetupStatus{}
+		if clusterInstance.Status.ClusterSetup != nil {
+			for _, setupStatus := range *clusterInstance.Status.ClusterSetup {
+				setupStatuses[setupStatus.Name] = setupStatus
+			}
+		}
+		for _, setup := range clusterInstance.Status.ClusterTemplateSpec.ClusterSetup {
+			overview.Steps = append(overview.Steps, getSetupStep(setup.Name, setupStatuses))
+		}
+	}
+
+	succeeded := 0
+	for _, step := range overview.Steps {
+		if step.Phase == StepSucceeded {
+			succeeded++
+		}
+	}
+	overview.Progress = succeeded * 100 / len(overview.Steps)
+
+	phase := clusterInstance.Status.Phase
+	if phase != "" && (len(overview.Timeline) == 0 ||
+		overview.Timeline[len(overview.Timeline)-1].Phase != phase) {
+		overview.Timeline = append(overview.Timeline, TimelineEntry{
+			Phase:   phase,
+			Message: clusterInstance.Status.Message,
+			Time:    metav1.Now(),
+		})
+		if len(overview.Timeline) > maxTimelineEntries {
+			overview.Timeline = overview.Timeline[len(overview.Timeline)-maxTimelineEntries:]
+		}
+	}
+
+	overview.Credentials = CredentialsOverview{}
+	if clusterInstance.Status.Kubeconfig != nil {
+		overview.Credentials.Kubeconfig = clusterInstance.Status.Kubeconfig.Name
+	}
+	if clusterInstance.Status.AdminPassword != nil {
+		overview.Credentials.AdminPassword = clusterInstance.Status.AdminPassword.Name
+	}
+
+	clusterInstance.Status.Overview = overview
+}
+
+func (clusterInstance *ClusterTemplateInstance) getInstallStep() OverviewStep {
+	step := OverviewStep{Name: string(InstallStep), Type: InstallStep, Phase: StepPending}
+	definitionCondition := meta.FindStatusCondition(
+		clusterInstance.Status.Conditions,
+		string(ClusterDefinitionCreated),
+	)
+	if definitionCondition != nil && definitionCondition.Status == metav1.ConditionFalse &&
+		(definitionCondition.Reason == string(ClusterDefinitionFailed) ||
+			definitionCondition.Reason == string(ValuesValidationFailed) ||
+			definitionCondition.Reason == string(ChartVerificationFailed)) {
+		step.Phase = StepFailed
+		step.Message = definitionCondition.Message
+		return step
+	}
+
+	installCondition := meta.FindStatusCondition(
+		clusterInstance.Status.Conditions,
+		string(ClusterInstallSucceeded),
+	)
+	if installCondition == nil {
+		return step
+	}
+	step.Message = installCondition.Message
+	switch {
+	case installCondition.Status == metav1.ConditionTrue:
+		step.Phase = StepSucceeded
+	case failedInstallReasons[installCondition.Reason]:
+		step.Phase = StepFailed
+	case installCondition.Reason != string(ClusterDefinitionNotCreated):
+		step.Phase = StepRunning
+	}
+	return step
+}
+
+func (clusterInstance *ClusterTemplateInstance) getArgoClusterStep() OverviewStep {
+	step := OverviewStep{Name: string(ArgoClusterStep), Type: ArgoClusterStep, Phase: StepPending}
+	condition := meta.FindStatusCondition(
+		clusterInstance.Status.Conditions,
+		string(ArgoClusterAdded),
+	)
+	if condition == nil {
+		return step
+	}
+	step.Message = condition.Message
+	if condition.Status == metav1.ConditionTrue {
+		step.Phase = StepSucceeded
+	} else if condition.Reason == string(ArgoClusterFailed) {
+		step.Phase = StepFailed
+	}
+	return step
+}
+
+func getSetupStep(name string, setupStatuses map[string]ClusterSetupStatus) OverviewStep {
+	step := OverviewStep{Name: name, Type: SetupStep, Phase: StepPending}
+	setupStatus, ok := setupStatuses[name]
+	if !ok {
+		return step
+	}
+	step.Message = setupStatus.Message
+	switch setupStatus.Status {
+	case argocd.ApplicationHealthy:
+		step.Phase = StepSucceeded
+	case argocd.ApplicationWaiting:
+		step.Phase = StepPending
+	case argocd.ApplicationError, argocd.ApplicationSyncFailed, argocd.ApplicationDegraded,
+		argocd.ApplicationCreateFailed:
+		step.Phase = StepFailed
+	default:
+		step.Phase = StepRunning
+	}
+	return step
+}
@@ -0,0 +1,96 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ClusterTemplateInstance overview", func() {
+	It("Computes steps and progress", func() {
+		cti := ClusterTemplateInstance{
+			Status: ClusterTemplateInstanceStatus{
+				Phase:   ClusterSetupRunningPhase,
+				Message: "Cluster setup is running",
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					ClusterSetup: []ClusterSetup{{Name: "foo"}, {Name: "bar"}, {Name: "baz"}},
+				},
+				ClusterSetup: &[]ClusterSetupStatus{
+					{Name: "foo", Status: argocd.ApplicationHealthy},
+					{Name: "bar", Status: argocd.ApplicationDegraded, Message: "bar is degraded"},
+				},
+				Kubeconfig: &corev1.LocalObjectReference{Name: "foo-kubeconfig"},
+			},
+		}
+		cti.SetClusterInstallCondition(metav1.ConditionTrue, ClusterInstalled, "Cluster installed")
+		cti.SetArgoClusterAddedCondition(metav1.ConditionTrue, ArgoClusterCreated, "Added")
+		cti.UpdateOverview()
+
+		overview := cti.Status.Overview
+		Expect(overview).ShouldNot(BeNil())
+		Expect(overview.Steps).Should(Equal([]OverviewStep{
+			{
+				Name:    string(InstallStep),
+				Type:    InstallStep,
+				Phase:   StepSucceeded,
+				Message: "Cluster installed",
+			},
+			{Name: string(ArgoClusterStep), Type: ArgoClusterStep, Phase: StepSucceeded, Message: "Added"},
+			{Name: "foo", Type: SetupStep, Phase: StepSucceeded},
+			{Name: "bar", Type: SetupStep, Phase: StepFailed, Message: "bar is degraded"},
+			{Name: "baz", Type: SetupStep, Phase: StepPending},
+		}))
+		Expect(overview.Progress).Should(Equal(60))
+		Expect(overview.Credentials.Kubeconfig).Should(Equal("foo-kubeconfig"))
+		Expect(overview.Credentials.AdminPassword).Should(BeEmpty())
+	})
+
+	It("Reports install progress", func() {
+		cti := ClusterTemplateInstance{}
+		cti.SetClusterInstallCondition(
+			metav1.ConditionFalse,
+			ClusterDefinitionNotCreated,
+			"Waiting for cluster definition to be created",
+		)
+		cti.UpdateOverview()
+		Expect(cti.Status.Overview.Steps[0].Phase).Should(Equal(StepPending))
+		Expect(cti.Status.Overview.Progress).Should(Equal(0))
+
+		cti.SetClusterInstallCondition(metav1.ConditionFalse, ClusterInstalling, "Installing")
+		cti.UpdateOverview()
+		Expect(cti.Status.Overview.Steps[0].Phase).Should(Equal(StepRunning))
+
+		cti.SetClusterInstallCondition(metav1.ConditionFalse, ClusterInstallTimedOut, "Timed out")
+		cti.UpdateOverview()
+		Expect(cti.Status.Overview.Steps[0].Phase).Should(Equal(StepFailed))
+		Expect(cti.Status.Overview.Steps[0].Message).Should(Equal("Timed out"))
+	})
+
+	It("Records phase changes in timeline", func() {
+		cti := ClusterTemplateInstance{
+			Status: ClusterTemplateInstanceStatus{Phase: PendingPhase},
+		}
+		cti.UpdateOverview()
+		cti.UpdateOverview()
+		Expect(cti.Status.Overview.Timeline).Should(HaveLen(1))
+
+		cti.Status.Phase = ClusterInstallingPhase
+		cti.Status.Message = "Installing"
+		cti.UpdateOverview()
+		Expect(cti.Status.Overview.Timeline).Should(HaveLen(2))
+		Expect(cti.Status.Overview.Timeline[1].Phase).Should(Equal(ClusterInstallingPhase))
+		Expect(cti.Status.Overview.Timeline[1].Message).Should(Equal("Installing"))
+
+		for i := 0; i < maxTimelineEntries; i++ {
+			cti.Status.Phase = PendingPhase
+			if i%2 == 0 {
+				cti.Status.Phase = ReadyPhase
+			}
+			cti.UpdateOverview()
+		}
+		Expect(cti.Status.Overview.Timeline).Should(HaveLen(maxTimelineEntries))
+		Expect(cti.Status.Overview.Timeline[maxTimelineEntries-1].Phase).Should(Equal(PendingPhase))
+	})
+})
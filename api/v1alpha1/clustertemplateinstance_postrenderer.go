@@ -0,0 +1,93 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+const (
+	// PostRendererPluginName is the name of the ArgoCD config management plugin which renders
+	// the Helm chart and applies the kustomization of the post renderer
+	PostRendererPluginName = "claas-helm-kustomize"
+
+	// environment variables passed to the plugin, ArgoCD prefixes them with 'ARGOCD_ENV_'
+	PostRendererRepoEnv    = "HELM_REPO"
+	PostRendererChartEnv   = "HELM_CHART"
+	PostRendererVersionEnv = "HELM_VERSION"
+	PostRendererReleaseEnv = "HELM_RELEASE"
+	PostRendererValuesEnv  = "HELM_VALUES"
+)
+
+// getPostRendererSource returns application source which renders the Helm chart source with
+// the post renderer plugin. Values of the chart (template values, values of referenced ConfigMaps
+// and Secrets and instance parameters) are passed to the plugin in an environment variable.
+func (i *ClusterTemplateInstance) getPostRendererSource(
+	postRenderer PostRenderer,
+	helmSource argo.ApplicationSource,
+	valuesFrom chartutil.Values,
+) (argo.ApplicationSource, error) {
+	if helmSource.Chart == "" {
+		return argo.ApplicationSource{}, fmt.Errorf(
+			"post renderer requires Helm chart cluster definition",
+		)
+	}
+	values, err := i.getPostRendererValues(valuesFrom)
+	if err != nil {
+		return argo.ApplicationSource{}, err
+	}
+	releaseName := i.GetReleaseName()
+	if helmSource.Helm != nil && helmSource.Helm.ReleaseName != "" {
+		releaseName = helmSource.Helm.ReleaseName
+	}
+	return argo.ApplicationSource{
+		RepoURL:        postRenderer.RepoURL,
+		Path:           postRenderer.Path,
+		TargetRevision: postRenderer.TargetRevision,
+		Plugin: &argo.ApplicationSourcePlugin{
+			Name: PostRendererPluginName,
+			Env: argo.Env{
+				{Name: PostRendererRepoEnv, Value: helmSource.RepoURL},
+				{Name: PostRendererChartEnv, Value: helmSource.Chart},
+				{Name: PostRendererVersionEnv, Value: helmSource.TargetRevision},
+				{Name: PostRendererReleaseEnv, Value: releaseName},
+				{Name: PostRendererValuesEnv, Value: values},
+			},
+		},
+	}, nil
+}
+
+// updatePostRendererValues sets current instance values in the post renderer plugin
+// environment. Returns false if the application does not use the post renderer.
+func (i *ClusterTemplateInstance) updatePostRendererValues(
+	appSpec *argo.ApplicationSpec,
+	valuesFrom chartutil.Values,
+) (bool, error) {
+	plugin := appSpec.Source.Plugin
+	if plugin == nil || plugin.Name != PostRendererPluginName {
+		return false, nil
+	}
+	values, err := i.getPostRendererValues(valuesFrom)
+	if err != nil {
+		return true, err
+	}
+	for _, env := range plugin.Env {
+		if env.Name == PostRendererValuesEnv {
+			env.Value = values
+			return true, nil
+		}
+	}
+	plugin.Env = append(plugin.Env, &argo.EnvEntry{Name: PostRendererValuesEnv, Value: values})
+	return true, nil
+}
+
+func (i *ClusterTemplateInstance) getPostRendererValues(
+	valuesFrom chartutil.Values,
+) (string, error) {
+	values, err := i.GetClusterDefinitionValues(valuesFrom)
+	if err != nil {
+		return "", err
+	}
+	return values.YAML()
+}
@@ -28,6 +28,18 @@ const (
 	CTINameLabel           = "clustertemplateinstance.openshift.io/name"
 	CTINamespaceLabel      = "clustertemplateinstance.openshift.io/namespace"
 	CTISetupLabel          = "clustertemplate.openshift.io/cluster-setup"
+	// set on target namespaces created by the operator, which are deleted with the last instance
+	CTIManagedNamespaceLabel = "clustertemplate.openshift.io/managed-namespace"
+	// status of the instance projected onto the instance and its view, for label selectors
+	CTIPhaseLabel    = "clustertemplateinstance.openshift.io/phase"
+	CTITemplateLabel = "clustertemplateinstance.openshift.io/template"
+	CTIVersionLabel  = "clustertemplateinstance.openshift.io/openshift-version"
+	// stable identifier of the cluster (status.clusterID) set on the instance, its view and the
+	// resources created for it
+	CTIClusterIDLabel = "clustertemplateinstance.openshift.io/cluster-id"
+	// name of the cluster (status.clusterName) set on the resources created for the instance, so it
+	// is kept when the instance is restored from a backup
+	CTIClusterNameLabel = "clustertemplateinstance.openshift.io/cluster-name"
 )
 
 type Parameter struct {
@@ -40,11 +52,201 @@ type Parameter struct {
 	ClusterSetup string `json:"clusterSetup,omitempty"`
 }
 
+// Reference to a ConfigMap or Secret holding Helm values
+type ValuesReference struct {
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	// Kind of the resource holding the values
+	Kind string `json:"kind"`
+	// Name of the ConfigMap or Secret in the namespace of the instance
+	Name string `json:"name"`
+	// +optional
+	// Key of the values in YAML format, defaults to 'values.yaml'
+	ValuesKey string `json:"valuesKey,omitempty"`
+	// +optional
+	// If empty, the values are passed to cluster installation chart
+	// otherwise the field value needs to match name of ClusterSetup of ClusterTemplate
+	ClusterSetup string `json:"clusterSetup,omitempty"`
+	// +optional
+	// If true, missing resource or key is ignored
+	Optional bool `json:"optional,omitempty"`
+}
+
+type ChartTestsPhase string
+
+const (
+	TestsRunning   ChartTestsPhase = "Running"
+	TestsSucceeded ChartTestsPhase = "Succeeded"
+	TestsFailed    ChartTestsPhase = "Failed"
+)
+
+type ChartTestStatus struct {
+	// Name of the test pod
+	Name string `json:"name"`
+	// Phase of the test
+	Phase ChartTestsPhase `json:"phase"`
+	// +optional
+	// Additional message for Phase
+	Message string `json:"message,omitempty"`
+	// +optional
+	// Tail of the test pod log
+	Log string `json:"log,omitempty"`
+}
+
+type ChartTestsStatus struct {
+	// Phase of all the tests
+	Phase ChartTestsPhase `json:"phase"`
+	// +optional
+	// Additional message for Phase
+	Message string `json:"message,omitempty"`
+	// +optional
+	// Namespace the test pods run in - namespace of the cluster definition Helm release
+	Namespace string `json:"namespace,omitempty"`
+	// +optional
+	// Time the tests were started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	// Results of the tests
+	Tests []ChartTestStatus `json:"tests,omitempty"`
+}
+
+// Upgrade of the cluster, either to a release image or to an OCP version. Supported for hypershift
+// clusters only.
+type ClusterUpgrade struct {
+	// +optional
+	//+kubebuilder:validation:Pattern=`^(\w+\S+)$`
+	// OCP release image the cluster is upgraded to
+	ReleaseImage string `json:"releaseImage,omitempty"`
+	// +optional
+	//+kubebuilder:validation:Pattern=`^\d+\.\d+\.\d+(-\S+)?$`
+	// OCP version the cluster is upgraded to, ie '4.12.1'. Translated to the release image of the
+	// version in the OCP release repository.
+	Version string `json:"version,omitempty"`
+	// +optional
+	// +kubebuilder:validation:Enum=x86_64;aarch64;ppc64le;s390x;multi
+	// Architecture of the release image of the version, defaults to 'x86_64'
+	Architecture string `json:"architecture,omitempty"`
+}
+
 type ClusterTemplateInstanceSpec struct {
 	// A reference to ClusterTemplate which will be used for installing and setting up the cluster
 	ClusterTemplateRef string `json:"clusterTemplateRef"`
 	// Helm parameters to be passed to cluster installation or setup
 	Parameters []Parameter `json:"parameters,omitempty"`
+	// +optional
+	// ConfigMaps and Secrets holding Helm values (ie cloud credentials or pull secrets), so they do
+	// not have to be inlined in the parameters. Values override values of the template, later
+	// references override earlier ones. Parameters override the values.
+	ValuesFrom []ValuesReference `json:"valuesFrom,omitempty"`
+	// +optional
+	// Upgrades the installed cluster - the control plane first, node pools once the control
+	// plane is upgraded
+	Upgrade *ClusterUpgrade `json:"upgrade,omitempty"`
+	// +optional
+	// Renders the cluster definition chart into a ConfigMap referenced by status.preview instead
+	// of installing the cluster. Setting it to false starts the installation.
+	Preview bool `json:"preview,omitempty"`
+	// +optional
+	// Add-ons of the template enabled for the cluster, ie 'logging: true'. Add-ons which are not
+	// listed are disabled.
+	AddOns map[string]bool `json:"addOns,omitempty"`
+	// +optional
+	// Node pools of the cluster, passed to the cluster definition chart. Can be changed after the
+	// instance is created, within the limits of the template.
+	NodePools []NodePool `json:"nodePools,omitempty"`
+	// +optional
+	// Replicas of the NodePools created by the cluster definition, by name of the NodePool. The
+	// NodePools of the installed hypershift cluster are scaled to them, can be changed anytime.
+	NodePoolReplicas map[string]int32 `json:"nodePoolReplicas,omitempty"`
+	// +optional
+	// Autoscaling bounds of the NodePools created by the cluster definition, by name of the
+	// NodePool. The NodePools of the installed hypershift cluster are autoscaled within them, can
+	// be changed anytime.
+	NodePoolAutoscaling map[string]NodePoolAutoscaling `json:"nodePoolAutoscaling,omitempty"`
+	// +optional
+	// Hibernates the installed hypershift cluster - its NodePools are scaled to zero and the
+	// HostedCluster is paused. Setting it to false resumes the cluster.
+	Hibernate bool `json:"hibernate,omitempty"`
+	// +optional
+	// Name of the ClusterSizeClass of the cluster, one of the size classes of the template. Worker
+	// nodes and vCPUs requested by the instance have to fit the limits of the size class.
+	SizeClass string `json:"sizeClass,omitempty"`
+}
+
+// Node pool of the cluster composed by the instance
+type NodePool struct {
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	// Name of the node pool, unique within the instance
+	Name string `json:"name"`
+	// +kubebuilder:validation:Minimum=0
+	// Number of nodes of the pool
+	Replicas int `json:"replicas"`
+	// +optional
+	// Instance type of the nodes, ie 'm5.xlarge'
+	InstanceType string `json:"instanceType,omitempty"`
+	// +optional
+	// Labels of the nodes
+	Labels map[string]string `json:"labels,omitempty"`
+	// +optional
+	// Taints of the nodes
+	Taints []NodePoolTaint `json:"taints,omitempty"`
+}
+
+// Autoscaling bounds of a node pool
+type NodePoolAutoscaling struct {
+	// +kubebuilder:validation:Minimum=1
+	// Minimal number of nodes of the pool
+	Min int32 `json:"min"`
+	// +kubebuilder:validation:Minimum=1
+	// Maximal number of nodes of the pool
+	Max int32 `json:"max"`
+}
+
+// Taint of the nodes of a node pool
+type NodePoolTaint struct {
+	// Key of the taint
+	Key string `json:"key"`
+	// +optional
+	// Value of the taint
+	Value string `json:"value,omitempty"`
+	// +kubebuilder:validation:Enum=NoSchedule;PreferNoSchedule;NoExecute
+	// Effect of the taint
+	Effect string `json:"effect"`
+}
+
+type UpgradePhase string
+
+const (
+	UpgradePending     UpgradePhase = "Pending"
+	UpgradeProgressing UpgradePhase = "Progressing"
+	UpgradeCompleted   UpgradePhase = "Completed"
+	UpgradeFailed      UpgradePhase = "Failed"
+)
+
+type ComponentUpgradeStatus struct {
+	// Name of the HostedCluster or NodePool
+	Name string `json:"name"`
+	// Phase of the component upgrade
+	Phase UpgradePhase `json:"phase"`
+	// +optional
+	// OCP version of the component, set once the component is upgraded
+	Version string `json:"version,omitempty"`
+}
+
+type ClusterUpgradeStatus struct {
+	// Release image which is rolled out
+	ReleaseImage string `json:"releaseImage"`
+	// Phase of the whole upgrade
+	Phase UpgradePhase `json:"phase"`
+	// +optional
+	// Additional message for Phase
+	Message string `json:"message,omitempty"`
+	// +optional
+	// Upgrade of the control plane (HostedCluster)
+	ControlPlane *ComponentUpgradeStatus `json:"controlPlane,omitempty"`
+	// +optional
+	// Upgrade of each NodePool
+	NodePools []ComponentUpgradeStatus `json:"nodePools,omitempty"`
 }
 
 type ClusterSetupStatus struct {
@@ -54,6 +256,24 @@ type ClusterSetupStatus struct {
 	Status argocd.ApplicationStatus `json:"status"`
 	// Description of the cluster setup status
 	Message string `json:"message"`
+	// +optional
+	// Name of the ArgoCD Application of the cluster setup
+	ApplicationName string `json:"applicationName,omitempty"`
+	// +optional
+	// Namespace of the ArgoCD Application of the cluster setup
+	ApplicationNamespace string `json:"applicationNamespace,omitempty"`
+	// +optional
+	// Link to the Application in ArgoCD UI, set if the URL of ArgoCD is configured
+	ApplicationURL string `json:"applicationURL,omitempty"`
+	// +optional
+	// Name of the Job of the cluster setup in the namespace of the instance (in the 'cluster-setup' namespace of the new cluster if it runs there), set for setups run by Jobs
+	JobName string `json:"jobName,omitempty"`
+	// +optional
+	// ID of the job launched in Ansible Automation Platform, set for setups run by Ansible jobs
+	AnsibleJobID int `json:"ansibleJobID,omitempty"`
+	// +optional
+	// Number of times the failed cluster setup was retried
+	Retries int `json:"retries,omitempty"`
 }
 
 type Phase string
@@ -61,9 +281,14 @@ type Phase string
 const (
 	PendingPhase                  Phase  = "Pending"
 	PendingMessage                string = "Pending"
+	PreviewPhase                  Phase  = "Preview"
+	PreviewFailedPhase            Phase  = "PreviewFailed"
 	ClusterDefinitionFailedPhase  Phase  = "ClusterDefinitionFailed"
 	ClusterInstallingPhase        Phase  = "ClusterInstalling"
 	ClusterInstallFailedPhase     Phase  = "ClusterInstallFailed"
+	ClusterUpgradeFailedPhase     Phase  = "ClusterUpgradeFailed"
+	NodePoolScalingFailedPhase    Phase  = "NodePoolScalingFailed"
+	HibernationFailedPhase        Phase  = "HibernationFailed"
 	ArgoClusterFailedPhase        Phase  = "ArgoClusterFailed"
 	AddingArgoClusterPhase        Phase  = "AddingArgoCluster"
 	ClusterSetupCreateFailedPhase Phase  = "ClusterSetupCreateFailedPhase"
@@ -75,10 +300,69 @@ const (
 	ReadyPhase                    Phase  = "Ready"
 	CredentialsFailedPhase        Phase  = "CredentialsFailed"
 	FailedPhase                   Phase  = "Failed"
+	DeletionHeldPhase             Phase  = "DeletionHeld"
 )
 
+// IsFailed returns true if the phase represents a failure
+func (p Phase) IsFailed() bool {
+	switch p {
+	case PreviewFailedPhase,
+		ClusterDefinitionFailedPhase,
+		ClusterInstallFailedPhase,
+		ClusterUpgradeFailedPhase,
+		NodePoolScalingFailedPhase,
+		HibernationFailedPhase,
+		ArgoClusterFailedPhase,
+		ClusterSetupCreateFailedPhase,
+		ClusterSetupDegradedPhase,
+		ClusterSetupErrorPhase,
+		ClusterSetupFailedPhase,
+		CredentialsFailedPhase,
+		FailedPhase:
+		return true
+	}
+	return false
+}
+
+// ProvisioningSLOType names a provisioning SLO of the template
+// +kubebuilder:validation:Enum=clusterInstall;ready
+type ProvisioningSLOType string
+
+const (
+	ClusterInstallSLO ProvisioningSLOType = "clusterInstall"
+	ReadySLO          ProvisioningSLOType = "ready"
+)
+
+type ProvisioningSLOStatus struct {
+	// +optional
+	// How long it took to install the cluster
+	ClusterInstallDuration *metav1.Duration `json:"clusterInstallDuration,omitempty"`
+	// +optional
+	// How long it took until the instance became Ready
+	ReadyDuration *metav1.Duration `json:"readyDuration,omitempty"`
+	// +optional
+	// Provisioning SLOs of the template the instance violated
+	ViolatedSLOs []ProvisioningSLOType `json:"violatedSLOs,omitempty"`
+}
+
 type ClusterTemplateInstanceStatus struct {
 	ClusterTemplateSpec *ClusterTemplateSpec `json:"clusterTemplateSpec,omitempty"`
+	// +optional
+	// Stable identifier of the cluster, generated when the instance is created (the UID of the instance) and kept when the instance is restored from a backup. Used as the Helm release name of the cluster definition instead of the name and namespace of the instance
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ClusterID string `json:"clusterID,omitempty"`
+	// +optional
+	// Name of the cluster passed in the cluster definition values, generated when the template of the instance is snapshotted if the template sets clusterName
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ClusterName string `json:"clusterName,omitempty"`
+	// +optional
+	// Time the instance is deleted at, set if the template limits the lifetime of its instances
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+	// +optional
+	// Provisioning durations of the instance, set if the template declares provisioning SLOs
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ProvisioningSLO *ProvisioningSLOStatus `json:"provisioningSLO,omitempty"`
 	// A reference for secret which contains username and password under keys "username" and "password"
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	AdminPassword *corev1.LocalObjectReference `json:"adminPassword,omitempty"`
@@ -88,6 +372,20 @@ type ClusterTemplateInstanceStatus struct {
 	// API server URL of the new cluster
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	APIserverURL string `json:"apiServerURL,omitempty"`
+	// +optional
+	// API server URL of the new cluster reachable from the hub cluster network, set if the cluster
+	// provider exposes such endpoint. Kubeconfig contains a context with '-internal' suffix using it.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	APIserverInternalURL string `json:"apiServerInternalURL,omitempty"`
+	// +optional
+	// URL of the web console of the new cluster, set for OpenShift clusters
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ConsoleURL string `json:"consoleURL,omitempty"`
+	// +optional
+	// OpenShift version the new cluster runs, reported by the cluster provider (ie the last
+	// completed version of the HostedCluster) or read from the ClusterVersion of the cluster
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	OpenShiftVersion string `json:"openshiftVersion,omitempty"`
 	// Resource conditions
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	Conditions []metav1.Condition `json:"conditions"`
@@ -100,6 +398,193 @@ type ClusterTemplateInstanceStatus struct {
 	// Additional message for Phase
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	Message string `json:"message"`
+	// +optional
+	// The generation observed by the controller
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +optional
+	// How many times a rolled back cluster installation was retried
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	InstallRetries int `json:"installRetries,omitempty"`
+	// +optional
+	// Progress of the cluster upgrade requested by spec.upgrade
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Upgrade *ClusterUpgradeStatus `json:"upgrade,omitempty"`
+	// +optional
+	// Results of the test hooks of the cluster definition Helm chart
+	ChartTests *ChartTestsStatus `json:"chartTests,omitempty"`
+	// +optional
+	// A reference for ConfigMap which contains manifests rendered by spec.preview under key "manifests.yaml"
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Preview *corev1.LocalObjectReference `json:"preview,omitempty"`
+	// +optional
+	// Summary of the instance computed from the rest of the status, intended for UIs like the
+	// console plugin. The schema is stable, fields are only added.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Overview *InstanceOverview `json:"overview,omitempty"`
+	// +optional
+	// Infrastructure platform of the cluster and its platform specific details, reported for HostedClusters and Agent ClusterDeployments
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Platform *ClusterPlatformStatus `json:"platform,omitempty"`
+	// +optional
+	// Node counts and conditions of the node pools created by the cluster definition, reported for HostedClusters
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	NodePools []NodePoolStatus `json:"nodePools,omitempty"`
+	// +optional
+	// Cluster-scoped resources (ie ClusterRoles, CRDs) created by the cluster definition. Resources
+	// shared with other instances are not deleted with the instance.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ClusterScopedResources []ClusterScopedResource `json:"clusterScopedResources,omitempty"`
+	// +optional
+	// Helm charts verified by spec.chartVerification of the template and installed by the ArgoCD Applications
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	VerifiedCharts []VerifiedChart `json:"verifiedCharts,omitempty"`
+}
+
+// Helm chart verified against the public keys trusted by the template
+type VerifiedChart struct {
+	// +optional
+	// Name of the cluster setup, empty for the cluster definition
+	ClusterSetup string `json:"clusterSetup,omitempty"`
+	// Name of the chart
+	Chart string `json:"chart"`
+	// Version of the chart
+	Version string `json:"version"`
+	// Digest of the verified chart archive, ie 'sha256:<hex>'
+	Digest string `json:"digest"`
+}
+
+// Cluster-scoped resource created by the cluster definition
+type ClusterScopedResource struct {
+	// +optional
+	// API group of the resource, empty for the core group
+	Group string `json:"group,omitempty"`
+	// Version of the resource
+	Version string `json:"version"`
+	// Kind of the resource
+	Kind string `json:"kind"`
+	// Name of the resource
+	Name string `json:"name"`
+}
+
+// Status of a node pool of the cluster
+type NodePoolStatus struct {
+	// Name of the node pool
+	Name string `json:"name"`
+	// Number of nodes the pool should have, the minimum for autoscaled pools
+	DesiredReplicas int32 `json:"desiredReplicas"`
+	// Number of nodes which joined the cluster
+	ReadyReplicas int32 `json:"readyReplicas"`
+	// +optional
+	// Version of OpenShift applied to the nodes
+	Version string `json:"version,omitempty"`
+	// +optional
+	// Conditions of the node pool
+	Conditions []NodePoolCondition `json:"conditions,omitempty"`
+}
+
+type NodePoolCondition struct {
+	// Type of the condition, ie 'Ready'
+	Type string `json:"type"`
+	// Status of the condition, one of True, False, Unknown
+	Status string `json:"status"`
+	// +optional
+	// Reason of the last transition
+	Reason string `json:"reason,omitempty"`
+	// +optional
+	// Details of the last transition
+	Message string `json:"message,omitempty"`
+}
+
+// Infrastructure platform of the cluster
+type ClusterPlatformStatus struct {
+	// Type of the platform, ie 'AWS', 'Agent', 'KubeVirt' or 'None'
+	Type string `json:"type"`
+	// +optional
+	// Region of the cluster, set for AWS
+	Region string `json:"region,omitempty"`
+	// +optional
+	// Namespace the Agents of the cluster are searched in, set for Agent
+	AgentNamespace string `json:"agentNamespace,omitempty"`
+	// +optional
+	// Endpoint of the ignition server the nodes fetch their configuration from, needed to boot nodes of Agent, KubeVirt and None clusters
+	IgnitionEndpoint string `json:"ignitionEndpoint,omitempty"`
+	// +optional
+	// Template of the OAuth callback URL of identity providers, '[identity-provider-name]' is replaced by the name of the provider
+	OAuthCallbackURLTemplate string `json:"oauthCallbackURLTemplate,omitempty"`
+	// +optional
+	// Number of hosts the Assisted Installer needs to install the cluster, set for Agent clusters of ClusterDeployments
+	RequiredAgents int `json:"requiredAgents,omitempty"`
+	// +optional
+	// Number of discovered hosts bound to the cluster, set for Agent clusters of ClusterDeployments
+	BoundAgents int `json:"boundAgents,omitempty"`
+}
+
+type OverviewStepType string
+
+const (
+	// Installation of the cluster definition
+	InstallStep OverviewStepType = "Install"
+	// Registration of the new cluster in ArgoCD
+	ArgoClusterStep OverviewStepType = "ArgoCluster"
+	// A cluster setup
+	SetupStep OverviewStepType = "Setup"
+)
+
+type OverviewStepPhase string
+
+const (
+	StepPending   OverviewStepPhase = "Pending"
+	StepRunning   OverviewStepPhase = "Running"
+	StepSucceeded OverviewStepPhase = "Succeeded"
+	StepFailed    OverviewStepPhase = "Failed"
+)
+
+type OverviewStep struct {
+	// Name of the step - name of the cluster setup for setup steps, type of the step otherwise
+	Name string `json:"name"`
+	// Type of the step
+	// +kubebuilder:validation:Enum=Install;ArgoCluster;Setup
+	Type OverviewStepType `json:"type"`
+	// Phase of the step
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+	Phase OverviewStepPhase `json:"phase"`
+	// +optional
+	// Additional message for the phase
+	Message string `json:"message,omitempty"`
+}
+
+type TimelineEntry struct {
+	// Phase the instance entered
+	Phase Phase `json:"phase"`
+	// +optional
+	// Message of the phase
+	Message string `json:"message,omitempty"`
+	// Time the instance entered the phase
+	Time metav1.Time `json:"time"`
+}
+
+type CredentialsOverview struct {
+	// +optional
+	// Name of the secret which contains kubeconfig under key "kubeconfig"
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// +optional
+	// Name of the secret which contains username and password under keys "username" and "password"
+	AdminPassword string `json:"adminPassword,omitempty"`
+}
+
+type InstanceOverview struct {
+	// Percentage of succeeded steps
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Progress int `json:"progress"`
+	// Provisioning steps in the order of execution
+	Steps []OverviewStep `json:"steps"`
+	// +optional
+	// Phases the instance went through, the oldest first. Only the latest entries are kept.
+	Timeline []TimelineEntry `json:"timeline,omitempty"`
+	// Secrets with credentials of the cluster, in the namespace of the instance
+	Credentials CredentialsOverview `json:"credentials"`
 }
 
 //+kubebuilder:object:root=true
@@ -109,6 +594,7 @@ type ClusterTemplateInstanceStatus struct {
 //+kubebuilder:printcolumn:name="Adminpassword",type="string",JSONPath=".status.adminPassword.name",description="Admin Secret"
 //+kubebuilder:printcolumn:name="Kubeconfig",type="string",JSONPath=".status.kubeconfig.name",description="Kubeconfig Secret"
 //+kubebuilder:printcolumn:name="API URL",type="string",JSONPath=".status.apiServerURL",description="API URL"
+//+kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.openshiftVersion",description="OpenShift version"
 //+operator-sdk:csv:customresourcedefinitions:displayName="Cluster template instance",resources={{Pod, v1, ""}}
 
 // Represents instance of a cluster
@@ -0,0 +1,35 @@
+package v1alpha1
+
+import "fmt"
+
+const (
+	// OCPReleaseRepository holds the release images of OCP versions
+	OCPReleaseRepository = "quay.io/openshift-release-dev/ocp-release"
+	// DefaultReleaseArchitecture is the architecture of the release image of a version unless
+	// set by the upgrade
+	DefaultReleaseArchitecture = "x86_64"
+)
+
+// Validate checks the upgrade sets exactly one of the release image and the version
+func (u *ClusterUpgrade) Validate() error {
+	if (u.ReleaseImage == "") == (u.Version == "") {
+		return fmt.Errorf("upgrade has to set exactly one of releaseImage and version")
+	}
+	if u.Architecture != "" && u.Version == "" {
+		return fmt.Errorf("upgrade architecture is supported with version only")
+	}
+	return nil
+}
+
+// GetReleaseImage returns the release image the cluster is upgraded to, the release image of
+// the version in the OCP release repository if the version is set
+func (u *ClusterUpgrade) GetReleaseImage() string {
+	if u.ReleaseImage != "" {
+		return u.ReleaseImage
+	}
+	architecture := u.Architecture
+	if architecture == "" {
+		architecture = DefaultReleaseArchitecture
+	}
+	return fmt.Sprintf("%s:%s-%s", OCPReleaseRepository, u.Version, architecture)
+}
@@ -18,12 +18,19 @@ package v1alpha1
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
 	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/kubernetes-client/go-base/config/api"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/strvals"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -31,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/selection"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -48,6 +56,179 @@ func (i *ClusterTemplateInstance) GetKubeconfigRef() string {
 	return i.Name + "-admin-kubeconfig"
 }
 
+// GetSetupCredentialsRef returns name of the Secret holding kubeconfig of the identity of the
+// cluster setup
+func (i *ClusterTemplateInstance) GetSetupCredentialsRef(setupName string) string {
+	return i.Name + "-" + setupName + "-credentials"
+}
+
+// GetDefaultPullSecretRef returns name of the copy of the hub default pull secret, the namespace
+// the copy is deployed to may be shared by instances of several namespaces
+func (i *ClusterTemplateInstance) GetDefaultPullSecretRef() string {
+	return i.Namespace + "-" + i.Name + "-default-pull-secret"
+}
+
+// GetDefaultSSHKeyRef returns name of the copy of the hub default SSH key, the namespace the copy
+// is deployed to may be shared by instances of several namespaces
+func (i *ClusterTemplateInstance) GetDefaultSSHKeyRef() string {
+	return i.Namespace + "-" + i.Name + "-default-ssh-key"
+}
+
+// GetVerifiedChartRef returns name of the Secret of the ArgoCD namespace holding the verified
+// chart of the cluster setup (empty setupName for the cluster definition)
+func (i *ClusterTemplateInstance) GetVerifiedChartRef(setupName string) string {
+	if setupName == "" {
+		return i.Namespace + "-" + i.Name + "-verified-chart"
+	}
+	return i.Namespace + "-" + i.Name + "-" + setupName + "-verified-chart"
+}
+
+// GetTrustedCABundleRef returns name of the ConfigMap with the copy of the CA bundle trusted by
+// the hub
+func (i *ClusterTemplateInstance) GetTrustedCABundleRef() string {
+	return i.Name + "-trusted-ca-bundle"
+}
+
+// GetAccessLogRef returns name of the ConfigMap recording ClusterCredentialRequest-s
+func (i *ClusterTemplateInstance) GetAccessLogRef() string {
+	return i.Name + "-access-log"
+}
+
+// GetPreviewRef returns name of the ConfigMap with manifests rendered by spec.preview
+func (i *ClusterTemplateInstance) GetPreviewRef() string {
+	return i.Name + "-preview"
+}
+
+// maximum length of Helm release name
+const maxReleaseNameLength = 53
+
+// GetReleaseName returns the Helm release name of the cluster definition - the stable cluster ID
+// of the instance, so names derived from the release by the chart (ie the cluster name in DNS)
+// do not depend on the name of the instance. Instances created before the ID was introduced use
+// the namespace and name of the instance, too long names are truncated and suffixed with a hash
+// of the full name to keep them unique.
+func (i *ClusterTemplateInstance) GetReleaseName() string {
+	if i.Status.ClusterID != "" {
+		return i.Status.ClusterID
+	}
+	return truncateName(i.Namespace + "-" + i.Name)
+}
+
+// GetClusterDefinitionNamespace returns the namespace the cluster definition is deployed to - the
+// target namespace of the template if set, the destination namespace of the cluster definition
+// otherwise
+func (i *ClusterTemplateInstance) GetClusterDefinitionNamespace() string {
+	ctSpec := i.Status.ClusterTemplateSpec
+	if ctSpec.TargetNamespace != "" {
+		return ctSpec.TargetNamespace
+	}
+	if ctSpec.ClusterDefinition.Destination.Namespace == CTIInstanceNamespaceVar {
+		return i.Namespace
+	}
+	return ctSpec.ClusterDefinition.Destination.Namespace
+}
+
+// GetDay1ApplicationName returns name of the cluster definition ArgoCD Application. The name is
+// deterministic, so the application is not created twice when the instance is reconciled again
+// before the cache observed the application (ie by a new leader after failover).
+func (i *ClusterTemplateInstance) GetDay1ApplicationName() string {
+	return i.GetReleaseName()
+}
+
+// GetClusterClaimName returns name of the ClusterClaim of the instance. Claims of instances from
+// all namespaces are created in the namespace of the pool, so the namespace is part of the name.
+func (i *ClusterTemplateInstance) GetClusterClaimName() string {
+	return i.GetReleaseName()
+}
+
+// maximum length of OCM cluster names
+const maxOCMClusterNameLength = 15
+
+// GetOCMClusterName returns name of the cluster created through OCM. Cluster names are unique
+// within the OCM organization and limited to 15 characters, so the name is derived from the
+// cluster ID instead of the name of the instance.
+func (i *ClusterTemplateInstance) GetOCMClusterName() string {
+	id := i.Status.ClusterID
+	if id == "" {
+		id = string(i.UID)
+	}
+	name := "claas-" + strings.ReplaceAll(id, "-", "")
+	if len(name) > maxOCMClusterNameLength {
+		name = name[:maxOCMClusterNameLength]
+	}
+	return name
+}
+
+const (
+	// maximum length of cluster names, they are DNS labels
+	maxClusterNameLength = 63
+	// length of the random suffix of generated cluster names, as for metadata.generateName
+	clusterNameSuffixLength = 5
+)
+
+// GenerateClusterName returns the name of the cluster passed in the cluster definition values -
+// the name of the instance, suffixed by random characters if the template generates unique names.
+// Empty if the template does not pass the cluster name.
+func (i *ClusterTemplateInstance) GenerateClusterName(ctSpec *ClusterTemplateSpec) string {
+	if ctSpec.ClusterName == nil {
+		return ""
+	}
+	if !ctSpec.ClusterName.GenerateSuffix {
+		return i.Name
+	}
+	base := i.Name
+	if maxBase := maxClusterNameLength - clusterNameSuffixLength - 1; len(base) > maxBase {
+		base = base[:maxBase]
+	}
+	return base + "-" + utilrand.String(clusterNameSuffixLength)
+}
+
+// GetDay2ApplicationName returns name of the ArgoCD Application of the cluster setup. ArgoCD uses
+// it as the Helm release name of the setup chart, so it is limited to the release name length.
+func (i *ClusterTemplateInstance) GetDay2ApplicationName(setup string) string {
+	return truncateName(i.Namespace + "-" + i.Name + "-" + setup)
+}
+
+// GetSetupJobName returns name of the Job of the cluster setup, created in the namespace of the
+// instance or on the new cluster. It is limited to the release name length, the Job controller
+// labels the pods by it.
+func (i *ClusterTemplateInstance) GetSetupJobName(setup string) string {
+	return truncateName(i.Name + "-setup-" + setup)
+}
+
+// GetSetupJobRetryName returns name of the Job of the retry of the failed cluster setup, the Jobs
+// of previous attempts are kept
+func (i *ClusterTemplateInstance) GetSetupJobRetryName(setup string, retry int) string {
+	return truncateName(fmt.Sprintf("%s-setup-%s-retry-%d", i.Name, setup, retry))
+}
+
+// GetInstanceLabels returns labels of resources created for the cluster definition (empty setup)
+// or the cluster setup of the instance, ie of their ArgoCD Applications
+func (i *ClusterTemplateInstance) GetInstanceLabels(setup string) map[string]string {
+	labels := map[string]string{
+		CTINameLabel:      i.Name,
+		CTINamespaceLabel: i.Namespace,
+	}
+	if setup != "" {
+		labels[CTISetupLabel] = setup
+	}
+	if i.Status.ClusterID != "" {
+		labels[CTIClusterIDLabel] = i.Status.ClusterID
+	}
+	if i.Status.ClusterName != "" {
+		labels[CTIClusterNameLabel] = i.Status.ClusterName
+	}
+	return labels
+}
+
+func truncateName(name string) string {
+	if len(name) <= maxReleaseNameLength {
+		return name
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))[:8]
+	return name[:maxReleaseNameLength-len(hash)-1] + "-" + hash
+}
+
 func (i *ClusterTemplateInstance) GetOwnerReference() metav1.OwnerReference {
 	return metav1.OwnerReference{
 		Kind:       "ClusterTemplateInstance",
@@ -133,25 +314,168 @@ func (i *ClusterTemplateInstance) CreateDay1Application(
 		appSpec.Source.Helm.Parameters = params
 	}
 
-	if appSpec.Destination.Namespace == CTIInstanceNamespaceVar {
-		appSpec.Destination.Namespace = i.Namespace
+	appSpec.Destination.Namespace = i.GetClusterDefinitionNamespace()
+
+	// ArgoCD names the release after the application unless set. Applications created before
+	// keep their release name, changing it would reinstall the cluster.
+	if appSpec.Source.Helm != nil || appSpec.Source.Chart != "" {
+		// copy, the helm source is shared with the template spec stored in status
+		helm := appSpec.Source.Helm.DeepCopy()
+		if helm == nil {
+			helm = &argo.ApplicationSourceHelm{}
+		}
+		if helm.ReleaseName == "" {
+			helm.ReleaseName = i.GetReleaseName()
+		}
+		if i.Status.ClusterTemplateSpec.SkipCRDs {
+			helm.SkipCrds = true
+		}
+		appSpec.Source.Helm = helm
+	}
+
+	valuesFrom, err := i.GetValuesFrom(ctx, k8sClient, "")
+	if err != nil {
+		return err
+	}
+	if err = setHelmValues(&appSpec, i.getTemplateValues(""), valuesFrom); err != nil {
+		return err
+	}
+
+	if postRenderer := i.Status.ClusterTemplateSpec.PostRenderer; postRenderer != nil {
+		appSpec.Source, err = i.getPostRendererSource(*postRenderer, appSpec.Source, valuesFrom)
+		if err != nil {
+			return err
+		}
+	}
+
+	if i.Status.ClusterTemplateSpec.SelfHeal {
+		// copy, the sync policy is shared with the template spec stored in status
+		syncPolicy := appSpec.SyncPolicy.DeepCopy()
+		if syncPolicy == nil {
+			syncPolicy = &argo.SyncPolicy{}
+		}
+		if syncPolicy.Automated == nil {
+			syncPolicy.Automated = &argo.SyncPolicyAutomated{}
+		}
+		syncPolicy.Automated.SelfHeal = true
+		appSpec.SyncPolicy = syncPolicy
 	}
 
 	argoApp = &argo.Application{
 		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: i.Name + "-",
-			Namespace:    argoCDNamespace,
+			Name:      i.GetDay1ApplicationName(),
+			Namespace: argoCDNamespace,
 			Finalizers: []string{
 				argo.ResourcesFinalizerName,
 			},
-			Labels: map[string]string{
-				CTINameLabel:      i.Name,
-				CTINamespaceLabel: i.Namespace,
-			},
+			Labels: i.GetInstanceLabels(""),
 		},
 		Spec: appSpec,
 	}
-	return k8sClient.Create(ctx, argoApp)
+	return i.createApplication(ctx, k8sClient, argoApp)
+}
+
+// createApplication creates the ArgoCD Application unless the application of the instance exists
+// already - it was created by a previous reconciliation which is not observed by the cache yet.
+// Application of the same name which is being deleted (ie of a previous instance of the same
+// name) is reported as an error, so the creation is retried.
+func (i *ClusterTemplateInstance) createApplication(
+	ctx context.Context,
+	k8sClient client.Client,
+	app *argo.Application,
+) error {
+	err := k8sClient.Create(ctx, app)
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	existing := &argo.Application{}
+	if err = k8sClient.Get(ctx, client.ObjectKeyFromObject(app), existing); err != nil {
+		return err
+	}
+	if existing.DeletionTimestamp != nil {
+		return fmt.Errorf("application %s is being deleted", app.Name)
+	}
+	if existing.Labels[CTINameLabel] != i.Name ||
+		existing.Labels[CTINamespaceLabel] != i.Namespace ||
+		existing.Labels[CTISetupLabel] != app.Labels[CTISetupLabel] {
+		return fmt.Errorf("application %s already exists", app.Name)
+	}
+	return nil
+}
+
+// UpdateApplicationsParameters sets Helm parameters and values of existing day1 and day2
+// applications to the current instance parameters and referenced values
+func (i *ClusterTemplateInstance) UpdateApplicationsParameters(
+	ctx context.Context,
+	k8sClient client.Client,
+	argoCDNamespace string,
+) error {
+	app, err := i.GetDay1Application(ctx, k8sClient, argoCDNamespace)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	if app != nil {
+		valuesFrom, err := i.GetValuesFrom(ctx, k8sClient, "")
+		if err != nil {
+			return err
+		}
+		postRendered, err := i.updatePostRendererValues(&app.Spec, valuesFrom)
+		if err != nil {
+			return err
+		}
+		if !postRendered {
+			params, err := i.GetHelmParameters("")
+			if err != nil {
+				return err
+			}
+			setHelmParameters(&app.Spec, params)
+			if err = setHelmValues(&app.Spec, i.getTemplateValues(""), valuesFrom); err != nil {
+				return err
+			}
+		}
+		if err := k8sClient.Update(ctx, app); err != nil {
+			return err
+		}
+	}
+
+	apps, err := i.GetDay2Applications(ctx, k8sClient, argoCDNamespace)
+	if err != nil {
+		return err
+	}
+	for _, app := range apps.Items {
+		setup := app.GetLabels()[CTISetupLabel]
+		params, err := i.GetHelmParameters(setup)
+		if err != nil {
+			return err
+		}
+		setHelmParameters(&app.Spec, params)
+		valuesFrom, err := i.GetValuesFrom(ctx, k8sClient, setup)
+		if err != nil {
+			return err
+		}
+		if err = setHelmValues(&app.Spec, i.getTemplateValues(setup), valuesFrom); err != nil {
+			return err
+		}
+		if err := k8sClient.Update(ctx, &app); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setHelmParameters(appSpec *argo.ApplicationSpec, params []argo.HelmParameter) {
+	if len(params) == 0 {
+		if appSpec.Source.Helm != nil {
+			appSpec.Source.Helm.Parameters = nil
+		}
+		return
+	}
+	if appSpec.Source.Helm == nil {
+		appSpec.Source.Helm = &argo.ApplicationSourceHelm{}
+	}
+	appSpec.Source.Helm.Parameters = params
 }
 
 func (i *ClusterTemplateInstance) GetDay2Applications(
@@ -189,6 +513,50 @@ func (i *ClusterTemplateInstance) GetDay2Applications(
 	return applications, err
 }
 
+// SetupCreationError is returned by CreateDay2Applications when applications of some cluster
+// setups could not be created
+// +kubebuilder:object:generate=false
+type SetupCreationError struct {
+	// Errors keyed by name of the cluster setup
+	Errors map[string]error
+}
+
+func (e *SetupCreationError) Error() string {
+	names := []string{}
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := []string{}
+	for _, name := range names {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", name, e.Errors[name]))
+	}
+	return "failed to create applications of cluster setups - " + strings.Join(msgs, "; ")
+}
+
+// GetPendingSetupDependencies returns names of the cluster setups the cluster setup depends on
+// which did not succeed yet
+func (i *ClusterTemplateInstance) GetPendingSetupDependencies(setup ClusterSetup) []string {
+	succeeded := map[string]bool{}
+	if i.Status.ClusterSetup != nil {
+		for _, setupStatus := range *i.Status.ClusterSetup {
+			if setupStatus.Status == argocd.ApplicationHealthy {
+				succeeded[setupStatus.Name] = true
+			}
+		}
+	}
+	pending := []string{}
+	for _, dependency := range setup.DependsOn {
+		if !succeeded[dependency] {
+			pending = append(pending, dependency)
+		}
+	}
+	return pending
+}
+
+// CreateDay2Applications creates applications of cluster setups which do not exist yet. A failure
+// of one setup does not prevent creation of the others, the failures are returned as
+// SetupCreationError.
 func (i *ClusterTemplateInstance) CreateDay2Applications(
 	ctx context.Context,
 	k8sClient client.Client,
@@ -221,7 +589,16 @@ func (i *ClusterTemplateInstance) CreateDay2Applications(
 		return err
 	}
 
+	errs := map[string]error{}
 	for _, clusterSetup := range i.Status.ClusterTemplateSpec.ClusterSetup {
+		// run by a Job instead of an application
+		if !clusterSetup.UsesApplication() {
+			continue
+		}
+		// created once the setups it depends on succeed
+		if len(i.GetPendingSetupDependencies(clusterSetup)) > 0 {
+			continue
+		}
 		setupAlreadyExists := false
 		for _, app := range apps.Items {
 			val := app.GetLabels()[CTISetupLabel]
@@ -229,42 +606,77 @@ func (i *ClusterTemplateInstance) CreateDay2Applications(
 				setupAlreadyExists = true
 			}
 		}
-		if !setupAlreadyExists {
-			params, err := i.GetHelmParameters(clusterSetup.Name)
+		if setupAlreadyExists {
+			continue
+		}
+		// remaining setups are created even if one fails, failed are retried by next reconcile
+		if err := i.createDay2Application(
+			ctx,
+			k8sClient,
+			argoCDNamespace,
+			clusterSetup,
+			kubeconfig,
+		); err != nil {
+			log.Error(err, "Failed to create day2 application", "setup", clusterSetup.Name)
+			errs[clusterSetup.Name] = err
+		}
+	}
+	if len(errs) > 0 {
+		return &SetupCreationError{Errors: errs}
+	}
+	return nil
+}
 
-			if err != nil {
-				return err
-			}
+func (i *ClusterTemplateInstance) createDay2Application(
+	ctx context.Context,
+	k8sClient client.Client,
+	argoCDNamespace string,
+	clusterSetup ClusterSetup,
+	kubeconfig api.Config,
+) error {
+	params, err := i.GetHelmParameters(clusterSetup.Name)
+	if err != nil {
+		return err
+	}
+	if clusterSetup.Identity != nil {
+		params = append(params, argo.HelmParameter{
+			Name:  SetupCredentialsParameter,
+			Value: i.GetSetupCredentialsRef(clusterSetup.Name),
+		})
+	}
 
-			if len(params) > 0 {
-				if clusterSetup.Spec.Source.Helm == nil {
-					clusterSetup.Spec.Source.Helm = &argo.ApplicationSourceHelm{}
-				}
-				clusterSetup.Spec.Source.Helm.Parameters = params
-			}
+	if len(params) > 0 {
+		if clusterSetup.Spec.Source.Helm == nil {
+			clusterSetup.Spec.Source.Helm = &argo.ApplicationSourceHelm{}
+		}
+		clusterSetup.Spec.Source.Helm.Parameters = params
+	}
 
-			if clusterSetup.Spec.Destination.Server == CTIClusterTargetVar {
-				clusterSetup.Spec.Destination.Server = kubeconfig.Clusters[0].Cluster.Server
-			}
+	valuesFrom, err := i.GetValuesFrom(ctx, k8sClient, clusterSetup.Name)
+	if err != nil {
+		return err
+	}
+	if err = setHelmValues(
+		&clusterSetup.Spec,
+		i.getTemplateValues(clusterSetup.Name),
+		valuesFrom,
+	); err != nil {
+		return err
+	}
 
-			argoApp := argo.Application{
-				ObjectMeta: metav1.ObjectMeta{
-					GenerateName: i.Name + "-",
-					Namespace:    argoCDNamespace,
-					Labels: map[string]string{
-						CTINameLabel:      i.Name,
-						CTINamespaceLabel: i.Namespace,
-						CTISetupLabel:     clusterSetup.Name,
-					},
-				},
-				Spec: clusterSetup.Spec,
-			}
-			if err := k8sClient.Create(ctx, &argoApp); err != nil {
-				return err
-			}
-		}
+	if clusterSetup.Spec.Destination.Server == CTIClusterTargetVar {
+		clusterSetup.Spec.Destination.Server = kubeconfig.Clusters[0].Cluster.Server
 	}
-	return nil
+
+	argoApp := argo.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      i.GetDay2ApplicationName(clusterSetup.Name),
+			Namespace: argoCDNamespace,
+			Labels:    i.GetInstanceLabels(clusterSetup.Name),
+		},
+		Spec: clusterSetup.Spec,
+	}
+	return i.createApplication(ctx, k8sClient, &argoApp)
 }
 
 func (i *ClusterTemplateInstance) GetHelmParameters(
@@ -287,7 +699,7 @@ func (i *ClusterTemplateInstance) GetHelmParameters(
 		}
 	}
 
-	for _, param := range i.Spec.Parameters {
+	for _, param := range ctSpec.MigrateParameters(i.Spec.Parameters) {
 		if param.ClusterSetup == day2Name {
 			added := false
 			for _, ctParam := range params {
@@ -308,6 +720,146 @@ func (i *ClusterTemplateInstance) GetHelmParameters(
 	return params, nil
 }
 
+// ValidateClusterDefinitionValues validates values of the cluster definition Helm chart
+// (chart values overridden by template values, values of referenced ConfigMaps and Secrets and
+// parameters) against chart values.schema.json
+func (i *ClusterTemplateInstance) ValidateClusterDefinitionValues(
+	chartValues string,
+	chartSchema string,
+	valuesFrom chartutil.Values,
+) error {
+	if chartSchema == "" {
+		return nil
+	}
+
+	values, err := i.getClusterDefinitionValues(chartValues, valuesFrom)
+	if err != nil {
+		return err
+	}
+
+	if err = i.setParameterValues(values); err != nil {
+		return err
+	}
+
+	return chartutil.ValidateAgainstSingleSchema(values, []byte(chartSchema))
+}
+
+// GetClusterDefinitionValues returns values passed to the cluster definition chart - template
+// values overridden by values of referenced ConfigMaps and Secrets (valuesFrom) and by instance
+// parameters. Defaults of the chart are not included.
+func (i *ClusterTemplateInstance) GetClusterDefinitionValues(
+	valuesFrom chartutil.Values,
+) (chartutil.Values, error) {
+	values, err := i.getClusterDefinitionValues("", valuesFrom)
+	if err != nil {
+		return nil, err
+	}
+	if err = i.setParameterValues(values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// setParameterValues sets cluster definition parameters of the instance in values
+func (i *ClusterTemplateInstance) setParameterValues(values chartutil.Values) error {
+	params, err := i.GetHelmParameters("")
+	if err != nil {
+		return err
+	}
+	for _, param := range params {
+		parse := strvals.ParseInto
+		if param.ForceString {
+			parse = strvals.ParseIntoString
+		}
+		if err := parse(fmt.Sprintf("%s=%s", param.Name, param.Value), values); err != nil {
+			return fmt.Errorf("failed to parse parameter '%s' - %q", param.Name, err)
+		}
+	}
+	return nil
+}
+
+// getClusterDefinitionValues returns chart values overridden by template values and by values
+// read from ConfigMaps and Secrets referenced by the instance
+func (i *ClusterTemplateInstance) getClusterDefinitionValues(
+	chartValues string,
+	valuesFrom chartutil.Values,
+) (chartutil.Values, error) {
+	values, err := chartutil.ReadValues([]byte(chartValues))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse chart values - %q", err)
+	}
+
+	templateValues, err := overrideValues(i.getTemplateValues(""), valuesFrom)
+	if err != nil {
+		return nil, err
+	}
+	return chartutil.CoalesceTables(templateValues, values), nil
+}
+
+// GetRequestedCompute returns number of worker nodes and vCPUs requested by the instance
+func (i *ClusterTemplateInstance) GetRequestedCompute(
+	ctSpec ClusterTemplateSpec,
+) (int, int, error) {
+	if ctSpec.Compute == nil {
+		return 0, 0, nil
+	}
+
+	nodes, err := i.getComputeValue(ctSpec, ctSpec.Compute.Nodes)
+	if err != nil {
+		return 0, 0, err
+	}
+	// nodes of composed node pools replace the nodes set by the parameter
+	if ctSpec.NodePools != nil && len(i.Spec.NodePools) > 0 {
+		nodes = i.GetRequestedNodes()
+	} else if scaled := i.getScaledNodes(); scaled > nodes {
+		// NodePools rendered by the chart can not be told apart within the nodes set by the
+		// parameter, the scaled NodePools are counted unless the parameter requests more nodes
+		nodes = scaled
+	}
+
+	vcpuPerNode, err := i.getComputeValue(ctSpec, ctSpec.Compute.VCPUPerNode)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return nodes, nodes * vcpuPerNode, nil
+}
+
+func (i *ClusterTemplateInstance) getComputeValue(
+	ctSpec ClusterTemplateSpec,
+	rule *ComputeRule,
+) (int, error) {
+	if rule == nil {
+		return 0, nil
+	}
+
+	value := ""
+	if rule.Parameter != "" {
+		if ctSpec.ClusterDefinition.Source.Helm != nil {
+			for _, param := range ctSpec.ClusterDefinition.Source.Helm.Parameters {
+				if param.Name == rule.Parameter {
+					value = param.Value
+				}
+			}
+		}
+		for _, param := range ctSpec.MigrateParameters(i.Spec.Parameters) {
+			if param.ClusterSetup == "" && param.Name == rule.Parameter {
+				value = param.Value
+			}
+		}
+	}
+
+	if value == "" {
+		return rule.Default, nil
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("parameter '%s' is not a valid count - %q", rule.Parameter, value)
+	}
+	return count, nil
+}
+
 func (i *ClusterTemplateInstance) GetSubjectsWithClusterTemplateUserRole(
 	ctx context.Context, k8sClient client.Client) ([]rbacv1.Subject, error) {
 	allRoleBindingsInNamespace := &rbacv1.RoleBindingList{}
@@ -335,11 +887,26 @@ func (i *ClusterTemplateInstance) GetSubjectsWithClusterTemplateUserRole(
 	return result, nil
 }
 
+// CreateDynamicRole creates the Role giving access to secrets of the cluster. If credential
+// access is audited, the secrets can be read only through a ClusterCredentialRequest.
 func (i *ClusterTemplateInstance) CreateDynamicRole(
-	ctx context.Context, k8sClient client.Client) (*rbacv1.Role, error) {
+	ctx context.Context, k8sClient client.Client, auditCredentialAccess bool) (*rbacv1.Role, error) {
 	roleName := i.Name + "-role-managed"
 	roleNamespace := i.Namespace
 	secretNames := []string{i.GetKubeadminPassRef(), i.GetKubeconfigRef()}
+	rule := rbacv1.PolicyRule{
+		APIGroups:     []string{""},
+		Verbs:         []string{"get"},
+		Resources:     []string{"secrets"},
+		ResourceNames: secretNames,
+	}
+	if auditCredentialAccess {
+		rule = rbacv1.PolicyRule{
+			APIGroups: []string{GroupVersion.Group},
+			Verbs:     []string{"create", "get", "list", "watch"},
+			Resources: []string{"clustercredentialrequests"},
+		}
+	}
 
 	existingRole := &rbacv1.Role{}
 	err := k8sClient.Get(
@@ -357,16 +924,12 @@ func (i *ClusterTemplateInstance) CreateDynamicRole(
 			Namespace:       roleNamespace,
 			OwnerReferences: []metav1.OwnerReference{i.GetOwnerReference()},
 		},
-		Rules: []rbacv1.PolicyRule{{
-			APIGroups:     []string{""},
-			Verbs:         []string{"get"},
-			Resources:     []string{"secrets"},
-			ResourceNames: secretNames,
-		}},
+		Rules: []rbacv1.PolicyRule{rule},
 	}
 
 	if err == nil {
 		// Results in no action if there is no difference in content
+		desiredRole.ResourceVersion = existingRole.ResourceVersion
 		return desiredRole, k8sClient.Update(ctx, desiredRole)
 	} else if apierrors.IsNotFound(err) {
 		return desiredRole, k8sClient.Create(ctx, desiredRole)
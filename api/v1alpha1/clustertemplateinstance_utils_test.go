@@ -2,6 +2,8 @@ package v1alpha1
 
 import (
 	"context"
+	"errors"
+	"strings"
 
 	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/kubernetes-client/go-base/config/api"
@@ -14,6 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -50,6 +53,16 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 		}
 		Expect(cti.GetKubeconfigRef()).Should(Equal("foo-admin-kubeconfig"))
 	})
+	It("GetVerifiedChartRef", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+		}
+		Expect(cti.GetVerifiedChartRef("")).Should(Equal("bar-foo-verified-chart"))
+		Expect(cti.GetVerifiedChartRef("setup")).Should(Equal("bar-foo-setup-verified-chart"))
+	})
 	It("GetOwnerReference", func() {
 		cti := ClusterTemplateInstance{
 			ObjectMeta: metav1.ObjectMeta{
@@ -370,6 +383,103 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 		Expect(app).Should(BeNil())
 	})
 
+	It("GetReleaseName", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+		}
+		Expect(cti.GetReleaseName()).Should(Equal("default-foo"))
+
+		cti.Namespace = strings.Repeat("a", 40)
+		cti.Name = strings.Repeat("b", 40)
+		releaseName := cti.GetReleaseName()
+		Expect(releaseName).Should(HaveLen(53))
+		Expect(releaseName).Should(HavePrefix(cti.Namespace + "-"))
+
+		cti.Name = strings.Repeat("b", 39) + "c"
+		Expect(cti.GetReleaseName()).ShouldNot(Equal(releaseName))
+
+		cti.Status.ClusterID = "0b7b1f0c-5c4e-4c55-9d67-2b9f1e6c7a10"
+		Expect(cti.GetReleaseName()).Should(Equal(cti.Status.ClusterID))
+		Expect(cti.GetDay1ApplicationName()).Should(Equal(cti.Status.ClusterID))
+	})
+
+	It("GetClusterDefinitionNamespace", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					ClusterDefinition: argo.ApplicationSpec{
+						Destination: argo.ApplicationDestination{
+							Namespace: CTIInstanceNamespaceVar,
+						},
+					},
+				},
+			},
+		}
+		Expect(cti.GetClusterDefinitionNamespace()).Should(Equal("default"))
+
+		cti.Status.ClusterTemplateSpec.ClusterDefinition.Destination.Namespace = "hosted"
+		Expect(cti.GetClusterDefinitionNamespace()).Should(Equal("hosted"))
+
+		cti.Status.ClusterTemplateSpec.TargetNamespace = "clusters"
+		Expect(cti.GetClusterDefinitionNamespace()).Should(Equal("clusters"))
+	})
+
+	It("GetDay2ApplicationName", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+		}
+		Expect(cti.GetDay1ApplicationName()).Should(Equal("default-foo"))
+		Expect(cti.GetDay2ApplicationName("day2")).Should(Equal("default-foo-day2"))
+		Expect(cti.GetDay2ApplicationName(strings.Repeat("a", 60))).Should(HaveLen(53))
+	})
+
+	It("Does not duplicate applications created before failover", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					ClusterDefinition: argo.ApplicationSpec{
+						Source: argo.ApplicationSource{
+							RepoURL: "http://foo",
+							Chart:   "hypershift-template",
+						},
+					},
+				},
+			},
+		}
+
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		Expect(cti.CreateDay1Application(ctx, k8sClient, "argocd")).Should(Succeed())
+
+		// cache of the new leader did not observe the application created by the previous one
+		staleClient := staleCacheClient{Client: k8sClient}
+		Expect(cti.CreateDay1Application(ctx, staleClient, "argocd")).Should(Succeed())
+
+		apps := argo.ApplicationList{}
+		Expect(k8sClient.List(ctx, &apps)).Should(Succeed())
+		Expect(apps.Items).Should(HaveLen(1))
+		Expect(apps.Items[0].Name).Should(Equal("default-foo"))
+
+		// application of a deleted instance of the same name is still being deleted
+		Expect(k8sClient.Delete(ctx, &apps.Items[0])).Should(Succeed())
+		Expect(cti.CreateDay1Application(ctx, staleClient, "argocd")).Should(MatchError(
+			"application default-foo is being deleted",
+		))
+	})
+
 	It("CreateDay1Application", func() {
 		cti := ClusterTemplateInstance{
 			ObjectMeta: metav1.ObjectMeta{
@@ -430,7 +540,172 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 		Expect(apps.Items[0].Spec.Destination.Namespace).To(Equal("default"))
 		Expect(apps.Items[0].Spec.Source.Helm.Parameters[0].Name).To(Equal("fooParam"))
 		Expect(apps.Items[0].Spec.Source.Helm.Parameters[0].Value).To(Equal("foo"))
+		Expect(apps.Items[0].Spec.Source.Helm.ReleaseName).To(Equal("default-foo"))
+		Expect(cti.Status.ClusterTemplateSpec.ClusterDefinition.Source.Helm).To(BeNil())
+		Expect(apps.Items[0].Spec.SyncPolicy).To(BeNil())
+
+		cti.Status.ClusterTemplateSpec.SelfHeal = true
+
+		client = fake.NewFakeClientWithScheme(scheme.Scheme)
+		err = cti.CreateDay1Application(ctx, client, "argocd")
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		apps = argo.ApplicationList{}
+		Expect(client.List(ctx, &apps)).Should(Succeed())
+
+		Expect(apps.Items[0].Spec.SyncPolicy.Automated.SelfHeal).To(BeTrue())
+		Expect(cti.Status.ClusterTemplateSpec.ClusterDefinition.SyncPolicy).To(BeNil())
+		Expect(apps.Items[0].Spec.Source.Helm.SkipCrds).To(BeFalse())
+
+		cti.Status.ClusterTemplateSpec.SkipCRDs = true
+
+		client = fake.NewFakeClientWithScheme(scheme.Scheme)
+		err = cti.CreateDay1Application(ctx, client, "argocd")
+
+		Expect(err).ShouldNot(HaveOccurred())
+
+		apps = argo.ApplicationList{}
+		Expect(client.List(ctx, &apps)).Should(Succeed())
+
+		Expect(apps.Items[0].Spec.Source.Helm.SkipCrds).To(BeTrue())
+	})
+
+	It("CreateDay1Application with post renderer", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				Parameters: []Parameter{
+					{
+						Name:  "fooParam",
+						Value: "foo",
+					},
+				},
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					ClusterDefinition: argo.ApplicationSpec{
+						Source: argo.ApplicationSource{
+							RepoURL:        "http://foo",
+							Chart:          "hypershift-template",
+							TargetRevision: "0.0.2",
+						},
+					},
+					PostRenderer: &PostRenderer{
+						RepoURL: "https://foo.io/patches.git",
+						Path:    "hub",
+					},
+				},
+			},
+		}
+
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		Expect(cti.CreateDay1Application(ctx, k8sClient, "argocd")).Should(Succeed())
+
+		apps := argo.ApplicationList{}
+		Expect(k8sClient.List(ctx, &apps)).Should(Succeed())
+		source := apps.Items[0].Spec.Source
+		Expect(source.RepoURL).Should(Equal("https://foo.io/patches.git"))
+		Expect(source.Path).Should(Equal("hub"))
+		Expect(source.Chart).Should(BeEmpty())
+		Expect(source.Helm).Should(BeNil())
+		Expect(source.Plugin.Name).Should(Equal(PostRendererPluginName))
+		Expect(source.Plugin.Env.Environ()).Should(Equal([]string{
+			"HELM_REPO=http://foo",
+			"HELM_CHART=hypershift-template",
+			"HELM_VERSION=0.0.2",
+			"HELM_RELEASE=default-foo",
+			"HELM_VALUES=fooParam: foo\n",
+		}))
+
+		cti.Spec.Parameters[0].Value = "bar"
+		Expect(cti.UpdateApplicationsParameters(ctx, k8sClient, "argocd")).Should(Succeed())
+		app := &argo.Application{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&apps.Items[0]), app)).Should(Succeed())
+		Expect(app.Spec.Source.Plugin.Env.Environ()[4]).Should(Equal("HELM_VALUES=fooParam: bar\n"))
+		Expect(app.Spec.Source.Helm).Should(BeNil())
 
+		cti.Status.ClusterTemplateSpec.ClusterDefinition.Source.Chart = ""
+		k8sClient = fake.NewFakeClientWithScheme(scheme.Scheme)
+		Expect(cti.CreateDay1Application(ctx, k8sClient, "argocd")).ShouldNot(Succeed())
+	})
+
+	It("UpdateApplicationsParameters", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				Parameters: []Parameter{
+					{
+						Name:  "fooParam",
+						Value: "foo",
+					},
+					{
+						Name:         "barParam",
+						Value:        "bar",
+						ClusterSetup: "day2",
+					},
+				},
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					ClusterDefinition: argo.ApplicationSpec{
+						Source: argo.ApplicationSource{
+							RepoURL: "http://foo",
+						},
+					},
+				},
+			},
+		}
+		day1App := &argo.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-day1",
+				Namespace: "argocd",
+				Labels: map[string]string{
+					CTINameLabel:      "foo",
+					CTINamespaceLabel: "default",
+				},
+			},
+		}
+		day2App := &argo.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-day2",
+				Namespace: "argocd",
+				Labels: map[string]string{
+					CTINameLabel:      "foo",
+					CTINamespaceLabel: "default",
+					CTISetupLabel:     "day2",
+				},
+			},
+		}
+
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, day1App, day2App)
+		err := cti.UpdateApplicationsParameters(ctx, k8sClient, "argocd")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		app := &argo.Application{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(day1App), app)).Should(Succeed())
+		Expect(app.Spec.Source.Helm.Parameters).Should(Equal([]argo.HelmParameter{
+			{
+				Name:  "fooParam",
+				Value: "foo",
+			},
+		}))
+		// applications created before namespaced release names keep their release
+		Expect(app.Spec.Source.Helm.ReleaseName).Should(BeEmpty())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(day2App), app)).Should(Succeed())
+		Expect(app.Spec.Source.Helm.Parameters).Should(Equal([]argo.HelmParameter{
+			{
+				Name:  "barParam",
+				Value: "bar",
+			},
+		}))
 	})
 
 	It("CreateDay2Applications", func() {
@@ -528,6 +803,60 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 		Expect(apps.Items[0].Spec.Destination.Server).To(Equal("foo-server"))
 	})
 
+	It("CreateDay2Applications continues when a setup fails", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					ClusterSetup: []ClusterSetup{{Name: "bar"}, {Name: "baz"}},
+				},
+			},
+		}
+		kubeconfig := api.Config{
+			Clusters: []api.NamedCluster{{Name: "foo", Cluster: api.Cluster{Server: "foo-server"}}},
+		}
+		data, err := yaml.Marshal(&kubeconfig)
+		Expect(err).ShouldNot(HaveOccurred())
+		kubeconfigSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetKubeconfigRef(),
+				Namespace: cti.Namespace,
+			},
+			Data: map[string][]byte{"kubeconfig": data},
+		}
+		// application of other owner blocks creation of the 'bar' setup
+		conflictingApp := &argo.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetDay2ApplicationName("bar"),
+				Namespace: "argocd",
+			},
+		}
+
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, kubeconfigSecret, conflictingApp)
+		err = cti.CreateDay2Applications(ctx, client, "argocd")
+		Expect(err).Should(HaveOccurred())
+		setupErr := &SetupCreationError{}
+		Expect(errors.As(err, &setupErr)).Should(BeTrue())
+		Expect(setupErr.Errors).Should(HaveLen(1))
+		Expect(setupErr.Errors).Should(HaveKey("bar"))
+		Expect(err.Error()).Should(ContainSubstring("bar: application default-foo-bar already exists"))
+
+		apps, err := cti.GetDay2Applications(ctx, client, "argocd")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(apps.Items).Should(HaveLen(1))
+		Expect(apps.Items[0].Labels[CTISetupLabel]).Should(Equal("baz"))
+
+		// created setups are skipped, failed are retried
+		Expect(client.Delete(ctx, conflictingApp)).Should(Succeed())
+		Expect(cti.CreateDay2Applications(ctx, client, "argocd")).Should(Succeed())
+		apps, err = cti.GetDay2Applications(ctx, client, "argocd")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(apps.Items).Should(HaveLen(2))
+	})
+
 	It(
 		"GetSubjectsWithClusterTemplateUserRole, CreateDynamicRole and CreateDynamicRoleBinding",
 		func() {
@@ -618,13 +947,76 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 			Expect(roleSubjects).ShouldNot(BeNil())
 			Expect(len(roleSubjects)).Should(Equal(4))
 
-			role, err := cti.CreateDynamicRole(ctx, client)
+			role, err := cti.CreateDynamicRole(ctx, client, false)
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(role).ShouldNot(BeNil())
+			Expect(role.Rules[0].Resources).Should(Equal([]string{"secrets"}))
+
+			role, err = cti.CreateDynamicRole(ctx, client, true)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(role.Rules[0].Resources).Should(Equal([]string{"clustercredentialrequests"}))
 
 			rb, err := cti.CreateDynamicRoleBinding(ctx, client, role, roleSubjects)
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(rb).ShouldNot(BeNil())
 		},
 	)
+	It("ValidateClusterDefinitionValues", func() {
+		schema := `{
+			"type": "object",
+			"properties": {
+				"nodeCount": {
+					"type": "integer",
+					"minimum": 1
+				}
+			}
+		}`
+		cti := ClusterTemplateInstance{
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					ClusterDefinition: argo.ApplicationSpec{
+						Source: argo.ApplicationSource{
+							Chart: "foo",
+						},
+					},
+				},
+			},
+		}
+		err := cti.ValidateClusterDefinitionValues("nodeCount: 2", "", nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = cti.ValidateClusterDefinitionValues("nodeCount: 2", schema, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		cti.Spec.Parameters = []Parameter{
+			{
+				Name:  "nodeCount",
+				Value: "0",
+			},
+		}
+		err = cti.ValidateClusterDefinitionValues("nodeCount: 2", schema, nil)
+		Expect(err).Should(HaveOccurred())
+
+		cti.Spec.Parameters = []Parameter{
+			{
+				Name:  "nodeCount",
+				Value: "3",
+			},
+		}
+		err = cti.ValidateClusterDefinitionValues("nodeCount: 2", schema, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
 })
+
+// staleCacheClient simulates a cache which did not observe existing objects yet
+type staleCacheClient struct {
+	client.Client
+}
+
+func (c staleCacheClient) List(
+	ctx context.Context,
+	list client.ObjectList,
+	opts ...client.ListOption,
+) error {
+	return nil
+}
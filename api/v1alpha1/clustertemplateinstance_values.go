@@ -0,0 +1,284 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/strvals"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	ValuesFromConfigMap = "ConfigMap"
+	ValuesFromSecret    = "Secret"
+
+	// DefaultValuesKey is the key of values in referenced ConfigMap or Secret unless set
+	DefaultValuesKey = "values.yaml"
+
+	// keys of the hub default credentials in their Secrets
+	DefaultPullSecretKey = corev1.DockerConfigJsonKey
+	DefaultSSHKeyKey     = "id_rsa.pub"
+	// TrustedCABundleKey is the key of the PEM encoded CA bundle in ConfigMaps
+	TrustedCABundleKey = "ca-bundle.crt"
+)
+
+// GetValuesFrom reads values of the cluster definition (empty clusterSetup) or of the cluster
+// setup from ConfigMaps and Secrets referenced by the instance. Values of later references
+// override the earlier ones. The cluster ID and name, names of the hub default credentials and
+// the trusted CA bundle, overridden by the references, and node pools composed by the instance
+// are added to the values of the cluster definition.
+func (i *ClusterTemplateInstance) GetValuesFrom(
+	ctx context.Context,
+	k8sClient client.Client,
+	clusterSetup string,
+) (chartutil.Values, error) {
+	values := chartutil.Values{}
+	if clusterSetup == "" {
+		// instances created before the cluster ID was introduced have none
+		if key := i.Status.ClusterTemplateSpec.ClusterIDValuesKey; key != "" &&
+			i.Status.ClusterID != "" {
+			values[key] = i.Status.ClusterID
+		}
+		if clusterName := i.Status.ClusterTemplateSpec.ClusterName; clusterName != nil &&
+			i.Status.ClusterName != "" {
+			values[clusterName.ValuesKey] = i.Status.ClusterName
+		}
+		if err := i.setDefaultCredentialsValues(values); err != nil {
+			return nil, err
+		}
+		if err := i.setTrustedCABundleValues(ctx, k8sClient, values); err != nil {
+			return nil, err
+		}
+	}
+	for _, ref := range i.Spec.ValuesFrom {
+		if ref.ClusterSetup != clusterSetup {
+			continue
+		}
+		data, err := i.getValuesReferenceData(ctx, k8sClient, ref)
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			continue
+		}
+		refValues, err := chartutil.ReadValues(data)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to parse values of %s %s - %q", ref.Kind, ref.Name, err,
+			)
+		}
+		values = chartutil.CoalesceTables(refValues, values)
+	}
+	if clusterSetup == "" {
+		if err := i.checkComputeValues(values); err != nil {
+			return nil, err
+		}
+		i.setNodePoolValues(values)
+	}
+	return values, nil
+}
+
+// setDefaultCredentialsValues sets names of the copies of the hub default credentials in values
+// of the cluster definition. The copies are created in the namespace the cluster definition is
+// deployed to, where the chart references them (ie by the pull secret of a HostedCluster). Their
+// content is not passed in values, which end up in the ArgoCD Application.
+func (i *ClusterTemplateInstance) setDefaultCredentialsValues(values chartutil.Values) error {
+	credentials := i.Status.ClusterTemplateSpec.DefaultCredentials
+	if credentials == nil {
+		return nil
+	}
+	for _, cred := range []struct {
+		valuesKey  string
+		secretName string
+	}{
+		{credentials.PullSecretValuesKey, i.GetDefaultPullSecretRef()},
+		{credentials.SSHKeyValuesKey, i.GetDefaultSSHKeyRef()},
+	} {
+		if cred.valuesKey == "" {
+			continue
+		}
+		if err := strvals.ParseIntoString(
+			fmt.Sprintf("%s=%s", cred.valuesKey, cred.secretName),
+			values,
+		); err != nil {
+			return fmt.Errorf(
+				"failed to set default credentials value '%s' - %q",
+				cred.valuesKey,
+				err,
+			)
+		}
+	}
+	return nil
+}
+
+// setTrustedCABundleValues sets the CA bundle trusted by the hub, copied into the namespace of
+// the instance, in values of the cluster definition. Nothing is set if the hub does not trust
+// additional CAs.
+func (i *ClusterTemplateInstance) setTrustedCABundleValues(
+	ctx context.Context,
+	k8sClient client.Client,
+	values chartutil.Values,
+) error {
+	trustedCABundle := i.Status.ClusterTemplateSpec.TrustedCABundle
+	if trustedCABundle == nil {
+		return nil
+	}
+	cm := &corev1.ConfigMap{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: i.GetTrustedCABundleRef(), Namespace: i.Namespace},
+		cm,
+	); err != nil {
+		return fmt.Errorf("failed to get trusted CA bundle %s - %q", i.GetTrustedCABundleRef(), err)
+	}
+	if bundle := cm.Data[TrustedCABundleKey]; bundle != "" {
+		values[trustedCABundle.ValuesKey] = bundle
+	}
+	return nil
+}
+
+// checkComputeValues rejects referenced values holding compute counted by quotas, quotas are
+// checked for parameters only
+func (i *ClusterTemplateInstance) checkComputeValues(values chartutil.Values) error {
+	compute := i.Status.ClusterTemplateSpec.Compute
+	if compute == nil {
+		return nil
+	}
+	for _, rule := range []*ComputeRule{compute.Nodes, compute.VCPUPerNode} {
+		if rule == nil || rule.Parameter == "" {
+			continue
+		}
+		if _, err := values.PathValue(rule.Parameter); err == nil {
+			return fmt.Errorf(
+				"value '%s' is counted by quotas, it has to be set by parameters",
+				rule.Parameter,
+			)
+		}
+	}
+	return nil
+}
+
+// getValuesReferenceData returns the values of the reference, nil if the optional reference
+// is missing
+func (i *ClusterTemplateInstance) getValuesReferenceData(
+	ctx context.Context,
+	k8sClient client.Client,
+	ref ValuesReference,
+) ([]byte, error) {
+	key := ref.ValuesKey
+	if key == "" {
+		key = DefaultValuesKey
+	}
+	objKey := client.ObjectKey{Name: ref.Name, Namespace: i.Namespace}
+
+	var data []byte
+	var found bool
+	var err error
+	switch ref.Kind {
+	case ValuesFromConfigMap:
+		cm := &corev1.ConfigMap{}
+		if err = k8sClient.Get(ctx, objKey, cm); err == nil {
+			var value string
+			if value, found = cm.Data[key]; found {
+				data = []byte(value)
+			} else {
+				data, found = cm.BinaryData[key]
+			}
+		}
+	case ValuesFromSecret:
+		secret := &corev1.Secret{}
+		if err = k8sClient.Get(ctx, objKey, secret); err == nil {
+			data, found = secret.Data[key]
+		}
+	default:
+		return nil, fmt.Errorf("unknown kind of values reference '%s'", ref.Kind)
+	}
+
+	if err != nil {
+		if apierrors.IsNotFound(err) && ref.Optional {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get values from %s %s - %q", ref.Kind, ref.Name, err)
+	}
+	if !found {
+		if ref.Optional {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s %s has no key '%s'", ref.Kind, ref.Name, key)
+	}
+	return data, nil
+}
+
+// getTemplateValues returns Helm values set by the template for the cluster definition (empty
+// clusterSetup) or for the cluster setup
+func (i *ClusterTemplateInstance) getTemplateValues(clusterSetup string) string {
+	ctSpec := i.Status.ClusterTemplateSpec
+	if clusterSetup == "" {
+		if ctSpec.ClusterDefinition.Source.Helm != nil {
+			return ctSpec.ClusterDefinition.Source.Helm.Values
+		}
+		return ""
+	}
+	for _, setup := range ctSpec.ClusterSetup {
+		if setup.Name == clusterSetup && setup.Spec.Source.Helm != nil {
+			return setup.Spec.Source.Helm.Values
+		}
+	}
+	return ""
+}
+
+// setHelmValues sets Helm values of the application to the template values overridden by the
+// values read from referenced ConfigMaps and Secrets
+func setHelmValues(
+	appSpec *argo.ApplicationSpec,
+	templateValues string,
+	valuesFrom chartutil.Values,
+) error {
+	values := templateValues
+	if len(valuesFrom) > 0 {
+		merged, err := overrideValues(templateValues, valuesFrom)
+		if err != nil {
+			return err
+		}
+		if values, err = merged.YAML(); err != nil {
+			return err
+		}
+	}
+	if appSpec.Source.Helm == nil && values == "" {
+		return nil
+	}
+	// copy, the helm source may be shared with the template spec stored in status
+	helm := appSpec.Source.Helm.DeepCopy()
+	if helm == nil {
+		helm = &argo.ApplicationSourceHelm{}
+	}
+	helm.Values = values
+	appSpec.Source.Helm = helm
+	return nil
+}
+
+// overrideValues returns values parsed from YAML overridden by the given values, which are
+// not modified
+func overrideValues(values string, override chartutil.Values) (chartutil.Values, error) {
+	base, err := chartutil.ReadValues([]byte(values))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template values - %q", err)
+	}
+	if len(override) == 0 {
+		return base, nil
+	}
+	// copy, coalescing modifies the overriding values
+	overrideYAML, err := override.YAML()
+	if err != nil {
+		return nil, err
+	}
+	merged, err := chartutil.ReadValues([]byte(overrideYAML))
+	if err != nil {
+		return nil, err
+	}
+	return chartutil.CoalesceTables(merged, base), nil
+}
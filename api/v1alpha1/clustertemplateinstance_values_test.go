@@ -0,0 +1,246 @@
+package v1alpha1
+
+import (
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClusterTemplateInstance valuesFrom", func() {
+	var cti ClusterTemplateInstance
+	var k8sClient client.Client
+
+	BeforeEach(func() {
+		cti = ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ValuesFrom: []ValuesReference{
+					{Kind: ValuesFromConfigMap, Name: "cluster"},
+					{Kind: ValuesFromSecret, Name: "credentials", ValuesKey: "creds.yaml"},
+					{Kind: ValuesFromSecret, Name: "setup", ClusterSetup: "day2"},
+				},
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					ClusterDefinition: argo.ApplicationSpec{
+						Source: argo.ApplicationSource{
+							Chart: "foo",
+							Helm: &argo.ApplicationSourceHelm{
+								Values: "nodeCount: 1\nplatform: aws\n",
+							},
+						},
+					},
+				},
+			},
+		}
+		k8sClient = fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", Namespace: "default"},
+				Data: map[string]string{
+					DefaultValuesKey: "nodeCount: 2\npullSecret: public\n",
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "default"},
+				Data: map[string][]byte{
+					"creds.yaml": []byte("pullSecret: secret\n"),
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "setup", Namespace: "default"},
+				Data: map[string][]byte{
+					DefaultValuesKey: []byte("token: foo\n"),
+				},
+			},
+		)
+	})
+
+	It("Merges referenced values", func() {
+		values, err := cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(values).Should(Equal(chartutil.Values{
+			"nodeCount":  float64(2),
+			"pullSecret": "secret",
+		}))
+
+		values, err = cti.GetValuesFrom(ctx, k8sClient, "day2")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(values).Should(Equal(chartutil.Values{"token": "foo"}))
+	})
+
+	It("Fails on missing references unless optional", func() {
+		cti.Spec.ValuesFrom = []ValuesReference{{Kind: ValuesFromSecret, Name: "missing"}}
+		_, err := cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).Should(HaveOccurred())
+
+		cti.Spec.ValuesFrom = []ValuesReference{
+			{Kind: ValuesFromConfigMap, Name: "cluster", ValuesKey: "missing"},
+		}
+		_, err = cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).Should(HaveOccurred())
+
+		cti.Spec.ValuesFrom = []ValuesReference{
+			{Kind: ValuesFromSecret, Name: "missing", Optional: true},
+			{Kind: ValuesFromConfigMap, Name: "cluster", ValuesKey: "missing", Optional: true},
+		}
+		values, err := cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(values).Should(BeEmpty())
+	})
+
+	It("Overrides template values and is overridden by parameters", func() {
+		cti.Spec.Parameters = []Parameter{{Name: "nodeCount", Value: "3"}}
+		valuesFrom, err := cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		values, err := cti.GetClusterDefinitionValues(valuesFrom)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(values).Should(Equal(chartutil.Values{
+			"nodeCount":  int64(3),
+			"platform":   "aws",
+			"pullSecret": "secret",
+		}))
+	})
+
+	It("Injects hub default credentials", func() {
+		cti.Status.ClusterTemplateSpec.DefaultCredentials = &DefaultCredentials{
+			PullSecretValuesKey: "pullSecret.name",
+			SSHKeyValuesKey:     "sshKey.name",
+		}
+		values, err := cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).ShouldNot(HaveOccurred())
+		// referenced values override the default credentials
+		Expect(values).Should(Equal(chartutil.Values{
+			"nodeCount":  float64(2),
+			"pullSecret": "secret",
+			"sshKey": map[string]interface{}{
+				"name": cti.GetDefaultSSHKeyRef(),
+			},
+		}))
+
+		// names of the copies are injected, not their content
+		cti.Spec.ValuesFrom = nil
+		values, err = cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(values).Should(Equal(chartutil.Values{
+			"pullSecret": map[string]interface{}{
+				"name": cti.GetDefaultPullSecretRef(),
+			},
+			"sshKey": map[string]interface{}{
+				"name": cti.GetDefaultSSHKeyRef(),
+			},
+		}))
+	})
+
+	It("Injects cluster ID", func() {
+		cti.Spec.ValuesFrom = nil
+		cti.Status.ClusterTemplateSpec.ClusterIDValuesKey = "clusterID"
+		// instance created before the cluster ID was introduced
+		values, err := cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(values).Should(BeEmpty())
+
+		cti.Status.ClusterID = "0b7b1f0c-5c4e-4c55-9d67-2b9f1e6c7a10"
+		values, err = cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(values).Should(Equal(chartutil.Values{
+			"clusterID": "0b7b1f0c-5c4e-4c55-9d67-2b9f1e6c7a10",
+		}))
+		// not passed to the cluster setup
+		values, err = cti.GetValuesFrom(ctx, k8sClient, "day2")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(values).Should(BeEmpty())
+	})
+
+	It("Injects generated cluster name", func() {
+		cti.Spec.ValuesFrom = nil
+		cti.Status.ClusterTemplateSpec.ClusterName = &ClusterName{ValuesKey: "clusterName"}
+		Expect(cti.GenerateClusterName(cti.Status.ClusterTemplateSpec)).Should(Equal(cti.Name))
+
+		cti.Status.ClusterTemplateSpec.ClusterName.GenerateSuffix = true
+		cti.Status.ClusterName = cti.GenerateClusterName(cti.Status.ClusterTemplateSpec)
+		Expect(cti.Status.ClusterName).Should(HavePrefix(cti.Name + "-"))
+		Expect(cti.Status.ClusterName).Should(HaveLen(len(cti.Name) + 6))
+		Expect(cti.GenerateClusterName(cti.Status.ClusterTemplateSpec)).
+			ShouldNot(Equal(cti.Status.ClusterName))
+
+		values, err := cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(values).Should(Equal(chartutil.Values{"clusterName": cti.Status.ClusterName}))
+		Expect(cti.GetInstanceLabels("")).Should(
+			HaveKeyWithValue(CTIClusterNameLabel, cti.Status.ClusterName),
+		)
+	})
+
+	It("Injects CA bundle trusted by the hub", func() {
+		cti.Spec.ValuesFrom = nil
+		cti.Status.ClusterTemplateSpec.TrustedCABundle = &TrustedCABundle{
+			ValuesKey: "additionalTrustBundle",
+		}
+		_, err := cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).Should(HaveOccurred())
+
+		bundle := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: cti.GetTrustedCABundleRef(), Namespace: "default"},
+			Data:       map[string]string{TrustedCABundleKey: ""},
+		}
+		Expect(k8sClient.Create(ctx, bundle)).Should(Succeed())
+		// the hub does not trust additional CAs
+		values, err := cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(values).Should(BeEmpty())
+
+		bundle.Data[TrustedCABundleKey] = "-----BEGIN CERTIFICATE-----"
+		Expect(k8sClient.Update(ctx, bundle)).Should(Succeed())
+		values, err = cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(values).Should(Equal(chartutil.Values{
+			"additionalTrustBundle": "-----BEGIN CERTIFICATE-----",
+		}))
+	})
+
+	It("Rejects values counted by quotas", func() {
+		cti.Status.ClusterTemplateSpec.Compute = &ClusterCompute{
+			Nodes: &ComputeRule{Parameter: "nodeCount"},
+		}
+		_, err := cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).Should(HaveOccurred())
+
+		cti.Status.ClusterTemplateSpec.Compute.Nodes.Parameter = "workers"
+		_, err = cti.GetValuesFrom(ctx, k8sClient, "")
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("Sets values of created and updated applications", func() {
+		Expect(cti.CreateDay1Application(ctx, k8sClient, "argocd")).Should(Succeed())
+		app, err := cti.GetDay1Application(ctx, k8sClient, "argocd")
+		Expect(err).ShouldNot(HaveOccurred())
+		values, err := chartutil.ReadValues([]byte(app.Spec.Source.Helm.Values))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(values).Should(Equal(chartutil.Values{
+			"nodeCount":  float64(2),
+			"platform":   "aws",
+			"pullSecret": "secret",
+		}))
+		// template values stored in status are not modified
+		Expect(cti.Status.ClusterTemplateSpec.ClusterDefinition.Source.Helm.Values).Should(
+			Equal("nodeCount: 1\nplatform: aws\n"),
+		)
+
+		cti.Spec.ValuesFrom = nil
+		Expect(cti.UpdateApplicationsParameters(ctx, k8sClient, "argocd")).Should(Succeed())
+		app, err = cti.GetDay1Application(ctx, k8sClient, "argocd")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(app.Spec.Source.Helm.Values).Should(Equal("nodeCount: 1\nplatform: aws\n"))
+	})
+})
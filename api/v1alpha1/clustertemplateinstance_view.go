@@ -0,0 +1,33 @@
+package v1alpha1
+
+// GetViewName returns name of the ClusterTemplateInstanceView of the instance. Views of instances
+// of all namespaces live in one namespace, so the namespace is part of the name.
+func (i *ClusterTemplateInstance) GetViewName() string {
+	return truncateName(i.Namespace + "-" + i.Name)
+}
+
+// GetViewStatus returns the status of the instance shared by its ClusterTemplateInstanceView.
+// Messages are left out, they can contain values of the instance.
+func (i *ClusterTemplateInstance) GetViewStatus() ClusterTemplateInstanceViewStatus {
+	status := ClusterTemplateInstanceViewStatus{
+		Instance: InstanceReference{
+			Name:      i.Name,
+			Namespace: i.Namespace,
+		},
+		ClusterTemplate:  i.Spec.ClusterTemplateRef,
+		Owner:            i.Annotations[CTIRequesterAnnotation],
+		Phase:            i.Status.Phase,
+		APIserverURL:     i.Status.APIserverURL,
+		ConsoleURL:       i.Status.ConsoleURL,
+		OpenShiftVersion: i.Status.OpenShiftVersion,
+	}
+	if !i.CreationTimestamp.IsZero() {
+		created := i.CreationTimestamp
+		status.CreationTime = &created
+	}
+	if ctSpec := i.Status.ClusterTemplateSpec; ctSpec != nil &&
+		ctSpec.ClusterDefinition.Source.Chart != "" {
+		status.ChartVersion = ctSpec.ClusterDefinition.Source.TargetRevision
+	}
+	return status
+}
@@ -0,0 +1,50 @@
+package v1alpha1
+
+import (
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ClusterTemplateInstance view", func() {
+	It("GetViewStatus", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "foo",
+				Namespace:   "default",
+				Annotations: map[string]string{CTIRequesterAnnotation: "alice"},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "aws-small",
+			},
+			Status: ClusterTemplateInstanceStatus{
+				Phase:            ReadyPhase,
+				Message:          "Cluster is ready",
+				APIserverURL:     "https://api.foo.example.com:6443",
+				ConsoleURL:       "https://console-openshift-console.apps.foo.example.com",
+				OpenShiftVersion: "4.12.1",
+			},
+		}
+		Expect(cti.GetViewName()).Should(Equal("default-foo"))
+		Expect(cti.GetViewStatus()).Should(Equal(ClusterTemplateInstanceViewStatus{
+			Instance:         InstanceReference{Name: "foo", Namespace: "default"},
+			ClusterTemplate:  "aws-small",
+			Owner:            "alice",
+			Phase:            ReadyPhase,
+			APIserverURL:     "https://api.foo.example.com:6443",
+			ConsoleURL:       "https://console-openshift-console.apps.foo.example.com",
+			OpenShiftVersion: "4.12.1",
+		}))
+
+		cti.Status.ClusterTemplateSpec = &ClusterTemplateSpec{
+			ClusterDefinition: argo.ApplicationSpec{
+				Source: argo.ApplicationSource{
+					Chart:          "hypershift-template",
+					TargetRevision: "0.0.2",
+				},
+			},
+		}
+		Expect(cti.GetViewStatus().ChartVersion).Should(Equal("0.0.2"))
+	})
+})
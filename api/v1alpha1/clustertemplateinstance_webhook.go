@@ -72,6 +72,11 @@ var _ webhook.Validator = &ClusterTemplateInstance{}
 func (r *ClusterTemplateInstance) ValidateCreate() error {
 	clustertemplateinstancelog.Info("validate create", "name", r.Name)
 
+	if r.Spec.Upgrade != nil {
+		if err := r.Spec.Upgrade.Validate(); err != nil {
+			return err
+		}
+	}
 	if err := r.checkQuota(); err != nil {
 		return err
 	}
@@ -95,11 +100,48 @@ func (r *ClusterTemplateInstance) checkProps() error {
 		return fmt.Errorf("failed to get cluster template - %q", err)
 	}
 
+	for name, enabled := range r.Spec.AddOns {
+		if enabled && template.Spec.GetAddOn(name) == nil {
+			return fmt.Errorf("add-on '%s' is not defined by cluster template '%s'", name, template.Name)
+		}
+	}
+
+	if err := template.Spec.ValidateNodePools(r.Spec.NodePools); err != nil {
+		return err
+	}
+	if err := template.Spec.ValidateNodePoolReplicas(r.Spec.NodePoolReplicas); err != nil {
+		return err
+	}
+	if err := template.Spec.ValidateNodePoolAutoscaling(
+		r.Spec.NodePoolAutoscaling,
+		r.Spec.NodePoolReplicas,
+	); err != nil {
+		return err
+	}
+
+	if r.Spec.Hibernate {
+		if err := template.Spec.validateHibernation(); err != nil {
+			return err
+		}
+	}
+
 	// TODO check values
 	return nil
 
 }
 
+// validateHibernation checks the clusters of the template can be hibernated, clusters created
+// through OCM or claimed from a cluster pool are not hibernated by the operator
+func (s *ClusterTemplateSpec) validateHibernation() error {
+	switch {
+	case s.OCM != nil:
+		return fmt.Errorf("clusters created through OCM can not be hibernated")
+	case s.ClusterPool != nil:
+		return fmt.Errorf("clusters claimed from a cluster pool can not be hibernated")
+	}
+	return nil
+}
+
 func (r *ClusterTemplateInstance) checkQuota() error {
 	quotas := ClusterTemplateQuotaList{}
 	opts := []client.ListOption{
@@ -133,15 +175,42 @@ func (r *ClusterTemplateInstance) checkQuota() error {
 		return fmt.Errorf("cluster template does not exist")
 	}
 
+	nodes, vcpu, err := r.GetRequestedCompute(templates.Items[templateIdx].Spec)
+	if err != nil {
+		return fmt.Errorf("failed quota: could not compute requested resources - %q", err)
+	}
+
+	sizeClass, err := r.checkSizeClass(templates.Items[templateIdx].Spec, nodes, vcpu)
+	if err != nil {
+		return err
+	}
+	cost := templates.Items[templateIdx].Spec.Cost * sizeClass.GetWeight()
+
 	templateAllowed := false
 	for _, quota := range quotas.Items {
+		if err := quota.checkSizeClass(r.Spec.SizeClass); err != nil {
+			return err
+		}
+
 		if quota.Spec.Budget > 0 &&
-			quota.Spec.Budget < quota.Status.BudgetSpent+templates.Items[templateIdx].Spec.Cost {
+			quota.Spec.Budget < quota.Status.BudgetSpent+cost {
 			return fmt.Errorf(
 				"failed quota: cluster instance not allowed - cluster cost would exceed budget",
 			)
 		}
 
+		if quota.Spec.MaxNodes > 0 && quota.Spec.MaxNodes < quota.Status.NodesSpent+nodes {
+			return fmt.Errorf(
+				"failed quota: cluster instance not allowed - worker nodes would exceed quota",
+			)
+		}
+
+		if quota.Spec.MaxVCPU > 0 && quota.Spec.MaxVCPU < quota.Status.VCPUSpent+vcpu {
+			return fmt.Errorf(
+				"failed quota: cluster instance not allowed - worker vCPUs would exceed quota",
+			)
+		}
+
 		maxAllowed := 0
 		for _, tempInstance := range quota.Spec.AllowedTemplates {
 			if tempInstance.Name == r.Spec.ClusterTemplateRef {
@@ -172,6 +241,141 @@ func (r *ClusterTemplateInstance) checkQuota() error {
 	return nil
 }
 
+// checkSizeClass checks the instance selects a size class of the template and the requested
+// worker nodes and vCPUs fit the limits of the size class. Returns nil size class if the instance
+// does not select any.
+func (r *ClusterTemplateInstance) checkSizeClass(
+	ctSpec ClusterTemplateSpec,
+	nodes int,
+	vcpu int,
+) (*ClusterSizeClass, error) {
+	if r.Spec.SizeClass == "" {
+		if len(ctSpec.SizeClasses) > 0 {
+			return nil, fmt.Errorf(
+				"cluster template '%s' requires one of size classes %v",
+				r.Spec.ClusterTemplateRef,
+				ctSpec.SizeClasses,
+			)
+		}
+		return nil, nil
+	}
+	allowed := len(ctSpec.SizeClasses) == 0
+	for _, name := range ctSpec.SizeClasses {
+		if name == r.Spec.SizeClass {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return nil, fmt.Errorf(
+			"size class '%s' is not allowed by cluster template '%s', allowed values are %v",
+			r.Spec.SizeClass,
+			r.Spec.ClusterTemplateRef,
+			ctSpec.SizeClasses,
+		)
+	}
+	sizeClass := &ClusterSizeClass{}
+	if err := instanceControllerClient.Get(
+		context.TODO(),
+		client.ObjectKey{Name: r.Spec.SizeClass},
+		sizeClass,
+	); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("cluster size class '%s' not found", r.Spec.SizeClass)
+		}
+		return nil, fmt.Errorf("failed to get cluster size class - %q", err)
+	}
+	if err := sizeClass.Validate(nodes, vcpu); err != nil {
+		return nil, err
+	}
+	return sizeClass, nil
+}
+
+// getUpdatedTemplateSpec returns spec of the template the updated instance was created from, or
+// the current spec of the template if the instance was not reconciled yet
+func getUpdatedTemplateSpec(oldCti *ClusterTemplateInstance) (*ClusterTemplateSpec, error) {
+	if oldCti.Status.ClusterTemplateSpec != nil {
+		return oldCti.Status.ClusterTemplateSpec, nil
+	}
+	template := ClusterTemplate{}
+	if err := instanceControllerClient.Get(
+		context.TODO(),
+		client.ObjectKey{Name: oldCti.Spec.ClusterTemplateRef},
+		&template,
+	); err != nil {
+		return nil, err
+	}
+	return &template.Spec, nil
+}
+
+// checkSizeClassUpdate checks the worker nodes and vCPUs requested by the updated parameters,
+// node pools, node pool replicas and autoscaling still fit the size class of the instance
+func (r *ClusterTemplateInstance) checkSizeClassUpdate(oldCti *ClusterTemplateInstance) error {
+	if r.Spec.SizeClass == "" {
+		return nil
+	}
+	ctSpec, err := getUpdatedTemplateSpec(oldCti)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster template - %q", err)
+	}
+	nodes, vcpu, err := r.GetRequestedCompute(*ctSpec)
+	if err != nil {
+		return fmt.Errorf("could not compute requested resources - %q", err)
+	}
+	_, err = r.checkSizeClass(*ctSpec, nodes, vcpu)
+	return err
+}
+
+// checkQuotaUpdate checks the worker nodes and vCPUs added by the updated parameters, node pools,
+// node pool replicas and autoscaling fit the quotas of the namespace. The quotas already account for the compute requested
+// before the update, so only the difference is checked.
+func (r *ClusterTemplateInstance) checkQuotaUpdate(oldCti *ClusterTemplateInstance) error {
+	ctSpec, err := getUpdatedTemplateSpec(oldCti)
+	if err != nil {
+		// instances of missing templates are not accounted in quotas
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get cluster template - %q", err)
+	}
+	oldNodes, oldVCPU, err := oldCti.GetRequestedCompute(*ctSpec)
+	if err != nil {
+		return fmt.Errorf("failed quota: could not compute requested resources - %q", err)
+	}
+	nodes, vcpu, err := r.GetRequestedCompute(*ctSpec)
+	if err != nil {
+		return fmt.Errorf("failed quota: could not compute requested resources - %q", err)
+	}
+	addedNodes := nodes - oldNodes
+	addedVCPU := vcpu - oldVCPU
+	if addedNodes <= 0 && addedVCPU <= 0 {
+		return nil
+	}
+
+	quotas := ClusterTemplateQuotaList{}
+	if err := instanceControllerClient.List(
+		context.TODO(),
+		&quotas,
+		client.InNamespace(r.Namespace),
+	); err != nil {
+		return fmt.Errorf("could not list cluster template quotas - %q", err)
+	}
+	for _, quota := range quotas.Items {
+		if addedNodes > 0 && quota.Spec.MaxNodes > 0 &&
+			quota.Spec.MaxNodes < quota.Status.NodesSpent+addedNodes {
+			return fmt.Errorf(
+				"failed quota: cluster instance update not allowed - worker nodes would exceed quota",
+			)
+		}
+		if addedVCPU > 0 && quota.Spec.MaxVCPU > 0 &&
+			quota.Spec.MaxVCPU < quota.Status.VCPUSpent+addedVCPU {
+			return fmt.Errorf(
+				"failed quota: cluster instance update not allowed - worker vCPUs would exceed quota",
+			)
+		}
+	}
+	return nil
+}
+
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ClusterTemplateInstance) ValidateUpdate(old runtime.Object) error {
 	clustertemplateinstancelog.Info("validate update", "name", r.Name)
@@ -180,7 +384,77 @@ func (r *ClusterTemplateInstance) ValidateUpdate(old runtime.Object) error {
 	if oldCti.Annotations[CTIRequesterAnnotation] != r.Annotations[CTIRequesterAnnotation] {
 		return fmt.Errorf("cluster requester cannot be changed")
 	}
-	if !equality.Semantic.DeepEqual(r.Spec, oldCti.Spec) {
+	// parameters and values can be changed, changes are propagated to the applications
+	newSpec := r.Spec.DeepCopy()
+	newSpec.Parameters = oldCti.Spec.Parameters
+	newSpec.ValuesFrom = oldCti.Spec.ValuesFrom
+	// node pools can be scaled and added within the limits of the template the instance was
+	// created from
+	newSpec.NodePools = oldCti.Spec.NodePools
+	if !equality.Semantic.DeepEqual(r.Spec.NodePools, oldCti.Spec.NodePools) {
+		if ctSpec := oldCti.Status.ClusterTemplateSpec; ctSpec != nil {
+			if err := ctSpec.ValidateNodePools(r.Spec.NodePools); err != nil {
+				return err
+			}
+		} else if err := r.checkProps(); err != nil {
+			return err
+		}
+	}
+	// upgrade of the installed cluster can be requested anytime
+	newSpec.Upgrade = oldCti.Spec.Upgrade
+	if r.Spec.Upgrade != nil {
+		if err := r.Spec.Upgrade.Validate(); err != nil {
+			return err
+		}
+	}
+	// node pools of the installed cluster can be scaled and autoscaled anytime
+	newSpec.NodePoolReplicas = oldCti.Spec.NodePoolReplicas
+	newSpec.NodePoolAutoscaling = oldCti.Spec.NodePoolAutoscaling
+	// the installed cluster can be hibernated and resumed anytime
+	newSpec.Hibernate = oldCti.Spec.Hibernate
+	if r.Spec.Hibernate && !oldCti.Spec.Hibernate {
+		if ctSpec := oldCti.Status.ClusterTemplateSpec; ctSpec != nil {
+			if err := ctSpec.validateHibernation(); err != nil {
+				return err
+			}
+		} else if err := r.checkProps(); err != nil {
+			return err
+		}
+	}
+	if !equality.Semantic.DeepEqual(r.Spec.NodePoolReplicas, oldCti.Spec.NodePoolReplicas) ||
+		!equality.Semantic.DeepEqual(r.Spec.NodePoolAutoscaling, oldCti.Spec.NodePoolAutoscaling) {
+		if ctSpec := oldCti.Status.ClusterTemplateSpec; ctSpec != nil {
+			if err := ctSpec.ValidateNodePoolReplicas(r.Spec.NodePoolReplicas); err != nil {
+				return err
+			}
+			if err := ctSpec.ValidateNodePoolAutoscaling(
+				r.Spec.NodePoolAutoscaling,
+				r.Spec.NodePoolReplicas,
+			); err != nil {
+				return err
+			}
+		} else if err := r.checkProps(); err != nil {
+			return err
+		}
+	}
+	// worker nodes and vCPUs requested by the updated parameters, node pools and their scaling
+	// have to fit the size class and the quotas
+	if !equality.Semantic.DeepEqual(r.Spec.Parameters, oldCti.Spec.Parameters) ||
+		!equality.Semantic.DeepEqual(r.Spec.NodePools, oldCti.Spec.NodePools) ||
+		!equality.Semantic.DeepEqual(r.Spec.NodePoolReplicas, oldCti.Spec.NodePoolReplicas) ||
+		!equality.Semantic.DeepEqual(r.Spec.NodePoolAutoscaling, oldCti.Spec.NodePoolAutoscaling) {
+		if err := r.checkSizeClassUpdate(oldCti); err != nil {
+			return err
+		}
+		if err := r.checkQuotaUpdate(oldCti); err != nil {
+			return err
+		}
+	}
+	// previewed instance is installed by turning the preview off
+	if oldCti.Spec.Preview {
+		newSpec.Preview = oldCti.Spec.Preview
+	}
+	if !equality.Semantic.DeepEqual(*newSpec, oldCti.Spec) {
 		return fmt.Errorf("spec is immutable")
 	}
 	return nil
@@ -161,6 +161,118 @@ var _ = Describe("ClusterTemplateInstance validating webhook", func() {
 			err.Error(),
 		).Should(Equal("failed quota: cluster instance not allowed - maximum cluster instances reached"))
 	})
+	It("Fails when worker nodes would exceed quota", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ctq := &ClusterTemplateQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				AllowedTemplates: []AllowedTemplate{
+					{
+						Name: "foo-tmp",
+					},
+				},
+				MaxNodes: 5,
+			},
+			Status: ClusterTemplateQuotaStatus{
+				NodesSpent: 3,
+			},
+		}
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				Compute: &ClusterCompute{
+					Nodes: &ComputeRule{
+						Parameter: "nodeCount",
+						Default:   2,
+					},
+				},
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				Parameters: []Parameter{
+					{
+						Name:  "nodeCount",
+						Value: "3",
+					},
+				},
+			},
+		}
+		err = cti.ValidateCreate()
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("failed quota: cluster instance not allowed - worker nodes would exceed quota"))
+
+		cti.Spec.Parameters = []Parameter{}
+		err = cti.ValidateCreate()
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+	It("Fails when worker vCPUs would exceed quota", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ctq := &ClusterTemplateQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				AllowedTemplates: []AllowedTemplate{
+					{
+						Name: "foo-tmp",
+					},
+				},
+				MaxVCPU: 16,
+			},
+			Status: ClusterTemplateQuotaStatus{
+				VCPUSpent: 8,
+			},
+		}
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				Compute: &ClusterCompute{
+					Nodes: &ComputeRule{
+						Default: 3,
+					},
+					VCPUPerNode: &ComputeRule{
+						Default: 4,
+					},
+				},
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+		err = cti.ValidateCreate()
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("failed quota: cluster instance not allowed - worker vCPUs would exceed quota"))
+	})
 	It("Passes when ctq allows template", func() {
 		scheme := runtime.NewScheme()
 		err := AddToScheme(scheme)
@@ -197,6 +309,240 @@ var _ = Describe("ClusterTemplateInstance validating webhook", func() {
 		Expect(err).ShouldNot(HaveOccurred())
 	})
 
+	It("Validates size class of the instance", func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).Should(Succeed())
+		ctq := &ClusterTemplateQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				AllowedTemplates:   []AllowedTemplate{{Name: "foo-tmp"}},
+				AllowedSizeClasses: []string{"small", "medium"},
+				Budget:             10,
+			},
+			Status: ClusterTemplateQuotaStatus{
+				BudgetSpent: 4,
+			},
+		}
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				Cost:        2,
+				SizeClasses: []string{"small", "medium", "large"},
+				Compute: &ClusterCompute{
+					Nodes: &ComputeRule{
+						Parameter: "nodeCount",
+						Default:   3,
+					},
+				},
+			},
+		}
+		small := &ClusterSizeClass{
+			ObjectMeta: v1.ObjectMeta{Name: "small"},
+			Spec:       ClusterSizeClassSpec{MaxNodes: 3, Weight: 1},
+		}
+		medium := &ClusterSizeClass{
+			ObjectMeta: v1.ObjectMeta{Name: "medium"},
+			Spec:       ClusterSizeClassSpec{MaxNodes: 6, Weight: 4},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq, ct, small, medium)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+		Expect(cti.ValidateCreate()).Should(MatchError(
+			"cluster template 'foo-tmp' requires one of size classes [small medium large]",
+		))
+
+		cti.Spec.SizeClass = "xlarge"
+		Expect(cti.ValidateCreate()).Should(MatchError(
+			"size class 'xlarge' is not allowed by cluster template 'foo-tmp', allowed values are [small medium large]",
+		))
+
+		cti.Spec.SizeClass = "large"
+		Expect(cti.ValidateCreate()).Should(MatchError("cluster size class 'large' not found"))
+
+		cti.Spec.SizeClass = "small"
+		Expect(cti.ValidateCreate()).Should(Succeed())
+
+		cti.Spec.Parameters = []Parameter{{Name: "nodeCount", Value: "4"}}
+		Expect(cti.ValidateCreate()).Should(MatchError(
+			"4 worker nodes exceed 3 nodes allowed by size class 'small'",
+		))
+
+		// cost of the template is multiplied by the weight of the size class
+		cti.Spec.SizeClass = "medium"
+		Expect(cti.ValidateCreate()).Should(MatchError(
+			"failed quota: cluster instance not allowed - cluster cost would exceed budget",
+		))
+
+		ct.Spec.SizeClasses = nil
+		Expect(instanceControllerClient.Update(context.TODO(), ct)).Should(Succeed())
+		cti.Spec.SizeClass = ""
+		Expect(cti.ValidateCreate()).Should(MatchError(
+			"failed quota: quota requires one of size classes [small medium]",
+		))
+	})
+	It("Validates size class when updating parameters", func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).Should(Succeed())
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, &ClusterSizeClass{
+			ObjectMeta: v1.ObjectMeta{Name: "small"},
+			Spec:       ClusterSizeClassSpec{MaxNodes: 3},
+		})
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				SizeClass:          "small",
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					Compute: &ClusterCompute{
+						Nodes: &ComputeRule{Parameter: "nodeCount", Default: 2},
+					},
+				},
+			},
+		}
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.Parameters = []Parameter{{Name: "nodeCount", Value: "3"}}
+		Expect(newCti.ValidateUpdate(&cti)).Should(Succeed())
+
+		newCti.Spec.Parameters[0].Value = "5"
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"5 worker nodes exceed 3 nodes allowed by size class 'small'",
+		))
+
+		newCti = cti.DeepCopy()
+		newCti.Spec.SizeClass = "large"
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError("spec is immutable"))
+	})
+
+	It("Fails when updated parameters would exceed quota", func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).Should(Succeed())
+		ctq := &ClusterTemplateQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				AllowedTemplates: []AllowedTemplate{{Name: "foo-tmp"}},
+				MaxNodes:         5,
+				MaxVCPU:          20,
+			},
+			Status: ClusterTemplateQuotaStatus{
+				NodesSpent: 3,
+				VCPUSpent:  12,
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				Parameters:         []Parameter{{Name: "nodeCount", Value: "1"}},
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					Compute: &ClusterCompute{
+						Nodes:       &ComputeRule{Parameter: "nodeCount", Default: 2},
+						VCPUPerNode: &ComputeRule{Parameter: "vcpu", Default: 4},
+					},
+				},
+			},
+		}
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.Parameters[0].Value = "3"
+		Expect(newCti.ValidateUpdate(&cti)).Should(Succeed())
+
+		newCti.Spec.Parameters[0].Value = "100"
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"failed quota: cluster instance update not allowed - worker nodes would exceed quota",
+		))
+
+		newCti = cti.DeepCopy()
+		newCti.Spec.Parameters = append(newCti.Spec.Parameters, Parameter{Name: "vcpu", Value: "16"})
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"failed quota: cluster instance update not allowed - worker vCPUs would exceed quota",
+		))
+
+		// scaling down is always allowed
+		ctq.Status.NodesSpent = 10
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq)
+		newCti = cti.DeepCopy()
+		newCti.Spec.Parameters[0].Value = "0"
+		Expect(newCti.ValidateUpdate(&cti)).Should(Succeed())
+	})
+
+	It("Fails when enabling add-on not defined by template", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ctq := &ClusterTemplateQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				AllowedTemplates: []AllowedTemplate{
+					{
+						Name: "foo-tmp",
+					},
+				},
+			},
+		}
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				AddOns: []AddOn{
+					{
+						Name: "logging",
+					},
+				},
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				AddOns: map[string]bool{
+					"logging": true,
+					"gpu":     false,
+				},
+			},
+		}
+		Expect(cti.ValidateCreate()).Should(Succeed())
+
+		cti.Spec.AddOns["gpu"] = true
+		err = cti.ValidateCreate()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("add-on 'gpu' is not defined by cluster template 'foo-tmp'"))
+	})
+
 	It("Fails when updating requester", func() {
 		cti := ClusterTemplateInstance{
 			ObjectMeta: v1.ObjectMeta{
@@ -264,6 +610,349 @@ var _ = Describe("ClusterTemplateInstance validating webhook", func() {
 			err.Error(),
 		).Should(Equal("spec is immutable"))
 	})
+	It("Succeeds when updating parameters", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				Parameters: []Parameter{
+					{
+						Name:  "foo",
+						Value: "bar",
+					},
+				},
+			},
+		}
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.Parameters[0].Value = "baz"
+
+		err := cti.ValidateUpdate(newCti)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+	It("Succeeds when updating values references", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.ValuesFrom = []ValuesReference{{Kind: ValuesFromSecret, Name: "creds"}}
+
+		err := cti.ValidateUpdate(newCti)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+	It("Succeeds when turning preview off", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+
+		oldCti := cti.DeepCopy()
+		oldCti.Spec.Preview = true
+
+		Expect(cti.ValidateUpdate(oldCti)).ShouldNot(HaveOccurred())
+		Expect(oldCti.ValidateUpdate(&cti)).Should(HaveOccurred())
+	})
+	It("Validates node pools against template limits", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				NodePools:          []NodePool{{Name: "workers", Replicas: 2}},
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					NodePools: &NodePoolOptions{MaxReplicas: 3},
+				},
+			},
+		}
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.NodePools[0].Replicas = 3
+		Expect(newCti.ValidateUpdate(&cti)).Should(Succeed())
+
+		newCti.Spec.NodePools[0].Replicas = 4
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"node pool 'workers' requests 4 replicas, cluster template allows at most 3",
+		))
+	})
+	It("Validates scaling of node pools", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					NodePools: &NodePoolOptions{MaxReplicas: 3},
+				},
+			},
+		}
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.NodePoolReplicas = map[string]int32{"workers": 3}
+		Expect(newCti.ValidateUpdate(&cti)).Should(Succeed())
+
+		newCti.Spec.NodePoolReplicas["workers"] = 4
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"node pool 'workers' requests 4 replicas, cluster template allows at most 3",
+		))
+
+		newCti.Spec.NodePoolReplicas["workers"] = -1
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"replicas of node pool 'workers' can not be negative",
+		))
+	})
+	It("Fails when scaling node pools of clusters without node pools", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					OCM: &OCMCluster{},
+				},
+			},
+		}
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.NodePoolReplicas = map[string]int32{"workers": 3}
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"node pools of clusters created through OCM can not be scaled",
+		))
+
+		cti.Status.ClusterTemplateSpec = &ClusterTemplateSpec{ClusterPool: &ClusterPoolRef{}}
+		newCti = cti.DeepCopy()
+		newCti.Spec.NodePoolAutoscaling = map[string]NodePoolAutoscaling{
+			"workers": {Min: 1, Max: 3},
+		}
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"node pools of clusters claimed from a cluster pool can not be scaled",
+		))
+	})
+	It("Fails when hibernating clusters without node pools", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					ClusterPool: &ClusterPoolRef{},
+				},
+			},
+		}
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.Hibernate = true
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"clusters claimed from a cluster pool can not be hibernated",
+		))
+
+		// hibernated clusters can always be resumed
+		Expect(cti.ValidateUpdate(newCti)).Should(Succeed())
+	})
+	It("Fails when scaling node pools would exceed quota", func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).Should(Succeed())
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, &ClusterTemplateQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				AllowedTemplates: []AllowedTemplate{{Name: "foo-tmp"}},
+				MaxNodes:         5,
+			},
+			Status: ClusterTemplateQuotaStatus{
+				NodesSpent: 2,
+			},
+		})
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					Compute: &ClusterCompute{
+						Nodes: &ComputeRule{Parameter: "nodeCount", Default: 2},
+					},
+				},
+			},
+		}
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.NodePoolReplicas = map[string]int32{"workers": 5}
+		Expect(newCti.ValidateUpdate(&cti)).Should(Succeed())
+
+		newCti.Spec.NodePoolReplicas["workers"] = 100
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"failed quota: cluster instance update not allowed - worker nodes would exceed quota",
+		))
+	})
+	It("Fails when autoscaling node pools would exceed quota", func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).Should(Succeed())
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, &ClusterTemplateQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				AllowedTemplates: []AllowedTemplate{{Name: "foo-tmp"}},
+				MaxNodes:         5,
+			},
+			Status: ClusterTemplateQuotaStatus{
+				NodesSpent: 2,
+			},
+		})
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					Compute: &ClusterCompute{
+						Nodes: &ComputeRule{Parameter: "nodeCount", Default: 2},
+					},
+				},
+			},
+		}
+
+		// autoscaled node pools are accounted with their maximum
+		newCti := cti.DeepCopy()
+		newCti.Spec.NodePoolAutoscaling = map[string]NodePoolAutoscaling{
+			"workers": {Min: 1, Max: 5},
+		}
+		Expect(newCti.ValidateUpdate(&cti)).Should(Succeed())
+
+		newCti.Spec.NodePoolAutoscaling["workers"] = NodePoolAutoscaling{Min: 1, Max: 6}
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"failed quota: cluster instance update not allowed - worker nodes would exceed quota",
+		))
+	})
+	It("Validates autoscaling of node pools", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				NodePoolReplicas:   map[string]int32{"infra": 2},
+			},
+			Status: ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &ClusterTemplateSpec{
+					NodePools: &NodePoolOptions{MaxReplicas: 5},
+				},
+			},
+		}
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.NodePoolAutoscaling = map[string]NodePoolAutoscaling{
+			"workers": {Min: 2, Max: 5},
+		}
+		Expect(newCti.ValidateUpdate(&cti)).Should(Succeed())
+
+		newCti.Spec.NodePoolAutoscaling["workers"] = NodePoolAutoscaling{Min: 2, Max: 6}
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"node pool 'workers' autoscales up to 6 replicas, cluster template allows at most 5",
+		))
+
+		newCti.Spec.NodePoolAutoscaling["workers"] = NodePoolAutoscaling{Min: 3, Max: 2}
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"autoscaling of node pool 'workers' requires 1 <= min <= max, got min 3 and max 2",
+		))
+
+		newCti.Spec.NodePoolAutoscaling = map[string]NodePoolAutoscaling{
+			"infra": {Min: 1, Max: 3},
+		}
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"node pool 'infra' can not be both scaled and autoscaled",
+		))
+	})
+	It("Succeeds when requesting upgrade", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.Upgrade = &ClusterUpgrade{
+			ReleaseImage: "quay.io/openshift-release-dev/ocp-release:4.12.1-x86_64",
+		}
+
+		err := cti.ValidateUpdate(newCti)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+	It("Validates requested upgrade", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.Upgrade = &ClusterUpgrade{Version: "4.12.1", Architecture: "aarch64"}
+		Expect(newCti.ValidateUpdate(&cti)).Should(Succeed())
+		Expect(newCti.Spec.Upgrade.GetReleaseImage()).Should(Equal(
+			"quay.io/openshift-release-dev/ocp-release:4.12.1-aarch64",
+		))
+
+		newCti.Spec.Upgrade.ReleaseImage = "quay.io/openshift-release-dev/ocp-release:4.12.1-x86_64"
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"upgrade has to set exactly one of releaseImage and version",
+		))
+
+		newCti.Spec.Upgrade = &ClusterUpgrade{}
+		Expect(newCti.ValidateUpdate(&cti)).Should(MatchError(
+			"upgrade has to set exactly one of releaseImage and version",
+		))
+	})
 	It("Succeeds when updating annotations", func() {
 		cti := ClusterTemplateInstance{
 			ObjectMeta: v1.ObjectMeta{
@@ -0,0 +1,86 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ClusterTemplateInstanceCleanupSpec struct {
+	// Selects ClusterTemplateInstances (in all namespaces) which should be deleted, can not be empty
+	Selector metav1.LabelSelector `json:"selector"`
+	// Only ClusterTemplateInstances older than this duration are deleted
+	OlderThan metav1.Duration `json:"olderThan"`
+	// +optional
+	// If true, matching ClusterTemplateInstances are only reported in status and not deleted
+	DryRun bool `json:"dryRun,omitempty"`
+	//+kubebuilder:validation:Minimum=1
+	//+kubebuilder:default=5
+	// +optional
+	// How many ClusterTemplateInstances are being deleted at once. Next instance is deleted once one of them is gone.
+	BatchSize int `json:"batchSize,omitempty"`
+}
+
+type InstanceReference struct {
+	// Name of the ClusterTemplateInstance
+	Name string `json:"name"`
+	// Namespace of the ClusterTemplateInstance
+	Namespace string `json:"namespace"`
+}
+
+// ClusterTemplateInstanceCleanupStatus defines the observed state of ClusterTemplateInstanceCleanup
+type ClusterTemplateInstanceCleanupStatus struct {
+	// ClusterTemplateInstances matching the selector and age when the cleanup started
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	MatchedInstances []InstanceReference `json:"matchedInstances,omitempty"`
+	// How many ClusterTemplateInstances were deleted
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	DeletedInstances int `json:"deletedInstances,omitempty"`
+	// Time when the cleanup started, the age of the instances is compared to this time
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Time when the cleanup finished
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=clustertemplateinstancecleanups,shortName=ctic;ctics,scope=Cluster
+//+operator-sdk:csv:customresourcedefinitions:displayName="Cluster template instance cleanup",resources={{ClusterTemplateInstance, v1alpha1, ""}}
+
+// Deletes ClusterTemplateInstances matching a label selector which are older than given age
+type ClusterTemplateInstanceCleanup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterTemplateInstanceCleanupSpec   `json:"spec,omitempty"`
+	Status ClusterTemplateInstanceCleanupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterTemplateInstanceCleanupList contains a list of ClusterTemplateInstanceCleanup
+type ClusterTemplateInstanceCleanupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterTemplateInstanceCleanup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterTemplateInstanceCleanup{}, &ClusterTemplateInstanceCleanupList{})
+}
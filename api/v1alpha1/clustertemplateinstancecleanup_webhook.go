@@ -0,0 +1,69 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+var clustertemplateinstancecleanuplog = logf.Log.WithName("clustertemplateinstancecleanup-resource")
+
+func (r *ClusterTemplateInstanceCleanup) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-clustertemplate-openshift-io-v1alpha1-clustertemplateinstancecleanup,mutating=false,failurePolicy=fail,sideEffects=None,groups=clustertemplate.openshift.io,resources=clustertemplateinstancecleanups,verbs=create;update,versions=v1alpha1,name=vclustertemplateinstancecleanup.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &ClusterTemplateInstanceCleanup{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterTemplateInstanceCleanup) ValidateCreate() error {
+	clustertemplateinstancecleanuplog.Info("validate create", "name", r.Name)
+	return r.ValidateSelector()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterTemplateInstanceCleanup) ValidateUpdate(old runtime.Object) error {
+	clustertemplateinstancecleanuplog.Info("validate update", "name", r.Name)
+	return r.ValidateSelector()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterTemplateInstanceCleanup) ValidateDelete() error {
+	return nil
+}
+
+// ValidateSelector checks the selector of the cleanup is valid and not empty - an empty selector
+// matches all the instances of the hub
+func (r *ClusterTemplateInstanceCleanup) ValidateSelector() error {
+	selector, err := metav1.LabelSelectorAsSelector(&r.Spec.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid selector - %q", err)
+	}
+	if selector.Empty() {
+		return fmt.Errorf("selector can not be empty")
+	}
+	return nil
+}
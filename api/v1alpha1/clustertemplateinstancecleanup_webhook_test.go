@@ -0,0 +1,36 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ClusterTemplateInstanceCleanup validating webhook", func() {
+	It("Fails for empty selector", func() {
+		cleanup := &ClusterTemplateInstanceCleanup{
+			ObjectMeta: v1.ObjectMeta{Name: "cleanup"},
+		}
+		Expect(cleanup.ValidateCreate()).Should(MatchError("selector can not be empty"))
+
+		cleanup.Spec.Selector.MatchLabels = map[string]string{}
+		Expect(cleanup.ValidateUpdate(cleanup)).Should(MatchError("selector can not be empty"))
+
+		cleanup.Spec.Selector.MatchLabels["env"] = "ci"
+		Expect(cleanup.ValidateCreate()).Should(Succeed())
+	})
+
+	It("Fails for invalid selector", func() {
+		cleanup := &ClusterTemplateInstanceCleanup{
+			ObjectMeta: v1.ObjectMeta{Name: "cleanup"},
+			Spec: ClusterTemplateInstanceCleanupSpec{
+				Selector: v1.LabelSelector{
+					MatchExpressions: []v1.LabelSelectorRequirement{
+						{Key: "env", Operator: "Foo"},
+					},
+				},
+			},
+		}
+		Expect(cleanup.ValidateCreate()).ShouldNot(Succeed())
+	})
+})
@@ -0,0 +1,76 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ClusterTemplateInstanceSupportBundleSpec struct {
+	// Name of the ClusterTemplateInstance (in the namespace of the bundle) to gather data about
+	ClusterTemplateInstanceRef string `json:"clusterTemplateInstanceRef"`
+}
+
+// ClusterTemplateInstanceSupportBundleStatus defines the observed state of
+// ClusterTemplateInstanceSupportBundle
+type ClusterTemplateInstanceSupportBundleStatus struct {
+	// ConfigMap with the gathered data
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ConfigMap *corev1.LocalObjectReference `json:"configMap,omitempty"`
+	// Why the data could not be gathered or which of them were truncated
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Message string `json:"message,omitempty"`
+	// Time when the data were gathered
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=clustertemplateinstancesupportbundles,shortName=ctisb;ctisbs,scope=Namespaced
+//+kubebuilder:printcolumn:name="Instance",type="string",JSONPath=".spec.clusterTemplateInstanceRef",description="Name of the instance"
+//+kubebuilder:printcolumn:name="ConfigMap",type="string",JSONPath=".status.configMap.name",description="ConfigMap with the gathered data"
+//+kubebuilder:printcolumn:name="Completed",type="date",JSONPath=".status.completionTime",description="Time when the data were gathered"
+//+operator-sdk:csv:customresourcedefinitions:displayName="Cluster template instance support bundle",resources={{ClusterTemplateInstance, v1alpha1, ""},{ConfigMap, v1, ""}}
+
+// Gathers the data needed to troubleshoot a ClusterTemplateInstance (the instance, its ArgoCD
+// Applications, the resources of its cluster definition, its events and logs of failed chart
+// tests) into a ConfigMap which can be attached to a support ticket
+type ClusterTemplateInstanceSupportBundle struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterTemplateInstanceSupportBundleSpec   `json:"spec,omitempty"`
+	Status ClusterTemplateInstanceSupportBundleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterTemplateInstanceSupportBundleList contains a list of ClusterTemplateInstanceSupportBundle
+type ClusterTemplateInstanceSupportBundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterTemplateInstanceSupportBundle `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(
+		&ClusterTemplateInstanceSupportBundle{},
+		&ClusterTemplateInstanceSupportBundleList{},
+	)
+}
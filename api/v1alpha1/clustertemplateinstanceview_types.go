@@ -0,0 +1,84 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterTemplateInstanceViewStatus is the non-sensitive part of the ClusterTemplateInstance status
+type ClusterTemplateInstanceViewStatus struct {
+	// The ClusterTemplateInstance
+	Instance InstanceReference `json:"instance"`
+	// Name of the ClusterTemplate the instance was created from
+	ClusterTemplate string `json:"clusterTemplate"`
+	// +optional
+	// User who created the instance
+	Owner string `json:"owner,omitempty"`
+	// +optional
+	// Phase of the instance
+	Phase Phase `json:"phase,omitempty"`
+	// +optional
+	// Version of the cluster definition Helm chart
+	ChartVersion string `json:"chartVersion,omitempty"`
+	// +optional
+	// API server URL of the cluster
+	APIserverURL string `json:"apiServerURL,omitempty"`
+	// +optional
+	// URL of the web console of the cluster
+	ConsoleURL string `json:"consoleURL,omitempty"`
+	// +optional
+	// OpenShift version of the cluster
+	OpenShiftVersion string `json:"openshiftVersion,omitempty"`
+	// +optional
+	// Time the instance was created
+	CreationTime *metav1.Time `json:"creationTime,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:path=clustertemplateinstanceviews,shortName=ctiv;ctivs,scope=Namespaced
+//+kubebuilder:printcolumn:name="Instance namespace",type="string",JSONPath=".status.instance.namespace",description="Namespace of the instance"
+//+kubebuilder:printcolumn:name="Instance",type="string",JSONPath=".status.instance.name",description="Name of the instance"
+//+kubebuilder:printcolumn:name="Template",type="string",JSONPath=".status.clusterTemplate",description="Cluster template"
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Cluster phase"
+//+kubebuilder:printcolumn:name="Owner",type="string",JSONPath=".status.owner",description="Owner of the instance"
+//+kubebuilder:printcolumn:name="API URL",type="string",JSONPath=".status.apiServerURL",description="API URL"
+//+kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.openshiftVersion",description="OpenShift version"
+//+operator-sdk:csv:customresourcedefinitions:displayName="Cluster template instance view",resources={{ClusterTemplateInstance, v1alpha1, ""}}
+
+// Read-only projection of a ClusterTemplateInstance maintained by the operator in a shared
+// namespace. Contains no credentials, so the status of the clusters can be shared with teams which
+// can not access namespaces of the instances.
+type ClusterTemplateInstanceView struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status ClusterTemplateInstanceViewStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterTemplateInstanceViewList contains a list of ClusterTemplateInstanceView
+type ClusterTemplateInstanceViewList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterTemplateInstanceView `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterTemplateInstanceView{}, &ClusterTemplateInstanceViewList{})
+}
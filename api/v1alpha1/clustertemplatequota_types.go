@@ -20,6 +20,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Name of the ConfigMap in which the quota controller publishes remaining quotas of the namespace
+const RemainingQuotaConfigMapName = "claas-remaining-quota"
+
 type AllowedTemplate struct {
 	// Name of the ClusterTemplate
 	Name string `json:"name"`
@@ -34,6 +37,18 @@ type ClusterTemplateQuotaSpec struct {
 	// +optional
 	// Total budget for all clusters within given namespace
 	Budget int `json:"budget,omitempty"`
+	//+kubebuilder:validation:Minimum=1
+	// +optional
+	// Maximum number of worker nodes for all clusters within given namespace
+	MaxNodes int `json:"maxNodes,omitempty"`
+	//+kubebuilder:validation:Minimum=1
+	// +optional
+	// Maximum number of worker vCPUs for all clusters within given namespace
+	MaxVCPU int `json:"maxVCPU,omitempty"`
+	// +optional
+	// Names of the ClusterSizeClasses which can be selected by instances within given namespace,
+	// all size classes are allowed if empty
+	AllowedSizeClasses []string `json:"allowedSizeClasses,omitempty"`
 	// Represents all ClusterTemplates which can be used in given namespace
 	AllowedTemplates []AllowedTemplate `json:"allowedTemplates"`
 }
@@ -43,6 +58,12 @@ type ClusterTemplateQuotaStatus struct {
 	// How much budget is currenly spent
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	BudgetSpent int `json:"budgetSpent"`
+	// How many worker nodes are currently requested
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	NodesSpent int `json:"nodesSpent,omitempty"`
+	// How many worker vCPUs are currently requested
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	VCPUSpent int `json:"vcpuSpent,omitempty"`
 	// Which instances are in use
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	TemplateInstances []AllowedTemplate `json:"templateInstances"`
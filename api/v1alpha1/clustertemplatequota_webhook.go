@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -73,9 +74,41 @@ func (r *ClusterTemplateQuota) ValidateCreate() error {
 			return fmt.Errorf("template '%s' does not exist", allowedTemplate.Name)
 		}
 	}
+
+	for _, sizeClass := range r.Spec.AllowedSizeClasses {
+		if err := quotaControllerClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: sizeClass},
+			&ClusterSizeClass{},
+		); err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("size class '%s' does not exist", sizeClass)
+			}
+			return fmt.Errorf("failed to get cluster size class - %q", err)
+		}
+	}
 	return nil
 }
 
+// checkSizeClass checks the size class selected by an instance is allowed by the quota
+func (r *ClusterTemplateQuota) checkSizeClass(sizeClass string) error {
+	if len(r.Spec.AllowedSizeClasses) == 0 {
+		return nil
+	}
+	for _, allowed := range r.Spec.AllowedSizeClasses {
+		if allowed == sizeClass {
+			return nil
+		}
+	}
+	if sizeClass == "" {
+		return fmt.Errorf(
+			"failed quota: quota requires one of size classes %v",
+			r.Spec.AllowedSizeClasses,
+		)
+	}
+	return fmt.Errorf("failed quota: quota does not allow '%s' size class", sizeClass)
+}
+
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ClusterTemplateQuota) ValidateUpdate(old runtime.Object) error {
 	clustertemplatequotalog.Info("validate update", "name", r.Name)
@@ -0,0 +1,65 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Allowed values of the catalog fields of ClusterTemplates. Empty list allows any value
+type ClusterTemplateTaxonomySpec struct {
+	// +optional
+	// Allowed infrastructure providers, ie 'aws'
+	Providers []string `json:"providers,omitempty"`
+	// +optional
+	// Allowed cluster sizes, ie 'small'
+	Sizes []string `json:"sizes,omitempty"`
+	// +optional
+	// Allowed purposes, ie 'development'
+	Purposes []string `json:"purposes,omitempty"`
+	// +optional
+	// Allowed compliance levels, ie 'pci-dss'
+	ComplianceLevels []string `json:"complianceLevels,omitempty"`
+	// +optional
+	// Allowed tags
+	Tags []string `json:"tags,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:path=clustertemplatetaxonomies,shortName=ctt;ctts,scope=Cluster
+//+operator-sdk:csv:customresourcedefinitions:displayName="Cluster template taxonomy",resources={{ClusterTemplate, v1alpha1, ""}}
+
+// Defines categories and tags which can be used in the catalog of ClusterTemplates
+type ClusterTemplateTaxonomy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterTemplateTaxonomySpec `json:"spec"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterTemplateTaxonomyList contains a list of ClusterTemplateTaxonomy
+type ClusterTemplateTaxonomyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterTemplateTaxonomy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterTemplateTaxonomy{}, &ClusterTemplateTaxonomyList{})
+}
@@ -60,6 +60,30 @@ var (
 		Version:  "v1",
 	}
 
+	AgentClusterInstallGVK = schema.GroupVersionResource{
+		Group:    "extensions.hive.openshift.io",
+		Resource: "AgentClusterInstall",
+		Version:  "v1beta1",
+	}
+
+	AgentGVK = schema.GroupVersionResource{
+		Group:    "agent-install.openshift.io",
+		Resource: "Agent",
+		Version:  "v1beta1",
+	}
+
+	CAPIClusterGVK = schema.GroupVersionResource{
+		Group:    "cluster.x-k8s.io",
+		Resource: "Cluster",
+		Version:  "v1beta1",
+	}
+
+	CAPIMachineGVK = schema.GroupVersionResource{
+		Group:    "cluster.x-k8s.io",
+		Resource: "Machine",
+		Version:  "v1beta1",
+	}
+
 	ConsolePluginGVK = schema.GroupVersionResource{
 		Group:    "console.openshift.io",
 		Resource: "ConsolePlugin",
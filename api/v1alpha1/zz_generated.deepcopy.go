@@ -23,10 +23,33 @@ package v1alpha1
 
 import (
 	"k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddOn) DeepCopyInto(out *AddOn) {
+	*out = *in
+	if in.ClusterSetup != nil {
+		in, out := &in.ClusterSetup, &out.ClusterSetup
+		*out = make([]ClusterSetup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddOn.
+func (in *AddOn) DeepCopy() *AddOn {
+	if in == nil {
+		return nil
+	}
+	out := new(AddOn)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AllowedTemplate) DeepCopyInto(out *AllowedTemplate) {
 	*out = *in
@@ -43,105 +66,168 @@ func (in *AllowedTemplate) DeepCopy() *AllowedTemplate {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterDefinitionSchema) DeepCopyInto(out *ClusterDefinitionSchema) {
+func (in *BaseDomainFromHub) DeepCopyInto(out *BaseDomainFromHub) {
 	*out = *in
-	if in.Error != nil {
-		in, out := &in.Error, &out.Error
-		*out = new(string)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaseDomainFromHub.
+func (in *BaseDomainFromHub) DeepCopy() *BaseDomainFromHub {
+	if in == nil {
+		return nil
+	}
+	out := new(BaseDomainFromHub)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryOptions) DeepCopyInto(out *CanaryOptions) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]Parameter, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDefinitionSchema.
-func (in *ClusterDefinitionSchema) DeepCopy() *ClusterDefinitionSchema {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryOptions.
+func (in *CanaryOptions) DeepCopy() *CanaryOptions {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterDefinitionSchema)
+	out := new(CanaryOptions)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSetup) DeepCopyInto(out *ClusterSetup) {
+func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
 	*out = *in
-	in.Spec.DeepCopyInto(&out.Spec)
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSetup.
-func (in *ClusterSetup) DeepCopy() *ClusterSetup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStatus.
+func (in *CanaryStatus) DeepCopy() *CanaryStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSetup)
+	out := new(CanaryStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSetupSchema) DeepCopyInto(out *ClusterSetupSchema) {
+func (in *ChartTestStatus) DeepCopyInto(out *ChartTestStatus) {
 	*out = *in
-	if in.Error != nil {
-		in, out := &in.Error, &out.Error
-		*out = new(string)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartTestStatus.
+func (in *ChartTestStatus) DeepCopy() *ChartTestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChartTestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChartTests) DeepCopyInto(out *ChartTests) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSetupSchema.
-func (in *ClusterSetupSchema) DeepCopy() *ClusterSetupSchema {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartTests.
+func (in *ChartTests) DeepCopy() *ChartTests {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSetupSchema)
+	out := new(ChartTests)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterSetupStatus) DeepCopyInto(out *ClusterSetupStatus) {
+func (in *ChartTestsStatus) DeepCopyInto(out *ChartTestsStatus) {
 	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tests != nil {
+		in, out := &in.Tests, &out.Tests
+		*out = make([]ChartTestStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSetupStatus.
-func (in *ClusterSetupStatus) DeepCopy() *ClusterSetupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartTestsStatus.
+func (in *ChartTestsStatus) DeepCopy() *ChartTestsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterSetupStatus)
+	out := new(ChartTestsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplate) DeepCopyInto(out *ClusterTemplate) {
+func (in *ChartVerification) DeepCopyInto(out *ChartVerification) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplate.
-func (in *ClusterTemplate) DeepCopy() *ClusterTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartVerification.
+func (in *ChartVerification) DeepCopy() *ChartVerification {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplate)
+	out := new(ChartVerification)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplate) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCompute) DeepCopyInto(out *ClusterCompute) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(ComputeRule)
+		**out = **in
 	}
-	return nil
+	if in.VCPUPerNode != nil {
+		in, out := &in.VCPUPerNode, &out.VCPUPerNode
+		*out = new(ComputeRule)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCompute.
+func (in *ClusterCompute) DeepCopy() *ClusterCompute {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCompute)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateInstance) DeepCopyInto(out *ClusterTemplateInstance) {
+func (in *ClusterCredentialRequest) DeepCopyInto(out *ClusterCredentialRequest) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -149,18 +235,18 @@ func (in *ClusterTemplateInstance) DeepCopyInto(out *ClusterTemplateInstance) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstance.
-func (in *ClusterTemplateInstance) DeepCopy() *ClusterTemplateInstance {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCredentialRequest.
+func (in *ClusterCredentialRequest) DeepCopy() *ClusterCredentialRequest {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateInstance)
+	out := new(ClusterCredentialRequest)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateInstance) DeepCopyObject() runtime.Object {
+func (in *ClusterCredentialRequest) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -168,31 +254,31 @@ func (in *ClusterTemplateInstance) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateInstanceList) DeepCopyInto(out *ClusterTemplateInstanceList) {
+func (in *ClusterCredentialRequestList) DeepCopyInto(out *ClusterCredentialRequestList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterTemplateInstance, len(*in))
+		*out = make([]ClusterCredentialRequest, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceList.
-func (in *ClusterTemplateInstanceList) DeepCopy() *ClusterTemplateInstanceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCredentialRequestList.
+func (in *ClusterCredentialRequestList) DeepCopy() *ClusterCredentialRequestList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateInstanceList)
+	out := new(ClusterCredentialRequestList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateInstanceList) DeepCopyObject() runtime.Object {
+func (in *ClusterCredentialRequestList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -200,105 +286,178 @@ func (in *ClusterTemplateInstanceList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateInstanceSpec) DeepCopyInto(out *ClusterTemplateInstanceSpec) {
+func (in *ClusterCredentialRequestSpec) DeepCopyInto(out *ClusterCredentialRequestSpec) {
 	*out = *in
-	if in.Parameters != nil {
-		in, out := &in.Parameters, &out.Parameters
-		*out = make([]Parameter, len(*in))
-		copy(*out, *in)
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceSpec.
-func (in *ClusterTemplateInstanceSpec) DeepCopy() *ClusterTemplateInstanceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCredentialRequestSpec.
+func (in *ClusterCredentialRequestSpec) DeepCopy() *ClusterCredentialRequestSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateInstanceSpec)
+	out := new(ClusterCredentialRequestSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateInstanceStatus) DeepCopyInto(out *ClusterTemplateInstanceStatus) {
+func (in *ClusterCredentialRequestStatus) DeepCopyInto(out *ClusterCredentialRequestStatus) {
 	*out = *in
-	if in.ClusterTemplateSpec != nil {
-		in, out := &in.ClusterTemplateSpec, &out.ClusterTemplateSpec
-		*out = new(ClusterTemplateSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.AdminPassword != nil {
-		in, out := &in.AdminPassword, &out.AdminPassword
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
-	if in.Kubeconfig != nil {
-		in, out := &in.Kubeconfig, &out.Kubeconfig
-		*out = new(v1.LocalObjectReference)
+	if in.IssueTime != nil {
+		in, out := &in.IssueTime, &out.IssueTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCredentialRequestStatus.
+func (in *ClusterCredentialRequestStatus) DeepCopy() *ClusterCredentialRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCredentialRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDefinitionSchema) DeepCopyInto(out *ClusterDefinitionSchema) {
+	*out = *in
+	if in.Error != nil {
+		in, out := &in.Error, &out.Error
+		*out = new(string)
 		**out = **in
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDefinitionSchema.
+func (in *ClusterDefinitionSchema) DeepCopy() *ClusterDefinitionSchema {
+	if in == nil {
+		return nil
 	}
-	if in.ClusterSetup != nil {
-		in, out := &in.ClusterSetup, &out.ClusterSetup
-		*out = new([]ClusterSetupStatus)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]ClusterSetupStatus, len(*in))
-			copy(*out, *in)
-		}
+	out := new(ClusterDefinitionSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterName) DeepCopyInto(out *ClusterName) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterName.
+func (in *ClusterName) DeepCopy() *ClusterName {
+	if in == nil {
+		return nil
 	}
+	out := new(ClusterName)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceStatus.
-func (in *ClusterTemplateInstanceStatus) DeepCopy() *ClusterTemplateInstanceStatus {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPlatformStatus) DeepCopyInto(out *ClusterPlatformStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPlatformStatus.
+func (in *ClusterPlatformStatus) DeepCopy() *ClusterPlatformStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateInstanceStatus)
+	out := new(ClusterPlatformStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateList) DeepCopyInto(out *ClusterTemplateList) {
+func (in *ClusterPoolRef) DeepCopyInto(out *ClusterPoolRef) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ClusterTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPoolRef.
+func (in *ClusterPoolRef) DeepCopy() *ClusterPoolRef {
+	if in == nil {
+		return nil
 	}
+	out := new(ClusterPoolRef)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateList.
-func (in *ClusterTemplateList) DeepCopy() *ClusterTemplateList {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterScopedResource) DeepCopyInto(out *ClusterScopedResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterScopedResource.
+func (in *ClusterScopedResource) DeepCopy() *ClusterScopedResource {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateList)
+	out := new(ClusterScopedResource)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSetup) DeepCopyInto(out *ClusterSetup) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(SetupGitSource)
+		**out = **in
 	}
-	return nil
+	if in.Identity != nil {
+		in, out := &in.Identity, &out.Identity
+		*out = new(SetupIdentity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(SetupJob)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AnsibleJob != nil {
+		in, out := &in.AnsibleJob, &out.AnsibleJob
+		*out = new(SetupAnsibleJob)
+		**out = **in
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSetup.
+func (in *ClusterSetup) DeepCopy() *ClusterSetup {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSetup)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateQuota) DeepCopyInto(out *ClusterTemplateQuota) {
+func (in *ClusterSetupDefinition) DeepCopyInto(out *ClusterSetupDefinition) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -306,18 +465,18 @@ func (in *ClusterTemplateQuota) DeepCopyInto(out *ClusterTemplateQuota) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuota.
-func (in *ClusterTemplateQuota) DeepCopy() *ClusterTemplateQuota {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSetupDefinition.
+func (in *ClusterSetupDefinition) DeepCopy() *ClusterSetupDefinition {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateQuota)
+	out := new(ClusterSetupDefinition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateQuota) DeepCopyObject() runtime.Object {
+func (in *ClusterSetupDefinition) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -325,31 +484,31 @@ func (in *ClusterTemplateQuota) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateQuotaList) DeepCopyInto(out *ClusterTemplateQuotaList) {
+func (in *ClusterSetupDefinitionList) DeepCopyInto(out *ClusterSetupDefinitionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterTemplateQuota, len(*in))
+		*out = make([]ClusterSetupDefinition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuotaList.
-func (in *ClusterTemplateQuotaList) DeepCopy() *ClusterTemplateQuotaList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSetupDefinitionList.
+func (in *ClusterSetupDefinitionList) DeepCopy() *ClusterSetupDefinitionList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateQuotaList)
+	out := new(ClusterSetupDefinitionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateQuotaList) DeepCopyObject() runtime.Object {
+func (in *ClusterSetupDefinitionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -357,102 +516,1754 @@ func (in *ClusterTemplateQuotaList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateQuotaSpec) DeepCopyInto(out *ClusterTemplateQuotaSpec) {
+func (in *ClusterSetupDefinitionSpec) DeepCopyInto(out *ClusterSetupDefinitionSpec) {
 	*out = *in
-	if in.AllowedTemplates != nil {
-		in, out := &in.AllowedTemplates, &out.AllowedTemplates
-		*out = make([]AllowedTemplate, len(*in))
-		copy(*out, *in)
-	}
+	in.Setup.DeepCopyInto(&out.Setup)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuotaSpec.
-func (in *ClusterTemplateQuotaSpec) DeepCopy() *ClusterTemplateQuotaSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSetupDefinitionSpec.
+func (in *ClusterSetupDefinitionSpec) DeepCopy() *ClusterSetupDefinitionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateQuotaSpec)
+	out := new(ClusterSetupDefinitionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateQuotaStatus) DeepCopyInto(out *ClusterTemplateQuotaStatus) {
+func (in *ClusterSetupDefinitionStatus) DeepCopyInto(out *ClusterSetupDefinitionStatus) {
 	*out = *in
-	if in.TemplateInstances != nil {
-		in, out := &in.TemplateInstances, &out.TemplateInstances
-		*out = make([]AllowedTemplate, len(*in))
+	if in.ClusterTemplates != nil {
+		in, out := &in.ClusterTemplates, &out.ClusterTemplates
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuotaStatus.
-func (in *ClusterTemplateQuotaStatus) DeepCopy() *ClusterTemplateQuotaStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSetupDefinitionStatus.
+func (in *ClusterSetupDefinitionStatus) DeepCopy() *ClusterSetupDefinitionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateQuotaStatus)
+	out := new(ClusterSetupDefinitionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateSpec) DeepCopyInto(out *ClusterTemplateSpec) {
+func (in *ClusterSetupSchema) DeepCopyInto(out *ClusterSetupSchema) {
 	*out = *in
-	in.ClusterDefinition.DeepCopyInto(&out.ClusterDefinition)
-	if in.ClusterSetup != nil {
-		in, out := &in.ClusterSetup, &out.ClusterSetup
-		*out = make([]ClusterSetup, len(*in))
+	if in.Error != nil {
+		in, out := &in.Error, &out.Error
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSetupSchema.
+func (in *ClusterSetupSchema) DeepCopy() *ClusterSetupSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSetupSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSetupStatus) DeepCopyInto(out *ClusterSetupStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSetupStatus.
+func (in *ClusterSetupStatus) DeepCopy() *ClusterSetupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSetupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSizeClass) DeepCopyInto(out *ClusterSizeClass) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSizeClass.
+func (in *ClusterSizeClass) DeepCopy() *ClusterSizeClass {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSizeClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSizeClass) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSizeClassList) DeepCopyInto(out *ClusterSizeClassList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterSizeClass, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSpec.
-func (in *ClusterTemplateSpec) DeepCopy() *ClusterTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSizeClassList.
+func (in *ClusterSizeClassList) DeepCopy() *ClusterSizeClassList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateSpec)
+	out := new(ClusterSizeClassList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSizeClassList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateStatus) DeepCopyInto(out *ClusterTemplateStatus) {
+func (in *ClusterSizeClassSpec) DeepCopyInto(out *ClusterSizeClassSpec) {
 	*out = *in
-	in.ClusterDefinition.DeepCopyInto(&out.ClusterDefinition)
-	if in.ClusterSetup != nil {
-		in, out := &in.ClusterSetup, &out.ClusterSetup
-		*out = make([]ClusterSetupSchema, len(*in))
+	if in.ControlPlaneQuota != nil {
+		in, out := &in.ControlPlaneQuota, &out.ControlPlaneQuota
+		*out = new(ControlPlaneQuota)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSizeClassSpec.
+func (in *ClusterSizeClassSpec) DeepCopy() *ClusterSizeClassSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSizeClassSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplate) DeepCopyInto(out *ClusterTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplate.
+func (in *ClusterTemplate) DeepCopy() *ClusterTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateCatalog) DeepCopyInto(out *ClusterTemplateCatalog) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateCatalog.
+func (in *ClusterTemplateCatalog) DeepCopy() *ClusterTemplateCatalog {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateCatalog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstance) DeepCopyInto(out *ClusterTemplateInstance) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstance.
+func (in *ClusterTemplateInstance) DeepCopy() *ClusterTemplateInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateInstance) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstanceCleanup) DeepCopyInto(out *ClusterTemplateInstanceCleanup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceCleanup.
+func (in *ClusterTemplateInstanceCleanup) DeepCopy() *ClusterTemplateInstanceCleanup {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateInstanceCleanup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateInstanceCleanup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstanceCleanupList) DeepCopyInto(out *ClusterTemplateInstanceCleanupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplateInstanceCleanup, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateStatus.
-func (in *ClusterTemplateStatus) DeepCopy() *ClusterTemplateStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceCleanupList.
+func (in *ClusterTemplateInstanceCleanupList) DeepCopy() *ClusterTemplateInstanceCleanupList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateStatus)
+	out := new(ClusterTemplateInstanceCleanupList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateInstanceCleanupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Parameter) DeepCopyInto(out *Parameter) {
+func (in *ClusterTemplateInstanceCleanupSpec) DeepCopyInto(out *ClusterTemplateInstanceCleanupSpec) {
 	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	out.OlderThan = in.OlderThan
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Parameter.
-func (in *Parameter) DeepCopy() *Parameter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceCleanupSpec.
+func (in *ClusterTemplateInstanceCleanupSpec) DeepCopy() *ClusterTemplateInstanceCleanupSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Parameter)
+	out := new(ClusterTemplateInstanceCleanupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstanceCleanupStatus) DeepCopyInto(out *ClusterTemplateInstanceCleanupStatus) {
+	*out = *in
+	if in.MatchedInstances != nil {
+		in, out := &in.MatchedInstances, &out.MatchedInstances
+		*out = make([]InstanceReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceCleanupStatus.
+func (in *ClusterTemplateInstanceCleanupStatus) DeepCopy() *ClusterTemplateInstanceCleanupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateInstanceCleanupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstanceList) DeepCopyInto(out *ClusterTemplateInstanceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplateInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceList.
+func (in *ClusterTemplateInstanceList) DeepCopy() *ClusterTemplateInstanceList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateInstanceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateInstanceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstanceSpec) DeepCopyInto(out *ClusterTemplateInstanceSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]Parameter, len(*in))
+		copy(*out, *in)
+	}
+	if in.ValuesFrom != nil {
+		in, out := &in.ValuesFrom, &out.ValuesFrom
+		*out = make([]ValuesReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(ClusterUpgrade)
+		**out = **in
+	}
+	if in.AddOns != nil {
+		in, out := &in.AddOns, &out.AddOns
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodePoolReplicas != nil {
+		in, out := &in.NodePoolReplicas, &out.NodePoolReplicas
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodePoolAutoscaling != nil {
+		in, out := &in.NodePoolAutoscaling, &out.NodePoolAutoscaling
+		*out = make(map[string]NodePoolAutoscaling, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceSpec.
+func (in *ClusterTemplateInstanceSpec) DeepCopy() *ClusterTemplateInstanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateInstanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstanceStatus) DeepCopyInto(out *ClusterTemplateInstanceStatus) {
+	*out = *in
+	if in.ClusterTemplateSpec != nil {
+		in, out := &in.ClusterTemplateSpec, &out.ClusterTemplateSpec
+		*out = new(ClusterTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProvisioningSLO != nil {
+		in, out := &in.ProvisioningSLO, &out.ProvisioningSLO
+		*out = new(ProvisioningSLOStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdminPassword != nil {
+		in, out := &in.AdminPassword, &out.AdminPassword
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Kubeconfig != nil {
+		in, out := &in.Kubeconfig, &out.Kubeconfig
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterSetup != nil {
+		in, out := &in.ClusterSetup, &out.ClusterSetup
+		*out = new([]ClusterSetupStatus)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]ClusterSetupStatus, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(ClusterUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ChartTests != nil {
+		in, out := &in.ChartTests, &out.ChartTests
+		*out = new(ChartTestsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Preview != nil {
+		in, out := &in.Preview, &out.Preview
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Overview != nil {
+		in, out := &in.Overview, &out.Overview
+		*out = new(InstanceOverview)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Platform != nil {
+		in, out := &in.Platform, &out.Platform
+		*out = new(ClusterPlatformStatus)
+		**out = **in
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePoolStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterScopedResources != nil {
+		in, out := &in.ClusterScopedResources, &out.ClusterScopedResources
+		*out = make([]ClusterScopedResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.VerifiedCharts != nil {
+		in, out := &in.VerifiedCharts, &out.VerifiedCharts
+		*out = make([]VerifiedChart, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceStatus.
+func (in *ClusterTemplateInstanceStatus) DeepCopy() *ClusterTemplateInstanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateInstanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstanceSupportBundle) DeepCopyInto(out *ClusterTemplateInstanceSupportBundle) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceSupportBundle.
+func (in *ClusterTemplateInstanceSupportBundle) DeepCopy() *ClusterTemplateInstanceSupportBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateInstanceSupportBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateInstanceSupportBundle) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstanceSupportBundleList) DeepCopyInto(out *ClusterTemplateInstanceSupportBundleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplateInstanceSupportBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceSupportBundleList.
+func (in *ClusterTemplateInstanceSupportBundleList) DeepCopy() *ClusterTemplateInstanceSupportBundleList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateInstanceSupportBundleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateInstanceSupportBundleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstanceSupportBundleSpec) DeepCopyInto(out *ClusterTemplateInstanceSupportBundleSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceSupportBundleSpec.
+func (in *ClusterTemplateInstanceSupportBundleSpec) DeepCopy() *ClusterTemplateInstanceSupportBundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateInstanceSupportBundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstanceSupportBundleStatus) DeepCopyInto(out *ClusterTemplateInstanceSupportBundleStatus) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceSupportBundleStatus.
+func (in *ClusterTemplateInstanceSupportBundleStatus) DeepCopy() *ClusterTemplateInstanceSupportBundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateInstanceSupportBundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstanceView) DeepCopyInto(out *ClusterTemplateInstanceView) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceView.
+func (in *ClusterTemplateInstanceView) DeepCopy() *ClusterTemplateInstanceView {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateInstanceView)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateInstanceView) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstanceViewList) DeepCopyInto(out *ClusterTemplateInstanceViewList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplateInstanceView, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceViewList.
+func (in *ClusterTemplateInstanceViewList) DeepCopy() *ClusterTemplateInstanceViewList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateInstanceViewList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateInstanceViewList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstanceViewStatus) DeepCopyInto(out *ClusterTemplateInstanceViewStatus) {
+	*out = *in
+	out.Instance = in.Instance
+	if in.CreationTime != nil {
+		in, out := &in.CreationTime, &out.CreationTime
+		*out = new(metav1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceViewStatus.
+func (in *ClusterTemplateInstanceViewStatus) DeepCopy() *ClusterTemplateInstanceViewStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateInstanceViewStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateList) DeepCopyInto(out *ClusterTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateList.
+func (in *ClusterTemplateList) DeepCopy() *ClusterTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateQuota) DeepCopyInto(out *ClusterTemplateQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuota.
+func (in *ClusterTemplateQuota) DeepCopy() *ClusterTemplateQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateQuotaList) DeepCopyInto(out *ClusterTemplateQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplateQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuotaList.
+func (in *ClusterTemplateQuotaList) DeepCopy() *ClusterTemplateQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateQuotaSpec) DeepCopyInto(out *ClusterTemplateQuotaSpec) {
+	*out = *in
+	if in.AllowedSizeClasses != nil {
+		in, out := &in.AllowedSizeClasses, &out.AllowedSizeClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedTemplates != nil {
+		in, out := &in.AllowedTemplates, &out.AllowedTemplates
+		*out = make([]AllowedTemplate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuotaSpec.
+func (in *ClusterTemplateQuotaSpec) DeepCopy() *ClusterTemplateQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateQuotaStatus) DeepCopyInto(out *ClusterTemplateQuotaStatus) {
+	*out = *in
+	if in.TemplateInstances != nil {
+		in, out := &in.TemplateInstances, &out.TemplateInstances
+		*out = make([]AllowedTemplate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuotaStatus.
+func (in *ClusterTemplateQuotaStatus) DeepCopy() *ClusterTemplateQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateSpec) DeepCopyInto(out *ClusterTemplateSpec) {
+	*out = *in
+	in.ClusterDefinition.DeepCopyInto(&out.ClusterDefinition)
+	if in.ClusterPool != nil {
+		in, out := &in.ClusterPool, &out.ClusterPool
+		*out = new(ClusterPoolRef)
+		**out = **in
+	}
+	if in.OCM != nil {
+		in, out := &in.OCM, &out.OCM
+		*out = new(OCMCluster)
+		**out = **in
+	}
+	if in.EmbeddedChart != nil {
+		in, out := &in.EmbeddedChart, &out.EmbeddedChart
+		*out = new(EmbeddedChart)
+		**out = **in
+	}
+	if in.RepositoryMirrors != nil {
+		in, out := &in.RepositoryMirrors, &out.RepositoryMirrors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChartVerification != nil {
+		in, out := &in.ChartVerification, &out.ChartVerification
+		*out = new(ChartVerification)
+		**out = **in
+	}
+	if in.PostRenderer != nil {
+		in, out := &in.PostRenderer, &out.PostRenderer
+		*out = new(PostRenderer)
+		**out = **in
+	}
+	if in.ClusterSetup != nil {
+		in, out := &in.ClusterSetup, &out.ClusterSetup
+		*out = make([]ClusterSetup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SetupRetryBackoff != nil {
+		in, out := &in.SetupRetryBackoff, &out.SetupRetryBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SetupTimeout != nil {
+		in, out := &in.SetupTimeout, &out.SetupTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Compute != nil {
+		in, out := &in.Compute, &out.Compute
+		*out = new(ClusterCompute)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SizeClasses != nil {
+		in, out := &in.SizeClasses, &out.SizeClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ParameterMigrations != nil {
+		in, out := &in.ParameterMigrations, &out.ParameterMigrations
+		*out = make([]ParameterMigration, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostingCluster != nil {
+		in, out := &in.HostingCluster, &out.HostingCluster
+		*out = new(HostingCluster)
+		**out = **in
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = new(NodePoolOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionGates != nil {
+		in, out := &in.DeletionGates, &out.DeletionGates
+		*out = make([]DeletionGate, len(*in))
+		copy(*out, *in)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstallOptions != nil {
+		in, out := &in.InstallOptions, &out.InstallOptions
+		*out = new(InstallOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxLifetime != nil {
+		in, out := &in.MaxLifetime, &out.MaxLifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ProvisioningSLO != nil {
+		in, out := &in.ProvisioningSLO, &out.ProvisioningSLO
+		*out = new(ProvisioningSLO)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BaseDomainFromHub != nil {
+		in, out := &in.BaseDomainFromHub, &out.BaseDomainFromHub
+		*out = new(BaseDomainFromHub)
+		**out = **in
+	}
+	if in.DefaultCredentials != nil {
+		in, out := &in.DefaultCredentials, &out.DefaultCredentials
+		*out = new(DefaultCredentials)
+		**out = **in
+	}
+	if in.TrustedCABundle != nil {
+		in, out := &in.TrustedCABundle, &out.TrustedCABundle
+		*out = new(TrustedCABundle)
+		**out = **in
+	}
+	if in.ClusterName != nil {
+		in, out := &in.ClusterName, &out.ClusterName
+		*out = new(ClusterName)
+		**out = **in
+	}
+	if in.ChartTests != nil {
+		in, out := &in.ChartTests, &out.ChartTests
+		*out = new(ChartTests)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HubRequirements != nil {
+		in, out := &in.HubRequirements, &out.HubRequirements
+		*out = new(HubRequirements)
+		**out = **in
+	}
+	if in.Catalog != nil {
+		in, out := &in.Catalog, &out.Catalog
+		*out = new(ClusterTemplateCatalog)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AddOns != nil {
+		in, out := &in.AddOns, &out.AddOns
+		*out = make([]AddOn, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSpec.
+func (in *ClusterTemplateSpec) DeepCopy() *ClusterTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateStatus) DeepCopyInto(out *ClusterTemplateStatus) {
+	*out = *in
+	in.ClusterDefinition.DeepCopyInto(&out.ClusterDefinition)
+	if in.ClusterSetup != nil {
+		in, out := &in.ClusterSetup, &out.ClusterSetup
+		*out = make([]ClusterSetupSchema, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateStatus.
+func (in *ClusterTemplateStatus) DeepCopy() *ClusterTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateTaxonomy) DeepCopyInto(out *ClusterTemplateTaxonomy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateTaxonomy.
+func (in *ClusterTemplateTaxonomy) DeepCopy() *ClusterTemplateTaxonomy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateTaxonomy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateTaxonomy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateTaxonomyList) DeepCopyInto(out *ClusterTemplateTaxonomyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplateTaxonomy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateTaxonomyList.
+func (in *ClusterTemplateTaxonomyList) DeepCopy() *ClusterTemplateTaxonomyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateTaxonomyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateTaxonomyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateTaxonomySpec) DeepCopyInto(out *ClusterTemplateTaxonomySpec) {
+	*out = *in
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sizes != nil {
+		in, out := &in.Sizes, &out.Sizes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Purposes != nil {
+		in, out := &in.Purposes, &out.Purposes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ComplianceLevels != nil {
+		in, out := &in.ComplianceLevels, &out.ComplianceLevels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateTaxonomySpec.
+func (in *ClusterTemplateTaxonomySpec) DeepCopy() *ClusterTemplateTaxonomySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateTaxonomySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgrade) DeepCopyInto(out *ClusterUpgrade) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgrade.
+func (in *ClusterUpgrade) DeepCopy() *ClusterUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradeStatus) DeepCopyInto(out *ClusterUpgradeStatus) {
+	*out = *in
+	if in.ControlPlane != nil {
+		in, out := &in.ControlPlane, &out.ControlPlane
+		*out = new(ComponentUpgradeStatus)
+		**out = **in
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]ComponentUpgradeStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradeStatus.
+func (in *ClusterUpgradeStatus) DeepCopy() *ClusterUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentUpgradeStatus) DeepCopyInto(out *ComponentUpgradeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentUpgradeStatus.
+func (in *ComponentUpgradeStatus) DeepCopy() *ComponentUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComputeRule) DeepCopyInto(out *ComputeRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComputeRule.
+func (in *ComputeRule) DeepCopy() *ComputeRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ComputeRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneQuota) DeepCopyInto(out *ControlPlaneQuota) {
+	*out = *in
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.DefaultLimits != nil {
+		in, out := &in.DefaultLimits, &out.DefaultLimits
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.DefaultRequests != nil {
+		in, out := &in.DefaultRequests, &out.DefaultRequests
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneQuota.
+func (in *ControlPlaneQuota) DeepCopy() *ControlPlaneQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialsOverview) DeepCopyInto(out *CredentialsOverview) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialsOverview.
+func (in *CredentialsOverview) DeepCopy() *CredentialsOverview {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialsOverview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultCredentials) DeepCopyInto(out *DefaultCredentials) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultCredentials.
+func (in *DefaultCredentials) DeepCopy() *DefaultCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionGate) DeepCopyInto(out *DeletionGate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionGate.
+func (in *DeletionGate) DeepCopy() *DeletionGate {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmbeddedChart) DeepCopyInto(out *EmbeddedChart) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmbeddedChart.
+func (in *EmbeddedChart) DeepCopy() *EmbeddedChart {
+	if in == nil {
+		return nil
+	}
+	out := new(EmbeddedChart)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostingCluster) DeepCopyInto(out *HostingCluster) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostingCluster.
+func (in *HostingCluster) DeepCopy() *HostingCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(HostingCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HubRequirements) DeepCopyInto(out *HubRequirements) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HubRequirements.
+func (in *HubRequirements) DeepCopy() *HubRequirements {
+	if in == nil {
+		return nil
+	}
+	out := new(HubRequirements)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallOptions) DeepCopyInto(out *InstallOptions) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallOptions.
+func (in *InstallOptions) DeepCopy() *InstallOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceOverview) DeepCopyInto(out *InstanceOverview) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]OverviewStep, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeline != nil {
+		in, out := &in.Timeline, &out.Timeline
+		*out = make([]TimelineEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Credentials = in.Credentials
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceOverview.
+func (in *InstanceOverview) DeepCopy() *InstanceOverview {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceOverview)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceReference) DeepCopyInto(out *InstanceReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceReference.
+func (in *InstanceReference) DeepCopy() *InstanceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePool) DeepCopyInto(out *NodePool) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]NodePoolTaint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
+func (in *NodePool) DeepCopy() *NodePool {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolAutoscaling) DeepCopyInto(out *NodePoolAutoscaling) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolAutoscaling.
+func (in *NodePoolAutoscaling) DeepCopy() *NodePoolAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolCondition) DeepCopyInto(out *NodePoolCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolCondition.
+func (in *NodePoolCondition) DeepCopy() *NodePoolCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolOptions) DeepCopyInto(out *NodePoolOptions) {
+	*out = *in
+	if in.InstanceTypes != nil {
+		in, out := &in.InstanceTypes, &out.InstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolOptions.
+func (in *NodePoolOptions) DeepCopy() *NodePoolOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolStatus) DeepCopyInto(out *NodePoolStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]NodePoolCondition, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolStatus.
+func (in *NodePoolStatus) DeepCopy() *NodePoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolTaint) DeepCopyInto(out *NodePoolTaint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolTaint.
+func (in *NodePoolTaint) DeepCopy() *NodePoolTaint {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolTaint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCMCluster) DeepCopyInto(out *OCMCluster) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCMCluster.
+func (in *OCMCluster) DeepCopy() *OCMCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(OCMCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OverviewStep) DeepCopyInto(out *OverviewStep) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverviewStep.
+func (in *OverviewStep) DeepCopy() *OverviewStep {
+	if in == nil {
+		return nil
+	}
+	out := new(OverviewStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Parameter) DeepCopyInto(out *Parameter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Parameter.
+func (in *Parameter) DeepCopy() *Parameter {
+	if in == nil {
+		return nil
+	}
+	out := new(Parameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParameterMigration) DeepCopyInto(out *ParameterMigration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterMigration.
+func (in *ParameterMigration) DeepCopy() *ParameterMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(ParameterMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostRenderer) DeepCopyInto(out *PostRenderer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostRenderer.
+func (in *PostRenderer) DeepCopy() *PostRenderer {
+	if in == nil {
+		return nil
+	}
+	out := new(PostRenderer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningSLO) DeepCopyInto(out *ProvisioningSLO) {
+	*out = *in
+	if in.ClusterInstall != nil {
+		in, out := &in.ClusterInstall, &out.ClusterInstall
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Ready != nil {
+		in, out := &in.Ready, &out.Ready
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningSLO.
+func (in *ProvisioningSLO) DeepCopy() *ProvisioningSLO {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningSLO)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvisioningSLOStatus) DeepCopyInto(out *ProvisioningSLOStatus) {
+	*out = *in
+	if in.ClusterInstallDuration != nil {
+		in, out := &in.ClusterInstallDuration, &out.ClusterInstallDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ReadyDuration != nil {
+		in, out := &in.ReadyDuration, &out.ReadyDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ViolatedSLOs != nil {
+		in, out := &in.ViolatedSLOs, &out.ViolatedSLOs
+		*out = make([]ProvisioningSLOType, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvisioningSLOStatus.
+func (in *ProvisioningSLOStatus) DeepCopy() *ProvisioningSLOStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvisioningSLOStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SetupAnsibleJob) DeepCopyInto(out *SetupAnsibleJob) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SetupAnsibleJob.
+func (in *SetupAnsibleJob) DeepCopy() *SetupAnsibleJob {
+	if in == nil {
+		return nil
+	}
+	out := new(SetupAnsibleJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SetupGitSource) DeepCopyInto(out *SetupGitSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SetupGitSource.
+func (in *SetupGitSource) DeepCopy() *SetupGitSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SetupGitSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SetupIdentity) DeepCopyInto(out *SetupIdentity) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SetupIdentity.
+func (in *SetupIdentity) DeepCopy() *SetupIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(SetupIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SetupJob) DeepCopyInto(out *SetupJob) {
+	*out = *in
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SetupJob.
+func (in *SetupJob) DeepCopy() *SetupJob {
+	if in == nil {
+		return nil
+	}
+	out := new(SetupJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimelineEntry) DeepCopyInto(out *TimelineEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimelineEntry.
+func (in *TimelineEntry) DeepCopy() *TimelineEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(TimelineEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedCABundle) DeepCopyInto(out *TrustedCABundle) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedCABundle.
+func (in *TrustedCABundle) DeepCopy() *TrustedCABundle {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedCABundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValuesReference) DeepCopyInto(out *ValuesReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValuesReference.
+func (in *ValuesReference) DeepCopy() *ValuesReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ValuesReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerifiedChart) DeepCopyInto(out *VerifiedChart) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerifiedChart.
+func (in *VerifiedChart) DeepCopy() *VerifiedChart {
+	if in == nil {
+		return nil
+	}
+	out := new(VerifiedChart)
 	in.DeepCopyInto(out)
 	return out
 }
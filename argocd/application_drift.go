@@ -0,0 +1,23 @@
+package argocd
+
+import (
+	"strings"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// GetDriftedResources returns resources of the application whose live state differs from
+// the rendered manifests (including deleted resources), formatted as Kind/Namespace/Name
+func GetDriftedResources(application *argo.Application) []string {
+	drifted := []string{}
+	for _, resource := range application.Status.Resources {
+		if resource.Hook || resource.Status != argo.SyncStatusCodeOutOfSync {
+			continue
+		}
+		drifted = append(
+			drifted,
+			strings.Join([]string{resource.Kind, resource.Namespace, resource.Name}, "/"),
+		)
+	}
+	return drifted
+}
@@ -0,0 +1,41 @@
+package argocd
+
+import (
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Application drift", func() {
+	It("No resources", func() {
+		app := &argo.Application{}
+		Expect(GetDriftedResources(app)).Should(BeEmpty())
+	})
+	It("Returns out of sync resources", func() {
+		app := &argo.Application{
+			Status: argo.ApplicationStatus{
+				Resources: []argo.ResourceStatus{
+					{
+						Kind:      "HostedCluster",
+						Namespace: "clusters",
+						Name:      "foo",
+						Status:    argo.SyncStatusCodeSynced,
+					},
+					{
+						Kind:      "NodePool",
+						Namespace: "clusters",
+						Name:      "foo-workers",
+						Status:    argo.SyncStatusCodeOutOfSync,
+					},
+					{
+						Kind:   "Job",
+						Name:   "foo-hook",
+						Status: argo.SyncStatusCodeOutOfSync,
+						Hook:   true,
+					},
+				},
+			},
+		}
+		Expect(GetDriftedResources(app)).Should(Equal([]string{"NodePool/clusters/foo-workers"}))
+	})
+})
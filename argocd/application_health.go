@@ -15,7 +15,12 @@ const (
 	ApplicationError       ApplicationStatus = "ApplicationError"
 	ApplicationSyncRunning ApplicationStatus = "ApplicationSyncRunning"
 	ApplicationDegraded    ApplicationStatus = "ApplicationDegraded"
+	ApplicationSyncFailed  ApplicationStatus = "ApplicationSyncFailed"
 	ApplicationHealthy     ApplicationStatus = "ApplicationHealthy"
+	// The application could not be created
+	ApplicationCreateFailed ApplicationStatus = "ApplicationCreateFailed"
+	// The cluster setup waits for the cluster setups it depends on, it is not created yet
+	ApplicationWaiting ApplicationStatus = "ApplicationWaiting"
 )
 
 func GetApplicationHealth(application *argo.Application) (ApplicationStatus, string) {
@@ -33,6 +38,16 @@ func GetApplicationHealth(application *argo.Application) (ApplicationStatus, str
 		return ApplicationDegraded, msg
 	}
 
+	if application.Status.OperationState != nil &&
+		(application.Status.OperationState.Phase == synccommon.OperationFailed ||
+			application.Status.OperationState.Phase == synccommon.OperationError) {
+		msg := getOperationMsg(application)
+		if msg == "" {
+			msg = "Application sync failed"
+		}
+		return ApplicationSyncFailed, msg
+	}
+
 	if application.Status.OperationState == nil ||
 		application.Status.OperationState.Phase != synccommon.OperationSucceeded ||
 		application.Status.Health.Status != argoHealth.HealthStatusHealthy {
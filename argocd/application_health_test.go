@@ -73,6 +73,34 @@ var _ = Describe("Application health", func() {
 		Expect(status).Should(Equal(ApplicationSyncRunning))
 		Expect(msg).Should(Equal("foo msg"))
 	})
+	It("Sync failed", func() {
+		app := &argo.Application{
+			Status: argo.ApplicationStatus{
+				Health: argo.HealthStatus{
+					Status: argoHealth.HealthStatusProgressing,
+				},
+				OperationState: &argo.OperationState{
+					Phase:   common.OperationFailed,
+					Message: "one or more objects failed to apply",
+				},
+			},
+		}
+		status, msg := GetApplicationHealth(app)
+		Expect(status).Should(Equal(ApplicationSyncFailed))
+		Expect(msg).Should(Equal("one or more objects failed to apply"))
+	})
+	It("Sync errored without message", func() {
+		app := &argo.Application{
+			Status: argo.ApplicationStatus{
+				OperationState: &argo.OperationState{
+					Phase: common.OperationError,
+				},
+			},
+		}
+		status, msg := GetApplicationHealth(app)
+		Expect(status).Should(Equal(ApplicationSyncFailed))
+		Expect(msg).Should(Equal("Application sync failed"))
+	})
 	It("Synced", func() {
 		app := &argo.Application{
 			Status: argo.ApplicationStatus{
@@ -0,0 +1,54 @@
+package argocd
+
+import (
+	"encoding/json"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// PausedSyncPolicyAnnotation keeps the automated sync policy of an application while its
+// automated syncs are paused
+const PausedSyncPolicyAnnotation = "clustertemplate.openshift.io/paused-sync-policy"
+
+// PauseAutomatedSync turns off automated syncs (and self healing) of the application, the
+// automated sync policy is kept in an annotation. Returns true if the application was changed.
+func PauseAutomatedSync(app *argo.Application) (bool, error) {
+	if app.Spec.SyncPolicy == nil || app.Spec.SyncPolicy.Automated == nil {
+		return false, nil
+	}
+	automated, err := json.Marshal(app.Spec.SyncPolicy.Automated)
+	if err != nil {
+		return false, err
+	}
+	if app.Annotations == nil {
+		app.Annotations = map[string]string{}
+	}
+	app.Annotations[PausedSyncPolicyAnnotation] = string(automated)
+	app.Spec.SyncPolicy.Automated = nil
+	return true, nil
+}
+
+// ResumeAutomatedSync restores the automated sync policy paused by PauseAutomatedSync. Returns
+// true if the application was changed.
+func ResumeAutomatedSync(app *argo.Application) (bool, error) {
+	paused, ok := app.Annotations[PausedSyncPolicyAnnotation]
+	if !ok {
+		return false, nil
+	}
+	automated := &argo.SyncPolicyAutomated{}
+	if err := json.Unmarshal([]byte(paused), automated); err != nil {
+		return false, err
+	}
+	if app.Spec.SyncPolicy == nil {
+		app.Spec.SyncPolicy = &argo.SyncPolicy{}
+	}
+	app.Spec.SyncPolicy.Automated = automated
+	delete(app.Annotations, PausedSyncPolicyAnnotation)
+	return true, nil
+}
+
+// IsAutomatedSyncPaused returns true if automated syncs of the application are paused
+func IsAutomatedSyncPaused(app *argo.Application) bool {
+	_, ok := app.Annotations[PausedSyncPolicyAnnotation]
+	return ok
+}
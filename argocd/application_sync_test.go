@@ -0,0 +1,49 @@
+package argocd
+
+import (
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Application sync", func() {
+	It("Pauses and resumes automated sync", func() {
+		app := &argo.Application{
+			Spec: argo.ApplicationSpec{
+				SyncPolicy: &argo.SyncPolicy{
+					Automated: &argo.SyncPolicyAutomated{SelfHeal: true, Prune: true},
+				},
+			},
+		}
+		changed, err := PauseAutomatedSync(app)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(changed).Should(BeTrue())
+		Expect(app.Spec.SyncPolicy.Automated).Should(BeNil())
+		Expect(IsAutomatedSyncPaused(app)).Should(BeTrue())
+
+		// paused already
+		changed, err = PauseAutomatedSync(app)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(changed).Should(BeFalse())
+
+		changed, err = ResumeAutomatedSync(app)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(changed).Should(BeTrue())
+		Expect(app.Spec.SyncPolicy.Automated).Should(
+			Equal(&argo.SyncPolicyAutomated{SelfHeal: true, Prune: true}),
+		)
+		Expect(IsAutomatedSyncPaused(app)).Should(BeFalse())
+
+		changed, err = ResumeAutomatedSync(app)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(changed).Should(BeFalse())
+	})
+
+	It("Does not pause manually synced application", func() {
+		app := &argo.Application{}
+		changed, err := PauseAutomatedSync(app)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(changed).Should(BeFalse())
+		Expect(IsAutomatedSyncPaused(app)).Should(BeFalse())
+	})
+})
@@ -0,0 +1,46 @@
+package argocd
+
+import (
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// TrackingLabel is set by ArgoCD on resources of an application to the application name,
+	// unless ArgoCD is configured to track resources by the annotation
+	TrackingLabel = "app.kubernetes.io/instance"
+	// TrackingAnnotation is set by ArgoCD on resources of an application to
+	// '<application>:<group>/<kind>:<namespace>/<name>'
+	TrackingAnnotation = "argocd.argoproj.io/tracking-id"
+)
+
+// GetTrackingApplication returns name of the ArgoCD application the resource is managed by,
+// empty if the resource is not tracked by any application
+func GetTrackingApplication(obj client.Object) string {
+	if trackingID, ok := obj.GetAnnotations()[TrackingAnnotation]; ok {
+		if app, _, found := strings.Cut(trackingID, ":"); found {
+			return app
+		}
+	}
+	return obj.GetLabels()[TrackingLabel]
+}
+
+// SetTrackingApplication hands the resource over to another ArgoCD application - the tracking
+// label and annotation which are set on the resource are changed to the application name
+func SetTrackingApplication(obj client.Object, app string) {
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		if trackingID, ok := annotations[TrackingAnnotation]; ok {
+			if _, resource, found := strings.Cut(trackingID, ":"); found {
+				annotations[TrackingAnnotation] = app + ":" + resource
+				obj.SetAnnotations(annotations)
+			}
+		}
+	}
+	if labels := obj.GetLabels(); labels != nil {
+		if _, ok := labels[TrackingLabel]; ok {
+			labels[TrackingLabel] = app
+			obj.SetLabels(labels)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package argocd
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Application tracking", func() {
+	It("Returns application tracking the resource", func() {
+		obj := &corev1.ConfigMap{}
+		Expect(GetTrackingApplication(obj)).Should(BeEmpty())
+
+		obj.Labels = map[string]string{TrackingLabel: "foo"}
+		Expect(GetTrackingApplication(obj)).Should(Equal("foo"))
+
+		obj.ObjectMeta = metav1.ObjectMeta{
+			Annotations: map[string]string{
+				TrackingAnnotation: "bar:hypershift.openshift.io/HostedCluster:clusters/baz",
+			},
+		}
+		Expect(GetTrackingApplication(obj)).Should(Equal("bar"))
+	})
+
+	It("Hands the resource over to another application", func() {
+		obj := &corev1.ConfigMap{}
+		SetTrackingApplication(obj, "foo")
+		Expect(obj.Labels).Should(BeNil())
+		Expect(obj.Annotations).Should(BeNil())
+
+		obj.ObjectMeta = metav1.ObjectMeta{
+			Labels: map[string]string{TrackingLabel: "bar"},
+			Annotations: map[string]string{
+				TrackingAnnotation: "bar:rbac.authorization.k8s.io/ClusterRole:/baz",
+			},
+		}
+		SetTrackingApplication(obj, "foo")
+		Expect(obj.Labels[TrackingLabel]).Should(Equal("foo"))
+		Expect(obj.Annotations[TrackingAnnotation]).Should(
+			Equal("foo:rbac.authorization.k8s.io/ClusterRole:/baz"),
+		)
+		Expect(GetTrackingApplication(obj)).Should(Equal("foo"))
+	})
+})
@@ -0,0 +1,100 @@
+package bridge
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	controllers "github.com/stolostron/cluster-templates-operator/controllers"
+	helm "github.com/stolostron/cluster-templates-operator/helm"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const chartsAPI = "/charts"
+
+// getEmbeddedChart serves a Helm repository of the chart embedded in a ConfigMap or Secret of
+// the ArgoCD namespace - the index file and the chart tarball. The repository is used by ArgoCD,
+// which does not authenticate, so only resources labeled as embedded charts are served.
+func getEmbeddedChart(
+	w http.ResponseWriter,
+	r *http.Request,
+	params httprouter.Params,
+	k8sClient *kubernetes.Clientset,
+) {
+	kind, ok := helm.GetEmbeddedChartKind(params.ByName("kind"))
+	if !ok {
+		writeError(w, "Unknown kind of embedded chart", http.StatusNotFound)
+		return
+	}
+	name := params.ByName("name")
+	var obj client.Object
+	var err error
+	switch kind {
+	case helm.EmbeddedChartConfigMap:
+		obj, err = k8sClient.CoreV1().
+			ConfigMaps(controllers.ArgoCDNamespace).
+			Get(r.Context(), name, metav1.GetOptions{})
+	case helm.EmbeddedChartSecret:
+		obj, err = k8sClient.CoreV1().
+			Secrets(controllers.ArgoCDNamespace).
+			Get(r.Context(), name, metav1.GetOptions{})
+	}
+	if err != nil {
+		code := http.StatusInternalServerError
+		if apierrors.IsNotFound(err) {
+			code = http.StatusNotFound
+		}
+		writeError(w, "Failed to get embedded chart: "+err.Error(), code)
+		return
+	}
+	data, err := helm.GetEmbeddedChartData(obj)
+	if err != nil {
+		writeError(w, "Failed to get embedded chart: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	switch params.ByName("file") {
+	case "index.yaml":
+		indexFile, err := helm.GetEmbeddedChartIndex(data)
+		if err != nil {
+			writeError(
+				w,
+				"Failed to load embedded chart: "+err.Error(),
+				http.StatusInternalServerError,
+			)
+			return
+		}
+		out, err := yaml.Marshal(indexFile)
+		if err != nil {
+			writeError(w, "Failed to serialize index file", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-yaml")
+		w.Write(out)
+	case helm.EmbeddedChartKey:
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Write(data)
+	default:
+		writeError(w, "File not found", http.StatusNotFound)
+	}
+}
+
+// withOperatorClient passes client with the credentials of the operator to the handler
+func withOperatorClient(h HandleWithToken, config rest.Config) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		client, err := kubernetes.NewForConfig(&config)
+		if err != nil {
+			writeError(
+				w,
+				"Failed to create k8s client: "+err.Error(),
+				http.StatusInternalServerError,
+			)
+			return
+		}
+		h(w, r, params, client)
+	}
+}
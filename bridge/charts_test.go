@@ -0,0 +1,77 @@
+package bridge
+
+import (
+	"io"
+	"net/http"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/helm"
+	testutils "github.com/stolostron/cluster-templates-operator/testutils"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("Embedded charts", func() {
+	var chartData []byte
+	var cm *corev1.ConfigMap
+	BeforeEach(func() {
+		var err error
+		chartData, err = os.ReadFile("../testutils/helm/hypershift-template-0.0.2.tgz")
+		Expect(err).ShouldNot(HaveOccurred())
+		cm = &corev1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "hypershift-template",
+				Namespace: "argocd",
+				Labels:    map[string]string{helm.EmbeddedChartLabel: "true"},
+			},
+			BinaryData: map[string][]byte{helm.EmbeddedChartKey: chartData},
+		}
+	})
+	AfterEach(func() {
+		testutils.DeleteResource(ctx, cm, k8sClient)
+	})
+
+	get := func(path string) (int, []byte) {
+		resp, err := http.Get(server.URL + chartsAPI + path)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return resp.StatusCode, body
+	}
+
+	It("Serves index and chart without authentication", func() {
+		Expect(k8sClient.Create(ctx, cm)).Should(Succeed())
+
+		code, body := get("/configmaps/hypershift-template/index.yaml")
+		Expect(code).To(Equal(http.StatusOK))
+		indexFile := &repo.IndexFile{}
+		Expect(yaml.Unmarshal(body, indexFile)).Should(Succeed())
+		Expect(indexFile.Entries["hypershift-template"]).Should(HaveLen(1))
+		Expect(indexFile.Entries["hypershift-template"][0].URLs).
+			Should(Equal([]string{helm.EmbeddedChartKey}))
+
+		code, body = get("/configmaps/hypershift-template/" + helm.EmbeddedChartKey)
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(Equal(chartData))
+
+		code, _ = get("/configmaps/hypershift-template/foo.tgz")
+		Expect(code).To(Equal(http.StatusNotFound))
+	})
+
+	It("Does not serve resources which are not embedded charts", func() {
+		cm.Labels = nil
+		Expect(k8sClient.Create(ctx, cm)).Should(Succeed())
+
+		code, _ := get("/configmaps/hypershift-template/" + helm.EmbeddedChartKey)
+		Expect(code).To(Equal(http.StatusNotFound))
+		code, _ = get("/pods/hypershift-template/" + helm.EmbeddedChartKey)
+		Expect(code).To(Equal(http.StatusNotFound))
+		code, _ = get("/secrets/foo/" + helm.EmbeddedChartKey)
+		Expect(code).To(Equal(http.StatusNotFound))
+	})
+})
@@ -62,6 +62,7 @@ func getRepositoryIndex(
 		repoURL,
 		[]corev1.Secret{*secret},
 		cm,
+		controllers.HelmCABundle,
 	)
 	if err != nil {
 		repository.Error = err.Error()
@@ -205,5 +206,6 @@ func GetRouter(config *rest.Config) *httprouter.Router {
 	router := httprouter.New()
 	router.GET(repositoryAPI+"/:name", withUserClient(getRepo, *config))
 	router.GET(repositoriesAPI, withUserClient(getRepositories, *config))
+	router.GET(chartsAPI+"/:kind/:name/:file", withOperatorClient(getEmbeddedChart, *config))
 	return router
 }
@@ -72,6 +72,10 @@ func (cd *CredentialsOptions) run(k8sClient client.Client, args []string) error
 		cti.Status.APIserverURL,
 	)
 
+	if cti.Status.ConsoleURL != "" {
+		result = result + fmt.Sprintf("Console URL: %s\n", cti.Status.ConsoleURL)
+	}
+
 	if ocLogin == "true" {
 		result = result + fmt.Sprintf("Login cmd: oc login %s -u %s -p %s\n",
 			cti.Status.APIserverURL,
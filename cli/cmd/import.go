@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/spf13/cobra"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
+	"github.com/stolostron/cluster-templates-operator/helm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// repoURL of the embedded chart source is replaced by the operator
+const embeddedChartsURL = "https://cluster-aas-operator-repo-bridge-service.cluster-aas-operator.svc:8001/charts"
+
+type ImportOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	Namespace              string
+	HostedClusterNamespace string
+	ArgoCDNamespace        string
+	DryRun                 bool
+}
+
+// NewImportOptions provides an instance of ImportOptions with default values
+func NewImportOptions(namespace string, streams genericclioptions.IOStreams) *ImportOptions {
+	return &ImportOptions{
+		configFlags:            genericclioptions.NewConfigFlags(true),
+		IOStreams:              streams,
+		Namespace:              namespace,
+		HostedClusterNamespace: "clusters",
+		ArgoCDNamespace:        "argocd",
+	}
+}
+
+func NewCmdImport(
+	k8sClient client.Client,
+	namespace string,
+	streams genericclioptions.IOStreams,
+) *cobra.Command {
+	o := NewImportOptions(namespace, streams)
+	cmd := &cobra.Command{
+		Use:          "import [hosted-cluster-name]",
+		Short:        "Import existing HostedCluster as a cluster template instance",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.run(k8sClient, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(
+		&o.HostedClusterNamespace,
+		"hosted-cluster-namespace",
+		o.HostedClusterNamespace,
+		"Namespace of the HostedCluster",
+	)
+	cmd.Flags().StringVar(
+		&o.ArgoCDNamespace,
+		"argocd-namespace",
+		o.ArgoCDNamespace,
+		"Namespace of ArgoCD, the generated chart is stored there",
+	)
+	cmd.Flags().BoolVar(
+		&o.DryRun,
+		"dry-run",
+		false,
+		"Print the generated resources instead of creating them",
+	)
+	return cmd
+}
+
+func (io *ImportOptions) run(k8sClient client.Client, args []string) error {
+	ctx := context.TODO()
+	name := fmt.Sprintf("imported-%s-%s", io.HostedClusterNamespace, args[0])
+	helmChart, err := clusterprovider.GetImportChart(
+		ctx,
+		k8sClient,
+		name,
+		args[0],
+		io.HostedClusterNamespace,
+	)
+	if err != nil {
+		return err
+	}
+	chartData, err := helm.PackageChart(helmChart)
+	if err != nil {
+		return err
+	}
+
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: io.ArgoCDNamespace,
+			Labels:    map[string]string{helm.EmbeddedChartLabel: "true"},
+		},
+		BinaryData: map[string][]byte{helm.EmbeddedChartKey: chartData},
+	}
+	template := &v1alpha1.ClusterTemplate{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       "ClusterTemplate",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{v1alpha1.CTImportedLabel: "true"},
+		},
+		Spec: v1alpha1.ClusterTemplateSpec{
+			EmbeddedChart: &v1alpha1.EmbeddedChart{
+				Kind: helm.EmbeddedChartConfigMap,
+				Name: name,
+			},
+			ClusterDefinition: argo.ApplicationSpec{
+				Destination: argo.ApplicationDestination{
+					Namespace: io.HostedClusterNamespace,
+					Server:    "https://kubernetes.default.svc",
+				},
+				Project: "default",
+				Source: argo.ApplicationSource{
+					RepoURL: helm.GetEmbeddedChartRepoURL(
+						embeddedChartsURL,
+						helm.EmbeddedChartConfigMap,
+						name,
+					),
+					TargetRevision: clusterprovider.ImportedChartVersion,
+					Chart:          name,
+				},
+				SyncPolicy: &argo.SyncPolicy{
+					Automated: &argo.SyncPolicyAutomated{},
+				},
+			},
+		},
+	}
+	instance := &v1alpha1.ClusterTemplateInstance{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       "ClusterTemplateInstance",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      args[0],
+			Namespace: io.Namespace,
+		},
+		Spec: v1alpha1.ClusterTemplateInstanceSpec{
+			ClusterTemplateRef: name,
+		},
+	}
+
+	for _, obj := range []client.Object{configMap, template, instance} {
+		if io.DryRun {
+			manifest, err := yaml.Marshal(obj)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(io.Out, "---\n%s", manifest); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := k8sClient.Create(ctx, obj); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(
+			io.Out,
+			"%s %s created\n",
+			obj.GetObjectKind().GroupVersionKind().Kind,
+			obj.GetName(),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
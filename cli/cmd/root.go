@@ -7,6 +7,7 @@ import (
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	argoOperator "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
 	olmv1 "github.com/operator-framework/api/pkg/operators/v1"
 	olm "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	mce "github.com/stolostron/backplane-operator/api/v1"
@@ -37,6 +38,7 @@ func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 	cmd.AddCommand(NewCmdTemplates(k8sClient, ns, streams))
 	cmd.AddCommand(NewCmdTemplateDescribe(k8sClient, streams))
 	cmd.AddCommand(NewCmdListInstances(k8sClient, ns, streams))
+	cmd.AddCommand(NewCmdImport(k8sClient, ns, streams))
 	cmd.AddCommand(NewCmdInstallOperator(k8sClient, streams))
 	cmd.AddCommand(NewCmdUninstallOperator(k8sClient, streams))
 	return cmd
@@ -51,6 +53,7 @@ func CreateK8sClient() (client.Client, string) {
 	utilruntime.Must(mce.AddToScheme(scheme))
 	utilruntime.Must(ocm.AddToScheme(scheme))
 	utilruntime.Must(addonapi.AddToScheme(scheme))
+	utilruntime.Must(hypershiftv1alpha1.AddToScheme(scheme))
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	configOverrides := &clientcmd.ConfigOverrides{}
 	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
@@ -0,0 +1,165 @@
+package clusterprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// Cluster API writes the admin kubeconfig of the cluster to the <cluster name>-kubeconfig secret
+const (
+	capiKubeconfigSuffix = "-kubeconfig"
+	capiKubeconfigKey    = "value"
+	capiMachineRunning   = "Running"
+)
+
+// conditions of the Cluster the cluster waits for, in the order they are checked
+var capiReadyConditions = []string{"InfrastructureReady", "ControlPlaneReady"}
+
+func init() {
+	RegisterProvider(ProviderRegistration{
+		Name: "Cluster API",
+		GVK:  v1alpha1.CAPIClusterGVK,
+		Detect: func(resource argo.ResourceStatus, application argo.Application) ClusterProvider {
+			machines := []string{}
+			for _, obj := range application.Status.Resources {
+				if obj.Kind == v1alpha1.CAPIMachineGVK.Resource &&
+					obj.Group == v1alpha1.CAPIMachineGVK.Group {
+					machines = append(machines, obj.Name)
+				}
+			}
+			return ClusterAPIProvider{
+				ClusterName:      resource.Name,
+				ClusterNamespace: getApplicationNamespace(resource, application),
+				MachineNames:     machines,
+			}
+		},
+	})
+}
+
+type ClusterAPIProvider struct {
+	ClusterName      string
+	ClusterNamespace string
+	MachineNames     []string
+}
+
+func (ca ClusterAPIProvider) GetClusterStatus(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+) (bool, string, error) {
+	cluster := newCAPIObject(v1alpha1.CAPIClusterGVK)
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: ca.ClusterName, Namespace: ca.ClusterNamespace},
+		cluster,
+	); err != nil {
+		return false, "", err
+	}
+
+	failure, _, err := unstructured.NestedString(cluster.Object, "status", "failureMessage")
+	if err != nil {
+		return false, "", err
+	}
+	if failure != "" {
+		return false, "Not available - failed: " + failure, nil
+	}
+
+	conditions, _, err := unstructured.NestedSlice(cluster.Object, "status", "conditions")
+	if err != nil {
+		return false, "", err
+	}
+	for _, conditionType := range capiReadyConditions {
+		if msg := getCAPIConditionMessage(conditions, conditionType); msg != "" {
+			return false, "Not available - " + msg + ca.getMachinesProgress(ctx, k8sClient), nil
+		}
+	}
+
+	kubeconfigSecret := corev1.Secret{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: ca.ClusterName + capiKubeconfigSuffix, Namespace: ca.ClusterNamespace},
+		&kubeconfigSecret,
+	); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, "Waiting for kubeconfig secret", nil
+		}
+		return false, "", err
+	}
+	kubeconfig, ok := kubeconfigSecret.Data[capiKubeconfigKey]
+	if !ok {
+		return false, "", errors.New("unexpected kubeconfig format")
+	}
+
+	if err := CreateKubeconfigSecret(ctx, k8sClient, kubeconfig, templateInstance); err != nil {
+		return false, "", err
+	}
+	return true, "Available", nil
+}
+
+// getCAPIConditionMessage returns why the condition of the Cluster is not True, empty message is
+// returned once it is True
+func getCAPIConditionMessage(conditions []interface{}, conditionType string) string {
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != conditionType {
+			continue
+		}
+		if condition["status"] == string(corev1.ConditionTrue) {
+			return ""
+		}
+		for _, key := range []string{"message", "reason"} {
+			if msg, ok := condition[key].(string); ok && msg != "" {
+				return conditionType + ": " + msg
+			}
+		}
+		return conditionType + ": " + fmt.Sprint(condition["status"])
+	}
+	return "waiting for " + conditionType
+}
+
+// getMachinesProgress returns how many Machines of the cluster definition are running, Machines
+// which can not be read are reported as not running
+func (ca ClusterAPIProvider) getMachinesProgress(
+	ctx context.Context,
+	k8sClient client.Client,
+) string {
+	if len(ca.MachineNames) == 0 {
+		return ""
+	}
+	running := 0
+	for _, name := range ca.MachineNames {
+		machine := newCAPIObject(v1alpha1.CAPIMachineGVK)
+		if err := k8sClient.Get(
+			ctx,
+			client.ObjectKey{Name: name, Namespace: ca.ClusterNamespace},
+			machine,
+		); err != nil {
+			continue
+		}
+		phase, _, _ := unstructured.NestedString(machine.Object, "status", "phase")
+		if phase == capiMachineRunning {
+			running++
+		}
+	}
+	return fmt.Sprintf(" (%d/%d machines running)", running, len(ca.MachineNames))
+}
+
+func newCAPIObject(gvr schema.GroupVersionResource) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   gvr.Group,
+		Version: gvr.Version,
+		Kind:    gvr.Resource,
+	})
+	return obj
+}
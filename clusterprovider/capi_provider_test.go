@@ -0,0 +1,151 @@
+package clusterprovider
+
+import (
+	"context"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Cluster API provider", func() {
+	It("Detects Cluster API clusters", func() {
+		app := argo.Application{
+			Spec: argo.ApplicationSpec{
+				Destination: argo.ApplicationDestination{Namespace: "clusters"},
+			},
+			Status: argo.ApplicationStatus{
+				Resources: []argo.ResourceStatus{
+					{
+						Kind:    "Cluster",
+						Version: "v1beta1",
+						Group:   "cluster.x-k8s.io",
+						Name:    "foo",
+					},
+					{
+						Kind:    "Machine",
+						Version: "v1beta1",
+						Group:   "cluster.x-k8s.io",
+						Name:    "foo-cp-0",
+					},
+				},
+			},
+		}
+		Expect(GetClusterProvider(app)).Should(Equal(ClusterAPIProvider{
+			ClusterName:      "foo",
+			ClusterNamespace: "clusters",
+			MachineNames:     []string{"foo-cp-0"},
+		}))
+
+		app.Status.Resources[0].Version = "v1alpha4"
+		Expect(GetClusterProvider(app)).Should(BeNil())
+	})
+
+	It("Waits for infrastructure and control plane of the cluster", func() {
+		cti := v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cti",
+				Namespace: "default",
+			},
+		}
+		cluster := newCAPIObject(v1alpha1.CAPIClusterGVK)
+		cluster.SetName("foo")
+		cluster.SetNamespace("clusters")
+		machine := newCAPIObject(v1alpha1.CAPIMachineGVK)
+		machine.SetName("foo-cp-0")
+		machine.SetNamespace("clusters")
+		Expect(unstructured.SetNestedField(machine.Object, "Provisioning", "status", "phase")).
+			Should(Succeed())
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cluster, machine)
+		provider := ClusterAPIProvider{
+			ClusterName:      "foo",
+			ClusterNamespace: "clusters",
+			MachineNames:     []string{"foo-cp-0"},
+		}
+
+		ready, msg, err := provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal(
+			"Not available - waiting for InfrastructureReady (0/1 machines running)",
+		))
+
+		setConditions := func(conditions ...interface{}) {
+			Expect(unstructured.SetNestedSlice(
+				cluster.Object,
+				conditions,
+				"status",
+				"conditions",
+			)).Should(Succeed())
+			Expect(k8sClient.Update(context.TODO(), cluster)).Should(Succeed())
+		}
+		setConditions(
+			map[string]interface{}{"type": "InfrastructureReady", "status": "True"},
+			map[string]interface{}{
+				"type":   "ControlPlaneReady",
+				"status": "False",
+				"reason": "WaitingForControlPlane",
+			},
+		)
+		ready, msg, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal(
+			"Not available - ControlPlaneReady: WaitingForControlPlane (0/1 machines running)",
+		))
+
+		setConditions(
+			map[string]interface{}{"type": "InfrastructureReady", "status": "True"},
+			map[string]interface{}{"type": "ControlPlaneReady", "status": "True"},
+		)
+		ready, msg, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal("Waiting for kubeconfig secret"))
+
+		Expect(k8sClient.Create(context.TODO(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-kubeconfig",
+				Namespace: "clusters",
+			},
+			Data: map[string][]byte{"value": []byte("kubeconfig")},
+		})).Should(Succeed())
+		ready, msg, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeTrue())
+		Expect(msg).Should(Equal("Available"))
+
+		kubeconfigSecret := &corev1.Secret{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: cti.GetKubeconfigRef(), Namespace: cti.Namespace},
+			kubeconfigSecret,
+		)).Should(Succeed())
+		Expect(kubeconfigSecret.Data["kubeconfig"]).Should(Equal([]byte("kubeconfig")))
+		// Cluster API does not create admin credentials
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: cti.GetKubeadminPassRef(), Namespace: cti.Namespace},
+			&corev1.Secret{},
+		)).ShouldNot(Succeed())
+
+		Expect(unstructured.SetNestedField(
+			cluster.Object,
+			"failed to create VPC",
+			"status",
+			"failureMessage",
+		)).Should(Succeed())
+		Expect(k8sClient.Update(context.TODO(), cluster)).Should(Succeed())
+		ready, msg, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal("Not available - failed: failed to create VPC"))
+	})
+})
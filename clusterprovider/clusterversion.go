@@ -0,0 +1,156 @@
+package clusterprovider
+
+import (
+	"context"
+
+	configv1 "github.com/openshift/api/config/v1"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var clusterVersionGVK = schema.GroupVersionKind{
+	Group:   "config.openshift.io",
+	Version: "v1",
+	Kind:    "ClusterVersion",
+}
+
+// label Hive keeps in sync with the version reported by the ClusterVersion of the cluster
+const hiveVersionLabel = "hive.openshift.io/version-major-minor-patch"
+
+// VersionProvider is implemented by cluster providers which report the OpenShift version the
+// cluster runs. Version of clusters of other providers is read from the cluster itself.
+type VersionProvider interface {
+	GetOpenShiftVersion(ctx context.Context, k8sClient client.Client) (string, error)
+}
+
+var _ VersionProvider = HostedClusterProvider{}
+var _ VersionProvider = ClusterDeploymentProvider{}
+var _ VersionProvider = ClusterClaimProvider{}
+
+func (hc HostedClusterProvider) GetOpenShiftVersion(
+	ctx context.Context,
+	k8sClient client.Client,
+) (string, error) {
+	hostedCluster := &hypershiftv1alpha1.HostedCluster{}
+	if err := hc.getHostingClient(k8sClient).Get(
+		ctx,
+		client.ObjectKey{Name: hc.HostedClusterName, Namespace: hc.HostedClusterNamespace},
+		hostedCluster,
+	); err != nil {
+		return "", err
+	}
+	if hostedCluster.Status.Version == nil {
+		return "", nil
+	}
+	// the most recent update is first, the cluster runs the version of the latest completed one
+	for _, update := range hostedCluster.Status.Version.History {
+		if update.State == configv1.CompletedUpdate {
+			return update.Version, nil
+		}
+	}
+	return "", nil
+}
+
+func (cd ClusterDeploymentProvider) GetOpenShiftVersion(
+	ctx context.Context,
+	k8sClient client.Client,
+) (string, error) {
+	clusterDeployment := hivev1.ClusterDeployment{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: cd.ClusterDeploymentName, Namespace: cd.ClusterDeploymentNamespace},
+		&clusterDeployment,
+	); err != nil {
+		return "", err
+	}
+	if version := clusterDeployment.Labels[hiveVersionLabel]; version != "" {
+		return version, nil
+	}
+	if clusterDeployment.Status.InstallVersion != nil {
+		return *clusterDeployment.Status.InstallVersion, nil
+	}
+	return "", nil
+}
+
+func (cc ClusterClaimProvider) GetOpenShiftVersion(
+	ctx context.Context,
+	k8sClient client.Client,
+) (string, error) {
+	clusterClaim := hivev1.ClusterClaim{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: cc.ClusterClaimName, Namespace: cc.ClusterClaimNamespace},
+		&clusterClaim,
+	); err != nil {
+		return "", err
+	}
+	if clusterClaim.Spec.Namespace == "" {
+		return "", nil
+	}
+	return ClusterDeploymentProvider{
+		ClusterDeploymentName:      clusterClaim.Spec.Namespace,
+		ClusterDeploymentNamespace: clusterClaim.Spec.Namespace,
+	}.GetOpenShiftVersion(ctx, k8sClient)
+}
+
+// GetClusterOpenShiftVersion reads the version of the latest completed update from the
+// ClusterVersion of the cluster. Returns empty version for clusters which are not OpenShift
+// clusters.
+func GetClusterOpenShiftVersion(ctx context.Context, clusterClient client.Client) (string, error) {
+	clusterVersion := &unstructured.Unstructured{}
+	clusterVersion.SetGroupVersionKind(clusterVersionGVK)
+	if err := clusterClient.Get(ctx, client.ObjectKey{Name: "version"}, clusterVersion); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	history, _, err := unstructured.NestedSlice(clusterVersion.Object, "status", "history")
+	if err != nil {
+		return "", err
+	}
+	for _, h := range history {
+		update, ok := h.(map[string]interface{})
+		if !ok || update["state"] != string(configv1.CompletedUpdate) {
+			continue
+		}
+		version, _ := update["version"].(string)
+		return version, nil
+	}
+	return "", nil
+}
+
+// GetClusterUpgradeProgress returns true and the message of the Progressing condition of the
+// ClusterVersion while the cluster is upgrading. Clusters which are not OpenShift clusters
+// are never reported as upgrading.
+func GetClusterUpgradeProgress(
+	ctx context.Context,
+	clusterClient client.Client,
+) (bool, string, error) {
+	clusterVersion := &unstructured.Unstructured{}
+	clusterVersion.SetGroupVersionKind(clusterVersionGVK)
+	if err := clusterClient.Get(ctx, client.ObjectKey{Name: "version"}, clusterVersion); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return false, "", nil
+		}
+		return false, "", err
+	}
+	conditions, _, err := unstructured.NestedSlice(clusterVersion.Object, "status", "conditions")
+	if err != nil {
+		return false, "", err
+	}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Progressing" {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		return condition["status"] == "True", message, nil
+	}
+	return false, "", nil
+}
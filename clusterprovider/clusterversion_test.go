@@ -0,0 +1,139 @@
+package clusterprovider
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Cluster version", func() {
+	It("Detects upgrade of the cluster", func() {
+		clusterVersion := &unstructured.Unstructured{}
+		clusterVersion.SetAPIVersion("config.openshift.io/v1")
+		clusterVersion.SetKind("ClusterVersion")
+		clusterVersion.SetName("version")
+		Expect(unstructured.SetNestedSlice(
+			clusterVersion.Object,
+			[]interface{}{
+				map[string]interface{}{
+					"type":   "Available",
+					"status": "True",
+				},
+				map[string]interface{}{
+					"type":    "Progressing",
+					"status":  "True",
+					"message": "Working towards 4.12.1: 95 of 829 done (11% complete)",
+				},
+			},
+			"status",
+			"conditions",
+		)).Should(Succeed())
+
+		upgrading, message, err := GetClusterUpgradeProgress(
+			ctx,
+			fake.NewFakeClientWithScheme(scheme.Scheme, clusterVersion),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(upgrading).Should(BeTrue())
+		Expect(message).Should(Equal("Working towards 4.12.1: 95 of 829 done (11% complete)"))
+
+		upgrading, _, err = GetClusterUpgradeProgress(
+			ctx,
+			fake.NewFakeClientWithScheme(scheme.Scheme),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(upgrading).Should(BeFalse())
+	})
+
+	It("Reads version of the cluster", func() {
+		clusterVersion := &unstructured.Unstructured{}
+		clusterVersion.SetAPIVersion("config.openshift.io/v1")
+		clusterVersion.SetKind("ClusterVersion")
+		clusterVersion.SetName("version")
+		Expect(unstructured.SetNestedSlice(
+			clusterVersion.Object,
+			[]interface{}{
+				map[string]interface{}{
+					"state":   "Partial",
+					"version": "4.12.1",
+				},
+				map[string]interface{}{
+					"state":   "Completed",
+					"version": "4.12.0",
+				},
+			},
+			"status",
+			"history",
+		)).Should(Succeed())
+
+		version, err := GetClusterOpenShiftVersion(
+			ctx,
+			fake.NewFakeClientWithScheme(scheme.Scheme, clusterVersion),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(version).Should(Equal("4.12.0"))
+
+		version, err = GetClusterOpenShiftVersion(ctx, fake.NewFakeClientWithScheme(scheme.Scheme))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(version).Should(BeEmpty())
+	})
+
+	It("Reads version of the HostedCluster", func() {
+		Expect(hypershiftv1alpha1.AddToScheme(scheme.Scheme)).To(Succeed())
+		hostedCluster := &hypershiftv1alpha1.HostedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Status: hypershiftv1alpha1.HostedClusterStatus{
+				Version: &hypershiftv1alpha1.ClusterVersionStatus{
+					History: []configv1.UpdateHistory{
+						{Version: "4.12.1", State: configv1.PartialUpdate},
+						{Version: "4.12.0", State: configv1.CompletedUpdate},
+					},
+				},
+			},
+		}
+		version, err := HostedClusterProvider{
+			HostedClusterName:      "foo",
+			HostedClusterNamespace: "bar",
+		}.GetOpenShiftVersion(ctx, fake.NewFakeClientWithScheme(scheme.Scheme, hostedCluster))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(version).Should(Equal("4.12.0"))
+	})
+
+	It("Reads version of the ClusterDeployment", func() {
+		Expect(hivev1.AddToScheme(scheme.Scheme)).To(Succeed())
+		installVersion := "4.12.0"
+		clusterDeployment := &hivev1.ClusterDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "foo",
+			},
+			Status: hivev1.ClusterDeploymentStatus{
+				InstallVersion: &installVersion,
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, clusterDeployment)
+		provider := ClusterDeploymentProvider{
+			ClusterDeploymentName:      "foo",
+			ClusterDeploymentNamespace: "foo",
+		}
+		version, err := provider.GetOpenShiftVersion(ctx, k8sClient)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(version).Should(Equal("4.12.0"))
+
+		// upgraded since the install
+		clusterDeployment.Labels = map[string]string{hiveVersionLabel: "4.12.1"}
+		Expect(k8sClient.Update(ctx, clusterDeployment)).Should(Succeed())
+		version, err = provider.GetOpenShiftVersion(ctx, k8sClient)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(version).Should(Equal("4.12.1"))
+	})
+})
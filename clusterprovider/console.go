@@ -0,0 +1,76 @@
+package clusterprovider
+
+import (
+	"context"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConsoleURLProvider is implemented by cluster providers which report the web console URL of the
+// cluster. Console URL of clusters of other providers is read from the cluster itself.
+type ConsoleURLProvider interface {
+	GetConsoleURL(ctx context.Context, k8sClient client.Client) (string, error)
+}
+
+var _ ConsoleURLProvider = ClusterDeploymentProvider{}
+var _ ConsoleURLProvider = ClusterClaimProvider{}
+
+func (cd ClusterDeploymentProvider) GetConsoleURL(
+	ctx context.Context,
+	k8sClient client.Client,
+) (string, error) {
+	clusterDeployment := hivev1.ClusterDeployment{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: cd.ClusterDeploymentName, Namespace: cd.ClusterDeploymentNamespace},
+		&clusterDeployment,
+	); err != nil {
+		return "", err
+	}
+	return clusterDeployment.Status.WebConsoleURL, nil
+}
+
+func (cc ClusterClaimProvider) GetConsoleURL(
+	ctx context.Context,
+	k8sClient client.Client,
+) (string, error) {
+	clusterClaim := hivev1.ClusterClaim{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: cc.ClusterClaimName, Namespace: cc.ClusterClaimNamespace},
+		&clusterClaim,
+	); err != nil {
+		return "", err
+	}
+	if clusterClaim.Spec.Namespace == "" {
+		return "", nil
+	}
+	return ClusterDeploymentProvider{
+		ClusterDeploymentName:      clusterClaim.Spec.Namespace,
+		ClusterDeploymentNamespace: clusterClaim.Spec.Namespace,
+	}.GetConsoleURL(ctx, k8sClient)
+}
+
+// GetClusterConsoleURL reads the web console URL from the console config of the cluster. Returns
+// empty URL for clusters which do not run the OpenShift console (ie not OpenShift clusters).
+func GetClusterConsoleURL(ctx context.Context, clusterClient client.Client) (string, error) {
+	console := &unstructured.Unstructured{}
+	console.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "config.openshift.io",
+		Version: "v1",
+		Kind:    "Console",
+	})
+	if err := clusterClient.Get(ctx, client.ObjectKey{Name: "cluster"}, console); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	url, _, err := unstructured.NestedString(console.Object, "status", "consoleURL")
+	return url, err
+}
@@ -0,0 +1,73 @@
+package clusterprovider
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Cluster console", func() {
+	consoleURL := "https://console-openshift-console.apps.foo.example.com"
+
+	It("Reads console URL of hive clusters", func() {
+		Expect(hivev1.AddToScheme(scheme.Scheme)).Should(Succeed())
+		clusterDeployment := &hivev1.ClusterDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "foo",
+			},
+			Status: hivev1.ClusterDeploymentStatus{
+				WebConsoleURL: consoleURL,
+			},
+		}
+		clusterClaim := &hivev1.ClusterClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "claim",
+				Namespace: "bar",
+			},
+			Spec: hivev1.ClusterClaimSpec{
+				Namespace: "foo",
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, clusterDeployment, clusterClaim)
+
+		url, err := ClusterDeploymentProvider{
+			ClusterDeploymentName:      "foo",
+			ClusterDeploymentNamespace: "foo",
+		}.GetConsoleURL(ctx, k8sClient)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(url).Should(Equal(consoleURL))
+
+		url, err = ClusterClaimProvider{
+			ClusterClaimName:      "claim",
+			ClusterClaimNamespace: "bar",
+		}.GetConsoleURL(ctx, k8sClient)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(url).Should(Equal(consoleURL))
+	})
+
+	It("Reads console URL from the cluster", func() {
+		console := &unstructured.Unstructured{}
+		console.SetAPIVersion("config.openshift.io/v1")
+		console.SetKind("Console")
+		console.SetName("cluster")
+		Expect(unstructured.SetNestedField(
+			console.Object,
+			consoleURL,
+			"status",
+			"consoleURL",
+		)).Should(Succeed())
+
+		url, err := GetClusterConsoleURL(ctx, fake.NewFakeClientWithScheme(scheme.Scheme, console))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(url).Should(Equal(consoleURL))
+
+		url, err = GetClusterConsoleURL(ctx, fake.NewFakeClientWithScheme(scheme.Scheme))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(url).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,209 @@
+package clusterprovider
+
+import (
+	"context"
+	"fmt"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// conditions of the AgentClusterInstall reported by the Assisted Installer
+const (
+	agentInstallFailedCondition    = "Failed"
+	agentInstallCompletedCondition = "Completed"
+	agentPlatform                  = "Agent"
+)
+
+var _ PlatformStatusProvider = ClusterDeploymentProvider{}
+
+// GetPlatformStatus reports how many hosts the Assisted Installer needs and how many are bound to
+// the cluster. Nil status is returned for ClusterDeployments installed by other installers.
+func (cd ClusterDeploymentProvider) GetPlatformStatus(
+	ctx context.Context,
+	k8sClient client.Client,
+) (*v1alpha1.ClusterPlatformStatus, error) {
+	clusterDeployment := hivev1.ClusterDeployment{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: cd.ClusterDeploymentName, Namespace: cd.ClusterDeploymentNamespace},
+		&clusterDeployment,
+	); err != nil {
+		return nil, err
+	}
+	agentClusterInstall, err := getAgentClusterInstall(ctx, k8sClient, clusterDeployment)
+	if agentClusterInstall == nil || err != nil {
+		return nil, err
+	}
+	bound, _, err := countAgents(ctx, k8sClient, clusterDeployment)
+	if err != nil {
+		return nil, err
+	}
+	return &v1alpha1.ClusterPlatformStatus{
+		Type:           agentPlatform,
+		RequiredAgents: getRequiredAgents(agentClusterInstall),
+		BoundAgents:    bound,
+	}, nil
+}
+
+// getAgentInstallStatus returns progress of the install of the ClusterDeployment by the Assisted
+// Installer - host discovery and approval first, then the install itself. Empty status is returned
+// for ClusterDeployments installed by other installers and once the install completed.
+func getAgentInstallStatus(
+	ctx context.Context,
+	k8sClient client.Client,
+	clusterDeployment hivev1.ClusterDeployment,
+) (string, error) {
+	agentClusterInstall, err := getAgentClusterInstall(ctx, k8sClient, clusterDeployment)
+	if agentClusterInstall == nil || err != nil {
+		return "", err
+	}
+	conditions, _, err := unstructured.NestedSlice(
+		agentClusterInstall.Object,
+		"status",
+		"conditions",
+	)
+	if err != nil {
+		return "", err
+	}
+	if condition := findAgentCondition(conditions, agentInstallCompletedCondition); condition != nil &&
+		condition["status"] == string(corev1.ConditionTrue) {
+		return "", nil
+	}
+	if condition := findAgentCondition(conditions, agentInstallFailedCondition); condition != nil &&
+		condition["status"] == string(corev1.ConditionTrue) {
+		msg, _ := condition["message"].(string)
+		return "Not available - failed: " + msg, nil
+	}
+
+	required := getRequiredAgents(agentClusterInstall)
+	bound, approved, err := countAgents(ctx, k8sClient, clusterDeployment)
+	if err != nil {
+		return "", err
+	}
+	if bound < required {
+		return fmt.Sprintf(
+			"Not available - waiting for %d hosts (%d/%d bound)",
+			required-bound,
+			bound,
+			required,
+		), nil
+	}
+	if approved < bound {
+		return fmt.Sprintf("Not available - waiting for approval of %d hosts", bound-approved), nil
+	}
+
+	state, _, _ := unstructured.NestedString(
+		agentClusterInstall.Object,
+		"status",
+		"debugInfo",
+		"state",
+	)
+	if state == "" {
+		return "Not available - waiting for install", nil
+	}
+	progress, found, _ := unstructured.NestedInt64(
+		agentClusterInstall.Object,
+		"status",
+		"progress",
+		"totalPercentage",
+	)
+	if found && progress > 0 {
+		return fmt.Sprintf("Not available - %s (%d%%)", state, progress), nil
+	}
+	return "Not available - " + state, nil
+}
+
+// getAgentClusterInstall returns the AgentClusterInstall referenced by the ClusterDeployment, nil
+// is returned if the cluster is installed by other installer
+func getAgentClusterInstall(
+	ctx context.Context,
+	k8sClient client.Client,
+	clusterDeployment hivev1.ClusterDeployment,
+) (*unstructured.Unstructured, error) {
+	ref := clusterDeployment.Spec.ClusterInstallRef
+	if ref == nil || ref.Group != v1alpha1.AgentClusterInstallGVK.Group ||
+		ref.Kind != v1alpha1.AgentClusterInstallGVK.Resource {
+		return nil, nil
+	}
+	agentClusterInstall := &unstructured.Unstructured{}
+	agentClusterInstall.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   v1alpha1.AgentClusterInstallGVK.Group,
+		Version: v1alpha1.AgentClusterInstallGVK.Version,
+		Kind:    v1alpha1.AgentClusterInstallGVK.Resource,
+	})
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: ref.Name, Namespace: clusterDeployment.Namespace},
+		agentClusterInstall,
+	); err != nil {
+		return nil, err
+	}
+	return agentClusterInstall, nil
+}
+
+// getRequiredAgents returns number of control plane and worker hosts the install waits for
+func getRequiredAgents(agentClusterInstall *unstructured.Unstructured) int {
+	required := 0
+	for _, field := range []string{"controlPlaneAgents", "workerAgents"} {
+		count, _, _ := unstructured.NestedInt64(
+			agentClusterInstall.Object,
+			"spec",
+			"provisionRequirements",
+			field,
+		)
+		required += int(count)
+	}
+	return required
+}
+
+// countAgents returns how many discovered hosts are bound to the ClusterDeployment and how many
+// of them are approved. Agents live in the namespace of their InfraEnv, which is not necessarily
+// the namespace of the ClusterDeployment.
+func countAgents(
+	ctx context.Context,
+	k8sClient client.Client,
+	clusterDeployment hivev1.ClusterDeployment,
+) (int, int, error) {
+	agents := &unstructured.UnstructuredList{}
+	agents.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   v1alpha1.AgentGVK.Group,
+		Version: v1alpha1.AgentGVK.Version,
+		Kind:    v1alpha1.AgentGVK.Resource + "List",
+	})
+	if err := k8sClient.List(ctx, agents); err != nil {
+		return 0, 0, err
+	}
+	bound, approved := 0, 0
+	for _, agent := range agents.Items {
+		name, _, _ := unstructured.NestedString(agent.Object, "spec", "clusterDeploymentName", "name")
+		namespace, _, _ := unstructured.NestedString(
+			agent.Object,
+			"spec",
+			"clusterDeploymentName",
+			"namespace",
+		)
+		if name != clusterDeployment.Name || namespace != clusterDeployment.Namespace {
+			continue
+		}
+		bound++
+		if isApproved, _, _ := unstructured.NestedBool(agent.Object, "spec", "approved"); isApproved {
+			approved++
+		}
+	}
+	return bound, approved, nil
+}
+
+func findAgentCondition(conditions []interface{}, conditionType string) map[string]interface{} {
+	for _, c := range conditions {
+		if condition, ok := c.(map[string]interface{}); ok && condition["type"] == conditionType {
+			return condition
+		}
+	}
+	return nil
+}
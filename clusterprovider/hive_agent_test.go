@@ -0,0 +1,135 @@
+package clusterprovider
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Agent ClusterDeployment", func() {
+	cti := v1alpha1.ClusterTemplateInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cti",
+			Namespace: "default",
+		},
+	}
+	provider := ClusterDeploymentProvider{
+		ClusterDeploymentName:      "foo",
+		ClusterDeploymentNamespace: "bar",
+	}
+
+	newAgent := func(name string, approved bool) *unstructured.Unstructured {
+		agent := newCAPIObject(v1alpha1.AgentGVK)
+		agent.SetName(name)
+		agent.SetNamespace("hosts")
+		agent.Object["spec"] = map[string]interface{}{
+			"approved": approved,
+			"clusterDeploymentName": map[string]interface{}{
+				"name":      "foo",
+				"namespace": "bar",
+			},
+		}
+		return agent
+	}
+
+	It("Reports host discovery and install progress", func() {
+		clusterDeployment := &hivev1.ClusterDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Spec: hivev1.ClusterDeploymentSpec{
+				ClusterInstallRef: &hivev1.ClusterInstallLocalReference{
+					Group:   "extensions.hive.openshift.io",
+					Version: "v1beta1",
+					Kind:    "AgentClusterInstall",
+					Name:    "foo",
+				},
+			},
+		}
+		agentClusterInstall := newCAPIObject(v1alpha1.AgentClusterInstallGVK)
+		agentClusterInstall.SetName("foo")
+		agentClusterInstall.SetNamespace("bar")
+		agentClusterInstall.Object["spec"] = map[string]interface{}{
+			"provisionRequirements": map[string]interface{}{
+				"controlPlaneAgents": int64(3),
+				"workerAgents":       int64(0),
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			clusterDeployment,
+			agentClusterInstall,
+			newAgent("host-0", true),
+		)
+
+		ready, msg, err := provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal("Not available - waiting for 2 hosts (1/3 bound)"))
+		platform, err := provider.GetPlatformStatus(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(platform).Should(Equal(&v1alpha1.ClusterPlatformStatus{
+			Type:           "Agent",
+			RequiredAgents: 3,
+			BoundAgents:    1,
+		}))
+
+		Expect(k8sClient.Create(context.TODO(), newAgent("host-1", true))).Should(Succeed())
+		Expect(k8sClient.Create(context.TODO(), newAgent("host-2", false))).Should(Succeed())
+		_, msg, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(msg).Should(Equal("Not available - waiting for approval of 1 hosts"))
+
+		host := newCAPIObject(v1alpha1.AgentGVK)
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: "host-2", Namespace: "hosts"},
+			host,
+		)).Should(Succeed())
+		Expect(unstructured.SetNestedField(host.Object, true, "spec", "approved")).Should(Succeed())
+		Expect(k8sClient.Update(context.TODO(), host)).Should(Succeed())
+		agentClusterInstall.Object["status"] = map[string]interface{}{
+			"debugInfo": map[string]interface{}{"state": "installing"},
+			"progress":  map[string]interface{}{"totalPercentage": int64(45)},
+		}
+		Expect(k8sClient.Update(context.TODO(), agentClusterInstall)).Should(Succeed())
+		_, msg, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(msg).Should(Equal("Not available - installing (45%)"))
+
+		agentClusterInstall.Object["status"] = map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"type":    "Failed",
+					"status":  "True",
+					"message": "host-1 failed to boot",
+				},
+			},
+		}
+		Expect(k8sClient.Update(context.TODO(), agentClusterInstall)).Should(Succeed())
+		_, msg, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(msg).Should(Equal("Not available - failed: host-1 failed to boot"))
+	})
+
+	It("Ignores ClusterDeployments installed by Hive", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, &hivev1.ClusterDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+		})
+		platform, err := provider.GetPlatformStatus(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(platform).Should(BeNil())
+	})
+})
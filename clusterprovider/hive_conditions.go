@@ -0,0 +1,96 @@
+package clusterprovider
+
+import (
+	"context"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ ClusterConditionsProvider = ClusterDeploymentProvider{}
+var _ ClusterConditionsProvider = ClusterClaimProvider{}
+
+// conditions of the ClusterDeployment mirrored to the instance, in the order they are reported
+var mirroredClusterDeploymentConditions = []struct {
+	clusterDeploymentType hivev1.ClusterDeploymentConditionType
+	instanceType          v1alpha1.ConditionType
+}{
+	{hivev1.ProvisionedCondition, v1alpha1.ClusterDeploymentProvisioned},
+	{hivev1.ProvisionFailedCondition, v1alpha1.ClusterDeploymentProvisionFailed},
+	{hivev1.ProvisionStoppedCondition, v1alpha1.ClusterDeploymentProvisionStopped},
+}
+
+// GetClusterConditions returns the Provisioned, ProvisionFailed and ProvisionStopped conditions
+// of the ClusterDeployment as conditions of the instance, they track progress of the Hive
+// install. Conditions not reported by the ClusterDeployment yet are Unknown.
+func (cd ClusterDeploymentProvider) GetClusterConditions(
+	ctx context.Context,
+	k8sClient client.Client,
+) ([]metav1.Condition, error) {
+	clusterDeployment := &hivev1.ClusterDeployment{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: cd.ClusterDeploymentName, Namespace: cd.ClusterDeploymentNamespace},
+		clusterDeployment,
+	); err != nil {
+		return nil, err
+	}
+	return getClusterDeploymentConditions(*clusterDeployment), nil
+}
+
+// GetClusterConditions returns conditions of the ClusterDeployment the claim is assigned to. No
+// conditions are returned until the claim is assigned a cluster of the pool.
+func (cc ClusterClaimProvider) GetClusterConditions(
+	ctx context.Context,
+	k8sClient client.Client,
+) ([]metav1.Condition, error) {
+	clusterClaim := hivev1.ClusterClaim{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: cc.ClusterClaimName, Namespace: cc.ClusterClaimNamespace},
+		&clusterClaim,
+	); err != nil {
+		return nil, err
+	}
+	if clusterClaim.Spec.Namespace == "" {
+		return nil, nil
+	}
+	return ClusterDeploymentProvider{
+		ClusterDeploymentName:      clusterClaim.Spec.Namespace,
+		ClusterDeploymentNamespace: clusterClaim.Spec.Namespace,
+	}.GetClusterConditions(ctx, k8sClient)
+}
+
+func getClusterDeploymentConditions(
+	clusterDeployment hivev1.ClusterDeployment,
+) []metav1.Condition {
+	conditions := []metav1.Condition{}
+	for _, mirrored := range mirroredClusterDeploymentConditions {
+		condition := metav1.Condition{
+			Type:    string(mirrored.instanceType),
+			Status:  metav1.ConditionUnknown,
+			Reason:  "NotReported",
+			Message: "ClusterDeployment does not report the condition yet",
+		}
+		for _, cdCondition := range clusterDeployment.Status.Conditions {
+			if cdCondition.Type != mirrored.clusterDeploymentType {
+				continue
+			}
+			condition.Status = metav1.ConditionStatus(cdCondition.Status)
+			condition.Reason = cdCondition.Reason
+			condition.Message = cdCondition.Message
+			condition.LastTransitionTime = cdCondition.LastTransitionTime
+			// reason of the instance condition is required
+			if condition.Reason == "" {
+				condition.Reason = string(cdCondition.Status)
+			}
+		}
+		if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions
+}
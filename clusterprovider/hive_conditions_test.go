@@ -0,0 +1,130 @@
+package clusterprovider
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClusterDeployment conditions", func() {
+	clusterDeployment := func(
+		spec hivev1.ClusterDeploymentSpec,
+		conditions ...hivev1.ClusterDeploymentCondition,
+	) *hivev1.ClusterDeployment {
+		return &hivev1.ClusterDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Spec: spec,
+			Status: hivev1.ClusterDeploymentStatus{
+				Conditions: conditions,
+			},
+		}
+	}
+	provider := ClusterDeploymentProvider{
+		ClusterDeploymentName:      "foo",
+		ClusterDeploymentNamespace: "bar",
+	}
+
+	It("Mirrors conditions of the ClusterDeployment", func() {
+		k8sClient := fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			clusterDeployment(
+				hivev1.ClusterDeploymentSpec{},
+				hivev1.ClusterDeploymentCondition{
+					Type:    hivev1.ProvisionedCondition,
+					Status:  corev1.ConditionFalse,
+					Reason:  "Provisioning",
+					Message: "Cluster is provisioning",
+				},
+				hivev1.ClusterDeploymentCondition{
+					Type:   hivev1.ProvisionFailedCondition,
+					Status: corev1.ConditionFalse,
+				},
+				hivev1.ClusterDeploymentCondition{
+					Type:   hivev1.RequirementsMetCondition,
+					Status: corev1.ConditionTrue,
+					Reason: "AllRequirementsMet",
+				},
+			),
+		)
+		conditions, err := provider.GetClusterConditions(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(conditions).Should(HaveLen(3))
+		Expect(conditions[0].Type).Should(Equal(string(v1alpha1.ClusterDeploymentProvisioned)))
+		Expect(conditions[0].Status).Should(Equal(metav1.ConditionFalse))
+		Expect(conditions[0].Reason).Should(Equal("Provisioning"))
+		Expect(conditions[0].Message).Should(Equal("Cluster is provisioning"))
+		Expect(conditions[1].Type).Should(Equal(string(v1alpha1.ClusterDeploymentProvisionFailed)))
+		Expect(conditions[1].Status).Should(Equal(metav1.ConditionFalse))
+		Expect(conditions[1].Reason).Should(Equal("False"))
+		Expect(conditions[2].Type).Should(Equal(string(v1alpha1.ClusterDeploymentProvisionStopped)))
+		Expect(conditions[2].Status).Should(Equal(metav1.ConditionUnknown))
+		Expect(conditions[2].Reason).Should(Equal("NotReported"))
+		for _, condition := range conditions {
+			Expect(condition.LastTransitionTime.IsZero()).Should(BeFalse())
+		}
+	})
+
+	It("Returns no conditions of unassigned claims", func() {
+		claim := &hivev1.ClusterClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, claim)
+		conditions, err := ClusterClaimProvider{
+			ClusterClaimName:      "foo",
+			ClusterClaimNamespace: "bar",
+		}.GetClusterConditions(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(conditions).Should(BeEmpty())
+	})
+
+	It("Reports progress of the Hive install", func() {
+		cti := v1alpha1.ClusterTemplateInstance{}
+		cd := clusterDeployment(hivev1.ClusterDeploymentSpec{})
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cd)
+		_, msg, err := provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(msg).Should(Equal("Not available"))
+
+		cd.Status.ProvisionRef = &corev1.LocalObjectReference{Name: "foo-0-abcde"}
+		Expect(k8sClient.Update(context.TODO(), cd)).Should(Succeed())
+		_, msg, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(msg).Should(Equal("Not available - provisioning"))
+
+		cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{
+			{
+				Type:    hivev1.ProvisionStoppedCondition,
+				Status:  corev1.ConditionTrue,
+				Reason:  "InstallAttemptsLimitReached",
+				Message: "Install attempts limit reached",
+			},
+		}
+		Expect(k8sClient.Update(context.TODO(), cd)).Should(Succeed())
+		ready, msg, err := provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal("Not available - provision stopped: Install attempts limit reached"))
+
+		// installed by the Hive installer, waiting for the admin secrets
+		cd.Status.Conditions = nil
+		cd.Spec.Installed = true
+		Expect(k8sClient.Update(context.TODO(), cd)).Should(Succeed())
+		ready, msg, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal("Waiting for pass/kubeconfig secrets"))
+	})
+})
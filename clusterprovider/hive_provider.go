@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -11,6 +12,29 @@ import (
 	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
 )
 
+func init() {
+	RegisterProvider(ProviderRegistration{
+		Name: "Hive",
+		GVK:  v1alpha1.ClusterDeploymentGVK,
+		Detect: func(resource argo.ResourceStatus, application argo.Application) ClusterProvider {
+			return ClusterDeploymentProvider{
+				ClusterDeploymentName:      resource.Name,
+				ClusterDeploymentNamespace: resource.Namespace,
+			}
+		},
+	})
+	RegisterProvider(ProviderRegistration{
+		Name: "Hive",
+		GVK:  v1alpha1.ClusterClaimGVK,
+		Detect: func(resource argo.ResourceStatus, application argo.Application) ClusterProvider {
+			return ClusterClaimProvider{
+				ClusterClaimName:      resource.Name,
+				ClusterClaimNamespace: resource.Namespace,
+			}
+		},
+	})
+}
+
 type ClusterDeploymentProvider struct {
 	ClusterDeploymentName      string
 	ClusterDeploymentNamespace string
@@ -30,6 +54,27 @@ func (cd ClusterDeploymentProvider) GetClusterStatus(
 		return false, "", err
 	}
 
+	// installs by the Hive installer pods set installed flag, the ClusterInstallCompleted condition
+	// is reported by installs delegated to other agents (ie the Assisted Installer)
+	if clusterDeployment.Spec.Installed {
+		return createCDSecrets(ctx, k8sClient, clusterDeployment, templateInstance)
+	}
+	for _, condition := range clusterDeployment.Status.Conditions {
+		// Hive gave up the install, it is not retried anymore
+		if condition.Type == hivev1.ProvisionStoppedCondition &&
+			condition.Status == corev1.ConditionTrue {
+			msg := condition.Message
+			if msg == "" {
+				msg = condition.Reason
+			}
+			return false, "Not available - provision stopped: " + msg, nil
+		}
+	}
+	// the Assisted Installer reports host discovery and install progress in the AgentClusterInstall
+	if status, err := getAgentInstallStatus(ctx, k8sClient, clusterDeployment); status != "" ||
+		err != nil {
+		return false, status, err
+	}
 	for _, condition := range clusterDeployment.Status.Conditions {
 		if condition.Type == hivev1.ClusterInstallCompletedClusterDeploymentCondition {
 			if condition.Status == corev1.ConditionTrue {
@@ -39,6 +84,9 @@ func (cd ClusterDeploymentProvider) GetClusterStatus(
 			}
 		}
 	}
+	if clusterDeployment.Status.ProvisionRef != nil {
+		return false, "Not available - provisioning", nil
+	}
 	return false, "Not available", nil
 }
 
@@ -0,0 +1,77 @@
+package clusterprovider
+
+import (
+	"context"
+
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClusterConditionsProvider is implemented by cluster providers which report conditions of the
+// cluster resources, they are mirrored to the conditions of the instance
+type ClusterConditionsProvider interface {
+	GetClusterConditions(ctx context.Context, k8sClient client.Client) ([]metav1.Condition, error)
+}
+
+var _ ClusterConditionsProvider = HostedClusterProvider{}
+
+// conditions of the HostedCluster mirrored to the instance, in the order they are reported
+var mirroredHostedClusterConditions = []struct {
+	hostedClusterType hypershiftv1alpha1.ConditionType
+	instanceType      v1alpha1.ConditionType
+}{
+	{hypershiftv1alpha1.HostedClusterAvailable, v1alpha1.HostedClusterAvailable},
+	{hypershiftv1alpha1.HostedClusterDegraded, v1alpha1.HostedClusterDegraded},
+	{hypershiftv1alpha1.EtcdAvailable, v1alpha1.HostedClusterEtcdAvailable},
+	{hypershiftv1alpha1.InfrastructureReady, v1alpha1.HostedClusterInfrastructureReady},
+}
+
+// GetClusterConditions returns the Available, Degraded, EtcdAvailable and InfrastructureReady
+// conditions of the HostedCluster as conditions of the instance. Conditions not reported by the
+// HostedCluster yet are Unknown.
+func (hc HostedClusterProvider) GetClusterConditions(
+	ctx context.Context,
+	k8sClient client.Client,
+) ([]metav1.Condition, error) {
+	hostedCluster := &hypershiftv1alpha1.HostedCluster{}
+	if err := hc.getHostingClient(k8sClient).Get(
+		ctx,
+		client.ObjectKey{Name: hc.HostedClusterName, Namespace: hc.HostedClusterNamespace},
+		hostedCluster,
+	); err != nil {
+		return nil, err
+	}
+	return getHostedClusterConditions(*hostedCluster), nil
+}
+
+func getHostedClusterConditions(hostedCluster hypershiftv1alpha1.HostedCluster) []metav1.Condition {
+	conditions := []metav1.Condition{}
+	for _, mirrored := range mirroredHostedClusterConditions {
+		condition := metav1.Condition{
+			Type:    string(mirrored.instanceType),
+			Status:  metav1.ConditionUnknown,
+			Reason:  "NotReported",
+			Message: "HostedCluster does not report the condition yet",
+		}
+		for _, hcCondition := range hostedCluster.Status.Conditions {
+			if hcCondition.Type != string(mirrored.hostedClusterType) {
+				continue
+			}
+			condition.Status = hcCondition.Status
+			condition.Reason = hcCondition.Reason
+			condition.Message = hcCondition.Message
+			condition.LastTransitionTime = hcCondition.LastTransitionTime
+			// reason of the instance condition is required
+			if condition.Reason == "" {
+				condition.Reason = string(hcCondition.Status)
+			}
+		}
+		if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions
+}
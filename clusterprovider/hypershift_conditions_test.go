@@ -0,0 +1,68 @@
+package clusterprovider
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("HostedCluster conditions", func() {
+	It("Mirrors conditions of the HostedCluster", func() {
+		hostedCluster := &hypershiftv1alpha1.HostedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Status: hypershiftv1alpha1.HostedClusterStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:    string(hypershiftv1alpha1.HostedClusterAvailable),
+						Status:  metav1.ConditionFalse,
+						Reason:  "WaitingForAvailable",
+						Message: "Waiting for hosted control plane to be healthy",
+					},
+					{
+						Type:    string(hypershiftv1alpha1.EtcdAvailable),
+						Status:  metav1.ConditionTrue,
+						Reason:  "QuorumAvailable",
+						Message: "Etcd has quorum",
+					},
+					{
+						Type:   string(hypershiftv1alpha1.InfrastructureReady),
+						Status: metav1.ConditionTrue,
+					},
+					{
+						Type:   string(hypershiftv1alpha1.ReconciliationActive),
+						Status: metav1.ConditionTrue,
+						Reason: "ReconciliationActive",
+					},
+				},
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, hostedCluster)
+		provider := HostedClusterProvider{
+			HostedClusterName:      "foo",
+			HostedClusterNamespace: "bar",
+		}
+		conditions, err := provider.GetClusterConditions(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(conditions).Should(HaveLen(4))
+		Expect(conditions[0].Type).Should(Equal("HostedClusterAvailable"))
+		Expect(conditions[0].Status).Should(Equal(metav1.ConditionFalse))
+		Expect(conditions[0].Reason).Should(Equal("WaitingForAvailable"))
+		Expect(conditions[0].Message).Should(Equal("Waiting for hosted control plane to be healthy"))
+		Expect(conditions[1].Type).Should(Equal("HostedClusterDegraded"))
+		Expect(conditions[1].Status).Should(Equal(metav1.ConditionUnknown))
+		Expect(conditions[1].Reason).Should(Equal("NotReported"))
+		Expect(conditions[2].Type).Should(Equal("HostedClusterEtcdAvailable"))
+		Expect(conditions[2].Reason).Should(Equal("QuorumAvailable"))
+		Expect(conditions[3].Type).Should(Equal("HostedClusterInfrastructureReady"))
+		Expect(conditions[3].Status).Should(Equal(metav1.ConditionTrue))
+		Expect(conditions[3].Reason).Should(Equal("True"))
+	})
+})
@@ -0,0 +1,142 @@
+package clusterprovider
+
+import (
+	"context"
+	"encoding/json"
+
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Annotation of the hibernated NodePool holding its scaling before the hibernation
+const HibernatedScalingAnnotation = "clustertemplate.openshift.io/hibernated-scaling"
+
+// pausedUntil value which pauses the reconciliation of the HostedCluster indefinitely
+const pausedIndefinitely = "true"
+
+// Hibernator is implemented by cluster providers which can park the cluster without deleting it
+type Hibernator interface {
+	// Hibernate scales the workers of the cluster to zero and pauses the cluster, returns true
+	// once the cluster is hibernated
+	Hibernate(ctx context.Context, k8sClient client.Client) (bool, error)
+	// Resume reverts the hibernation, returns true once the workers are back
+	Resume(ctx context.Context, k8sClient client.Client) (bool, error)
+}
+
+var _ Hibernator = HostedClusterProvider{}
+
+// scaling of a NodePool saved before the hibernation
+type hibernatedScaling struct {
+	Replicas    *int32                                  `json:"replicas,omitempty"`
+	AutoScaling *hypershiftv1alpha1.NodePoolAutoScaling `json:"autoScaling,omitempty"`
+}
+
+// Hibernate scales the NodePools created by the cluster definition to zero and, once their nodes
+// are gone, pauses the reconciliation of the HostedCluster
+func (hc HostedClusterProvider) Hibernate(
+	ctx context.Context,
+	k8sClient client.Client,
+) (bool, error) {
+	k8sClient = hc.getHostingClient(k8sClient)
+	nodePools, err := hc.getCreatedNodePools(ctx, k8sClient)
+	if err != nil {
+		return false, err
+	}
+	scaledDown := true
+	for i := range nodePools {
+		nodePool := &nodePools[i]
+		if _, ok := nodePool.Annotations[HibernatedScalingAnnotation]; !ok {
+			scaling, err := json.Marshal(hibernatedScaling{
+				Replicas:    nodePool.Spec.Replicas,
+				AutoScaling: nodePool.Spec.AutoScaling,
+			})
+			if err != nil {
+				return false, err
+			}
+			patch := client.MergeFrom(nodePool.DeepCopy())
+			if nodePool.Annotations == nil {
+				nodePool.Annotations = map[string]string{}
+			}
+			nodePool.Annotations[HibernatedScalingAnnotation] = string(scaling)
+			zero := int32(0)
+			nodePool.Spec.Replicas = &zero
+			nodePool.Spec.AutoScaling = nil
+			if err := k8sClient.Patch(ctx, nodePool, patch); err != nil {
+				return false, err
+			}
+		}
+		if nodePool.Status.Replicas > 0 {
+			scaledDown = false
+		}
+	}
+	if !scaledDown {
+		return false, nil
+	}
+
+	hostedCluster := &hypershiftv1alpha1.HostedCluster{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: hc.HostedClusterName, Namespace: hc.HostedClusterNamespace},
+		hostedCluster,
+	); err != nil {
+		return false, err
+	}
+	if hostedCluster.Spec.PausedUntil == nil {
+		patch := client.MergeFrom(hostedCluster.DeepCopy())
+		paused := pausedIndefinitely
+		hostedCluster.Spec.PausedUntil = &paused
+		if err := k8sClient.Patch(ctx, hostedCluster, patch); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Resume unpauses the HostedCluster and restores the scaling of the hibernated NodePools
+func (hc HostedClusterProvider) Resume(
+	ctx context.Context,
+	k8sClient client.Client,
+) (bool, error) {
+	k8sClient = hc.getHostingClient(k8sClient)
+	hostedCluster := &hypershiftv1alpha1.HostedCluster{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: hc.HostedClusterName, Namespace: hc.HostedClusterNamespace},
+		hostedCluster,
+	); err != nil {
+		return false, err
+	}
+	if hostedCluster.Spec.PausedUntil != nil {
+		patch := client.MergeFrom(hostedCluster.DeepCopy())
+		hostedCluster.Spec.PausedUntil = nil
+		if err := k8sClient.Patch(ctx, hostedCluster, patch); err != nil {
+			return false, err
+		}
+	}
+
+	nodePools, err := hc.getCreatedNodePools(ctx, k8sClient)
+	if err != nil {
+		return false, err
+	}
+	resumed := true
+	for i := range nodePools {
+		nodePool := &nodePools[i]
+		if value, ok := nodePool.Annotations[HibernatedScalingAnnotation]; ok {
+			scaling := hibernatedScaling{}
+			if err := json.Unmarshal([]byte(value), &scaling); err != nil {
+				return false, err
+			}
+			patch := client.MergeFrom(nodePool.DeepCopy())
+			delete(nodePool.Annotations, HibernatedScalingAnnotation)
+			nodePool.Spec.Replicas = scaling.Replicas
+			nodePool.Spec.AutoScaling = scaling.AutoScaling
+			if err := k8sClient.Patch(ctx, nodePool, patch); err != nil {
+				return false, err
+			}
+		}
+		if nodePool.Status.Replicas < getNodePoolStatus(*nodePool).DesiredReplicas {
+			resumed = false
+		}
+	}
+	return resumed, nil
+}
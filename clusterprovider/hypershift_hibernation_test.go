@@ -0,0 +1,94 @@
+package clusterprovider
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("HostedCluster hibernation", func() {
+	It("Hibernates and resumes the cluster", func() {
+		hostedCluster := &hypershiftv1alpha1.HostedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+		}
+		workers := &hypershiftv1alpha1.NodePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "workers",
+				Namespace: "bar",
+			},
+			Spec: hypershiftv1alpha1.NodePoolSpec{
+				ClusterName: "foo",
+				Replicas:    pointer.Int32(3),
+			},
+			Status: hypershiftv1alpha1.NodePoolStatus{Replicas: 3},
+		}
+		autoscaled := &hypershiftv1alpha1.NodePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "autoscaled",
+				Namespace: "bar",
+			},
+			Spec: hypershiftv1alpha1.NodePoolSpec{
+				ClusterName: "foo",
+				AutoScaling: &hypershiftv1alpha1.NodePoolAutoScaling{Min: 2, Max: 5},
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, hostedCluster, workers, autoscaled)
+		provider := HostedClusterProvider{
+			HostedClusterName:      "foo",
+			HostedClusterNamespace: "bar",
+			NodePoolNames:          []string{"workers", "autoscaled"},
+		}
+
+		hibernated, err := provider.Hibernate(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hibernated).Should(BeFalse())
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(workers), workers)).
+			Should(Succeed())
+		Expect(*workers.Spec.Replicas).Should(BeZero())
+		Expect(workers.Annotations[HibernatedScalingAnnotation]).Should(MatchJSON(`{"replicas": 3}`))
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(autoscaled), autoscaled)).
+			Should(Succeed())
+		Expect(*autoscaled.Spec.Replicas).Should(BeZero())
+		Expect(autoscaled.Spec.AutoScaling).Should(BeNil())
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(hostedCluster), hostedCluster)).
+			Should(Succeed())
+		Expect(hostedCluster.Spec.PausedUntil).Should(BeNil())
+
+		// the nodes are gone
+		workers.Status.Replicas = 0
+		Expect(k8sClient.Update(context.TODO(), workers)).Should(Succeed())
+		hibernated, err = provider.Hibernate(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hibernated).Should(BeTrue())
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(hostedCluster), hostedCluster)).
+			Should(Succeed())
+		Expect(*hostedCluster.Spec.PausedUntil).Should(Equal("true"))
+
+		resumed, err := provider.Resume(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resumed).Should(BeFalse())
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(hostedCluster), hostedCluster)).
+			Should(Succeed())
+		Expect(hostedCluster.Spec.PausedUntil).Should(BeNil())
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(workers), workers)).
+			Should(Succeed())
+		Expect(*workers.Spec.Replicas).Should(Equal(int32(3)))
+		Expect(workers.Annotations).ShouldNot(HaveKey(HibernatedScalingAnnotation))
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(autoscaled), autoscaled)).
+			Should(Succeed())
+		Expect(autoscaled.Spec.Replicas).Should(BeNil())
+		Expect(*autoscaled.Spec.AutoScaling).Should(Equal(
+			hypershiftv1alpha1.NodePoolAutoScaling{Min: 2, Max: 5},
+		))
+	})
+})
@@ -0,0 +1,155 @@
+package clusterprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/stolostron/cluster-templates-operator/argocd"
+)
+
+const (
+	// ImportedChartVersion is the version of the charts generated for imported clusters
+	ImportedChartVersion = "0.0.1"
+
+	releaseImagePlaceholder = "__release_image__"
+	replicasPlaceholder     = "__replicas_%s__"
+)
+
+// GetImportChart generates Helm chart rendering the existing HostedCluster and its NodePools as
+// they are, so a cluster which was not created by the operator can be managed by an instance.
+// The release image and the replicas of the node pools which are not autoscaled are captured in
+// the 'releaseImage' and 'nodePoolReplicas' values of the chart.
+func GetImportChart(
+	ctx context.Context,
+	k8sClient client.Client,
+	chartName string,
+	name string,
+	namespace string,
+) (*chart.Chart, error) {
+	hostedCluster := &hypershiftv1alpha1.HostedCluster{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: name, Namespace: namespace},
+		hostedCluster,
+	); err != nil {
+		return nil, err
+	}
+	if app := argocd.GetTrackingApplication(hostedCluster); app != "" {
+		return nil, fmt.Errorf(
+			"HostedCluster %s/%s is managed by ArgoCD application %s",
+			namespace,
+			name,
+			app,
+		)
+	}
+	hc := HostedClusterProvider{HostedClusterName: name, HostedClusterNamespace: namespace}
+	nodePools, err := hc.getNodePools(ctx, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+
+	placeholders := map[string]string{
+		releaseImagePlaceholder: "{{ .Values.releaseImage | quote }}",
+	}
+	values := map[string]interface{}{"releaseImage": hostedCluster.Spec.Release.Image}
+	hostedCluster.Spec.Release.Image = releaseImagePlaceholder
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(hostedCluster)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := getImportManifest(obj, "HostedCluster")
+	if err != nil {
+		return nil, err
+	}
+	templates := []*chart.File{{Name: "templates/hostedcluster.yaml", Data: manifest}}
+
+	replicas := map[string]interface{}{}
+	for i := range nodePools {
+		nodePool := &nodePools[i]
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(nodePool)
+		if err != nil {
+			return nil, err
+		}
+		if nodePool.Spec.Replicas != nil && nodePool.Spec.AutoScaling == nil {
+			placeholder := fmt.Sprintf(replicasPlaceholder, nodePool.Name)
+			placeholders[placeholder] = fmt.Sprintf(
+				"{{ index .Values.nodePoolReplicas %q }}",
+				nodePool.Name,
+			)
+			replicas[nodePool.Name] = *nodePool.Spec.Replicas
+			obj["spec"].(map[string]interface{})["replicas"] = placeholder
+		}
+		manifest, err := getImportManifest(obj, "NodePool")
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, &chart.File{
+			Name: fmt.Sprintf("templates/nodepool-%s.yaml", nodePool.Name),
+			Data: manifest,
+		})
+	}
+	if len(replicas) > 0 {
+		values["nodePoolReplicas"] = replicas
+	}
+
+	for _, template := range templates {
+		data := string(template.Data)
+		for placeholder, value := range placeholders {
+			data = strings.ReplaceAll(data, placeholder, value)
+		}
+		template.Data = []byte(data)
+	}
+	valuesData, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion:  chart.APIVersionV2,
+			Name:        chartName,
+			Version:     ImportedChartVersion,
+			Description: fmt.Sprintf("HostedCluster %s/%s imported to cluster templates", namespace, name),
+			Type:        "application",
+		},
+		Templates: templates,
+		Values:    values,
+		Raw:       []*chart.File{{Name: chartutil.ValuesfileName, Data: valuesData}},
+	}, nil
+}
+
+// getImportManifest returns manifest of the resource without its status and the metadata set by
+// the API server. Template actions of the manifest are escaped, the chart renders it as it is.
+func getImportManifest(resource map[string]interface{}, kind string) ([]byte, error) {
+	metadata, _ := resource["metadata"].(map[string]interface{})
+	importedMetadata := map[string]interface{}{
+		"name":      metadata["name"],
+		"namespace": metadata["namespace"],
+	}
+	if labels, ok := metadata["labels"]; ok {
+		importedMetadata["labels"] = labels
+	}
+	if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+		delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+		if len(annotations) > 0 {
+			importedMetadata["annotations"] = annotations
+		}
+	}
+	manifest, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion": hypershiftv1alpha1.GroupVersion.String(),
+		"kind":       kind,
+		"metadata":   importedMetadata,
+		"spec":       resource["spec"],
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strings.ReplaceAll(string(manifest), "{{", "{{`{{`}}")), nil
+}
@@ -0,0 +1,100 @@
+package clusterprovider
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/helm"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("HostedCluster import", func() {
+	getNodePool := func(name string, clusterName string) *hypershiftv1alpha1.NodePool {
+		return &hypershiftv1alpha1.NodePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "clusters",
+				ResourceVersion: "42",
+			},
+			Spec: hypershiftv1alpha1.NodePoolSpec{
+				ClusterName: clusterName,
+				Release:     hypershiftv1alpha1.Release{Image: "ocp-release:4.11.0"},
+			},
+		}
+	}
+
+	It("Generates chart of the HostedCluster", func() {
+		hostedCluster := &hypershiftv1alpha1.HostedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "foo",
+				Namespace:   "clusters",
+				Annotations: map[string]string{"note": "{{ not a template }}"},
+			},
+			Spec: hypershiftv1alpha1.HostedClusterSpec{
+				Release: hypershiftv1alpha1.Release{Image: "ocp-release:4.11.0"},
+			},
+		}
+		workers := getNodePool("workers", "foo")
+		workers.Spec.Replicas = pointer.Int32(3)
+		autoscaled := getNodePool("autoscaled", "foo")
+		autoscaled.Spec.AutoScaling = &hypershiftv1alpha1.NodePoolAutoScaling{Min: 1, Max: 5}
+		other := getNodePool("other", "bar")
+		k8sClient := fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			hostedCluster,
+			workers,
+			autoscaled,
+			other,
+		)
+
+		helmChart, err := GetImportChart(
+			context.TODO(),
+			k8sClient,
+			"imported-clusters-foo",
+			"foo",
+			"clusters",
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(helmChart.Metadata.Name).Should(Equal("imported-clusters-foo"))
+		Expect(helmChart.Templates).Should(HaveLen(3))
+		Expect(helmChart.Values).Should(Equal(map[string]interface{}{
+			"releaseImage":     "ocp-release:4.11.0",
+			"nodePoolReplicas": map[string]interface{}{"workers": int32(3)},
+		}))
+		_, err = helm.PackageChart(helmChart)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		manifests, err := helm.RenderChart(helmChart, "foo", "clusters", map[string]interface{}{
+			"releaseImage":     "ocp-release:4.12.0",
+			"nodePoolReplicas": map[string]interface{}{"workers": 5},
+		}, true)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(manifests).Should(ContainSubstring(`image: "ocp-release:4.12.0"`))
+		Expect(manifests).Should(ContainSubstring("replicas: 5"))
+		Expect(manifests).Should(ContainSubstring("note: '{{ not a template }}'"))
+		Expect(manifests).Should(ContainSubstring("max: 5"))
+		Expect(manifests).ShouldNot(ContainSubstring("name: other"))
+		Expect(manifests).ShouldNot(ContainSubstring("resourceVersion"))
+		Expect(manifests).ShouldNot(ContainSubstring("status:"))
+	})
+
+	It("Does not import HostedCluster managed by ArgoCD", func() {
+		hostedCluster := &hypershiftv1alpha1.HostedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "clusters",
+				Labels:    map[string]string{"app.kubernetes.io/instance": "foo-day1"},
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, hostedCluster)
+		_, err := GetImportChart(context.TODO(), k8sClient, "imported", "foo", "clusters")
+		Expect(err).Should(MatchError(
+			"HostedCluster clusters/foo is managed by ArgoCD application foo-day1",
+		))
+	})
+})
@@ -0,0 +1,199 @@
+package clusterprovider
+
+import (
+	"context"
+	"fmt"
+
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NodePoolStatusProvider is implemented by cluster providers which report node pools of the
+// cluster
+type NodePoolStatusProvider interface {
+	GetNodePoolStatus(
+		ctx context.Context,
+		k8sClient client.Client,
+	) ([]v1alpha1.NodePoolStatus, error)
+}
+
+// NodePoolScaler is implemented by cluster providers which can scale node pools of the cluster
+type NodePoolScaler interface {
+	ScaleNodePools(ctx context.Context, k8sClient client.Client, replicas map[string]int32) error
+}
+
+// NodePoolAutoscaler is implemented by cluster providers which can autoscale node pools of the
+// cluster
+type NodePoolAutoscaler interface {
+	AutoscaleNodePools(
+		ctx context.Context,
+		k8sClient client.Client,
+		autoscaling map[string]v1alpha1.NodePoolAutoscaling,
+	) error
+}
+
+// set on NodePools autoscaled by the operator, which can be scaled to fixed replicas again
+const autoscaledAnnotation = "clustertemplate.openshift.io/autoscaled"
+
+var _ NodePoolStatusProvider = HostedClusterProvider{}
+var _ NodePoolScaler = HostedClusterProvider{}
+var _ NodePoolAutoscaler = HostedClusterProvider{}
+
+// GetNodePoolStatus returns status of the NodePools of the HostedCluster created by the cluster
+// definition
+func (hc HostedClusterProvider) GetNodePoolStatus(
+	ctx context.Context,
+	k8sClient client.Client,
+) ([]v1alpha1.NodePoolStatus, error) {
+	if len(hc.NodePoolNames) == 0 {
+		return nil, nil
+	}
+	nodePools, err := hc.getCreatedNodePools(ctx, hc.getHostingClient(k8sClient))
+	if err != nil {
+		return nil, err
+	}
+	status := []v1alpha1.NodePoolStatus{}
+	for _, nodePool := range nodePools {
+		status = append(status, getNodePoolStatus(nodePool))
+	}
+	return status, nil
+}
+
+// ScaleNodePools sets replicas of the NodePools created by the cluster definition
+func (hc HostedClusterProvider) ScaleNodePools(
+	ctx context.Context,
+	k8sClient client.Client,
+	replicas map[string]int32,
+) error {
+	k8sClient = hc.getHostingClient(k8sClient)
+	nodePools, err := hc.getCreatedNodePools(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+	found := map[string]bool{}
+	for i := range nodePools {
+		nodePool := &nodePools[i]
+		count, ok := replicas[nodePool.Name]
+		if !ok {
+			continue
+		}
+		found[nodePool.Name] = true
+		_, autoscaledByOperator := nodePool.Annotations[autoscaledAnnotation]
+		if nodePool.Spec.AutoScaling != nil && !autoscaledByOperator {
+			return fmt.Errorf("node pool '%s' is autoscaled and can not be scaled", nodePool.Name)
+		}
+		if nodePool.Spec.AutoScaling == nil && nodePool.Spec.Replicas != nil &&
+			*nodePool.Spec.Replicas == count {
+			continue
+		}
+		patch := client.MergeFrom(nodePool.DeepCopy())
+		delete(nodePool.Annotations, autoscaledAnnotation)
+		nodePool.Spec.AutoScaling = nil
+		nodePool.Spec.Replicas = &count
+		if err := k8sClient.Patch(ctx, nodePool, patch); err != nil {
+			return err
+		}
+	}
+	for name := range replicas {
+		if !found[name] {
+			return fmt.Errorf("node pool '%s' is not created by the cluster definition", name)
+		}
+	}
+	return nil
+}
+
+// AutoscaleNodePools sets autoscaling bounds of the NodePools created by the cluster definition.
+// The NodePools are annotated, so they can be scaled to fixed replicas later.
+func (hc HostedClusterProvider) AutoscaleNodePools(
+	ctx context.Context,
+	k8sClient client.Client,
+	autoscaling map[string]v1alpha1.NodePoolAutoscaling,
+) error {
+	k8sClient = hc.getHostingClient(k8sClient)
+	nodePools, err := hc.getCreatedNodePools(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+	found := map[string]bool{}
+	for i := range nodePools {
+		nodePool := &nodePools[i]
+		bounds, ok := autoscaling[nodePool.Name]
+		if !ok {
+			continue
+		}
+		found[nodePool.Name] = true
+		current := nodePool.Spec.AutoScaling
+		if current != nil && current.Min == bounds.Min && current.Max == bounds.Max &&
+			nodePool.Spec.Replicas == nil {
+			continue
+		}
+		patch := client.MergeFrom(nodePool.DeepCopy())
+		if nodePool.Annotations == nil {
+			nodePool.Annotations = map[string]string{}
+		}
+		nodePool.Annotations[autoscaledAnnotation] = "true"
+		nodePool.Spec.AutoScaling = &hypershiftv1alpha1.NodePoolAutoScaling{
+			Min: bounds.Min,
+			Max: bounds.Max,
+		}
+		nodePool.Spec.Replicas = nil
+		if err := k8sClient.Patch(ctx, nodePool, patch); err != nil {
+			return err
+		}
+	}
+	for name := range autoscaling {
+		if !found[name] {
+			return fmt.Errorf("node pool '%s' is not created by the cluster definition", name)
+		}
+	}
+	return nil
+}
+
+// getCreatedNodePools returns the NodePools of the HostedCluster created by the cluster
+// definition
+func (hc HostedClusterProvider) getCreatedNodePools(
+	ctx context.Context,
+	k8sClient client.Client,
+) ([]hypershiftv1alpha1.NodePool, error) {
+	nodePools, err := hc.getNodePools(ctx, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+	created := map[string]bool{}
+	for _, name := range hc.NodePoolNames {
+		created[name] = true
+	}
+	createdNodePools := []hypershiftv1alpha1.NodePool{}
+	for _, nodePool := range nodePools {
+		if created[nodePool.Name] {
+			createdNodePools = append(createdNodePools, nodePool)
+		}
+	}
+	return createdNodePools, nil
+}
+
+func getNodePoolStatus(nodePool hypershiftv1alpha1.NodePool) v1alpha1.NodePoolStatus {
+	status := v1alpha1.NodePoolStatus{
+		Name:          nodePool.Name,
+		ReadyReplicas: nodePool.Status.Replicas,
+		Version:       nodePool.Status.Version,
+	}
+	switch {
+	case nodePool.Spec.Replicas != nil:
+		status.DesiredReplicas = *nodePool.Spec.Replicas
+	case nodePool.Spec.AutoScaling != nil:
+		status.DesiredReplicas = nodePool.Spec.AutoScaling.Min
+	case nodePool.Spec.NodeCount != nil:
+		status.DesiredReplicas = *nodePool.Spec.NodeCount
+	}
+	for _, condition := range nodePool.Status.Conditions {
+		status.Conditions = append(status.Conditions, v1alpha1.NodePoolCondition{
+			Type:    condition.Type,
+			Status:  string(condition.Status),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		})
+	}
+	return status
+}
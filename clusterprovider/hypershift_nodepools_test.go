@@ -0,0 +1,177 @@
+package clusterprovider
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("HostedCluster node pools", func() {
+	getNodePool := func(name string, clusterName string) *hypershiftv1alpha1.NodePool {
+		return &hypershiftv1alpha1.NodePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "bar",
+			},
+			Spec: hypershiftv1alpha1.NodePoolSpec{
+				ClusterName: clusterName,
+			},
+		}
+	}
+
+	It("Reports node pools created by the cluster definition", func() {
+		workers := getNodePool("workers", "foo")
+		workers.Spec.Replicas = pointer.Int32(3)
+		workers.Status = hypershiftv1alpha1.NodePoolStatus{
+			Replicas: 1,
+			Version:  "4.11.0",
+			Conditions: []hypershiftv1alpha1.NodePoolCondition{
+				{
+					Type:    hypershiftv1alpha1.NodePoolReadyConditionType,
+					Status:  corev1.ConditionFalse,
+					Reason:  "WaitingForNodes",
+					Message: "1 of 3 nodes joined",
+				},
+			},
+		}
+		autoscaled := getNodePool("autoscaled", "foo")
+		autoscaled.Spec.AutoScaling = &hypershiftv1alpha1.NodePoolAutoScaling{Min: 2, Max: 5}
+		// not created by the cluster definition
+		manual := getNodePool("manual", "foo")
+		// node pool of another cluster
+		other := getNodePool("other", "baz")
+
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, workers, autoscaled, manual, other)
+		provider := HostedClusterProvider{
+			HostedClusterName:      "foo",
+			HostedClusterNamespace: "bar",
+			NodePoolNames:          []string{"workers", "autoscaled", "other"},
+		}
+		nodePools, err := provider.GetNodePoolStatus(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nodePools).Should(ConsistOf(
+			v1alpha1.NodePoolStatus{
+				Name:            "workers",
+				DesiredReplicas: 3,
+				ReadyReplicas:   1,
+				Version:         "4.11.0",
+				Conditions: []v1alpha1.NodePoolCondition{
+					{
+						Type:    "Ready",
+						Status:  "False",
+						Reason:  "WaitingForNodes",
+						Message: "1 of 3 nodes joined",
+					},
+				},
+			},
+			v1alpha1.NodePoolStatus{
+				Name:            "autoscaled",
+				DesiredReplicas: 2,
+			},
+		))
+	})
+
+	It("Reports no node pools when the cluster definition has none", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, getNodePool("workers", "foo"))
+		provider := HostedClusterProvider{HostedClusterName: "foo", HostedClusterNamespace: "bar"}
+		nodePools, err := provider.GetNodePoolStatus(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(nodePools).Should(BeEmpty())
+	})
+
+	It("Scales node pools created by the cluster definition", func() {
+		workers := getNodePool("workers", "foo")
+		workers.Spec.Replicas = pointer.Int32(2)
+		autoscaled := getNodePool("autoscaled", "foo")
+		autoscaled.Spec.AutoScaling = &hypershiftv1alpha1.NodePoolAutoScaling{Min: 2, Max: 5}
+		manual := getNodePool("manual", "foo")
+
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, workers, autoscaled, manual)
+		provider := HostedClusterProvider{
+			HostedClusterName:      "foo",
+			HostedClusterNamespace: "bar",
+			NodePoolNames:          []string{"workers", "autoscaled"},
+		}
+		Expect(provider.ScaleNodePools(
+			context.TODO(),
+			k8sClient,
+			map[string]int32{"workers": 5},
+		)).Should(Succeed())
+		nodePool := &hypershiftv1alpha1.NodePool{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKeyFromObject(workers),
+			nodePool,
+		)).Should(Succeed())
+		Expect(*nodePool.Spec.Replicas).Should(Equal(int32(5)))
+
+		Expect(provider.ScaleNodePools(
+			context.TODO(),
+			k8sClient,
+			map[string]int32{"autoscaled": 3},
+		)).Should(MatchError("node pool 'autoscaled' is autoscaled and can not be scaled"))
+
+		Expect(provider.ScaleNodePools(
+			context.TODO(),
+			k8sClient,
+			map[string]int32{"manual": 3},
+		)).Should(MatchError("node pool 'manual' is not created by the cluster definition"))
+	})
+
+	It("Autoscales node pools created by the cluster definition", func() {
+		workers := getNodePool("workers", "foo")
+		workers.Spec.Replicas = pointer.Int32(2)
+		manual := getNodePool("manual", "foo")
+
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, workers, manual)
+		provider := HostedClusterProvider{
+			HostedClusterName:      "foo",
+			HostedClusterNamespace: "bar",
+			NodePoolNames:          []string{"workers"},
+		}
+		Expect(provider.AutoscaleNodePools(
+			context.TODO(),
+			k8sClient,
+			map[string]v1alpha1.NodePoolAutoscaling{"workers": {Min: 2, Max: 6}},
+		)).Should(Succeed())
+		nodePool := &hypershiftv1alpha1.NodePool{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKeyFromObject(workers),
+			nodePool,
+		)).Should(Succeed())
+		Expect(nodePool.Spec.Replicas).Should(BeNil())
+		Expect(nodePool.Spec.AutoScaling).Should(Equal(
+			&hypershiftv1alpha1.NodePoolAutoScaling{Min: 2, Max: 6},
+		))
+
+		// autoscaled by the operator, can be scaled to fixed replicas again
+		Expect(provider.ScaleNodePools(
+			context.TODO(),
+			k8sClient,
+			map[string]int32{"workers": 3},
+		)).Should(Succeed())
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKeyFromObject(workers),
+			nodePool,
+		)).Should(Succeed())
+		Expect(nodePool.Spec.AutoScaling).Should(BeNil())
+		Expect(*nodePool.Spec.Replicas).Should(Equal(int32(3)))
+
+		Expect(provider.AutoscaleNodePools(
+			context.TODO(),
+			k8sClient,
+			map[string]v1alpha1.NodePoolAutoscaling{"manual": {Min: 1, Max: 3}},
+		)).Should(MatchError("node pool 'manual' is not created by the cluster definition"))
+	})
+})
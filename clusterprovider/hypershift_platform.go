@@ -0,0 +1,111 @@
+package clusterprovider
+
+import (
+	"context"
+
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PlatformStatusProvider is implemented by cluster providers which report the infrastructure
+// platform of the cluster
+type PlatformStatusProvider interface {
+	GetPlatformStatus(
+		ctx context.Context,
+		k8sClient client.Client,
+	) (*v1alpha1.ClusterPlatformStatus, error)
+}
+
+var _ PlatformStatusProvider = HostedClusterProvider{}
+
+func (hc HostedClusterProvider) GetPlatformStatus(
+	ctx context.Context,
+	k8sClient client.Client,
+) (*v1alpha1.ClusterPlatformStatus, error) {
+	hostedCluster := &hypershiftv1alpha1.HostedCluster{}
+	if err := hc.getHostingClient(k8sClient).Get(
+		ctx,
+		client.ObjectKey{Name: hc.HostedClusterName, Namespace: hc.HostedClusterNamespace},
+		hostedCluster,
+	); err != nil {
+		return nil, err
+	}
+	return getPlatformStatus(*hostedCluster), nil
+}
+
+// getPlatformStatus extracts the platform specific details of the HostedCluster
+func getPlatformStatus(hostedCluster hypershiftv1alpha1.HostedCluster) *v1alpha1.ClusterPlatformStatus {
+	platform := hostedCluster.Spec.Platform
+	status := &v1alpha1.ClusterPlatformStatus{
+		Type:                     string(platform.Type),
+		IgnitionEndpoint:         hostedCluster.Status.IgnitionEndpoint,
+		OAuthCallbackURLTemplate: hostedCluster.Status.OAuthCallbackURLTemplate,
+	}
+	switch platform.Type {
+	case hypershiftv1alpha1.AWSPlatform:
+		if platform.AWS != nil {
+			status.Region = platform.AWS.Region
+		}
+	case hypershiftv1alpha1.AgentPlatform:
+		if platform.Agent != nil {
+			status.AgentNamespace = platform.Agent.AgentNamespace
+		}
+	}
+	return status
+}
+
+// getPlatformNotReadyMessage returns why the platform of the HostedCluster is not ready, empty if
+// it is. Conditions which were not reported yet do not block the cluster.
+func getPlatformNotReadyMessage(hostedCluster hypershiftv1alpha1.HostedCluster) string {
+	switch hostedCluster.Spec.Platform.Type {
+	case hypershiftv1alpha1.AWSPlatform:
+		if msg, ok := getFalseCondition(
+			hostedCluster,
+			hypershiftv1alpha1.PlatformCredentialsFound,
+		); ok {
+			return withMessage("AWS credentials not found", msg)
+		}
+		if msg, ok := getFalseCondition(
+			hostedCluster,
+			hypershiftv1alpha1.ValidOIDCConfiguration,
+		); ok {
+			return withMessage("Invalid OIDC configuration", msg)
+		}
+	// nodes of these platforms are booted by the user or by the platform from the ignition
+	case hypershiftv1alpha1.AgentPlatform,
+		hypershiftv1alpha1.KubevirtPlatform,
+		hypershiftv1alpha1.NonePlatform:
+		if msg, ok := getFalseCondition(
+			hostedCluster,
+			hypershiftv1alpha1.IgnitionEndpointAvailable,
+		); ok {
+			return withMessage("Waiting for ignition endpoint", msg)
+		}
+	}
+	return ""
+}
+
+// getFalseCondition returns message of the condition if its status is False
+func getFalseCondition(
+	hostedCluster hypershiftv1alpha1.HostedCluster,
+	conditionType hypershiftv1alpha1.ConditionType,
+) (string, bool) {
+	for _, condition := range hostedCluster.Status.Conditions {
+		if condition.Type == string(conditionType) && condition.Status == metav1.ConditionFalse {
+			if condition.Message != "" {
+				return condition.Message, true
+			}
+			return condition.Reason, true
+		}
+	}
+	return "", false
+}
+
+func withMessage(reason string, msg string) string {
+	if msg == "" {
+		return reason
+	}
+	return reason + " - " + msg
+}
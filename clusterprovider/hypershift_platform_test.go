@@ -0,0 +1,116 @@
+package clusterprovider
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("HostedCluster platforms", func() {
+	getPlatformHostedCluster := func(
+		platform hypershiftv1alpha1.PlatformSpec,
+		conditions ...metav1.Condition,
+	) *hypershiftv1alpha1.HostedCluster {
+		return &hypershiftv1alpha1.HostedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Spec: hypershiftv1alpha1.HostedClusterSpec{
+				Platform: platform,
+			},
+			Status: hypershiftv1alpha1.HostedClusterStatus{
+				IgnitionEndpoint:         "ignition.example.com",
+				OAuthCallbackURLTemplate: "https://oauth.example.com/[identity-provider-name]",
+				Conditions:               conditions,
+			},
+		}
+	}
+
+	It("Reports AWS platform", func() {
+		hostedCluster := getPlatformHostedCluster(hypershiftv1alpha1.PlatformSpec{
+			Type: hypershiftv1alpha1.AWSPlatform,
+			AWS:  &hypershiftv1alpha1.AWSPlatformSpec{Region: "us-east-1"},
+		})
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, hostedCluster)
+		provider := HostedClusterProvider{HostedClusterName: "foo", HostedClusterNamespace: "bar"}
+		platform, err := provider.GetPlatformStatus(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(platform).Should(Equal(&v1alpha1.ClusterPlatformStatus{
+			Type:                     "AWS",
+			Region:                   "us-east-1",
+			IgnitionEndpoint:         "ignition.example.com",
+			OAuthCallbackURLTemplate: "https://oauth.example.com/[identity-provider-name]",
+		}))
+	})
+
+	It("Reports Agent platform", func() {
+		platform := getPlatformStatus(*getPlatformHostedCluster(hypershiftv1alpha1.PlatformSpec{
+			Type:  hypershiftv1alpha1.AgentPlatform,
+			Agent: &hypershiftv1alpha1.AgentPlatformSpec{AgentNamespace: "agents"},
+		}))
+		Expect(platform.Type).Should(Equal("Agent"))
+		Expect(platform.AgentNamespace).Should(Equal("agents"))
+		Expect(platform.Region).Should(BeEmpty())
+	})
+
+	It("Reports missing AWS credentials", func() {
+		hostedCluster := getPlatformHostedCluster(
+			hypershiftv1alpha1.PlatformSpec{Type: hypershiftv1alpha1.AWSPlatform},
+			metav1.Condition{
+				Type:    string(hypershiftv1alpha1.PlatformCredentialsFound),
+				Status:  metav1.ConditionFalse,
+				Message: "secret not found",
+			},
+		)
+		Expect(getPlatformNotReadyMessage(*hostedCluster)).Should(Equal(
+			"AWS credentials not found - secret not found",
+		))
+
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, hostedCluster)
+		provider := HostedClusterProvider{HostedClusterName: "foo", HostedClusterNamespace: "bar"}
+		ready, msg, err := provider.GetClusterStatus(
+			context.TODO(),
+			k8sClient,
+			v1alpha1.ClusterTemplateInstance{},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal("Not available - AWS credentials not found - secret not found"))
+	})
+
+	It("Waits for ignition endpoint of platforms booting nodes from ignition", func() {
+		for _, platformType := range []hypershiftv1alpha1.PlatformType{
+			hypershiftv1alpha1.AgentPlatform,
+			hypershiftv1alpha1.KubevirtPlatform,
+			hypershiftv1alpha1.NonePlatform,
+		} {
+			hostedCluster := getPlatformHostedCluster(
+				hypershiftv1alpha1.PlatformSpec{Type: platformType},
+				metav1.Condition{
+					Type:   string(hypershiftv1alpha1.IgnitionEndpointAvailable),
+					Status: metav1.ConditionFalse,
+					Reason: "IgnitionServerDeploymentNotFound",
+				},
+			)
+			Expect(getPlatformNotReadyMessage(*hostedCluster)).Should(Equal(
+				"Waiting for ignition endpoint - IgnitionServerDeploymentNotFound",
+			))
+		}
+
+		hostedCluster := getPlatformHostedCluster(
+			hypershiftv1alpha1.PlatformSpec{Type: hypershiftv1alpha1.AWSPlatform},
+			metav1.Condition{
+				Type:   string(hypershiftv1alpha1.IgnitionEndpointAvailable),
+				Status: metav1.ConditionFalse,
+			},
+		)
+		Expect(getPlatformNotReadyMessage(*hostedCluster)).Should(BeEmpty())
+	})
+})
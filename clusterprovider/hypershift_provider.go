@@ -3,7 +3,10 @@ package clusterprovider
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
 	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -11,10 +14,45 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// name of the API server service in the hosted control plane namespace
+const kubeAPIServerService = "kube-apiserver"
+
 type HostedClusterProvider struct {
 	HostedClusterName      string
 	HostedClusterNamespace string
 	NodePoolNames          []string
+	// client of the remote cluster hosting the HostedCluster, the hub client is used if nil
+	HostingClient client.Client
+}
+
+func init() {
+	RegisterProvider(ProviderRegistration{
+		Name: "Hypershift",
+		GVK:  v1alpha1.HostedClusterGVK,
+		Detect: func(resource argo.ResourceStatus, application argo.Application) ClusterProvider {
+			nodePools := []string{}
+			for _, obj := range application.Status.Resources {
+				if obj.Kind == "NodePool" {
+					nodePools = append(nodePools, obj.Name)
+				}
+			}
+			// the HostedCluster (and the secrets of the cluster) may live in another namespace
+			// than the cluster definition is released to
+			return HostedClusterProvider{
+				HostedClusterName:      resource.Name,
+				HostedClusterNamespace: getApplicationNamespace(resource, application),
+				NodePoolNames:          nodePools,
+			}
+		},
+	})
+}
+
+// getHostingClient returns client of the cluster the HostedCluster lives in
+func (hc HostedClusterProvider) getHostingClient(k8sClient client.Client) client.Client {
+	if hc.HostingClient != nil {
+		return hc.HostingClient
+	}
+	return k8sClient
 }
 
 func (hc HostedClusterProvider) GetClusterStatus(
@@ -22,8 +60,9 @@ func (hc HostedClusterProvider) GetClusterStatus(
 	k8sClient client.Client,
 	templateInstance v1alpha1.ClusterTemplateInstance,
 ) (bool, string, error) {
+	hostingClient := hc.getHostingClient(k8sClient)
 	hostedCluster := &hypershiftv1alpha1.HostedCluster{}
-	if err := k8sClient.Get(
+	if err := hostingClient.Get(
 		ctx,
 		client.ObjectKey{Name: hc.HostedClusterName, Namespace: hc.HostedClusterNamespace},
 		hostedCluster,
@@ -31,6 +70,10 @@ func (hc HostedClusterProvider) GetClusterStatus(
 		return false, "", err
 	}
 
+	if msg := getPlatformNotReadyMessage(*hostedCluster); msg != "" {
+		return false, "Not available - " + msg, nil
+	}
+
 	availableCondition := metav1.Condition{}
 
 	for _, condition := range hostedCluster.Status.Conditions {
@@ -58,7 +101,7 @@ func (hc HostedClusterProvider) GetClusterStatus(
 	}
 
 	hypershiftKubeconfigSecret := corev1.Secret{}
-	if err := k8sClient.Get(
+	if err := hostingClient.Get(
 		ctx,
 		client.ObjectKey{
 			Name:      hypershiftKubeConfig,
@@ -75,8 +118,22 @@ func (hc HostedClusterProvider) GetClusterStatus(
 		return false, "", errors.New("unexpected kubeconfig format")
 	}
 
+	// services of the remote hosting cluster are not reachable from the hub
+	if hc.HostingClient == nil {
+		internalURL, err := hc.getInternalAPIServerURL(ctx, k8sClient)
+		if err != nil {
+			return false, "", err
+		}
+		if internalURL != "" {
+			kubeconfigBytes, err = AddInternalContext(kubeconfigBytes, internalURL)
+			if err != nil {
+				return false, "", err
+			}
+		}
+	}
+
 	hypershiftKubeadminSecret := corev1.Secret{}
-	if err := k8sClient.Get(
+	if err := hostingClient.Get(
 		ctx,
 		client.ObjectKey{Name: hypershiftPass, Namespace: hostedCluster.Namespace},
 		&hypershiftKubeadminSecret,
@@ -103,7 +160,7 @@ func (hc HostedClusterProvider) GetClusterStatus(
 
 	if len(hc.NodePoolNames) > 0 {
 		nodePools := &hypershiftv1alpha1.NodePoolList{}
-		if err := k8sClient.List(ctx, nodePools, &client.ListOptions{Namespace: hc.HostedClusterNamespace}); err != nil {
+		if err := hostingClient.List(ctx, nodePools, &client.ListOptions{Namespace: hc.HostedClusterNamespace}); err != nil {
 			return false, "", err
 		}
 
@@ -133,6 +190,43 @@ func (hc HostedClusterProvider) GetClusterStatus(
 	return true, "Available", nil
 }
 
+// getInternalAPIServerURL returns URL of the API server service in the control plane namespace,
+// which is reachable from the hub cluster even when the API server is not published externally
+func (hc HostedClusterProvider) getInternalAPIServerURL(
+	ctx context.Context,
+	k8sClient client.Client,
+) (string, error) {
+	controlPlaneNamespace := hc.GetControlPlaneNamespace()
+	service := &corev1.Service{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: kubeAPIServerService, Namespace: controlPlaneNamespace},
+		service,
+	); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	if len(service.Spec.Ports) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf(
+		"https://%s.%s.svc:%d",
+		kubeAPIServerService,
+		controlPlaneNamespace,
+		service.Spec.Ports[0].Port,
+	), nil
+}
+
+// GetControlPlaneNamespace returns the namespace hypershift runs the hosted control plane in. It
+// is derived from the namespace of the HostedCluster, which is not necessarily the namespace the
+// cluster definition is released to (ie charts placing the HostedCluster to 'clusters-<name>').
+func (hc HostedClusterProvider) GetControlPlaneNamespace() string {
+	return fmt.Sprintf(
+		"%s-%s",
+		hc.HostedClusterNamespace,
+		strings.ReplaceAll(hc.HostedClusterName, ".", "-"),
+	)
+}
+
 func getKubeAdminRef(hostedCluster hypershiftv1alpha1.HostedCluster) string {
 	if hostedCluster.Status.KubeadminPassword != nil {
 		return hostedCluster.Status.KubeadminPassword.Name
@@ -0,0 +1,139 @@
+package clusterprovider
+
+import (
+	"context"
+
+	configv1 "github.com/openshift/api/config/v1"
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Upgrade rolls out the release image to the HostedCluster and, once the control plane
+// finished its upgrade, to the NodePools of the cluster. Returns progress of the upgrade.
+func (hc HostedClusterProvider) Upgrade(
+	ctx context.Context,
+	k8sClient client.Client,
+	releaseImage string,
+) (*v1alpha1.ClusterUpgradeStatus, error) {
+	k8sClient = hc.getHostingClient(k8sClient)
+	hostedCluster := &hypershiftv1alpha1.HostedCluster{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: hc.HostedClusterName, Namespace: hc.HostedClusterNamespace},
+		hostedCluster,
+	); err != nil {
+		return nil, err
+	}
+	nodePools, err := hc.getNodePools(ctx, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &v1alpha1.ClusterUpgradeStatus{
+		ReleaseImage: releaseImage,
+		Phase:        v1alpha1.UpgradeProgressing,
+		Message:      "Upgrading control plane",
+		ControlPlane: &v1alpha1.ComponentUpgradeStatus{
+			Name:  hostedCluster.Name,
+			Phase: v1alpha1.UpgradeProgressing,
+		},
+		NodePools: []v1alpha1.ComponentUpgradeStatus{},
+	}
+	for _, nodePool := range nodePools {
+		status.NodePools = append(status.NodePools, v1alpha1.ComponentUpgradeStatus{
+			Name:  nodePool.Name,
+			Phase: v1alpha1.UpgradePending,
+		})
+	}
+
+	if hostedCluster.Spec.Release.Image != releaseImage {
+		patch := client.MergeFrom(hostedCluster.DeepCopy())
+		hostedCluster.Spec.Release.Image = releaseImage
+		if err := k8sClient.Patch(ctx, hostedCluster, patch); err != nil {
+			return nil, err
+		}
+		return status, nil
+	}
+
+	version, completed := getControlPlaneVersion(hostedCluster, releaseImage)
+	if !completed {
+		return status, nil
+	}
+	status.ControlPlane.Phase = v1alpha1.UpgradeCompleted
+	status.ControlPlane.Version = version
+
+	nodePoolsCompleted := true
+	for i := range nodePools {
+		nodePool := &nodePools[i]
+		if nodePool.Spec.Release.Image != releaseImage {
+			patch := client.MergeFrom(nodePool.DeepCopy())
+			nodePool.Spec.Release.Image = releaseImage
+			if err := k8sClient.Patch(ctx, nodePool, patch); err != nil {
+				return nil, err
+			}
+		}
+		status.NodePools[i].Phase = v1alpha1.UpgradeProgressing
+		if nodePool.Status.Version == version && !isNodePoolUpdating(*nodePool) {
+			status.NodePools[i].Phase = v1alpha1.UpgradeCompleted
+			status.NodePools[i].Version = version
+		} else {
+			nodePoolsCompleted = false
+		}
+	}
+	if !nodePoolsCompleted {
+		status.Message = "Upgrading node pools"
+		return status, nil
+	}
+	status.Phase = v1alpha1.UpgradeCompleted
+	status.Message = "Cluster upgraded to " + version
+	return status, nil
+}
+
+func (hc HostedClusterProvider) getNodePools(
+	ctx context.Context,
+	k8sClient client.Client,
+) ([]hypershiftv1alpha1.NodePool, error) {
+	nodePools := &hypershiftv1alpha1.NodePoolList{}
+	if err := k8sClient.List(
+		ctx,
+		nodePools,
+		&client.ListOptions{Namespace: hc.HostedClusterNamespace},
+	); err != nil {
+		return nil, err
+	}
+	clusterNodePools := []hypershiftv1alpha1.NodePool{}
+	for _, nodePool := range nodePools.Items {
+		if nodePool.Spec.ClusterName == hc.HostedClusterName {
+			clusterNodePools = append(clusterNodePools, nodePool)
+		}
+	}
+	return clusterNodePools, nil
+}
+
+// getControlPlaneVersion returns the OCP version of the release image and true if the control
+// plane finished the upgrade to it
+func getControlPlaneVersion(
+	hostedCluster *hypershiftv1alpha1.HostedCluster,
+	releaseImage string,
+) (string, bool) {
+	if hostedCluster.Status.Version == nil || len(hostedCluster.Status.Version.History) == 0 {
+		return "", false
+	}
+	// the most recent update is first
+	latest := hostedCluster.Status.Version.History[0]
+	if latest.Image != releaseImage {
+		return "", false
+	}
+	return latest.Version, latest.State == configv1.CompletedUpdate
+}
+
+func isNodePoolUpdating(nodePool hypershiftv1alpha1.NodePool) bool {
+	for _, condition := range nodePool.Status.Conditions {
+		if condition.Type == hypershiftv1alpha1.NodePoolUpdatingVersionConditionType {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
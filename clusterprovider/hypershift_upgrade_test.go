@@ -0,0 +1,114 @@
+package clusterprovider
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("HostedCluster upgrade", func() {
+	const releaseImage = "quay.io/openshift-release-dev/ocp-release:4.12.1-x86_64"
+
+	provider := HostedClusterProvider{
+		HostedClusterName:      "foo",
+		HostedClusterNamespace: "bar",
+	}
+	var hostedCluster *hypershiftv1alpha1.HostedCluster
+	var nodePool *hypershiftv1alpha1.NodePool
+
+	BeforeEach(func() {
+		Expect(hypershiftv1alpha1.AddToScheme(scheme.Scheme)).To(Succeed())
+		hostedCluster = &hypershiftv1alpha1.HostedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Spec: hypershiftv1alpha1.HostedClusterSpec{
+				Release: hypershiftv1alpha1.Release{
+					Image: "quay.io/openshift-release-dev/ocp-release:4.12.0-x86_64",
+				},
+			},
+		}
+		nodePool = &hypershiftv1alpha1.NodePool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "np1",
+				Namespace: "bar",
+			},
+			Spec: hypershiftv1alpha1.NodePoolSpec{
+				ClusterName: "foo",
+				Release:     hostedCluster.Spec.Release,
+			},
+		}
+	})
+
+	It("Upgrades control plane first", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, hostedCluster, nodePool)
+		status, err := provider.Upgrade(context.TODO(), k8sClient, releaseImage)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(status.Phase).Should(Equal(v1alpha1.UpgradeProgressing))
+		Expect(status.ControlPlane.Phase).Should(Equal(v1alpha1.UpgradeProgressing))
+		Expect(status.NodePools).Should(HaveLen(1))
+		Expect(status.NodePools[0].Phase).Should(Equal(v1alpha1.UpgradePending))
+
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(hostedCluster), hostedCluster)).
+			Should(Succeed())
+		Expect(hostedCluster.Spec.Release.Image).Should(Equal(releaseImage))
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(nodePool), nodePool)).
+			Should(Succeed())
+		Expect(nodePool.Spec.Release.Image).ShouldNot(Equal(releaseImage))
+	})
+
+	It("Upgrades node pools once control plane is upgraded", func() {
+		hostedCluster.Spec.Release.Image = releaseImage
+		hostedCluster.Status.Version = &hypershiftv1alpha1.ClusterVersionStatus{
+			History: []configv1.UpdateHistory{{
+				Image:   releaseImage,
+				Version: "4.12.1",
+				State:   configv1.CompletedUpdate,
+			}},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, hostedCluster, nodePool)
+		status, err := provider.Upgrade(context.TODO(), k8sClient, releaseImage)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(status.Phase).Should(Equal(v1alpha1.UpgradeProgressing))
+		Expect(status.ControlPlane.Phase).Should(Equal(v1alpha1.UpgradeCompleted))
+		Expect(status.ControlPlane.Version).Should(Equal("4.12.1"))
+		Expect(status.NodePools[0].Phase).Should(Equal(v1alpha1.UpgradeProgressing))
+
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(nodePool), nodePool)).
+			Should(Succeed())
+		Expect(nodePool.Spec.Release.Image).Should(Equal(releaseImage))
+	})
+
+	It("Completes upgrade when node pools are upgraded", func() {
+		hostedCluster.Spec.Release.Image = releaseImage
+		hostedCluster.Status.Version = &hypershiftv1alpha1.ClusterVersionStatus{
+			History: []configv1.UpdateHistory{{
+				Image:   releaseImage,
+				Version: "4.12.1",
+				State:   configv1.CompletedUpdate,
+			}},
+		}
+		nodePool.Spec.Release.Image = releaseImage
+		nodePool.Status.Version = "4.12.1"
+		nodePool.Status.Conditions = []hypershiftv1alpha1.NodePoolCondition{{
+			Type:   hypershiftv1alpha1.NodePoolUpdatingVersionConditionType,
+			Status: corev1.ConditionFalse,
+		}}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, hostedCluster, nodePool)
+		status, err := provider.Upgrade(context.TODO(), k8sClient, releaseImage)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(status.Phase).Should(Equal(v1alpha1.UpgradeCompleted))
+		Expect(status.NodePools[0].Phase).Should(Equal(v1alpha1.UpgradeCompleted))
+		Expect(status.NodePools[0].Version).Should(Equal("4.12.1"))
+	})
+})
@@ -0,0 +1,56 @@
+package clusterprovider
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// InternalContextSuffix is appended to the names of the current context and its cluster to name
+// the kubeconfig context which uses the API server endpoint internal to the hub cluster network
+const InternalContextSuffix = "-internal"
+
+// AddInternalContext adds a context using the internal API server URL to the kubeconfig. The current
+// context is kept, so consumers outside of the hub cluster network are not affected.
+func AddInternalContext(kubeconfig []byte, internalURL string) ([]byte, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	currentContext, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("current context %q not found in kubeconfig", config.CurrentContext)
+	}
+	cluster, ok := config.Clusters[currentContext.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("cluster %q not found in kubeconfig", currentContext.Cluster)
+	}
+
+	internalCluster := cluster.DeepCopy()
+	internalCluster.Server = internalURL
+	config.Clusters[currentContext.Cluster+InternalContextSuffix] = internalCluster
+
+	internalContext := currentContext.DeepCopy()
+	internalContext.Cluster = currentContext.Cluster + InternalContextSuffix
+	config.Contexts[config.CurrentContext+InternalContextSuffix] = internalContext
+
+	return clientcmd.Write(*config)
+}
+
+// GetInternalAPIServerURL returns the API server URL of the context added by AddInternalContext.
+// Returns empty string if the kubeconfig has no such context.
+func GetInternalAPIServerURL(kubeconfig []byte) (string, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return "", err
+	}
+	internalContext, ok := config.Contexts[config.CurrentContext+InternalContextSuffix]
+	if !ok {
+		return "", nil
+	}
+	cluster, ok := config.Clusters[internalContext.Cluster]
+	if !ok {
+		return "", nil
+	}
+	return cluster.Server, nil
+}
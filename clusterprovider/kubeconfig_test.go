@@ -0,0 +1,139 @@
+package clusterprovider
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	kubeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Kubeconfig contexts", func() {
+	var kubeconfig []byte
+
+	BeforeEach(func() {
+		var err error
+		kubeconfig, err = os.ReadFile("../testutils/kubeconfig_mock.yaml")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Adds internal context", func() {
+		internalURL := "https://kube-apiserver.bar-foo.svc:6443"
+		updated, err := AddInternalContext(kubeconfig, internalURL)
+		Expect(err).ToNot(HaveOccurred())
+
+		config, err := clientcmd.Load(updated)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.CurrentContext).Should(Equal("admin"))
+		Expect(config.Clusters["cluster"].Server).Should(Equal("https://foo.bar:6443"))
+		Expect(config.Contexts["admin-internal"].AuthInfo).Should(Equal("admin"))
+		Expect(config.Clusters["cluster-internal"].Server).Should(Equal(internalURL))
+
+		url, err := GetInternalAPIServerURL(updated)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(url).Should(Equal(internalURL))
+	})
+
+	It("Returns empty internal URL without internal context", func() {
+		url, err := GetInternalAPIServerURL(kubeconfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(url).Should(BeEmpty())
+	})
+
+	It("Adds internal context of HostedCluster", func() {
+		cti := v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cti",
+				Namespace: "bar",
+			},
+		}
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kube-apiserver",
+				Namespace: "bar-foo",
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{{Port: 6443}},
+			},
+		}
+		resources := append(getHostedCluster(ResourceOpts{
+			isReady:    true,
+			kubeadmin:  true,
+			kubeconfig: true,
+		}), service)
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, resources...)
+
+		provider := HostedClusterProvider{
+			HostedClusterName:      "foo",
+			HostedClusterNamespace: "bar",
+		}
+		ready, _, err := provider.GetClusterStatus(ctx, client, cti)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ready).Should(BeTrue())
+
+		kubeconfigSecret := &corev1.Secret{}
+		Expect(client.Get(
+			ctx,
+			kubeClient.ObjectKey{Name: cti.GetKubeconfigRef(), Namespace: cti.Namespace},
+			kubeconfigSecret,
+		)).Should(Succeed())
+		url, err := GetInternalAPIServerURL(kubeconfigSecret.Data["kubeconfig"])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(url).Should(Equal("https://kube-apiserver.bar-foo.svc:6443"))
+	})
+
+	It("Adds internal context of HostedCluster outside of the release namespace", func() {
+		cti := v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cti",
+				Namespace: "bar",
+			},
+		}
+		resources := []runtime.Object{
+			&corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kube-apiserver",
+					Namespace: "clusters-foo-foo",
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Port: 6443}},
+				},
+			},
+		}
+		// the HostedCluster and its secrets live in 'clusters-foo' instead of 'bar'
+		for _, obj := range getHostedCluster(ResourceOpts{
+			isReady:    true,
+			kubeadmin:  true,
+			kubeconfig: true,
+		}) {
+			obj.(kubeClient.Object).SetNamespace("clusters-foo")
+			resources = append(resources, obj)
+		}
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, resources...)
+
+		provider := HostedClusterProvider{
+			HostedClusterName:      "foo",
+			HostedClusterNamespace: "clusters-foo",
+		}
+		ready, _, err := provider.GetClusterStatus(ctx, client, cti)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ready).Should(BeTrue())
+
+		kubeconfigSecret := &corev1.Secret{}
+		Expect(client.Get(
+			ctx,
+			kubeClient.ObjectKey{Name: cti.GetKubeconfigRef(), Namespace: cti.Namespace},
+			kubeconfigSecret,
+		)).Should(Succeed())
+		url, err := GetInternalAPIServerURL(kubeconfigSecret.Data["kubeconfig"])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(url).Should(Equal("https://kube-apiserver.clusters-foo-foo.svc:6443"))
+	})
+})
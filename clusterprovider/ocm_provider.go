@@ -0,0 +1,93 @@
+package clusterprovider
+
+import (
+	"context"
+	"errors"
+
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/ocm"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OCMProvider reads status of a managed OpenShift cluster (OSD or ROSA) from the OpenShift
+// Cluster Manager API
+type OCMProvider struct {
+	Client      *ocm.Client
+	ClusterName string
+}
+
+var _ ConsoleURLProvider = OCMProvider{}
+var _ VersionProvider = OCMProvider{}
+
+func (o OCMProvider) GetClusterStatus(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+) (bool, string, error) {
+	cluster, err := o.Client.FindCluster(ctx, o.ClusterName)
+	if err != nil {
+		return false, "", err
+	}
+	if cluster == nil {
+		return false, "Not available - cluster " + o.ClusterName + " not found in OCM", nil
+	}
+	switch cluster.State {
+	case ocm.ClusterStateReady:
+	case ocm.ClusterStateError:
+		msg := cluster.Status.ProvisionErrorMessage
+		if msg == "" {
+			msg = cluster.Status.Description
+		}
+		return false, "Not available - failed: " + msg, nil
+	default:
+		return false, "Not available - " + cluster.State, nil
+	}
+
+	credentials, err := o.Client.GetCredentials(ctx, cluster.ID)
+	if err != nil {
+		if errors.Is(err, ocm.ErrNotFound) {
+			return false, "Waiting for cluster credentials", nil
+		}
+		return false, "", err
+	}
+	if credentials.Kubeconfig == "" {
+		return false, "Waiting for cluster credentials", nil
+	}
+	kubeconfig := []byte(credentials.Kubeconfig)
+	if credentials.Admin.User == "" {
+		if err := CreateKubeconfigSecret(ctx, k8sClient, kubeconfig, templateInstance); err != nil {
+			return false, "", err
+		}
+		return true, "Available", nil
+	}
+	if err := CreateClusterSecrets(
+		ctx,
+		k8sClient,
+		kubeconfig,
+		[]byte(credentials.Admin.User),
+		[]byte(credentials.Admin.Password),
+		templateInstance,
+	); err != nil {
+		return false, "", err
+	}
+	return true, "Available", nil
+}
+
+func (o OCMProvider) GetConsoleURL(ctx context.Context, k8sClient client.Client) (string, error) {
+	cluster, err := o.Client.FindCluster(ctx, o.ClusterName)
+	if err != nil || cluster == nil {
+		return "", err
+	}
+	return cluster.Console.URL, nil
+}
+
+func (o OCMProvider) GetOpenShiftVersion(
+	ctx context.Context,
+	k8sClient client.Client,
+) (string, error) {
+	cluster, err := o.Client.FindCluster(ctx, o.ClusterName)
+	if err != nil || cluster == nil {
+		return "", err
+	}
+	return cluster.OpenShiftVersion, nil
+}
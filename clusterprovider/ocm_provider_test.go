@@ -0,0 +1,121 @@
+package clusterprovider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/ocm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("OCM provider", func() {
+	var server *httptest.Server
+	var cluster *ocm.Cluster
+	var credentials string
+
+	BeforeEach(func() {
+		cluster = nil
+		credentials = ""
+		mux := http.NewServeMux()
+		mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"access_token":"token"}`))
+		})
+		mux.HandleFunc("/api/clusters_mgmt/v1/clusters", func(w http.ResponseWriter, r *http.Request) {
+			items := []ocm.Cluster{}
+			if cluster != nil {
+				items = append(items, *cluster)
+			}
+			Expect(json.NewEncoder(w).Encode(map[string]interface{}{"items": items})).
+				Should(Succeed())
+		})
+		mux.HandleFunc(
+			"/api/clusters_mgmt/v1/clusters/id-1/credentials",
+			func(w http.ResponseWriter, r *http.Request) {
+				if credentials == "" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(credentials))
+			},
+		)
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Tracks installation of the cluster", func() {
+		cti := v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cti",
+				Namespace: "default",
+			},
+		}
+		ocmClient, err := ocm.NewClient(server.URL, server.URL+"/token", map[string][]byte{
+			ocm.OfflineTokenKey: []byte("offline"),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		provider := OCMProvider{Client: ocmClient, ClusterName: "claas-1"}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+
+		ready, status, err := provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(status).Should(Equal("Not available - cluster claas-1 not found in OCM"))
+
+		cluster = &ocm.Cluster{ID: "id-1", Name: "claas-1", State: "installing"}
+		_, status, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(status).Should(Equal("Not available - installing"))
+
+		cluster.State = ocm.ClusterStateError
+		cluster.Status.ProvisionErrorMessage = "Insufficient quota"
+		_, status, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(status).Should(Equal("Not available - failed: Insufficient quota"))
+
+		cluster.State = ocm.ClusterStateReady
+		cluster.Console.URL = "https://console.example.com"
+		cluster.OpenShiftVersion = "4.14.1"
+		ready, status, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(status).Should(Equal("Waiting for cluster credentials"))
+
+		credentials = `{"kubeconfig":"kubeconfig","admin":{"user":"kubeadmin","password":"pass"}}`
+		ready, status, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeTrue())
+		Expect(status).Should(Equal("Available"))
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: cti.GetKubeconfigRef(), Namespace: cti.Namespace},
+			secret,
+		)).Should(Succeed())
+		Expect(secret.Data["kubeconfig"]).Should(BeEquivalentTo("kubeconfig"))
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: cti.GetKubeadminPassRef(), Namespace: cti.Namespace},
+			secret,
+		)).Should(Succeed())
+		Expect(secret.Data["password"]).Should(BeEquivalentTo("pass"))
+
+		consoleURL, err := provider.GetConsoleURL(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(consoleURL).Should(Equal("https://console.example.com"))
+		version, err := provider.GetOpenShiftVersion(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(version).Should(Equal("4.14.1"))
+	})
+})
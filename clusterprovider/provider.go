@@ -1,7 +1,9 @@
 package clusterprovider
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 
 	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
@@ -28,51 +30,21 @@ type ClusterProvider interface {
 	) (bool, string, error)
 }
 
+// GetClusterProvider returns provider of the cluster created by the application. The first resource
+// of the application detected by a registered backend decides the provider.
 func GetClusterProvider(application argo.Application) ClusterProvider {
 	for _, obj := range application.Status.Resources {
-		switch obj.Kind {
-		case v1alpha1.HostedClusterGVK.Resource:
-			if obj.Group == v1alpha1.HostedClusterGVK.Group {
-				providerLog.Info("Cluster provider: HostedCluster")
-				if obj.Version != v1alpha1.HostedClusterGVK.Version {
-					providerLog.Info("Unknown version: ", obj.Version)
-					return nil
-				}
-				nodePools := []string{}
-				for _, obj := range application.Status.Resources {
-					if obj.Kind == "NodePool" {
-						nodePools = append(nodePools, obj.Name)
-					}
-				}
-				return HostedClusterProvider{
-					HostedClusterName:      obj.Name,
-					HostedClusterNamespace: obj.Namespace,
-					NodePoolNames:          nodePools,
-				}
+		for _, registration := range registry {
+			if obj.Kind != registration.GVK.Resource || obj.Group != registration.GVK.Group {
+				continue
 			}
-		case v1alpha1.ClusterDeploymentGVK.Resource:
-			if obj.Group == v1alpha1.ClusterDeploymentGVK.Group {
-				providerLog.Info("Cluster provider: ClusterDeployment")
-				if obj.Version != v1alpha1.ClusterDeploymentGVK.Version {
-					providerLog.Info("Unknown version: ", obj.Version)
-					return nil
-				}
-				return ClusterDeploymentProvider{
-					ClusterDeploymentName:      obj.Name,
-					ClusterDeploymentNamespace: obj.Namespace,
-				}
+			if obj.Version != registration.GVK.Version {
+				providerLog.Info("Cluster provider: "+obj.Kind, "unknownVersion", obj.Version)
+				return nil
 			}
-		case v1alpha1.ClusterClaimGVK.Resource:
-			if obj.Group == v1alpha1.ClusterClaimGVK.Group {
-				providerLog.Info("Cluster provider: ClusterClaim")
-				if obj.Version != v1alpha1.ClusterClaimGVK.Version {
-					providerLog.Info("Unknown version: ", obj.Version)
-					return nil
-				}
-				return ClusterClaimProvider{
-					ClusterClaimName:      obj.Name,
-					ClusterClaimNamespace: obj.Namespace,
-				}
+			if provider := registration.Detect(obj, application); provider != nil {
+				providerLog.Info("Cluster provider: " + obj.Kind)
+				return provider
 			}
 		}
 	}
@@ -80,6 +52,20 @@ func GetClusterProvider(application argo.Application) ClusterProvider {
 	return nil
 }
 
+// WithHostingClient returns the provider reading the cluster resources by the client of the remote
+// hosting cluster. Only HostedClusters can be hosted by a remote cluster.
+func WithHostingClient(
+	provider ClusterProvider,
+	hostingClient client.Client,
+) (ClusterProvider, error) {
+	hostedCluster, ok := provider.(HostedClusterProvider)
+	if !ok {
+		return nil, fmt.Errorf("only HostedCluster resources can be installed to a hosting cluster")
+	}
+	hostedCluster.HostingClient = hostingClient
+	return hostedCluster, nil
+}
+
 func CreateClusterSecrets(
 	ctx context.Context,
 	k8sClient client.Client,
@@ -88,25 +74,8 @@ func CreateClusterSecrets(
 	kubeadminpass []byte,
 	templateInstance v1alpha1.ClusterTemplateInstance,
 ) error {
-	kubeconfigSecret := corev1.Secret{}
-	kubeconfigSecret.Name = templateInstance.GetKubeconfigRef()
-	kubeconfigSecret.Namespace = templateInstance.Namespace
-
-	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&kubeconfigSecret), &kubeconfigSecret); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return err
-		}
-
-		kubeconfigSecret.Data = map[string][]byte{
-			"kubeconfig": kubeconfig,
-		}
-		kubeconfigSecret.OwnerReferences = []metav1.OwnerReference{
-			templateInstance.GetOwnerReference(),
-		}
-
-		if err := k8sClient.Create(ctx, &kubeconfigSecret); err != nil {
-			return err
-		}
+	if err := CreateKubeconfigSecret(ctx, k8sClient, kubeconfig, templateInstance); err != nil {
+		return err
 	}
 
 	kubeadminSecret := corev1.Secret{}
@@ -129,6 +98,56 @@ func CreateClusterSecrets(
 		} else {
 			return err
 		}
+	} else if !bytes.Equal(kubeadminSecret.Data["username"], kubeadmin) ||
+		!bytes.Equal(kubeadminSecret.Data["password"], kubeadminpass) {
+		kubeadminSecret.Data = map[string][]byte{
+			"username": kubeadmin,
+			"password": kubeadminpass,
+		}
+		if err := k8sClient.Update(ctx, &kubeadminSecret); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CreateKubeconfigSecret copies the admin kubeconfig of the cluster to the kubeconfig secret of the
+// instance. Providers which do not create admin credentials (ie Cluster API) copy the kubeconfig
+// only.
+func CreateKubeconfigSecret(
+	ctx context.Context,
+	k8sClient client.Client,
+	kubeconfig []byte,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+) error {
+	kubeconfigSecret := corev1.Secret{}
+	kubeconfigSecret.Name = templateInstance.GetKubeconfigRef()
+	kubeconfigSecret.Namespace = templateInstance.Namespace
+
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&kubeconfigSecret), &kubeconfigSecret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		kubeconfigSecret.Data = map[string][]byte{
+			"kubeconfig": kubeconfig,
+		}
+		kubeconfigSecret.OwnerReferences = []metav1.OwnerReference{
+			templateInstance.GetOwnerReference(),
+		}
+
+		if err := k8sClient.Create(ctx, &kubeconfigSecret); err != nil {
+			return err
+		}
+	} else if !bytes.Equal(kubeconfigSecret.Data["kubeconfig"], kubeconfig) {
+		// the provider rotated the credentials, do not keep serving the stale ones
+		kubeconfigSecret.Data = map[string][]byte{
+			"kubeconfig": kubeconfig,
+		}
+		if err := k8sClient.Update(ctx, &kubeconfigSecret); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -92,6 +92,28 @@ var _ = Describe("Test cluster providers", func() {
 		}
 		Expect(provider).Should(Equal(expectedProvider))
 
+		app = argo.Application{
+			Spec: argo.ApplicationSpec{
+				Destination: argo.ApplicationDestination{Namespace: "clusters"},
+			},
+			Status: argo.ApplicationStatus{
+				Resources: []argo.ResourceStatus{
+					{
+						Kind:      "HostedCluster",
+						Version:   "v1alpha1",
+						Group:     "hypershift.openshift.io",
+						Name:      "foo",
+						Namespace: "clusters-foo",
+					},
+				},
+			},
+		}
+		provider = GetClusterProvider(app)
+		Expect(provider.(HostedClusterProvider).HostedClusterNamespace).Should(Equal("clusters-foo"))
+		app.Status.Resources[0].Namespace = ""
+		provider = GetClusterProvider(app)
+		Expect(provider.(HostedClusterProvider).HostedClusterNamespace).Should(Equal("clusters"))
+
 		app = argo.Application{
 			Status: argo.ApplicationStatus{
 				Resources: []argo.ResourceStatus{
@@ -206,6 +228,73 @@ var _ = Describe("Test cluster providers", func() {
 		Expect(provider).Should(BeNil())
 	})
 
+	Context("Test HostedCluster provider on hosting cluster", func() {
+		It("Reads cluster resources from hosting cluster", func() {
+			hostingClient := fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				getHostedCluster(ResourceOpts{isReady: true, kubeadmin: true, kubeconfig: true})...,
+			)
+			hubClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+			provider, err := WithHostingClient(HostedClusterProvider{
+				HostedClusterName:      "foo",
+				HostedClusterNamespace: "bar",
+			}, hostingClient)
+			Expect(err).ToNot(HaveOccurred())
+
+			ready, _, err := provider.GetClusterStatus(ctx, hubClient, cti)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ready).Should(BeTrue())
+			Expect(hubClient.Get(
+				ctx,
+				kubeClient.ObjectKey{Name: cti.GetKubeconfigRef(), Namespace: cti.Namespace},
+				&corev1.Secret{},
+			)).Should(Succeed())
+		})
+
+		It("Supports HostedCluster only", func() {
+			_, err := WithHostingClient(
+				clusterDeploymentProvider,
+				fake.NewFakeClientWithScheme(scheme.Scheme),
+			)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	It("Refreshes rotated cluster secrets", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		Expect(CreateClusterSecrets(
+			ctx,
+			k8sClient,
+			[]byte("old-kubeconfig"),
+			[]byte("kubeadmin"),
+			[]byte("old-pass"),
+			cti,
+		)).Should(Succeed())
+		Expect(CreateClusterSecrets(
+			ctx,
+			k8sClient,
+			[]byte("new-kubeconfig"),
+			[]byte("kubeadmin"),
+			[]byte("new-pass"),
+			cti,
+		)).Should(Succeed())
+
+		kubeconfigSecret := &corev1.Secret{}
+		Expect(k8sClient.Get(
+			ctx,
+			kubeClient.ObjectKey{Name: cti.GetKubeconfigRef(), Namespace: cti.Namespace},
+			kubeconfigSecret,
+		)).Should(Succeed())
+		Expect(string(kubeconfigSecret.Data["kubeconfig"])).Should(Equal("new-kubeconfig"))
+
+		kubeadminSecret := &corev1.Secret{}
+		Expect(k8sClient.Get(
+			ctx,
+			kubeClient.ObjectKey{Name: cti.GetKubeadminPassRef(), Namespace: cti.Namespace},
+			kubeadminSecret,
+		)).Should(Succeed())
+		Expect(string(kubeadminSecret.Data["password"])).Should(Equal("new-pass"))
+	})
 })
 
 func testProvider(
@@ -0,0 +1,54 @@
+package clusterprovider
+
+import (
+	"strings"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ProviderRegistration describes how a cluster backend is detected among the resources created by
+// the cluster definition. Status and credentials of the detected cluster are then read by the
+// returned ClusterProvider.
+type ProviderRegistration struct {
+	// Name of the backend reported when no backend is detected, ie 'Hive'
+	Name string
+	// Cluster resource the backend is detected by, only the listed version is supported
+	GVK schema.GroupVersionResource
+	// Detect returns the provider of the cluster resource created by the application, nil if
+	// the resource does not belong to the backend (ie a StatefulSet which is not a vcluster)
+	Detect func(resource argo.ResourceStatus, application argo.Application) ClusterProvider
+}
+
+var registry []ProviderRegistration
+
+// RegisterProvider adds a cluster backend. Backends register themselves from init functions, so new
+// backends are added without changing the controllers.
+func RegisterProvider(registration ProviderRegistration) {
+	registry = append(registry, registration)
+}
+
+// GetProviderNames returns names of the registered backends, ie 'Cluster API, Hive and Hypershift'
+func GetProviderNames() string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, registration := range registry {
+		if !seen[registration.Name] {
+			seen[registration.Name] = true
+			names = append(names, registration.Name)
+		}
+	}
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// getApplicationNamespace returns namespace of the resource, namespaced resources rendered without
+// a namespace are created in the destination namespace of the application
+func getApplicationNamespace(resource argo.ResourceStatus, application argo.Application) string {
+	if resource.Namespace != "" {
+		return resource.Namespace
+	}
+	return application.Spec.Destination.Namespace
+}
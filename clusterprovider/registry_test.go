@@ -0,0 +1,42 @@
+package clusterprovider
+
+import (
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("Provider registry", func() {
+	It("Detects clusters of registered backends", func() {
+		Expect(GetProviderNames()).Should(Equal("Cluster API, Hive, Hypershift and vcluster"))
+
+		app := argo.Application{
+			Status: argo.ApplicationStatus{
+				Resources: []argo.ResourceStatus{
+					{Group: "example.com", Version: "v1", Kind: "VirtualCluster", Name: "foo"},
+				},
+			},
+		}
+		Expect(GetClusterProvider(app)).Should(BeNil())
+
+		registered := registry
+		defer func() { registry = registered }()
+		RegisterProvider(ProviderRegistration{
+			Name: "Example",
+			GVK: schema.GroupVersionResource{
+				Group:    "example.com",
+				Version:  "v1",
+				Resource: "VirtualCluster",
+			},
+			Detect: func(resource argo.ResourceStatus, application argo.Application) ClusterProvider {
+				return HostedClusterProvider{HostedClusterName: resource.Name}
+			},
+		})
+		Expect(GetProviderNames()).Should(Equal("Cluster API, Hive, Hypershift, vcluster and Example"))
+		Expect(GetClusterProvider(app)).Should(Equal(HostedClusterProvider{HostedClusterName: "foo"}))
+
+		app.Status.Resources[0].Version = "v2"
+		Expect(GetClusterProvider(app)).Should(BeNil())
+	})
+})
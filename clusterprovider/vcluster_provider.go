@@ -0,0 +1,151 @@
+package clusterprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// vcluster runs in a StatefulSet named by the Helm release and writes the admin kubeconfig to
+// the vc-<release> secret
+const (
+	vclusterPrefix        = "vc-"
+	vclusterKubeconfigKey = "config"
+	vclusterChart         = "vcluster"
+)
+
+var vclusterStatefulSetGVK = schema.GroupVersionResource{
+	Group:    "apps",
+	Version:  "v1",
+	Resource: "StatefulSet",
+}
+
+func init() {
+	RegisterProvider(ProviderRegistration{
+		Name: "vcluster",
+		GVK:  vclusterStatefulSetGVK,
+		Detect: func(resource argo.ResourceStatus, application argo.Application) ClusterProvider {
+			if !isVCluster(resource, application) {
+				return nil
+			}
+			return VClusterProvider{
+				Name:      resource.Name,
+				Namespace: getApplicationNamespace(resource, application),
+			}
+		},
+	})
+}
+
+// isVCluster returns true if the StatefulSet was created by one of the vcluster charts
+// (vcluster, vcluster-k8s, vcluster-k0s, ...) or is accompanied by the vc-<name> service
+// account the charts create
+func isVCluster(statefulSet argo.ResourceStatus, application argo.Application) bool {
+	chart := application.Spec.Source.Chart
+	if chart == vclusterChart || strings.HasPrefix(chart, vclusterChart+"-") {
+		return true
+	}
+	for _, obj := range application.Status.Resources {
+		if obj.Group == "" && obj.Kind == "ServiceAccount" &&
+			obj.Name == vclusterPrefix+statefulSet.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// VClusterProvider reads status of a virtual cluster running in a namespace of the hub
+type VClusterProvider struct {
+	Name      string
+	Namespace string
+}
+
+func (vc VClusterProvider) GetClusterStatus(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+) (bool, string, error) {
+	statefulSet := appsv1.StatefulSet{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: vc.Name, Namespace: vc.Namespace},
+		&statefulSet,
+	); err != nil {
+		return false, "", err
+	}
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+	if statefulSet.Status.ReadyReplicas < replicas {
+		return false, fmt.Sprintf(
+			"Not available - waiting for vcluster pods (%d/%d ready)",
+			statefulSet.Status.ReadyReplicas,
+			replicas,
+		), nil
+	}
+
+	kubeconfigSecret := corev1.Secret{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: vclusterPrefix + vc.Name, Namespace: vc.Namespace},
+		&kubeconfigSecret,
+	); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, "Waiting for kubeconfig secret", nil
+		}
+		return false, "", err
+	}
+	kubeconfig, ok := kubeconfigSecret.Data[vclusterKubeconfigKey]
+	if !ok {
+		return false, "", errors.New("unexpected kubeconfig format")
+	}
+	kubeconfig, err := vc.setServiceURL(kubeconfig)
+	if err != nil {
+		return false, "", err
+	}
+
+	// vcluster has no admin password, the kubeconfig authenticates by a client certificate
+	if err := CreateKubeconfigSecret(ctx, k8sClient, kubeconfig, templateInstance); err != nil {
+		return false, "", err
+	}
+	return true, "Available", nil
+}
+
+// setServiceURL points the kubeconfig to the vcluster service. vcluster writes the kubeconfig for
+// port-forwarding (https://localhost:8443) unless the server is configured by
+// --out-kube-config-server, the service is reachable by the operator and ArgoCD on the hub.
+func (vc VClusterProvider) setServiceURL(kubeconfig []byte) ([]byte, error) {
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	currentContext, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("current context %q not found in kubeconfig", config.CurrentContext)
+	}
+	cluster, ok := config.Clusters[currentContext.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("cluster %q not found in kubeconfig", currentContext.Cluster)
+	}
+	server, err := url.Parse(cluster.Server)
+	if err != nil {
+		return nil, err
+	}
+	if host := server.Hostname(); host != "localhost" && host != "127.0.0.1" {
+		return kubeconfig, nil
+	}
+	cluster.Server = fmt.Sprintf("https://%s.%s.svc", vc.Name, vc.Namespace)
+	return clientcmd.Write(*config)
+}
@@ -0,0 +1,112 @@
+package clusterprovider
+
+import (
+	"context"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("vcluster provider", func() {
+	It("Detects vcluster StatefulSets", func() {
+		app := argo.Application{
+			Spec: argo.ApplicationSpec{
+				Destination: argo.ApplicationDestination{Namespace: "team-a"},
+			},
+			Status: argo.ApplicationStatus{
+				Resources: []argo.ResourceStatus{
+					{Group: "apps", Version: "v1", Kind: "StatefulSet", Name: "foo"},
+				},
+			},
+		}
+		Expect(GetClusterProvider(app)).Should(BeNil())
+
+		app.Status.Resources = append(app.Status.Resources, argo.ResourceStatus{
+			Version: "v1",
+			Kind:    "ServiceAccount",
+			Name:    "vc-foo",
+		})
+		Expect(GetClusterProvider(app)).Should(Equal(VClusterProvider{
+			Name:      "foo",
+			Namespace: "team-a",
+		}))
+
+		app.Status.Resources = app.Status.Resources[:1]
+		app.Spec.Source.Chart = "vcluster-k8s"
+		Expect(GetClusterProvider(app)).Should(Equal(VClusterProvider{
+			Name:      "foo",
+			Namespace: "team-a",
+		}))
+	})
+
+	It("Copies kubeconfig of the ready vcluster", func() {
+		cti := v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cti",
+				Namespace: "default",
+			},
+		}
+		statefulSet := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "team-a",
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, statefulSet)
+		provider := VClusterProvider{Name: "foo", Namespace: "team-a"}
+
+		ready, msg, err := provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal("Not available - waiting for vcluster pods (0/1 ready)"))
+
+		statefulSet.Status.ReadyReplicas = 1
+		Expect(k8sClient.Status().Update(context.TODO(), statefulSet)).Should(Succeed())
+		ready, msg, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal("Waiting for kubeconfig secret"))
+
+		config := clientcmdapi.NewConfig()
+		config.Clusters["my-vcluster"] = &clientcmdapi.Cluster{Server: "https://localhost:8443"}
+		config.AuthInfos["my-vcluster"] = &clientcmdapi.AuthInfo{Token: "token"}
+		config.Contexts["my-vcluster"] = &clientcmdapi.Context{
+			Cluster:  "my-vcluster",
+			AuthInfo: "my-vcluster",
+		}
+		config.CurrentContext = "my-vcluster"
+		kubeconfig, err := clientcmd.Write(*config)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(k8sClient.Create(context.TODO(), &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "vc-foo",
+				Namespace: "team-a",
+			},
+			Data: map[string][]byte{"config": kubeconfig},
+		})).Should(Succeed())
+		ready, msg, err = provider.GetClusterStatus(context.TODO(), k8sClient, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeTrue())
+		Expect(msg).Should(Equal("Available"))
+
+		kubeconfigSecret := corev1.Secret{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: cti.GetKubeconfigRef(), Namespace: "default"},
+			&kubeconfigSecret,
+		)).Should(Succeed())
+		copied, err := clientcmd.Load(kubeconfigSecret.Data["kubeconfig"])
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(copied.Clusters["my-vcluster"].Server).Should(Equal("https://foo.team-a.svc"))
+	})
+})
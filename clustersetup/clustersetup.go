@@ -7,8 +7,10 @@ import (
 	"fmt"
 
 	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	argoAppSet "github.com/argoproj/applicationset/pkg/utils"
 	"github.com/kubernetes-client/go-base/config/api"
@@ -25,7 +27,10 @@ type ClusterConfig struct {
 }
 
 type TLSClientConfig struct {
-	CAData string `json:"caData"`
+	CAData   string `json:"caData"`
+	CertData string `json:"certData,omitempty"`
+	KeyData  string `json:"keyData,omitempty"`
+	Insecure bool   `json:"insecure,omitempty"`
 }
 
 func AddClusterToArgo(
@@ -173,6 +178,66 @@ func AddClusterToArgo(
 	return ensureResourceExists(ctx, k8sClient, clusterSecret, false)
 }
 
+// GetHostingClusterSecretName returns name of the ArgoCD cluster secret of the hosting cluster
+// whose kubeconfig is stored in the kubeconfig secret
+func GetHostingClusterSecretName(kubeconfigSecret string) string {
+	return "claas-hosting-" + kubeconfigSecret
+}
+
+// AddHostingClusterToArgo registers the remote hosting cluster in ArgoCD, so cluster definitions
+// can be synced to it. The cluster secret is shared by all instances installed to the hosting
+// cluster and is updated when the credentials change. Returns the server URL of the cluster.
+func AddHostingClusterToArgo(
+	ctx context.Context,
+	k8sClient client.Client,
+	restConfig *rest.Config,
+	kubeconfigSecret string,
+	argoCDNamespace string,
+) (string, error) {
+	if restConfig.BearerToken == "" && len(restConfig.CertData) == 0 {
+		return "", fmt.Errorf(
+			"kubeconfig of hosting cluster %s has neither token nor client certificate",
+			kubeconfigSecret,
+		)
+	}
+	config := ClusterConfig{
+		BearerToken: restConfig.BearerToken,
+		TLSClientConfig: TLSClientConfig{
+			CAData:   base64.StdEncoding.EncodeToString(restConfig.CAData),
+			CertData: base64.StdEncoding.EncodeToString(restConfig.CertData),
+			KeyData:  base64.StdEncoding.EncodeToString(restConfig.KeyData),
+			Insecure: restConfig.Insecure,
+		},
+	}
+	jsonConfig, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+
+	clusterSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetHostingClusterSecretName(kubeconfigSecret),
+			Namespace: argoCDNamespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, k8sClient, clusterSecret, func() error {
+		if clusterSecret.Labels == nil {
+			clusterSecret.Labels = map[string]string{}
+		}
+		clusterSecret.Labels[argoAppSet.ArgoCDSecretTypeLabel] = argoAppSet.ArgoCDSecretTypeCluster
+		clusterSecret.Type = corev1.SecretTypeOpaque
+		clusterSecret.Data = map[string][]byte{
+			"name":   []byte("hosting/" + kubeconfigSecret),
+			"server": []byte(restConfig.Host),
+			"config": jsonConfig,
+		}
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	return restConfig.Host, nil
+}
+
 func ensureResourceExists(
 	ctx context.Context,
 	newClusterClient client.Client,
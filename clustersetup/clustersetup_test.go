@@ -1,6 +1,8 @@
 package clustersetup
 
 import (
+	"encoding/json"
+
 	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/kubernetes-client/go-base/config/api"
 	. "github.com/onsi/ginkgo"
@@ -11,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -90,4 +93,31 @@ var _ = Describe("Test cluster setup", func() {
 		)
 		Expect(err).Should(BeNil())
 	})
+	It("AddHostingClusterToArgo", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		restConfig := &rest.Config{Host: "https://api.hosting.example.com:6443"}
+		_, err := AddHostingClusterToArgo(ctx, k8sClient, restConfig, "hosting", "argocd")
+		Expect(err).Should(HaveOccurred())
+
+		restConfig.BearerToken = "token"
+		server, err := AddHostingClusterToArgo(ctx, k8sClient, restConfig, "hosting", "argocd")
+		Expect(err).Should(BeNil())
+		Expect(server).Should(Equal(restConfig.Host))
+
+		restConfig.BearerToken = "rotated"
+		_, err = AddHostingClusterToArgo(ctx, k8sClient, restConfig, "hosting", "argocd")
+		Expect(err).Should(BeNil())
+
+		argoClusterSecret := &corev1.Secret{}
+		err = k8sClient.Get(
+			ctx,
+			types.NamespacedName{Name: GetHostingClusterSecretName("hosting"), Namespace: "argocd"},
+			argoClusterSecret,
+		)
+		Expect(err).Should(BeNil())
+		Expect(string(argoClusterSecret.Data["server"])).Should(Equal(restConfig.Host))
+		config := ClusterConfig{}
+		Expect(json.Unmarshal(argoClusterSecret.Data["config"], &config)).Should(Succeed())
+		Expect(config.BearerToken).Should(Equal("rotated"))
+	})
 })
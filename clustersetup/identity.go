@@ -0,0 +1,116 @@
+package clustersetup
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// namespace of the setup ServiceAccounts on the new cluster
+const setupServiceAccountNamespace = "kube-system"
+
+// GetSetupServiceAccountName returns name of the ServiceAccount of the cluster setup created on
+// the new cluster
+func GetSetupServiceAccountName(setupName string) string {
+	return "claas-setup-" + setupName
+}
+
+// GetSetupServiceAccountKubeconfig creates a ServiceAccount of the cluster setup on the new
+// cluster, allowed by the rules only, and returns kubeconfig authenticating by its token. The
+// rules are updated if they changed.
+func GetSetupServiceAccountKubeconfig(
+	ctx context.Context,
+	newClusterClient client.Client,
+	adminKubeconfig []byte,
+	setupName string,
+	rules []rbacv1.PolicyRule,
+) ([]byte, error) {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetSetupServiceAccountName(setupName),
+			Namespace: setupServiceAccountNamespace,
+		},
+	}
+	if err := ensureResourceExists(ctx, newClusterClient, sa, false); err != nil {
+		return nil, err
+	}
+
+	clusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: sa.Name,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, newClusterClient, clusterRole, func() error {
+		clusterRole.Rules = rules
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: sa.Name,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     clusterRole.Name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      sa.Name,
+				Namespace: sa.Namespace,
+			},
+		},
+	}
+	if err := ensureResourceExists(ctx, newClusterClient, clusterRoleBinding, false); err != nil {
+		return nil, err
+	}
+
+	tokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sa.Name + "-token",
+			Namespace: sa.Namespace,
+			Annotations: map[string]string{
+				corev1.ServiceAccountNameKey: sa.Name,
+			},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}
+	if err := ensureResourceExists(ctx, newClusterClient, tokenSecret, true); err != nil {
+		return nil, err
+	}
+	if len(tokenSecret.Data["token"]) == 0 {
+		return nil, fmt.Errorf("token not found")
+	}
+	if len(tokenSecret.Data["ca.crt"]) == 0 {
+		return nil, fmt.Errorf("ca.crt not found")
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(adminKubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters["cluster"] = &clientcmdapi.Cluster{
+		Server:                   restConfig.Host,
+		CertificateAuthorityData: tokenSecret.Data["ca.crt"],
+	}
+	kubeconfig.AuthInfos[sa.Name] = &clientcmdapi.AuthInfo{
+		Token: string(tokenSecret.Data["token"]),
+	}
+	kubeconfig.Contexts[sa.Name] = &clientcmdapi.Context{
+		Cluster:  "cluster",
+		AuthInfo: sa.Name,
+	}
+	kubeconfig.CurrentContext = sa.Name
+	return clientcmd.Write(*kubeconfig)
+}
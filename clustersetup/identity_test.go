@@ -0,0 +1,100 @@
+package clustersetup
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Cluster setup identity", func() {
+	adminKubeconfig := []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: foo
+  cluster:
+    server: https://api.foo.example.com:6443
+users:
+- name: admin
+  user:
+    token: admin-token
+contexts:
+- name: admin
+  context:
+    cluster: foo
+    user: admin
+current-context: admin
+`)
+
+	It("GetSetupServiceAccountKubeconfig", func() {
+		tokenSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "claas-setup-logging-token",
+				Namespace: "kube-system",
+			},
+			Type: corev1.SecretTypeServiceAccountToken,
+			Data: map[string][]byte{
+				"token":  []byte("sa-token"),
+				"ca.crt": []byte("ca.crt"),
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, tokenSecret)
+		rules := []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"logging.openshift.io"},
+				Resources: []string{"clusterloggings"},
+				Verbs:     []string{"*"},
+			},
+		}
+		data, err := GetSetupServiceAccountKubeconfig(
+			context.TODO(),
+			k8sClient,
+			adminKubeconfig,
+			"logging",
+			rules,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		restConfig, err := clientcmd.RESTConfigFromKubeConfig(data)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(restConfig.Host).Should(Equal("https://api.foo.example.com:6443"))
+		Expect(restConfig.BearerToken).Should(Equal("sa-token"))
+		Expect(restConfig.CAData).Should(Equal([]byte("ca.crt")))
+
+		clusterRole := &rbacv1.ClusterRole{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: "claas-setup-logging"},
+			clusterRole,
+		)).Should(Succeed())
+		Expect(clusterRole.Rules).Should(Equal(rules))
+
+		clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: "claas-setup-logging"},
+			clusterRoleBinding,
+		)).Should(Succeed())
+		Expect(clusterRoleBinding.Subjects[0].Name).Should(Equal("claas-setup-logging"))
+		Expect(clusterRoleBinding.Subjects[0].Namespace).Should(Equal("kube-system"))
+	})
+
+	It("GetSetupServiceAccountKubeconfig fails without token", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		_, err := GetSetupServiceAccountKubeconfig(
+			context.TODO(),
+			k8sClient,
+			adminKubeconfig,
+			"logging",
+			nil,
+		)
+		Expect(err).Should(MatchError("token not found"))
+	})
+})
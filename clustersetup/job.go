@@ -0,0 +1,167 @@
+package clustersetup
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+)
+
+const (
+	// directory the kubeconfig of the new cluster is mounted to in setup Jobs
+	setupJobKubeconfigDir = "/etc/claas"
+	// key of the kubeconfig in the kubeconfig Secrets of the instance
+	setupJobKubeconfigKey = "kubeconfig"
+	// retries of setup Jobs unless the template sets the backoff limit
+	defaultSetupJobBackoffLimit = int32(3)
+	// namespace of setup Jobs run on the new cluster
+	SetupJobClusterNamespace = "cluster-setup"
+)
+
+// NewSetupJob returns the Job of the cluster setup of the instance, of its retry if retry is not
+// 0. The Job runs the script of the setup with the kubeconfig of the new cluster mounted - of the
+// setup identity if the setup defines one, the admin kubeconfig otherwise. Jobs run on the new
+// cluster are not owned by the instance, which lives on the hub, and mount a copy of the
+// kubeconfig named after the Job.
+func NewSetupJob(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setup v1alpha1.ClusterSetup,
+	retry int,
+) *batchv1.Job {
+	name := clusterTemplateInstance.GetSetupJobName(setup.Name)
+	if retry > 0 {
+		name = clusterTemplateInstance.GetSetupJobRetryName(setup.Name, retry)
+	}
+	kubeconfigSecret := GetSetupJobKubeconfigRef(clusterTemplateInstance, setup)
+	backoffLimit := defaultSetupJobBackoffLimit
+	if setup.Job.BackoffLimit != nil {
+		backoffLimit = *setup.Job.BackoffLimit
+	}
+	labels := clusterTemplateInstance.GetInstanceLabels(setup.Name)
+	ownerReferences := []metav1.OwnerReference{clusterTemplateInstance.GetOwnerReference()}
+	if setup.Job.RunOnCluster {
+		ownerReferences = nil
+		kubeconfigSecret = name
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       GetSetupJobNamespace(clusterTemplateInstance, setup),
+			Labels:          labels,
+			OwnerReferences: ownerReferences,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{
+						{
+							Name:    "setup",
+							Image:   setup.Job.Image,
+							Command: []string{"/bin/sh", "-c", setup.Job.Script},
+							Env: []corev1.EnvVar{
+								{
+									Name:  "KUBECONFIG",
+									Value: path.Join(setupJobKubeconfigDir, setupJobKubeconfigKey),
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "kubeconfig",
+									MountPath: setupJobKubeconfigDir,
+									ReadOnly:  true,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "kubeconfig",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: kubeconfigSecret,
+									Items: []corev1.KeyToPath{
+										{Key: setupJobKubeconfigKey, Path: setupJobKubeconfigKey},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// GetSetupJobKubeconfigRef returns name of the Secret in the namespace of the instance with the
+// kubeconfig the Jobs of the cluster setup use
+func GetSetupJobKubeconfigRef(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setup v1alpha1.ClusterSetup,
+) string {
+	if setup.Identity != nil {
+		return clusterTemplateInstance.GetSetupCredentialsRef(setup.Name)
+	}
+	return clusterTemplateInstance.GetKubeconfigRef()
+}
+
+// GetSetupJobNamespace returns namespace of Jobs of the cluster setup, on the hub or on the new
+// cluster
+func GetSetupJobNamespace(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setup v1alpha1.ClusterSetup,
+) string {
+	if setup.Job.RunOnCluster {
+		return SetupJobClusterNamespace
+	}
+	return clusterTemplateInstance.Namespace
+}
+
+// GetSetupJobStatus returns status of the cluster setup run by the Job. Jobs report the statuses
+// of ArgoCD Applications, so setups of both kinds are aggregated the same way - a running Job is
+// syncing, a failed one failed to sync.
+func GetSetupJobStatus(job *batchv1.Job) (argocd.ApplicationStatus, string) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return argocd.ApplicationHealthy, "Job completed"
+		case batchv1.JobFailed:
+			msg := condition.Message
+			if msg == "" {
+				msg = condition.Reason
+			}
+			return argocd.ApplicationSyncFailed, "Job failed - " + msg
+		}
+	}
+	if job.Status.Failed > 0 {
+		return argocd.ApplicationSyncRunning, fmt.Sprintf(
+			"Job is running (%d failed attempts)",
+			job.Status.Failed,
+		)
+	}
+	return argocd.ApplicationSyncRunning, "Job is running"
+}
+
+// GetSetupJobFailedTime returns when the Job failed, zero time if it did not fail
+func GetSetupJobFailedTime(job *batchv1.Job) time.Time {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return condition.LastTransitionTime.Time
+		}
+	}
+	return time.Time{}
+}
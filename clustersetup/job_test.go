@@ -0,0 +1,94 @@
+package clustersetup
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+)
+
+var _ = Describe("Cluster setup Job", func() {
+	cti := &v1alpha1.ClusterTemplateInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+		},
+	}
+	setup := v1alpha1.ClusterSetup{
+		Name: "monitoring",
+		Job: &v1alpha1.SetupJob{
+			Image:  "quay.io/openshift/origin-cli:latest",
+			Script: "oc apply -f monitoring.yaml",
+		},
+	}
+
+	It("Mounts kubeconfig of the new cluster", func() {
+		job := NewSetupJob(cti, setup, 0)
+		Expect(job.Name).Should(Equal("foo-setup-monitoring"))
+		Expect(job.Namespace).Should(Equal("default"))
+		Expect(job.Labels).Should(HaveKeyWithValue(v1alpha1.CTISetupLabel, "monitoring"))
+		Expect(job.OwnerReferences).Should(HaveLen(1))
+		Expect(*job.Spec.BackoffLimit).Should(Equal(int32(3)))
+
+		container := job.Spec.Template.Spec.Containers[0]
+		Expect(container.Image).Should(Equal("quay.io/openshift/origin-cli:latest"))
+		Expect(container.Command).Should(Equal(
+			[]string{"/bin/sh", "-c", "oc apply -f monitoring.yaml"},
+		))
+		Expect(container.Env).Should(ContainElement(
+			corev1.EnvVar{Name: "KUBECONFIG", Value: "/etc/claas/kubeconfig"},
+		))
+		Expect(job.Spec.Template.Spec.Volumes[0].Secret.SecretName).
+			Should(Equal(cti.GetKubeconfigRef()))
+
+		identitySetup := *setup.DeepCopy()
+		identitySetup.Identity = &v1alpha1.SetupIdentity{Type: v1alpha1.KubeconfigIdentity}
+		identitySetup.Job.BackoffLimit = new(int32)
+		job = NewSetupJob(cti, identitySetup, 0)
+		Expect(job.Spec.Template.Spec.Volumes[0].Secret.SecretName).
+			Should(Equal(cti.GetSetupCredentialsRef("monitoring")))
+		Expect(*job.Spec.BackoffLimit).Should(Equal(int32(0)))
+
+		clusterSetup := *setup.DeepCopy()
+		clusterSetup.Job.RunOnCluster = true
+		job = NewSetupJob(cti, clusterSetup, 0)
+		Expect(job.Namespace).Should(Equal(SetupJobClusterNamespace))
+		Expect(job.OwnerReferences).Should(BeEmpty())
+		Expect(job.Spec.Template.Spec.Volumes[0].Secret.SecretName).Should(Equal(job.Name))
+	})
+
+	It("Reports status of the Job", func() {
+		job := NewSetupJob(cti, setup, 0)
+		status, msg := GetSetupJobStatus(job)
+		Expect(status).Should(Equal(argocd.ApplicationSyncRunning))
+		Expect(msg).Should(Equal("Job is running"))
+
+		job.Status.Failed = 2
+		status, msg = GetSetupJobStatus(job)
+		Expect(status).Should(Equal(argocd.ApplicationSyncRunning))
+		Expect(msg).Should(Equal("Job is running (2 failed attempts)"))
+
+		job.Status.Conditions = []batchv1.JobCondition{
+			{
+				Type:    batchv1.JobFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  "BackoffLimitExceeded",
+				Message: "Job has reached the specified backoff limit",
+			},
+		}
+		status, msg = GetSetupJobStatus(job)
+		Expect(status).Should(Equal(argocd.ApplicationSyncFailed))
+		Expect(msg).Should(Equal("Job failed - Job has reached the specified backoff limit"))
+
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+		}
+		status, msg = GetSetupJobStatus(job)
+		Expect(status).Should(Equal(argocd.ApplicationHealthy))
+		Expect(msg).Should(Equal("Job completed"))
+	})
+})
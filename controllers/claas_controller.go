@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"os"
+	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -33,6 +34,7 @@ import (
 
 	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
 	"github.com/stolostron/cluster-templates-operator/controllers/defaultresources"
+	"github.com/stolostron/cluster-templates-operator/helm"
 )
 
 var (
@@ -40,9 +42,25 @@ var (
 	ctiControllerCancel context.CancelFunc
 )
 
+const (
+	// how often optional CRDs which are not established yet are checked
+	crdEstablishedCheckInterval = 10 * time.Second
+	// how long to wait before the failed cti-controller is started again
+	ctiControllerRetryInterval = 30 * time.Second
+)
+
+// CRDs of the optional providers and integrations, their watches are started once the CRD is
+// established
+var optionalCRDs = []schema.GroupVersionResource{
+	v1alpha1.HostedClusterGVK,
+	v1alpha1.ClusterDeploymentGVK,
+	v1alpha1.ConsolePluginGVK,
+}
+
 type CLaaSReconciler struct {
 	Manager ctrl.Manager
 	client.Client
+	HelmClient          *helm.HelmClient
 	enableHypershift    bool
 	enableHive          bool
 	enableConsolePlugin bool
@@ -63,12 +81,23 @@ func (r *CLaaSReconciler) Reconcile(
 		return ctrl.Result{}, err
 	}
 
+	// watches of a CRD which is not served yet fail, the CRD is checked again later
+	if isOptionalCRD(crd) && !isCRDEstablished(crd) {
+		CLaaSlog.Info("crd not established yet", "name", crd.Name)
+		return ctrl.Result{RequeueAfter: crdEstablishedCheckInterval}, nil
+	}
+
 	//restart controller if needed
 
 	if !r.enableHypershift && isCRDSupported(crd, v1alpha1.HostedClusterGVK) {
 		r.enableHypershift = true
 		ctiControllerCancel()
-		ctiControllerCancel = StartCTIController(r.Manager, r.enableHypershift, r.enableHive)
+		ctiControllerCancel = StartCTIController(
+			r.Manager,
+			r.HelmClient,
+			r.enableHypershift,
+			r.enableHive,
+		)
 
 		if err := (&defaultresources.HypershiftTemplateReconciler{
 			Client: r.Manager.GetClient(),
@@ -82,13 +111,12 @@ func (r *CLaaSReconciler) Reconcile(
 	if !r.enableHive && isCRDSupported(crd, v1alpha1.ClusterDeploymentGVK) {
 		r.enableHive = true
 		ctiControllerCancel()
-		ctiControllerCancel = StartCTIController(r.Manager, r.enableHypershift, r.enableHive)
-	}
-
-	if !r.enableHive && isCRDSupported(crd, v1alpha1.ClusterDeploymentGVK) {
-		r.enableHive = true
-		ctiControllerCancel()
-		ctiControllerCancel = StartCTIController(r.Manager, r.enableHypershift, r.enableHive)
+		ctiControllerCancel = StartCTIController(
+			r.Manager,
+			r.HelmClient,
+			r.enableHypershift,
+			r.enableHive,
+		)
 	}
 
 	if !r.enableConsolePlugin && isCRDSupported(crd, v1alpha1.ConsolePluginGVK) {
@@ -113,7 +141,12 @@ func (r *CLaaSReconciler) SetupWithManager() error {
 	r.enableHive = isCRDAvailable(client, v1alpha1.ClusterDeploymentGVK)
 	r.enableConsolePlugin = isCRDAvailable(client, v1alpha1.ConsolePluginGVK)
 
-	ctiControllerCancel = StartCTIController(r.Manager, r.enableHypershift, r.enableHive)
+	ctiControllerCancel = StartCTIController(
+		r.Manager,
+		r.HelmClient,
+		r.enableHypershift,
+		r.enableHive,
+	)
 
 	if r.enableHypershift {
 		if err := (&defaultresources.HypershiftTemplateReconciler{
@@ -145,8 +178,9 @@ func (r *CLaaSReconciler) SetupWithManager() error {
 				CreateFunc: func(e event.CreateEvent) bool {
 					return true
 				},
+				// CRDs are established after they are created
 				UpdateFunc: func(e event.UpdateEvent) bool {
-					return false
+					return true
 				},
 				GenericFunc: func(e event.GenericEvent) bool {
 					return false
@@ -180,3 +214,22 @@ func isCRDSupported(
 	}
 	return false
 }
+
+func isOptionalCRD(crd *apiextensions.CustomResourceDefinition) bool {
+	for _, gvk := range optionalCRDs {
+		if isCRDSupported(crd, gvk) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCRDEstablished returns true once the API server serves resources of the CRD
+func isCRDEstablished(crd *apiextensions.CustomResourceDefinition) bool {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensions.Established {
+			return condition.Status == apiextensions.ConditionTrue
+		}
+	}
+	return false
+}
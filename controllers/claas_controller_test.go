@@ -184,6 +184,33 @@ var _ = Describe("CLaaS controller", func() {
 	})
 })
 
+var _ = Describe("Optional CRDs", func() {
+	It("Waits for optional CRDs to be established", func() {
+		crd := &apiextensions.CustomResourceDefinition{
+			Spec: apiextensions.CustomResourceDefinitionSpec{
+				Group: v1alpha1.HostedClusterGVK.Group,
+				Versions: []apiextensions.CustomResourceDefinitionVersion{
+					{Name: v1alpha1.HostedClusterGVK.Version},
+				},
+				Names: apiextensions.CustomResourceDefinitionNames{
+					Kind: v1alpha1.HostedClusterGVK.Resource,
+				},
+			},
+		}
+		Expect(isOptionalCRD(crd)).Should(BeTrue())
+		Expect(isCRDEstablished(crd)).Should(BeFalse())
+
+		crd.Status.Conditions = []apiextensions.CustomResourceDefinitionCondition{
+			{Type: apiextensions.NamesAccepted, Status: apiextensions.ConditionTrue},
+			{Type: apiextensions.Established, Status: apiextensions.ConditionTrue},
+		}
+		Expect(isCRDEstablished(crd)).Should(BeTrue())
+
+		crd.Spec.Group = "example.com"
+		Expect(isOptionalCRD(crd)).Should(BeFalse())
+	})
+})
+
 func startTestEnv(crds []string) {
 	claasCtx, claasCtxCancel = context.WithCancel(context.TODO())
 	crdPaths := []string{
@@ -0,0 +1,372 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+const (
+	// key of the access log ConfigMap holding one line per issued credentials
+	accessLogKey = "access.log"
+	// number of entries kept in the access log, older are dropped
+	maxAccessLogEntries = 100
+	// how long the requester can read the credentials if the request does not set it
+	defaultCredentialsTTL = time.Hour
+)
+
+var CCRLog = logf.Log.WithName("ccr-controller")
+
+// ClusterCredentialRequestReconciler reconciles a ClusterCredentialRequest object
+type ClusterCredentialRequestReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustercredentialrequests,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustercredentialrequests/status,verbs=get;update;patch
+
+func (r *ClusterCredentialRequestReconciler) Reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+) (ctrl.Result, error) {
+	ccr := &v1alpha1.ClusterCredentialRequest{}
+	if err := r.Get(ctx, req.NamespacedName, ccr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// credentials are issued only once, then they are revoked once they expire
+	if ccr.Status.IssueTime != nil {
+		return r.revokeExpiredCredentials(ctx, ccr)
+	}
+
+	requester := ccr.Annotations[v1alpha1.CTIRequesterAnnotation]
+	if requester == "" {
+		return ctrl.Result{}, r.setMessage(ctx, ccr, "Requester is not known")
+	}
+
+	cti := &v1alpha1.ClusterTemplateInstance{}
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{Name: ccr.Spec.ClusterTemplateInstanceRef, Namespace: ccr.Namespace},
+		cti,
+	); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, r.setMessage(
+				ctx,
+				ccr,
+				fmt.Sprintf("ClusterTemplateInstance %s not found", ccr.Spec.ClusterTemplateInstanceRef),
+			)
+		}
+		return ctrl.Result{}, err
+	}
+	if cti.Status.Kubeconfig == nil {
+		// the instance is watched, the request is reconciled again once the cluster is ready
+		return ctrl.Result{}, r.setMessage(ctx, ccr, "Cluster credentials are not available yet")
+	}
+
+	credentials, err := r.createCredentialsSecret(ctx, ccr, cti)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.grantCredentialsAccess(ctx, ccr, credentials, requester); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	now := metav1.Now()
+	if err := r.appendAccessLog(ctx, cti, getAccessLogEntry(now, requester, ccr)); err != nil {
+		return ctrl.Result{}, err
+	}
+	// the operator log can be shipped to an external sink
+	CCRLog.Info(
+		"Cluster credentials issued",
+		"requester", requester,
+		"clustertemplateinstance", cti.Name,
+		"namespace", cti.Namespace,
+		"request", ccr.Name,
+	)
+
+	ttl := getCredentialsTTL(ccr)
+	ccr.Status.Credentials = &corev1.LocalObjectReference{Name: credentials.Name}
+	ccr.Status.IssueTime = &now
+	ccr.Status.ExpirationTime = &metav1.Time{Time: now.Add(ttl)}
+	ccr.Status.Message = ""
+	return ctrl.Result{RequeueAfter: ttl}, r.Status().Update(ctx, ccr)
+}
+
+func getCredentialsTTL(ccr *v1alpha1.ClusterCredentialRequest) time.Duration {
+	if ccr.Spec.TTL != nil {
+		return ccr.Spec.TTL.Duration
+	}
+	return defaultCredentialsTTL
+}
+
+// revokeExpiredCredentials deletes the copy of the credentials together with the Role and
+// RoleBinding granting the access to it once the credentials expire, and records it in the access
+// log. Returns when to check again if the credentials did not expire yet.
+func (r *ClusterCredentialRequestReconciler) revokeExpiredCredentials(
+	ctx context.Context,
+	ccr *v1alpha1.ClusterCredentialRequest,
+) (ctrl.Result, error) {
+	if ccr.Status.Credentials == nil {
+		return ctrl.Result{}, nil
+	}
+	// requests issued before the expiration was recorded expire by their TTL
+	expirationTime := ccr.Status.IssueTime.Add(getCredentialsTTL(ccr))
+	if ccr.Status.ExpirationTime != nil {
+		expirationTime = ccr.Status.ExpirationTime.Time
+	}
+	if remaining := time.Until(expirationTime); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	objectMeta := metav1.ObjectMeta{Name: ccr.Status.Credentials.Name, Namespace: ccr.Namespace}
+	for _, obj := range []client.Object{
+		&rbacv1.RoleBinding{ObjectMeta: objectMeta},
+		&rbacv1.Role{ObjectMeta: objectMeta},
+		&corev1.Secret{ObjectMeta: objectMeta},
+	} {
+		if err := r.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+
+	requester := ccr.Annotations[v1alpha1.CTIRequesterAnnotation]
+	cti := &v1alpha1.ClusterTemplateInstance{}
+	err := r.Get(
+		ctx,
+		client.ObjectKey{Name: ccr.Spec.ClusterTemplateInstanceRef, Namespace: ccr.Namespace},
+		cti,
+	)
+	if err == nil {
+		entry := getAccessLogEntry(metav1.Now(), requester, ccr) + " expired"
+		if err := r.appendAccessLog(ctx, cti, entry); err != nil {
+			return ctrl.Result{}, err
+		}
+	} else if !apierrors.IsNotFound(err) {
+		// the access log is deleted together with the instance
+		return ctrl.Result{}, err
+	}
+	CCRLog.Info(
+		"Cluster credentials revoked",
+		"requester", requester,
+		"clustertemplateinstance", ccr.Spec.ClusterTemplateInstanceRef,
+		"namespace", ccr.Namespace,
+		"request", ccr.Name,
+	)
+
+	ccr.Status.Credentials = nil
+	ccr.Status.Message = "Credentials expired"
+	return ctrl.Result{}, r.Status().Update(ctx, ccr)
+}
+
+func (r *ClusterCredentialRequestReconciler) setMessage(
+	ctx context.Context,
+	ccr *v1alpha1.ClusterCredentialRequest,
+	message string,
+) error {
+	if ccr.Status.Message == message {
+		return nil
+	}
+	ccr.Status.Message = message
+	return r.Status().Update(ctx, ccr)
+}
+
+// createCredentialsSecret copies kubeconfig and admin credentials of the cluster to a secret
+// owned by the request
+func (r *ClusterCredentialRequestReconciler) createCredentialsSecret(
+	ctx context.Context,
+	ccr *v1alpha1.ClusterCredentialRequest,
+	cti *v1alpha1.ClusterTemplateInstance,
+) (*corev1.Secret, error) {
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ccr.Name + "-credentials",
+			Namespace: ccr.Namespace,
+		},
+		Data: map[string][]byte{},
+	}
+	for _, name := range []string{cti.GetKubeconfigRef(), cti.GetKubeadminPassRef()} {
+		secret := &corev1.Secret{}
+		if err := r.Get(
+			ctx,
+			client.ObjectKey{Name: name, Namespace: cti.Namespace},
+			secret,
+		); err != nil {
+			// not every cluster provider creates admin credentials
+			if apierrors.IsNotFound(err) && name == cti.GetKubeadminPassRef() {
+				continue
+			}
+			return nil, err
+		}
+		for key, val := range secret.Data {
+			credentials.Data[key] = val
+		}
+	}
+	if err := controllerutil.SetControllerReference(ccr, credentials, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Create(ctx, credentials); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+// grantCredentialsAccess allows the requester to read the credentials secret
+func (r *ClusterCredentialRequestReconciler) grantCredentialsAccess(
+	ctx context.Context,
+	ccr *v1alpha1.ClusterCredentialRequest,
+	credentials *corev1.Secret,
+	requester string,
+) error {
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      credentials.Name,
+			Namespace: ccr.Namespace,
+		},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups:     []string{""},
+			Verbs:         []string{"get"},
+			Resources:     []string{"secrets"},
+			ResourceNames: []string{credentials.Name},
+		}},
+	}
+	if err := controllerutil.SetControllerReference(ccr, role, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, role); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      credentials.Name,
+			Namespace: ccr.Namespace,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.SchemeGroupVersion.Group,
+			Kind:     "Role",
+			Name:     role.Name,
+		},
+		// service accounts are matched by their username too
+		Subjects: []rbacv1.Subject{{
+			APIGroup: rbacv1.SchemeGroupVersion.Group,
+			Kind:     rbacv1.UserKind,
+			Name:     requester,
+		}},
+	}
+	if err := controllerutil.SetControllerReference(ccr, roleBinding, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, roleBinding); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// getAccessLogEntry returns the access log line with the time, requester and request name
+func getAccessLogEntry(
+	t metav1.Time,
+	requester string,
+	ccr *v1alpha1.ClusterCredentialRequest,
+) string {
+	return fmt.Sprintf("%s %s %s", t.UTC().Format(time.RFC3339), requester, ccr.Name)
+}
+
+// appendAccessLog records issued or revoked credentials in the access log ConfigMap of the
+// instance
+func (r *ClusterCredentialRequestReconciler) appendAccessLog(
+	ctx context.Context,
+	cti *v1alpha1.ClusterTemplateInstance,
+	entry string,
+) error {
+	accessLog := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cti.GetAccessLogRef(),
+			Namespace: cti.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, accessLog, func() error {
+		accessLog.OwnerReferences = []metav1.OwnerReference{cti.GetOwnerReference()}
+		entries := []string{}
+		if val := accessLog.Data[accessLogKey]; val != "" {
+			entries = strings.Split(strings.TrimSuffix(val, "\n"), "\n")
+		}
+		entries = append(entries, entry)
+		if len(entries) > maxAccessLogEntries {
+			entries = entries[len(entries)-maxAccessLogEntries:]
+		}
+		if accessLog.Data == nil {
+			accessLog.Data = map[string]string{}
+		}
+		accessLog.Data[accessLogKey] = strings.Join(entries, "\n") + "\n"
+		return nil
+	})
+	return err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterCredentialRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ClusterCredentialRequest{}).
+		Watches(
+			&source.Kind{Type: &v1alpha1.ClusterTemplateInstance{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapInstanceToRequests),
+		).
+		Complete(r)
+}
+
+func (r *ClusterCredentialRequestReconciler) mapInstanceToRequests(
+	obj client.Object,
+) []reconcile.Request {
+	requests := []reconcile.Request{}
+	ccrs := &v1alpha1.ClusterCredentialRequestList{}
+	if err := r.List(context.TODO(), ccrs, client.InNamespace(obj.GetNamespace())); err != nil {
+		CCRLog.Error(err, "failed to list cluster credential requests")
+		return requests
+	}
+	for _, ccr := range ccrs.Items {
+		if ccr.Spec.ClusterTemplateInstanceRef == obj.GetName() && ccr.Status.IssueTime == nil {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: ccr.Name, Namespace: ccr.Namespace},
+			})
+		}
+	}
+	return requests
+}
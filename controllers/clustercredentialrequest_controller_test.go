@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClusterCredentialRequest controller", func() {
+	var cti *v1alpha1.ClusterTemplateInstance
+	var ccr *v1alpha1.ClusterCredentialRequest
+
+	BeforeEach(func() {
+		cti = &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+		}
+		ccr = &v1alpha1.ClusterCredentialRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-request",
+				Namespace: "default",
+				Annotations: map[string]string{
+					v1alpha1.CTIRequesterAnnotation: "foo-user",
+				},
+			},
+			Spec: v1alpha1.ClusterCredentialRequestSpec{
+				ClusterTemplateInstanceRef: "foo",
+			},
+		}
+	})
+
+	It("Waits for cluster credentials", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti, ccr)
+		reconciler := &ClusterCredentialRequestReconciler{Client: k8sClient, Scheme: scheme.Scheme}
+		_, err := reconciler.Reconcile(
+			context.TODO(),
+			ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ccr)},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(ccr), ccr)).Should(Succeed())
+		Expect(ccr.Status.Credentials).Should(BeNil())
+		Expect(ccr.Status.Message).Should(Equal("Cluster credentials are not available yet"))
+	})
+
+	It("Issues credentials and records the access", func() {
+		cti.Status.Kubeconfig = &corev1.LocalObjectReference{Name: cti.GetKubeconfigRef()}
+		kubeconfig := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetKubeconfigRef(),
+				Namespace: cti.Namespace,
+			},
+			Data: map[string][]byte{
+				"kubeconfig": []byte("foo"),
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti, ccr, kubeconfig)
+		reconciler := &ClusterCredentialRequestReconciler{Client: k8sClient, Scheme: scheme.Scheme}
+		result, err := reconciler.Reconcile(
+			context.TODO(),
+			ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ccr)},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result.RequeueAfter).Should(Equal(time.Hour))
+
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(ccr), ccr)).Should(Succeed())
+		Expect(ccr.Status.Credentials).ShouldNot(BeNil())
+		Expect(ccr.Status.IssueTime).ShouldNot(BeNil())
+		Expect(ccr.Status.ExpirationTime.Sub(ccr.Status.IssueTime.Time)).Should(Equal(time.Hour))
+
+		credentials := &corev1.Secret{}
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKey{
+			Name:      ccr.Status.Credentials.Name,
+			Namespace: ccr.Namespace,
+		}, credentials)).Should(Succeed())
+		Expect(string(credentials.Data["kubeconfig"])).Should(Equal("foo"))
+
+		roleBinding := &rbacv1.RoleBinding{}
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKey{
+			Name:      ccr.Status.Credentials.Name,
+			Namespace: ccr.Namespace,
+		}, roleBinding)).Should(Succeed())
+		Expect(roleBinding.Subjects[0].Name).Should(Equal("foo-user"))
+
+		accessLog := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKey{
+			Name:      cti.GetAccessLogRef(),
+			Namespace: cti.Namespace,
+		}, accessLog)).Should(Succeed())
+		Expect(strings.HasSuffix(accessLog.Data[accessLogKey], " foo-user foo-request\n")).
+			Should(BeTrue())
+	})
+
+	It("Revokes expired credentials", func() {
+		issueTime := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		ccr.Spec.TTL = &metav1.Duration{Duration: time.Hour}
+		ccr.Status = v1alpha1.ClusterCredentialRequestStatus{
+			Credentials: &corev1.LocalObjectReference{Name: "foo-request-credentials"},
+			IssueTime:   &issueTime,
+		}
+		objectMeta := metav1.ObjectMeta{Name: "foo-request-credentials", Namespace: "default"}
+		k8sClient := fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			cti,
+			ccr,
+			&corev1.Secret{ObjectMeta: objectMeta},
+			&rbacv1.Role{ObjectMeta: objectMeta},
+			&rbacv1.RoleBinding{ObjectMeta: objectMeta},
+		)
+		reconciler := &ClusterCredentialRequestReconciler{Client: k8sClient, Scheme: scheme.Scheme}
+		_, err := reconciler.Reconcile(
+			context.TODO(),
+			ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ccr)},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(ccr), ccr)).Should(Succeed())
+		Expect(ccr.Status.Credentials).Should(BeNil())
+		Expect(ccr.Status.Message).Should(Equal("Credentials expired"))
+		for _, obj := range []client.Object{&corev1.Secret{}, &rbacv1.Role{}, &rbacv1.RoleBinding{}} {
+			err := k8sClient.Get(context.TODO(), client.ObjectKey{
+				Name:      objectMeta.Name,
+				Namespace: objectMeta.Namespace,
+			}, obj)
+			Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+		}
+
+		accessLog := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKey{
+			Name:      cti.GetAccessLogRef(),
+			Namespace: cti.Namespace,
+		}, accessLog)).Should(Succeed())
+		Expect(strings.HasSuffix(accessLog.Data[accessLogKey], " foo-user foo-request expired\n")).
+			Should(BeTrue())
+	})
+
+	It("Keeps credentials until they expire", func() {
+		issueTime := metav1.Now()
+		ccr.Status = v1alpha1.ClusterCredentialRequestStatus{
+			Credentials:    &corev1.LocalObjectReference{Name: "foo-request-credentials"},
+			IssueTime:      &issueTime,
+			ExpirationTime: &metav1.Time{Time: issueTime.Add(time.Hour)},
+		}
+		credentials := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-request-credentials", Namespace: "default"},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti, ccr, credentials)
+		reconciler := &ClusterCredentialRequestReconciler{Client: k8sClient, Scheme: scheme.Scheme}
+		result, err := reconciler.Reconcile(
+			context.TODO(),
+			ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ccr)},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(result.RequeueAfter).Should(BeNumerically("~", time.Hour, time.Minute))
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(credentials), credentials)).
+			Should(Succeed())
+	})
+})
@@ -0,0 +1,103 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// ClusterSetupDefinitionReconciler reconciles a ClusterSetupDefinition object
+type ClusterSetupDefinitionReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustersetupdefinitions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustersetupdefinitions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplates,verbs=get;list;watch
+
+func (r *ClusterSetupDefinitionReconciler) Reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+) (ctrl.Result, error) {
+	definition := &v1alpha1.ClusterSetupDefinition{}
+	if err := r.Get(ctx, req.NamespacedName, definition); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	templates := &v1alpha1.ClusterTemplateList{}
+	if err := r.List(ctx, templates); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	usedBy := []string{}
+	for _, template := range templates.Items {
+		if template.UsesClusterSetupDefinition(definition.Name) {
+			usedBy = append(usedBy, template.Name)
+		}
+	}
+	sort.Strings(usedBy)
+
+	if reflect.DeepEqual(usedBy, definition.Status.ClusterTemplates) {
+		return ctrl.Result{}, nil
+	}
+	definition.Status.ClusterTemplates = usedBy
+	return ctrl.Result{}, r.Status().Update(ctx, definition)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterSetupDefinitionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ClusterSetupDefinition{}).
+		Watches(
+			&source.Kind{Type: &v1alpha1.ClusterTemplate{}},
+			handler.EnqueueRequestsFromMapFunc(mapClusterTemplateToDefinitions),
+		).
+		Complete(r)
+}
+
+// on update both old and new template are mapped, so removed references are picked up too
+func mapClusterTemplateToDefinitions(template client.Object) []reconcile.Request {
+	ct, ok := template.(*v1alpha1.ClusterTemplate)
+	if !ok {
+		return []reconcile.Request{}
+	}
+	reqs := []reconcile.Request{}
+	for _, setup := range ct.Spec.ClusterSetup {
+		if setup.DefinitionRef != "" {
+			reqs = append(reqs, reconcile.Request{
+				NamespacedName: client.ObjectKey{Name: setup.DefinitionRef},
+			})
+		}
+	}
+	return reqs
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/testutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("ClusterSetupDefinition controller", func() {
+	var definition *v1alpha1.ClusterSetupDefinition
+	var ct *v1alpha1.ClusterTemplate
+
+	BeforeEach(func() {
+		definition = &v1alpha1.ClusterSetupDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "gitops",
+			},
+		}
+		Expect(k8sClient.Create(ctx, definition)).Should(Succeed())
+
+		ct = &v1alpha1.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "csd-template",
+			},
+			Spec: v1alpha1.ClusterTemplateSpec{
+				ClusterSetup: []v1alpha1.ClusterSetup{
+					{
+						Name:          "gitops",
+						DefinitionRef: definition.Name,
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		testutils.DeleteResource(ctx, ct, k8sClient)
+		testutils.DeleteResource(ctx, definition, k8sClient)
+	})
+
+	It("Lists ClusterTemplates which use the definition", func() {
+		Expect(k8sClient.Create(ctx, ct)).Should(Succeed())
+
+		Eventually(func() []string {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(definition), definition)
+			if err != nil {
+				return nil
+			}
+			return definition.Status.ClusterTemplates
+		}, timeout, interval).Should(Equal([]string{ct.Name}))
+	})
+
+	It("Removes ClusterTemplates which stopped using the definition", func() {
+		Expect(k8sClient.Create(ctx, ct)).Should(Succeed())
+		Eventually(func() int {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(definition), definition)
+			if err != nil {
+				return 0
+			}
+			return len(definition.Status.ClusterTemplates)
+		}, timeout, interval).Should(Equal(1))
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(ct), ct)).Should(Succeed())
+		ct.Spec.ClusterSetup = []v1alpha1.ClusterSetup{}
+		Expect(k8sClient.Update(ctx, ct)).Should(Succeed())
+
+		Eventually(func() int {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(definition), definition)
+			if err != nil {
+				return 1
+			}
+			return len(definition.Status.ClusterTemplates)
+		}, timeout, interval).Should(Equal(0))
+	})
+})
@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// how long a canary instance has to become ready within if the template does not set it
+const defaultCanaryTimeout = 2 * time.Hour
+
+// getCanaryName returns name of the canary instance testing the generation of the template
+func getCanaryName(clusterTemplate *v1alpha1.ClusterTemplate) string {
+	return fmt.Sprintf("%s-canary-%d", clusterTemplate.Name, clusterTemplate.Generation)
+}
+
+func getCanaryTimeout(canary *v1alpha1.CanaryOptions) time.Duration {
+	if canary.Timeout != nil {
+		return canary.Timeout.Duration
+	}
+	return defaultCanaryTimeout
+}
+
+// reconcileCanary provisions a canary instance whenever the template changes, records its result
+// and deletes it once it finishes. The Ready condition of the template reflects the result.
+// Returns how long to wait before the canary times out.
+func (r *ClusterTemplateReconciler) reconcileCanary(
+	ctx context.Context,
+	clusterTemplate *v1alpha1.ClusterTemplate,
+) (time.Duration, error) {
+	canary := clusterTemplate.Spec.Canary
+	if canary == nil {
+		clusterTemplate.Status.Canary = nil
+		clusterTemplate.SetReadyCondition(
+			metav1.ConditionTrue,
+			v1alpha1.NoCanary,
+			"Template has no canary",
+		)
+		return 0, r.deleteCanaries(ctx, clusterTemplate, "")
+	}
+
+	status := clusterTemplate.Status.Canary
+	if status == nil || status.Generation != clusterTemplate.Generation {
+		return getCanaryTimeout(canary), r.createCanary(ctx, clusterTemplate)
+	}
+
+	if status.Phase != v1alpha1.CanaryRunning {
+		return 0, nil
+	}
+
+	instance := &v1alpha1.ClusterTemplateInstance{}
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{Name: status.Name, Namespace: status.Namespace},
+		instance,
+	); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return 0, err
+		}
+		finishCanary(clusterTemplate, v1alpha1.CanaryFailed, "Canary instance was deleted")
+		return 0, nil
+	}
+
+	remaining := getCanaryTimeout(canary) - time.Since(status.StartTime.Time)
+	switch {
+	case instance.Status.Phase == v1alpha1.ReadyPhase:
+		finishCanary(clusterTemplate, v1alpha1.CanarySucceeded, "Canary instance became ready")
+	case instance.Status.Phase.IsFailed():
+		finishCanary(
+			clusterTemplate,
+			v1alpha1.CanaryFailed,
+			fmt.Sprintf("Canary instance failed - %s", instance.Status.Message),
+		)
+	case remaining <= 0:
+		finishCanary(
+			clusterTemplate,
+			v1alpha1.CanaryFailed,
+			fmt.Sprintf("Canary instance did not become ready within %s", getCanaryTimeout(canary)),
+		)
+	default:
+		return remaining, nil
+	}
+	return 0, client.IgnoreNotFound(r.Delete(ctx, instance))
+}
+
+// createCanary replaces canaries of the previous generations of the template by a new one
+func (r *ClusterTemplateReconciler) createCanary(
+	ctx context.Context,
+	clusterTemplate *v1alpha1.ClusterTemplate,
+) error {
+	name := getCanaryName(clusterTemplate)
+	if err := r.deleteCanaries(ctx, clusterTemplate, name); err != nil {
+		return err
+	}
+	canary := clusterTemplate.Spec.Canary
+	instance := &v1alpha1.ClusterTemplateInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: canary.Namespace,
+			Labels: map[string]string{
+				v1alpha1.CTCanaryLabel: clusterTemplate.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(
+					clusterTemplate,
+					v1alpha1.GroupVersion.WithKind("ClusterTemplate"),
+				),
+			},
+		},
+		Spec: v1alpha1.ClusterTemplateInstanceSpec{
+			ClusterTemplateRef: clusterTemplate.Name,
+			Parameters:         canary.Parameters,
+		},
+	}
+	if err := r.Create(ctx, instance); err != nil && !apierrors.IsAlreadyExists(err) {
+		msg := fmt.Sprintf("Failed to create canary instance - %q", err)
+		clusterTemplate.SetReadyCondition(
+			metav1.ConditionFalse,
+			v1alpha1.CanaryInstanceFailed,
+			msg,
+		)
+		return fmt.Errorf("%s", msg)
+	}
+	clusterTemplate.Status.Canary = &v1alpha1.CanaryStatus{
+		Generation: clusterTemplate.Generation,
+		Namespace:  instance.Namespace,
+		Name:       instance.Name,
+		Phase:      v1alpha1.CanaryRunning,
+		Message:    "Canary instance is being provisioned",
+		StartTime:  metav1.Now(),
+	}
+	clusterTemplate.SetReadyCondition(
+		metav1.ConditionFalse,
+		v1alpha1.CanaryInstanceRunning,
+		fmt.Sprintf("Waiting for canary instance %s/%s", instance.Namespace, instance.Name),
+	)
+	return nil
+}
+
+// deleteCanaries deletes canary instances of the template except the one with the given name
+func (r *ClusterTemplateReconciler) deleteCanaries(
+	ctx context.Context,
+	clusterTemplate *v1alpha1.ClusterTemplate,
+	keep string,
+) error {
+	instances := &v1alpha1.ClusterTemplateInstanceList{}
+	if err := r.List(
+		ctx,
+		instances,
+		client.MatchingLabels{v1alpha1.CTCanaryLabel: clusterTemplate.Name},
+	); err != nil {
+		return err
+	}
+	for i := range instances.Items {
+		instance := &instances.Items[i]
+		if instance.Name == keep || instance.DeletionTimestamp != nil {
+			continue
+		}
+		if err := r.Delete(ctx, instance); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finishCanary records the result of the canary in the template status
+func finishCanary(
+	clusterTemplate *v1alpha1.ClusterTemplate,
+	phase v1alpha1.CanaryPhase,
+	msg string,
+) {
+	now := metav1.Now()
+	clusterTemplate.Status.Canary.Phase = phase
+	clusterTemplate.Status.Canary.Message = msg
+	clusterTemplate.Status.Canary.CompletionTime = &now
+	if phase == v1alpha1.CanarySucceeded {
+		clusterTemplate.SetReadyCondition(metav1.ConditionTrue, v1alpha1.CanaryInstanceReady, msg)
+	} else {
+		clusterTemplate.SetReadyCondition(metav1.ConditionFalse, v1alpha1.CanaryInstanceFailed, msg)
+	}
+}
+
+// mapCanaryToTemplate reconciles the template tested by the canary instance once the instance
+// finishes
+func (r *ClusterTemplateReconciler) mapCanaryToTemplate(obj client.Object) []reconcile.Request {
+	instance, ok := obj.(*v1alpha1.ClusterTemplateInstance)
+	if !ok {
+		return []reconcile.Request{}
+	}
+	templateName, ok := instance.Labels[v1alpha1.CTCanaryLabel]
+	if !ok ||
+		(instance.Status.Phase != v1alpha1.ReadyPhase && !instance.Status.Phase.IsFailed()) {
+		return []reconcile.Request{}
+	}
+	return []reconcile.Request{{NamespacedName: client.ObjectKey{Name: templateName}}}
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClusterTemplate canary", func() {
+	var ct *v1alpha1.ClusterTemplate
+
+	BeforeEach(func() {
+		ct = &v1alpha1.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "foo",
+				Generation: 2,
+				UID:        "foo-uid",
+			},
+			Spec: v1alpha1.ClusterTemplateSpec{
+				Canary: &v1alpha1.CanaryOptions{
+					Namespace:  "canaries",
+					Parameters: []v1alpha1.Parameter{{Name: "nodeCount", Value: "1"}},
+				},
+			},
+		}
+	})
+
+	getReadyCondition := func() *metav1.Condition {
+		return meta.FindStatusCondition(ct.Status.Conditions, string(v1alpha1.TemplateReady))
+	}
+
+	It("Is ready without canary", func() {
+		ct.Spec.Canary = nil
+		reconciler := &ClusterTemplateReconciler{Client: fake.NewFakeClientWithScheme(scheme.Scheme)}
+		remaining, err := reconciler.reconcileCanary(context.TODO(), ct)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(remaining).Should(BeZero())
+		Expect(getReadyCondition().Status).Should(Equal(metav1.ConditionTrue))
+		Expect(getReadyCondition().Reason).Should(Equal(string(v1alpha1.NoCanary)))
+	})
+
+	It("Replaces canary of previous generation", func() {
+		oldCanary := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-canary-1",
+				Namespace: "canaries",
+				Labels:    map[string]string{v1alpha1.CTCanaryLabel: "foo"},
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, oldCanary)
+		reconciler := &ClusterTemplateReconciler{Client: k8sClient}
+		remaining, err := reconciler.reconcileCanary(context.TODO(), ct)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(remaining).Should(Equal(defaultCanaryTimeout))
+
+		instances := &v1alpha1.ClusterTemplateInstanceList{}
+		Expect(k8sClient.List(context.TODO(), instances)).Should(Succeed())
+		Expect(instances.Items).Should(HaveLen(1))
+		canary := instances.Items[0]
+		Expect(canary.Name).Should(Equal("foo-canary-2"))
+		Expect(canary.Spec.ClusterTemplateRef).Should(Equal("foo"))
+		Expect(canary.Spec.Parameters).Should(Equal(ct.Spec.Canary.Parameters))
+		Expect(canary.OwnerReferences[0].UID).Should(BeEquivalentTo("foo-uid"))
+
+		Expect(ct.Status.Canary.Generation).Should(Equal(int64(2)))
+		Expect(ct.Status.Canary.Phase).Should(Equal(v1alpha1.CanaryRunning))
+		Expect(getReadyCondition().Status).Should(Equal(metav1.ConditionFalse))
+		Expect(getReadyCondition().Reason).Should(Equal(string(v1alpha1.CanaryInstanceRunning)))
+	})
+
+	It("Records result of the canary and deletes it", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		reconciler := &ClusterTemplateReconciler{Client: k8sClient}
+		_, err := reconciler.reconcileCanary(context.TODO(), ct)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		canary := &v1alpha1.ClusterTemplateInstance{}
+		key := client.ObjectKey{Name: "foo-canary-2", Namespace: "canaries"}
+		Expect(k8sClient.Get(context.TODO(), key, canary)).Should(Succeed())
+		Expect(reconciler.mapCanaryToTemplate(canary)).Should(BeEmpty())
+
+		canary.Status.Phase = v1alpha1.ReadyPhase
+		Expect(k8sClient.Update(context.TODO(), canary)).Should(Succeed())
+		Expect(reconciler.mapCanaryToTemplate(canary)).Should(HaveLen(1))
+
+		_, err = reconciler.reconcileCanary(context.TODO(), ct)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ct.Status.Canary.Phase).Should(Equal(v1alpha1.CanarySucceeded))
+		Expect(ct.Status.Canary.CompletionTime).ShouldNot(BeNil())
+		Expect(getReadyCondition().Status).Should(Equal(metav1.ConditionTrue))
+		Expect(k8sClient.Get(context.TODO(), key, canary)).ShouldNot(Succeed())
+	})
+
+	It("Fails canary which does not become ready in time", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		reconciler := &ClusterTemplateReconciler{Client: k8sClient}
+		_, err := reconciler.reconcileCanary(context.TODO(), ct)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		ct.Status.Canary.StartTime = metav1.NewTime(time.Now().Add(-3 * time.Hour))
+		_, err = reconciler.reconcileCanary(context.TODO(), ct)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ct.Status.Canary.Phase).Should(Equal(v1alpha1.CanaryFailed))
+		Expect(ct.Status.Canary.Message).Should(Equal(
+			"Canary instance did not become ready within 2h0m0s",
+		))
+		Expect(getReadyCondition().Status).Should(Equal(metav1.ConditionFalse))
+		Expect(getReadyCondition().Reason).Should(Equal(string(v1alpha1.CanaryInstanceFailed)))
+	})
+})
@@ -2,17 +2,30 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/hashicorp/go-multierror"
 	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
 	"github.com/stolostron/cluster-templates-operator/helm"
+	"github.com/stolostron/cluster-templates-operator/hubversion"
+	"helm.sh/helm/v3/pkg/chart"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
+// how often status of templates which depend on the environment is refreshed
+const statusRefreshInterval = 30 * time.Minute
+
 type RepoEntry struct {
 	Version string   `json:"version"`
 	Urls    []string `json:"urls"`
@@ -30,6 +43,11 @@ type ClusterTemplateReconciler struct {
 
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplates/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplates,verbs=get
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustersetupdefinitions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateinstances,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=multicluster.openshift.io,resources=multiclusterengines,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
 
 func (r *ClusterTemplateReconciler) Reconcile(
 	ctx context.Context,
@@ -42,81 +60,291 @@ func (r *ClusterTemplateReconciler) Reconcile(
 		return ctrl.Result{}, err
 	}
 
-	cdValues, cdSchema, err := r.getValuesAndSchema(
+	cd, err := r.getValuesAndSchema(
 		ctx,
 		clusterTemplate.Spec.ClusterDefinition,
+		clusterTemplate.Spec.HelmChartURL,
+		clusterTemplate.Spec.EmbeddedChart,
+		clusterTemplate,
 	)
 	if err == nil {
-		clusterTemplate.Status.ClusterDefinition.Values = cdValues
-		clusterTemplate.Status.ClusterDefinition.Schema = cdSchema
+		clusterTemplate.Status.ClusterDefinition.Values = cd.values
+		clusterTemplate.Status.ClusterDefinition.Schema = cd.schema
+		clusterTemplate.Status.ClusterDefinition.Version = cd.version
+		clusterTemplate.Status.ClusterDefinition.RepoURL = cd.repoURL
 		clusterTemplate.Status.ClusterDefinition.Error = nil
 	} else {
 		errors = multierror.Append(errors, err)
 		clusterTemplate.Status.ClusterDefinition.Error = pointer.String(err.Error())
 	}
 
+	// newer chart matching a version constraint can be published at any time, the primary
+	// repository can recover when a mirror was used
+	versionResolved := cd.version != "" && clusterTemplate.Spec.HelmChartURL == "" &&
+		(cd.version != clusterTemplate.Spec.ClusterDefinition.Source.TargetRevision ||
+			cd.repoURL != clusterTemplate.Spec.ClusterDefinition.Source.RepoURL)
+
 	clusterSetupStatus := []v1alpha1.ClusterSetupSchema{}
 	for _, setup := range clusterTemplate.Spec.ClusterSetup {
-		values, schema, err := r.getValuesAndSchema(
+		css := v1alpha1.ClusterSetupSchema{}
+		setupSpec, err := setup.GetSpec(ctx, r.Client)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+			css.Error = pointer.String(err.Error())
+			clusterSetupStatus = append(clusterSetupStatus, css)
+			continue
+		}
+		setupChart, err := r.getValuesAndSchema(
 			ctx,
-			setup.Spec,
+			setupSpec,
+			"",
+			nil,
+			clusterTemplate,
 		)
-		css := v1alpha1.ClusterSetupSchema{}
 		if err != nil {
 			errors = multierror.Append(errors, err)
 			css.Error = pointer.String(err.Error())
 		} else {
 			css.Name = setup.Name
-			css.Values = values
-			css.Schema = schema
+			css.Values = setupChart.values
+			css.Schema = setupChart.schema
+			css.Version = setupChart.version
+			css.RepoURL = setupChart.repoURL
 			css.Error = nil
+			versionResolved = versionResolved ||
+				(setupChart.version != "" && setupChart.version != setupSpec.Source.TargetRevision) ||
+				(setupChart.repoURL != "" && setupChart.repoURL != setupSpec.Source.RepoURL)
 		}
 		clusterSetupStatus = append(clusterSetupStatus, css)
 	}
 	clusterTemplate.Status.ClusterSetup = clusterSetupStatus
 
+	result := ctrl.Result{}
+	if versionResolved {
+		result.RequeueAfter = statusRefreshInterval
+	}
+	if clusterTemplate.Spec.HubRequirements != nil {
+		errors = multierror.Append(errors, r.checkHubRequirements(ctx, clusterTemplate))
+		// hub can be upgraded at any time
+		result.RequeueAfter = statusRefreshInterval
+	} else {
+		clusterTemplate.SetUnsupportedCondition(
+			metav1.ConditionFalse,
+			v1alpha1.HubVersionSupported,
+			"Template has no hub requirements",
+		)
+	}
+
+	// chart errors are already reported, the document is published once the charts are fetched
+	if clusterTemplate.Status.ClusterDefinition.Error == nil {
+		errors = multierror.Append(errors, r.reconcileOpenAPIDocument(ctx, clusterTemplate))
+	}
+
+	canaryRemaining, err := r.reconcileCanary(ctx, clusterTemplate)
+	errors = multierror.Append(errors, err)
+	// canary instance times out
+	if canaryRemaining > 0 &&
+		(result.RequeueAfter == 0 || canaryRemaining < result.RequeueAfter) {
+		result.RequeueAfter = canaryRemaining
+	}
+
 	err = r.Client.Status().Update(ctx, clusterTemplate)
 	errors = multierror.Append(errors, err)
-	return ctrl.Result{}, errors.ErrorOrNil()
+	return result, errors.ErrorOrNil()
+}
+
+func (r *ClusterTemplateReconciler) checkHubRequirements(
+	ctx context.Context,
+	clusterTemplate *v1alpha1.ClusterTemplate,
+) error {
+	versions, err := hubversion.GetHubVersions(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+	if reqErr := hubversion.CheckRequirements(
+		clusterTemplate.Spec.HubRequirements,
+		versions,
+	); reqErr != nil {
+		clusterTemplate.SetUnsupportedCondition(
+			metav1.ConditionTrue,
+			v1alpha1.HubVersionUnsupported,
+			reqErr.Error(),
+		)
+		return nil
+	}
+	clusterTemplate.SetUnsupportedCondition(
+		metav1.ConditionFalse,
+		v1alpha1.HubVersionSupported,
+		"Hub satisfies requirements of the template",
+	)
+	return nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClusterTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.ClusterTemplate{}).
+		Watches(
+			&source.Kind{Type: &v1alpha1.ClusterSetupDefinition{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapClusterSetupDefinition),
+		).
+		Watches(
+			&source.Kind{Type: &v1alpha1.ClusterTemplateInstance{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapCanaryToTemplate),
+		).
 		Complete(r)
 }
 
+func (r *ClusterTemplateReconciler) mapClusterSetupDefinition(
+	definition client.Object,
+) []reconcile.Request {
+	templates := &v1alpha1.ClusterTemplateList{}
+	if err := r.List(context.TODO(), templates); err != nil {
+		return []reconcile.Request{}
+	}
+	reqs := []reconcile.Request{}
+	for _, template := range templates.Items {
+		if template.UsesClusterSetupDefinition(definition.GetName()) {
+			reqs = append(reqs, reconcile.Request{
+				NamespacedName: client.ObjectKey{Name: template.Name},
+			})
+		}
+	}
+	return reqs
+}
+
+// chartSchema holds values, schema and resolved version of a Helm chart and the repository it
+// was fetched from
+type chartSchema struct {
+	values  string
+	schema  string
+	version string
+	repoURL string
+}
+
+// getValuesAndSchema reads values and schema of the application Helm chart. If chartURL is set,
+// the chart tarball is downloaded from it instead of the repository of the application and stored
+// to be served by the operator. If embeddedChart is set, the chart is read from the ConfigMap or
+// Secret. For both, the repository of the operator serving the chart is returned. Repository
+// mirrors of the template are tried in order when the chart can not be fetched from the
+// repository of the application.
 func (r *ClusterTemplateReconciler) getValuesAndSchema(
 	ctx context.Context,
 	appSpec argo.ApplicationSpec,
-) (string, string, error) {
-	values := ""
-	schema := ""
-	if appSpec.Source.Chart != "" {
-		repoURL := appSpec.Source.RepoURL
-		chartName := appSpec.Source.Chart
-		chartVersion := appSpec.Source.TargetRevision
-		chart, err := r.HelmClient.GetChart(
+	chartURL string,
+	embeddedChart *v1alpha1.EmbeddedChart,
+	clusterTemplate *v1alpha1.ClusterTemplate,
+) (chartSchema, error) {
+	result := chartSchema{}
+	var helmChart *chart.Chart
+	var err error
+	switch {
+	case embeddedChart != nil:
+		helmChart, err = r.HelmClient.GetEmbeddedChart(
 			ctx,
 			r.Client,
-			repoURL,
-			chartName,
-			chartVersion,
+			embeddedChart.Kind,
+			embeddedChart.Name,
 			ArgoCDNamespace,
 		)
-		if err != nil {
-			return values, schema, err
+		if err == nil && helmChart.Metadata.Name != appSpec.Source.Chart {
+			err = fmt.Errorf(
+				"embedded chart %s does not match chart %s of the application",
+				helmChart.Metadata.Name,
+				appSpec.Source.Chart,
+			)
+		}
+		result.repoURL = helm.GetEmbeddedChartRepoURL(
+			EmbeddedChartsURL,
+			embeddedChart.Kind,
+			embeddedChart.Name,
+		)
+	case chartURL != "":
+		var data []byte
+		helmChart, data, err = r.HelmClient.GetChartFromURL(
+			ctx,
+			r.Client,
+			chartURL,
+			ArgoCDNamespace,
+			HelmCABundle,
+		)
+		// ArgoCD installs the chart by name and version of the application source
+		if err == nil && (helmChart.Metadata.Name != appSpec.Source.Chart ||
+			helmChart.Metadata.Version != appSpec.Source.TargetRevision) {
+			err = fmt.Errorf(
+				"chart %s %s of helmChartURL does not match chart %s %s of the application",
+				helmChart.Metadata.Name,
+				helmChart.Metadata.Version,
+				appSpec.Source.Chart,
+				appSpec.Source.TargetRevision,
+			)
+		}
+		if err == nil {
+			result.repoURL, err = r.storeHelmChartURLChart(ctx, clusterTemplate, data)
+		}
+	case appSpec.Source.Chart != "":
+		helmChart, result.repoURL, err = r.HelmClient.GetChartFromRepositories(
+			ctx,
+			r.Client,
+			clusterTemplate.Spec.GetRepositories(appSpec.Source.RepoURL),
+			appSpec.Source.Chart,
+			appSpec.Source.TargetRevision,
+			ArgoCDNamespace,
+			HelmCABundle,
+		)
+	default:
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	result.version = helmChart.Metadata.Version
+	for _, file := range helmChart.Raw {
+		if file.Name == "values.yaml" {
+			result.values = string(file.Data)
+		}
+		if file.Name == "values.schema.json" {
+			result.schema = string(file.Data)
+		}
+	}
+	return result, nil
+}
+
+// storeHelmChartURLChart stores the chart tarball downloaded from helmChartURL of the template in
+// an embedded chart Secret of the ArgoCD namespace owned by the template and returns URL of the
+// repository of the operator serving it, so ArgoCD installs the chart of the URL
+func (r *ClusterTemplateReconciler) storeHelmChartURLChart(
+	ctx context.Context,
+	clusterTemplate *v1alpha1.ClusterTemplate,
+	data []byte,
+) (string, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterTemplate.GetHelmChartURLSecretName(),
+			Namespace: ArgoCDNamespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
 		}
-		for _, file := range chart.Raw {
-			if file.Name == "values.yaml" {
-				values = string(file.Data)
-			}
-			if file.Name == "values.schema.json" {
-				schema = string(file.Data)
-			}
+		secret.Labels[helm.EmbeddedChartLabel] = "true"
+		secret.OwnerReferences = []metav1.OwnerReference{
+			{
+				APIVersion: v1alpha1.APIVersion,
+				Kind:       "ClusterTemplate",
+				Name:       clusterTemplate.Name,
+				UID:        clusterTemplate.UID,
+			},
 		}
-		return values, schema, nil
+		secret.Data = map[string][]byte{helm.EmbeddedChartKey: data}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to store chart of helmChartURL - %q", err)
 	}
-	return values, schema, nil
+	return helm.GetEmbeddedChartRepoURL(
+		EmbeddedChartsURL,
+		helm.EmbeddedChartSecret,
+		secret.Name,
+	), nil
 }
@@ -2,13 +2,16 @@ package controllers
 
 import (
 	"net/http/httptest"
+	"os"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/helm"
 
 	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	testutils "github.com/stolostron/cluster-templates-operator/testutils"
@@ -121,6 +124,99 @@ var _ = Describe("ClusterTemplate controller", func() {
 		}, timeout, interval).Should(BeTrue())
 	})
 
+	It("Should read values and schema from chart URL", func() {
+		ArgoCDNamespace = "default"
+		defer func() { ArgoCDNamespace = defaultArgoCDNs }()
+		ct.Spec.ClusterDefinition.Source.Chart = "hypershift-template"
+		ct.Spec.ClusterDefinition.Source.RepoURL = "https://github.com/foo/charts"
+		ct.Spec.ClusterDefinition.Source.TargetRevision = "0.0.2"
+		ct.Spec.HelmChartURL = server.URL + "/hypershift-template-0.0.2.tgz"
+		Expect(k8sClient.Create(ctx, ct)).Should(Succeed())
+
+		Eventually(func() bool {
+			foundCT := &v1alpha1.ClusterTemplate{}
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(ct), foundCT)
+			if err != nil {
+				return false
+			}
+
+			return len(foundCT.Status.ClusterDefinition.Values) > 0 &&
+				len(foundCT.Status.ClusterDefinition.Schema) > 0 &&
+				foundCT.Status.ClusterDefinition.Version == "0.0.2" &&
+				foundCT.Status.ClusterDefinition.RepoURL ==
+					EmbeddedChartsURL+"/secrets/foo-helm-chart-url"
+		}, timeout, interval).Should(BeTrue())
+
+		chartData, err := os.ReadFile("../testutils/helm/hypershift-template-0.0.2.tgz")
+		Expect(err).ShouldNot(HaveOccurred())
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(
+			ctx,
+			client.ObjectKey{Name: "foo-helm-chart-url", Namespace: ArgoCDNamespace},
+			secret,
+		)).Should(Succeed())
+		defer testutils.DeleteResource(ctx, secret, k8sClient)
+		Expect(secret.Labels[helm.EmbeddedChartLabel]).Should(Equal("true"))
+		Expect(secret.Data[helm.EmbeddedChartKey]).Should(Equal(chartData))
+	})
+
+	It("Should set error when chart URL does not match the application source", func() {
+		ct.Spec.ClusterDefinition.Source.Chart = "hypershift-template"
+		ct.Spec.ClusterDefinition.Source.RepoURL = "https://github.com/foo/charts"
+		ct.Spec.ClusterDefinition.Source.TargetRevision = "0.0.1"
+		ct.Spec.HelmChartURL = server.URL + "/hypershift-template-0.0.2.tgz"
+		Expect(k8sClient.Create(ctx, ct)).Should(Succeed())
+
+		Eventually(func() bool {
+			foundCT := &v1alpha1.ClusterTemplate{}
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(ct), foundCT)
+			if err != nil {
+				return false
+			}
+
+			return foundCT.Status.ClusterDefinition.Error != nil &&
+				len(foundCT.Status.ClusterDefinition.Values) == 0
+		}, timeout, interval).Should(BeTrue())
+	})
+
+	It("Should read values and schema from embedded chart", func() {
+		ArgoCDNamespace = "default"
+		defer func() { ArgoCDNamespace = defaultArgoCDNs }()
+		chartData, err := os.ReadFile("../testutils/helm/hypershift-template-0.0.2.tgz")
+		Expect(err).ShouldNot(HaveOccurred())
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hypershift-template",
+				Namespace: ArgoCDNamespace,
+				Labels:    map[string]string{helm.EmbeddedChartLabel: "true"},
+			},
+			BinaryData: map[string][]byte{helm.EmbeddedChartKey: chartData},
+		}
+		Expect(k8sClient.Create(ctx, cm)).Should(Succeed())
+		defer testutils.DeleteResource(ctx, cm, k8sClient)
+
+		ct.Spec.ClusterDefinition.Source.Chart = "hypershift-template"
+		ct.Spec.ClusterDefinition.Source.RepoURL = "https://foo.io/charts"
+		ct.Spec.EmbeddedChart = &v1alpha1.EmbeddedChart{
+			Kind: helm.EmbeddedChartConfigMap,
+			Name: cm.Name,
+		}
+		Expect(k8sClient.Create(ctx, ct)).Should(Succeed())
+
+		Eventually(func() bool {
+			foundCT := &v1alpha1.ClusterTemplate{}
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(ct), foundCT)
+			if err != nil {
+				return false
+			}
+
+			return len(foundCT.Status.ClusterDefinition.Values) > 0 &&
+				foundCT.Status.ClusterDefinition.Version == "0.0.2" &&
+				foundCT.Status.ClusterDefinition.RepoURL ==
+					EmbeddedChartsURL+"/configmaps/hypershift-template"
+		}, timeout, interval).Should(BeTrue())
+	})
+
 	It("Should set error for ClusterDefinition in case of invalid port", func() {
 		ct.Spec.ClusterDefinition.Source.Chart = "hypershift-template"
 		ct.Spec.ClusterDefinition.Source.RepoURL = server.URL + "NONEXISTING"
@@ -160,4 +256,20 @@ var _ = Describe("ClusterTemplate controller", func() {
 		}, timeout, interval).Should(BeTrue())
 	})
 
+	It("Should set Unsupported condition when hub requirements are not met", func() {
+		ct.Spec.HubRequirements = &v1alpha1.HubRequirements{
+			OpenShiftVersion: ">= 4.12",
+		}
+		Expect(k8sClient.Create(ctx, ct)).Should(Succeed())
+
+		Eventually(func() string {
+			foundCT := &v1alpha1.ClusterTemplate{}
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(ct), foundCT)
+			if err != nil {
+				return ""
+			}
+			return foundCT.GetUnsupportedMessage()
+		}, timeout, interval).Should(ContainSubstring("not detected on the hub"))
+	})
+
 })
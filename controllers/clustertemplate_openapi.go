@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// reconcileOpenAPIDocument publishes the OpenAPI document of the template parameters in a
+// ConfigMap in the config namespace, so API gateways and portals can generate request forms
+// and clients without parsing the charts. The ConfigMap is deleted together with the template.
+func (r *ClusterTemplateReconciler) reconcileOpenAPIDocument(
+	ctx context.Context,
+	clusterTemplate *v1alpha1.ClusterTemplate,
+) error {
+	doc, err := clusterTemplate.GetOpenAPIDocument()
+	if err != nil {
+		return err
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterTemplate.GetOpenAPIConfigMapName(),
+			Namespace: configNamespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[v1alpha1.CTOpenAPILabel] = clusterTemplate.Name
+		configMap.OwnerReferences = []metav1.OwnerReference{
+			{
+				APIVersion: v1alpha1.APIVersion,
+				Kind:       "ClusterTemplate",
+				Name:       clusterTemplate.Name,
+				UID:        clusterTemplate.UID,
+			},
+		}
+		configMap.Data = map[string]string{v1alpha1.OpenAPIDocumentKey: string(doc)}
+		return nil
+	})
+	return err
+}
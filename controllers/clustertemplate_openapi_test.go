@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClusterTemplate OpenAPI document", func() {
+	It("Publishes OpenAPI document of the template parameters", func() {
+		ct := &v1alpha1.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+				UID:  "foo-uid",
+			},
+			Status: v1alpha1.ClusterTemplateStatus{
+				ClusterDefinition: v1alpha1.ClusterDefinitionSchema{
+					Version: "0.0.1",
+					Values:  "workers: 2\n",
+				},
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, ct)
+		reconciler := &ClusterTemplateReconciler{Client: k8sClient}
+		Expect(reconciler.reconcileOpenAPIDocument(context.TODO(), ct)).Should(Succeed())
+
+		cm := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: "foo-openapi", Namespace: configNamespace},
+			cm,
+		)).Should(Succeed())
+		Expect(cm.Labels).Should(HaveKeyWithValue(v1alpha1.CTOpenAPILabel, "foo"))
+		Expect(cm.OwnerReferences).Should(HaveLen(1))
+		Expect(cm.OwnerReferences[0].UID).Should(BeEquivalentTo("foo-uid"))
+		doc := map[string]interface{}{}
+		Expect(json.Unmarshal([]byte(cm.Data[v1alpha1.OpenAPIDocumentKey]), &doc)).
+			Should(Succeed())
+		Expect(doc["info"]).Should(HaveKeyWithValue("version", "0.0.1"))
+
+		// new chart version of the template
+		ct.Status.ClusterDefinition.Version = "0.0.2"
+		Expect(reconciler.reconcileOpenAPIDocument(context.TODO(), ct)).Should(Succeed())
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cm), cm)).Should(Succeed())
+		Expect(json.Unmarshal([]byte(cm.Data[v1alpha1.OpenAPIDocumentKey]), &doc)).
+			Should(Succeed())
+		Expect(doc["info"]).Should(HaveKeyWithValue("version", "0.0.2"))
+	})
+})
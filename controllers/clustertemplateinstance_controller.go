@@ -18,8 +18,11 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/kubernetes-client/go-base/config/api"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,6 +37,8 @@ import (
 
 	"github.com/stolostron/cluster-templates-operator/clusterprovider"
 	"github.com/stolostron/cluster-templates-operator/clustersetup"
+	"github.com/stolostron/cluster-templates-operator/helm"
+	"github.com/stolostron/cluster-templates-operator/hubversion"
 	"gopkg.in/yaml.v3"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
@@ -47,14 +52,22 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 var (
 	CTIlog = logf.Log.WithName("cti-controller")
+
+	errClusterUpdateFailed = errors.New("cluster update failed")
+
+	// interval of checking the Applications of the deleted instance are gone
+	applicationsDeletionCheckInterval = 15 * time.Second
 )
 
 type ClusterTemplateInstanceReconciler struct {
@@ -62,16 +75,37 @@ type ClusterTemplateInstanceReconciler struct {
 	Scheme           *runtime.Scheme
 	EnableHypershift bool
 	EnableHive       bool
+	Recorder         record.EventRecorder
+	HelmClient       *helm.HelmClient
+	// KubeClient reads logs of the chart test pods
+	KubeClient kubernetes.Interface
 }
 
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateinstances,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateinstances/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplates,verbs=get;list;watch
-// +kubebuilder:rbac:groups=hypershift.openshift.io,resources=hostedclusters;nodepools,verbs=get;list;watch
-// +kubebuilder:rbac:groups=hive.openshift.io,resources=clusterclaims;clusterdeployments,verbs=get;list;watch
-// +kubebuilder:rbac:groups=argoproj.io,resources=applications,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustersetupdefinitions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateinstanceviews,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=hypershift.openshift.io,resources=hostedclusters;nodepools,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=ingresses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=proxies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=hive.openshift.io,resources=clusterclaims,verbs=create;delete;get;list;watch
+// +kubebuilder:rbac:groups=hive.openshift.io,resources=clusterdeployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=extensions.hive.openshift.io,resources=agentclusterinstalls,verbs=get;list;watch
+// +kubebuilder:rbac:groups=agent-install.openshift.io,resources=agents,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;machines,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=argoproj.io,resources=applications,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings;roles,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=limitranges;resourcequotas,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles;clusterrolebindings,verbs=get;patch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;patch
 
 func (r *ClusterTemplateInstanceReconciler) Reconcile(
 	ctx context.Context,
@@ -85,6 +119,7 @@ func (r *ClusterTemplateInstanceReconciler) Reconcile(
 				"name",
 				req.NamespacedName,
 			)
+			trackedInstances.forget(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
@@ -96,6 +131,17 @@ func (r *ClusterTemplateInstanceReconciler) Reconcile(
 			v1alpha1.CTIFinalizer,
 		) {
 			if clusterTemplateInstance.Status.ClusterTemplateSpec != nil {
+				if hold := getDeletionHold(ctx, clusterTemplateInstance); hold != "" {
+					if err := r.holdDeletion(ctx, clusterTemplateInstance, hold); err != nil {
+						return ctrl.Result{}, err
+					}
+					return ctrl.Result{RequeueAfter: deletionGatesCheckInterval}, nil
+				}
+
+				if err := r.releaseSharedResources(ctx, clusterTemplateInstance); err != nil {
+					return ctrl.Result{}, err
+				}
+
 				app, err := clusterTemplateInstance.GetDay1Application(
 					ctx,
 					r.Client,
@@ -113,6 +159,14 @@ func (r *ClusterTemplateInstanceReconciler) Reconcile(
 					}
 				}
 
+				if err := r.deleteClusterClaim(ctx, clusterTemplateInstance); err != nil {
+					return ctrl.Result{}, err
+				}
+
+				if err := r.deleteOCMCluster(ctx, clusterTemplateInstance); err != nil {
+					return ctrl.Result{}, err
+				}
+
 				apps, err := clusterTemplateInstance.GetDay2Applications(
 					ctx,
 					r.Client,
@@ -132,6 +186,18 @@ func (r *ClusterTemplateInstanceReconciler) Reconcile(
 					}
 				}
 
+				// ArgoCD deletes the resources of the Applications before the Applications
+				// themselves, the cluster secrets, default credentials and the target namespace
+				// are needed until then
+				if app != nil || (apps != nil && len(apps.Items) > 0) {
+					CTIlog.Info(
+						"Waiting for applications to be deleted",
+						"name",
+						req.NamespacedName,
+					)
+					return ctrl.Result{RequeueAfter: applicationsDeletionCheckInterval}, nil
+				}
+
 				// cleanup argocd secrets (ie new cluster)
 				ctiNameLabelReq, _ := labels.NewRequirement(
 					v1alpha1.CTINameLabel,
@@ -157,6 +223,17 @@ func (r *ClusterTemplateInstanceReconciler) Reconcile(
 						return ctrl.Result{}, err
 					}
 				}
+
+				if err := r.deleteDefaultCredentials(ctx, clusterTemplateInstance); err != nil {
+					return ctrl.Result{}, err
+				}
+
+				if err := r.releaseTargetNamespace(ctx, clusterTemplateInstance); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+			if err := r.deleteInstanceView(ctx, clusterTemplateInstance); err != nil {
+				return ctrl.Result{}, err
 			}
 			controllerutil.RemoveFinalizer(
 				clusterTemplateInstance,
@@ -174,12 +251,45 @@ func (r *ClusterTemplateInstanceReconciler) Reconcile(
 		clusterTemplateInstance.Status.Message = v1alpha1.PendingMessage
 	}
 
+	previousPhase := clusterTemplateInstance.Status.Phase
+	// conditions are updated in place
+	previousConditions := append(
+		[]metav1.Condition{},
+		clusterTemplateInstance.Status.Conditions...,
+	)
+
 	if clusterTemplateInstance.Status.ClusterTemplateSpec == nil {
 		clusterTemplate := v1alpha1.ClusterTemplate{}
-		if err := r.Client.Get(ctx, client.ObjectKey{Name: clusterTemplateInstance.Spec.ClusterTemplateRef}, &clusterTemplate); err != nil {
+		err := r.Client.Get(ctx, client.ObjectKey{Name: clusterTemplateInstance.Spec.ClusterTemplateRef}, &clusterTemplate)
+		if err != nil {
+			err = fmt.Errorf("failed to fetch ClusterTemplate - %q", err)
+		} else if msg := clusterTemplate.GetUnsupportedMessage(); msg != "" {
+			err = fmt.Errorf("ClusterTemplate is not supported on this hub - %s", msg)
+		} else if err = clusterTemplate.Spec.ApplyAddOns(clusterTemplateInstance.Spec.AddOns); err == nil {
+			err = clusterTemplate.Spec.ResolveClusterSetupDefinitions(ctx, r.Client)
+		}
+		if err == nil && clusterTemplate.Spec.BaseDomainFromHub != nil {
+			err = r.setHubBaseDomain(ctx, &clusterTemplate.Spec)
+		}
+		if err == nil {
+			clusterTemplate.Spec.PinChartVersions(clusterTemplate.Status)
+			clusterTemplate.Spec.SetRevisionHistoryLimit(RevisionHistoryLimit)
+			// the UID is generated by the API server, so the ID is the same if the status
+			// update fails and the template is snapshotted again
+			if clusterTemplateInstance.Status.ClusterID == "" {
+				clusterTemplateInstance.Status.ClusterID = string(clusterTemplateInstance.UID)
+			}
+			clusterTemplateInstance.Status.ClusterName = clusterTemplateInstance.GenerateClusterName(
+				&clusterTemplate.Spec,
+			)
+			err = r.reattachRestoredInstance(ctx, clusterTemplateInstance, &clusterTemplate.Spec)
+		}
+		if err != nil {
 			clusterTemplateInstance.Status.Phase = v1alpha1.FailedPhase
-			errMsg := fmt.Sprintf("failed to fetch ClusterTemplate - %q", err)
-			clusterTemplateInstance.Status.Message = errMsg
+			clusterTemplateInstance.Status.Message = err.Error()
+			clusterTemplateInstance.Status.ObservedGeneration = clusterTemplateInstance.Generation
+			clusterTemplateInstance.SetReadinessConditions()
+			clusterTemplateInstance.UpdateOverview()
 			if updErr := r.Status().Update(ctx, clusterTemplateInstance); updErr != nil {
 				return ctrl.Result{}, fmt.Errorf(
 					"failed to update status of clustertemplateinstance %q: %w",
@@ -187,6 +297,9 @@ func (r *ClusterTemplateInstanceReconciler) Reconcile(
 					updErr,
 				)
 			}
+			r.recordInstanceEvents(clusterTemplateInstance, previousPhase, previousConditions)
+			r.reconcileInstanceView(ctx, clusterTemplateInstance)
+			r.reconcileStatusLabels(ctx, clusterTemplateInstance)
 			return ctrl.Result{}, err
 		}
 		clusterTemplateInstance.Status.ClusterTemplateSpec = &clusterTemplate.Spec
@@ -198,7 +311,25 @@ func (r *ClusterTemplateInstanceReconciler) Reconcile(
 		}
 	}
 
+	r.reconcileDefaultsDrift(ctx, clusterTemplateInstance)
+
+	if reconcileLifetime(clusterTemplateInstance) {
+		return r.deleteExpiredInstance(
+			ctx,
+			clusterTemplateInstance,
+			previousPhase,
+			previousConditions,
+		)
+	}
+
 	err := r.reconcile(ctx, clusterTemplateInstance)
+	// keep previous generation so the parameters update is retried
+	if !errors.Is(err, errClusterUpdateFailed) {
+		clusterTemplateInstance.Status.ObservedGeneration = clusterTemplateInstance.Generation
+	}
+	clusterTemplateInstance.SetReadinessConditions()
+	reconcileProvisioningSLO(clusterTemplateInstance)
+	clusterTemplateInstance.UpdateOverview()
 
 	if updErr := r.Status().Update(ctx, clusterTemplateInstance); updErr != nil {
 		return ctrl.Result{}, fmt.Errorf(
@@ -207,20 +338,103 @@ func (r *ClusterTemplateInstanceReconciler) Reconcile(
 			updErr,
 		)
 	}
+	r.recordInstanceEvents(clusterTemplateInstance, previousPhase, previousConditions)
+	r.reconcileInstanceView(ctx, clusterTemplateInstance)
+	r.reconcileStatusLabels(ctx, clusterTemplateInstance)
+	r.logPhaseChange(clusterTemplateInstance, previousPhase)
+	trackedInstances.setPhase(req.NamespacedName, clusterTemplateInstance.Status.Phase)
+
+	result := ctrl.Result{}
+	// make sure the timeout and injected delay elapse even if nothing else changes
+	if remaining, limited := installTimeRemaining(clusterTemplateInstance); limited && remaining > 0 {
+		result.RequeueAfter = remaining
+	}
+	if remaining, limited := setupTimeRemaining(clusterTemplateInstance); limited && remaining > 0 &&
+		(result.RequeueAfter == 0 || remaining < result.RequeueAfter) {
+		result.RequeueAfter = remaining
+	}
+	if delay := injectedReadyDelayRemaining(clusterTemplateInstance); delay > 0 &&
+		(result.RequeueAfter == 0 || delay < result.RequeueAfter) {
+		result.RequeueAfter = delay
+	}
+	// test pods are not watched
+	if chartTestsRunning(clusterTemplateInstance) &&
+		(result.RequeueAfter == 0 || chartTestsCheckInterval < result.RequeueAfter) {
+		result.RequeueAfter = chartTestsCheckInterval
+	}
+	if hibernationInProgress(clusterTemplateInstance) &&
+		(result.RequeueAfter == 0 || hibernationCheckInterval < result.RequeueAfter) {
+		result.RequeueAfter = hibernationCheckInterval
+	}
+	if setupJobsRunning(clusterTemplateInstance) &&
+		(result.RequeueAfter == 0 || setupJobsCheckInterval < result.RequeueAfter) {
+		result.RequeueAfter = setupJobsCheckInterval
+	}
+	if setupPaused(clusterTemplateInstance) &&
+		(result.RequeueAfter == 0 || setupPauseCheckInterval < result.RequeueAfter) {
+		result.RequeueAfter = setupPauseCheckInterval
+	}
+	if ocmClusterInstalling(clusterTemplateInstance) &&
+		(result.RequeueAfter == 0 || ocmCheckInterval < result.RequeueAfter) {
+		result.RequeueAfter = ocmCheckInterval
+	}
+	if agentInstallInProgress(clusterTemplateInstance) &&
+		(result.RequeueAfter == 0 || agentCheckInterval < result.RequeueAfter) {
+		result.RequeueAfter = agentCheckInterval
+	}
+	if after := lifetimeCheckAfter(clusterTemplateInstance); after > 0 &&
+		(result.RequeueAfter == 0 || after < result.RequeueAfter) {
+		result.RequeueAfter = after
+	}
+	if after := provisioningSLOCheckAfter(clusterTemplateInstance); after > 0 &&
+		(result.RequeueAfter == 0 || after < result.RequeueAfter) {
+		result.RequeueAfter = after
+	}
+	return result, err
+}
 
-	return ctrl.Result{}, err
+// setHubBaseDomain sets the base domain of the cluster derived from the ingress domain of the hub
+func (r *ClusterTemplateInstanceReconciler) setHubBaseDomain(
+	ctx context.Context,
+	ctSpec *v1alpha1.ClusterTemplateSpec,
+) error {
+	ingressDomain, err := hubversion.GetIngressDomain(ctx, r.Client)
+	if err != nil {
+		return fmt.Errorf("failed to get ingress domain of the hub - %q", err)
+	}
+	if ingressDomain == "" {
+		return fmt.Errorf("base domain can not be derived, ingress domain of the hub was not detected")
+	}
+	ctSpec.SetBaseDomain(ctSpec.GetHubBaseDomain(ingressDomain))
+	return nil
 }
 
 func (r *ClusterTemplateInstanceReconciler) reconcile(
 	ctx context.Context,
 	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
 ) error {
+	if isPreview(clusterTemplateInstance) {
+		if err := r.reconcilePreview(ctx, clusterTemplateInstance); err != nil {
+			clusterTemplateInstance.Status.Phase = v1alpha1.PreviewFailedPhase
+			errMsg := fmt.Sprintf("failed to render preview - %q", err)
+			clusterTemplateInstance.Status.Message = errMsg
+			return fmt.Errorf(errMsg)
+		}
+		return nil
+	}
+
 	if err := r.reconcileClusterCreate(ctx, clusterTemplateInstance); err != nil {
 		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterDefinitionFailedPhase
 		errMsg := fmt.Sprintf("failed to create cluster definition - %q", err)
 		clusterTemplateInstance.Status.Message = errMsg
 		return fmt.Errorf(errMsg)
 	}
+	if err := r.reconcileClusterUpdate(ctx, clusterTemplateInstance); err != nil {
+		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterDefinitionFailedPhase
+		errMsg := fmt.Sprintf("failed to update cluster parameters - %q", err)
+		clusterTemplateInstance.Status.Message = errMsg
+		return fmt.Errorf("%s: %w", errMsg, errClusterUpdateFailed)
+	}
 	if err := r.reconcileClusterStatus(
 		ctx,
 		clusterTemplateInstance,
@@ -231,6 +445,47 @@ func (r *ClusterTemplateInstanceReconciler) reconcile(
 		return fmt.Errorf(errMsg)
 	}
 
+	if err := r.reconcileInventory(ctx, clusterTemplateInstance); err != nil {
+		// the inventory is only used on deletion, it is retried on the next reconcile
+		CTIlog.Error(
+			err,
+			"Failed to record cluster-scoped resources",
+			"name",
+			clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		)
+	}
+
+	if err := r.reconcileChartTests(ctx, clusterTemplateInstance); err != nil {
+		// the tests do not affect the cluster, checking them is retried on the next reconcile
+		CTIlog.Error(
+			err,
+			"Failed to check chart tests",
+			"name",
+			clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		)
+	}
+
+	if err := r.reconcileClusterUpgrade(ctx, clusterTemplateInstance); err != nil {
+		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterUpgradeFailedPhase
+		errMsg := fmt.Sprintf("failed to upgrade cluster - %q", err)
+		clusterTemplateInstance.Status.Message = errMsg
+		return fmt.Errorf(errMsg)
+	}
+
+	if err := r.reconcileNodePoolScaling(ctx, clusterTemplateInstance); err != nil {
+		clusterTemplateInstance.Status.Phase = v1alpha1.NodePoolScalingFailedPhase
+		errMsg := fmt.Sprintf("failed to scale node pools - %q", err)
+		clusterTemplateInstance.Status.Message = errMsg
+		return fmt.Errorf(errMsg)
+	}
+
+	if err := r.reconcileHibernation(ctx, clusterTemplateInstance); err != nil {
+		clusterTemplateInstance.Status.Phase = v1alpha1.HibernationFailedPhase
+		errMsg := fmt.Sprintf("failed to hibernate cluster - %q", err)
+		clusterTemplateInstance.Status.Message = errMsg
+		return fmt.Errorf(errMsg)
+	}
+
 	if err := r.reconcileAddClusterToArgo(ctx, clusterTemplateInstance); err != nil {
 		clusterTemplateInstance.Status.Phase = v1alpha1.ArgoClusterFailedPhase
 		errMsg := fmt.Sprintf("failed to add cluster to argo - %q", err)
@@ -238,14 +493,26 @@ func (r *ClusterTemplateInstanceReconciler) reconcile(
 		return fmt.Errorf(errMsg)
 	}
 
-	if err := r.reconcileClusterSetupCreate(ctx, clusterTemplateInstance); err != nil {
+	// the new cluster setup Jobs run on is accessed at most once per reconcile
+	jobClients := r.newSetupJobClients(clusterTemplateInstance)
+	if err := r.reconcileClusterSetupCreate(ctx, clusterTemplateInstance, jobClients); err != nil {
 		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterSetupCreateFailedPhase
 		errMsg := fmt.Sprintf("failed to create cluster setup - %q", err)
 		clusterTemplateInstance.Status.Message = errMsg
 		return fmt.Errorf(errMsg)
 	}
 
-	if err := r.reconcileClusterSetup(ctx, clusterTemplateInstance); err != nil {
+	if err := r.reconcileSetupPause(ctx, clusterTemplateInstance); err != nil {
+		// the setups keep syncing, pausing them is retried on the next reconcile
+		CTIlog.Error(
+			err,
+			"Failed to check upgrade of the cluster",
+			"name",
+			clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		)
+	}
+
+	if err := r.reconcileClusterSetup(ctx, clusterTemplateInstance, jobClients); err != nil {
 		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterSetupFailedPhase
 		errMsg := fmt.Sprintf("failed to reconcile cluster setup - %q", err)
 		clusterTemplateInstance.Status.Message = errMsg
@@ -273,6 +540,111 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterCreate(
 	)
 
 	if clusterDefinitionCreatedCondition.Status == metav1.ConditionFalse {
+		if installFailureInjected(clusterTemplateInstance) {
+			clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
+				metav1.ConditionFalse,
+				v1alpha1.ClusterDefinitionFailed,
+				"Installation failure injected by operator config",
+			)
+			return fmt.Errorf(
+				"injected install failure for ClusterTemplate %s",
+				clusterTemplateInstance.Spec.ClusterTemplateRef,
+			)
+		}
+		if clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterPool != nil {
+			if err := r.createClusterClaim(ctx, clusterTemplateInstance); err != nil {
+				clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
+					metav1.ConditionFalse,
+					v1alpha1.ClusterDefinitionFailed,
+					fmt.Sprintf("Failed to create cluster claim - %q", err),
+				)
+				return err
+			}
+			clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
+				metav1.ConditionTrue,
+				v1alpha1.ClusterClaimCreated,
+				"ClusterClaim created",
+			)
+			return nil
+		}
+		if clusterTemplateInstance.Status.ClusterTemplateSpec.OCM != nil {
+			if err := r.createOCMCluster(ctx, clusterTemplateInstance); err != nil {
+				clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
+					metav1.ConditionFalse,
+					v1alpha1.ClusterDefinitionFailed,
+					fmt.Sprintf("Failed to create OCM cluster - %q", err),
+				)
+				return err
+			}
+			clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
+				metav1.ConditionTrue,
+				v1alpha1.OCMClusterCreated,
+				"OCM cluster created",
+			)
+			return nil
+		}
+		if err := r.copyTrustedCABundle(ctx, clusterTemplateInstance); err != nil {
+			clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
+				metav1.ConditionFalse,
+				v1alpha1.TrustedCABundleFailed,
+				fmt.Sprintf("Failed to copy trusted CA bundle - %q", err),
+			)
+			return err
+		}
+		if err := r.validateClusterDefinitionValues(ctx, clusterTemplateInstance); err != nil {
+			clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
+				metav1.ConditionFalse,
+				v1alpha1.ValuesValidationFailed,
+				fmt.Sprintf("Values do not match chart schema - %q", err),
+			)
+			return err
+		}
+		ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+		if err := r.verifyChart(
+			ctx,
+			clusterTemplateInstance,
+			"",
+			&ctSpec.ClusterDefinition.Source,
+		); err != nil {
+			clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
+				metav1.ConditionFalse,
+				v1alpha1.ChartVerificationFailed,
+				fmt.Sprintf("Failed to verify cluster definition chart - %q", err),
+			)
+			return err
+		}
+		if err := r.setHostingClusterDestination(ctx, clusterTemplateInstance); err != nil {
+			clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
+				metav1.ConditionFalse,
+				v1alpha1.ClusterDefinitionFailed,
+				fmt.Sprintf("Failed to add hosting cluster to argo - %q", err),
+			)
+			return err
+		}
+		if err := r.ensureTargetNamespace(ctx, clusterTemplateInstance); err != nil {
+			clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
+				metav1.ConditionFalse,
+				v1alpha1.ClusterDefinitionFailed,
+				fmt.Sprintf("Failed to create target namespace - %q", err),
+			)
+			return err
+		}
+		if err := r.copyDefaultCredentials(ctx, clusterTemplateInstance); err != nil {
+			clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
+				metav1.ConditionFalse,
+				v1alpha1.DefaultCredentialsFailed,
+				fmt.Sprintf("Failed to copy default credentials - %q", err),
+			)
+			return err
+		}
+		if err := r.checkClusterNameAvailable(ctx, clusterTemplateInstance); err != nil {
+			clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
+				metav1.ConditionFalse,
+				v1alpha1.ClusterNameConflict,
+				fmt.Sprintf("Cluster name is not available - %q", err),
+			)
+			return err
+		}
 		if err := clusterTemplateInstance.CreateDay1Application(ctx, r.Client, ArgoCDNamespace); err != nil {
 			clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
 				metav1.ConditionFalse,
@@ -290,11 +662,103 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterCreate(
 	return nil
 }
 
-func (r *ClusterTemplateInstanceReconciler) reconcileClusterStatus(
+// reconcileClusterUpdate propagates changed instance parameters to the applications,
+// ArgoCD then syncs them (upgrades the Helm releases)
+func (r *ClusterTemplateInstanceReconciler) reconcileClusterUpdate(
 	ctx context.Context,
 	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
 ) error {
+	observedGeneration := clusterTemplateInstance.Status.ObservedGeneration
+	if observedGeneration == 0 || observedGeneration == clusterTemplateInstance.Generation {
+		return nil
+	}
 	CTIlog.Info(
+		"Instance parameters changed, updating applications",
+		"name",
+		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+	)
+	if err := r.validateClusterDefinitionValues(ctx, clusterTemplateInstance); err != nil {
+		return err
+	}
+	return clusterTemplateInstance.UpdateApplicationsParameters(ctx, r.Client, ArgoCDNamespace)
+}
+
+// validateClusterDefinitionValues validates instance values against values.schema.json of
+// the cluster definition chart. Values and schema of the chart are taken from the ClusterTemplate
+// status, where they are kept up to date by the ClusterTemplate controller.
+func (r *ClusterTemplateInstanceReconciler) validateClusterDefinitionValues(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+	if ctSpec.ClusterDefinition.Source.Chart == "" && ctSpec.HelmChartURL == "" {
+		return nil
+	}
+	clusterTemplate := &v1alpha1.ClusterTemplate{}
+	if err := r.Client.Get(
+		ctx,
+		client.ObjectKey{Name: clusterTemplateInstance.Spec.ClusterTemplateRef},
+		clusterTemplate,
+	); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	chartStatus := clusterTemplate.Status.ClusterDefinition
+	if chartStatus.Error != nil {
+		return nil
+	}
+	lintClusterDefinitionParameters(clusterTemplateInstance, chartStatus)
+	valuesFrom, err := clusterTemplateInstance.GetValuesFrom(ctx, r.Client, "")
+	if err != nil {
+		return err
+	}
+	return clusterTemplateInstance.ValidateClusterDefinitionValues(
+		chartStatus.Values,
+		chartStatus.Schema,
+		valuesFrom,
+	)
+}
+
+// lintClusterDefinitionParameters warns about parameters which are not used by the chart,
+// the installation continues as helm ignores such values
+func lintClusterDefinitionParameters(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	chartStatus v1alpha1.ClusterDefinitionSchema,
+) {
+	unknown, deprecated, err := clusterTemplateInstance.LintClusterDefinitionParameters(
+		chartStatus.Values,
+		chartStatus.Schema,
+	)
+	if err != nil {
+		// invalid values are reported by the schema validation
+		return
+	}
+	switch {
+	case len(unknown) > 0:
+		clusterTemplateInstance.SetParametersValidCondition(
+			metav1.ConditionFalse,
+			v1alpha1.UnknownParameters,
+			fmt.Sprintf("Parameters are not defined by the chart and will be ignored - %v", unknown),
+		)
+	case len(deprecated) > 0:
+		clusterTemplateInstance.SetParametersValidCondition(
+			metav1.ConditionFalse,
+			v1alpha1.DeprecatedParameters,
+			fmt.Sprintf("Parameters are deprecated by the chart - %v", deprecated),
+		)
+	default:
+		clusterTemplateInstance.SetParametersValidCondition(
+			metav1.ConditionTrue,
+			v1alpha1.ParametersKnown,
+			"All parameters are defined by the chart",
+		)
+	}
+}
+
+func (r *ClusterTemplateInstanceReconciler) reconcileClusterStatus(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	CTIlog.V(1).Info(
 		"Reconcile instance status",
 		"name",
 		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
@@ -307,7 +771,173 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterStatus(
 		return nil
 	}
 
-	CTIlog.Info(
+	// the application was deleted, keep the failure
+	installCondition := meta.FindStatusCondition(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.ClusterInstallSucceeded),
+	)
+	if installCondition.Reason == string(v1alpha1.ClusterInstallRolledBack) {
+		return r.retryRolledBackInstall(ctx, clusterTemplateInstance)
+	}
+
+	var provider clusterprovider.ClusterProvider
+	if clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterPool != nil {
+		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallingPhase
+		clusterTemplateInstance.Status.Message = "Cluster is being claimed from the pool"
+		provider = getClusterPoolProvider(clusterTemplateInstance)
+	} else if clusterTemplateInstance.Status.ClusterTemplateSpec.OCM != nil {
+		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallingPhase
+		clusterTemplateInstance.Status.Message = "Cluster is being installed by OCM"
+		var err error
+		if provider, err = r.getOCMProvider(ctx, clusterTemplateInstance); err != nil {
+			msg := fmt.Sprintf("Failed to access OCM - %q", err)
+			clusterTemplateInstance.SetClusterInstallCondition(
+				metav1.ConditionFalse,
+				v1alpha1.ClusterStatusFailed,
+				msg,
+			)
+			clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallFailedPhase
+			clusterTemplateInstance.Status.Message = msg
+			return err
+		}
+	} else {
+		var err error
+		provider, err = r.getApplicationClusterProvider(ctx, clusterTemplateInstance)
+		if provider == nil || err != nil {
+			return err
+		}
+		if hostedCluster, ok := provider.(clusterprovider.HostedClusterProvider); ok {
+			if err := r.ensureControlPlaneQuota(ctx, clusterTemplateInstance, hostedCluster); err != nil {
+				msg := fmt.Sprintf("Failed to create control plane quota - %q", err)
+				clusterTemplateInstance.SetClusterInstallCondition(
+					metav1.ConditionFalse,
+					v1alpha1.ClusterStatusFailed,
+					msg,
+				)
+				clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallFailedPhase
+				clusterTemplateInstance.Status.Message = msg
+				return err
+			}
+		}
+	}
+
+	ready, status, err := provider.GetClusterStatus(ctx, r.Client, *clusterTemplateInstance)
+	if trackedInstances.statusChanged(client.ObjectKeyFromObject(clusterTemplateInstance), status) {
+		CTIlog.Info(
+			"Instance status - "+status,
+			"name",
+			clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Failed to detect cluster status - %q", err)
+		clusterTemplateInstance.SetClusterInstallCondition(
+			metav1.ConditionFalse,
+			v1alpha1.ClusterStatusFailed,
+			msg,
+		)
+		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallFailedPhase
+		clusterTemplateInstance.Status.Message = msg
+		return err
+	}
+
+	if platformProvider, ok := provider.(clusterprovider.PlatformStatusProvider); ok {
+		// platform details are informative, failing to read them does not fail the instance
+		if platform, err := platformProvider.GetPlatformStatus(ctx, r.Client); err != nil {
+			CTIlog.Error(
+				err,
+				"Failed to detect cluster platform",
+				"name",
+				clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+			)
+		} else {
+			clusterTemplateInstance.Status.Platform = platform
+		}
+	}
+	if nodePoolProvider, ok := provider.(clusterprovider.NodePoolStatusProvider); ok {
+		if nodePools, err := nodePoolProvider.GetNodePoolStatus(ctx, r.Client); err != nil {
+			CTIlog.Error(
+				err,
+				"Failed to detect node pools",
+				"name",
+				clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+			)
+		} else {
+			clusterTemplateInstance.Status.NodePools = nodePools
+		}
+	}
+	if conditionsProvider, ok := provider.(clusterprovider.ClusterConditionsProvider); ok {
+		if conditions, err := conditionsProvider.GetClusterConditions(ctx, r.Client); err != nil {
+			CTIlog.Error(
+				err,
+				"Failed to detect cluster conditions",
+				"name",
+				clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+			)
+		} else {
+			for _, condition := range conditions {
+				meta.SetStatusCondition(&clusterTemplateInstance.Status.Conditions, condition)
+			}
+		}
+	}
+
+	if consoleProvider, ok := provider.(clusterprovider.ConsoleURLProvider); ok && ready {
+		if consoleURL, err := consoleProvider.GetConsoleURL(ctx, r.Client); err != nil {
+			CTIlog.Error(
+				err,
+				"Failed to detect cluster console URL",
+				"name",
+				clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+			)
+		} else if consoleURL != "" {
+			clusterTemplateInstance.Status.ConsoleURL = consoleURL
+		}
+	}
+	if versionProvider, ok := provider.(clusterprovider.VersionProvider); ok && ready {
+		if version, err := versionProvider.GetOpenShiftVersion(ctx, r.Client); err != nil {
+			CTIlog.Error(
+				err,
+				"Failed to detect cluster version",
+				"name",
+				clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+			)
+		} else if version != "" {
+			clusterTemplateInstance.Status.OpenShiftVersion = version
+		}
+	}
+
+	if ready && injectedReadyDelayRemaining(clusterTemplateInstance) > 0 {
+		ready = false
+		status = "Cluster availability delayed by operator config"
+	}
+
+	if ready {
+		clusterTemplateInstance.SetClusterInstallCondition(
+			metav1.ConditionTrue,
+			v1alpha1.ClusterInstalled,
+			status,
+		)
+	} else {
+		clusterTemplateInstance.SetClusterInstallCondition(
+			metav1.ConditionFalse,
+			v1alpha1.ClusterInstalling,
+			status,
+		)
+		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallingPhase
+		clusterTemplateInstance.Status.Message = "Cluster is installing"
+	}
+
+	return nil
+}
+
+// getApplicationClusterProvider tracks the sync of the cluster definition application and
+// detects the cluster provider from the resources of the application. Nil provider is returned
+// until the status of the cluster can be read from the provider.
+func (r *ClusterTemplateInstanceReconciler) getApplicationClusterProvider(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) (clusterprovider.ClusterProvider, error) {
+	CTIlog.V(1).Info(
 		"Fetch day1 argo application",
 		"name",
 		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
@@ -318,12 +948,32 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterStatus(
 		failedMsg := fmt.Sprintf("Failed to fetch application - %q", err)
 		clusterTemplateInstance.SetClusterInstallCondition(
 			metav1.ConditionFalse,
-			v1alpha1.ApplicationFetchFailed,
-			failedMsg,
+			v1alpha1.ApplicationFetchFailed,
+			failedMsg,
+		)
+		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallFailedPhase
+		clusterTemplateInstance.Status.Message = failedMsg
+		return nil, err
+	}
+
+	// resources are expected to be out of sync until the first sync finishes
+	if len(application.Status.History) > 0 {
+		reconcileClusterDrift(clusterTemplateInstance, application)
+	}
+
+	if installTimedOut(clusterTemplateInstance) {
+		msg := fmt.Sprintf(
+			"Cluster installation did not finish within %s",
+			clusterTemplateInstance.Status.ClusterTemplateSpec.InstallOptions.Timeout.Duration,
+		)
+		clusterTemplateInstance.SetClusterInstallCondition(
+			metav1.ConditionFalse,
+			v1alpha1.ClusterInstallTimedOut,
+			msg,
 		)
 		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallFailedPhase
-		clusterTemplateInstance.Status.Message = failedMsg
-		return err
+		clusterTemplateInstance.Status.Message = msg
+		return nil, r.rollbackFailedInstall(ctx, clusterTemplateInstance, application, msg)
 	}
 
 	appHealth, msg := argocd.GetApplicationHealth(application)
@@ -335,7 +985,7 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterStatus(
 		)
 		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallingPhase
 		clusterTemplateInstance.Status.Message = msg
-		return nil
+		return nil, nil
 	}
 
 	if appHealth == argocd.ApplicationDegraded {
@@ -346,7 +996,7 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterStatus(
 		)
 		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallFailedPhase
 		clusterTemplateInstance.Status.Message = msg
-		return nil
+		return nil, r.rollbackFailedInstall(ctx, clusterTemplateInstance, application, msg)
 	}
 
 	if appHealth == argocd.ApplicationError {
@@ -357,38 +1007,30 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterStatus(
 		)
 		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallFailedPhase
 		clusterTemplateInstance.Status.Message = msg
-		return nil
-	}
-
-	clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallingPhase
-	clusterTemplateInstance.Status.Message = "Cluster is installing"
-	if _, ok := clusterTemplateInstance.Annotations[clusterprovider.ClusterProviderExperimentalAnnotation]; ok {
-		CTIlog.Info("Experimental provider specified", "name", clusterTemplateInstance.Name)
-		return nil
+		return nil, r.rollbackFailedInstall(ctx, clusterTemplateInstance, application, msg)
 	}
 
-	provider := clusterprovider.GetClusterProvider(*application)
-
-	if provider == nil {
-		msg := "Unknown cluster provider - only Hive and Hypershift clusters are recognized"
+	if appHealth == argocd.ApplicationSyncFailed {
 		clusterTemplateInstance.SetClusterInstallCondition(
 			metav1.ConditionFalse,
-			v1alpha1.ClusterProviderDetectionFailed,
+			v1alpha1.ApplicationSyncFailed,
 			msg,
 		)
 		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallFailedPhase
 		clusterTemplateInstance.Status.Message = msg
-		return nil
+		return nil, r.rollbackFailedInstall(ctx, clusterTemplateInstance, application, msg)
 	}
 
-	ready, status, err := provider.GetClusterStatus(ctx, r.Client, *clusterTemplateInstance)
-	CTIlog.Info(
-		"Instance status - "+status,
-		"name",
-		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
-	)
+	clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallingPhase
+	clusterTemplateInstance.Status.Message = "Cluster is installing"
+	if _, ok := clusterTemplateInstance.Annotations[clusterprovider.ClusterProviderExperimentalAnnotation]; ok {
+		CTIlog.Info("Experimental provider specified", "name", clusterTemplateInstance.Name)
+		return nil, nil
+	}
+
+	provider, err := r.getClusterProvider(ctx, clusterTemplateInstance, application)
 	if err != nil {
-		msg := fmt.Sprintf("Failed to detect cluster status - %q", err)
+		msg := fmt.Sprintf("Failed to access hosting cluster - %q", err)
 		clusterTemplateInstance.SetClusterInstallCondition(
 			metav1.ConditionFalse,
 			v1alpha1.ClusterStatusFailed,
@@ -396,26 +1038,22 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterStatus(
 		)
 		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallFailedPhase
 		clusterTemplateInstance.Status.Message = msg
-		return err
+		return nil, err
 	}
 
-	if ready {
-		clusterTemplateInstance.SetClusterInstallCondition(
-			metav1.ConditionTrue,
-			v1alpha1.ClusterInstalled,
-			status,
-		)
-	} else {
+	if provider == nil {
+		msg := "Unknown cluster provider - only " + clusterprovider.GetProviderNames() +
+			" clusters are recognized"
 		clusterTemplateInstance.SetClusterInstallCondition(
 			metav1.ConditionFalse,
-			v1alpha1.ClusterInstalling,
-			status,
+			v1alpha1.ClusterProviderDetectionFailed,
+			msg,
 		)
-		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallingPhase
-		clusterTemplateInstance.Status.Message = "Cluster is installing"
+		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallFailedPhase
+		clusterTemplateInstance.Status.Message = msg
+		return nil, nil
 	}
-
-	return nil
+	return provider, nil
 }
 
 func (r *ClusterTemplateInstanceReconciler) reconcileClusterCredentials(
@@ -431,29 +1069,90 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterCredentials(
 		return nil
 	}
 
-	if clusterTemplateInstance.Status.APIserverURL == "" {
-		kubeconfigSecret := corev1.Secret{}
+	// the kubeconfig is refreshed when the provider rotates it, keep the URLs in sync with it
+	kubeconfigSecret := corev1.Secret{}
 
-		if err := r.Client.Get(
-			ctx,
-			client.ObjectKey{
-				Name:      clusterTemplateInstance.GetKubeconfigRef(),
-				Namespace: clusterTemplateInstance.Namespace,
-			},
-			&kubeconfigSecret,
-		); err != nil {
-			return err
-		}
+	if err := r.Client.Get(
+		ctx,
+		client.ObjectKey{
+			Name:      clusterTemplateInstance.GetKubeconfigRef(),
+			Namespace: clusterTemplateInstance.Namespace,
+		},
+		&kubeconfigSecret,
+	); err != nil {
+		return err
+	}
 
-		kubeconfig := api.Config{}
-		if err := yaml.Unmarshal(kubeconfigSecret.Data["kubeconfig"], &kubeconfig); err != nil {
-			return err
-		}
-		clusterTemplateInstance.Status.APIserverURL = kubeconfig.Clusters[0].Cluster.Server
+	kubeconfig := api.Config{}
+	if err := yaml.Unmarshal(kubeconfigSecret.Data["kubeconfig"], &kubeconfig); err != nil {
+		return err
+	}
+	internalURL, err := clusterprovider.GetInternalAPIServerURL(
+		kubeconfigSecret.Data["kubeconfig"],
+	)
+	if err != nil {
+		return err
+	}
+	apiServerURL := kubeconfig.Clusters[0].Cluster.Server
+	if r.Recorder != nil && clusterTemplateInstance.Status.APIserverURL != "" &&
+		clusterTemplateInstance.Status.APIserverURL != apiServerURL {
+		r.Recorder.Event(
+			clusterTemplateInstance,
+			corev1.EventTypeNormal,
+			"APIServerURLChanged",
+			fmt.Sprintf(
+				"API server URL changed from %s to %s",
+				clusterTemplateInstance.Status.APIserverURL,
+				apiServerURL,
+			),
+		)
+	}
+	clusterTemplateInstance.Status.APIserverURL = apiServerURL
+	clusterTemplateInstance.Status.APIserverInternalURL = internalURL
+
+	if err := r.reconcileConsoleURL(
+		ctx,
+		clusterTemplateInstance,
+		kubeconfigSecret.Data["kubeconfig"],
+	); err != nil {
+		// the console URL is informative, failing to read it does not fail the instance
+		CTIlog.Error(
+			err,
+			"Failed to read cluster console URL",
+			"name",
+			clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		)
+	}
+	if err := r.reconcileOpenShiftVersion(
+		ctx,
+		clusterTemplateInstance,
+		kubeconfigSecret.Data["kubeconfig"],
+	); err != nil {
+		CTIlog.Error(
+			err,
+			"Failed to read cluster version",
+			"name",
+			clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		)
 	}
 
-	clusterTemplateInstance.Status.AdminPassword = &corev1.LocalObjectReference{
-		Name: clusterTemplateInstance.GetKubeadminPassRef(),
+	// not every cluster provider creates admin credentials (ie Cluster API)
+	kubeadminSecret := corev1.Secret{}
+	if err := r.Client.Get(
+		ctx,
+		client.ObjectKey{
+			Name:      clusterTemplateInstance.GetKubeadminPassRef(),
+			Namespace: clusterTemplateInstance.Namespace,
+		},
+		&kubeadminSecret,
+	); err == nil {
+		clusterTemplateInstance.Status.AdminPassword = &corev1.LocalObjectReference{
+			Name: clusterTemplateInstance.GetKubeadminPassRef(),
+		}
+	} else if apierrors.IsNotFound(err) {
+		clusterTemplateInstance.Status.AdminPassword = nil
+	} else {
+		return err
 	}
 	clusterTemplateInstance.Status.Kubeconfig = &corev1.LocalObjectReference{
 		Name: clusterTemplateInstance.GetKubeconfigRef(),
@@ -491,7 +1190,7 @@ func (*ClusterTemplateInstanceReconciler) ReconcileDynamicRoles(
 		return fmt.Errorf(errMsg)
 	}
 
-	role, err := clusterTemplateInstance.CreateDynamicRole(ctx, k8sClient)
+	role, err := clusterTemplateInstance.CreateDynamicRole(ctx, k8sClient, AuditCredentialAccess)
 
 	if err != nil {
 		clusterTemplateInstance.Status.Phase = v1alpha1.CredentialsFailedPhase
@@ -515,6 +1214,157 @@ func (*ClusterTemplateInstanceReconciler) ReconcileDynamicRoles(
 	return nil
 }
 
+// isClusterInstalled returns true if the cluster finished its installation at least once
+func isClusterInstalled(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) bool {
+	return meta.IsStatusConditionTrue(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.ClusterInstallSucceeded),
+	) || meta.IsStatusConditionTrue(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.ArgoClusterAdded),
+	)
+}
+
+// installTimeRemaining returns time left until the installation times out. The second
+// return value is false if the installation is not limited (anymore)
+func installTimeRemaining(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) (time.Duration, bool) {
+	if clusterTemplateInstance.Status.ClusterTemplateSpec == nil {
+		return 0, false
+	}
+	installOptions := clusterTemplateInstance.Status.ClusterTemplateSpec.InstallOptions
+	if installOptions == nil || installOptions.Timeout == nil || isClusterInstalled(clusterTemplateInstance) {
+		return 0, false
+	}
+	appCreatedCondition := meta.FindStatusCondition(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.ClusterDefinitionCreated),
+	)
+	if appCreatedCondition == nil || appCreatedCondition.Status != metav1.ConditionTrue {
+		return 0, false
+	}
+	elapsed := time.Since(appCreatedCondition.LastTransitionTime.Time)
+	return installOptions.Timeout.Duration - elapsed, true
+}
+
+func installTimedOut(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) bool {
+	remaining, limited := installTimeRemaining(clusterTemplateInstance)
+	return limited && remaining <= 0
+}
+
+// rollbackFailedInstall deletes the day1 application of atomic installations, ArgoCD then
+// deletes all resources which were created by the application
+func (r *ClusterTemplateInstanceReconciler) rollbackFailedInstall(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	application *argo.Application,
+	failedMsg string,
+) error {
+	installOptions := clusterTemplateInstance.Status.ClusterTemplateSpec.InstallOptions
+	if installOptions == nil || !installOptions.Atomic || isClusterInstalled(clusterTemplateInstance) {
+		return nil
+	}
+	CTIlog.Info(
+		"Cluster installation failed, deleting cluster definition application",
+		"name",
+		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+	)
+	if err := r.Client.Delete(ctx, application); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	msg := fmt.Sprintf("%s. Cluster resources were deleted", failedMsg)
+	clusterTemplateInstance.SetClusterInstallCondition(
+		metav1.ConditionFalse,
+		v1alpha1.ClusterInstallRolledBack,
+		msg,
+	)
+	clusterTemplateInstance.Status.Message = msg
+	return nil
+}
+
+// retryRolledBackInstall resets the cluster definition once the rolled back application is
+// gone, so the next reconcile recreates it. Does nothing when retries are exhausted
+func (r *ClusterTemplateInstanceReconciler) retryRolledBackInstall(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	installOptions := clusterTemplateInstance.Status.ClusterTemplateSpec.InstallOptions
+	if installOptions == nil || clusterTemplateInstance.Status.InstallRetries >= installOptions.Retries {
+		return nil
+	}
+	_, err := clusterTemplateInstance.GetDay1Application(ctx, r.Client, ArgoCDNamespace)
+	if err == nil {
+		// ArgoCD is still deleting the resources of the failed installation
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	clusterTemplateInstance.Status.InstallRetries++
+	CTIlog.Info(
+		"Retrying cluster installation",
+		"name",
+		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		"retry",
+		clusterTemplateInstance.Status.InstallRetries,
+	)
+	msg := fmt.Sprintf(
+		"Retrying cluster installation (%d/%d)",
+		clusterTemplateInstance.Status.InstallRetries,
+		installOptions.Retries,
+	)
+	clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
+		metav1.ConditionFalse,
+		v1alpha1.ClusterDefinitionPending,
+		msg,
+	)
+	clusterTemplateInstance.SetClusterInstallCondition(
+		metav1.ConditionFalse,
+		v1alpha1.ClusterDefinitionNotCreated,
+		msg,
+	)
+	clusterTemplateInstance.Status.Phase = v1alpha1.PendingPhase
+	clusterTemplateInstance.Status.Message = msg
+	return nil
+}
+
+func reconcileClusterDrift(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	application *argo.Application,
+) {
+	drifted := argocd.GetDriftedResources(application)
+	if len(drifted) == 0 {
+		clusterTemplateInstance.SetClusterDefinitionDriftedCondition(
+			metav1.ConditionFalse,
+			v1alpha1.NoDrift,
+			"Cluster resources match the cluster definition",
+		)
+		return
+	}
+	CTIlog.Info(
+		"Cluster resources drifted",
+		"name",
+		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		"resources",
+		drifted,
+	)
+	msg := fmt.Sprintf("Resources changed outside of ArgoCD - %s", strings.Join(drifted, ", "))
+	if clusterTemplateInstance.Status.ClusterTemplateSpec.SelfHeal {
+		clusterTemplateInstance.SetClusterDefinitionDriftedCondition(
+			metav1.ConditionTrue,
+			v1alpha1.DriftResyncing,
+			msg+". Resources are being re-applied",
+		)
+		return
+	}
+	clusterTemplateInstance.SetClusterDefinitionDriftedCondition(
+		metav1.ConditionTrue,
+		v1alpha1.DriftDetected,
+		msg,
+	)
+}
+
 func (r *ClusterTemplateInstanceReconciler) reconcileAddClusterToArgo(
 	ctx context.Context,
 	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
@@ -562,6 +1412,7 @@ func (r *ClusterTemplateInstanceReconciler) reconcileAddClusterToArgo(
 func (r *ClusterTemplateInstanceReconciler) reconcileClusterSetupCreate(
 	ctx context.Context,
 	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	jobClients *setupJobClients,
 ) error {
 
 	argoClusterAddedCondition := meta.FindStatusCondition(
@@ -596,11 +1447,58 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterSetupCreate(
 		"name",
 		clusterTemplateInstance.Name,
 	)
+	ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+	for i, setup := range ctSpec.ClusterSetup {
+		if !setup.UsesApplication() {
+			continue
+		}
+		if err := r.verifyChart(
+			ctx,
+			clusterTemplateInstance,
+			setup.Name,
+			&ctSpec.ClusterSetup[i].Spec.Source,
+		); err != nil {
+			clusterTemplateInstance.SetClusterSetupCreatedCondition(
+				metav1.ConditionFalse,
+				v1alpha1.SetupChartVerificationFailed,
+				fmt.Sprintf("Failed to verify chart of cluster setup %s - %q", setup.Name, err),
+			)
+			return err
+		}
+	}
+	if err := r.reconcileSetupCredentials(ctx, clusterTemplateInstance); err != nil {
+		clusterTemplateInstance.SetClusterSetupCreatedCondition(
+			metav1.ConditionFalse,
+			v1alpha1.SetupIdentityFailed,
+			fmt.Sprintf("Failed to create credentials of cluster setup - %q", err),
+		)
+		return err
+	}
 	if err := clusterTemplateInstance.CreateDay2Applications(
 		ctx,
 		r.Client,
 		ArgoCDNamespace,
 	); err != nil {
+		setupErr := &v1alpha1.SetupCreationError{}
+		if errors.As(err, &setupErr) {
+			r.setSetupCreationStatus(ctx, clusterTemplateInstance, setupErr)
+		}
+		clusterTemplateInstance.SetClusterSetupCreatedCondition(
+			metav1.ConditionFalse,
+			v1alpha1.ClusterSetupCreationFailed,
+			fmt.Sprintf("Failed to create cluster setup - %q", err),
+		)
+		return err
+	}
+	if err := r.createSetupJobs(ctx, clusterTemplateInstance, jobClients); err != nil {
+		clusterTemplateInstance.SetClusterSetupCreatedCondition(
+			metav1.ConditionFalse,
+			v1alpha1.ClusterSetupCreationFailed,
+			fmt.Sprintf("Failed to create cluster setup - %q", err),
+		)
+		return err
+	}
+	if err := r.launchSetupAnsibleJobs(ctx, clusterTemplateInstance); err != nil {
 		clusterTemplateInstance.SetClusterSetupCreatedCondition(
 			metav1.ConditionFalse,
 			v1alpha1.ClusterSetupCreationFailed,
@@ -616,9 +1514,64 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterSetupCreate(
 	return nil
 }
 
+// setSetupCreationStatus reports the status of cluster setups which were created and the error
+// of those which failed, until all cluster setups are created
+func (r *ClusterTemplateInstanceReconciler) setSetupCreationStatus(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setupErr *v1alpha1.SetupCreationError,
+) {
+	apps := map[string]argo.Application{}
+	applications, err := clusterTemplateInstance.GetDay2Applications(ctx, r.Client, ArgoCDNamespace)
+	if err == nil {
+		for _, app := range applications.Items {
+			apps[app.Labels[v1alpha1.CTISetupLabel]] = app
+		}
+	}
+	clusterSetupStatus := []v1alpha1.ClusterSetupStatus{}
+	for _, setup := range clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterSetup {
+		if createErr, ok := setupErr.Errors[setup.Name]; ok {
+			clusterSetupStatus = append(clusterSetupStatus, v1alpha1.ClusterSetupStatus{
+				Name:                 setup.Name,
+				Status:               argocd.ApplicationCreateFailed,
+				Message:              createErr.Error(),
+				ApplicationName:      clusterTemplateInstance.GetDay2ApplicationName(setup.Name),
+				ApplicationNamespace: ArgoCDNamespace,
+			})
+			continue
+		}
+		if app, ok := apps[setup.Name]; ok {
+			clusterSetupStatus = append(clusterSetupStatus, getClusterSetupStatus(setup.Name, &app))
+		}
+	}
+	clusterTemplateInstance.Status.ClusterSetup = &clusterSetupStatus
+}
+
+// getClusterSetupStatus returns status of the cluster setup reported by its application
+func getClusterSetupStatus(setupName string, app *argo.Application) v1alpha1.ClusterSetupStatus {
+	status, msg := argocd.GetApplicationHealth(app)
+	setupStatus := v1alpha1.ClusterSetupStatus{
+		Name:                 setupName,
+		Status:               status,
+		Message:              msg,
+		ApplicationName:      app.Name,
+		ApplicationNamespace: app.Namespace,
+	}
+	if ArgoCDURL != "" {
+		setupStatus.ApplicationURL = fmt.Sprintf(
+			"%s/applications/%s/%s",
+			ArgoCDURL,
+			app.Namespace,
+			app.Name,
+		)
+	}
+	return setupStatus
+}
+
 func (r *ClusterTemplateInstanceReconciler) reconcileClusterSetup(
 	ctx context.Context,
 	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	jobClients *setupJobClients,
 ) error {
 
 	clusterSetupCreatedCondition := meta.FindStatusCondition(
@@ -635,7 +1588,8 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterSetup(
 		string(v1alpha1.ClusterSetupSucceeded),
 	)
 
-	if clusterSetupSucceededCondition.Status == metav1.ConditionTrue {
+	if clusterSetupSucceededCondition.Status == metav1.ConditionTrue ||
+		clusterSetupSucceededCondition.Reason == string(v1alpha1.ClusterSetupTimedOut) {
 		return nil
 	}
 
@@ -648,7 +1602,7 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterSetup(
 		return nil
 	}
 
-	CTIlog.Info(
+	CTIlog.V(1).Info(
 		"reconcile cluster setup for clustertemplateinstance",
 		"name",
 		clusterTemplateInstance.Name,
@@ -664,7 +1618,28 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterSetup(
 		return err
 	}
 
-	if len(applications.Items) == 0 {
+	jobStatuses, err := r.getSetupJobStatuses(ctx, clusterTemplateInstance, jobClients)
+	if err != nil {
+		clusterTemplateInstance.SetClusterSetupSucceededCondition(
+			metav1.ConditionFalse,
+			v1alpha1.ClusterSetupFetchFailed,
+			fmt.Sprintf("Failed to get setup jobs - %q", err),
+		)
+		return err
+	}
+
+	ansibleJobStatuses, err := r.getSetupAnsibleJobStatuses(ctx, clusterTemplateInstance)
+	if err != nil {
+		clusterTemplateInstance.SetClusterSetupSucceededCondition(
+			metav1.ConditionFalse,
+			v1alpha1.ClusterSetupFetchFailed,
+			fmt.Sprintf("Failed to get setup Ansible jobs - %q", err),
+		)
+		return err
+	}
+	jobStatuses = append(jobStatuses, ansibleJobStatuses...)
+
+	if len(applications.Items) == 0 && len(jobStatuses) == 0 {
 		clusterTemplateInstance.SetClusterSetupSucceededCondition(
 			metav1.ConditionFalse,
 			v1alpha1.ClusterSetupAppsNotFound,
@@ -677,23 +1652,30 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterSetup(
 	clusterTemplateInstance.Status.Message = "Cluster setup is running"
 	clusterSetupStatus := []v1alpha1.ClusterSetupStatus{}
 	allSynced := true
+	unfinished := false
 	errorSetups := []string{}
 	degradedSetups := []string{}
 	for _, app := range applications.Items {
-		setupName := app.Labels[v1alpha1.CTISetupLabel]
-		status, msg := argocd.GetApplicationHealth(&app)
-
-		clusterSetupStatus = append(clusterSetupStatus, v1alpha1.ClusterSetupStatus{
-			Name:    setupName,
-			Status:  status,
-			Message: msg,
-		})
+		clusterSetupStatus = append(
+			clusterSetupStatus,
+			getClusterSetupStatus(app.Labels[v1alpha1.CTISetupLabel], &app),
+		)
+	}
+	clusterSetupStatus = append(clusterSetupStatus, jobStatuses...)
+	clusterSetupStatus = addWaitingSetupStatuses(clusterTemplateInstance, clusterSetupStatus)
+	for _, setupStatus := range clusterSetupStatus {
+		setupName := setupStatus.Name
+		status := setupStatus.Status
 
 		if status != argocd.ApplicationHealthy {
 			allSynced = false
 		}
 
-		if status == argocd.ApplicationError {
+		if status == argocd.ApplicationSyncRunning || status == argocd.ApplicationWaiting {
+			unfinished = true
+		}
+
+		if status == argocd.ApplicationError || status == argocd.ApplicationSyncFailed {
 			errorSetups = append(errorSetups, setupName)
 		}
 
@@ -704,6 +1686,19 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterSetup(
 
 	clusterTemplateInstance.Status.ClusterSetup = &clusterSetupStatus
 
+	if unfinished && setupTimedOut(clusterTemplateInstance) {
+		return r.timeOutClusterSetup(ctx, clusterTemplateInstance)
+	}
+
+	if err := r.createNextSetupStages(ctx, clusterTemplateInstance, jobClients); err != nil {
+		clusterTemplateInstance.SetClusterSetupSucceededCondition(
+			metav1.ConditionFalse,
+			v1alpha1.ClusterSetupNotCreated,
+			fmt.Sprintf("Failed to create next stage of cluster setup - %q", err),
+		)
+		return err
+	}
+
 	if allSynced {
 		clusterTemplateInstance.SetClusterSetupSucceededCondition(
 			metav1.ConditionTrue,
@@ -743,26 +1738,40 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterSetup(
 
 func StartCTIController(
 	mgr ctrl.Manager,
+	helmClient *helm.HelmClient,
 	enableHypershift bool,
 	enableHive bool,
 ) context.CancelFunc {
+	kubeClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		CTIlog.Error(err, "unable to create kubernetes client for cti-controller")
+		os.Exit(1)
+	}
 	ctiReconciller := &ClusterTemplateInstanceReconciler{
 		Client:           mgr.GetClient(),
 		Scheme:           mgr.GetScheme(),
 		EnableHypershift: enableHypershift,
 		EnableHive:       enableHive,
+		Recorder:         mgr.GetEventRecorderFor("cti-controller"),
+		HelmClient:       helmClient,
+		KubeClient:       kubeClient,
 	}
-	ctiController, err := controller.NewUnmanaged("cti-controller", mgr, controller.Options{
-		Reconciler: ctiReconciller,
-	})
-
+	newController := func() (controller.Controller, error) {
+		ctiController, err := controller.NewUnmanaged("cti-controller", mgr, controller.Options{
+			Reconciler: ctiReconciller,
+		})
+		if err != nil {
+			return nil, err
+		}
+		ctiReconciller.SetupWatches(ctiController)
+		return ctiController, nil
+	}
+	ctiController, err := newController()
 	if err != nil {
 		CTIlog.Error(err, "unable to create cti-controller")
 		os.Exit(1)
 	}
 
-	ctiReconciller.SetupWatches(ctiController)
-
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Start our controller in a goroutine so that we do not block.
@@ -772,10 +1781,30 @@ func StartCTIController(
 		// to handle that.
 		<-mgr.Elected()
 
-		// Start our controller. This will block until the context is
-		// closed, or the controller returns an error.
-		if err := ctiController.Start(ctx); err != nil {
-			CTIlog.Error(err, "cannot run cti-controller")
+		// Start our controller. This will block until the context is closed, or the
+		// controller returns an error - ie caches of the watched resources did not sync
+		// because their CRD was removed or is not served yet. A controller can not be started
+		// twice, a new one is created for the retry.
+		for {
+			err := ctiController.Start(ctx)
+			if err == nil || ctx.Err() != nil {
+				return
+			}
+			CTIlog.Error(
+				err,
+				"cannot run cti-controller, retrying",
+				"after",
+				ctiControllerRetryInterval,
+			)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(ctiControllerRetryInterval):
+			}
+			if ctiController, err = newController(); err != nil {
+				CTIlog.Error(err, "unable to create cti-controller")
+				return
+			}
 		}
 	}()
 
@@ -806,6 +1835,9 @@ func (r *ClusterTemplateInstanceReconciler) SetupWatches(ctrl controller.Control
 
 	mapResourceToInstance := func(resourceGVK schema.GroupVersionResource) func(res client.Object) []reconcile.Request {
 		return func(res client.Object) []reconcile.Request {
+			if reply := r.mapTrackedResourceToInstance(res); len(reply) > 0 {
+				return reply
+			}
 			reply := []reconcile.Request{}
 			apps := &argo.ApplicationList{}
 
@@ -873,11 +1905,18 @@ func (r *ClusterTemplateInstanceReconciler) SetupWatches(ctrl controller.Control
 		&source.Kind{Type: &argo.Application{}},
 		handler.EnqueueRequestsFromMapFunc(mapApplicationToInstance),
 	)
+	ctrl.Watch(
+		&source.Kind{Type: &v1alpha1.ClusterTemplate{}},
+		handler.EnqueueRequestsFromMapFunc(r.mapTemplateToInstances),
+		predicate.GenerationChangedPredicate{},
+	)
 
 	if r.EnableHive {
 		ctrl.Watch(
 			&source.Kind{Type: &hivev1.ClusterClaim{}},
-			handler.EnqueueRequestsFromMapFunc(mapResourceToInstance(v1alpha1.ClusterClaimGVK)),
+			handler.EnqueueRequestsFromMapFunc(mapClusterClaimToInstance(
+				mapResourceToInstance(v1alpha1.ClusterClaimGVK),
+			)),
 		)
 
 		ctrl.Watch(
@@ -895,6 +1934,11 @@ func (r *ClusterTemplateInstanceReconciler) SetupWatches(ctrl controller.Control
 		ctrl.Watch(
 			&source.Kind{Type: &hypershiftv1alpha1.NodePool{}},
 			handler.EnqueueRequestsFromMapFunc(mapResourceToInstance(v1alpha1.NodePoolGVK)))
+		ctrl.Watch(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapHostedClusterSecretToInstance(
+				mapResourceToInstance(v1alpha1.HostedClusterGVK),
+			)))
 	}
 }
 
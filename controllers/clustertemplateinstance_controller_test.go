@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -9,6 +11,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
 	"github.com/stolostron/cluster-templates-operator/testutils"
 
 	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
@@ -50,7 +53,11 @@ var _ = Describe("ClusterTemplateInstance controller", func() {
 					return 0
 				}
 				return len(cti.Status.Conditions)
-			}, timeout, interval).Should(Equal(5))
+			}, timeout, interval).Should(Equal(7))
+			Expect(
+				meta.IsStatusConditionFalse(cti.Status.Conditions, string(v1alpha1.Ready)),
+			).Should(BeTrue())
+			Expect(cti.Status.ObservedGeneration).Should(Equal(cti.Generation))
 		})
 	})
 
@@ -76,6 +83,38 @@ var _ = Describe("ClusterTemplateInstance controller", func() {
 		})
 	})
 
+	Context("Failure injection", func() {
+		cti := &v1alpha1.ClusterTemplateInstance{}
+		ct := &v1alpha1.ClusterTemplate{}
+
+		BeforeEach(func() {
+			InjectInstallFailure = []string{testutils.GetCT(false).Name}
+			ct = testutils.GetCT(false)
+			Expect(k8sClient.Create(ctx, ct)).Should(Succeed())
+			cti = testutils.GetCTI()
+			Expect(k8sClient.Create(ctx, cti)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			InjectInstallFailure = nil
+			testutils.DeleteResource(ctx, cti, k8sClient)
+			testutils.DeleteResource(ctx, ct, k8sClient)
+		})
+
+		It("Fails cluster definition creation", func() {
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(cti), cti)
+				if err != nil {
+					return false
+				}
+				return cti.Status.Phase == v1alpha1.ClusterDefinitionFailedPhase
+			}, timeout, interval).Should(BeTrue())
+
+			_, err := cti.GetDay1Application(ctx, k8sClient, "argocd")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
 	Context("Cluster definition phase", func() {
 		cti := &v1alpha1.ClusterTemplateInstance{}
 
@@ -219,6 +258,44 @@ var _ = Describe("ClusterTemplateInstance controller", func() {
 			}, timeout, interval).Should(BeTrue())
 		})
 
+		It("Detects drifted resources", func() {
+			app.Status.Health = argo.HealthStatus{
+				Status: health.HealthStatusHealthy,
+			}
+			app.Status.History = argo.RevisionHistories{
+				{
+					ID:         0,
+					Revision:   "0.1.0",
+					DeployedAt: metav1.Now(),
+				},
+			}
+			app.Status.Resources = []argo.ResourceStatus{
+				{
+					Kind:      "NodePool",
+					Namespace: "clusters",
+					Name:      "foo",
+					Status:    argo.SyncStatusCodeOutOfSync,
+				},
+			}
+
+			Expect(k8sClient.Update(ctx, app)).Should(Succeed())
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(cti), cti)
+				if err != nil {
+					return false
+				}
+				driftCondition := meta.FindStatusCondition(
+					cti.Status.Conditions,
+					string(v1alpha1.ClusterDefinitionDrifted),
+				)
+				if driftCondition == nil {
+					return false
+				}
+				return driftCondition.Status == metav1.ConditionTrue &&
+					driftCondition.Reason == string(v1alpha1.DriftDetected)
+			}, timeout, interval).Should(BeTrue())
+		})
+
 		It("Handles unknown provider", func() {
 			app.Status.Health = argo.HealthStatus{
 				Status: health.HealthStatusHealthy,
@@ -503,6 +580,125 @@ var _ = Describe("ClusterTemplateInstance controller", func() {
 		})
 	})
 
+	Context("Install options", func() {
+		cti := &v1alpha1.ClusterTemplateInstance{}
+		ct := &v1alpha1.ClusterTemplate{}
+		app := &argo.Application{}
+
+		createInstance := func(installOptions *v1alpha1.InstallOptions) {
+			ct = testutils.GetCT(false)
+			ct.Spec.InstallOptions = installOptions
+			Expect(k8sClient.Create(ctx, ct)).Should(Succeed())
+
+			clusterti := testutils.GetCTI()
+			Expect(k8sClient.Create(ctx, clusterti)).Should(Succeed())
+
+			Eventually(func() bool {
+				k8sClient.Get(ctx, client.ObjectKeyFromObject(clusterti), cti)
+				return cti.Status.ClusterTemplateSpec != nil
+			}, timeout, interval).Should(BeTrue())
+			var err error
+			app, err = cti.GetDay1Application(ctx, k8sClient, "argocd")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(app).ShouldNot(BeNil())
+		}
+
+		AfterEach(func() {
+			testutils.DeleteResource(ctx, cti, k8sClient)
+			testutils.DeleteResource(ctx, ct, k8sClient)
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(app), app)).Should(Succeed())
+			app.Finalizers = []string{}
+			Expect(k8sClient.Update(ctx, app)).Should(Succeed())
+			testutils.EnsureResourceDoesNotExist(ctx, app, k8sClient)
+		})
+
+		It("Fails installation after timeout", func() {
+			createInstance(&v1alpha1.InstallOptions{
+				Timeout: &metav1.Duration{Duration: time.Second},
+			})
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(cti), cti)
+				if err != nil {
+					return false
+				}
+				clusterCondition := meta.FindStatusCondition(
+					cti.Status.Conditions,
+					string(v1alpha1.ClusterInstallSucceeded),
+				)
+				return clusterCondition.Reason == string(v1alpha1.ClusterInstallTimedOut) &&
+					cti.Status.Phase == v1alpha1.ClusterInstallFailedPhase
+			}, timeout, interval).Should(BeTrue())
+		})
+
+		It("Deletes application of failed atomic installation", func() {
+			createInstance(&v1alpha1.InstallOptions{
+				Atomic: true,
+			})
+			app.Status.Health = argo.HealthStatus{
+				Status: health.HealthStatusDegraded,
+			}
+			Expect(k8sClient.Update(ctx, app)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(cti), cti)
+				if err != nil {
+					return false
+				}
+				clusterCondition := meta.FindStatusCondition(
+					cti.Status.Conditions,
+					string(v1alpha1.ClusterInstallSucceeded),
+				)
+				return clusterCondition.Reason == string(v1alpha1.ClusterInstallRolledBack) &&
+					cti.Status.Phase == v1alpha1.ClusterInstallFailedPhase
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(app), app)).Should(Succeed())
+			Expect(app.DeletionTimestamp).ShouldNot(BeNil())
+		})
+
+		It("Retries rolled back installation", func() {
+			createInstance(&v1alpha1.InstallOptions{
+				Atomic:  true,
+				Retries: 1,
+			})
+			app.Status.OperationState = &argo.OperationState{
+				Phase:   synccommon.OperationFailed,
+				Message: "one or more objects failed to apply",
+			}
+			Expect(k8sClient.Update(ctx, app)).Should(Succeed())
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(app), app)
+				return err == nil && app.DeletionTimestamp != nil
+			}, timeout, interval).Should(BeTrue())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cti), cti)).Should(Succeed())
+			clusterCondition := meta.FindStatusCondition(
+				cti.Status.Conditions,
+				string(v1alpha1.ClusterInstallSucceeded),
+			)
+			Expect(clusterCondition.Message).Should(ContainSubstring("one or more objects failed to apply"))
+
+			// ArgoCD removes the finalizer once the resources are deleted
+			app.Finalizers = []string{}
+			Expect(k8sClient.Update(ctx, app)).Should(Succeed())
+			testutils.EnsureResourceDoesNotExist(ctx, app, k8sClient)
+
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(cti), cti)
+				if err != nil {
+					return false
+				}
+				retried, err := cti.GetDay1Application(ctx, k8sClient, "argocd")
+				if err != nil {
+					return false
+				}
+				app = retried
+				return cti.Status.InstallRetries == 1
+			}, timeout, interval).Should(BeTrue())
+		})
+	})
+
 	Context("Cluster setup create phase", func() {
 		ct := &v1alpha1.ClusterTemplate{}
 		cti := &v1alpha1.ClusterTemplateInstance{}
@@ -552,7 +748,8 @@ var _ = Describe("ClusterTemplateInstance controller", func() {
 			reconciler := &ClusterTemplateInstanceReconciler{
 				Client: client,
 			}
-			err = reconciler.reconcileClusterSetupCreate(ctx, cti)
+			jobClients := reconciler.newSetupJobClients(cti)
+			err = reconciler.reconcileClusterSetupCreate(ctx, cti, jobClients)
 			Expect(err).Should(BeNil())
 			apps, err := cti.GetDay2Applications(ctx, client, "argocd")
 			Expect(err).Should(BeNil())
@@ -617,7 +814,8 @@ var _ = Describe("ClusterTemplateInstance controller", func() {
 			reconciler := &ClusterTemplateInstanceReconciler{
 				Client: client,
 			}
-			err = reconciler.reconcileClusterSetup(ctx, cti)
+			jobClients := reconciler.newSetupJobClients(cti)
+			err = reconciler.reconcileClusterSetup(ctx, cti, jobClients)
 			Expect(err).Should(BeNil())
 			clusterSetupSucceededCondition := meta.FindStatusCondition(
 				cti.Status.Conditions,
@@ -628,6 +826,89 @@ var _ = Describe("ClusterTemplateInstance controller", func() {
 				clusterSetupSucceededCondition.Reason,
 			).Should(Equal(string(v1alpha1.ClusterSetupRunning)))
 		})
+
+		It("Reports day2 apps which failed to be created", func() {
+			setupCTI := &v1alpha1.ClusterTemplateInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "default",
+				},
+				Status: v1alpha1.ClusterTemplateInstanceStatus{
+					ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+						ClusterSetup: []v1alpha1.ClusterSetup{{Name: "bar"}, {Name: "baz"}},
+					},
+				},
+			}
+			SetDefaultConditions(setupCTI)
+			setupCTI.SetArgoClusterAddedCondition(
+				metav1.ConditionTrue,
+				v1alpha1.ArgoClusterCreated,
+				"Cluster added to argo successfully",
+			)
+
+			kubeconfig := api.Config{
+				Clusters: []api.NamedCluster{{Name: "foo", Cluster: api.Cluster{Server: "foo-server"}}},
+			}
+			data, err := yaml.Marshal(&kubeconfig)
+			Expect(err).ShouldNot(HaveOccurred())
+			kubeconfigSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      setupCTI.GetKubeconfigRef(),
+					Namespace: setupCTI.Namespace,
+				},
+				Data: map[string][]byte{"kubeconfig": data},
+			}
+			conflictingApp := &argo.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      setupCTI.GetDay2ApplicationName("bar"),
+					Namespace: "argocd",
+				},
+			}
+
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, kubeconfigSecret, conflictingApp)
+			reconciler := &ClusterTemplateInstanceReconciler{Client: client}
+			jobClients := reconciler.newSetupJobClients(setupCTI)
+			Expect(reconciler.reconcileClusterSetupCreate(ctx, setupCTI, jobClients)).
+				ShouldNot(Succeed())
+
+			condition := meta.FindStatusCondition(
+				setupCTI.Status.Conditions,
+				string(v1alpha1.ClusterSetupCreated),
+			)
+			Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).Should(Equal(string(v1alpha1.ClusterSetupCreationFailed)))
+
+			Expect(setupCTI.Status.ClusterSetup).ShouldNot(BeNil())
+			setupStatus := *setupCTI.Status.ClusterSetup
+			Expect(setupStatus).Should(HaveLen(2))
+			Expect(setupStatus[0].Name).Should(Equal("bar"))
+			Expect(setupStatus[0].Status).Should(Equal(argocd.ApplicationCreateFailed))
+			Expect(setupStatus[0].Message).Should(ContainSubstring("already exists"))
+			Expect(setupStatus[0].ApplicationName).Should(Equal("default-foo-bar"))
+			Expect(setupStatus[1].Name).Should(Equal("baz"))
+			Expect(setupStatus[1].Status).ShouldNot(Equal(argocd.ApplicationCreateFailed))
+			Expect(setupStatus[1].ApplicationName).Should(Equal("default-foo-baz"))
+			Expect(setupStatus[1].ApplicationNamespace).Should(Equal("argocd"))
+		})
+
+		It("Links day2 app in ArgoCD UI", func() {
+			app := &argo.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default-foo-bar",
+					Namespace: "argocd",
+				},
+			}
+			setupStatus := getClusterSetupStatus("bar", app)
+			Expect(setupStatus.ApplicationName).Should(Equal("default-foo-bar"))
+			Expect(setupStatus.ApplicationURL).Should(BeEmpty())
+
+			ArgoCDURL = "https://argocd.example.com"
+			defer func() { ArgoCDURL = "" }()
+			setupStatus = getClusterSetupStatus("bar", app)
+			Expect(setupStatus.ApplicationURL).Should(Equal(
+				"https://argocd.example.com/applications/argocd/default-foo-bar",
+			))
+		})
 	})
 
 	Context("Credentials phase", func() {
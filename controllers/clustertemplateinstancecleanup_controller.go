@@ -0,0 +1,159 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+const (
+	defaultCleanupBatchSize = 5
+	// how often the deletion of the instances is checked
+	cleanupCheckInterval = 10 * time.Second
+)
+
+var CTICleanupLog = logf.Log.WithName("cti-cleanup-controller")
+
+// ClusterTemplateInstanceCleanupReconciler reconciles a ClusterTemplateInstanceCleanup object
+type ClusterTemplateInstanceCleanupReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateinstancecleanups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateinstancecleanups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateinstances,verbs=get;list;watch;delete
+
+func (r *ClusterTemplateInstanceCleanupReconciler) Reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+) (ctrl.Result, error) {
+	cleanup := &v1alpha1.ClusterTemplateInstanceCleanup{}
+	if err := r.Get(ctx, req.NamespacedName, cleanup); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if cleanup.Status.CompletionTime != nil {
+		return ctrl.Result{}, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&cleanup.Spec.Selector)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// empty selector would match all the instances of the hub, the webhook rejects it
+	if selector.Empty() {
+		CTICleanupLog.Info("Ignoring cleanup with empty selector", "name", cleanup.Name)
+		return ctrl.Result{}, nil
+	}
+
+	instances := &v1alpha1.ClusterTemplateInstanceList{}
+	if err := r.List(ctx, instances, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// the age is compared to the start of the cleanup, instances which become old enough while
+	// the cleanup runs are not deleted
+	started := cleanup.Status.StartTime != nil
+	if !started {
+		now := metav1.Now()
+		cleanup.Status.StartTime = &now
+	}
+	cutoff := cleanup.Status.StartTime.Add(-cleanup.Spec.OlderThan.Duration)
+	matched := []v1alpha1.InstanceReference{}
+	pending := []v1alpha1.ClusterTemplateInstance{}
+	deleting := 0
+	for _, instance := range instances.Items {
+		if !instance.CreationTimestamp.Time.Before(cutoff) {
+			continue
+		}
+		matched = append(matched, v1alpha1.InstanceReference{
+			Name:      instance.Name,
+			Namespace: instance.Namespace,
+		})
+		if instance.DeletionTimestamp.IsZero() {
+			pending = append(pending, instance)
+		} else {
+			deleting++
+		}
+	}
+	if !started {
+		cleanup.Status.MatchedInstances = matched
+	}
+
+	if cleanup.Spec.DryRun {
+		now := metav1.Now()
+		cleanup.Status.CompletionTime = &now
+		return ctrl.Result{}, r.Status().Update(ctx, cleanup)
+	}
+
+	// at most batchSize instances are being deleted at once, next instance is deleted once one
+	// of them is gone, so the hub and ArgoCD are not overloaded by many cluster uninstallations
+	batchSize := cleanup.Spec.BatchSize
+	if batchSize < 1 {
+		batchSize = defaultCleanupBatchSize
+	}
+	for i := 0; i < len(pending) && deleting < batchSize; i++ {
+		CTICleanupLog.Info(
+			"Deleting cluster template instance",
+			"name", pending[i].Name,
+			"namespace", pending[i].Namespace,
+		)
+		if err := r.Delete(ctx, &pending[i]); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+		cleanup.Status.DeletedInstances++
+		deleting++
+	}
+
+	if deleting == 0 {
+		now := metav1.Now()
+		cleanup.Status.CompletionTime = &now
+		return ctrl.Result{}, r.Status().Update(ctx, cleanup)
+	}
+
+	if err := r.Status().Update(ctx, cleanup); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: cleanupCheckInterval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterTemplateInstanceCleanupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(
+			&v1alpha1.ClusterTemplateInstanceCleanup{},
+			// status updates must not trigger next check before cleanupCheckInterval
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Complete(r)
+}
@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/testutils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func getCleanupCTI(name string, labels map[string]string) *v1alpha1.ClusterTemplateInstance {
+	return &v1alpha1.ClusterTemplateInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    labels,
+		},
+		Spec: v1alpha1.ClusterTemplateInstanceSpec{
+			ClusterTemplateRef: "foo",
+		},
+	}
+}
+
+func getCleanup(dryRun bool, olderThan time.Duration) *v1alpha1.ClusterTemplateInstanceCleanup {
+	return &v1alpha1.ClusterTemplateInstanceCleanup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cleanup",
+		},
+		Spec: v1alpha1.ClusterTemplateInstanceCleanupSpec{
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"env": "ci",
+				},
+			},
+			OlderThan: metav1.Duration{Duration: olderThan},
+			DryRun:    dryRun,
+			BatchSize: 1,
+		},
+	}
+}
+
+var _ = Describe("ClusterTemplateInstanceCleanup controller", func() {
+	var matchingCTI *v1alpha1.ClusterTemplateInstance
+	var otherCTI *v1alpha1.ClusterTemplateInstance
+	var cleanup *v1alpha1.ClusterTemplateInstanceCleanup
+
+	BeforeEach(func() {
+		matchingCTI = getCleanupCTI("matching", map[string]string{"env": "ci"})
+		Expect(k8sClient.Create(ctx, matchingCTI)).Should(Succeed())
+		otherCTI = getCleanupCTI("other", map[string]string{"env": "prod"})
+		Expect(k8sClient.Create(ctx, otherCTI)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		testutils.DeleteResource(ctx, cleanup, k8sClient)
+		testutils.DeleteResource(ctx, otherCTI, k8sClient)
+		err := k8sClient.Delete(ctx, matchingCTI)
+		Expect(client.IgnoreNotFound(err)).ShouldNot(HaveOccurred())
+		testutils.EnsureResourceDoesNotExist(ctx, matchingCTI, k8sClient)
+	})
+
+	It("Reports matching instances in dry run", func() {
+		cleanup = getCleanup(true, 0)
+		Expect(k8sClient.Create(ctx, cleanup)).Should(Succeed())
+
+		Eventually(func() bool {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(cleanup), cleanup)
+			return err == nil && cleanup.Status.CompletionTime != nil
+		}, timeout, interval).Should(BeTrue())
+
+		Expect(cleanup.Status.MatchedInstances).Should(Equal([]v1alpha1.InstanceReference{
+			{
+				Name:      matchingCTI.Name,
+				Namespace: matchingCTI.Namespace,
+			},
+		}))
+		Expect(cleanup.Status.DeletedInstances).Should(Equal(0))
+		Expect(
+			k8sClient.Get(ctx, client.ObjectKeyFromObject(matchingCTI), matchingCTI),
+		).Should(Succeed())
+	})
+
+	It("Skips instances which are not old enough", func() {
+		cleanup = getCleanup(false, time.Hour)
+		Expect(k8sClient.Create(ctx, cleanup)).Should(Succeed())
+
+		Eventually(func() bool {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(cleanup), cleanup)
+			return err == nil && cleanup.Status.CompletionTime != nil
+		}, timeout, interval).Should(BeTrue())
+
+		Expect(cleanup.Status.MatchedInstances).Should(BeEmpty())
+		Expect(
+			k8sClient.Get(ctx, client.ObjectKeyFromObject(matchingCTI), matchingCTI),
+		).Should(Succeed())
+	})
+
+	It("Deletes matching instances", func() {
+		cleanup = getCleanup(false, 0)
+		Expect(k8sClient.Create(ctx, cleanup)).Should(Succeed())
+
+		Eventually(func() bool {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(matchingCTI), matchingCTI)
+			return apierrors.IsNotFound(err)
+		}, timeout, interval).Should(BeTrue())
+
+		Eventually(func() int {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(cleanup), cleanup)
+			if err != nil {
+				return 0
+			}
+			return cleanup.Status.DeletedInstances
+		}, timeout, interval).Should(Equal(1))
+		Expect(
+			k8sClient.Get(ctx, client.ObjectKeyFromObject(otherCTI), otherCTI),
+		).Should(Succeed())
+	})
+})
+
+var _ = Describe("ClusterTemplateInstanceCleanup deletion queue", func() {
+	It("Deletes at most batchSize instances at once", func() {
+		created := metav1.NewTime(time.Now().Add(-time.Hour))
+		instances := []client.Object{}
+		for _, name := range []string{"first", "second", "third"} {
+			cti := getCleanupCTI(name, map[string]string{"env": "ci"})
+			cti.CreationTimestamp = created
+			// the instance controller removes the finalizer once the cluster is uninstalled
+			cti.Finalizers = []string{v1alpha1.CTIFinalizer}
+			instances = append(instances, cti)
+		}
+		cleanup := getCleanup(false, time.Minute)
+		cleanup.Spec.BatchSize = 2
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(append(instances, cleanup)...).
+			Build()
+		reconciler := &ClusterTemplateInstanceCleanupReconciler{Client: k8sClient}
+		req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cleanup)}
+		getDeleting := func() []string {
+			deleting := []string{}
+			for _, instance := range instances {
+				cti := &v1alpha1.ClusterTemplateInstance{}
+				err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(instance), cti)
+				if err == nil && !cti.DeletionTimestamp.IsZero() {
+					deleting = append(deleting, cti.Name)
+				}
+			}
+			return deleting
+		}
+
+		res, err := reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.RequeueAfter).Should(Equal(cleanupCheckInterval))
+		Expect(getDeleting()).Should(HaveLen(2))
+
+		// nothing is deleted until one of the instances is gone
+		_, err = reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(getDeleting()).Should(HaveLen(2))
+
+		cti := &v1alpha1.ClusterTemplateInstance{}
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKey{
+			Name:      getDeleting()[0],
+			Namespace: "default",
+		}, cti)).Should(Succeed())
+		cti.Finalizers = nil
+		Expect(k8sClient.Update(context.TODO(), cti)).Should(Succeed())
+		_, err = reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(getDeleting()).Should(HaveLen(2))
+
+		Expect(k8sClient.Get(context.TODO(), req.NamespacedName, cleanup)).Should(Succeed())
+		Expect(cleanup.Status.DeletedInstances).Should(Equal(3))
+		Expect(cleanup.Status.MatchedInstances).Should(HaveLen(3))
+		Expect(cleanup.Status.CompletionTime).Should(BeNil())
+	})
+
+	It("Compares the age of instances to the start of the cleanup", func() {
+		cti := getCleanupCTI("young", map[string]string{"env": "ci"})
+		cti.CreationTimestamp = metav1.NewTime(time.Now().Add(-3 * time.Hour))
+		cleanup := getCleanup(false, 2*time.Hour)
+		// the instance got old enough only after the cleanup started
+		cleanup.Status.StartTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(cti, cleanup).
+			Build()
+		reconciler := &ClusterTemplateInstanceCleanupReconciler{Client: k8sClient}
+		req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cleanup)}
+
+		_, err := reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cti), cti)).Should(Succeed())
+		Expect(k8sClient.Get(context.TODO(), req.NamespacedName, cleanup)).Should(Succeed())
+		Expect(cleanup.Status.CompletionTime).ShouldNot(BeNil())
+	})
+
+	It("Ignores cleanup with empty selector", func() {
+		cti := getCleanupCTI("instance", nil)
+		cleanup := getCleanup(false, 0)
+		cleanup.Spec.Selector = metav1.LabelSelector{}
+		k8sClient := fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithObjects(cti, cleanup).
+			Build()
+		reconciler := &ClusterTemplateInstanceCleanupReconciler{Client: k8sClient}
+		req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cleanup)}
+
+		_, err := reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cti), cti)).Should(Succeed())
+	})
+})
@@ -0,0 +1,384 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/yaml"
+
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// keys of the support bundle ConfigMap
+const (
+	supportBundleInstanceKey     = "instance.yaml"
+	supportBundleApplicationsKey = "applications.yaml"
+	supportBundleResourcesKey    = "resources.yaml"
+	supportBundleEventsKey       = "events.log"
+	supportBundleTestsKey        = "tests.log"
+)
+
+const (
+	// ConfigMaps are limited to 1MiB, every part of the bundle is truncated to fit
+	supportBundleMaxPartBytes = 190 * 1024
+	// most recent events of the instance kept in the bundle
+	supportBundleMaxEvents = 100
+)
+
+var CTISupportBundleLog = logf.Log.WithName("cti-support-bundle-controller")
+
+// ClusterTemplateInstanceSupportBundleReconciler reconciles a ClusterTemplateInstanceSupportBundle
+// object
+type ClusterTemplateInstanceSupportBundleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateinstancesupportbundles,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateinstancesupportbundles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=list
+
+func (r *ClusterTemplateInstanceSupportBundleReconciler) Reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+) (ctrl.Result, error) {
+	bundle := &v1alpha1.ClusterTemplateInstanceSupportBundle{}
+	if err := r.Get(ctx, req.NamespacedName, bundle); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if bundle.Status.CompletionTime != nil {
+		return ctrl.Result{}, nil
+	}
+
+	cti := &v1alpha1.ClusterTemplateInstance{}
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{Name: bundle.Spec.ClusterTemplateInstanceRef, Namespace: bundle.Namespace},
+		cti,
+	); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		bundle.Status.Message = fmt.Sprintf(
+			"ClusterTemplateInstance %s not found",
+			bundle.Spec.ClusterTemplateInstanceRef,
+		)
+		return ctrl.Result{}, r.Status().Update(ctx, bundle)
+	}
+
+	data, err := r.gatherSupportBundle(ctx, cti)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	truncated := []string{}
+	for key, value := range data {
+		if len(value) > supportBundleMaxPartBytes {
+			data[key] = strings.ToValidUTF8(value[:supportBundleMaxPartBytes], "") +
+				"\n... truncated\n"
+			truncated = append(truncated, key)
+		}
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bundle.Name + "-support-bundle",
+			Namespace: bundle.Namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		configMap.Data = data
+		return controllerutil.SetControllerReference(bundle, configMap, r.Scheme)
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	CTISupportBundleLog.Info(
+		"Support bundle gathered",
+		"instance", cti.Namespace+"/"+cti.Name,
+		"configMap", configMap.Name,
+	)
+
+	bundle.Status.ConfigMap = &corev1.LocalObjectReference{Name: configMap.Name}
+	bundle.Status.Message = ""
+	if len(truncated) > 0 {
+		sort.Strings(truncated)
+		bundle.Status.Message = "Truncated to fit the ConfigMap: " + strings.Join(truncated, ", ")
+	}
+	now := metav1.Now()
+	bundle.Status.CompletionTime = &now
+	return ctrl.Result{}, r.Status().Update(ctx, bundle)
+}
+
+// gatherSupportBundle returns the support bundle of the instance keyed by the ConfigMap keys
+func (r *ClusterTemplateInstanceSupportBundleReconciler) gatherSupportBundle(
+	ctx context.Context,
+	cti *v1alpha1.ClusterTemplateInstance,
+) (map[string]string, error) {
+	instance := cti.DeepCopy()
+	instance.APIVersion = v1alpha1.GroupVersion.String()
+	instance.Kind = "ClusterTemplateInstance"
+	instance.ManagedFields = nil
+	instanceYAML, err := yaml.Marshal(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	apps, err := r.getInstanceApplications(ctx, cti)
+	if err != nil {
+		return nil, err
+	}
+	appDocs := []string{}
+	for i := range apps {
+		appYAML, err := yaml.Marshal(redactApplication(apps[i]))
+		if err != nil {
+			return nil, err
+		}
+		appDocs = append(appDocs, string(appYAML))
+	}
+
+	resources := ""
+	if len(apps) > 0 {
+		// the Day1 application is listed first, resources of the Day2 applications live on the
+		// new cluster
+		if resources, err = r.getClusterDefinitionResources(ctx, cti, apps[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	events, err := r.getInstanceEvents(ctx, cti)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		supportBundleInstanceKey:     string(instanceYAML),
+		supportBundleApplicationsKey: strings.Join(appDocs, "---\n"),
+		supportBundleResourcesKey:    resources,
+		supportBundleEventsKey:       events,
+		supportBundleTestsKey:        getFailedChartTestLogs(cti),
+	}, nil
+}
+
+// getInstanceApplications returns the Day1 application of the instance followed by the Day2
+// applications, no applications are returned before the Day1 application is created
+func (r *ClusterTemplateInstanceSupportBundleReconciler) getInstanceApplications(
+	ctx context.Context,
+	cti *v1alpha1.ClusterTemplateInstance,
+) ([]argo.Application, error) {
+	if cti.Status.ClusterTemplateSpec == nil {
+		return nil, nil
+	}
+	day1App, err := cti.GetDay1Application(ctx, r.Client, ArgoCDNamespace)
+	if err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	day2Apps, err := cti.GetDay2Applications(ctx, r.Client, ArgoCDNamespace)
+	if err != nil {
+		return nil, err
+	}
+	return append([]argo.Application{*day1App}, day2Apps.Items...), nil
+}
+
+// redactApplication leaves out the Helm values of the application, they may contain values of
+// Secrets. The values set by the instance are part of the instance. Besides the spec, the source
+// is recorded in the status (compared source, sync history and operation) - all copies are
+// redacted.
+func redactApplication(app argo.Application) *argo.Application {
+	redacted := app.DeepCopy()
+	redacted.APIVersion = argo.ApplicationSchemaGroupVersionKind.GroupVersion().String()
+	redacted.Kind = argo.ApplicationSchemaGroupVersionKind.Kind
+	redacted.ManagedFields = nil
+	redactSource(&redacted.Spec.Source)
+	if op := redacted.Operation; op != nil && op.Sync != nil {
+		redactSource(op.Sync.Source)
+	}
+	redactSource(&redacted.Status.Sync.ComparedTo.Source)
+	for i := range redacted.Status.History {
+		redactSource(&redacted.Status.History[i].Source)
+	}
+	if opState := redacted.Status.OperationState; opState != nil {
+		if opState.Operation.Sync != nil {
+			redactSource(opState.Operation.Sync.Source)
+		}
+		if opState.SyncResult != nil {
+			redactSource(&opState.SyncResult.Source)
+		}
+	}
+	return redacted
+}
+
+// redactSource leaves out the Helm values of the source, including the merged values passed to
+// the post renderer plugin
+func redactSource(source *argo.ApplicationSource) {
+	if source == nil {
+		return
+	}
+	if helm := source.Helm; helm != nil {
+		helm.Values = ""
+		helm.Parameters = nil
+		helm.FileParameters = nil
+	}
+	if plugin := source.Plugin; plugin != nil {
+		for _, env := range plugin.Env {
+			if env.Name == v1alpha1.PostRendererValuesEnv {
+				env.Value = ""
+			}
+		}
+	}
+}
+
+// getClusterDefinitionResources returns the live manifests of the resources created by the cluster
+// definition, including the status reported by the cluster provider. Secrets are left out.
+func (r *ClusterTemplateInstanceSupportBundleReconciler) getClusterDefinitionResources(
+	ctx context.Context,
+	cti *v1alpha1.ClusterTemplateInstance,
+	app argo.Application,
+) (string, error) {
+	if cti.Status.ClusterTemplateSpec.HostingCluster != nil {
+		return "# resources of the cluster definition live on the hosting cluster " +
+			cti.Status.ClusterTemplateSpec.HostingCluster.KubeconfigSecret + "\n", nil
+	}
+	docs := []string{}
+	for _, res := range app.Status.Resources {
+		if res.Group == "" && res.Kind == "Secret" {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{
+			Group:   res.Group,
+			Version: res.Version,
+			Kind:    res.Kind,
+		})
+		if err := r.Get(
+			ctx,
+			client.ObjectKey{Name: res.Name, Namespace: res.Namespace},
+			obj,
+		); err != nil {
+			docs = append(
+				docs,
+				fmt.Sprintf("# %s %s/%s: %s\n", res.Kind, res.Namespace, res.Name, err),
+			)
+			continue
+		}
+		obj.SetManagedFields(nil)
+		annotations := obj.GetAnnotations()
+		delete(annotations, corev1.LastAppliedConfigAnnotation)
+		obj.SetAnnotations(annotations)
+		objYAML, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return "", err
+		}
+		docs = append(docs, string(objYAML))
+	}
+	return strings.Join(docs, "---\n"), nil
+}
+
+// getInstanceEvents returns the most recent events of the instance, one per line
+func (r *ClusterTemplateInstanceSupportBundleReconciler) getInstanceEvents(
+	ctx context.Context,
+	cti *v1alpha1.ClusterTemplateInstance,
+) (string, error) {
+	eventList := &corev1.EventList{}
+	if err := r.List(ctx, eventList, client.InNamespace(cti.Namespace)); err != nil {
+		return "", err
+	}
+	events := []corev1.Event{}
+	for _, event := range eventList.Items {
+		if event.InvolvedObject.Kind == "ClusterTemplateInstance" &&
+			event.InvolvedObject.Name == cti.Name {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return getEventTime(events[i]).Before(getEventTime(events[j]))
+	})
+	if len(events) > supportBundleMaxEvents {
+		events = events[len(events)-supportBundleMaxEvents:]
+	}
+	lines := []string{}
+	for _, event := range events {
+		lines = append(lines, fmt.Sprintf(
+			"%s %s %s (x%d) %s",
+			getEventTime(event).UTC().Format(time.RFC3339),
+			event.Type,
+			event.Reason,
+			event.Count,
+			event.Message,
+		))
+	}
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// getEventTime returns when the event was last seen
+func getEventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// getFailedChartTestLogs returns messages and log tails of the failed chart tests of the instance
+func getFailedChartTestLogs(cti *v1alpha1.ClusterTemplateInstance) string {
+	if cti.Status.ChartTests == nil {
+		return ""
+	}
+	logs := ""
+	for _, test := range cti.Status.ChartTests.Tests {
+		if test.Phase != v1alpha1.TestsFailed {
+			continue
+		}
+		logs += fmt.Sprintf("=== %s: %s\n%s\n", test.Name, test.Message, test.Log)
+	}
+	return logs
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterTemplateInstanceSupportBundleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(
+			&v1alpha1.ClusterTemplateInstanceSupportBundle{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Complete(r)
+}
@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClusterTemplateInstanceSupportBundle controller", func() {
+	newBundle := func() *v1alpha1.ClusterTemplateInstanceSupportBundle {
+		return &v1alpha1.ClusterTemplateInstanceSupportBundle{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bundle",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.ClusterTemplateInstanceSupportBundleSpec{
+				ClusterTemplateInstanceRef: "foo",
+			},
+		}
+	}
+
+	reconcileBundle := func(
+		k8sClient client.Client,
+		bundle *v1alpha1.ClusterTemplateInstanceSupportBundle,
+	) {
+		reconciler := &ClusterTemplateInstanceSupportBundleReconciler{
+			Client: k8sClient,
+			Scheme: scheme.Scheme,
+		}
+		_, err := reconciler.Reconcile(
+			context.TODO(),
+			ctrl.Request{NamespacedName: client.ObjectKeyFromObject(bundle)},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(bundle), bundle)).
+			Should(Succeed())
+	}
+
+	It("Gathers data of the instance", func() {
+		cti := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{},
+				ChartTests: &v1alpha1.ChartTestsStatus{
+					Tests: []v1alpha1.ChartTestStatus{
+						{Name: "passed", Phase: v1alpha1.TestsSucceeded, Log: "all good"},
+						{
+							Name:    "connectivity",
+							Phase:   v1alpha1.TestsFailed,
+							Message: "Error",
+							Log:     "api server unreachable",
+						},
+					},
+				},
+			},
+		}
+		app := &argo.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetDay1ApplicationName(),
+				Namespace: ArgoCDNamespace,
+				Labels: map[string]string{
+					v1alpha1.CTINameLabel:      cti.Name,
+					v1alpha1.CTINamespaceLabel: cti.Namespace,
+				},
+			},
+			Spec: argo.ApplicationSpec{
+				Source: argo.ApplicationSource{
+					Helm: &argo.ApplicationSourceHelm{
+						Parameters: []argo.HelmParameter{{Name: "pullSecret", Value: "secret"}},
+					},
+				},
+			},
+			Status: argo.ApplicationStatus{
+				Resources: []argo.ResourceStatus{
+					{Version: "v1", Kind: "ConfigMap", Name: "cluster-config", Namespace: "clusters"},
+					{Version: "v1", Kind: "Secret", Name: "pull-secret", Namespace: "clusters"},
+				},
+			},
+		}
+		clusterConfig := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-config",
+				Namespace: "clusters",
+			},
+			Data: map[string]string{"region": "us-east-1"},
+		}
+		event := &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo.1",
+				Namespace: "default",
+			},
+			InvolvedObject: corev1.ObjectReference{Kind: "ClusterTemplateInstance", Name: "foo"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "ClusterInstallFailed",
+			Message:        "provision stopped",
+			Count:          2,
+			LastTimestamp:  metav1.NewTime(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)),
+		}
+		otherEvent := event.DeepCopy()
+		otherEvent.Name = "bar.1"
+		otherEvent.InvolvedObject.Name = "bar"
+		bundle := newBundle()
+		k8sClient := fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			cti,
+			app,
+			clusterConfig,
+			event,
+			otherEvent,
+			bundle,
+		)
+
+		reconcileBundle(k8sClient, bundle)
+		Expect(bundle.Status.CompletionTime).ShouldNot(BeNil())
+		Expect(bundle.Status.Message).Should(BeEmpty())
+		Expect(bundle.Status.ConfigMap.Name).Should(Equal("bundle-support-bundle"))
+
+		configMap := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: "bundle-support-bundle", Namespace: "default"},
+			configMap,
+		)).Should(Succeed())
+		Expect(configMap.OwnerReferences).Should(HaveLen(1))
+		Expect(configMap.Data[supportBundleInstanceKey]).Should(
+			ContainSubstring("kind: ClusterTemplateInstance"),
+		)
+		Expect(configMap.Data[supportBundleApplicationsKey]).Should(
+			ContainSubstring("name: " + app.Name),
+		)
+		Expect(configMap.Data[supportBundleApplicationsKey]).
+			ShouldNot(ContainSubstring("pullSecret"))
+		Expect(configMap.Data[supportBundleResourcesKey]).Should(ContainSubstring("us-east-1"))
+		Expect(configMap.Data[supportBundleResourcesKey]).ShouldNot(ContainSubstring("pull-secret"))
+		Expect(configMap.Data[supportBundleEventsKey]).Should(Equal(
+			"2022-01-01T00:00:00Z Warning ClusterInstallFailed (x2) provision stopped\n",
+		))
+		Expect(configMap.Data[supportBundleTestsKey]).Should(Equal(
+			"=== connectivity: Error\napi server unreachable\n",
+		))
+	})
+
+	It("Redacts values passed to the post renderer", func() {
+		postRendererSource := argo.ApplicationSource{
+			RepoURL: "https://github.com/foo/kustomize",
+			Path:    "overlays/cluster",
+			Plugin: &argo.ApplicationSourcePlugin{
+				Name: v1alpha1.PostRendererPluginName,
+				Env: argo.Env{
+					{Name: v1alpha1.PostRendererChartEnv, Value: "hypershift-template"},
+					{Name: v1alpha1.PostRendererValuesEnv, Value: "pullSecret: top-secret\n"},
+				},
+			},
+		}
+		cti := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{},
+			},
+		}
+		app := &argo.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetDay1ApplicationName(),
+				Namespace: ArgoCDNamespace,
+				Labels: map[string]string{
+					v1alpha1.CTINameLabel:      cti.Name,
+					v1alpha1.CTINamespaceLabel: cti.Namespace,
+				},
+			},
+			Spec: argo.ApplicationSpec{
+				Source: postRendererSource,
+			},
+			Status: argo.ApplicationStatus{
+				Sync: argo.SyncStatus{
+					ComparedTo: argo.ComparedTo{Source: *postRendererSource.DeepCopy()},
+				},
+				History: argo.RevisionHistories{
+					{ID: 1, Source: *postRendererSource.DeepCopy()},
+				},
+				OperationState: &argo.OperationState{
+					SyncResult: &argo.SyncOperationResult{
+						Source: *postRendererSource.DeepCopy(),
+					},
+				},
+			},
+		}
+		bundle := newBundle()
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti, app, bundle)
+
+		reconcileBundle(k8sClient, bundle)
+		Expect(bundle.Status.Message).Should(BeEmpty())
+
+		configMap := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: "bundle-support-bundle", Namespace: "default"},
+			configMap,
+		)).Should(Succeed())
+		Expect(configMap.Data[supportBundleApplicationsKey]).
+			Should(ContainSubstring("hypershift-template"))
+		Expect(configMap.Data[supportBundleApplicationsKey]).
+			ShouldNot(ContainSubstring("top-secret"))
+	})
+
+	It("Reports missing instance", func() {
+		bundle := newBundle()
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, bundle)
+
+		reconcileBundle(k8sClient, bundle)
+		Expect(bundle.Status.CompletionTime).Should(BeNil())
+		Expect(bundle.Status.Message).Should(Equal("ClusterTemplateInstance foo not found"))
+	})
+})
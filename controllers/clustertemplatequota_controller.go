@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -40,6 +41,8 @@ type ClusterTemplateQuotaReconciler struct {
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplatequotas/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateinstances,verbs=get;list;watch
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustersizeclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;delete
 
 func (r *ClusterTemplateQuotaReconciler) Reconcile(
 	ctx context.Context,
@@ -47,6 +50,19 @@ func (r *ClusterTemplateQuotaReconciler) Reconcile(
 ) (ctrl.Result, error) {
 	clusterTemplateQuota := &v1alpha1.ClusterTemplateQuota{}
 	if err := r.Get(ctx, req.NamespacedName, clusterTemplateQuota); err != nil {
+		if apierrors.IsNotFound(err) {
+			// drop the deleted quota from the remaining quota of the namespace
+			clusterTemplateList := &v1alpha1.ClusterTemplateList{}
+			if err := r.List(ctx, clusterTemplateList); err != nil {
+				return ctrl.Result{}, err
+			}
+			err := r.reconcileRemainingQuota(
+				ctx,
+				req.Namespace,
+				clusterTemplateList.Items,
+			)
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{}, err
 	}
 
@@ -63,22 +79,48 @@ func (r *ClusterTemplateQuotaReconciler) Reconcile(
 		return ctrl.Result{}, err
 	}
 
+	sizeClassList := &v1alpha1.ClusterSizeClassList{}
+	if err := r.List(ctx, sizeClassList); err != nil {
+		return ctrl.Result{}, err
+	}
+	sizeClasses := map[string]*v1alpha1.ClusterSizeClass{}
+	for i := range sizeClassList.Items {
+		sizeClasses[sizeClassList.Items[i].Name] = &sizeClassList.Items[i]
+	}
+
 	currentInstances := []v1alpha1.AllowedTemplate{}
 	currentConst := 0
+	currentNodes := 0
+	currentVCPU := 0
 	for _, template := range clusterTemplateQuota.Spec.AllowedTemplates {
 		count := 0
 
-		templateCost := 0
+		var templateSpec *v1alpha1.ClusterTemplateSpec
 		for _, cTemplate := range clusterTemplateList.Items {
 			if cTemplate.Name == template.Name {
-				templateCost = cTemplate.Spec.Cost
+				templateSpec = cTemplate.Spec.DeepCopy()
 			}
 		}
 
 		for _, instance := range clusterTemplateInstanceList.Items {
 			if instance.Spec.ClusterTemplateRef == template.Name {
 				count++
-				currentConst += templateCost
+				if templateSpec == nil {
+					continue
+				}
+				// cost of the template is weighted by the size class of the instance
+				currentConst += templateSpec.Cost * sizeClasses[instance.Spec.SizeClass].GetWeight()
+				// prefer the template spec the instance was created from
+				instanceTemplateSpec := templateSpec
+				if instance.Status.ClusterTemplateSpec != nil {
+					instanceTemplateSpec = instance.Status.ClusterTemplateSpec
+				}
+				nodes, vcpu, err := instance.GetRequestedCompute(*instanceTemplateSpec)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+				currentNodes += nodes
+				currentVCPU += vcpu
 			}
 		}
 
@@ -90,6 +132,8 @@ func (r *ClusterTemplateQuotaReconciler) Reconcile(
 
 	clusterTemplateQuota.Status = v1alpha1.ClusterTemplateQuotaStatus{
 		BudgetSpent:       currentConst,
+		NodesSpent:        currentNodes,
+		VCPUSpent:         currentVCPU,
 		TemplateInstances: currentInstances,
 	}
 
@@ -97,6 +141,14 @@ func (r *ClusterTemplateQuotaReconciler) Reconcile(
 		return ctrl.Result{}, err
 	}
 
+	if err := r.reconcileRemainingQuota(
+		ctx,
+		req.Namespace,
+		clusterTemplateList.Items,
+	); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -3,14 +3,25 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/stolostron/cluster-templates-operator/helm"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/yaml"
 
 	v1 "k8s.io/api/core/v1"
 )
@@ -19,17 +30,82 @@ const (
 	argoCDNsConfig = "argocd-ns"
 	enableUIConfig = "enable-ui"
 	uiImageConfig  = "ui-image"
+	// URL of ArgoCD UI, used for links to the applications
+	argoCDURLConfig = "argocd-url"
+	// name of the ConfigMap (in the config namespace) with CA bundle trusted for helm repositories
+	helmCABundleConfig = "helm-ca-bundle-cm"
+	// proxy for helm repositories, overrides HTTP_PROXY, HTTPS_PROXY and NO_PROXY of the operator
+	helmHTTPProxyConfig  = "helm-http-proxy"
+	helmHTTPSProxyConfig = "helm-https-proxy"
+	helmNoProxyConfig    = "helm-no-proxy"
+	// URL ArgoCD reaches the charts embedded in ConfigMaps and Secrets served by the operator at
+	embeddedChartsURLConfig = "embedded-charts-url"
+	// comma separated URLs of helm repositories registered in ArgoCD if missing, empty disables it
+	defaultHelmReposConfig = "default-helm-repositories"
+	// number of revisions kept in history of the applications, unless set by the template
+	revisionHistoryLimitConfig = "revision-history-limit"
+	// namespace of ClusterTemplateInstanceViews of all instances, empty disables them
+	instanceViewsNsConfig = "instance-views-ns"
+	// credentials of clusters can be read only through ClusterCredentialRequest-s, which are logged
+	auditCredentialAccessConfig = "audit-credential-access"
+	// failure injection for testing of error handling on non-production hubs
+	injectInstallFailureConfig    = "inject-install-failure"
+	injectClusterReadyDelayConfig = "inject-cluster-ready-delay"
+	// operational settings of the operator Deployment, left as set by the manifests if empty
+	operatorPriorityClassConfig = "operator-priority-class-name"
+	operatorResourcesConfig     = "operator-resources"
+	// minAvailable (number or percentage) of the operator PodDisruptionBudget, empty disables it
+	operatorPDBMinAvailableConfig = "operator-pdb-min-available"
+	// serves pprof endpoints at /debug/pprof/ of the metrics server
+	enableProfilingConfig = "enable-profiling"
+	// names of Secrets (in the config namespace) with the pull secret and public SSH key injected
+	// into instances of templates which require them
+	defaultPullSecretConfig = "default-pull-secret"
+	defaultSSHKeyConfig     = "default-ssh-key"
+
+	configName      = "claas-config"
+	configNamespace = "cluster-aas-operator"
 
 	defaultArgoCDNs = "argocd"
 	defaultEnableUI = "false"
 	defaultUIImage  = "quay.io/stolostron/cluster-templates-console-plugin:latest"
+	// repository of the default templates
+	defaultHelmRepos = "https://stolostron.github.io/cluster-templates-operator"
+	// service of the helm repo bridge
+	defaultEmbeddedChartsURL = "https://cluster-aas-operator-repo-bridge-service.cluster-aas-operator.svc:8001/charts"
 )
 
 var (
+	Configlog          = logf.Log.WithName("config-controller")
 	ArgoCDNamespace    = defaultArgoCDNs
+	ArgoCDURL          = ""
 	EnableUI           = defaultEnableUI
 	UIImage            = defaultUIImage
 	EnableUIconfigSync = make(chan event.GenericEvent)
+	HelmCABundleCM     = ""
+	HelmCABundle       []byte
+	EmbeddedChartsURL  = defaultEmbeddedChartsURL
+	// number of revisions kept in history of new applications, ArgoCD default is used if nil
+	RevisionHistoryLimit *int64
+	// namespace of ClusterTemplateInstanceViews, views are not maintained if empty
+	InstanceViewsNamespace = ""
+	// users get cluster credentials through ClusterCredentialRequest-s instead of the secrets
+	AuditCredentialAccess bool
+	// names of ClusterTemplates whose installation always fails
+	InjectInstallFailure []string
+	// how long clusters are reported as not ready after they are installed
+	InjectClusterReadyDelay time.Duration
+	// priority class and resources of the operator pods, not changed if empty
+	OperatorPriorityClassName string
+	OperatorResources         *v1.ResourceRequirements
+	// minAvailable of the operator PodDisruptionBudget, no budget is created if nil
+	OperatorPDBMinAvailable *intstr.IntOrString
+	OperatorConfigSync      = make(chan event.GenericEvent, 1)
+	// pprof endpoints are served by the metrics server
+	EnableProfiling bool
+	// hub default credentials, templates requiring them fail if not set
+	DefaultPullSecret   = ""
+	DefaultSSHKeySecret = ""
 )
 
 type ConfigReconciler struct {
@@ -47,9 +123,26 @@ func (r *ConfigReconciler) Reconcile(
 	if err := r.Get(ctx, req.NamespacedName, config); err != nil {
 		if apierrors.IsNotFound(err) {
 			ArgoCDNamespace = defaultArgoCDNs
+			ArgoCDURL = ""
 			EnableUI = defaultEnableUI
 			UIImage = defaultUIImage
+			HelmCABundleCM = ""
+			HelmCABundle = nil
+			EmbeddedChartsURL = defaultEmbeddedChartsURL
+			InjectInstallFailure = nil
+			InjectClusterReadyDelay = 0
+			RevisionHistoryLimit = nil
+			AuditCredentialAccess = false
+			InstanceViewsNamespace = ""
+			OperatorPriorityClassName = ""
+			OperatorResources = nil
+			OperatorPDBMinAvailable = nil
+			EnableProfiling = false
+			DefaultPullSecret = ""
+			DefaultSSHKeySecret = ""
+			helm.SetProxy("", "", "")
 			EnableUIconfigSync <- event.GenericEvent{Object: GetPluginDeployment()}
+			syncOperatorConfig()
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
@@ -58,6 +151,11 @@ func (r *ConfigReconciler) Reconcile(
 	if ok && val != "" {
 		ArgoCDNamespace = val
 	}
+	ArgoCDURL = strings.TrimSuffix(config.Data[argoCDURLConfig], "/")
+	EmbeddedChartsURL = defaultEmbeddedChartsURL
+	if val := config.Data[embeddedChartsURLConfig]; val != "" {
+		EmbeddedChartsURL = strings.TrimSuffix(val, "/")
+	}
 	enableUI, enableUIOk := config.Data[enableUIConfig]
 	uiImage, uiImageOk := config.Data[uiImageConfig]
 	if enableUIOk || uiImageOk {
@@ -69,13 +167,107 @@ func (r *ConfigReconciler) Reconcile(
 		}
 		EnableUIconfigSync <- event.GenericEvent{Object: GetPluginDeployment()}
 	}
+
+	if err := loadFailureInjection(config); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	AuditCredentialAccess = config.Data[auditCredentialAccessConfig] == "true"
+	InstanceViewsNamespace = config.Data[instanceViewsNsConfig]
+	DefaultPullSecret = config.Data[defaultPullSecretConfig]
+	DefaultSSHKeySecret = config.Data[defaultSSHKeyConfig]
+
+	RevisionHistoryLimit = nil
+	if val := config.Data[revisionHistoryLimitConfig]; val != "" {
+		limit, err := strconv.ParseInt(val, 10, 64)
+		if err != nil || limit < 0 {
+			return ctrl.Result{}, fmt.Errorf(
+				"invalid %s config - must be a non-negative number",
+				revisionHistoryLimitConfig,
+			)
+		}
+		RevisionHistoryLimit = &limit
+	}
+
+	if err := loadOperatorConfig(config); err != nil {
+		return ctrl.Result{}, err
+	}
+	syncOperatorConfig()
+
+	helm.SetProxy(
+		config.Data[helmHTTPProxyConfig],
+		config.Data[helmHTTPSProxyConfig],
+		config.Data[helmNoProxyConfig],
+	)
+
+	HelmCABundleCM = config.Data[helmCABundleConfig]
+	caBundle, err := helm.GetCABundle(ctx, r.Client, HelmCABundleCM, config.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	HelmCABundle = caBundle
 	return ctrl.Result{}, nil
 }
 
+func loadFailureInjection(config *v1.ConfigMap) error {
+	InjectInstallFailure = nil
+	for _, template := range strings.Split(config.Data[injectInstallFailureConfig], ",") {
+		if template = strings.TrimSpace(template); template != "" {
+			InjectInstallFailure = append(InjectInstallFailure, template)
+		}
+	}
+
+	InjectClusterReadyDelay = 0
+	if val := config.Data[injectClusterReadyDelayConfig]; val != "" {
+		delay, err := time.ParseDuration(val)
+		if err != nil {
+			return fmt.Errorf("invalid %s config - %w", injectClusterReadyDelayConfig, err)
+		}
+		InjectClusterReadyDelay = delay
+	}
+
+	if len(InjectInstallFailure) > 0 || InjectClusterReadyDelay > 0 {
+		Configlog.Info(
+			"Failure injection is enabled, do not use in production",
+			"installFailure", InjectInstallFailure,
+			"clusterReadyDelay", InjectClusterReadyDelay.String(),
+		)
+	}
+	return nil
+}
+
+func loadOperatorConfig(config *v1.ConfigMap) error {
+	OperatorPriorityClassName = config.Data[operatorPriorityClassConfig]
+	EnableProfiling = config.Data[enableProfilingConfig] == "true"
+
+	OperatorResources = nil
+	if val := config.Data[operatorResourcesConfig]; val != "" {
+		resources := &v1.ResourceRequirements{}
+		if err := yaml.UnmarshalStrict([]byte(val), resources); err != nil {
+			return fmt.Errorf("invalid %s config - %w", operatorResourcesConfig, err)
+		}
+		OperatorResources = resources
+	}
+
+	OperatorPDBMinAvailable = nil
+	if val := config.Data[operatorPDBMinAvailableConfig]; val != "" {
+		minAvailable := intstr.Parse(val)
+		if _, err := intstr.GetScaledValueFromIntOrPercent(&minAvailable, 100, true); err != nil {
+			return fmt.Errorf("invalid %s config - %w", operatorPDBMinAvailableConfig, err)
+		}
+		OperatorPDBMinAvailable = &minAvailable
+	}
+	return nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if err := ctrl.NewControllerManagedBy(mgr).
 		For(&v1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(selectCM))).
+		Watches(
+			&source.Kind{Type: &v1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(mapCABundleCM),
+		).
 		Complete(r); err != nil {
 		return fmt.Errorf("failed to construct controller: %w", err)
 	}
@@ -83,5 +275,20 @@ func (r *ConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 }
 
 func selectCM(obj client.Object) bool {
-	return obj.GetName() == "claas-config" && obj.GetNamespace() == "cluster-aas-operator"
+	return obj.GetName() == configName && obj.GetNamespace() == configNamespace
+}
+
+func mapCABundleCM(obj client.Object) []reconcile.Request {
+	if HelmCABundleCM == "" || obj.GetName() != HelmCABundleCM ||
+		obj.GetNamespace() != configNamespace {
+		return []reconcile.Request{}
+	}
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      configName,
+				Namespace: configNamespace,
+			},
+		},
+	}
 }
@@ -2,12 +2,14 @@ package controllers
 
 import (
 	"reflect"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	applicationset "github.com/argoproj/applicationset/pkg/utils"
+	"github.com/stolostron/cluster-templates-operator/helm"
 	testutils "github.com/stolostron/cluster-templates-operator/testutils"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
@@ -93,4 +95,123 @@ var _ = Describe("CLaaS Config", func() {
 		}, timeout, interval).Should(BeNil())
 		Expect(deployment.Spec.Template.Spec.Containers[0].Image == customImg)
 	})
+	It("Loads helm CA bundle", func() {
+		caCM := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "trusted-ca",
+				Namespace: "cluster-aas-operator",
+			},
+			Data: map[string]string{
+				helm.CABundleKey: "foo",
+			},
+		}
+		createResource(caCM)
+		cm := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "claas-config",
+				Namespace: "cluster-aas-operator",
+			},
+			Data: map[string]string{
+				helmCABundleConfig: "trusted-ca",
+			},
+		}
+		createResource(cm)
+
+		Eventually(func() string {
+			return string(HelmCABundle)
+		}, timeout, interval).Should(Equal("foo"))
+
+		caCM.Data[helm.CABundleKey] = "bar"
+		err := k8sClient.Update(ctx, caCM)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() string {
+			return string(HelmCABundle)
+		}, timeout, interval).Should(Equal("bar"))
+	})
+	It("Loads helm proxy", func() {
+		cm := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "claas-config",
+				Namespace: "cluster-aas-operator",
+			},
+			Data: map[string]string{
+				helmHTTPSProxyConfig: "http://proxy.example.com:3128",
+				helmNoProxyConfig:    ".cluster.local",
+			},
+		}
+		createResource(cm)
+
+		Eventually(func() bool {
+			proxy := helm.GetProxy()
+			return proxy != nil && proxy.HTTPSProxy == "http://proxy.example.com:3128"
+		}, timeout, interval).Should(BeTrue())
+		Expect(helm.GetProxy().NoProxy).Should(Equal(".cluster.local"))
+	})
+	It("Loads failure injection", func() {
+		cm := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "claas-config",
+				Namespace: "cluster-aas-operator",
+			},
+			Data: map[string]string{
+				injectInstallFailureConfig:    "foo, bar",
+				injectClusterReadyDelayConfig: "10m",
+			},
+		}
+		createResource(cm)
+
+		Eventually(func() []string {
+			return InjectInstallFailure
+		}, timeout, interval).Should(Equal([]string{"foo", "bar"}))
+		Expect(InjectClusterReadyDelay).Should(Equal(10 * time.Minute))
+	})
+	It("Loads revision history limit", func() {
+		cm := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "claas-config",
+				Namespace: "cluster-aas-operator",
+			},
+			Data: map[string]string{
+				revisionHistoryLimitConfig: "3",
+			},
+		}
+		createResource(cm)
+
+		Eventually(func() bool {
+			return RevisionHistoryLimit != nil && *RevisionHistoryLimit == 3
+		}, timeout, interval).Should(BeTrue())
+	})
+	It("Loads ArgoCD URL", func() {
+		cm := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "claas-config",
+				Namespace: "cluster-aas-operator",
+			},
+			Data: map[string]string{
+				argoCDURLConfig: "https://argocd.example.com/",
+			},
+		}
+		createResource(cm)
+
+		Eventually(func() string {
+			return ArgoCDURL
+		}, timeout, interval).Should(Equal("https://argocd.example.com"))
+	})
+	It("Loads embedded charts URL", func() {
+		cm := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "claas-config",
+				Namespace: "cluster-aas-operator",
+			},
+			Data: map[string]string{
+				embeddedChartsURLConfig: "https://charts.example.com/embedded/",
+			},
+		}
+		createResource(cm)
+
+		Eventually(func() string {
+			return EmbeddedChartsURL
+		}, timeout, interval).Should(Equal("https://charts.example.com/embedded"))
+	})
 })
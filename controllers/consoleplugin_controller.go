@@ -246,7 +246,7 @@ func (r *ConsolePluginReconciler) SetupWithManager(mgr ctrl.Manager) error {
 }
 
 // +kubebuilder:rbac:groups="",resources=configmaps;services,verbs=get;list;watch;create;update
-// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=apps,namespace=cluster-aas-operator,resources=deployments,verbs=get;list;watch;create;update
 // +kubebuilder:rbac:groups=console.openshift.io,resources=consoleplugins,verbs=get;list;watch;create;update
 
 func (r *ConsolePluginReconciler) Reconcile(
@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// installFailureInjected returns true if installation of instances of the template should fail
+func installFailureInjected(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) bool {
+	for _, template := range InjectInstallFailure {
+		if template == clusterTemplateInstance.Spec.ClusterTemplateRef {
+			return true
+		}
+	}
+	return false
+}
+
+// injectedReadyDelayRemaining returns how long the cluster should still be reported as not ready.
+// The delay is counted from creation of the cluster definition application
+func injectedReadyDelayRemaining(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) time.Duration {
+	if InjectClusterReadyDelay == 0 || isClusterInstalled(clusterTemplateInstance) {
+		return 0
+	}
+	appCreatedCondition := meta.FindStatusCondition(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.ClusterDefinitionCreated),
+	)
+	if appCreatedCondition == nil || appCreatedCondition.Status != metav1.ConditionTrue {
+		return 0
+	}
+	return InjectClusterReadyDelay - time.Since(appCreatedCondition.LastTransitionTime.Time)
+}
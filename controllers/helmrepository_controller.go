@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/stolostron/cluster-templates-operator/helm"
+)
+
+var HelmRepositorylog = logf.Log.WithName("helm-repository-controller")
+
+// HelmRepositoryReconciler registers the default Helm repositories in ArgoCD, so the default
+// templates work without any setup. Repositories are registered on startup and when the
+// config changes - repositories removed by the admin are not recreated until then.
+type HelmRepositoryReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+func (r *HelmRepositoryReconciler) Reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+) (ctrl.Result, error) {
+	// the config is read here rather than from ConfigReconciler, which may not have loaded
+	// it yet on startup
+	config := &v1.ConfigMap{}
+	if err := r.Get(ctx, req.NamespacedName, config); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+	argoCDNamespace := defaultArgoCDNs
+	if val := config.Data[argoCDNsConfig]; val != "" {
+		argoCDNamespace = val
+	}
+	repoURLs := getDefaultHelmRepositories(config)
+	if len(repoURLs) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	created, err := helm.EnsureRepositories(ctx, r.Client, argoCDNamespace, repoURLs)
+	if len(created) > 0 {
+		HelmRepositorylog.Info(
+			"Registered default helm repositories",
+			"namespace", argoCDNamespace,
+			"repositories", created,
+		)
+	}
+	return ctrl.Result{}, err
+}
+
+// getDefaultHelmRepositories returns URLs of the default helm repositories. The bootstrapping
+// is disabled if the config key is set to an empty value.
+func getDefaultHelmRepositories(config *v1.ConfigMap) []string {
+	val, ok := config.Data[defaultHelmReposConfig]
+	if !ok {
+		val = defaultHelmRepos
+	}
+	repoURLs := []string{}
+	for _, repoURL := range strings.Split(val, ",") {
+		if repoURL = strings.TrimSpace(repoURL); repoURL != "" {
+			repoURLs = append(repoURLs, repoURL)
+		}
+	}
+	return repoURLs
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HelmRepositoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// A channel is used to generate an initial sync event, as the config may not exist.
+	// Afterwards, the controller syncs on the config.
+	initialSync := make(chan event.GenericEvent)
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("helmrepository").
+		For(&v1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(selectCM))).
+		Watches(&source.Channel{Source: initialSync}, &handler.EnqueueRequestForObject{}).
+		Complete(r); err != nil {
+		return fmt.Errorf("failed to construct controller: %w", err)
+	}
+	go func() {
+		initialSync <- event.GenericEvent{Object: &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      configName,
+				Namespace: configNamespace,
+			},
+		}}
+	}()
+	return nil
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/helm"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Default helm repositories", func() {
+	configKey := client.ObjectKey{Name: configName, Namespace: configNamespace}
+
+	It("Registers default repository without config", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		reconciler := &HelmRepositoryReconciler{Client: k8sClient}
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: configKey})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		secret := &v1.Secret{}
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKey{
+			Name:      helm.GetBootstrapSecretName(defaultHelmRepos),
+			Namespace: defaultArgoCDNs,
+		}, secret)).Should(Succeed())
+		Expect(string(secret.Data["url"])).Should(Equal(defaultHelmRepos))
+	})
+
+	It("Registers configured repositories", func() {
+		config := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      configName,
+				Namespace: configNamespace,
+			},
+			Data: map[string]string{
+				argoCDNsConfig:         "gitops",
+				defaultHelmReposConfig: "https://foo.io/charts, https://bar.io/charts",
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, config)
+		reconciler := &HelmRepositoryReconciler{Client: k8sClient}
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: configKey})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		secrets := &v1.SecretList{}
+		Expect(k8sClient.List(context.TODO(), secrets, client.InNamespace("gitops"))).
+			Should(Succeed())
+		Expect(secrets.Items).Should(HaveLen(2))
+	})
+
+	It("Does not register repositories when disabled", func() {
+		config := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      configName,
+				Namespace: configNamespace,
+			},
+			Data: map[string]string{
+				defaultHelmReposConfig: "",
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, config)
+		reconciler := &HelmRepositoryReconciler{Client: k8sClient}
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: configKey})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		secrets := &v1.SecretList{}
+		Expect(k8sClient.List(context.TODO(), secrets)).Should(Succeed())
+		Expect(secrets.Items).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// Agents and AgentClusterInstalls are not watched, discovery of hosts is checked periodically
+const agentCheckInterval = 30 * time.Second
+
+// agentInstallInProgress returns true while the Assisted Installer installs the ClusterDeployment
+// of the instance
+func agentInstallInProgress(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) bool {
+	platform := clusterTemplateInstance.Status.Platform
+	return platform != nil && platform.RequiredAgents > 0 &&
+		!isClusterInstalled(clusterTemplateInstance)
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+)
+
+// checkClusterNameAvailable fails if a HostedCluster of the cluster name of the instance already
+// exists in any namespace of the hosting cluster. HostedClusters derive the infra ID (names and
+// tags of cloud resources) from their name, a cluster of a re-created instance of the same name
+// would otherwise silently share them with the previous cluster. The HostedCluster of the instance
+// itself (the Day1 application was created, but the status update failed) is not a conflict.
+func (r *ClusterTemplateInstanceReconciler) checkClusterNameAvailable(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	clusterName := clusterTemplateInstance.Status.ClusterName
+	if clusterName == "" {
+		return nil
+	}
+	hostingClient, err := r.getHostingClient(ctx, clusterTemplateInstance)
+	if err != nil {
+		return err
+	}
+	hostedClusters := &hypershiftv1alpha1.HostedClusterList{}
+	if err := hostingClient.List(ctx, hostedClusters); err != nil {
+		// Hypershift is not installed, there are no HostedClusters to collide with
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list HostedClusters - %q", err)
+	}
+	for i := range hostedClusters.Items {
+		hostedCluster := &hostedClusters.Items[i]
+		if hostedCluster.Name != clusterName ||
+			argocd.GetTrackingApplication(hostedCluster) ==
+				clusterTemplateInstance.GetDay1ApplicationName() {
+			continue
+		}
+		return fmt.Errorf(
+			"HostedCluster %s already exists in namespace %s",
+			clusterName,
+			hostedCluster.Namespace,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Cluster name", func() {
+	var cti *v1alpha1.ClusterTemplateInstance
+
+	BeforeEach(func() {
+		cti = &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					ClusterName: &v1alpha1.ClusterName{ValuesKey: "clusterName"},
+				},
+				ClusterName: "foo",
+			},
+		}
+	})
+
+	newHostedCluster := func(namespace string, app string) *hypershiftv1alpha1.HostedCluster {
+		return &hypershiftv1alpha1.HostedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: namespace,
+				Labels:    map[string]string{argocd.TrackingLabel: app},
+			},
+		}
+	}
+
+	It("Rejects cluster name of existing HostedCluster", func() {
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				newHostedCluster("clusters", "other-app"),
+			),
+		}
+		Expect(reconciler.checkClusterNameAvailable(context.TODO(), cti)).Should(MatchError(
+			"HostedCluster foo already exists in namespace clusters",
+		))
+
+		cti.Status.ClusterName = "foo-x7k2p"
+		Expect(reconciler.checkClusterNameAvailable(context.TODO(), cti)).Should(Succeed())
+	})
+
+	It("Accepts HostedCluster of the instance", func() {
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				newHostedCluster("default", cti.GetDay1ApplicationName()),
+			),
+		}
+		Expect(reconciler.checkClusterNameAvailable(context.TODO(), cti)).Should(Succeed())
+	})
+})
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"context"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
+)
+
+// createClusterClaim claims a pre-provisioned cluster from the ClusterPool of the template
+// instead of installing the cluster definition. The claim lives in the namespace of the pool, so
+// it is labeled by the instance instead of being owned by it.
+func (r *ClusterTemplateInstanceReconciler) createClusterClaim(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	clusterPool := clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterPool
+	claim := &hivev1.ClusterClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterTemplateInstance.GetClusterClaimName(),
+			Namespace: clusterPool.Namespace,
+			Labels:    clusterTemplateInstance.GetInstanceLabels(""),
+		},
+		Spec: hivev1.ClusterClaimSpec{
+			ClusterPoolName: clusterPool.Name,
+		},
+	}
+	if err := r.Client.Create(ctx, claim); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteClusterClaim releases the claimed cluster, Hive deprovisions it and the pool replaces it
+// with a new cluster
+func (r *ClusterTemplateInstanceReconciler) deleteClusterClaim(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	clusterPool := clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterPool
+	if clusterPool == nil {
+		return nil
+	}
+	claim := &hivev1.ClusterClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterTemplateInstance.GetClusterClaimName(),
+			Namespace: clusterPool.Namespace,
+		},
+	}
+	return client.IgnoreNotFound(r.Client.Delete(ctx, claim))
+}
+
+// getClusterPoolProvider returns provider of the cluster claimed by the instance
+func getClusterPoolProvider(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) clusterprovider.ClusterProvider {
+	return clusterprovider.ClusterClaimProvider{
+		ClusterClaimName:      clusterTemplateInstance.GetClusterClaimName(),
+		ClusterClaimNamespace: clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterPool.Namespace,
+	}
+}
+
+// mapClusterClaimToInstance maps claims created for instances of pool templates by their labels,
+// other claims (ie rendered by the cluster definition) are mapped by mapClaim
+func mapClusterClaimToInstance(
+	mapClaim func(res client.Object) []reconcile.Request,
+) func(res client.Object) []reconcile.Request {
+	return func(res client.Object) []reconcile.Request {
+		name := res.GetLabels()[v1alpha1.CTINameLabel]
+		namespace := res.GetLabels()[v1alpha1.CTINamespaceLabel]
+		if name == "" || namespace == "" {
+			return mapClaim(res)
+		}
+		return []reconcile.Request{
+			{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}},
+		}
+	}
+}
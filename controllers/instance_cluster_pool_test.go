@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Instance cluster pool", func() {
+	newInstance := func() *v1alpha1.ClusterTemplateInstance {
+		return &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					ClusterPool: &v1alpha1.ClusterPoolRef{
+						Name:      "pool",
+						Namespace: "pools",
+					},
+				},
+			},
+		}
+	}
+
+	It("Claims cluster from the pool and releases it", func() {
+		cti := newInstance()
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+
+		Expect(reconciler.createClusterClaim(context.TODO(), cti)).Should(Succeed())
+		// already claimed
+		Expect(reconciler.createClusterClaim(context.TODO(), cti)).Should(Succeed())
+		claim := &hivev1.ClusterClaim{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: cti.GetClusterClaimName(), Namespace: "pools"},
+			claim,
+		)).Should(Succeed())
+		Expect(claim.Spec.ClusterPoolName).Should(Equal("pool"))
+		Expect(claim.Labels[v1alpha1.CTINameLabel]).Should(Equal(cti.Name))
+		Expect(claim.Labels[v1alpha1.CTINamespaceLabel]).Should(Equal(cti.Namespace))
+
+		Expect(getClusterPoolProvider(cti)).Should(Equal(clusterprovider.ClusterClaimProvider{
+			ClusterClaimName:      cti.GetClusterClaimName(),
+			ClusterClaimNamespace: "pools",
+		}))
+
+		Expect(reconciler.deleteClusterClaim(context.TODO(), cti)).Should(Succeed())
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: cti.GetClusterClaimName(), Namespace: "pools"},
+			claim,
+		)).ShouldNot(Succeed())
+		// already released
+		Expect(reconciler.deleteClusterClaim(context.TODO(), cti)).Should(Succeed())
+	})
+
+	It("Maps claims to instances by labels", func() {
+		mapped := false
+		fallback := func(res client.Object) []reconcile.Request {
+			mapped = true
+			return nil
+		}
+		claim := &hivev1.ClusterClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "default-foo",
+				Namespace: "pools",
+				Labels: map[string]string{
+					v1alpha1.CTINameLabel:      "foo",
+					v1alpha1.CTINamespaceLabel: "default",
+				},
+			},
+		}
+		Expect(mapClusterClaimToInstance(fallback)(claim)).Should(Equal([]reconcile.Request{
+			{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "default"}},
+		}))
+		Expect(mapped).Should(BeFalse())
+
+		claim.Labels = nil
+		Expect(mapClusterClaimToInstance(fallback)(claim)).Should(BeEmpty())
+		Expect(mapped).Should(BeTrue())
+	})
+})
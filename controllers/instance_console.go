@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
+)
+
+// reconcileConsoleURL reads the web console URL from the new cluster unless the cluster provider
+// reported it already. The URL is read once, clusters which do not run the OpenShift console are
+// asked again on next reconciles.
+func (r *ClusterTemplateInstanceReconciler) reconcileConsoleURL(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	kubeconfig []byte,
+) error {
+	if clusterTemplateInstance.Status.ConsoleURL != "" {
+		return nil
+	}
+	newClusterClient, err := getNewClusterClient(kubeconfig)
+	if err != nil {
+		return err
+	}
+	consoleURL, err := clusterprovider.GetClusterConsoleURL(ctx, newClusterClient)
+	if err != nil {
+		return err
+	}
+	clusterTemplateInstance.Status.ConsoleURL = consoleURL
+	return nil
+}
+
+// reconcileOpenShiftVersion reads the OpenShift version from the ClusterVersion of the new
+// cluster. Unlike the console URL, the version changes with upgrades of the cluster, so it is
+// read on every reconcile. Version reported by the cluster provider is kept for clusters which
+// are not OpenShift clusters.
+func (r *ClusterTemplateInstanceReconciler) reconcileOpenShiftVersion(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	kubeconfig []byte,
+) error {
+	newClusterClient, err := getNewClusterClient(kubeconfig)
+	if err != nil {
+		return err
+	}
+	version, err := clusterprovider.GetClusterOpenShiftVersion(ctx, newClusterClient)
+	if err != nil {
+		return err
+	}
+	if version != "" {
+		clusterTemplateInstance.Status.OpenShiftVersion = version
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clustersetup"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance console", func() {
+	consoleURL := "https://console-openshift-console.apps.foo.example.com"
+
+	AfterEach(func() {
+		getNewClusterClient = clustersetup.GetClientForCluster
+	})
+
+	It("Reads console URL from the new cluster", func() {
+		console := &unstructured.Unstructured{}
+		console.SetAPIVersion("config.openshift.io/v1")
+		console.SetKind("Console")
+		console.SetName("cluster")
+		Expect(unstructured.SetNestedField(
+			console.Object,
+			consoleURL,
+			"status",
+			"consoleURL",
+		)).Should(Succeed())
+		calls := 0
+		getNewClusterClient = func(_ []byte) (client.Client, error) {
+			calls++
+			return fake.NewFakeClientWithScheme(scheme.Scheme, console), nil
+		}
+
+		reconciler := &ClusterTemplateInstanceReconciler{}
+		cti := &v1alpha1.ClusterTemplateInstance{}
+		Expect(reconciler.reconcileConsoleURL(context.TODO(), cti, []byte{})).Should(Succeed())
+		Expect(cti.Status.ConsoleURL).Should(Equal(consoleURL))
+
+		// reported already
+		Expect(reconciler.reconcileConsoleURL(context.TODO(), cti, []byte{})).Should(Succeed())
+		Expect(calls).Should(Equal(1))
+	})
+
+	It("Reads OpenShift version from the new cluster", func() {
+		clusterVersion := &unstructured.Unstructured{}
+		clusterVersion.SetAPIVersion("config.openshift.io/v1")
+		clusterVersion.SetKind("ClusterVersion")
+		clusterVersion.SetName("version")
+		Expect(unstructured.SetNestedSlice(
+			clusterVersion.Object,
+			[]interface{}{
+				map[string]interface{}{
+					"state":   "Completed",
+					"version": "4.12.1",
+				},
+			},
+			"status",
+			"history",
+		)).Should(Succeed())
+		newClusterClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		getNewClusterClient = func(_ []byte) (client.Client, error) {
+			return newClusterClient, nil
+		}
+
+		reconciler := &ClusterTemplateInstanceReconciler{}
+		cti := &v1alpha1.ClusterTemplateInstance{}
+		cti.Status.OpenShiftVersion = "4.12.0"
+
+		// not an OpenShift cluster, the version reported by the provider is kept
+		Expect(reconciler.reconcileOpenShiftVersion(context.TODO(), cti, []byte{})).Should(Succeed())
+		Expect(cti.Status.OpenShiftVersion).Should(Equal("4.12.0"))
+
+		Expect(newClusterClient.Create(context.TODO(), clusterVersion)).Should(Succeed())
+		Expect(reconciler.reconcileOpenShiftVersion(context.TODO(), cti, []byte{})).Should(Succeed())
+		Expect(cti.Status.OpenShiftVersion).Should(Equal("4.12.1"))
+	})
+})
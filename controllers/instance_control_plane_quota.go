@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
+)
+
+// name of the ResourceQuota and LimitRange created in the hosted control plane namespace
+const controlPlaneQuotaName = "cluster-templates-control-plane"
+
+// ensureControlPlaneQuota creates the ResourceQuota and LimitRange of the size class of the
+// instance in the namespace hypershift runs the hosted control plane in. The namespace is created
+// as soon as the HostedCluster is known if hypershift did not create it yet, so the control plane
+// pods are admitted against the quota from the start. Hypershift adopts the namespace and deletes
+// it together with the HostedCluster.
+func (r *ClusterTemplateInstanceReconciler) ensureControlPlaneQuota(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	hostedCluster clusterprovider.HostedClusterProvider,
+) error {
+	if clusterTemplateInstance.Spec.SizeClass == "" {
+		return nil
+	}
+	sizeClass := &v1alpha1.ClusterSizeClass{}
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{Name: clusterTemplateInstance.Spec.SizeClass},
+		sizeClass,
+	); err != nil {
+		// the size class was deleted after the instance was admitted
+		return client.IgnoreNotFound(err)
+	}
+	quota := sizeClass.Spec.ControlPlaneQuota
+	if quota == nil {
+		return nil
+	}
+
+	hostingClient := hostedCluster.HostingClient
+	if hostingClient == nil {
+		hostingClient = r.Client
+	}
+	namespace := hostedCluster.GetControlPlaneNamespace()
+	labels := clusterTemplateInstance.GetInstanceLabels("")
+
+	ns := &corev1.Namespace{}
+	if err := hostingClient.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		ns = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   namespace,
+				Labels: labels,
+			},
+		}
+		CTIlog.Info(
+			"Creating control plane namespace",
+			"name",
+			clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+			"namespace",
+			namespace,
+		)
+		if err := hostingClient.Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	} else if ns.DeletionTimestamp != nil {
+		return nil
+	}
+
+	if len(quota.Hard) > 0 {
+		resourceQuota := &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      controlPlaneQuotaName,
+				Namespace: namespace,
+			},
+		}
+		if _, err := controllerutil.CreateOrUpdate(ctx, hostingClient, resourceQuota, func() error {
+			resourceQuota.Labels = labels
+			resourceQuota.Spec.Hard = quota.Hard
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	if len(quota.DefaultLimits) > 0 || len(quota.DefaultRequests) > 0 {
+		limitRange := &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      controlPlaneQuotaName,
+				Namespace: namespace,
+			},
+		}
+		if _, err := controllerutil.CreateOrUpdate(ctx, hostingClient, limitRange, func() error {
+			limitRange.Labels = labels
+			limitRange.Spec.Limits = []corev1.LimitRangeItem{
+				{
+					Type:           corev1.LimitTypeContainer,
+					Default:        quota.DefaultLimits,
+					DefaultRequest: quota.DefaultRequests,
+				},
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance control plane quota", func() {
+	hostedCluster := clusterprovider.HostedClusterProvider{
+		HostedClusterName:      "foo",
+		HostedClusterNamespace: "clusters",
+	}
+	controlPlaneNamespace := client.ObjectKey{Name: "clusters-foo"}
+
+	newInstance := func(sizeClass string) *v1alpha1.ClusterTemplateInstance {
+		return &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.ClusterTemplateInstanceSpec{
+				SizeClass: sizeClass,
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{},
+			},
+		}
+	}
+
+	It("Creates quota of the size class in the control plane namespace", func() {
+		sizeClass := &v1alpha1.ClusterSizeClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "small"},
+			Spec: v1alpha1.ClusterSizeClassSpec{
+				ControlPlaneQuota: &v1alpha1.ControlPlaneQuota{
+					Hard: corev1.ResourceList{
+						corev1.ResourceRequestsCPU: resource.MustParse("4"),
+					},
+					DefaultLimits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, sizeClass)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		cti := newInstance(sizeClass.Name)
+		Expect(reconciler.ensureControlPlaneQuota(context.TODO(), cti, hostedCluster)).
+			Should(Succeed())
+
+		ns := &corev1.Namespace{}
+		Expect(k8sClient.Get(context.TODO(), controlPlaneNamespace, ns)).Should(Succeed())
+		Expect(ns.Labels[v1alpha1.CTINameLabel]).Should(Equal(cti.Name))
+
+		key := client.ObjectKey{Name: controlPlaneQuotaName, Namespace: controlPlaneNamespace.Name}
+		quota := &corev1.ResourceQuota{}
+		Expect(k8sClient.Get(context.TODO(), key, quota)).Should(Succeed())
+		Expect(quota.Spec.Hard.Name(corev1.ResourceRequestsCPU, resource.DecimalSI).String()).
+			Should(Equal("4"))
+		limitRange := &corev1.LimitRange{}
+		Expect(k8sClient.Get(context.TODO(), key, limitRange)).Should(Succeed())
+		Expect(limitRange.Spec.Limits).Should(HaveLen(1))
+		Expect(limitRange.Spec.Limits[0].Default.Memory().String()).Should(Equal("1Gi"))
+
+		// changed limits of the size class are propagated
+		sizeClass.Spec.ControlPlaneQuota.Hard[corev1.ResourceRequestsCPU] = resource.MustParse("8")
+		Expect(k8sClient.Update(context.TODO(), sizeClass)).Should(Succeed())
+		Expect(reconciler.ensureControlPlaneQuota(context.TODO(), cti, hostedCluster)).
+			Should(Succeed())
+		Expect(k8sClient.Get(context.TODO(), key, quota)).Should(Succeed())
+		Expect(quota.Spec.Hard.Name(corev1.ResourceRequestsCPU, resource.DecimalSI).String()).
+			Should(Equal("8"))
+	})
+
+	It("Skips instances without control plane quota", func() {
+		sizeClass := &v1alpha1.ClusterSizeClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "small"},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, sizeClass)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		for _, cti := range []*v1alpha1.ClusterTemplateInstance{
+			newInstance(""),
+			newInstance(sizeClass.Name),
+			newInstance("deleted"),
+		} {
+			Expect(reconciler.ensureControlPlaneQuota(context.TODO(), cti, hostedCluster)).
+				Should(Succeed())
+			Expect(k8sClient.Get(context.TODO(), controlPlaneNamespace, &corev1.Namespace{})).
+				ShouldNot(Succeed())
+		}
+	})
+})
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// copyDefaultCredentials copies the hub default pull secret and SSH key required by the template
+// into the namespace the cluster definition is deployed to (on the hosting cluster, if set),
+// where the chart references them by the names injected into the cluster definition values.
+// Users of the namespace do not need access to the Secrets in the config namespace. The copies are
+// refreshed whenever the cluster definition is created and deleted together with the instance,
+// once the Applications of the instance are gone.
+func (r *ClusterTemplateInstanceReconciler) copyDefaultCredentials(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	credentials := clusterTemplateInstance.Status.ClusterTemplateSpec.DefaultCredentials
+	if credentials == nil {
+		return nil
+	}
+	hostingClient, err := r.getHostingClient(ctx, clusterTemplateInstance)
+	if err != nil {
+		return err
+	}
+	for _, cred := range []struct {
+		valuesKey string
+		config    string
+		source    string
+		target    string
+		key       string
+	}{
+		{
+			credentials.PullSecretValuesKey,
+			defaultPullSecretConfig,
+			DefaultPullSecret,
+			clusterTemplateInstance.GetDefaultPullSecretRef(),
+			v1alpha1.DefaultPullSecretKey,
+		},
+		{
+			credentials.SSHKeyValuesKey,
+			defaultSSHKeyConfig,
+			DefaultSSHKeySecret,
+			clusterTemplateInstance.GetDefaultSSHKeyRef(),
+			v1alpha1.DefaultSSHKeyKey,
+		},
+	} {
+		if cred.valuesKey == "" {
+			continue
+		}
+		if cred.source == "" {
+			return fmt.Errorf("template requires %s, but it is not set in the operator config", cred.config)
+		}
+		source := &corev1.Secret{}
+		if err := r.Get(
+			ctx,
+			client.ObjectKey{Name: cred.source, Namespace: configNamespace},
+			source,
+		); err != nil {
+			return fmt.Errorf("failed to get %s Secret %s - %q", cred.config, cred.source, err)
+		}
+		data, found := source.Data[cred.key]
+		if !found {
+			return fmt.Errorf("%s Secret %s has no key '%s'", cred.config, cred.source, cred.key)
+		}
+
+		target := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cred.target,
+				Namespace: clusterTemplateInstance.GetClusterDefinitionNamespace(),
+			},
+		}
+		if _, err := controllerutil.CreateOrUpdate(ctx, hostingClient, target, func() error {
+			if target.ResourceVersion != "" &&
+				!isDefaultCredentialsCopy(target, clusterTemplateInstance) {
+				return fmt.Errorf(
+					"%s Secret %s/%s already exists and is not a copy of the instance",
+					cred.config,
+					target.Namespace,
+					target.Name,
+				)
+			}
+			target.Labels = map[string]string{
+				v1alpha1.CTINameLabel:      clusterTemplateInstance.Name,
+				v1alpha1.CTINamespaceLabel: clusterTemplateInstance.Namespace,
+			}
+			if ownsDefaultCredentials(clusterTemplateInstance) {
+				target.OwnerReferences = []metav1.OwnerReference{
+					clusterTemplateInstance.GetOwnerReference(),
+				}
+			}
+			if target.CreationTimestamp.IsZero() {
+				target.Type = source.Type
+			}
+			target.Data = map[string][]byte{cred.key: data}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteDefaultCredentials deletes the copies of the hub default credentials of the deleted
+// instance which are not garbage collected together with the instance. The cluster definition
+// references the copies, so it is called once the Applications of the instance are deleted.
+func (r *ClusterTemplateInstanceReconciler) deleteDefaultCredentials(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	if clusterTemplateInstance.Status.ClusterTemplateSpec.DefaultCredentials == nil ||
+		ownsDefaultCredentials(clusterTemplateInstance) {
+		return nil
+	}
+	hostingClient, err := r.getHostingClient(ctx, clusterTemplateInstance)
+	if err != nil {
+		return err
+	}
+	for _, name := range []string{
+		clusterTemplateInstance.GetDefaultPullSecretRef(),
+		clusterTemplateInstance.GetDefaultSSHKeyRef(),
+	} {
+		secret := &corev1.Secret{}
+		if err := hostingClient.Get(
+			ctx,
+			client.ObjectKey{
+				Name:      name,
+				Namespace: clusterTemplateInstance.GetClusterDefinitionNamespace(),
+			},
+			secret,
+		); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		// a Secret of the same name not created by the instance is left untouched
+		if !isDefaultCredentialsCopy(secret, clusterTemplateInstance) {
+			continue
+		}
+		if err := hostingClient.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isDefaultCredentialsCopy returns true if the Secret is labeled as a copy of the default
+// credentials of the instance
+func isDefaultCredentialsCopy(
+	secret *corev1.Secret,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) bool {
+	return secret.Labels[v1alpha1.CTINameLabel] == clusterTemplateInstance.Name &&
+		secret.Labels[v1alpha1.CTINamespaceLabel] == clusterTemplateInstance.Namespace
+}
+
+// ownsDefaultCredentials returns true if the copies of the default credentials are owned by the
+// instance, owner references work only within the namespace of the instance on the hub
+func ownsDefaultCredentials(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) bool {
+	return clusterTemplateInstance.Status.ClusterTemplateSpec.HostingCluster == nil &&
+		clusterTemplateInstance.GetClusterDefinitionNamespace() == clusterTemplateInstance.Namespace
+}
@@ -0,0 +1,231 @@
+package controllers
+
+import (
+	"context"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance default credentials", func() {
+	AfterEach(func() {
+		DefaultPullSecret = ""
+		DefaultSSHKeySecret = ""
+	})
+
+	It("Copies hub default credentials into the cluster definition namespace", func() {
+		cti := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					ClusterDefinition: argo.ApplicationSpec{
+						Destination: argo.ApplicationDestination{
+							Namespace: v1alpha1.CTIInstanceNamespaceVar,
+						},
+					},
+					DefaultCredentials: &v1alpha1.DefaultCredentials{
+						PullSecretValuesKey: "pullSecret.name",
+					},
+				},
+			},
+		}
+		pullSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hub-pull-secret",
+				Namespace: configNamespace,
+			},
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				v1alpha1.DefaultPullSecretKey: []byte(`{"auths":{}}`),
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, pullSecret)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+
+		// not configured by the operator config
+		Expect(reconciler.copyDefaultCredentials(context.TODO(), cti)).ShouldNot(Succeed())
+
+		DefaultPullSecret = pullSecret.Name
+		Expect(reconciler.copyDefaultCredentials(context.TODO(), cti)).Should(Succeed())
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: cti.GetDefaultPullSecretRef(), Namespace: cti.Namespace},
+			secret,
+		)).Should(Succeed())
+		Expect(secret.Type).Should(Equal(corev1.SecretTypeDockerConfigJson))
+		Expect(secret.Data).Should(Equal(pullSecret.Data))
+		Expect(secret.OwnerReferences).Should(HaveLen(1))
+		Expect(secret.Labels).Should(HaveKeyWithValue(v1alpha1.CTINameLabel, cti.Name))
+
+		// the SSH key is not required by the template
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: cti.GetDefaultSSHKeyRef(), Namespace: cti.Namespace},
+			secret,
+		)).ShouldNot(Succeed())
+
+		// the copy in the instance namespace is garbage collected with the instance
+		Expect(reconciler.deleteDefaultCredentials(context.TODO(), cti)).Should(Succeed())
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: cti.GetDefaultPullSecretRef(), Namespace: cti.Namespace},
+			secret,
+		)).Should(Succeed())
+	})
+
+	It("Copies hub default credentials into the target namespace", func() {
+		cti := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					TargetNamespace: "clusters",
+					DefaultCredentials: &v1alpha1.DefaultCredentials{
+						SSHKeyValuesKey: "sshKey.name",
+					},
+				},
+			},
+		}
+		sshKey := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hub-ssh-key",
+				Namespace: configNamespace,
+			},
+			Data: map[string][]byte{
+				v1alpha1.DefaultSSHKeyKey: []byte("ssh-rsa AAAA"),
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, sshKey)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		DefaultSSHKeySecret = sshKey.Name
+
+		Expect(reconciler.copyDefaultCredentials(context.TODO(), cti)).Should(Succeed())
+		secret := &corev1.Secret{}
+		key := client.ObjectKey{Name: cti.GetDefaultSSHKeyRef(), Namespace: "clusters"}
+		Expect(k8sClient.Get(context.TODO(), key, secret)).Should(Succeed())
+		Expect(secret.Data).Should(Equal(sshKey.Data))
+		Expect(secret.OwnerReferences).Should(BeEmpty())
+
+		Expect(reconciler.deleteDefaultCredentials(context.TODO(), cti)).Should(Succeed())
+		Expect(k8sClient.Get(context.TODO(), key, secret)).ShouldNot(Succeed())
+	})
+
+	It("Does not overwrite or delete Secrets of other instances", func() {
+		cti := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					TargetNamespace: "clusters",
+					DefaultCredentials: &v1alpha1.DefaultCredentials{
+						SSHKeyValuesKey: "sshKey.name",
+					},
+				},
+			},
+		}
+		sshKey := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hub-ssh-key",
+				Namespace: configNamespace,
+			},
+			Data: map[string][]byte{
+				v1alpha1.DefaultSSHKeyKey: []byte("ssh-rsa AAAA"),
+			},
+		}
+		foreign := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetDefaultSSHKeyRef(),
+				Namespace: "clusters",
+				Labels: map[string]string{
+					v1alpha1.CTINameLabel:      cti.Name,
+					v1alpha1.CTINamespaceLabel: "other",
+				},
+			},
+			Data: map[string][]byte{
+				v1alpha1.DefaultSSHKeyKey: []byte("ssh-rsa BBBB"),
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, sshKey, foreign)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		DefaultSSHKeySecret = sshKey.Name
+
+		Expect(reconciler.copyDefaultCredentials(context.TODO(), cti)).ShouldNot(Succeed())
+		Expect(reconciler.deleteDefaultCredentials(context.TODO(), cti)).Should(Succeed())
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(foreign), secret)).
+			Should(Succeed())
+		Expect(secret.Data).Should(Equal(foreign.Data))
+	})
+
+	It("Deletes the copies once the applications are gone", func() {
+		now := metav1.Now()
+		cti := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "foo",
+				Namespace:         "default",
+				DeletionTimestamp: &now,
+				Finalizers:        []string{v1alpha1.CTIFinalizer},
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					TargetNamespace: "clusters",
+					DefaultCredentials: &v1alpha1.DefaultCredentials{
+						SSHKeyValuesKey: "sshKey.name",
+					},
+				},
+			},
+		}
+		copied := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetDefaultSSHKeyRef(),
+				Namespace: "clusters",
+				Labels: map[string]string{
+					v1alpha1.CTINameLabel:      cti.Name,
+					v1alpha1.CTINamespaceLabel: cti.Namespace,
+				},
+			},
+		}
+		app := &argo.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       cti.GetDay1ApplicationName(),
+				Namespace:  ArgoCDNamespace,
+				Labels:     copied.Labels,
+				Finalizers: []string{"resources-finalizer.argocd.argoproj.io"},
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti, copied, app)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cti)}
+
+		// the cluster definition may still reference the copies until ArgoCD deletes it
+		_, err := reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(copied), secret)).
+			Should(Succeed())
+
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(app), app)).Should(Succeed())
+		app.Finalizers = nil
+		Expect(k8sClient.Update(context.TODO(), app)).Should(Succeed())
+		_, err = reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(copied), secret)).
+			ShouldNot(Succeed())
+	})
+})
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// maximum number of changed keys listed in the DefaultsDrifted condition
+const maxDriftedKeys = 10
+
+// reconcileDefaultsDrift compares the values set by the current ClusterTemplate with the values
+// the instance was created with and reports the changed keys in DefaultsDrifted condition. The
+// instance is not changed, owners decide whether to re-apply the new defaults. The condition is
+// not reported until the first drift.
+func (r *ClusterTemplateInstanceReconciler) reconcileDefaultsDrift(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) {
+	clusterTemplate := &v1alpha1.ClusterTemplate{}
+	if err := r.Client.Get(
+		ctx,
+		client.ObjectKey{Name: clusterTemplateInstance.Spec.ClusterTemplateRef},
+		clusterTemplate,
+	); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			CTIlog.Error(err, "failed to fetch ClusterTemplate for defaults drift detection")
+		}
+		return
+	}
+
+	changed, err := getDefaultsDrift(ctx, r.Client, clusterTemplateInstance, clusterTemplate)
+	if err != nil {
+		clusterTemplateInstance.SetDefaultsDriftedCondition(
+			metav1.ConditionUnknown,
+			v1alpha1.DefaultsCheckFailed,
+			fmt.Sprintf("Failed to compare template defaults - %q", err),
+		)
+		return
+	}
+	if len(changed) == 0 {
+		// the condition is added once a drift is detected
+		if meta.FindStatusCondition(
+			clusterTemplateInstance.Status.Conditions,
+			string(v1alpha1.DefaultsDrifted),
+		) == nil {
+			return
+		}
+		clusterTemplateInstance.SetDefaultsDriftedCondition(
+			metav1.ConditionFalse,
+			v1alpha1.DefaultsUnchanged,
+			"Template defaults match the installed values",
+		)
+		return
+	}
+	summary := strings.Join(changed, ", ")
+	if len(changed) > maxDriftedKeys {
+		summary = fmt.Sprintf(
+			"%s and %d more",
+			strings.Join(changed[:maxDriftedKeys], ", "),
+			len(changed)-maxDriftedKeys,
+		)
+	}
+	clusterTemplateInstance.SetDefaultsDriftedCondition(
+		metav1.ConditionTrue,
+		v1alpha1.DefaultsChanged,
+		fmt.Sprintf("Template defaults changed since the cluster was created - %s", summary),
+	)
+}
+
+// getDefaultsDrift composes the current template the same way as when the instance was created
+// and returns the keys of changed defaults
+func getDefaultsDrift(
+	ctx context.Context,
+	k8sClient client.Client,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	clusterTemplate *v1alpha1.ClusterTemplate,
+) ([]string, error) {
+	current := clusterTemplate.Spec.DeepCopy()
+	if err := current.ApplyAddOns(clusterTemplateInstance.Spec.AddOns); err != nil {
+		return nil, err
+	}
+	if err := current.ResolveClusterSetupDefinitions(ctx, k8sClient); err != nil {
+		return nil, err
+	}
+	// the base domain derived from the hub is not a default of the template
+	if installed := clusterTemplateInstance.Status.ClusterTemplateSpec; installed != nil {
+		current.SetBaseDomain(installed.GetBaseDomain())
+	}
+	return clusterTemplateInstance.GetDefaultsDrift(*current)
+}
+
+// mapTemplateToInstances enqueues instances of the changed ClusterTemplate
+func (r *ClusterTemplateInstanceReconciler) mapTemplateToInstances(
+	template client.Object,
+) []reconcile.Request {
+	requests := []reconcile.Request{}
+	instances := &v1alpha1.ClusterTemplateInstanceList{}
+	if err := r.Client.List(context.TODO(), instances); err != nil {
+		CTIlog.Error(err, "failed to list cluster template instances")
+		return requests
+	}
+	for _, instance := range instances.Items {
+		if instance.Spec.ClusterTemplateRef == template.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&instance),
+			})
+		}
+	}
+	return requests
+}
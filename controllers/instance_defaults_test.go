@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"context"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance defaults drift", func() {
+	var ct *v1alpha1.ClusterTemplate
+	var cti *v1alpha1.ClusterTemplateInstance
+
+	BeforeEach(func() {
+		ct = &v1alpha1.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+			},
+			Spec: v1alpha1.ClusterTemplateSpec{
+				ClusterDefinition: argo.ApplicationSpec{
+					Source: argo.ApplicationSource{
+						Chart: "hypershift-template",
+						Helm: &argo.ApplicationSourceHelm{
+							Values: "nodePool:\n  replicas: 2\n",
+						},
+					},
+				},
+			},
+		}
+		cti = &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: ct.Spec.DeepCopy(),
+			},
+		}
+	})
+
+	It("Does not report unchanged defaults", func() {
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme, ct),
+		}
+		reconciler.reconcileDefaultsDrift(context.TODO(), cti)
+		Expect(meta.FindStatusCondition(
+			cti.Status.Conditions,
+			string(v1alpha1.DefaultsDrifted),
+		)).Should(BeNil())
+	})
+
+	It("Reports changed defaults", func() {
+		ct.Spec.ClusterDefinition.Source.Helm.Values = "nodePool:\n  replicas: 3\n"
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme, ct),
+		}
+		reconciler.reconcileDefaultsDrift(context.TODO(), cti)
+		condition := meta.FindStatusCondition(
+			cti.Status.Conditions,
+			string(v1alpha1.DefaultsDrifted),
+		)
+		Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).Should(Equal(string(v1alpha1.DefaultsChanged)))
+		Expect(condition.Message).Should(Equal(
+			"Template defaults changed since the cluster was created - nodePool.replicas",
+		))
+
+		// template reverted
+		ct.Spec.ClusterDefinition.Source.Helm.Values = "nodePool:\n  replicas: 2\n"
+		reconciler.Client = fake.NewFakeClientWithScheme(scheme.Scheme, ct)
+		reconciler.reconcileDefaultsDrift(context.TODO(), cti)
+		Expect(meta.IsStatusConditionFalse(
+			cti.Status.Conditions,
+			string(v1alpha1.DefaultsDrifted),
+		)).Should(BeTrue())
+	})
+})
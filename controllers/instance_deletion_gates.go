@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// deletionGatesCheckInterval is how often deletion gates holding the deletion are checked again,
+// hold annotations are watched
+var deletionGatesCheckInterval = 30 * time.Second
+
+var deletionGateHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// deletionGateRequest is the body POSTed to URL deletion gates
+type deletionGateRequest struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	ClusterTemplate string `json:"clusterTemplate"`
+	APIServerURL    string `json:"apiServerURL,omitempty"`
+}
+
+// getDeletionHold returns why the deletion of the instance is held by the first deletion gate
+// of the template which did not acknowledge it yet, empty if all gates are open
+func getDeletionHold(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) string {
+	for _, gate := range clusterTemplateInstance.Status.ClusterTemplateSpec.DeletionGates {
+		if gate.HoldAnnotation != "" {
+			if _, ok := clusterTemplateInstance.Annotations[gate.HoldAnnotation]; ok {
+				return fmt.Sprintf(
+					"Deletion is held by gate '%s' - annotation %s is present",
+					gate.Name,
+					gate.HoldAnnotation,
+				)
+			}
+			continue
+		}
+		if err := callDeletionGate(ctx, gate.URL, clusterTemplateInstance); err != nil {
+			return fmt.Sprintf("Deletion is held by gate '%s' - %s", gate.Name, err)
+		}
+	}
+	return ""
+}
+
+func callDeletionGate(
+	ctx context.Context,
+	gateURL string,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	body, err := json.Marshal(deletionGateRequest{
+		Name:            clusterTemplateInstance.Name,
+		Namespace:       clusterTemplateInstance.Namespace,
+		ClusterTemplate: clusterTemplateInstance.Spec.ClusterTemplateRef,
+		APIServerURL:    clusterTemplateInstance.Status.APIserverURL,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gateURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := deletionGateHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with status code %d", gateURL, resp.StatusCode)
+	}
+	return nil
+}
+
+// holdDeletion reports the deletion held by a gate in the instance status
+func (r *ClusterTemplateInstanceReconciler) holdDeletion(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	hold string,
+) error {
+	if clusterTemplateInstance.Status.Phase == v1alpha1.DeletionHeldPhase &&
+		clusterTemplateInstance.Status.Message == hold {
+		return nil
+	}
+	CTIlog.Info(
+		"Deletion is held",
+		"name",
+		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		"reason",
+		hold,
+	)
+	clusterTemplateInstance.Status.Phase = v1alpha1.DeletionHeldPhase
+	clusterTemplateInstance.Status.Message = hold
+	return r.Status().Update(ctx, clusterTemplateInstance)
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance deletion gates", func() {
+	var server *httptest.Server
+	var acknowledged bool
+	var received deletionGateRequest
+	var cti *v1alpha1.ClusterTemplateInstance
+
+	BeforeEach(func() {
+		acknowledged = false
+		received = deletionGateRequest{}
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if !acknowledged {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		cti = &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "bar",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{},
+				APIserverURL:        "https://api.foo.example.com:6443",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Is open without gates", func() {
+		Expect(getDeletionHold(context.TODO(), cti)).Should(BeEmpty())
+	})
+
+	It("Holds deletion until URL acknowledges it", func() {
+		cti.Status.ClusterTemplateSpec.DeletionGates = []v1alpha1.DeletionGate{
+			{Name: "billing", URL: server.URL},
+		}
+		Expect(getDeletionHold(context.TODO(), cti)).Should(Equal(
+			"Deletion is held by gate 'billing' - " + server.URL + " responded with status code 409",
+		))
+		Expect(received).Should(Equal(deletionGateRequest{
+			Name:            "foo",
+			Namespace:       "default",
+			ClusterTemplate: "bar",
+			APIServerURL:    "https://api.foo.example.com:6443",
+		}))
+
+		acknowledged = true
+		Expect(getDeletionHold(context.TODO(), cti)).Should(BeEmpty())
+	})
+
+	It("Holds deletion while annotation is present", func() {
+		cti.Status.ClusterTemplateSpec.DeletionGates = []v1alpha1.DeletionGate{
+			{Name: "security", HoldAnnotation: "security.example.com/hold"},
+		}
+		cti.Annotations = map[string]string{"security.example.com/hold": ""}
+		Expect(getDeletionHold(context.TODO(), cti)).Should(Equal(
+			"Deletion is held by gate 'security' - annotation security.example.com/hold is present",
+		))
+
+		delete(cti.Annotations, "security.example.com/hold")
+		Expect(getDeletionHold(context.TODO(), cti)).Should(BeEmpty())
+	})
+
+	It("Reports held deletion in status", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		Expect(reconciler.holdDeletion(context.TODO(), cti, "held")).Should(Succeed())
+
+		updated := &v1alpha1.ClusterTemplateInstance{}
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cti), updated)).Should(Succeed())
+		Expect(updated.Status.Phase).Should(Equal(v1alpha1.DeletionHeldPhase))
+		Expect(updated.Status.Message).Should(Equal("held"))
+	})
+})
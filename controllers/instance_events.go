@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// conditions which do not change the phase but are worth reporting, with the status
+// which is reported as a warning
+var eventConditions = map[v1alpha1.ConditionType]metav1.ConditionStatus{
+	v1alpha1.ClusterDefinitionDrifted: metav1.ConditionTrue,
+	v1alpha1.ParametersValid:          metav1.ConditionFalse,
+	v1alpha1.DefaultsDrifted:          metav1.ConditionTrue,
+	v1alpha1.ChartTestsSucceeded:      metav1.ConditionFalse,
+	v1alpha1.Expiring:                 metav1.ConditionTrue,
+	v1alpha1.ProvisioningSLOMet:       metav1.ConditionFalse,
+}
+
+// recordInstanceEvents emits events on the instance when its phase or one of eventConditions
+// changes. The instance lives in the user's namespace, so the user can see failures of the
+// applications and cluster resources without access to the namespaces they live in
+func (r *ClusterTemplateInstanceReconciler) recordInstanceEvents(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	previousPhase v1alpha1.Phase,
+	previousConditions []metav1.Condition,
+) {
+	if r.Recorder == nil {
+		return
+	}
+	phase := clusterTemplateInstance.Status.Phase
+	if phase != previousPhase {
+		eventType := corev1.EventTypeNormal
+		if phase.IsFailed() {
+			eventType = corev1.EventTypeWarning
+		}
+		r.Recorder.Event(
+			clusterTemplateInstance,
+			eventType,
+			string(phase),
+			clusterTemplateInstance.Status.Message,
+		)
+	}
+
+	for conditionType, warningStatus := range eventConditions {
+		condition := meta.FindStatusCondition(
+			clusterTemplateInstance.Status.Conditions,
+			string(conditionType),
+		)
+		if condition == nil {
+			continue
+		}
+		previous := meta.FindStatusCondition(previousConditions, string(conditionType))
+		if previous != nil && previous.Reason == condition.Reason &&
+			previous.Message == condition.Message {
+			continue
+		}
+		eventType := corev1.EventTypeNormal
+		if condition.Status == warningStatus {
+			eventType = corev1.EventTypeWarning
+		}
+		r.Recorder.Event(clusterTemplateInstance, eventType, condition.Reason, condition.Message)
+	}
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/testutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+var _ = Describe("Instance events", func() {
+	var recorder *record.FakeRecorder
+	var reconciler *ClusterTemplateInstanceReconciler
+	var cti *v1alpha1.ClusterTemplateInstance
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &ClusterTemplateInstanceReconciler{Recorder: recorder}
+		cti = testutils.GetCTI()
+	})
+
+	It("Emits warning when phase fails", func() {
+		cti.Status.Phase = v1alpha1.ClusterInstallFailedPhase
+		cti.Status.Message = "HostedCluster is degraded"
+		reconciler.recordInstanceEvents(cti, v1alpha1.ClusterInstallingPhase, nil)
+		Expect(recorder.Events).Should(Receive(Equal(
+			"Warning ClusterInstallFailed HostedCluster is degraded",
+		)))
+	})
+
+	It("Emits normal event when phase progresses", func() {
+		cti.Status.Phase = v1alpha1.ReadyPhase
+		cti.Status.Message = "Cluster is ready"
+		reconciler.recordInstanceEvents(cti, v1alpha1.ClusterSetupRunningPhase, nil)
+		Expect(recorder.Events).Should(Receive(Equal("Normal Ready Cluster is ready")))
+	})
+
+	It("Does not repeat events", func() {
+		cti.Status.Phase = v1alpha1.ClusterInstallingPhase
+		reconciler.recordInstanceEvents(cti, v1alpha1.ClusterInstallingPhase, nil)
+		Expect(recorder.Events).ShouldNot(Receive())
+	})
+
+	It("Emits warning for unknown parameters", func() {
+		cti.Status.Phase = v1alpha1.PendingPhase
+		cti.SetParametersValidCondition(
+			metav1.ConditionFalse,
+			v1alpha1.UnknownParameters,
+			"Parameters are not defined by the chart and will be ignored - [nodecount]",
+		)
+		reconciler.recordInstanceEvents(cti, v1alpha1.PendingPhase, nil)
+		Expect(recorder.Events).Should(Receive(Equal(
+			"Warning UnknownParameters Parameters are not defined by the chart and will be ignored - [nodecount]",
+		)))
+	})
+
+	It("Emits warning when drift is detected", func() {
+		cti.Status.Phase = v1alpha1.ReadyPhase
+		cti.SetClusterDefinitionDriftedCondition(
+			metav1.ConditionTrue,
+			v1alpha1.DriftDetected,
+			"Resources differ from the cluster definition - [NodePool/clusters/foo]",
+		)
+		reconciler.recordInstanceEvents(cti, v1alpha1.ReadyPhase, []metav1.Condition{
+			{
+				Type:   string(v1alpha1.ClusterDefinitionDrifted),
+				Status: metav1.ConditionFalse,
+				Reason: string(v1alpha1.NoDrift),
+			},
+		})
+		Expect(recorder.Events).Should(Receive(Equal(
+			"Warning DriftDetected Resources differ from the cluster definition - [NodePool/clusters/foo]",
+		)))
+	})
+})
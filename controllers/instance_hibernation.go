@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
+)
+
+const (
+	// node pools of remote hosting clusters are not watched, progress of the hibernation is
+	// checked periodically
+	hibernationCheckInterval = 30 * time.Second
+	autoScalingPointer       = "/spec/autoScaling"
+	pausedUntilPointer       = "/spec/pausedUntil"
+)
+
+// reconcileHibernation hibernates the installed hypershift cluster while spec.hibernate is set
+// and resumes it once it is unset. Progress is tracked in the Hibernated condition.
+func (r *ClusterTemplateInstanceReconciler) reconcileHibernation(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	hibernate := clusterTemplateInstance.Spec.Hibernate
+	condition := meta.FindStatusCondition(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.Hibernated),
+	)
+	if (!hibernate && condition == nil) || !isClusterInstalled(clusterTemplateInstance) {
+		return nil
+	}
+	// clusters created through OCM or claimed from a pool have no cluster definition application
+	if ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec; ctSpec.OCM != nil ||
+		ctSpec.ClusterPool != nil {
+		return fmt.Errorf("hibernation is supported for hypershift clusters only")
+	}
+
+	app, err := clusterTemplateInstance.GetDay1Application(ctx, r.Client, ArgoCDNamespace)
+	if err != nil {
+		return err
+	}
+	clusterProvider, err := r.getClusterProvider(ctx, clusterTemplateInstance, app)
+	if err != nil {
+		return err
+	}
+	hibernator, ok := clusterProvider.(clusterprovider.Hibernator)
+	if !ok {
+		return fmt.Errorf("hibernation is supported for hypershift clusters only")
+	}
+
+	// the chart would revert the scaling of the node pools and the pause of the cluster
+	for _, ignored := range []struct{ pointer, kind string }{
+		{replicasPointer, "NodePool"},
+		{autoScalingPointer, "NodePool"},
+		{pausedUntilPointer, "HostedCluster"},
+	} {
+		if err := ignoreHypershiftDifferences(
+			ctx,
+			r.Client,
+			app,
+			ignored.pointer,
+			ignored.kind,
+		); err != nil {
+			return err
+		}
+	}
+
+	if hibernate {
+		hibernated, err := hibernator.Hibernate(ctx, r.Client)
+		if err != nil {
+			return err
+		}
+		if hibernated {
+			clusterTemplateInstance.SetHibernatedCondition(
+				metav1.ConditionTrue,
+				v1alpha1.ClusterHibernated,
+				"Cluster is hibernated",
+			)
+		} else {
+			clusterTemplateInstance.SetHibernatedCondition(
+				metav1.ConditionFalse,
+				v1alpha1.Hibernating,
+				"Waiting for node pools to scale down",
+			)
+		}
+		return nil
+	}
+
+	resumed, err := hibernator.Resume(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+	if resumed {
+		meta.RemoveStatusCondition(
+			&clusterTemplateInstance.Status.Conditions,
+			string(v1alpha1.Hibernated),
+		)
+	} else {
+		clusterTemplateInstance.SetHibernatedCondition(
+			metav1.ConditionFalse,
+			v1alpha1.Resuming,
+			"Waiting for node pools to scale up",
+		)
+	}
+	return nil
+}
+
+// hibernationInProgress returns true while the cluster is being hibernated or resumed
+func hibernationInProgress(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) bool {
+	condition := meta.FindStatusCondition(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.Hibernated),
+	)
+	return condition != nil && condition.Status == metav1.ConditionFalse
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"context"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
+	"github.com/stolostron/cluster-templates-operator/clustersetup"
+	"github.com/stolostron/cluster-templates-operator/helm"
+)
+
+// getHostingHelmClient returns Helm client of the remote hosting cluster of the template,
+// configured by the kubeconfig Secret in the ArgoCD namespace
+func (r *ClusterTemplateInstanceReconciler) getHostingHelmClient(
+	ctx context.Context,
+	hostingCluster *v1alpha1.HostingCluster,
+) (*helm.HelmClient, error) {
+	return r.HelmClient.ForKubeconfigSecret(ctx, hostingCluster.KubeconfigSecret, ArgoCDNamespace)
+}
+
+// getHostingClient returns client of the cluster the cluster definition is installed to - the
+// remote hosting cluster of the template if set, the hub otherwise
+func (r *ClusterTemplateInstanceReconciler) getHostingClient(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) (client.Client, error) {
+	hostingCluster := clusterTemplateInstance.Status.ClusterTemplateSpec.HostingCluster
+	if hostingCluster == nil {
+		return r.Client, nil
+	}
+	helmClient, err := r.getHostingHelmClient(ctx, hostingCluster)
+	if err != nil {
+		return nil, err
+	}
+	return helmClient.GetClient()
+}
+
+// setHostingClusterDestination registers the remote hosting cluster of the template in ArgoCD and
+// points the destination of the cluster definition to it
+func (r *ClusterTemplateInstanceReconciler) setHostingClusterDestination(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+	if ctSpec.HostingCluster == nil {
+		return nil
+	}
+	helmClient, err := r.getHostingHelmClient(ctx, ctSpec.HostingCluster)
+	if err != nil {
+		return err
+	}
+	server, err := clustersetup.AddHostingClusterToArgo(
+		ctx,
+		r.Client,
+		helmClient.GetConfig(),
+		ctSpec.HostingCluster.KubeconfigSecret,
+		ArgoCDNamespace,
+	)
+	if err != nil {
+		return err
+	}
+	ctSpec.ClusterDefinition.Destination.Server = server
+	return nil
+}
+
+// getClusterProvider returns provider of the cluster installed by the application, which reads
+// the cluster resources from the hosting cluster of the template if set
+func (r *ClusterTemplateInstanceReconciler) getClusterProvider(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	app *argo.Application,
+) (clusterprovider.ClusterProvider, error) {
+	provider := clusterprovider.GetClusterProvider(*app)
+	if provider == nil || clusterTemplateInstance.Status.ClusterTemplateSpec.HostingCluster == nil {
+		return provider, nil
+	}
+	hostingClient, err := r.getHostingClient(ctx, clusterTemplateInstance)
+	if err != nil {
+		return nil, err
+	}
+	return clusterprovider.WithHostingClient(provider, hostingClient)
+}
+
+// getHostingClusterName returns name of the kubeconfig Secret of the hosting cluster, empty for
+// the hub
+func getHostingClusterName(ctSpec *v1alpha1.ClusterTemplateSpec) string {
+	if ctSpec.HostingCluster == nil {
+		return ""
+	}
+	return ctSpec.HostingCluster.KubeconfigSecret
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"context"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
+	"github.com/stolostron/cluster-templates-operator/clustersetup"
+	"github.com/stolostron/cluster-templates-operator/helm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance hosting cluster", func() {
+	var cti *v1alpha1.ClusterTemplateInstance
+	var app *argo.Application
+
+	BeforeEach(func() {
+		cti = &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					ClusterDefinition: argo.ApplicationSpec{
+						Destination: argo.ApplicationDestination{
+							Server: "https://kubernetes.default.svc",
+						},
+					},
+				},
+			},
+		}
+		app = &argo.Application{
+			Status: argo.ApplicationStatus{
+				Resources: []argo.ResourceStatus{
+					{
+						Group:     "hypershift.openshift.io",
+						Version:   "v1alpha1",
+						Kind:      "HostedCluster",
+						Name:      "foo",
+						Namespace: "clusters",
+					},
+				},
+			},
+		}
+	})
+
+	It("Uses the hub without hosting cluster", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		hostingClient, err := reconciler.getHostingClient(context.TODO(), cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hostingClient).Should(Equal(k8sClient))
+
+		Expect(reconciler.setHostingClusterDestination(context.TODO(), cti)).Should(Succeed())
+		Expect(cti.Status.ClusterTemplateSpec.ClusterDefinition.Destination.Server).Should(
+			Equal("https://kubernetes.default.svc"),
+		)
+
+		provider, err := reconciler.getClusterProvider(context.TODO(), cti, app)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(provider.(clusterprovider.HostedClusterProvider).HostingClient).Should(BeNil())
+	})
+
+	It("Installs to hosting cluster", func() {
+		kubeconfig := clientcmdapi.NewConfig()
+		kubeconfig.Clusters["hosting"] = &clientcmdapi.Cluster{
+			Server:                   cfg.Host,
+			CertificateAuthorityData: cfg.CAData,
+		}
+		kubeconfig.AuthInfos["admin"] = &clientcmdapi.AuthInfo{
+			ClientCertificateData: cfg.CertData,
+			ClientKeyData:         cfg.KeyData,
+		}
+		kubeconfig.Contexts["hosting"] = &clientcmdapi.Context{
+			Cluster:  "hosting",
+			AuthInfo: "admin",
+		}
+		kubeconfig.CurrentContext = "hosting"
+		data, err := clientcmd.Write(*kubeconfig)
+		Expect(err).ShouldNot(HaveOccurred())
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "hosting-kubeconfig", Namespace: ArgoCDNamespace},
+			Data:       map[string][]byte{helm.KubeconfigSecretKey: data},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, secret)
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client:     k8sClient,
+			HelmClient: helm.NewHelmClient(cfg, k8sClient, nil, nil, nil),
+		}
+		cti.Status.ClusterTemplateSpec.HostingCluster = &v1alpha1.HostingCluster{
+			KubeconfigSecret: secret.Name,
+		}
+
+		Expect(reconciler.setHostingClusterDestination(context.TODO(), cti)).Should(Succeed())
+		Expect(cti.Status.ClusterTemplateSpec.ClusterDefinition.Destination.Server).Should(
+			Equal(cfg.Host),
+		)
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKey{
+			Name:      clustersetup.GetHostingClusterSecretName(secret.Name),
+			Namespace: ArgoCDNamespace,
+		}, &corev1.Secret{})).Should(Succeed())
+
+		provider, err := reconciler.getClusterProvider(context.TODO(), cti, app)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(provider.(clusterprovider.HostedClusterProvider).HostingClient).ShouldNot(BeNil())
+	})
+})
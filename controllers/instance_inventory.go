@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+)
+
+// kinds of cluster-scoped resources which are shared by instances and handed over to other
+// instances on deletion, the operator is allowed to patch only these
+var sharedResourceKinds = map[schema.GroupKind]bool{
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:         true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:  true,
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}: true,
+}
+
+// reconcileInventory records cluster-scoped resources of the cluster definition application in
+// the instance status. Unlike namespaced resources, which are created in the namespace of the
+// instance, the same cluster-scoped resource (ie a ClusterRole) may be rendered by releases of
+// several instances. Only resources of the shared kinds are recorded.
+func (r *ClusterTemplateInstanceReconciler) reconcileInventory(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	app, err := clusterTemplateInstance.GetDay1Application(ctx, r.Client, ArgoCDNamespace)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	// the application was not reconciled by ArgoCD yet, keep the previous inventory
+	if len(app.Status.Resources) == 0 {
+		return nil
+	}
+	resources := []v1alpha1.ClusterScopedResource{}
+	for _, res := range app.Status.Resources {
+		if res.Namespace != "" || res.Hook ||
+			!sharedResourceKinds[schema.GroupKind{Group: res.Group, Kind: res.Kind}] {
+			continue
+		}
+		resources = append(resources, v1alpha1.ClusterScopedResource{
+			Group:   res.Group,
+			Version: res.Version,
+			Kind:    res.Kind,
+			Name:    res.Name,
+		})
+	}
+	clusterTemplateInstance.Status.ClusterScopedResources = resources
+	return nil
+}
+
+// releaseSharedResources hands the cluster-scoped resources of the instance, which are also
+// created by other instances, over to the cluster definition application of one of them. ArgoCD
+// deletes only the resources tracked by the deleted application, so uninstalling the instance
+// does not break the other instances.
+func (r *ClusterTemplateInstanceReconciler) releaseSharedResources(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	if len(clusterTemplateInstance.Status.ClusterScopedResources) == 0 {
+		return nil
+	}
+	instances := &v1alpha1.ClusterTemplateInstanceList{}
+	if err := r.Client.List(ctx, instances); err != nil {
+		return err
+	}
+	hostingCluster := getHostingClusterName(clusterTemplateInstance.Status.ClusterTemplateSpec)
+	owners := map[v1alpha1.ClusterScopedResource]string{}
+	for _, instance := range instances.Items {
+		// instances which are being deleted do not need the resources anymore
+		if instance.UID == clusterTemplateInstance.UID || !instance.DeletionTimestamp.IsZero() || instance.Status.ClusterTemplateSpec == nil ||
+			getHostingClusterName(instance.Status.ClusterTemplateSpec) != hostingCluster {
+			continue
+		}
+		for _, res := range instance.Status.ClusterScopedResources {
+			owners[res] = instance.GetDay1ApplicationName()
+		}
+	}
+
+	hostingClient, err := r.getHostingClient(ctx, clusterTemplateInstance)
+	if err != nil {
+		return err
+	}
+	appName := clusterTemplateInstance.GetDay1ApplicationName()
+	for _, res := range clusterTemplateInstance.Status.ClusterScopedResources {
+		owner, shared := owners[res]
+		if !shared || !sharedResourceKinds[schema.GroupKind{Group: res.Group, Kind: res.Kind}] {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{
+			Group:   res.Group,
+			Version: res.Version,
+			Kind:    res.Kind,
+		})
+		if err := hostingClient.Get(ctx, client.ObjectKey{Name: res.Name}, obj); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return err
+			}
+			continue
+		}
+		if argocd.GetTrackingApplication(obj) != appName {
+			continue
+		}
+		patch := client.MergeFrom(obj.DeepCopy())
+		argocd.SetTrackingApplication(obj, owner)
+		if err := hostingClient.Patch(ctx, obj, patch); err != nil {
+			return err
+		}
+		CTIlog.Info(
+			"Shared cluster-scoped resource handed over to another instance",
+			"name", clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+			"resource", res.Kind+"/"+res.Name,
+			"application", owner,
+		)
+	}
+	return nil
+}
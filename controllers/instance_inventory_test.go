@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"context"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance inventory", func() {
+	sharedRole := v1alpha1.ClusterScopedResource{
+		Group:   "rbac.authorization.k8s.io",
+		Version: "v1",
+		Kind:    "ClusterRole",
+		Name:    "shared",
+	}
+	ownRole := v1alpha1.ClusterScopedResource{
+		Group:   "rbac.authorization.k8s.io",
+		Version: "v1",
+		Kind:    "ClusterRole",
+		Name:    "own",
+	}
+
+	newInstance := func(
+		name string,
+		resources ...v1alpha1.ClusterScopedResource,
+	) *v1alpha1.ClusterTemplateInstance {
+		return &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				UID:       types.UID("uid-" + name),
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec:    &v1alpha1.ClusterTemplateSpec{},
+				ClusterScopedResources: resources,
+			},
+		}
+	}
+
+	newRole := func(name string, app string) *rbacv1.ClusterRole {
+		return &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{argocd.TrackingLabel: app},
+			},
+		}
+	}
+
+	It("Records cluster-scoped resources of the cluster definition", func() {
+		cti := newInstance("foo")
+		app := &argo.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetDay1ApplicationName(),
+				Namespace: ArgoCDNamespace,
+				Labels: map[string]string{
+					v1alpha1.CTINameLabel:      cti.Name,
+					v1alpha1.CTINamespaceLabel: cti.Namespace,
+				},
+			},
+			Status: argo.ApplicationStatus{
+				Resources: []argo.ResourceStatus{
+					{
+						Group:     "hypershift.openshift.io",
+						Version:   "v1alpha1",
+						Kind:      "HostedCluster",
+						Name:      "foo",
+						Namespace: "clusters",
+					},
+					{
+						Group:   sharedRole.Group,
+						Version: sharedRole.Version,
+						Kind:    sharedRole.Kind,
+						Name:    sharedRole.Name,
+					},
+					{
+						Group:   "batch",
+						Version: "v1",
+						Kind:    "Job",
+						Name:    "hook",
+						Hook:    true,
+					},
+				},
+			},
+		}
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme, app),
+		}
+		Expect(reconciler.reconcileInventory(context.TODO(), cti)).Should(Succeed())
+		Expect(cti.Status.ClusterScopedResources).Should(
+			Equal([]v1alpha1.ClusterScopedResource{sharedRole}),
+		)
+
+		// application not reconciled by ArgoCD yet
+		app.Status.Resources = nil
+		Expect(reconciler.Client.Update(context.TODO(), app)).Should(Succeed())
+		Expect(reconciler.reconcileInventory(context.TODO(), cti)).Should(Succeed())
+		Expect(cti.Status.ClusterScopedResources).Should(
+			Equal([]v1alpha1.ClusterScopedResource{sharedRole}),
+		)
+	})
+
+	It("Hands shared resources over to other instances on deletion", func() {
+		cti := newInstance("foo", sharedRole, ownRole)
+		now := metav1.Now()
+		cti.DeletionTimestamp = &now
+		sibling := newInstance("bar", sharedRole)
+		deleted := newInstance("baz", ownRole)
+		deleted.DeletionTimestamp = &now
+		deleted.Finalizers = []string{v1alpha1.CTIFinalizer}
+
+		k8sClient := fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			sibling,
+			deleted,
+			newRole(sharedRole.Name, cti.GetDay1ApplicationName()),
+			newRole(ownRole.Name, cti.GetDay1ApplicationName()),
+		)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		Expect(reconciler.releaseSharedResources(context.TODO(), cti)).Should(Succeed())
+
+		role := &rbacv1.ClusterRole{}
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: sharedRole.Name}, role)).
+			Should(Succeed())
+		Expect(argocd.GetTrackingApplication(role)).Should(Equal(sibling.GetDay1ApplicationName()))
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Name: ownRole.Name}, role)).
+			Should(Succeed())
+		Expect(argocd.GetTrackingApplication(role)).Should(Equal(cti.GetDay1ApplicationName()))
+	})
+})
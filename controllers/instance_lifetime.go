@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// expiration is reported a quarter of the lifetime ahead, at most a day ahead
+const maxExpirationWarningPeriod = 24 * time.Hour
+
+func getExpirationWarningPeriod(maxLifetime time.Duration) time.Duration {
+	if period := maxLifetime / 4; period < maxExpirationWarningPeriod {
+		return period
+	}
+	return maxExpirationWarningPeriod
+}
+
+// reconcileLifetime sets the expiration time of instances of templates limiting the lifetime of
+// their instances and reports the approaching expiration in the Expiring condition. Returns true
+// once the lifetime elapsed.
+func reconcileLifetime(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) bool {
+	maxLifetime := clusterTemplateInstance.Status.ClusterTemplateSpec.MaxLifetime
+	if maxLifetime == nil {
+		return false
+	}
+	expiration := metav1.NewTime(clusterTemplateInstance.CreationTimestamp.Add(maxLifetime.Duration))
+	clusterTemplateInstance.Status.ExpirationTime = &expiration
+
+	remaining := time.Until(expiration.Time)
+	msg := fmt.Sprintf(
+		"Instance is deleted at %s, when the maximum lifetime %s of the template elapses",
+		expiration.UTC().Format(time.RFC3339),
+		maxLifetime.Duration,
+	)
+	switch {
+	case remaining <= 0:
+		clusterTemplateInstance.SetExpiringCondition(
+			metav1.ConditionTrue,
+			v1alpha1.LifetimeExpired,
+			fmt.Sprintf(
+				"Maximum lifetime %s of the template elapsed, the instance is deleted",
+				maxLifetime.Duration,
+			),
+		)
+		return true
+	case remaining <= getExpirationWarningPeriod(maxLifetime.Duration):
+		clusterTemplateInstance.SetExpiringCondition(
+			metav1.ConditionTrue,
+			v1alpha1.LifetimeExpiring,
+			msg,
+		)
+	default:
+		clusterTemplateInstance.SetExpiringCondition(
+			metav1.ConditionFalse,
+			v1alpha1.LifetimeRemaining,
+			msg,
+		)
+	}
+	return false
+}
+
+// lifetimeCheckAfter returns how long it takes until the Expiring condition changes, zero is
+// returned for instances without limited lifetime
+func lifetimeCheckAfter(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) time.Duration {
+	ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+	expiration := clusterTemplateInstance.Status.ExpirationTime
+	if ctSpec == nil || ctSpec.MaxLifetime == nil || expiration == nil {
+		return 0
+	}
+	remaining := time.Until(expiration.Time)
+	if remaining <= 0 {
+		return 0
+	}
+	warningPeriod := getExpirationWarningPeriod(ctSpec.MaxLifetime.Duration)
+	if remaining > warningPeriod {
+		return remaining - warningPeriod
+	}
+	return remaining
+}
+
+// deleteExpiredInstance reports the expiration in the instance status and deletes the instance,
+// the applications and the cluster are deleted by the finalizer as usual
+func (r *ClusterTemplateInstanceReconciler) deleteExpiredInstance(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	previousPhase v1alpha1.Phase,
+	previousConditions []metav1.Condition,
+) (ctrl.Result, error) {
+	if err := r.Status().Update(ctx, clusterTemplateInstance); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.recordInstanceEvents(clusterTemplateInstance, previousPhase, previousConditions)
+	CTIlog.Info(
+		"Maximum lifetime elapsed, deleting instance",
+		"name",
+		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+	)
+	return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, clusterTemplateInstance))
+}
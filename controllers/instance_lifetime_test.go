@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance lifetime", func() {
+	newInstance := func(age time.Duration) *v1alpha1.ClusterTemplateInstance {
+		return &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "foo",
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					MaxLifetime: &metav1.Duration{Duration: 8 * 24 * time.Hour},
+				},
+			},
+		}
+	}
+
+	It("Warns about approaching expiration", func() {
+		cti := newInstance(time.Hour)
+		Expect(reconcileLifetime(cti)).Should(BeFalse())
+		Expect(cti.Status.ExpirationTime.Time).Should(
+			Equal(cti.CreationTimestamp.Add(8 * 24 * time.Hour)),
+		)
+		condition := meta.FindStatusCondition(cti.Status.Conditions, string(v1alpha1.Expiring))
+		Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).Should(Equal(string(v1alpha1.LifetimeRemaining)))
+		// re-checked when the warning period starts
+		Expect(lifetimeCheckAfter(cti)).Should(
+			BeNumerically("~", 6*24*time.Hour+23*time.Hour, time.Minute),
+		)
+
+		cti = newInstance(7*24*time.Hour + time.Hour)
+		Expect(reconcileLifetime(cti)).Should(BeFalse())
+		condition = meta.FindStatusCondition(cti.Status.Conditions, string(v1alpha1.Expiring))
+		Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).Should(Equal(string(v1alpha1.LifetimeExpiring)))
+		Expect(lifetimeCheckAfter(cti)).Should(BeNumerically("~", 23*time.Hour, time.Minute))
+
+		// short lifetimes are warned about a quarter ahead
+		Expect(getExpirationWarningPeriod(4 * time.Hour)).Should(Equal(time.Hour))
+	})
+
+	It("Deletes expired instances", func() {
+		cti := newInstance(9 * 24 * time.Hour)
+		cti.Finalizers = []string{v1alpha1.CTIFinalizer}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+
+		Expect(reconcileLifetime(cti)).Should(BeTrue())
+		Expect(lifetimeCheckAfter(cti)).Should(BeZero())
+		_, err := reconciler.deleteExpiredInstance(context.TODO(), cti, "", nil)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		deleted := &v1alpha1.ClusterTemplateInstance{}
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cti), deleted)).
+			Should(Succeed())
+		Expect(deleted.DeletionTimestamp).ShouldNot(BeNil())
+		Expect(meta.IsStatusConditionTrue(deleted.Status.Conditions, string(v1alpha1.Expiring))).
+			Should(BeTrue())
+	})
+
+	It("Ignores instances of templates without maximum lifetime", func() {
+		cti := newInstance(time.Hour)
+		cti.Status.ClusterTemplateSpec.MaxLifetime = nil
+		Expect(reconcileLifetime(cti)).Should(BeFalse())
+		Expect(cti.Status.ExpirationTime).Should(BeNil())
+		Expect(cti.Status.Conditions).Should(BeEmpty())
+		Expect(lifetimeCheckAfter(cti)).Should(BeZero())
+	})
+})
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// phases of instances waiting for their cluster or cluster setup, instances in these phases are
+// reconciled periodically until the phase changes
+var waitingPhases = map[v1alpha1.Phase]bool{
+	v1alpha1.PendingPhase:              true,
+	v1alpha1.ClusterInstallingPhase:    true,
+	v1alpha1.AddingArgoClusterPhase:    true,
+	v1alpha1.CreatingClusterSetupPhase: true,
+	v1alpha1.ClusterSetupRunningPhase:  true,
+}
+
+var waitingInstances = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "clustertemplateinstance_waiting",
+		Help: "Number of ClusterTemplateInstances waiting for their cluster or cluster setup, by phase",
+	},
+	[]string{"phase"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(waitingInstances)
+}
+
+// instanceTracker remembers the last logged status and the phase of every instance. Instances
+// waiting for their clusters are reconciled periodically, so the status is logged only when it
+// changes and the waiting instances are counted by the waitingInstances metric instead.
+type instanceTracker struct {
+	lock     sync.Mutex
+	statuses map[types.NamespacedName]string
+	phases   map[types.NamespacedName]v1alpha1.Phase
+}
+
+// trackedInstances is shared by the restarts of the instance controller
+var trackedInstances = newInstanceTracker()
+
+func newInstanceTracker() *instanceTracker {
+	return &instanceTracker{
+		statuses: map[types.NamespacedName]string{},
+		phases:   map[types.NamespacedName]v1alpha1.Phase{},
+	}
+}
+
+// statusChanged records the status of the instance, returns false if it is the same as
+// the last recorded one
+func (t *instanceTracker) statusChanged(key types.NamespacedName, status string) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if last, ok := t.statuses[key]; ok && last == status {
+		return false
+	}
+	t.statuses[key] = status
+	return true
+}
+
+// setPhase records the phase of the instance and updates the waitingInstances metric
+func (t *instanceTracker) setPhase(key types.NamespacedName, phase v1alpha1.Phase) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	previous, ok := t.phases[key]
+	if ok && previous == phase {
+		return
+	}
+	if ok && waitingPhases[previous] {
+		waitingInstances.WithLabelValues(string(previous)).Dec()
+	}
+	if waitingPhases[phase] {
+		waitingInstances.WithLabelValues(string(phase)).Inc()
+	}
+	t.phases[key] = phase
+}
+
+// forget drops the deleted instance
+func (t *instanceTracker) forget(key types.NamespacedName) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if previous, ok := t.phases[key]; ok && waitingPhases[previous] {
+		waitingInstances.WithLabelValues(string(previous)).Dec()
+	}
+	delete(t.phases, key)
+	delete(t.statuses, key)
+}
+
+// logPhaseChange logs transitions of the instance phase
+func (r *ClusterTemplateInstanceReconciler) logPhaseChange(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	previousPhase v1alpha1.Phase,
+) {
+	phase := clusterTemplateInstance.Status.Phase
+	if phase == previousPhase {
+		return
+	}
+	CTIlog.Info(
+		"Instance phase changed",
+		"name",
+		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		"phase",
+		phase,
+		"previousPhase",
+		previousPhase,
+		"message",
+		clusterTemplateInstance.Status.Message,
+	)
+}
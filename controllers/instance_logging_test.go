@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+var _ = Describe("Instance tracker", func() {
+	key := types.NamespacedName{Name: "tracked", Namespace: "default"}
+	waiting := func(phase v1alpha1.Phase) float64 {
+		return testutil.ToFloat64(waitingInstances.WithLabelValues(string(phase)))
+	}
+
+	It("Reports only changed status", func() {
+		tracker := newInstanceTracker()
+		Expect(tracker.statusChanged(key, "Cluster is installing")).Should(BeTrue())
+		Expect(tracker.statusChanged(key, "Cluster is installing")).Should(BeFalse())
+		Expect(tracker.statusChanged(key, "Cluster is ready")).Should(BeTrue())
+
+		tracker.forget(key)
+		Expect(tracker.statusChanged(key, "Cluster is ready")).Should(BeTrue())
+	})
+
+	It("Counts waiting instances", func() {
+		tracker := newInstanceTracker()
+		installing := waiting(v1alpha1.ClusterInstallingPhase)
+		setupRunning := waiting(v1alpha1.ClusterSetupRunningPhase)
+
+		tracker.setPhase(key, v1alpha1.ClusterInstallingPhase)
+		tracker.setPhase(key, v1alpha1.ClusterInstallingPhase)
+		Expect(waiting(v1alpha1.ClusterInstallingPhase)).Should(Equal(installing + 1))
+
+		tracker.setPhase(key, v1alpha1.ClusterSetupRunningPhase)
+		Expect(waiting(v1alpha1.ClusterInstallingPhase)).Should(Equal(installing))
+		Expect(waiting(v1alpha1.ClusterSetupRunningPhase)).Should(Equal(setupRunning + 1))
+
+		tracker.setPhase(key, v1alpha1.ReadyPhase)
+		Expect(waiting(v1alpha1.ClusterSetupRunningPhase)).Should(Equal(setupRunning))
+
+		tracker.setPhase(key, v1alpha1.ClusterInstallingPhase)
+		tracker.forget(key)
+		Expect(waiting(v1alpha1.ClusterInstallingPhase)).Should(Equal(installing))
+	})
+})
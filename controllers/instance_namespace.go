@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// ensureTargetNamespace creates the target namespace of the template on the hosting cluster, if
+// set and it does not exist. Namespaces created by the operator are labeled, so they can be
+// deleted together with the last instance deployed to them.
+func (r *ClusterTemplateInstanceReconciler) ensureTargetNamespace(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	name := clusterTemplateInstance.Status.ClusterTemplateSpec.TargetNamespace
+	if name == "" {
+		return nil
+	}
+	hostingClient, err := r.getHostingClient(ctx, clusterTemplateInstance)
+	if err != nil {
+		return err
+	}
+	ns := &corev1.Namespace{}
+	err = hostingClient.Get(ctx, client.ObjectKey{Name: name}, ns)
+	if err == nil {
+		if ns.DeletionTimestamp != nil {
+			return fmt.Errorf("target namespace %s is being deleted", name)
+		}
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	ns = &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				v1alpha1.CTIManagedNamespaceLabel: "true",
+				"argocd.argoproj.io/managed-by":   ArgoCDNamespace,
+			},
+		},
+	}
+	CTIlog.Info(
+		"Creating target namespace",
+		"name",
+		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		"namespace",
+		name,
+	)
+	if err := hostingClient.Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// releaseTargetNamespace deletes the target namespace of the deleted instance if it was created
+// by the operator and no other instance is (or is about to be) deployed to it on the same hosting
+// cluster. It is called once the Applications of the instance are deleted.
+func (r *ClusterTemplateInstanceReconciler) releaseTargetNamespace(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	name := clusterTemplateInstance.Status.ClusterTemplateSpec.TargetNamespace
+	if name == "" {
+		return nil
+	}
+	hostingClient, err := r.getHostingClient(ctx, clusterTemplateInstance)
+	if err != nil {
+		return err
+	}
+	ns := &corev1.Namespace{}
+	if err := hostingClient.Get(ctx, client.ObjectKey{Name: name}, ns); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if ns.Labels[v1alpha1.CTIManagedNamespaceLabel] != "true" || ns.DeletionTimestamp != nil {
+		return nil
+	}
+	instances := &v1alpha1.ClusterTemplateInstanceList{}
+	if err := r.Client.List(ctx, instances); err != nil {
+		return err
+	}
+	hostingCluster := getHostingClusterName(clusterTemplateInstance.Status.ClusterTemplateSpec)
+	for _, instance := range instances.Items {
+		if instance.UID == clusterTemplateInstance.UID {
+			continue
+		}
+		ctSpec := instance.Status.ClusterTemplateSpec
+		if ctSpec == nil {
+			// the instance did not take the snapshot of its template yet, it is deployed to the
+			// target namespace of the template it references
+			template := &v1alpha1.ClusterTemplate{}
+			if err := r.Client.Get(
+				ctx,
+				client.ObjectKey{Name: instance.Spec.ClusterTemplateRef},
+				template,
+			); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return err
+			}
+			ctSpec = &template.Spec
+		}
+		if ctSpec.TargetNamespace == name && getHostingClusterName(ctSpec) == hostingCluster {
+			return nil
+		}
+	}
+	CTIlog.Info(
+		"Deleting target namespace",
+		"name",
+		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		"namespace",
+		name,
+	)
+	return client.IgnoreNotFound(hostingClient.Delete(ctx, ns))
+}
@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"context"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance target namespace", func() {
+	var cti *v1alpha1.ClusterTemplateInstance
+
+	BeforeEach(func() {
+		cti = &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+				UID:       "foo",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					TargetNamespace: "clusters",
+				},
+			},
+		}
+	})
+
+	getNamespace := func(k8sClient client.Client) (*corev1.Namespace, error) {
+		ns := &corev1.Namespace{}
+		err := k8sClient.Get(context.TODO(), client.ObjectKey{Name: "clusters"}, ns)
+		return ns, err
+	}
+
+	It("Creates target namespace", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		Expect(reconciler.ensureTargetNamespace(context.TODO(), cti)).Should(Succeed())
+		ns, err := getNamespace(k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ns.Labels[v1alpha1.CTIManagedNamespaceLabel]).Should(Equal("true"))
+		Expect(ns.Labels["argocd.argoproj.io/managed-by"]).Should(Equal(ArgoCDNamespace))
+
+		Expect(reconciler.ensureTargetNamespace(context.TODO(), cti)).Should(Succeed())
+	})
+
+	It("Keeps target namespace used by another instance", func() {
+		other := cti.DeepCopy()
+		other.Name = "bar"
+		other.UID = "bar"
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti, other)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		Expect(reconciler.ensureTargetNamespace(context.TODO(), cti)).Should(Succeed())
+
+		Expect(reconciler.releaseTargetNamespace(context.TODO(), cti)).Should(Succeed())
+		_, err := getNamespace(k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(k8sClient.Delete(context.TODO(), other)).Should(Succeed())
+		Expect(reconciler.releaseTargetNamespace(context.TODO(), cti)).Should(Succeed())
+		_, err = getNamespace(k8sClient)
+		Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+	})
+
+	It("Keeps target namespace of pending instances of the template", func() {
+		template := &v1alpha1.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "foo-tmp"},
+			Spec:       *cti.Status.ClusterTemplateSpec,
+		}
+		pending := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "default",
+				UID:       "bar",
+			},
+			Spec: v1alpha1.ClusterTemplateInstanceSpec{ClusterTemplateRef: template.Name},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti, pending, template)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		Expect(reconciler.ensureTargetNamespace(context.TODO(), cti)).Should(Succeed())
+
+		Expect(reconciler.releaseTargetNamespace(context.TODO(), cti)).Should(Succeed())
+		_, err := getNamespace(k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		template.Spec.TargetNamespace = "other"
+		Expect(k8sClient.Update(context.TODO(), template)).Should(Succeed())
+		Expect(reconciler.releaseTargetNamespace(context.TODO(), cti)).Should(Succeed())
+		_, err = getNamespace(k8sClient)
+		Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+	})
+
+	It("Does not delete existing namespace", func() {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "clusters"}}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, ns, cti)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		Expect(reconciler.ensureTargetNamespace(context.TODO(), cti)).Should(Succeed())
+		Expect(reconciler.releaseTargetNamespace(context.TODO(), cti)).Should(Succeed())
+		_, err := getNamespace(k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("Deletes target namespace once the applications are gone", func() {
+		now := metav1.Now()
+		cti.DeletionTimestamp = &now
+		cti.Finalizers = []string{v1alpha1.CTIFinalizer}
+		app := &argo.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetDay1ApplicationName(),
+				Namespace: ArgoCDNamespace,
+				Labels: map[string]string{
+					v1alpha1.CTINameLabel:      cti.Name,
+					v1alpha1.CTINamespaceLabel: cti.Namespace,
+				},
+				// ArgoCD deletes the resources of the application first
+				Finalizers: []string{"resources-finalizer.argocd.argoproj.io"},
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti, app)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		Expect(reconciler.ensureTargetNamespace(context.TODO(), cti)).Should(Succeed())
+
+		req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cti)}
+		res, err := reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(res.RequeueAfter).Should(Equal(applicationsDeletionCheckInterval))
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(app), app)).Should(Succeed())
+		Expect(app.DeletionTimestamp).ShouldNot(BeNil())
+		_, err = getNamespace(k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(k8sClient.Get(context.TODO(), req.NamespacedName, cti)).Should(Succeed())
+		Expect(cti.Finalizers).Should(ConsistOf(v1alpha1.CTIFinalizer))
+
+		app.Finalizers = nil
+		Expect(k8sClient.Update(context.TODO(), app)).Should(Succeed())
+		_, err = reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = getNamespace(k8sClient)
+		Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+		err = k8sClient.Get(context.TODO(), req.NamespacedName, cti)
+		Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+	})
+})
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
+	"github.com/stolostron/cluster-templates-operator/ocm"
+)
+
+// ocmCheckInterval is how often the status of clusters installed through OCM is read, OCM can not
+// be watched
+var ocmCheckInterval = time.Minute
+
+// optional keys of the OCM credentials Secret with the AWS account clusters are created in (CCS)
+const (
+	ocmAWSAccountIDKey       = "awsAccountID"
+	ocmAWSAccessKeyIDKey     = "awsAccessKeyID"
+	ocmAWSSecretAccessKeyKey = "awsSecretAccessKey"
+)
+
+// getOCMClient returns client of the OCM API authenticated by the credentials Secret of the
+// template, the data of the Secret is returned too
+func (r *ClusterTemplateInstanceReconciler) getOCMClient(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) (*ocm.Client, map[string][]byte, error) {
+	ocmCluster := clusterTemplateInstance.Status.ClusterTemplateSpec.OCM
+	secret := &corev1.Secret{}
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{Name: ocmCluster.CredentialsSecret, Namespace: ArgoCDNamespace},
+		secret,
+	); err != nil {
+		return nil, nil, fmt.Errorf(
+			"failed to get OCM credentials Secret %s - %q",
+			ocmCluster.CredentialsSecret,
+			err,
+		)
+	}
+	ocmClient, err := ocm.NewClient(ocmCluster.URL, ocmCluster.TokenURL, secret.Data)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"invalid OCM credentials Secret %s - %w",
+			ocmCluster.CredentialsSecret,
+			err,
+		)
+	}
+	return ocmClient, secret.Data, nil
+}
+
+// createOCMCluster creates the managed cluster of the instance through OCM instead of installing
+// the cluster definition. The cluster is not created twice if the status of the instance was not
+// updated after the cluster was created.
+func (r *ClusterTemplateInstanceReconciler) createOCMCluster(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	ocmClient, credentials, err := r.getOCMClient(ctx, clusterTemplateInstance)
+	if err != nil {
+		return err
+	}
+	name := clusterTemplateInstance.GetOCMClusterName()
+	cluster, err := ocmClient.FindCluster(ctx, name)
+	if err != nil || cluster != nil {
+		return err
+	}
+	properties, err := clusterTemplateInstance.Status.ClusterTemplateSpec.OCM.GetClusterProperties(
+		name,
+		clusterTemplateInstance.Namespace+"-"+clusterTemplateInstance.Name,
+	)
+	if err != nil {
+		return err
+	}
+	if len(credentials[ocmAWSAccessKeyIDKey]) > 0 {
+		aws, _ := properties["aws"].(map[string]interface{})
+		if aws == nil {
+			aws = map[string]interface{}{}
+		}
+		aws["account_id"] = string(credentials[ocmAWSAccountIDKey])
+		aws["access_key_id"] = string(credentials[ocmAWSAccessKeyIDKey])
+		aws["secret_access_key"] = string(credentials[ocmAWSSecretAccessKeyKey])
+		properties["aws"] = aws
+		properties["ccs"] = map[string]interface{}{"enabled": true}
+	}
+	cluster, err = ocmClient.CreateCluster(ctx, properties)
+	if err != nil {
+		return err
+	}
+	CTIlog.Info(
+		"OCM cluster created",
+		"name",
+		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		"cluster",
+		cluster.ID,
+	)
+	return nil
+}
+
+// deleteOCMCluster starts uninstallation of the OCM cluster of the deleted instance
+func (r *ClusterTemplateInstanceReconciler) deleteOCMCluster(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	if clusterTemplateInstance.Status.ClusterTemplateSpec.OCM == nil {
+		return nil
+	}
+	ocmClient, _, err := r.getOCMClient(ctx, clusterTemplateInstance)
+	if err != nil {
+		return err
+	}
+	cluster, err := ocmClient.FindCluster(ctx, clusterTemplateInstance.GetOCMClusterName())
+	if err != nil || cluster == nil || cluster.State == ocm.ClusterStateUninstalling {
+		return err
+	}
+	return ocmClient.DeleteCluster(ctx, cluster.ID)
+}
+
+// getOCMProvider returns provider of the cluster created through OCM
+func (r *ClusterTemplateInstanceReconciler) getOCMProvider(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) (clusterprovider.ClusterProvider, error) {
+	ocmClient, _, err := r.getOCMClient(ctx, clusterTemplateInstance)
+	if err != nil {
+		return nil, err
+	}
+	return clusterprovider.OCMProvider{
+		Client:      ocmClient,
+		ClusterName: clusterTemplateInstance.GetOCMClusterName(),
+	}, nil
+}
+
+// ocmClusterInstalling returns true while the cluster created through OCM is not installed
+func ocmClusterInstalling(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) bool {
+	ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+	return ctSpec != nil && ctSpec.OCM != nil && meta.IsStatusConditionTrue(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.ClusterDefinitionCreated),
+	) && !isClusterInstalled(clusterTemplateInstance)
+}
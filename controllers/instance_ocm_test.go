@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/ocm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance OCM cluster", func() {
+	var server *httptest.Server
+	var clusters []ocm.Cluster
+	var created []map[string]interface{}
+	var deleted []string
+
+	BeforeEach(func() {
+		clusters = []ocm.Cluster{}
+		created = nil
+		deleted = nil
+		mux := http.NewServeMux()
+		mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"access_token":"token"}`))
+		})
+		mux.HandleFunc("/api/clusters_mgmt/v1/clusters", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				properties := map[string]interface{}{}
+				Expect(json.NewDecoder(r.Body).Decode(&properties)).Should(Succeed())
+				created = append(created, properties)
+				cluster := ocm.Cluster{ID: "id-1", Name: properties["name"].(string)}
+				clusters = append(clusters, cluster)
+				Expect(json.NewEncoder(w).Encode(cluster)).Should(Succeed())
+				return
+			}
+			Expect(json.NewEncoder(w).Encode(map[string]interface{}{"items": clusters})).
+				Should(Succeed())
+		})
+		mux.HandleFunc("/api/clusters_mgmt/v1/clusters/id-1", func(w http.ResponseWriter, r *http.Request) {
+			deleted = append(deleted, "id-1")
+			w.WriteHeader(http.StatusNoContent)
+		})
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Creates and deletes the cluster through OCM", func() {
+		cti := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterID: "0b7b1f0c-5c4e-4c55-9d67-2b9f1e6c7a10",
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					OCM: &v1alpha1.OCMCluster{
+						CredentialsSecret: "ocm-credentials",
+						URL:               server.URL,
+						TokenURL:          server.URL + "/token",
+						Product:           "osd",
+						Region:            "us-east-1",
+					},
+				},
+			},
+		}
+		credentials := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ocm-credentials",
+				Namespace: ArgoCDNamespace,
+			},
+			Data: map[string][]byte{
+				ocm.OfflineTokenKey:      []byte("offline"),
+				ocmAWSAccountIDKey:       []byte("123456789012"),
+				ocmAWSAccessKeyIDKey:     []byte("AKIA"),
+				ocmAWSSecretAccessKeyKey: []byte("secret"),
+			},
+		}
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme),
+		}
+		// the credentials Secret is missing
+		Expect(reconciler.createOCMCluster(context.TODO(), cti)).ShouldNot(Succeed())
+
+		reconciler.Client = fake.NewFakeClientWithScheme(scheme.Scheme, credentials)
+		Expect(reconciler.createOCMCluster(context.TODO(), cti)).Should(Succeed())
+		// created already
+		Expect(reconciler.createOCMCluster(context.TODO(), cti)).Should(Succeed())
+		Expect(created).Should(HaveLen(1))
+		Expect(created[0]["name"]).Should(Equal(cti.GetOCMClusterName()))
+		Expect(created[0]["display_name"]).Should(Equal("default-foo"))
+		Expect(created[0]["ccs"]).Should(Equal(map[string]interface{}{"enabled": true}))
+		Expect(created[0]["aws"]).Should(Equal(map[string]interface{}{
+			"account_id":        "123456789012",
+			"access_key_id":     "AKIA",
+			"secret_access_key": "secret",
+		}))
+
+		Expect(ocmClusterInstalling(cti)).Should(BeFalse())
+		cti.SetClusterDefinitionCreatedCondition(
+			metav1.ConditionTrue,
+			v1alpha1.OCMClusterCreated,
+			"OCM cluster created",
+		)
+		Expect(ocmClusterInstalling(cti)).Should(BeTrue())
+
+		Expect(reconciler.deleteOCMCluster(context.TODO(), cti)).Should(Succeed())
+		Expect(deleted).Should(Equal([]string{"id-1"}))
+		// uninstallation is in progress
+		clusters[0].State = ocm.ClusterStateUninstalling
+		Expect(reconciler.deleteOCMCluster(context.TODO(), cti)).Should(Succeed())
+		Expect(deleted).Should(HaveLen(1))
+	})
+})
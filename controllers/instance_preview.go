@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/chart"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/helm"
+)
+
+// key of the preview ConfigMap holding the rendered manifests
+const previewManifestsKey = "manifests.yaml"
+
+// isPreview returns true if the instance should be previewed instead of installed. Preview of
+// already created cluster definition is not possible.
+func isPreview(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) bool {
+	return clusterTemplateInstance.Spec.Preview && !meta.IsStatusConditionTrue(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.ClusterDefinitionCreated),
+	)
+}
+
+// reconcilePreview renders the cluster definition chart with the instance values into
+// the preview ConfigMap
+func (r *ClusterTemplateInstanceReconciler) reconcilePreview(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	if clusterTemplateInstance.Status.Phase == v1alpha1.PreviewPhase &&
+		clusterTemplateInstance.Status.ObservedGeneration == clusterTemplateInstance.Generation {
+		return nil
+	}
+
+	ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+	if ctSpec.PostRenderer != nil {
+		// the kustomization is applied by ArgoCD plugin, preview would show unpatched manifests
+		return fmt.Errorf("preview is not supported for templates with post renderer")
+	}
+	if !isHelmChartDefinition(ctSpec) {
+		return fmt.Errorf("preview is supported for Helm chart cluster definitions only")
+	}
+	helmChart, err := r.getClusterDefinitionChart(ctx, ctSpec)
+	if err != nil {
+		return err
+	}
+
+	// the preview ConfigMap is readable by users of the namespace, values of Secrets are left out
+	previewInstance := clusterTemplateInstance.DeepCopy()
+	previewInstance.Spec.ValuesFrom = nil
+	for _, ref := range clusterTemplateInstance.Spec.ValuesFrom {
+		if ref.Kind != v1alpha1.ValuesFromSecret {
+			previewInstance.Spec.ValuesFrom = append(previewInstance.Spec.ValuesFrom, ref)
+		}
+	}
+	if err := r.copyTrustedCABundle(ctx, clusterTemplateInstance); err != nil {
+		return err
+	}
+	valuesFrom, err := previewInstance.GetValuesFrom(ctx, r.Client, "")
+	if err != nil {
+		return err
+	}
+	values, err := clusterTemplateInstance.GetClusterDefinitionValues(valuesFrom)
+	if err != nil {
+		return err
+	}
+	releaseName, namespace := getClusterDefinitionRelease(clusterTemplateInstance)
+	manifests, err := helm.RenderChart(
+		helmChart,
+		releaseName,
+		namespace,
+		values,
+		clusterTemplateInstance.Status.ClusterTemplateSpec.SkipCRDs,
+	)
+	if err != nil {
+		return err
+	}
+
+	preview := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterTemplateInstance.GetPreviewRef(),
+			Namespace: clusterTemplateInstance.Namespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, preview, func() error {
+		preview.OwnerReferences = []metav1.OwnerReference{
+			clusterTemplateInstance.GetOwnerReference(),
+		}
+		preview.Data = map[string]string{previewManifestsKey: manifests}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	clusterTemplateInstance.Status.Preview = &corev1.LocalObjectReference{Name: preview.Name}
+	clusterTemplateInstance.Status.Phase = v1alpha1.PreviewPhase
+	clusterTemplateInstance.Status.Message = fmt.Sprintf(
+		"Manifests rendered to ConfigMap %s, set spec.preview to false to install the cluster",
+		preview.Name,
+	)
+	return nil
+}
+
+// isHelmChartDefinition returns true if the cluster definition of the template is a Helm chart
+func isHelmChartDefinition(ctSpec *v1alpha1.ClusterTemplateSpec) bool {
+	return ctSpec.EmbeddedChart != nil || ctSpec.HelmChartURL != "" ||
+		ctSpec.ClusterDefinition.Source.Chart != ""
+}
+
+// getClusterDefinitionChart fetches the cluster definition Helm chart of the template
+func (r *ClusterTemplateInstanceReconciler) getClusterDefinitionChart(
+	ctx context.Context,
+	ctSpec *v1alpha1.ClusterTemplateSpec,
+) (*chart.Chart, error) {
+	source := ctSpec.ClusterDefinition.Source
+	switch {
+	case ctSpec.EmbeddedChart != nil:
+		return r.HelmClient.GetEmbeddedChart(
+			ctx,
+			r.Client,
+			ctSpec.EmbeddedChart.Kind,
+			ctSpec.EmbeddedChart.Name,
+			ArgoCDNamespace,
+		)
+	case ctSpec.HelmChartURL != "":
+		helmChart, _, err := r.HelmClient.GetChartFromURL(
+			ctx,
+			r.Client,
+			ctSpec.HelmChartURL,
+			ArgoCDNamespace,
+			HelmCABundle,
+		)
+		return helmChart, err
+	case source.Chart != "":
+		helmChart, _, err := r.HelmClient.GetChartFromRepositories(
+			ctx,
+			r.Client,
+			ctSpec.GetRepositories(source.RepoURL),
+			source.Chart,
+			source.TargetRevision,
+			ArgoCDNamespace,
+			HelmCABundle,
+		)
+		return helmChart, err
+	}
+	return nil, fmt.Errorf("cluster definition is not a Helm chart")
+}
+
+// getClusterDefinitionRelease returns name and namespace of the cluster definition Helm release
+func getClusterDefinitionRelease(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) (string, string) {
+	source := clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterDefinition.Source
+	releaseName := clusterTemplateInstance.GetReleaseName()
+	if source.Helm != nil && source.Helm.ReleaseName != "" {
+		releaseName = source.Helm.ReleaseName
+	}
+	return releaseName, clusterTemplateInstance.GetClusterDefinitionNamespace()
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"context"
+	"net/http/httptest"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/helm"
+	helmserver "github.com/stolostron/cluster-templates-operator/testutils/helm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance preview", func() {
+	var server *httptest.Server
+	var cti *v1alpha1.ClusterTemplateInstance
+
+	BeforeEach(func() {
+		server = helmserver.StartHelmRepoServer()
+		cti = &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "foo",
+				Namespace:  "default",
+				Generation: 1,
+			},
+			Spec: v1alpha1.ClusterTemplateInstanceSpec{
+				Preview: true,
+				Parameters: []v1alpha1.Parameter{{
+					Name:  "ocpVersion",
+					Value: "4.12.0",
+				}},
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					ClusterDefinition: argo.ApplicationSpec{
+						Source: argo.ApplicationSource{
+							RepoURL:        server.URL,
+							Chart:          "hypershift-template",
+							TargetRevision: "0.0.2",
+						},
+						Destination: argo.ApplicationDestination{
+							Namespace: v1alpha1.CTIInstanceNamespaceVar,
+						},
+					},
+				},
+			},
+		}
+		SetDefaultConditions(cti)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Previews instance without cluster definition", func() {
+		Expect(isPreview(cti)).Should(BeTrue())
+		cti.SetClusterDefinitionCreatedCondition(
+			metav1.ConditionTrue,
+			v1alpha1.ApplicationCreated,
+			"Application created",
+		)
+		Expect(isPreview(cti)).Should(BeFalse())
+	})
+
+	It("Renders cluster definition to ConfigMap", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti)
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client:     k8sClient,
+			HelmClient: helm.NewHelmClient(cfg, k8sClient, nil, nil, nil),
+		}
+		Expect(reconciler.reconcilePreview(context.TODO(), cti)).Should(Succeed())
+		Expect(cti.Status.Phase).Should(Equal(v1alpha1.PreviewPhase))
+		Expect(cti.Status.Preview.Name).Should(Equal(cti.GetPreviewRef()))
+
+		preview := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: cti.GetPreviewRef(), Namespace: cti.Namespace},
+			preview,
+		)).Should(Succeed())
+		manifests := preview.Data[previewManifestsKey]
+		Expect(manifests).Should(ContainSubstring("kind: HostedCluster"))
+		Expect(manifests).Should(ContainSubstring("ocp-release:4.12.0-x86_64"))
+		Expect(manifests).Should(ContainSubstring("name: default-default-foo"))
+	})
+
+	It("Fails for cluster definition which is not a Helm chart", func() {
+		cti.Status.ClusterTemplateSpec.ClusterDefinition.Source = argo.ApplicationSource{
+			RepoURL: "https://github.com/foo/bar",
+			Path:    "cluster",
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		Expect(reconciler.reconcilePreview(context.TODO(), cti)).ShouldNot(Succeed())
+	})
+})
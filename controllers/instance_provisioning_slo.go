@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+var provisioningSLOViolations = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "clustertemplateinstance_provisioning_slo_violations_total",
+		Help: "Number of ClusterTemplateInstances which violated a provisioning SLO of their template",
+	},
+	[]string{"template", "slo"},
+)
+
+var provisioningDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name: "clustertemplateinstance_provisioning_duration_seconds",
+		Help: "Time from the creation of ClusterTemplateInstances until their cluster was installed " +
+			"(clusterInstall) or they became Ready (ready), for templates with provisioning SLOs",
+		Buckets: []float64{300, 600, 900, 1200, 1800, 2700, 3600, 5400, 7200, 10800, 14400},
+	},
+	[]string{"template", "slo"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(provisioningSLOViolations, provisioningDuration)
+}
+
+// provisioningSLO pairs a provisioning SLO of the template with the duration of the instance
+type provisioningSLO struct {
+	sloType  v1alpha1.ProvisioningSLOType
+	name     string
+	expected *metav1.Duration
+	actual   **metav1.Duration
+	// condition which becomes True once the provisioning step is done
+	doneCondition v1alpha1.ConditionType
+}
+
+func getProvisioningSLOs(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) []provisioningSLO {
+	slo := clusterTemplateInstance.Status.ClusterTemplateSpec.ProvisioningSLO
+	status := clusterTemplateInstance.Status.ProvisioningSLO
+	return []provisioningSLO{
+		{
+			sloType:       v1alpha1.ClusterInstallSLO,
+			name:          "Cluster install",
+			expected:      slo.ClusterInstall,
+			actual:        &status.ClusterInstallDuration,
+			doneCondition: v1alpha1.ClusterInstallSucceeded,
+		},
+		{
+			sloType:       v1alpha1.ReadySLO,
+			name:          "Ready",
+			expected:      slo.Ready,
+			actual:        &status.ReadyDuration,
+			doneCondition: v1alpha1.Ready,
+		},
+	}
+}
+
+// reconcileProvisioningSLO records how long the provisioning of the instance took and compares it
+// with the provisioning SLOs of the template. Violations are reported by the ProvisioningSLOMet
+// condition and counted by the provisioningSLOViolations metric once per instance. Durations are
+// recorded only once, so later changes of the instance (ie upgrades) do not affect them.
+func reconcileProvisioningSLO(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) {
+	if clusterTemplateInstance.Status.ClusterTemplateSpec.ProvisioningSLO == nil {
+		return
+	}
+	if clusterTemplateInstance.Status.ProvisioningSLO == nil {
+		clusterTemplateInstance.Status.ProvisioningSLO = &v1alpha1.ProvisioningSLOStatus{}
+	}
+	status := clusterTemplateInstance.Status.ProvisioningSLO
+	template := clusterTemplateInstance.Spec.ClusterTemplateRef
+	created := clusterTemplateInstance.CreationTimestamp.Time
+
+	pending := false
+	violations := []string{}
+	for _, slo := range getProvisioningSLOs(clusterTemplateInstance) {
+		if *slo.actual == nil {
+			condition := meta.FindStatusCondition(
+				clusterTemplateInstance.Status.Conditions,
+				string(slo.doneCondition),
+			)
+			if condition != nil && condition.Status == metav1.ConditionTrue {
+				*slo.actual = &metav1.Duration{
+					Duration: condition.LastTransitionTime.Sub(created).Round(time.Second),
+				}
+				provisioningDuration.WithLabelValues(template, string(slo.sloType)).
+					Observe((*slo.actual).Seconds())
+			}
+		}
+		if slo.expected == nil {
+			continue
+		}
+
+		elapsed := time.Since(created)
+		if *slo.actual != nil {
+			elapsed = (*slo.actual).Duration
+		} else {
+			pending = true
+		}
+		if elapsed <= slo.expected.Duration {
+			continue
+		}
+		if !isSLOViolated(status, slo.sloType) {
+			status.ViolatedSLOs = append(status.ViolatedSLOs, slo.sloType)
+			provisioningSLOViolations.WithLabelValues(template, string(slo.sloType)).Inc()
+		}
+		if *slo.actual != nil {
+			violations = append(violations, fmt.Sprintf(
+				"%s took %s, expected %s",
+				slo.name,
+				(*slo.actual).Duration,
+				slo.expected.Duration,
+			))
+		} else {
+			violations = append(violations, fmt.Sprintf(
+				"%s not reached within %s",
+				slo.name,
+				slo.expected.Duration,
+			))
+		}
+	}
+
+	switch {
+	case len(violations) > 0:
+		clusterTemplateInstance.SetProvisioningSLOMetCondition(
+			metav1.ConditionFalse,
+			v1alpha1.ProvisioningSLOViolated,
+			strings.Join(violations, ", "),
+		)
+	case pending:
+		clusterTemplateInstance.SetProvisioningSLOMetCondition(
+			metav1.ConditionUnknown,
+			v1alpha1.ProvisioningWithinSLO,
+			"Provisioning is within the SLOs of the template",
+		)
+	default:
+		clusterTemplateInstance.SetProvisioningSLOMetCondition(
+			metav1.ConditionTrue,
+			v1alpha1.ProvisionedWithinSLO,
+			"Provisioned within the SLOs of the template",
+		)
+	}
+}
+
+func isSLOViolated(
+	status *v1alpha1.ProvisioningSLOStatus,
+	sloType v1alpha1.ProvisioningSLOType,
+) bool {
+	for _, violated := range status.ViolatedSLOs {
+		if violated == sloType {
+			return true
+		}
+	}
+	return false
+}
+
+// provisioningSLOCheckAfter returns how long it takes until the next provisioning SLO of
+// the instance elapses, zero is returned if none is pending
+func provisioningSLOCheckAfter(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) time.Duration {
+	ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+	if ctSpec == nil || ctSpec.ProvisioningSLO == nil ||
+		clusterTemplateInstance.Status.ProvisioningSLO == nil {
+		return 0
+	}
+	var after time.Duration
+	for _, slo := range getProvisioningSLOs(clusterTemplateInstance) {
+		if slo.expected == nil || *slo.actual != nil {
+			continue
+		}
+		deadline := clusterTemplateInstance.CreationTimestamp.Add(slo.expected.Duration)
+		// checked just after the SLO elapsed
+		remaining := time.Until(deadline) + time.Second
+		if remaining > time.Second && (after == 0 || remaining < after) {
+			after = remaining
+		}
+	}
+	return after
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Instance provisioning SLO", func() {
+	newInstance := func(template string, age time.Duration) *v1alpha1.ClusterTemplateInstance {
+		return &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "foo",
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			},
+			Spec: v1alpha1.ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: template,
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					ProvisioningSLO: &v1alpha1.ProvisioningSLO{
+						ClusterInstall: &metav1.Duration{Duration: 45 * time.Minute},
+						Ready:          &metav1.Duration{Duration: time.Hour},
+					},
+				},
+			},
+		}
+	}
+	setDone := func(
+		cti *v1alpha1.ClusterTemplateInstance,
+		conditionType v1alpha1.ConditionType,
+		after time.Duration,
+	) {
+		meta.SetStatusCondition(&cti.Status.Conditions, metav1.Condition{
+			Type:               string(conditionType),
+			Status:             metav1.ConditionTrue,
+			Reason:             "Done",
+			LastTransitionTime: metav1.NewTime(cti.CreationTimestamp.Add(after)),
+		})
+	}
+	violations := func(template string, slo v1alpha1.ProvisioningSLOType) float64 {
+		return testutil.ToFloat64(
+			provisioningSLOViolations.WithLabelValues(template, string(slo)),
+		)
+	}
+
+	It("Reports instances provisioned within the SLOs", func() {
+		cti := newInstance("slo-met", 10*time.Minute)
+		reconcileProvisioningSLO(cti)
+		condition := meta.FindStatusCondition(
+			cti.Status.Conditions,
+			string(v1alpha1.ProvisioningSLOMet),
+		)
+		Expect(condition.Status).Should(Equal(metav1.ConditionUnknown))
+		Expect(condition.Reason).Should(Equal(string(v1alpha1.ProvisioningWithinSLO)))
+		Expect(provisioningSLOCheckAfter(cti)).Should(
+			BeNumerically("~", 35*time.Minute, time.Minute),
+		)
+
+		setDone(cti, v1alpha1.ClusterInstallSucceeded, 30*time.Minute)
+		setDone(cti, v1alpha1.Ready, 40*time.Minute)
+		reconcileProvisioningSLO(cti)
+		Expect(cti.Status.ProvisioningSLO).Should(Equal(&v1alpha1.ProvisioningSLOStatus{
+			ClusterInstallDuration: &metav1.Duration{Duration: 30 * time.Minute},
+			ReadyDuration:          &metav1.Duration{Duration: 40 * time.Minute},
+		}))
+		condition = meta.FindStatusCondition(
+			cti.Status.Conditions,
+			string(v1alpha1.ProvisioningSLOMet),
+		)
+		Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).Should(Equal(string(v1alpha1.ProvisionedWithinSLO)))
+		Expect(provisioningSLOCheckAfter(cti)).Should(BeZero())
+		Expect(violations("slo-met", v1alpha1.ClusterInstallSLO)).Should(BeZero())
+	})
+
+	It("Reports SLO violations once", func() {
+		cti := newInstance("slo-violated", 50*time.Minute)
+		reconcileProvisioningSLO(cti)
+		reconcileProvisioningSLO(cti)
+		condition := meta.FindStatusCondition(
+			cti.Status.Conditions,
+			string(v1alpha1.ProvisioningSLOMet),
+		)
+		Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).Should(Equal("Cluster install not reached within 45m0s"))
+		Expect(violations("slo-violated", v1alpha1.ClusterInstallSLO)).Should(Equal(1.0))
+		// ready SLO elapses later
+		Expect(provisioningSLOCheckAfter(cti)).Should(
+			BeNumerically("~", 10*time.Minute, time.Minute),
+		)
+
+		setDone(cti, v1alpha1.ClusterInstallSucceeded, 52*time.Minute)
+		setDone(cti, v1alpha1.Ready, 55*time.Minute)
+		reconcileProvisioningSLO(cti)
+		Expect(cti.Status.ProvisioningSLO.ViolatedSLOs).Should(Equal(
+			[]v1alpha1.ProvisioningSLOType{v1alpha1.ClusterInstallSLO},
+		))
+		condition = meta.FindStatusCondition(
+			cti.Status.Conditions,
+			string(v1alpha1.ProvisioningSLOMet),
+		)
+		Expect(condition.Message).Should(Equal("Cluster install took 52m0s, expected 45m0s"))
+		Expect(violations("slo-violated", v1alpha1.ClusterInstallSLO)).Should(Equal(1.0))
+		Expect(violations("slo-violated", v1alpha1.ReadySLO)).Should(BeZero())
+	})
+
+	It("Ignores templates without provisioning SLOs", func() {
+		cti := newInstance("no-slo", time.Hour)
+		cti.Status.ClusterTemplateSpec.ProvisioningSLO = nil
+		reconcileProvisioningSLO(cti)
+		Expect(cti.Status.ProvisioningSLO).Should(BeNil())
+		Expect(cti.Status.Conditions).Should(BeEmpty())
+		Expect(provisioningSLOCheckAfter(cti)).Should(BeZero())
+	})
+})
@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// reattachRestoredInstance adopts the resources of an instance restored from a backup of the hub.
+// Backup tools (ie OADP) restore the instance without its status and with a new UID, while
+// the ArgoCD Applications, the cluster resources and the cluster credentials are restored as they
+// were. Instead of installing the cluster again, the instance is re-attached to the restored
+// application once the installed chart and the cluster resources are verified. Does nothing for
+// new instances, whose application does not exist yet.
+func (r *ClusterTemplateInstanceReconciler) reattachRestoredInstance(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	ctSpec *v1alpha1.ClusterTemplateSpec,
+) error {
+	restored := clusterTemplateInstance.DeepCopy()
+	restored.Status.ClusterTemplateSpec = ctSpec
+	app, err := restored.GetDay1Application(ctx, r.Client, ArgoCDNamespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if app.DeletionTimestamp != nil {
+		return fmt.Errorf("restored application %s is being deleted", app.Name)
+	}
+	// the restored instance has a new UID, the cluster keeps its ID (applications created before
+	// the ID was introduced have none)
+	clusterTemplateInstance.Status.ClusterID = app.Labels[v1alpha1.CTIClusterIDLabel]
+	restored.Status.ClusterID = clusterTemplateInstance.Status.ClusterID
+	// the generated cluster name is kept too, the cluster would be re-created otherwise
+	if clusterName := app.Labels[v1alpha1.CTIClusterNameLabel]; clusterName != "" {
+		clusterTemplateInstance.Status.ClusterName = clusterName
+		restored.Status.ClusterName = clusterName
+	}
+	if err := pinInstalledChart(ctSpec, app); err != nil {
+		return err
+	}
+	if err := r.adoptRestoredResources(ctx, restored); err != nil {
+		return fmt.Errorf("failed to adopt restored cluster credentials - %q", err)
+	}
+
+	// the status of the application is restored too, unless ArgoCD did not refresh it yet
+	provider, err := r.getClusterProvider(ctx, restored, app)
+	if err != nil {
+		return err
+	}
+	ready, status := false, ""
+	if provider != nil {
+		ready, status, err = provider.GetClusterStatus(ctx, r.Client, *restored)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to verify cluster resources of restored application %s - %q",
+				app.Name,
+				err,
+			)
+		}
+	}
+
+	CTIlog.Info(
+		"Re-attaching instance to restored application",
+		"name",
+		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		"application",
+		app.Name,
+	)
+	msg := fmt.Sprintf("Application %s restored from backup re-attached", app.Name)
+	clusterTemplateInstance.SetClusterDefinitionCreatedCondition(
+		metav1.ConditionTrue,
+		v1alpha1.ApplicationReattached,
+		msg,
+	)
+	// installed clusters are never rolled back nor timed out
+	if ready {
+		clusterTemplateInstance.SetClusterInstallCondition(
+			metav1.ConditionTrue,
+			v1alpha1.ClusterInstalled,
+			status,
+		)
+	}
+	if r.Recorder != nil {
+		r.Recorder.Event(
+			clusterTemplateInstance,
+			corev1.EventTypeNormal,
+			string(v1alpha1.ApplicationReattached),
+			msg,
+		)
+	}
+	return nil
+}
+
+// pinInstalledChart sets the version of the cluster definition chart to the version installed by
+// the restored application, the template may have moved to a newer version since the backup.
+// Application installing a different chart than the template is not re-attached.
+func pinInstalledChart(ctSpec *v1alpha1.ClusterTemplateSpec, app *argo.Application) error {
+	source := &ctSpec.ClusterDefinition.Source
+	if source.Chart == "" {
+		return nil
+	}
+	chart, version := getInstalledChart(app)
+	if chart != source.Chart {
+		return fmt.Errorf(
+			"restored application %s installs chart %s, the template uses chart %s",
+			app.Name,
+			chart,
+			source.Chart,
+		)
+	}
+	source.TargetRevision = version
+	return nil
+}
+
+// getInstalledChart returns name and version of the chart installed by the application. Charts
+// of applications with post renderer are passed to the plugin in its environment.
+func getInstalledChart(app *argo.Application) (string, string) {
+	source := app.Spec.Source
+	if source.Plugin == nil || source.Plugin.Name != v1alpha1.PostRendererPluginName {
+		return source.Chart, source.TargetRevision
+	}
+	chart, version := "", ""
+	for _, env := range source.Plugin.Env {
+		switch env.Name {
+		case v1alpha1.PostRendererChartEnv:
+			chart = env.Value
+		case v1alpha1.PostRendererVersionEnv:
+			version = env.Value
+		}
+	}
+	return chart, version
+}
+
+// adoptRestoredResources points the owner references of the cluster credentials to the restored
+// instance. The references keep the UID of the instance which was backed up, the garbage
+// collector would delete the credentials once it finds out such owner does not exist.
+func (r *ClusterTemplateInstanceReconciler) adoptRestoredResources(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	owner := clusterTemplateInstance.GetOwnerReference()
+	resources := map[string]client.Object{
+		clusterTemplateInstance.GetKubeconfigRef():    &corev1.Secret{},
+		clusterTemplateInstance.GetKubeadminPassRef(): &corev1.Secret{},
+		clusterTemplateInstance.GetAccessLogRef():     &corev1.ConfigMap{},
+	}
+	for name, obj := range resources {
+		key := client.ObjectKey{Name: name, Namespace: clusterTemplateInstance.Namespace}
+		if err := r.Client.Get(ctx, key, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if !replaceOwnerUID(obj, owner) {
+			continue
+		}
+		if err := r.Client.Update(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaceOwnerUID updates UID of the owner references to the owner of the same kind and name,
+// returns true if any reference changed
+func replaceOwnerUID(obj metav1.Object, owner metav1.OwnerReference) bool {
+	refs := obj.GetOwnerReferences()
+	changed := false
+	for i := range refs {
+		if refs[i].Kind == owner.Kind && refs[i].Name == owner.Name && refs[i].UID != owner.UID {
+			refs[i].UID = owner.UID
+			changed = true
+		}
+	}
+	obj.SetOwnerReferences(refs)
+	return changed
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Restored instances", func() {
+	var cti *v1alpha1.ClusterTemplateInstance
+	var ctSpec *v1alpha1.ClusterTemplateSpec
+	var app *argo.Application
+
+	BeforeEach(func() {
+		cti = &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+				UID:       "restored",
+			},
+		}
+		SetDefaultConditions(cti)
+		ctSpec = &v1alpha1.ClusterTemplateSpec{
+			ClusterDefinition: argo.ApplicationSpec{
+				Source: argo.ApplicationSource{
+					RepoURL:        "https://charts.example.com",
+					Chart:          "hypershift-template",
+					TargetRevision: "0.0.3",
+				},
+			},
+		}
+		app = &argo.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetDay1ApplicationName(),
+				Namespace: ArgoCDNamespace,
+				Labels: map[string]string{
+					v1alpha1.CTINameLabel:      cti.Name,
+					v1alpha1.CTINamespaceLabel: cti.Namespace,
+				},
+			},
+			Spec: argo.ApplicationSpec{
+				Source: argo.ApplicationSource{
+					RepoURL:        "https://charts.example.com",
+					Chart:          "hypershift-template",
+					TargetRevision: "0.0.2",
+				},
+			},
+		}
+	})
+
+	It("Ignores new instances", func() {
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme),
+		}
+		Expect(reconciler.reattachRestoredInstance(context.TODO(), cti, ctSpec)).Should(Succeed())
+		Expect(ctSpec.ClusterDefinition.Source.TargetRevision).Should(Equal("0.0.3"))
+		Expect(cti.Status.ClusterID).Should(BeEmpty())
+		Expect(meta.IsStatusConditionTrue(
+			cti.Status.Conditions,
+			string(v1alpha1.ClusterDefinitionCreated),
+		)).Should(BeFalse())
+	})
+
+	It("Re-attaches restored application", func() {
+		kubeconfig := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetKubeconfigRef(),
+				Namespace: cti.Namespace,
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: v1alpha1.APIVersion,
+						Kind:       "ClusterTemplateInstance",
+						Name:       cti.Name,
+						UID:        "backed-up",
+					},
+				},
+			},
+		}
+		app.Labels[v1alpha1.CTIClusterIDLabel] = "backed-up"
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, app, kubeconfig)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		Expect(reconciler.reattachRestoredInstance(context.TODO(), cti, ctSpec)).Should(Succeed())
+
+		Expect(ctSpec.ClusterDefinition.Source.TargetRevision).Should(Equal("0.0.2"))
+		condition := meta.FindStatusCondition(
+			cti.Status.Conditions,
+			string(v1alpha1.ClusterDefinitionCreated),
+		)
+		Expect(condition.Status).Should(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).Should(Equal(string(v1alpha1.ApplicationReattached)))
+		Expect(cti.Status.ClusterID).Should(Equal("backed-up"))
+
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKeyFromObject(kubeconfig),
+			kubeconfig,
+		)).Should(Succeed())
+		Expect(kubeconfig.OwnerReferences[0].UID).Should(BeEquivalentTo("restored"))
+	})
+
+	It("Does not re-attach application of another chart", func() {
+		app.Spec.Source.Chart = "hive-template"
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme, app),
+		}
+		Expect(reconciler.reattachRestoredInstance(context.TODO(), cti, ctSpec)).Should(MatchError(
+			"restored application " + app.Name +
+				" installs chart hive-template, the template uses chart hypershift-template",
+		))
+	})
+
+	It("Verifies cluster resources of restored application", func() {
+		app.Status.Resources = []argo.ResourceStatus{
+			{
+				Group:     "hypershift.openshift.io",
+				Version:   "v1alpha1",
+				Kind:      "HostedCluster",
+				Name:      "foo",
+				Namespace: "clusters",
+			},
+		}
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme, app),
+		}
+		err := reconciler.reattachRestoredInstance(context.TODO(), cti, ctSpec)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("failed to verify cluster resources"))
+	})
+})
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
+)
+
+const replicasPointer = "/spec/replicas"
+
+// reconcileNodePoolScaling scales the NodePools of an installed hypershift cluster to the
+// replicas requested by spec.nodePoolReplicas and autoscales them within the bounds requested by
+// spec.nodePoolAutoscaling
+func (r *ClusterTemplateInstanceReconciler) reconcileNodePoolScaling(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	replicas := clusterTemplateInstance.Spec.NodePoolReplicas
+	autoscaling := clusterTemplateInstance.Spec.NodePoolAutoscaling
+	// hibernated node pools are scaled to zero
+	if (len(replicas) == 0 && len(autoscaling) == 0) || clusterTemplateInstance.Spec.Hibernate ||
+		!isClusterInstalled(clusterTemplateInstance) {
+		return nil
+	}
+	// clusters created through OCM or claimed from a pool have no cluster definition application
+	if ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec; ctSpec.OCM != nil ||
+		ctSpec.ClusterPool != nil {
+		return fmt.Errorf("scaling of node pools is supported for hypershift clusters only")
+	}
+
+	app, err := clusterTemplateInstance.GetDay1Application(ctx, r.Client, ArgoCDNamespace)
+	if err != nil {
+		return err
+	}
+	clusterProvider, err := r.getClusterProvider(ctx, clusterTemplateInstance, app)
+	if err != nil {
+		return err
+	}
+	scaler, ok := clusterProvider.(clusterprovider.NodePoolScaler)
+	if !ok {
+		return fmt.Errorf("scaling of node pools is supported for hypershift clusters only")
+	}
+
+	// the replicas and autoscaling rendered by the chart would revert the scaling
+	for _, pointer := range []string{replicasPointer, autoScalingPointer} {
+		if err := ignoreHypershiftDifferences(ctx, r.Client, app, pointer, "NodePool"); err != nil {
+			return err
+		}
+	}
+	if len(autoscaling) > 0 {
+		autoscaler, ok := clusterProvider.(clusterprovider.NodePoolAutoscaler)
+		if !ok {
+			return fmt.Errorf("autoscaling of node pools is supported for hypershift clusters only")
+		}
+		if err := autoscaler.AutoscaleNodePools(ctx, r.Client, autoscaling); err != nil {
+			return err
+		}
+	}
+	if len(replicas) == 0 {
+		return nil
+	}
+	return scaler.ScaleNodePools(ctx, r.Client, replicas)
+}
@@ -0,0 +1,304 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/cluster-templates-operator/aap"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+)
+
+// extra variables of Ansible jobs set by the operator
+const (
+	ansibleKubeconfigVar        = "cluster_kubeconfig"
+	ansibleInstanceNameVar      = "instance_name"
+	ansibleInstanceNamespaceVar = "instance_namespace"
+	ansibleLaunchIDVar          = "claas_launch_id"
+)
+
+// getAAPClient returns client of AAP authenticated by the credentials Secret of the Ansible job.
+// The Secrets are created by the hub admin in the operator config namespace, together with the
+// URL of the automation controller the token is meant for, so a template can not send the token
+// elsewhere.
+func (r *ClusterTemplateInstanceReconciler) getAAPClient(
+	ctx context.Context,
+	ansibleJob *v1alpha1.SetupAnsibleJob,
+) (*aap.Client, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{Name: ansibleJob.CredentialsSecret, Namespace: configNamespace},
+		secret,
+	); err != nil {
+		return nil, fmt.Errorf(
+			"failed to get AAP credentials Secret %s - %q",
+			ansibleJob.CredentialsSecret,
+			err,
+		)
+	}
+	aapClient, err := aap.NewClient(ansibleJob.URL, secret.Data)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"invalid AAP credentials Secret %s - %w",
+			ansibleJob.CredentialsSecret,
+			err,
+		)
+	}
+	return aapClient, nil
+}
+
+// launchSetupAnsibleJobs launches jobs of cluster setups run by Ansible which were not launched
+// yet. IDs of the launched jobs are kept in the status of the instance, a job launched before the
+// status could not be updated is found in AAP by its launch ID.
+func (r *ClusterTemplateInstanceReconciler) launchSetupAnsibleJobs(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	for _, setup := range clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterSetup {
+		if setup.AnsibleJob == nil || getAnsibleJobID(clusterTemplateInstance, setup.Name) != 0 ||
+			len(clusterTemplateInstance.GetPendingSetupDependencies(setup)) > 0 {
+			continue
+		}
+		id, err := r.launchSetupAnsibleJob(ctx, clusterTemplateInstance, setup, 0)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to launch Ansible job of cluster setup %s - %w",
+				setup.Name,
+				err,
+			)
+		}
+		setSetupStatus(clusterTemplateInstance, v1alpha1.ClusterSetupStatus{
+			Name:         setup.Name,
+			Status:       argocd.ApplicationSyncRunning,
+			Message:      "Ansible job launched",
+			AnsibleJobID: id,
+		})
+	}
+	return nil
+}
+
+// launchSetupAnsibleJob launches the job template of the cluster setup and returns ID of the job.
+// If the job of the same launch (retry) was launched already, its ID is returned instead.
+func (r *ClusterTemplateInstanceReconciler) launchSetupAnsibleJob(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setup v1alpha1.ClusterSetup,
+	retry int,
+) (int, error) {
+	aapClient, err := r.getAAPClient(ctx, setup.AnsibleJob)
+	if err != nil {
+		return 0, err
+	}
+	launchID := getAnsibleLaunchID(clusterTemplateInstance, setup.Name, retry)
+	job, err := aapClient.FindJob(ctx, setup.AnsibleJob.JobTemplateID, launchID)
+	if err != nil {
+		return 0, err
+	}
+	if job != nil {
+		return job.ID, nil
+	}
+
+	extraVars, err := chartutil.ReadValues([]byte(setup.AnsibleJob.ExtraVars))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse extra variables - %q", err)
+	}
+	kubeconfigSecret := clusterTemplateInstance.GetKubeconfigRef()
+	if setup.Identity != nil {
+		kubeconfigSecret = clusterTemplateInstance.GetSetupCredentialsRef(setup.Name)
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{Name: kubeconfigSecret, Namespace: clusterTemplateInstance.Namespace},
+		secret,
+	); err != nil {
+		return 0, err
+	}
+	extraVars[ansibleKubeconfigVar] = string(secret.Data["kubeconfig"])
+	extraVars[ansibleInstanceNameVar] = clusterTemplateInstance.Name
+	extraVars[ansibleInstanceNamespaceVar] = clusterTemplateInstance.Namespace
+	extraVars[ansibleLaunchIDVar] = launchID
+	return aapClient.LaunchJobTemplate(ctx, setup.AnsibleJob.JobTemplateID, extraVars)
+}
+
+// getSetupAnsibleJobStatuses returns status of cluster setups run by Ansible jobs. Finished jobs
+// are not read from AAP again.
+func (r *ClusterTemplateInstanceReconciler) getSetupAnsibleJobStatuses(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) ([]v1alpha1.ClusterSetupStatus, error) {
+	statuses := []v1alpha1.ClusterSetupStatus{}
+	for _, setup := range clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterSetup {
+		if setup.AnsibleJob == nil {
+			continue
+		}
+		setupStatus := v1alpha1.ClusterSetupStatus{Name: setup.Name}
+		previous := getSetupStatus(clusterTemplateInstance, setup.Name)
+		if previous != nil {
+			setupStatus.AnsibleJobID = previous.AnsibleJobID
+			setupStatus.Retries = previous.Retries
+		}
+		if setupStatus.AnsibleJobID == 0 {
+			pending := clusterTemplateInstance.GetPendingSetupDependencies(setup)
+			if len(pending) > 0 {
+				statuses = append(statuses, getWaitingSetupStatus(setup.Name, pending))
+				continue
+			}
+			setupStatus.Status = argocd.ApplicationError
+			setupStatus.Message = "Ansible job was not launched"
+			statuses = append(statuses, setupStatus)
+			continue
+		}
+		if previous.Status == argocd.ApplicationHealthy ||
+			previous.Status == argocd.ApplicationSyncFailed {
+			statuses = append(statuses, *previous)
+			continue
+		}
+		aapClient, err := r.getAAPClient(ctx, setup.AnsibleJob)
+		if err != nil {
+			return nil, err
+		}
+		job, err := aapClient.GetJob(ctx, setupStatus.AnsibleJobID)
+		if err != nil {
+			if !errors.Is(err, aap.ErrNotFound) {
+				return nil, err
+			}
+			setupStatus.Status = argocd.ApplicationError
+			setupStatus.Message = "Ansible job was deleted"
+		} else {
+			setupStatus.Status, setupStatus.Message = getAnsibleJobStatus(job)
+			if setupStatus.Status == argocd.ApplicationSyncFailed {
+				if err := r.retrySetupAnsibleJob(
+					ctx,
+					clusterTemplateInstance,
+					setup,
+					job,
+					&setupStatus,
+				); err != nil {
+					return nil, err
+				}
+			}
+		}
+		statuses = append(statuses, setupStatus)
+	}
+	return statuses, nil
+}
+
+// retrySetupAnsibleJob launches the job template of the failed cluster setup again once the retry
+// backoff elapses, the setup waiting for the retry is reported as running
+func (r *ClusterTemplateInstanceReconciler) retrySetupAnsibleJob(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setup v1alpha1.ClusterSetup,
+	job *aap.Job,
+	setupStatus *v1alpha1.ClusterSetupStatus,
+) error {
+	ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+	failedAt := time.Time{}
+	if job.FinishedAt != nil {
+		failedAt = *job.FinishedAt
+	}
+	delay, retry := getSetupRetryDelay(clusterTemplateInstance, setupStatus.Retries, failedAt)
+	if !retry || delay > 0 {
+		setRetryMessage(ctSpec, setupStatus, delay, retry)
+		return nil
+	}
+	id, err := r.launchSetupAnsibleJob(
+		ctx,
+		clusterTemplateInstance,
+		setup,
+		setupStatus.Retries+1,
+	)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to launch Ansible job of cluster setup %s - %w",
+			setup.Name,
+			err,
+		)
+	}
+	setupStatus.Retries++
+	setupStatus.AnsibleJobID = id
+	setupStatus.Status = argocd.ApplicationSyncRunning
+	setupStatus.Message = fmt.Sprintf(
+		"Retrying failed Ansible job (retry %d of %d)",
+		setupStatus.Retries,
+		ctSpec.SetupRetryLimit,
+	)
+	return nil
+}
+
+// getAnsibleJobStatus returns status of the cluster setup run by the Ansible job, reported like
+// statuses of ArgoCD Applications
+func getAnsibleJobStatus(job *aap.Job) (argocd.ApplicationStatus, string) {
+	switch job.Status {
+	case aap.JobStatusSuccessful:
+		return argocd.ApplicationHealthy, "Ansible job succeeded"
+	case aap.JobStatusFailed, aap.JobStatusError, aap.JobStatusCanceled:
+		msg := "Ansible job " + job.Status
+		if job.JobExplanation != "" {
+			msg += " - " + job.JobExplanation
+		}
+		return argocd.ApplicationSyncFailed, msg
+	}
+	return argocd.ApplicationSyncRunning, "Ansible job is " + job.Status
+}
+
+// getAnsibleLaunchID returns ID of the launch (retry) of the Ansible job of the cluster setup,
+// unique across instances
+func getAnsibleLaunchID(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setupName string,
+	retry int,
+) string {
+	return fmt.Sprintf("%s-%s-%d", clusterTemplateInstance.UID, setupName, retry)
+}
+
+// getAnsibleJobID returns ID of the Ansible job of the cluster setup, 0 if it was not launched
+func getAnsibleJobID(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setupName string,
+) int {
+	if setupStatus := getSetupStatus(clusterTemplateInstance, setupName); setupStatus != nil {
+		return setupStatus.AnsibleJobID
+	}
+	return 0
+}
+
+func getSetupStatus(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setupName string,
+) *v1alpha1.ClusterSetupStatus {
+	if clusterTemplateInstance.Status.ClusterSetup == nil {
+		return nil
+	}
+	for i, setupStatus := range *clusterTemplateInstance.Status.ClusterSetup {
+		if setupStatus.Name == setupName {
+			return &(*clusterTemplateInstance.Status.ClusterSetup)[i]
+		}
+	}
+	return nil
+}
+
+// setSetupStatus replaces status of the cluster setup, or adds it if the setup has no status yet
+func setSetupStatus(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setupStatus v1alpha1.ClusterSetupStatus,
+) {
+	if previous := getSetupStatus(clusterTemplateInstance, setupStatus.Name); previous != nil {
+		*previous = setupStatus
+		return
+	}
+	statuses := []v1alpha1.ClusterSetupStatus{}
+	if clusterTemplateInstance.Status.ClusterSetup != nil {
+		statuses = *clusterTemplateInstance.Status.ClusterSetup
+	}
+	statuses = append(statuses, setupStatus)
+	clusterTemplateInstance.Status.ClusterSetup = &statuses
+}
@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Cluster setup Ansible jobs", func() {
+	var server *httptest.Server
+	var extraVars map[string]interface{}
+	var launches int
+	var jobStatus string
+
+	BeforeEach(func() {
+		extraVars = nil
+		launches = 0
+		jobStatus = "running"
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/v2/job_templates/7/launch/", func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Authorization")).Should(Equal("Bearer token"))
+			body := struct {
+				ExtraVars map[string]interface{} `json:"extra_vars"`
+			}{}
+			Expect(json.NewDecoder(r.Body).Decode(&body)).Should(Succeed())
+			extraVars = body.ExtraVars
+			launches++
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"job":42}`))
+		})
+		mux.HandleFunc("/api/v2/job_templates/7/jobs/", func(w http.ResponseWriter, r *http.Request) {
+			// AAP finds the launched job by the launch ID in its extra variables
+			launchID, _ := extraVars["claas_launch_id"].(string)
+			if launches == 0 || r.URL.Query().Get("extra_vars__contains") != launchID {
+				_, _ = w.Write([]byte(`{"results":[]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"results":[{"id":42,"status":"` + jobStatus + `"}]}`))
+		})
+		mux.HandleFunc("/api/v2/jobs/42/", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"id":42,"status":"` + jobStatus + `"}`))
+		})
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Launches Ansible jobs of cluster setups and reports their status", func() {
+		cti := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+				UID:       "foo-uid",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					ClusterSetup: []v1alpha1.ClusterSetup{
+						{Name: "day2"},
+						{
+							Name: "register",
+							AnsibleJob: &v1alpha1.SetupAnsibleJob{
+								URL:               server.URL,
+								CredentialsSecret: "aap-credentials",
+								JobTemplateID:     7,
+								ExtraVars:         "inventory: prod\n",
+							},
+						},
+					},
+				},
+			},
+		}
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "aap-credentials",
+						Namespace: configNamespace,
+					},
+					Data: map[string][]byte{
+						"token": []byte("token"),
+						"url":   []byte(server.URL),
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      cti.GetKubeconfigRef(),
+						Namespace: cti.Namespace,
+					},
+					Data: map[string][]byte{"kubeconfig": []byte("kubeconfig")},
+				},
+			),
+		}
+
+		statuses, err := reconciler.getSetupAnsibleJobStatuses(context.TODO(), cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(statuses[0].Status).Should(Equal(argocd.ApplicationError))
+
+		Expect(reconciler.launchSetupAnsibleJobs(context.TODO(), cti)).Should(Succeed())
+		// launched jobs are not launched again
+		Expect(reconciler.launchSetupAnsibleJobs(context.TODO(), cti)).Should(Succeed())
+		Expect(launches).Should(Equal(1))
+		Expect(extraVars).Should(Equal(map[string]interface{}{
+			"inventory":          "prod",
+			"cluster_kubeconfig": "kubeconfig",
+			"instance_name":      "foo",
+			"instance_namespace": "default",
+			"claas_launch_id":    "foo-uid-register-0",
+		}))
+		Expect(*cti.Status.ClusterSetup).Should(HaveLen(1))
+		Expect((*cti.Status.ClusterSetup)[0].AnsibleJobID).Should(Equal(42))
+		Expect(setupJobsRunning(cti)).Should(BeTrue())
+
+		// the job launched before the status could not be updated is not launched again
+		cti.Status.ClusterSetup = nil
+		Expect(reconciler.launchSetupAnsibleJobs(context.TODO(), cti)).Should(Succeed())
+		Expect(launches).Should(Equal(1))
+		Expect((*cti.Status.ClusterSetup)[0].AnsibleJobID).Should(Equal(42))
+
+		statuses, err = reconciler.getSetupAnsibleJobStatuses(context.TODO(), cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(statuses).Should(Equal([]v1alpha1.ClusterSetupStatus{
+			{
+				Name:         "register",
+				Status:       argocd.ApplicationSyncRunning,
+				Message:      "Ansible job is running",
+				AnsibleJobID: 42,
+			},
+		}))
+
+		jobStatus = "successful"
+		statuses, err = reconciler.getSetupAnsibleJobStatuses(context.TODO(), cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(statuses[0].Status).Should(Equal(argocd.ApplicationHealthy))
+		cti.Status.ClusterSetup = &statuses
+		Expect(setupJobsRunning(cti)).Should(BeFalse())
+
+		// finished jobs are not read again
+		server.Close()
+		statuses, err = reconciler.getSetupAnsibleJobStatuses(context.TODO(), cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(statuses[0].Message).Should(Equal("Ansible job succeeded"))
+	})
+
+	It("Reads AAP credentials meant for the URL from the config namespace", func() {
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "aap-credentials",
+						Namespace: ArgoCDNamespace,
+					},
+					Data: map[string][]byte{"token": []byte("token")},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "aap-credentials",
+						Namespace: configNamespace,
+					},
+					Data: map[string][]byte{
+						"token": []byte("token"),
+						"url":   []byte(server.URL),
+					},
+				},
+			),
+		}
+		_, err := reconciler.getAAPClient(context.TODO(), &v1alpha1.SetupAnsibleJob{
+			URL:               server.URL,
+			CredentialsSecret: "aap-credentials",
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+
+		_, err = reconciler.getAAPClient(context.TODO(), &v1alpha1.SetupAnsibleJob{
+			URL:               "https://aap.example.com",
+			CredentialsSecret: "aap-credentials",
+		})
+		Expect(err).Should(MatchError(
+			"invalid AAP credentials Secret aap-credentials - " +
+				"credentials are not meant for https://aap.example.com",
+		))
+	})
+})
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clustersetup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// getNewClusterClient returns client of the new cluster, replaced in tests
+var getNewClusterClient = clustersetup.GetClientForCluster
+
+// reconcileSetupCredentials stores credentials of the new cluster for every cluster setup which
+// defines an identity, in Secrets passed to the setups
+func (r *ClusterTemplateInstanceReconciler) reconcileSetupCredentials(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	var adminKubeconfig []byte
+	var newClusterClient client.Client
+	for _, setup := range clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterSetup {
+		if setup.Identity == nil {
+			continue
+		}
+		if adminKubeconfig == nil {
+			kubeconfigSecret := &corev1.Secret{}
+			if err := r.Get(
+				ctx,
+				client.ObjectKey{
+					Name:      clusterTemplateInstance.GetKubeconfigRef(),
+					Namespace: clusterTemplateInstance.Namespace,
+				},
+				kubeconfigSecret,
+			); err != nil {
+				return err
+			}
+			adminKubeconfig = kubeconfigSecret.Data["kubeconfig"]
+		}
+
+		kubeconfig := adminKubeconfig
+		if setup.Identity.Type == v1alpha1.ServiceAccountIdentity {
+			if newClusterClient == nil {
+				var err error
+				newClusterClient, err = getNewClusterClient(adminKubeconfig)
+				if err != nil {
+					return err
+				}
+			}
+			var err error
+			kubeconfig, err = clustersetup.GetSetupServiceAccountKubeconfig(
+				ctx,
+				newClusterClient,
+				adminKubeconfig,
+				setup.Name,
+				setup.Identity.Rules,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to create identity of setup '%s' - %q", setup.Name, err)
+			}
+		}
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterTemplateInstance.GetSetupCredentialsRef(setup.Name),
+				Namespace: clusterTemplateInstance.Namespace,
+			},
+		}
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+			secret.OwnerReferences = []metav1.OwnerReference{
+				clusterTemplateInstance.GetOwnerReference(),
+			}
+			secret.Data = map[string][]byte{"kubeconfig": kubeconfig}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
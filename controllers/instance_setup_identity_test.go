@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clustersetup"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Cluster setup identity", func() {
+	adminKubeconfig := []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: foo
+  cluster:
+    server: https://api.foo.example.com:6443
+users:
+- name: admin
+  user:
+    token: admin-token
+contexts:
+- name: admin
+  context:
+    cluster: foo
+    user: admin
+current-context: admin
+`)
+
+	AfterEach(func() {
+		getNewClusterClient = clustersetup.GetClientForCluster
+	})
+
+	It("Stores credentials of setups with identity", func() {
+		newClusterClient := fake.NewFakeClientWithScheme(scheme.Scheme, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "claas-setup-logging-token",
+				Namespace: "kube-system",
+			},
+			Data: map[string][]byte{
+				"token":  []byte("sa-token"),
+				"ca.crt": []byte("ca.crt"),
+			},
+		})
+		getNewClusterClient = func(_ []byte) (client.Client, error) {
+			return newClusterClient, nil
+		}
+
+		cti := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					ClusterSetup: []v1alpha1.ClusterSetup{
+						{Name: "day2"},
+						{
+							Name:     "admin",
+							Identity: &v1alpha1.SetupIdentity{Type: v1alpha1.KubeconfigIdentity},
+						},
+						{
+							Name: "logging",
+							Identity: &v1alpha1.SetupIdentity{
+								Type:  v1alpha1.ServiceAccountIdentity,
+								Rules: []rbacv1.PolicyRule{{Verbs: []string{"get"}}},
+							},
+						},
+					},
+				},
+			},
+		}
+		kubeconfigSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetKubeconfigRef(),
+				Namespace: "bar",
+			},
+			Data: map[string][]byte{"kubeconfig": adminKubeconfig},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti, kubeconfigSecret)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		Expect(reconciler.reconcileSetupCredentials(context.TODO(), cti)).Should(Succeed())
+
+		secrets := &corev1.SecretList{}
+		Expect(k8sClient.List(context.TODO(), secrets)).Should(Succeed())
+		Expect(secrets.Items).Should(HaveLen(3))
+
+		admin := &corev1.Secret{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: "foo-admin-credentials", Namespace: "bar"},
+			admin,
+		)).Should(Succeed())
+		Expect(admin.Data["kubeconfig"]).Should(Equal(adminKubeconfig))
+		Expect(admin.OwnerReferences).Should(HaveLen(1))
+
+		logging := &corev1.Secret{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: "foo-logging-credentials", Namespace: "bar"},
+			logging,
+		)).Should(Succeed())
+		restConfig, err := clientcmd.RESTConfigFromKubeConfig(logging.Data["kubeconfig"])
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(restConfig.BearerToken).Should(Equal("sa-token"))
+	})
+})
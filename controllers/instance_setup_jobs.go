@@ -0,0 +1,308 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+	"github.com/stolostron/cluster-templates-operator/clustersetup"
+)
+
+// how often running setup Jobs are checked
+const setupJobsCheckInterval = 15 * time.Second
+
+// createSetupJobs creates Jobs of cluster setups run by Jobs which do not exist yet. Jobs on the
+// hub are owned by the instance, so they are deleted with it. Jobs on the new cluster are deleted
+// with the cluster.
+func (r *ClusterTemplateInstanceReconciler) createSetupJobs(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	jobClients *setupJobClients,
+) error {
+	for _, setup := range clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterSetup {
+		if setup.Job == nil || len(clusterTemplateInstance.GetPendingSetupDependencies(setup)) > 0 {
+			continue
+		}
+		jobClient, err := jobClients.get(ctx, setup)
+		if err != nil {
+			return err
+		}
+		if _, err := r.createSetupJob(
+			ctx,
+			clusterTemplateInstance,
+			setup,
+			jobClient,
+			0,
+		); err != nil {
+			return fmt.Errorf("failed to create Job of cluster setup %s - %q", setup.Name, err)
+		}
+	}
+	return nil
+}
+
+// createSetupJob creates Job of the cluster setup, of its retry if retry is not 0, unless it
+// exists already. The kubeconfig Secret mounted by Jobs run on the new cluster is copied there,
+// the copy is deleted once the Job finishes.
+func (r *ClusterTemplateInstanceReconciler) createSetupJob(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setup v1alpha1.ClusterSetup,
+	jobClient client.Client,
+	retry int,
+) (*batchv1.Job, error) {
+	job := clustersetup.NewSetupJob(clusterTemplateInstance, setup, retry)
+	job.Spec.ActiveDeadlineSeconds = getSetupJobDeadline(clusterTemplateInstance)
+	if setup.Job.RunOnCluster {
+		if err := r.copySetupJobKubeconfig(
+			ctx,
+			clusterTemplateInstance,
+			setup,
+			jobClient,
+			job,
+		); err != nil {
+			return nil, err
+		}
+	}
+	if err := jobClient.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, err
+	}
+	return job, nil
+}
+
+// setupJobClients holds clients of the clusters the Jobs of cluster setups run on during a
+// single reconcile. The client of the new cluster requires discovery of its API, so it is built
+// at most once.
+type setupJobClients struct {
+	reconciler              *ClusterTemplateInstanceReconciler
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance
+	newClusterClient        client.Client
+}
+
+func (r *ClusterTemplateInstanceReconciler) newSetupJobClients(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) *setupJobClients {
+	return &setupJobClients{reconciler: r, clusterTemplateInstance: clusterTemplateInstance}
+}
+
+// get returns client of the cluster the Jobs of the cluster setup run on - the hub, or the new
+// cluster accessed by its admin kubeconfig
+func (c *setupJobClients) get(
+	ctx context.Context,
+	setup v1alpha1.ClusterSetup,
+) (client.Client, error) {
+	if !setup.Job.RunOnCluster {
+		return c.reconciler.Client, nil
+	}
+	if c.newClusterClient != nil {
+		return c.newClusterClient, nil
+	}
+	kubeconfigSecret := &corev1.Secret{}
+	if err := c.reconciler.Get(
+		ctx,
+		client.ObjectKey{
+			Name:      c.clusterTemplateInstance.GetKubeconfigRef(),
+			Namespace: c.clusterTemplateInstance.Namespace,
+		},
+		kubeconfigSecret,
+	); err != nil {
+		return nil, err
+	}
+	newClusterClient, err := getNewClusterClient(kubeconfigSecret.Data["kubeconfig"])
+	if err != nil {
+		return nil, err
+	}
+	c.newClusterClient = newClusterClient
+	return newClusterClient, nil
+}
+
+// copySetupJobKubeconfig copies the kubeconfig Secret of the cluster setup from the namespace of
+// the instance to the Secret mounted by the Job in its namespace on the new cluster
+func (r *ClusterTemplateInstanceReconciler) copySetupJobKubeconfig(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setup v1alpha1.ClusterSetup,
+	newClusterClient client.Client,
+	job *batchv1.Job,
+) error {
+	kubeconfigSecret := &corev1.Secret{}
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{
+			Name:      clustersetup.GetSetupJobKubeconfigRef(clusterTemplateInstance, setup),
+			Namespace: clusterTemplateInstance.Namespace,
+		},
+		kubeconfigSecret,
+	); err != nil {
+		return err
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: job.Namespace}}
+	if err := newClusterClient.Create(ctx, namespace); err != nil &&
+		!apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      job.Spec.Template.Spec.Volumes[0].Secret.SecretName,
+			Namespace: job.Namespace,
+		},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, newClusterClient, secret, func() error {
+		secret.Labels = job.Labels
+		secret.Data = map[string][]byte{"kubeconfig": kubeconfigSecret.Data["kubeconfig"]}
+		return nil
+	})
+	return err
+}
+
+// deleteSetupJobKubeconfig deletes the copy of the kubeconfig mounted by the finished Job run on
+// the new cluster, so credentials of the cluster are not left there. Retries of the Job get their
+// own copy.
+func deleteSetupJobKubeconfig(
+	ctx context.Context,
+	newClusterClient client.Client,
+	job *batchv1.Job,
+) error {
+	secretName := job.Spec.Template.Spec.Volumes[0].Secret.SecretName
+	// Jobs created before the copies were named after them share a single copy
+	if secretName != job.Name {
+		return nil
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: job.Namespace},
+	}
+	return client.IgnoreNotFound(newClusterClient.Delete(ctx, secret))
+}
+
+// getSetupJobStatuses returns status of cluster setups run by Jobs
+func (r *ClusterTemplateInstanceReconciler) getSetupJobStatuses(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	jobClients *setupJobClients,
+) ([]v1alpha1.ClusterSetupStatus, error) {
+	statuses := []v1alpha1.ClusterSetupStatus{}
+	for _, setup := range clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterSetup {
+		if setup.Job == nil {
+			continue
+		}
+		setupStatus := v1alpha1.ClusterSetupStatus{
+			Name:    setup.Name,
+			JobName: clusterTemplateInstance.GetSetupJobName(setup.Name),
+		}
+		// Job of the last retry
+		if previous := getSetupStatus(clusterTemplateInstance, setup.Name); previous != nil &&
+			previous.JobName != "" {
+			setupStatus.JobName = previous.JobName
+			setupStatus.Retries = previous.Retries
+		}
+		jobClient, err := jobClients.get(ctx, setup)
+		if err != nil {
+			return nil, err
+		}
+		job := &batchv1.Job{}
+		if err := jobClient.Get(
+			ctx,
+			client.ObjectKey{
+				Name:      setupStatus.JobName,
+				Namespace: clustersetup.GetSetupJobNamespace(clusterTemplateInstance, setup),
+			},
+			job,
+		); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			pending := clusterTemplateInstance.GetPendingSetupDependencies(setup)
+			if len(pending) > 0 {
+				statuses = append(statuses, getWaitingSetupStatus(setup.Name, pending))
+				continue
+			}
+			setupStatus.Status = argocd.ApplicationError
+			setupStatus.Message = "Job was deleted"
+		} else {
+			setupStatus.Status, setupStatus.Message = clustersetup.GetSetupJobStatus(job)
+			if setup.Job.RunOnCluster && setupStatus.Status != argocd.ApplicationSyncRunning {
+				if err := deleteSetupJobKubeconfig(ctx, jobClient, job); err != nil {
+					return nil, err
+				}
+			}
+			if setupStatus.Status == argocd.ApplicationSyncFailed {
+				if err := r.retrySetupJob(
+					ctx,
+					clusterTemplateInstance,
+					setup,
+					jobClient,
+					job,
+					&setupStatus,
+				); err != nil {
+					return nil, err
+				}
+			}
+		}
+		statuses = append(statuses, setupStatus)
+	}
+	return statuses, nil
+}
+
+// retrySetupJob creates Job of the next retry of the failed cluster setup once the retry backoff
+// elapses, the setup waiting for the retry is reported as running
+func (r *ClusterTemplateInstanceReconciler) retrySetupJob(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setup v1alpha1.ClusterSetup,
+	jobClient client.Client,
+	job *batchv1.Job,
+	setupStatus *v1alpha1.ClusterSetupStatus,
+) error {
+	ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+	delay, retry := getSetupRetryDelay(
+		clusterTemplateInstance,
+		setupStatus.Retries,
+		clustersetup.GetSetupJobFailedTime(job),
+	)
+	if !retry || delay > 0 {
+		setRetryMessage(ctSpec, setupStatus, delay, retry)
+		return nil
+	}
+	retryJob, err := r.createSetupJob(
+		ctx,
+		clusterTemplateInstance,
+		setup,
+		jobClient,
+		setupStatus.Retries+1,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create Job of cluster setup %s - %q", setup.Name, err)
+	}
+	setupStatus.Retries++
+	setupStatus.JobName = retryJob.Name
+	setupStatus.Status = argocd.ApplicationSyncRunning
+	setupStatus.Message = fmt.Sprintf(
+		"Retrying failed Job (retry %d of %d)",
+		setupStatus.Retries,
+		ctSpec.SetupRetryLimit,
+	)
+	return nil
+}
+
+// setupJobsRunning returns true while any cluster setup Job or Ansible job runs, neither is
+// watched
+func setupJobsRunning(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) bool {
+	if clusterTemplateInstance.Status.ClusterSetup == nil {
+		return false
+	}
+	for _, setupStatus := range *clusterTemplateInstance.Status.ClusterSetup {
+		if (setupStatus.JobName != "" || setupStatus.AnsibleJobID != 0) &&
+			setupStatus.Status == argocd.ApplicationSyncRunning {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,274 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+	"github.com/stolostron/cluster-templates-operator/clustersetup"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Cluster setup Jobs", func() {
+	var cti *v1alpha1.ClusterTemplateInstance
+
+	BeforeEach(func() {
+		cti = &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					ClusterSetup: []v1alpha1.ClusterSetup{
+						{Name: "day2"},
+						{
+							Name: "monitoring",
+							Job: &v1alpha1.SetupJob{
+								Image:  "quay.io/openshift/origin-cli:latest",
+								Script: "oc apply -f monitoring.yaml",
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		getNewClusterClient = clustersetup.GetClientForCluster
+	})
+
+	It("Creates Jobs of cluster setups and reports their status", func() {
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme),
+		}
+		jobClients := reconciler.newSetupJobClients(cti)
+		Expect(reconciler.createSetupJobs(context.TODO(), cti, jobClients)).Should(Succeed())
+		// already existing Jobs are kept
+		Expect(reconciler.createSetupJobs(context.TODO(), cti, jobClients)).Should(Succeed())
+
+		jobs := &batchv1.JobList{}
+		Expect(reconciler.Client.List(context.TODO(), jobs)).Should(Succeed())
+		Expect(jobs.Items).Should(HaveLen(1))
+		Expect(jobs.Items[0].Name).Should(Equal("foo-setup-monitoring"))
+
+		statuses, err := reconciler.getSetupJobStatuses(context.TODO(), cti, jobClients)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(statuses).Should(HaveLen(1))
+		Expect(statuses[0].Name).Should(Equal("monitoring"))
+		Expect(statuses[0].JobName).Should(Equal("foo-setup-monitoring"))
+		Expect(statuses[0].Status).Should(Equal(argocd.ApplicationSyncRunning))
+
+		cti.Status.ClusterSetup = &statuses
+		Expect(setupJobsRunning(cti)).Should(BeTrue())
+
+		job := &jobs.Items[0]
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+		}
+		Expect(reconciler.Client.Status().Update(context.TODO(), job)).Should(Succeed())
+
+		statuses, err = reconciler.getSetupJobStatuses(context.TODO(), cti, jobClients)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(statuses[0].Status).Should(Equal(argocd.ApplicationHealthy))
+		cti.Status.ClusterSetup = &statuses
+		Expect(setupJobsRunning(cti)).Should(BeFalse())
+	})
+
+	It("Retries failed Jobs", func() {
+		cti.Status.ClusterTemplateSpec.SetupRetryLimit = 1
+		cti.Status.ClusterTemplateSpec.SetupRetryBackoff = &metav1.Duration{Duration: time.Hour}
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme),
+		}
+		jobClients := reconciler.newSetupJobClients(cti)
+		Expect(reconciler.createSetupJobs(context.TODO(), cti, jobClients)).Should(Succeed())
+		failJob := func(name string) {
+			job := &batchv1.Job{}
+			Expect(reconciler.Client.Get(
+				context.TODO(),
+				client.ObjectKey{Name: name, Namespace: cti.Namespace},
+				job,
+			)).Should(Succeed())
+			job.Status.Conditions = []batchv1.JobCondition{
+				{
+					Type:               batchv1.JobFailed,
+					Status:             corev1.ConditionTrue,
+					Message:            "BackoffLimitExceeded",
+					LastTransitionTime: metav1.Now(),
+				},
+			}
+			Expect(reconciler.Client.Status().Update(context.TODO(), job)).Should(Succeed())
+		}
+		failJob("foo-setup-monitoring")
+
+		// the backoff did not elapse yet
+		statuses, err := reconciler.getSetupJobStatuses(context.TODO(), cti, jobClients)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(statuses[0].Status).Should(Equal(argocd.ApplicationSyncRunning))
+		Expect(statuses[0].Message).Should(HavePrefix(
+			"Job failed - BackoffLimitExceeded, retry 1 of 1 in ",
+		))
+		cti.Status.ClusterSetup = &statuses
+
+		cti.Status.ClusterTemplateSpec.SetupRetryBackoff = &metav1.Duration{}
+		statuses, err = reconciler.getSetupJobStatuses(context.TODO(), cti, jobClients)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(statuses[0]).Should(Equal(v1alpha1.ClusterSetupStatus{
+			Name:    "monitoring",
+			Status:  argocd.ApplicationSyncRunning,
+			Message: "Retrying failed Job (retry 1 of 1)",
+			JobName: "foo-setup-monitoring-retry-1",
+			Retries: 1,
+		}))
+		cti.Status.ClusterSetup = &statuses
+
+		failJob("foo-setup-monitoring-retry-1")
+		statuses, err = reconciler.getSetupJobStatuses(context.TODO(), cti, jobClients)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(statuses[0].Status).Should(Equal(argocd.ApplicationSyncFailed))
+		Expect(statuses[0].Message).Should(Equal(
+			"Job failed - BackoffLimitExceeded (retried 1 times)",
+		))
+	})
+
+	It("Creates Jobs of cluster setups stage by stage", func() {
+		cti.Status.ClusterTemplateSpec.ClusterSetup = append(
+			cti.Status.ClusterTemplateSpec.ClusterSetup[1:],
+			v1alpha1.ClusterSetup{
+				Name:      "alerts",
+				DependsOn: []string{"monitoring"},
+				Job: &v1alpha1.SetupJob{
+					Image:  "quay.io/openshift/origin-cli:latest",
+					Script: "oc apply -f alerts.yaml",
+				},
+			},
+		)
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      cti.GetKubeconfigRef(),
+						Namespace: cti.Namespace,
+					},
+					Data: map[string][]byte{"kubeconfig": []byte("apiVersion: v1\n")},
+				},
+			),
+		}
+		jobClients := reconciler.newSetupJobClients(cti)
+		Expect(reconciler.createSetupJobs(context.TODO(), cti, jobClients)).Should(Succeed())
+		jobs := &batchv1.JobList{}
+		Expect(reconciler.Client.List(context.TODO(), jobs)).Should(Succeed())
+		Expect(jobs.Items).Should(HaveLen(1))
+
+		statuses, err := reconciler.getSetupJobStatuses(context.TODO(), cti, jobClients)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(statuses[1]).Should(Equal(v1alpha1.ClusterSetupStatus{
+			Name:    "alerts",
+			Status:  argocd.ApplicationWaiting,
+			Message: "Waiting for cluster setups [monitoring]",
+		}))
+		cti.Status.ClusterSetup = &statuses
+		Expect(reconciler.createNextSetupStages(context.TODO(), cti, jobClients)).Should(Succeed())
+		Expect(reconciler.Client.List(context.TODO(), jobs)).Should(Succeed())
+		Expect(jobs.Items).Should(HaveLen(1))
+
+		job := &jobs.Items[0]
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+		}
+		Expect(reconciler.Client.Status().Update(context.TODO(), job)).Should(Succeed())
+		statuses, err = reconciler.getSetupJobStatuses(context.TODO(), cti, jobClients)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(statuses[0].Status).Should(Equal(argocd.ApplicationHealthy))
+		cti.Status.ClusterSetup = &statuses
+
+		Expect(reconciler.createNextSetupStages(context.TODO(), cti, jobClients)).Should(Succeed())
+		Expect(reconciler.Client.List(context.TODO(), jobs)).Should(Succeed())
+		Expect(jobs.Items).Should(HaveLen(2))
+		statuses, err = reconciler.getSetupJobStatuses(context.TODO(), cti, jobClients)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(statuses[1].Status).Should(Equal(argocd.ApplicationSyncRunning))
+	})
+
+	It("Creates Jobs on the new cluster", func() {
+		cti.Status.ClusterTemplateSpec.ClusterSetup[1].Job.RunOnCluster = true
+		newClusterClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		clientsBuilt := 0
+		getNewClusterClient = func(_ []byte) (client.Client, error) {
+			clientsBuilt++
+			return newClusterClient, nil
+		}
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      cti.GetKubeconfigRef(),
+						Namespace: cti.Namespace,
+					},
+					Data: map[string][]byte{"kubeconfig": []byte("apiVersion: v1\n")},
+				},
+			),
+		}
+		jobClients := reconciler.newSetupJobClients(cti)
+		Expect(reconciler.createSetupJobs(context.TODO(), cti, jobClients)).Should(Succeed())
+
+		jobs := &batchv1.JobList{}
+		Expect(reconciler.Client.List(context.TODO(), jobs)).Should(Succeed())
+		Expect(jobs.Items).Should(BeEmpty())
+		Expect(newClusterClient.List(context.TODO(), jobs)).Should(Succeed())
+		Expect(jobs.Items).Should(HaveLen(1))
+		Expect(jobs.Items[0].Namespace).Should(Equal(clustersetup.SetupJobClusterNamespace))
+
+		secret := &corev1.Secret{}
+		secretKey := client.ObjectKey{
+			Name:      jobs.Items[0].Name,
+			Namespace: clustersetup.SetupJobClusterNamespace,
+		}
+		Expect(newClusterClient.Get(context.TODO(), secretKey, secret)).Should(Succeed())
+		Expect(secret.Data["kubeconfig"]).Should(Equal([]byte("apiVersion: v1\n")))
+
+		job := &jobs.Items[0]
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+		}
+		Expect(newClusterClient.Status().Update(context.TODO(), job)).Should(Succeed())
+		statuses, err := reconciler.getSetupJobStatuses(context.TODO(), cti, jobClients)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(statuses[0].Status).Should(Equal(argocd.ApplicationHealthy))
+		// the kubeconfig is not left on the cluster once the Job completed
+		Expect(newClusterClient.Get(context.TODO(), secretKey, secret)).ShouldNot(Succeed())
+		// the client of the new cluster is built once per reconcile
+		Expect(clientsBuilt).Should(Equal(1))
+	})
+
+	It("Reports deleted Jobs", func() {
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme),
+		}
+		jobClients := reconciler.newSetupJobClients(cti)
+		Expect(reconciler.createSetupJobs(context.TODO(), cti, jobClients)).Should(Succeed())
+		Expect(reconciler.Client.Delete(context.TODO(), &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetSetupJobName("monitoring"),
+				Namespace: cti.Namespace,
+			},
+		})).Should(Succeed())
+
+		statuses, err := reconciler.getSetupJobStatuses(context.TODO(), cti, jobClients)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(statuses[0].Status).Should(Equal(argocd.ApplicationError))
+		Expect(statuses[0].Message).Should(Equal("Job was deleted"))
+	})
+})
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
+)
+
+// ClusterVersion of the new cluster is not watched, the end of the upgrade is checked periodically
+const setupPauseCheckInterval = time.Minute
+
+// reconcileSetupPause pauses automated syncs of the cluster setups while the new cluster is
+// upgrading, so ArgoCD neither re-runs the setups nor reverts drifts of the resources the
+// upgrade is changing. The syncs are resumed once the upgrade completes.
+func (r *ClusterTemplateInstanceReconciler) reconcileSetupPause(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	if len(clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterSetup) == 0 ||
+		!meta.IsStatusConditionTrue(
+			clusterTemplateInstance.Status.Conditions,
+			string(v1alpha1.ClusterSetupCreated),
+		) {
+		return nil
+	}
+
+	kubeconfigSecret := &corev1.Secret{}
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{
+			Name:      clusterTemplateInstance.GetKubeconfigRef(),
+			Namespace: clusterTemplateInstance.Namespace,
+		},
+		kubeconfigSecret,
+	); err != nil {
+		return err
+	}
+	newClusterClient, err := getNewClusterClient(kubeconfigSecret.Data["kubeconfig"])
+	if err != nil {
+		return err
+	}
+	upgrading, message, err := clusterprovider.GetClusterUpgradeProgress(ctx, newClusterClient)
+	if err != nil {
+		return err
+	}
+
+	apps, err := clusterTemplateInstance.GetDay2Applications(ctx, r.Client, ArgoCDNamespace)
+	if err != nil {
+		return err
+	}
+	for i := range apps.Items {
+		app := &apps.Items[i]
+		var changed bool
+		if upgrading {
+			changed, err = argocd.PauseAutomatedSync(app)
+		} else {
+			changed, err = argocd.ResumeAutomatedSync(app)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update sync policy of application %s - %q", app.Name, err)
+		}
+		if !changed {
+			continue
+		}
+		if err := r.Update(ctx, app); err != nil {
+			return err
+		}
+	}
+
+	if upgrading {
+		if !setupPaused(clusterTemplateInstance) {
+			CTIlog.Info(
+				"Cluster is upgrading, pausing cluster setup",
+				"name",
+				clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+			)
+		}
+		clusterTemplateInstance.SetSetupPausedCondition(
+			metav1.ConditionTrue,
+			v1alpha1.ClusterUpgrading,
+			fmt.Sprintf("Cluster setup is paused while the cluster is upgrading - %s", message),
+		)
+		return nil
+	}
+	if setupPaused(clusterTemplateInstance) {
+		CTIlog.Info(
+			"Cluster upgrade completed, resuming cluster setup",
+			"name",
+			clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		)
+		meta.RemoveStatusCondition(
+			&clusterTemplateInstance.Status.Conditions,
+			string(v1alpha1.SetupPaused),
+		)
+	}
+	return nil
+}
+
+// setupPaused returns true while the cluster setups are paused
+func setupPaused(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) bool {
+	return meta.IsStatusConditionTrue(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.SetupPaused),
+	)
+}
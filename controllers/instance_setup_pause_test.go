@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"context"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+	"github.com/stolostron/cluster-templates-operator/clustersetup"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance setup pause", func() {
+	AfterEach(func() {
+		getNewClusterClient = clustersetup.GetClientForCluster
+	})
+
+	getClusterVersion := func(progressing string) *unstructured.Unstructured {
+		clusterVersion := &unstructured.Unstructured{}
+		clusterVersion.SetAPIVersion("config.openshift.io/v1")
+		clusterVersion.SetKind("ClusterVersion")
+		clusterVersion.SetName("version")
+		Expect(unstructured.SetNestedSlice(
+			clusterVersion.Object,
+			[]interface{}{
+				map[string]interface{}{
+					"type":    "Progressing",
+					"status":  progressing,
+					"message": "Working towards 4.12.1",
+				},
+			},
+			"status",
+			"conditions",
+		)).Should(Succeed())
+		return clusterVersion
+	}
+
+	It("Pauses automated sync of cluster setups while the cluster upgrades", func() {
+		cti := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					ClusterSetup: []v1alpha1.ClusterSetup{{Name: "day2"}},
+				},
+			},
+		}
+		cti.SetClusterSetupCreatedCondition(
+			metav1.ConditionTrue,
+			v1alpha1.SetupCreated,
+			"Cluster setup created",
+		)
+		kubeconfig := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetKubeconfigRef(),
+				Namespace: cti.Namespace,
+			},
+		}
+		app := &argo.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetDay2ApplicationName("day2"),
+				Namespace: ArgoCDNamespace,
+				Labels: map[string]string{
+					v1alpha1.CTINameLabel:      cti.Name,
+					v1alpha1.CTINamespaceLabel: cti.Namespace,
+					v1alpha1.CTISetupLabel:     "day2",
+				},
+			},
+			Spec: argo.ApplicationSpec{
+				SyncPolicy: &argo.SyncPolicy{
+					Automated: &argo.SyncPolicyAutomated{SelfHeal: true},
+				},
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, kubeconfig, app)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+
+		clusterVersion := getClusterVersion("True")
+		getNewClusterClient = func(_ []byte) (client.Client, error) {
+			return fake.NewFakeClientWithScheme(scheme.Scheme, clusterVersion), nil
+		}
+		Expect(reconciler.reconcileSetupPause(context.TODO(), cti)).Should(Succeed())
+		Expect(setupPaused(cti)).Should(BeTrue())
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(app), app)).Should(Succeed())
+		Expect(app.Spec.SyncPolicy.Automated).Should(BeNil())
+		Expect(argocd.IsAutomatedSyncPaused(app)).Should(BeTrue())
+
+		clusterVersion = getClusterVersion("False")
+		Expect(reconciler.reconcileSetupPause(context.TODO(), cti)).Should(Succeed())
+		Expect(meta.FindStatusCondition(cti.Status.Conditions, string(v1alpha1.SetupPaused))).
+			Should(BeNil())
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(app), app)).Should(Succeed())
+		Expect(app.Spec.SyncPolicy.Automated).Should(Equal(&argo.SyncPolicyAutomated{SelfHeal: true}))
+		Expect(argocd.IsAutomatedSyncPaused(app)).Should(BeFalse())
+	})
+})
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+)
+
+// delay before the first retry of a failed cluster setup unless the template sets it
+const defaultSetupRetryBackoff = time.Minute
+
+// getSetupRetryDelay returns how long the cluster setup which failed at the given time waits for
+// its next retry. The backoff doubles with every retry. False is returned if the retry limit of
+// the template is reached or the cluster setup timed out.
+func getSetupRetryDelay(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	retries int,
+	failedAt time.Time,
+) (time.Duration, bool) {
+	ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+	if retries >= ctSpec.SetupRetryLimit || setupTimedOut(clusterTemplateInstance) {
+		return 0, false
+	}
+	backoff := defaultSetupRetryBackoff
+	if ctSpec.SetupRetryBackoff != nil {
+		backoff = ctSpec.SetupRetryBackoff.Duration
+	}
+	delay := time.Until(failedAt.Add(backoff << retries))
+	if delay < 0 {
+		delay = 0
+	}
+	return delay, true
+}
+
+// setRetryMessage reports the failed cluster setup as running while it waits for the retry, and
+// how many times it was retried once it is not retried anymore
+func setRetryMessage(
+	ctSpec *v1alpha1.ClusterTemplateSpec,
+	setupStatus *v1alpha1.ClusterSetupStatus,
+	delay time.Duration,
+	retry bool,
+) {
+	if retry {
+		setupStatus.Status = argocd.ApplicationSyncRunning
+		setupStatus.Message = fmt.Sprintf(
+			"%s, retry %d of %d in %s",
+			setupStatus.Message,
+			setupStatus.Retries+1,
+			ctSpec.SetupRetryLimit,
+			delay.Round(time.Second),
+		)
+		return
+	}
+	if setupStatus.Retries > 0 {
+		setupStatus.Message = fmt.Sprintf(
+			"%s (retried %d times)",
+			setupStatus.Message,
+			setupStatus.Retries,
+		)
+	}
+}
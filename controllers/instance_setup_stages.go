@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+)
+
+// getWaitingSetupStatus returns status of the cluster setup which waits for the cluster setups it
+// depends on
+func getWaitingSetupStatus(setupName string, pending []string) v1alpha1.ClusterSetupStatus {
+	return v1alpha1.ClusterSetupStatus{
+		Name:    setupName,
+		Status:  argocd.ApplicationWaiting,
+		Message: fmt.Sprintf("Waiting for cluster setups %v", pending),
+	}
+}
+
+// addWaitingSetupStatuses adds status of the cluster setups run by applications which wait for
+// the cluster setups they depend on, their applications do not exist yet
+func addWaitingSetupStatuses(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	statuses []v1alpha1.ClusterSetupStatus,
+) []v1alpha1.ClusterSetupStatus {
+	reported := map[string]bool{}
+	for _, setupStatus := range statuses {
+		reported[setupStatus.Name] = true
+	}
+	for _, setup := range clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterSetup {
+		if reported[setup.Name] || !setup.UsesApplication() {
+			continue
+		}
+		if pending := clusterTemplateInstance.GetPendingSetupDependencies(setup); len(pending) > 0 {
+			statuses = append(statuses, getWaitingSetupStatus(setup.Name, pending))
+		}
+	}
+	return statuses
+}
+
+// createNextSetupStages creates the cluster setups whose dependencies succeeded since the
+// previous stage was created. Dependencies are evaluated against the current status of the
+// instance.
+func (r *ClusterTemplateInstanceReconciler) createNextSetupStages(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	jobClients *setupJobClients,
+) error {
+	ready := false
+	for _, setup := range clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterSetup {
+		setupStatus := getSetupStatus(clusterTemplateInstance, setup.Name)
+		if (setupStatus == nil || setupStatus.Status == argocd.ApplicationWaiting) &&
+			len(clusterTemplateInstance.GetPendingSetupDependencies(setup)) == 0 {
+			ready = true
+			break
+		}
+	}
+	if !ready {
+		return nil
+	}
+	if err := clusterTemplateInstance.CreateDay2Applications(
+		ctx,
+		r.Client,
+		ArgoCDNamespace,
+	); err != nil {
+		return err
+	}
+	if err := r.createSetupJobs(ctx, clusterTemplateInstance, jobClients); err != nil {
+		return err
+	}
+	return r.launchSetupAnsibleJobs(ctx, clusterTemplateInstance)
+}
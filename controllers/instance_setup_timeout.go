@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/cluster-templates-operator/aap"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+)
+
+// setupTimeRemaining returns time left until the cluster setup times out. The second return value
+// is false if the cluster setup is not limited (anymore)
+func setupTimeRemaining(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) (time.Duration, bool) {
+	ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+	if ctSpec == nil || ctSpec.SetupTimeout == nil {
+		return 0, false
+	}
+	setupCreatedCondition := meta.FindStatusCondition(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.ClusterSetupCreated),
+	)
+	if setupCreatedCondition == nil || setupCreatedCondition.Status != metav1.ConditionTrue ||
+		setupCreatedCondition.Reason != string(v1alpha1.SetupCreated) {
+		return 0, false
+	}
+	setupSucceededCondition := meta.FindStatusCondition(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.ClusterSetupSucceeded),
+	)
+	if setupSucceededCondition != nil &&
+		(setupSucceededCondition.Status == metav1.ConditionTrue ||
+			setupSucceededCondition.Reason == string(v1alpha1.ClusterSetupTimedOut)) {
+		return 0, false
+	}
+	elapsed := time.Since(setupCreatedCondition.LastTransitionTime.Time)
+	return ctSpec.SetupTimeout.Duration - elapsed, true
+}
+
+func setupTimedOut(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) bool {
+	remaining, limited := setupTimeRemaining(clusterTemplateInstance)
+	return limited && remaining <= 0
+}
+
+// getSetupJobDeadline returns the active deadline of setup Jobs, so Kubernetes stops them once the
+// cluster setup times out. Jobs of the first stage are created before the setup timer starts and
+// get the whole timeout.
+func getSetupJobDeadline(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) *int64 {
+	ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+	if ctSpec == nil || ctSpec.SetupTimeout == nil {
+		return nil
+	}
+	remaining, limited := setupTimeRemaining(clusterTemplateInstance)
+	if !limited {
+		remaining = ctSpec.SetupTimeout.Duration
+	}
+	seconds := int64(remaining.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	return &seconds
+}
+
+// timeOutClusterSetup cancels Ansible jobs which still run and fails the unfinished cluster
+// setups. Setup Jobs are stopped by their active deadline. ArgoCD Applications are left as they
+// are, the instance is not reconciled by the cluster setup anymore.
+func (r *ClusterTemplateInstanceReconciler) timeOutClusterSetup(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	unfinished := []string{}
+	for i := range *clusterTemplateInstance.Status.ClusterSetup {
+		setupStatus := &(*clusterTemplateInstance.Status.ClusterSetup)[i]
+		if setupStatus.Status != argocd.ApplicationSyncRunning &&
+			setupStatus.Status != argocd.ApplicationWaiting {
+			continue
+		}
+		if setupStatus.AnsibleJobID != 0 {
+			if err := r.cancelSetupAnsibleJob(ctx, clusterTemplateInstance, setupStatus); err != nil {
+				clusterTemplateInstance.SetClusterSetupSucceededCondition(
+					metav1.ConditionFalse,
+					v1alpha1.ClusterSetupError,
+					fmt.Sprintf("Failed to cancel Ansible job of timed out cluster setup - %q", err),
+				)
+				return err
+			}
+		}
+		unfinished = append(unfinished, setupStatus.Name)
+		setupStatus.Status = argocd.ApplicationSyncFailed
+		setupStatus.Message = "Cluster setup timed out - " + setupStatus.Message
+	}
+
+	msg := fmt.Sprintf(
+		"Cluster setup did not finish within %s - %v",
+		clusterTemplateInstance.Status.ClusterTemplateSpec.SetupTimeout.Duration,
+		unfinished,
+	)
+	clusterTemplateInstance.SetClusterSetupSucceededCondition(
+		metav1.ConditionFalse,
+		v1alpha1.ClusterSetupTimedOut,
+		msg,
+	)
+	clusterTemplateInstance.Status.Phase = v1alpha1.ClusterSetupErrorPhase
+	clusterTemplateInstance.Status.Message = msg
+	return nil
+}
+
+func (r *ClusterTemplateInstanceReconciler) cancelSetupAnsibleJob(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setupStatus *v1alpha1.ClusterSetupStatus,
+) error {
+	for _, setup := range clusterTemplateInstance.Status.ClusterTemplateSpec.ClusterSetup {
+		if setup.Name != setupStatus.Name || setup.AnsibleJob == nil {
+			continue
+		}
+		aapClient, err := r.getAAPClient(ctx, setup.AnsibleJob)
+		if err != nil {
+			return err
+		}
+		err = aapClient.CancelJob(ctx, setupStatus.AnsibleJobID)
+		if err != nil && !errors.Is(err, aap.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Cluster setup timeout", func() {
+	var server *httptest.Server
+	var canceled bool
+
+	BeforeEach(func() {
+		canceled = false
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/v2/jobs/42/cancel/", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`{"can_cancel":true}`))
+				return
+			}
+			canceled = true
+			w.WriteHeader(http.StatusAccepted)
+		})
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Cancels unfinished cluster setups once the timeout elapses", func() {
+		cti := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					SetupRetryLimit: 2,
+					SetupTimeout:    &metav1.Duration{Duration: time.Hour},
+					ClusterSetup: []v1alpha1.ClusterSetup{
+						{Name: "day2"},
+						{
+							Name: "register",
+							AnsibleJob: &v1alpha1.SetupAnsibleJob{
+								URL:               server.URL,
+								CredentialsSecret: "aap-credentials",
+								JobTemplateID:     7,
+							},
+						},
+					},
+				},
+				ClusterSetup: &[]v1alpha1.ClusterSetupStatus{
+					{
+						Name:    "day2",
+						Status:  argocd.ApplicationHealthy,
+						Message: "Synced",
+					},
+					{
+						Name:         "register",
+						Status:       argocd.ApplicationSyncRunning,
+						Message:      "Ansible job is running",
+						AnsibleJobID: 42,
+					},
+				},
+			},
+		}
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "aap-credentials",
+						Namespace: configNamespace,
+					},
+					Data: map[string][]byte{
+						"token": []byte("token"),
+						"url":   []byte(server.URL),
+					},
+				},
+			),
+		}
+
+		// the timer starts once the cluster setup is created
+		Expect(setupTimedOut(cti)).Should(BeFalse())
+		Expect(*getSetupJobDeadline(cti)).Should(Equal(int64(3600)))
+
+		meta.SetStatusCondition(&cti.Status.Conditions, metav1.Condition{
+			Type:               string(v1alpha1.ClusterSetupCreated),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(v1alpha1.SetupCreated),
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-30 * time.Minute)),
+		})
+		remaining, limited := setupTimeRemaining(cti)
+		Expect(limited).Should(BeTrue())
+		Expect(remaining).Should(BeNumerically("~", 30*time.Minute, time.Minute))
+		Expect(*getSetupJobDeadline(cti)).Should(BeNumerically("~", 1800, 60))
+
+		cti.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		Expect(setupTimedOut(cti)).Should(BeTrue())
+		_, retry := getSetupRetryDelay(cti, 0, time.Now())
+		Expect(retry).Should(BeFalse())
+
+		Expect(reconciler.timeOutClusterSetup(context.TODO(), cti)).Should(Succeed())
+		Expect(canceled).Should(BeTrue())
+		Expect((*cti.Status.ClusterSetup)[0].Status).Should(Equal(argocd.ApplicationHealthy))
+		Expect((*cti.Status.ClusterSetup)[1].Status).Should(Equal(argocd.ApplicationSyncFailed))
+		Expect((*cti.Status.ClusterSetup)[1].Message).Should(Equal(
+			"Cluster setup timed out - Ansible job is running",
+		))
+		Expect(setupJobsRunning(cti)).Should(BeFalse())
+		condition := meta.FindStatusCondition(
+			cti.Status.Conditions,
+			string(v1alpha1.ClusterSetupSucceeded),
+		)
+		Expect(condition.Reason).Should(Equal(string(v1alpha1.ClusterSetupTimedOut)))
+		Expect(condition.Message).Should(Equal("Cluster setup did not finish within 1h0m0s - [register]"))
+		Expect(cti.Status.Phase).Should(Equal(v1alpha1.ClusterSetupErrorPhase))
+
+		// timed out cluster setup is not limited anymore, so the instance is not requeued for it
+		_, limited = setupTimeRemaining(cti)
+		Expect(limited).Should(BeFalse())
+	})
+})
@@ -0,0 +1,346 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/helm"
+)
+
+const (
+	// how long the tests can run unless the template sets the timeout
+	defaultChartTestsTimeout = 10 * time.Minute
+	// how often running tests are checked
+	chartTestsCheckInterval = 10 * time.Second
+	// tail of the test pod log kept in the instance status
+	chartTestLogLines = 50
+	chartTestLogBytes = 4096
+)
+
+// reconcileChartTests runs test hooks of the cluster definition chart once the cluster is
+// installed, like 'helm test' does - ArgoCD does not run them. The tests are run only once,
+// their results do not affect the phase of the instance.
+func (r *ClusterTemplateInstanceReconciler) reconcileChartTests(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	if clusterTemplateInstance.Status.ClusterTemplateSpec.ChartTests == nil ||
+		!meta.IsStatusConditionTrue(
+			clusterTemplateInstance.Status.Conditions,
+			string(v1alpha1.ClusterInstallSucceeded),
+		) {
+		return nil
+	}
+	tests := clusterTemplateInstance.Status.ChartTests
+	if tests == nil {
+		r.startChartTests(ctx, clusterTemplateInstance)
+		return nil
+	}
+	if tests.Phase != v1alpha1.TestsRunning {
+		return nil
+	}
+	return r.checkChartTests(ctx, clusterTemplateInstance)
+}
+
+// startChartTests creates pods of the chart test hooks, failures to render or create them are
+// reported as failed tests
+func (r *ClusterTemplateInstanceReconciler) startChartTests(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) {
+	releaseName, namespace := getClusterDefinitionRelease(clusterTemplateInstance)
+	now := metav1.Now()
+	clusterTemplateInstance.Status.ChartTests = &v1alpha1.ChartTestsStatus{
+		Phase:     v1alpha1.TestsRunning,
+		Namespace: namespace,
+		StartTime: &now,
+	}
+
+	pods, err := r.renderChartTests(ctx, clusterTemplateInstance, releaseName, namespace)
+	if err != nil {
+		setChartTestsResult(
+			clusterTemplateInstance,
+			v1alpha1.TestsFailed,
+			fmt.Sprintf("Failed to render chart tests - %q", err),
+		)
+		return
+	}
+	if len(pods) == 0 {
+		setChartTestsResult(
+			clusterTemplateInstance,
+			v1alpha1.TestsSucceeded,
+			"Chart defines no tests",
+		)
+		return
+	}
+
+	for _, pod := range pods {
+		test := v1alpha1.ChartTestStatus{
+			Name:  pod.Name,
+			Phase: v1alpha1.TestsRunning,
+		}
+		// like the default 'before-hook-creation' delete policy of Helm
+		if err := r.Client.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+			test.Phase = v1alpha1.TestsFailed
+			test.Message = fmt.Sprintf("Failed to delete previous test pod - %q", err)
+		} else if err := r.Client.Create(ctx, pod); err != nil {
+			test.Phase = v1alpha1.TestsFailed
+			test.Message = fmt.Sprintf("Failed to create test pod - %q", err)
+		}
+		clusterTemplateInstance.Status.ChartTests.Tests = append(
+			clusterTemplateInstance.Status.ChartTests.Tests,
+			test,
+		)
+	}
+	clusterTemplateInstance.SetChartTestsSucceededCondition(
+		metav1.ConditionFalse,
+		v1alpha1.ChartTestsInProgress,
+		"Chart tests are running",
+	)
+	updateChartTestsPhase(clusterTemplateInstance)
+}
+
+// renderChartTests renders pods of the chart test hooks with the values of the instance
+func (r *ClusterTemplateInstanceReconciler) renderChartTests(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	releaseName string,
+	namespace string,
+) ([]*corev1.Pod, error) {
+	ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+	helmChart, err := r.getClusterDefinitionChart(ctx, ctSpec)
+	if err != nil {
+		return nil, err
+	}
+	valuesFrom, err := clusterTemplateInstance.GetValuesFrom(ctx, r.Client, "")
+	if err != nil {
+		return nil, err
+	}
+	values, err := clusterTemplateInstance.GetClusterDefinitionValues(valuesFrom)
+	if err != nil {
+		return nil, err
+	}
+	hooks, err := helm.RenderTestHooks(helmChart, releaseName, namespace, values)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := []*corev1.Pod{}
+	for _, hook := range hooks {
+		if hook.Kind != "Pod" {
+			return nil, fmt.Errorf(
+				"test hook %s is %s, only Pod test hooks are supported",
+				hook.Name,
+				hook.Kind,
+			)
+		}
+		pod := &corev1.Pod{}
+		if err := yaml.Unmarshal([]byte(hook.Manifest), pod); err != nil {
+			return nil, fmt.Errorf("failed to parse test hook %s - %q", hook.Name, err)
+		}
+		if err := validateChartTestPod(pod); err != nil {
+			return nil, fmt.Errorf("test hook %s is not allowed - %s", hook.Name, err)
+		}
+		pod.Namespace = namespace
+		// the pods run in a hub namespace, they do not get a token of the hub API
+		pod.Spec.AutomountServiceAccountToken = pointer.Bool(false)
+		if pod.Labels == nil {
+			pod.Labels = map[string]string{}
+		}
+		pod.Labels[v1alpha1.CTINameLabel] = clusterTemplateInstance.Name
+		pod.Labels[v1alpha1.CTINamespaceLabel] = clusterTemplateInstance.Namespace
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+// validateChartTestPod rejects test pods which could gain privileges on the hub - the operator
+// creates the pods on behalf of the instance, so they run with the default service account of
+// the namespace, without host access and without privileged containers
+func validateChartTestPod(pod *corev1.Pod) error {
+	spec := pod.Spec
+	if (spec.ServiceAccountName != "" && spec.ServiceAccountName != "default") ||
+		(spec.DeprecatedServiceAccount != "" && spec.DeprecatedServiceAccount != "default") {
+		return fmt.Errorf("serviceAccountName can not be set")
+	}
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		return fmt.Errorf("host namespaces can not be used")
+	}
+	if spec.NodeName != "" {
+		return fmt.Errorf("nodeName can not be set")
+	}
+	for _, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			return fmt.Errorf("hostPath volume %s can not be used", volume.Name)
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ServiceAccountToken != nil {
+					return fmt.Errorf(
+						"service account token volume %s can not be used",
+						volume.Name,
+					)
+				}
+			}
+		}
+	}
+	containers := append([]corev1.Container{}, spec.InitContainers...)
+	for _, container := range append(containers, spec.Containers...) {
+		securityContext := container.SecurityContext
+		if securityContext == nil {
+			continue
+		}
+		if securityContext.Privileged != nil && *securityContext.Privileged {
+			return fmt.Errorf("container %s can not be privileged", container.Name)
+		}
+		if securityContext.Capabilities != nil && len(securityContext.Capabilities.Add) > 0 {
+			return fmt.Errorf("container %s can not add capabilities", container.Name)
+		}
+	}
+	return nil
+}
+
+// checkChartTests records results of finished test pods and deletes them. Tests which do not
+// finish within the timeout are failed.
+func (r *ClusterTemplateInstanceReconciler) checkChartTests(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	tests := clusterTemplateInstance.Status.ChartTests
+	timeout := defaultChartTestsTimeout
+	if t := clusterTemplateInstance.Status.ClusterTemplateSpec.ChartTests.Timeout; t != nil {
+		timeout = t.Duration
+	}
+	timedOut := tests.StartTime != nil && time.Since(tests.StartTime.Time) > timeout
+
+	for i := range tests.Tests {
+		test := &tests.Tests[i]
+		if test.Phase != v1alpha1.TestsRunning {
+			continue
+		}
+		pod := &corev1.Pod{}
+		if err := r.Client.Get(
+			ctx,
+			client.ObjectKey{Name: test.Name, Namespace: tests.Namespace},
+			pod,
+		); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			test.Phase = v1alpha1.TestsFailed
+			test.Message = "Test pod was deleted"
+			continue
+		}
+		switch pod.Status.Phase {
+		case corev1.PodSucceeded:
+			test.Phase = v1alpha1.TestsSucceeded
+		case corev1.PodFailed:
+			test.Phase = v1alpha1.TestsFailed
+			test.Message = pod.Status.Message
+		default:
+			if !timedOut {
+				continue
+			}
+			test.Phase = v1alpha1.TestsFailed
+			test.Message = fmt.Sprintf("Test did not finish within %s", timeout)
+		}
+		test.Log = r.getChartTestLog(ctx, pod)
+		if err := r.Client.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	updateChartTestsPhase(clusterTemplateInstance)
+	return nil
+}
+
+// getChartTestLog returns tail of the test pod log
+func (r *ClusterTemplateInstanceReconciler) getChartTestLog(
+	ctx context.Context,
+	pod *corev1.Pod,
+) string {
+	if r.KubeClient == nil {
+		return ""
+	}
+	lines := int64(chartTestLogLines)
+	limit := int64(chartTestLogBytes)
+	log, err := r.KubeClient.CoreV1().Pods(pod.Namespace).GetLogs(
+		pod.Name,
+		&corev1.PodLogOptions{TailLines: &lines, LimitBytes: &limit},
+	).DoRaw(ctx)
+	if err != nil {
+		return fmt.Sprintf("Failed to get test pod log - %q", err)
+	}
+	return string(log)
+}
+
+// updateChartTestsPhase sets the result of all the tests once none of them is running
+func updateChartTestsPhase(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) {
+	failed := 0
+	for _, test := range clusterTemplateInstance.Status.ChartTests.Tests {
+		switch test.Phase {
+		case v1alpha1.TestsRunning:
+			return
+		case v1alpha1.TestsFailed:
+			failed++
+		}
+	}
+	total := len(clusterTemplateInstance.Status.ChartTests.Tests)
+	if failed > 0 {
+		setChartTestsResult(
+			clusterTemplateInstance,
+			v1alpha1.TestsFailed,
+			fmt.Sprintf("%d of %d chart tests failed", failed, total),
+		)
+		return
+	}
+	setChartTestsResult(
+		clusterTemplateInstance,
+		v1alpha1.TestsSucceeded,
+		fmt.Sprintf("%d chart tests passed", total),
+	)
+}
+
+func setChartTestsResult(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	phase v1alpha1.ChartTestsPhase,
+	message string,
+) {
+	clusterTemplateInstance.Status.ChartTests.Phase = phase
+	clusterTemplateInstance.Status.ChartTests.Message = message
+	switch {
+	case phase == v1alpha1.TestsFailed:
+		clusterTemplateInstance.SetChartTestsSucceededCondition(
+			metav1.ConditionFalse,
+			v1alpha1.ChartTestsNotPassed,
+			message,
+		)
+	case len(clusterTemplateInstance.Status.ChartTests.Tests) == 0:
+		clusterTemplateInstance.SetChartTestsSucceededCondition(
+			metav1.ConditionTrue,
+			v1alpha1.NoChartTests,
+			message,
+		)
+	default:
+		clusterTemplateInstance.SetChartTestsSucceededCondition(
+			metav1.ConditionTrue,
+			v1alpha1.ChartTestsPassed,
+			message,
+		)
+	}
+}
+
+// chartTestsRunning returns true if the instance waits for test pods to finish
+func chartTestsRunning(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) bool {
+	tests := clusterTemplateInstance.Status.ChartTests
+	return tests != nil && tests.Phase == v1alpha1.TestsRunning
+}
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"context"
+	"net/http/httptest"
+	"time"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/helm"
+	helmserver "github.com/stolostron/cluster-templates-operator/testutils/helm"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance chart tests", func() {
+	var server *httptest.Server
+	var cti *v1alpha1.ClusterTemplateInstance
+
+	BeforeEach(func() {
+		server = helmserver.StartHelmRepoServer()
+		cti = &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					ClusterDefinition: argo.ApplicationSpec{
+						Source: argo.ApplicationSource{
+							RepoURL:        server.URL,
+							Chart:          "hypershift-template",
+							TargetRevision: "0.0.2",
+						},
+						Destination: argo.ApplicationDestination{
+							Namespace: v1alpha1.CTIInstanceNamespaceVar,
+						},
+					},
+					ChartTests: &v1alpha1.ChartTests{},
+				},
+			},
+		}
+		SetDefaultConditions(cti)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Waits for the cluster to be installed", func() {
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme),
+		}
+		Expect(reconciler.reconcileChartTests(context.TODO(), cti)).Should(Succeed())
+		Expect(cti.Status.ChartTests).Should(BeNil())
+	})
+
+	It("Succeeds for chart without tests", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client:     k8sClient,
+			HelmClient: helm.NewHelmClient(cfg, k8sClient, nil, nil, nil),
+		}
+		cti.SetClusterInstallCondition(
+			metav1.ConditionTrue,
+			v1alpha1.ClusterInstalled,
+			"Cluster is installed",
+		)
+		Expect(reconciler.reconcileChartTests(context.TODO(), cti)).Should(Succeed())
+		Expect(cti.Status.ChartTests.Phase).Should(Equal(v1alpha1.TestsSucceeded))
+		Expect(cti.Status.ChartTests.Namespace).Should(Equal("default"))
+		condition := meta.FindStatusCondition(
+			cti.Status.Conditions,
+			string(v1alpha1.ChartTestsSucceeded),
+		)
+		Expect(condition.Reason).Should(Equal(string(v1alpha1.NoChartTests)))
+		Expect(chartTestsRunning(cti)).Should(BeFalse())
+	})
+
+	It("Records results of finished tests", func() {
+		testPod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Status:     corev1.PodStatus{Phase: phase, Message: "exit code 1"},
+			}
+		}
+		passed := testPod("passed", corev1.PodSucceeded)
+		failed := testPod("failed", corev1.PodFailed)
+		running := testPod("running", corev1.PodRunning)
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, passed, failed, running)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		cti.SetClusterInstallCondition(
+			metav1.ConditionTrue,
+			v1alpha1.ClusterInstalled,
+			"Cluster is installed",
+		)
+		now := metav1.Now()
+		cti.Status.ChartTests = &v1alpha1.ChartTestsStatus{
+			Phase:     v1alpha1.TestsRunning,
+			Namespace: "default",
+			StartTime: &now,
+			Tests: []v1alpha1.ChartTestStatus{
+				{Name: "passed", Phase: v1alpha1.TestsRunning},
+				{Name: "failed", Phase: v1alpha1.TestsRunning},
+				{Name: "running", Phase: v1alpha1.TestsRunning},
+			},
+		}
+
+		Expect(reconciler.reconcileChartTests(context.TODO(), cti)).Should(Succeed())
+		Expect(chartTestsRunning(cti)).Should(BeTrue())
+		tests := cti.Status.ChartTests.Tests
+		Expect(tests[0].Phase).Should(Equal(v1alpha1.TestsSucceeded))
+		Expect(tests[1].Phase).Should(Equal(v1alpha1.TestsFailed))
+		Expect(tests[1].Message).Should(Equal("exit code 1"))
+		Expect(tests[2].Phase).Should(Equal(v1alpha1.TestsRunning))
+		err := k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(passed), &corev1.Pod{})
+		Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+
+		cti.Status.ChartTests.StartTime = &metav1.Time{Time: now.Add(-time.Hour)}
+		Expect(reconciler.reconcileChartTests(context.TODO(), cti)).Should(Succeed())
+		Expect(cti.Status.ChartTests.Phase).Should(Equal(v1alpha1.TestsFailed))
+		Expect(cti.Status.ChartTests.Message).Should(Equal("2 of 3 chart tests failed"))
+		Expect(cti.Status.ChartTests.Tests[2].Message).Should(ContainSubstring("did not finish"))
+		condition := meta.FindStatusCondition(
+			cti.Status.Conditions,
+			string(v1alpha1.ChartTestsSucceeded),
+		)
+		Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+	})
+
+	It("Rejects privileged test pods", func() {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "test", Image: "busybox"}},
+			},
+		}
+		Expect(validateChartTestPod(pod)).Should(Succeed())
+
+		privileged := pod.DeepCopy()
+		privileged.Spec.ServiceAccountName = "operator"
+		Expect(validateChartTestPod(privileged)).Should(MatchError(
+			"serviceAccountName can not be set",
+		))
+
+		privileged = pod.DeepCopy()
+		privileged.Spec.HostNetwork = true
+		Expect(validateChartTestPod(privileged)).Should(MatchError(
+			"host namespaces can not be used",
+		))
+
+		privileged = pod.DeepCopy()
+		privileged.Spec.Volumes = []corev1.Volume{{
+			Name: "root",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{Path: "/"},
+			},
+		}}
+		Expect(validateChartTestPod(privileged)).Should(MatchError(
+			"hostPath volume root can not be used",
+		))
+
+		privileged = pod.DeepCopy()
+		privileged.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{
+			Privileged: pointer.Bool(true),
+		}
+		Expect(validateChartTestPod(privileged)).Should(MatchError(
+			"container test can not be privileged",
+		))
+	})
+})
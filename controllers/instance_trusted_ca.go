@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/hubversion"
+)
+
+// copyTrustedCABundle copies the additional CA bundle trusted by the hub into a ConfigMap in the
+// namespace of the instance, where it is read from when the cluster definition values are
+// composed. The ConfigMap is created even if the hub does not trust additional CAs, so the
+// bundle is not injected then. The copy is refreshed whenever the cluster definition is created
+// or previewed and deleted together with the instance.
+func (r *ClusterTemplateInstanceReconciler) copyTrustedCABundle(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	if clusterTemplateInstance.Status.ClusterTemplateSpec.TrustedCABundle == nil {
+		return nil
+	}
+	bundle, err := hubversion.GetTrustedCABundle(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterTemplateInstance.GetTrustedCABundleRef(),
+			Namespace: clusterTemplateInstance.Namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		cm.OwnerReferences = []metav1.OwnerReference{
+			clusterTemplateInstance.GetOwnerReference(),
+		}
+		cm.Data = map[string]string{v1alpha1.TrustedCABundleKey: bundle}
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/hubversion"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance trusted CA bundle", func() {
+	It("Copies CA bundle trusted by the hub into the instance namespace", func() {
+		cti := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					TrustedCABundle: &v1alpha1.TrustedCABundle{
+						ValuesKey: "additionalTrustBundle",
+					},
+				},
+			},
+		}
+		proxy := &unstructured.Unstructured{}
+		proxy.SetGroupVersionKind(hubversion.ProxyConfigGVK)
+		proxy.SetName("cluster")
+		Expect(unstructured.SetNestedField(
+			proxy.Object,
+			"user-ca-bundle",
+			"spec",
+			"trustedCA",
+			"name",
+		)).Should(Succeed())
+		k8sClient := fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			proxy,
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "user-ca-bundle",
+					Namespace: "openshift-config",
+				},
+				Data: map[string]string{
+					v1alpha1.TrustedCABundleKey: "-----BEGIN CERTIFICATE-----",
+				},
+			},
+		)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		Expect(reconciler.copyTrustedCABundle(context.TODO(), cti)).Should(Succeed())
+
+		cm := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: cti.GetTrustedCABundleRef(), Namespace: cti.Namespace},
+			cm,
+		)).Should(Succeed())
+		Expect(cm.Data[v1alpha1.TrustedCABundleKey]).Should(Equal("-----BEGIN CERTIFICATE-----"))
+		Expect(cm.OwnerReferences).Should(HaveLen(1))
+	})
+})
@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
+)
+
+const (
+	releaseImagePointer            = "/spec/release/image"
+	respectIgnoreDifferencesOption = "RespectIgnoreDifferences=true"
+)
+
+// reconcileClusterUpgrade rolls out the release image requested by spec.upgrade to an installed
+// hypershift cluster. Progress is tracked in status.upgrade and in the ClusterUpgraded condition.
+func (r *ClusterTemplateInstanceReconciler) reconcileClusterUpgrade(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	upgrade := clusterTemplateInstance.Spec.Upgrade
+	if upgrade == nil {
+		clusterTemplateInstance.Status.Upgrade = nil
+		meta.RemoveStatusCondition(
+			&clusterTemplateInstance.Status.Conditions,
+			string(v1alpha1.ClusterUpgraded),
+		)
+		return nil
+	}
+	if !isClusterInstalled(clusterTemplateInstance) {
+		return nil
+	}
+	releaseImage := upgrade.GetReleaseImage()
+	upgradeStatus := clusterTemplateInstance.Status.Upgrade
+	if upgradeStatus != nil && upgradeStatus.ReleaseImage == releaseImage &&
+		upgradeStatus.Phase == v1alpha1.UpgradeCompleted {
+		return nil
+	}
+
+	app, err := clusterTemplateInstance.GetDay1Application(ctx, r.Client, ArgoCDNamespace)
+	if err != nil {
+		return err
+	}
+	clusterProvider, err := r.getClusterProvider(ctx, clusterTemplateInstance, app)
+	if err != nil {
+		return err
+	}
+	provider, ok := clusterProvider.(clusterprovider.HostedClusterProvider)
+	if !ok {
+		clusterTemplateInstance.Status.Upgrade = &v1alpha1.ClusterUpgradeStatus{
+			ReleaseImage: releaseImage,
+			Phase:        v1alpha1.UpgradeFailed,
+			Message:      "Upgrade is supported for hypershift clusters only",
+		}
+		setClusterUpgradedCondition(clusterTemplateInstance)
+		return nil
+	}
+
+	if err := ignoreReleaseImageDifferences(ctx, r.Client, app); err != nil {
+		return err
+	}
+
+	CTIlog.Info(
+		"Upgrade cluster",
+		"name",
+		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		"releaseImage",
+		releaseImage,
+	)
+	status, err := provider.Upgrade(ctx, r.Client, releaseImage)
+	if err != nil {
+		clusterTemplateInstance.SetClusterUpgradedCondition(
+			metav1.ConditionFalse,
+			v1alpha1.UpgradeNotSucceeded,
+			fmt.Sprintf("Failed to upgrade cluster to %s - %q", releaseImage, err),
+		)
+		return err
+	}
+	clusterTemplateInstance.Status.Upgrade = status
+	setClusterUpgradedCondition(clusterTemplateInstance)
+	return nil
+}
+
+// setClusterUpgradedCondition reflects the phase of the upgrade in the ClusterUpgraded condition
+func setClusterUpgradedCondition(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance) {
+	status := clusterTemplateInstance.Status.Upgrade
+	switch status.Phase {
+	case v1alpha1.UpgradeCompleted:
+		clusterTemplateInstance.SetClusterUpgradedCondition(
+			metav1.ConditionTrue,
+			v1alpha1.UpgradeSucceeded,
+			status.Message,
+		)
+	case v1alpha1.UpgradeFailed:
+		clusterTemplateInstance.SetClusterUpgradedCondition(
+			metav1.ConditionFalse,
+			v1alpha1.UpgradeNotSucceeded,
+			status.Message,
+		)
+	default:
+		clusterTemplateInstance.SetClusterUpgradedCondition(
+			metav1.ConditionFalse,
+			v1alpha1.UpgradeInProgress,
+			status.Message,
+		)
+	}
+}
+
+// ignoreReleaseImageDifferences makes ArgoCD ignore the release image set by the upgrade,
+// otherwise the next sync of the application would revert it
+func ignoreReleaseImageDifferences(
+	ctx context.Context,
+	k8sClient client.Client,
+	app *argo.Application,
+) error {
+	return ignoreHypershiftDifferences(
+		ctx,
+		k8sClient,
+		app,
+		releaseImagePointer,
+		"HostedCluster",
+		"NodePool",
+	)
+}
+
+// ignoreHypershiftDifferences makes ArgoCD ignore the field of the hypershift resources of the
+// kinds, which is managed by the operator
+func ignoreHypershiftDifferences(
+	ctx context.Context,
+	k8sClient client.Client,
+	app *argo.Application,
+	jsonPointer string,
+	kinds ...string,
+) error {
+	changed := false
+	for _, kind := range kinds {
+		found := false
+		for _, ignore := range app.Spec.IgnoreDifferences {
+			if ignore.Group != v1alpha1.HostedClusterGVK.Group || ignore.Kind != kind {
+				continue
+			}
+			for _, pointer := range ignore.JSONPointers {
+				if pointer == jsonPointer {
+					found = true
+				}
+			}
+		}
+		if !found {
+			app.Spec.IgnoreDifferences = append(
+				app.Spec.IgnoreDifferences,
+				argo.ResourceIgnoreDifferences{
+					Group:        v1alpha1.HostedClusterGVK.Group,
+					Kind:         kind,
+					JSONPointers: []string{jsonPointer},
+				},
+			)
+			changed = true
+		}
+	}
+
+	if app.Spec.SyncPolicy == nil {
+		app.Spec.SyncPolicy = &argo.SyncPolicy{}
+	}
+	if !app.Spec.SyncPolicy.SyncOptions.HasOption(respectIgnoreDifferencesOption) {
+		app.Spec.SyncPolicy.SyncOptions = app.Spec.SyncPolicy.SyncOptions.AddOption(
+			respectIgnoreDifferencesOption,
+		)
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return k8sClient.Update(ctx, app)
+}
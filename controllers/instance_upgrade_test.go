@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Cluster upgrade", func() {
+	It("Upgrades the cluster to the requested version", func() {
+		cti := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "bar",
+			},
+			Spec: v1alpha1.ClusterTemplateInstanceSpec{
+				Upgrade: &v1alpha1.ClusterUpgrade{Version: "4.12.1"},
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{},
+			},
+		}
+		cti.SetClusterInstallCondition(
+			metav1.ConditionTrue,
+			v1alpha1.ClusterInstalled,
+			"Cluster installed",
+		)
+		app := &argo.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cti.GetDay1ApplicationName(),
+				Namespace: ArgoCDNamespace,
+				Labels: map[string]string{
+					v1alpha1.CTINameLabel:      cti.Name,
+					v1alpha1.CTINamespaceLabel: cti.Namespace,
+				},
+			},
+			Status: argo.ApplicationStatus{
+				Resources: []argo.ResourceStatus{
+					{
+						Group:     v1alpha1.HostedClusterGVK.Group,
+						Version:   v1alpha1.HostedClusterGVK.Version,
+						Kind:      v1alpha1.HostedClusterGVK.Resource,
+						Name:      "foo",
+						Namespace: "clusters",
+					},
+				},
+			},
+		}
+		hostedCluster := &hypershiftv1alpha1.HostedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "clusters",
+			},
+			Spec: hypershiftv1alpha1.HostedClusterSpec{
+				Release: hypershiftv1alpha1.Release{
+					Image: "quay.io/openshift-release-dev/ocp-release:4.11.0-x86_64",
+				},
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, app, hostedCluster)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		Expect(reconciler.reconcileClusterUpgrade(context.TODO(), cti)).Should(Succeed())
+
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKeyFromObject(hostedCluster),
+			hostedCluster,
+		)).Should(Succeed())
+		Expect(hostedCluster.Spec.Release.Image).Should(Equal(
+			"quay.io/openshift-release-dev/ocp-release:4.12.1-x86_64",
+		))
+		Expect(cti.Status.Upgrade.Phase).Should(Equal(v1alpha1.UpgradeProgressing))
+		condition := meta.FindStatusCondition(
+			cti.Status.Conditions,
+			string(v1alpha1.ClusterUpgraded),
+		)
+		Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).Should(Equal(string(v1alpha1.UpgradeInProgress)))
+
+		cti.Spec.Upgrade = nil
+		Expect(reconciler.reconcileClusterUpgrade(context.TODO(), cti)).Should(Succeed())
+		Expect(meta.FindStatusCondition(
+			cti.Status.Conditions,
+			string(v1alpha1.ClusterUpgraded),
+		)).Should(BeNil())
+	})
+})
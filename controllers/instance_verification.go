@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/helm"
+)
+
+// key of the ConfigMap referenced by spec.chartVerification of the template holding the public keys
+const chartPublicKeysKey = "publicKeys"
+
+// verifyChart checks the Helm chart of the application source (of the cluster setup, empty
+// setupName for the cluster definition) was signed by one of the keys trusted by the template. The
+// verified chart archive is stored in an embedded chart Secret of the ArgoCD namespace and the
+// source is pointed to the repository of the operator serving it, so ArgoCD installs the verified
+// archive instead of downloading the chart again. Digest of the verified chart is recorded in the
+// status. Does nothing if the template does not require chart verification or the source already
+// points to the verified chart.
+func (r *ClusterTemplateInstanceReconciler) verifyChart(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	setupName string,
+	source *argo.ApplicationSource,
+) error {
+	ctSpec := clusterTemplateInstance.Status.ClusterTemplateSpec
+	if ctSpec.ChartVerification == nil {
+		return nil
+	}
+	if source.Chart == "" {
+		return fmt.Errorf("chart verification requires Helm chart source")
+	}
+	secretName := clusterTemplateInstance.GetVerifiedChartRef(setupName)
+	verifiedRepoURL := helm.GetEmbeddedChartRepoURL(
+		EmbeddedChartsURL,
+		helm.EmbeddedChartSecret,
+		secretName,
+	)
+	if source.RepoURL == verifiedRepoURL {
+		return nil
+	}
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{
+			Name:      ctSpec.ChartVerification.PublicKeysConfigMap,
+			Namespace: ArgoCDNamespace,
+		},
+		cm,
+	); err != nil {
+		return fmt.Errorf("failed to get public keys - %q", err)
+	}
+	publicKeys := cm.Data[chartPublicKeysKey]
+	if publicKeys == "" {
+		return fmt.Errorf(
+			"ConfigMap %s does not contain public keys under key '%s'",
+			cm.Name,
+			chartPublicKeysKey,
+		)
+	}
+	data, digest, err := r.HelmClient.VerifyChart(
+		ctx,
+		r.Client,
+		source.RepoURL,
+		source.Chart,
+		source.TargetRevision,
+		ArgoCDNamespace,
+		HelmCABundle,
+		[]byte(publicKeys),
+	)
+	if err != nil {
+		return err
+	}
+
+	// deleted together with the other ArgoCD Secrets of the instance
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: ArgoCDNamespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		secret.Labels = map[string]string{
+			helm.EmbeddedChartLabel:    "true",
+			v1alpha1.CTINameLabel:      clusterTemplateInstance.Name,
+			v1alpha1.CTINamespaceLabel: clusterTemplateInstance.Namespace,
+		}
+		secret.Data = map[string][]byte{helm.EmbeddedChartKey: data}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to store verified chart - %q", err)
+	}
+	setVerifiedChart(clusterTemplateInstance, v1alpha1.VerifiedChart{
+		ClusterSetup: setupName,
+		Chart:        source.Chart,
+		Version:      source.TargetRevision,
+		Digest:       digest,
+	})
+	source.RepoURL = verifiedRepoURL
+	return nil
+}
+
+// setVerifiedChart records the verified chart in the status, replacing the previous verification
+// of the same application
+func setVerifiedChart(
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	verifiedChart v1alpha1.VerifiedChart,
+) {
+	for i, chart := range clusterTemplateInstance.Status.VerifiedCharts {
+		if chart.ClusterSetup == verifiedChart.ClusterSetup {
+			clusterTemplateInstance.Status.VerifiedCharts[i] = verifiedChart
+			return
+		}
+	}
+	clusterTemplateInstance.Status.VerifiedCharts = append(
+		clusterTemplateInstance.Status.VerifiedCharts,
+		verifiedChart,
+	)
+}
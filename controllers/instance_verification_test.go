@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"os"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/helm"
+	helmserver "github.com/stolostron/cluster-templates-operator/testutils/helm"
+	"helm.sh/helm/v3/pkg/provenance"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Chart verification", func() {
+	var server *httptest.Server
+	var cti *v1alpha1.ClusterTemplateInstance
+
+	getPublicKeysCM := func(file string) *corev1.ConfigMap {
+		publicKeys, err := os.ReadFile(file)
+		Expect(err).ShouldNot(HaveOccurred())
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "chart-keys",
+				Namespace: ArgoCDNamespace,
+			},
+			Data: map[string]string{chartPublicKeysKey: string(publicKeys)},
+		}
+	}
+
+	BeforeEach(func() {
+		server = helmserver.StartHelmRepoServer()
+		cti = &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				ClusterTemplateSpec: &v1alpha1.ClusterTemplateSpec{
+					ClusterDefinition: argo.ApplicationSpec{
+						Source: argo.ApplicationSource{
+							RepoURL:        server.URL,
+							Chart:          "hypershift-template",
+							TargetRevision: "0.0.2",
+						},
+					},
+					ChartVerification: &v1alpha1.ChartVerification{
+						PublicKeysConfigMap: "chart-keys",
+					},
+				},
+			},
+		}
+		SetDefaultConditions(cti)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Creates cluster definition from verified chart", func() {
+		k8sClient := fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			cti,
+			getPublicKeysCM("../testutils/helm/provenance-public-keys.asc"),
+		)
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client:     k8sClient,
+			HelmClient: helm.NewHelmClient(cfg, k8sClient, nil, nil, nil),
+		}
+		Expect(reconciler.reconcileClusterCreate(context.TODO(), cti)).Should(Succeed())
+		Expect(meta.IsStatusConditionTrue(
+			cti.Status.Conditions,
+			string(v1alpha1.ClusterDefinitionCreated),
+		)).Should(BeTrue())
+		Expect(cti.Status.VerifiedCharts).Should(HaveLen(1))
+		Expect(cti.Status.VerifiedCharts[0].Chart).Should(Equal("hypershift-template"))
+		Expect(cti.Status.VerifiedCharts[0].Version).Should(Equal("0.0.2"))
+		Expect(cti.Status.VerifiedCharts[0].Digest).Should(HavePrefix("sha256:"))
+
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: "default-foo-verified-chart", Namespace: ArgoCDNamespace},
+			secret,
+		)).Should(Succeed())
+		Expect(secret.Labels[helm.EmbeddedChartLabel]).Should(Equal("true"))
+		digest, err := provenance.Digest(bytes.NewReader(secret.Data[helm.EmbeddedChartKey]))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect("sha256:" + digest).Should(Equal(cti.Status.VerifiedCharts[0].Digest))
+
+		app, err := cti.GetDay1Application(context.TODO(), k8sClient, ArgoCDNamespace)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(app.Spec.Source.RepoURL).Should(Equal(
+			EmbeddedChartsURL + "/secrets/default-foo-verified-chart",
+		))
+		Expect(app.Spec.Source.Chart).Should(Equal("hypershift-template"))
+		Expect(app.Spec.Source.TargetRevision).Should(Equal("0.0.2"))
+	})
+
+	It("Does not verify the verified chart again", func() {
+		server.Close()
+		cti.Status.ClusterTemplateSpec.ClusterDefinition.Source.RepoURL =
+			EmbeddedChartsURL + "/secrets/default-foo-verified-chart"
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		Expect(reconciler.verifyChart(
+			context.TODO(),
+			cti,
+			"",
+			&cti.Status.ClusterTemplateSpec.ClusterDefinition.Source,
+		)).Should(Succeed())
+	})
+
+	It("Refuses chart signed by untrusted key", func() {
+		k8sClient := fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			cti,
+			getPublicKeysCM("../testutils/helm/provenance-untrusted-keys.asc"),
+		)
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client:     k8sClient,
+			HelmClient: helm.NewHelmClient(cfg, k8sClient, nil, nil, nil),
+		}
+		Expect(reconciler.reconcileClusterCreate(context.TODO(), cti)).ShouldNot(Succeed())
+		condition := meta.FindStatusCondition(
+			cti.Status.Conditions,
+			string(v1alpha1.ClusterDefinitionCreated),
+		)
+		Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).Should(Equal(string(v1alpha1.ChartVerificationFailed)))
+
+		_, err := cti.GetDay1Application(context.TODO(), k8sClient, ArgoCDNamespace)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("Fails without public keys", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		err := reconciler.verifyChart(
+			context.TODO(),
+			cti,
+			"",
+			&cti.Status.ClusterTemplateSpec.ClusterDefinition.Source,
+		)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("failed to get public keys"))
+	})
+})
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// reconcileInstanceView creates or updates the ClusterTemplateInstanceView of the instance in
+// the shared namespace, if it is configured. Failures do not affect the instance.
+func (r *ClusterTemplateInstanceReconciler) reconcileInstanceView(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) {
+	if InstanceViewsNamespace == "" {
+		return
+	}
+	view := &v1alpha1.ClusterTemplateInstanceView{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterTemplateInstance.GetViewName(),
+			Namespace: InstanceViewsNamespace,
+		},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, view, func() error {
+		if view.Labels == nil {
+			view.Labels = map[string]string{}
+		}
+		view.Labels[v1alpha1.CTINameLabel] = clusterTemplateInstance.Name
+		view.Labels[v1alpha1.CTINamespaceLabel] = clusterTemplateInstance.Namespace
+		clusterTemplateInstance.SetStatusLabels(view)
+		view.Status = clusterTemplateInstance.GetViewStatus()
+		return nil
+	}); err != nil {
+		CTIlog.Error(
+			err,
+			"Failed to update instance view",
+			"name",
+			clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		)
+	}
+}
+
+// reconcileStatusLabels projects the status of the instance onto its labels. The status is
+// updated through the status subresource, which ignores changes of the labels, so the labels are
+// patched separately. Failures do not affect the instance.
+func (r *ClusterTemplateInstanceReconciler) reconcileStatusLabels(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) {
+	patch := client.MergeFrom(clusterTemplateInstance.DeepCopy())
+	if !clusterTemplateInstance.SetStatusLabels(clusterTemplateInstance) {
+		return
+	}
+	if err := r.Patch(ctx, clusterTemplateInstance, patch); err != nil {
+		CTIlog.Error(
+			err,
+			"Failed to update status labels",
+			"name",
+			clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		)
+	}
+}
+
+// deleteInstanceView deletes the ClusterTemplateInstanceView of the deleted instance. The view
+// lives in another namespace than the instance, so it can not be garbage collected.
+func (r *ClusterTemplateInstanceReconciler) deleteInstanceView(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	if InstanceViewsNamespace == "" {
+		return nil
+	}
+	view := &v1alpha1.ClusterTemplateInstanceView{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterTemplateInstance.GetViewName(),
+			Namespace: InstanceViewsNamespace,
+		},
+	}
+	return client.IgnoreNotFound(r.Client.Delete(ctx, view))
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Instance views", func() {
+	var cti *v1alpha1.ClusterTemplateInstance
+
+	BeforeEach(func() {
+		cti = &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "aws-small",
+			},
+			Status: v1alpha1.ClusterTemplateInstanceStatus{
+				Phase: v1alpha1.ClusterInstallingPhase,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		InstanceViewsNamespace = ""
+	})
+
+	It("Does not maintain views unless configured", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		reconciler.reconcileInstanceView(context.TODO(), cti)
+		views := &v1alpha1.ClusterTemplateInstanceViewList{}
+		Expect(k8sClient.List(context.TODO(), views)).Should(Succeed())
+		Expect(views.Items).Should(BeEmpty())
+	})
+
+	It("Maintains view in the shared namespace", func() {
+		InstanceViewsNamespace = "views"
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		reconciler.reconcileInstanceView(context.TODO(), cti)
+
+		view := &v1alpha1.ClusterTemplateInstanceView{}
+		key := client.ObjectKey{Name: "default-foo", Namespace: "views"}
+		Expect(k8sClient.Get(context.TODO(), key, view)).Should(Succeed())
+		Expect(view.Labels[v1alpha1.CTINameLabel]).Should(Equal("foo"))
+		Expect(view.Labels[v1alpha1.CTINamespaceLabel]).Should(Equal("default"))
+		Expect(view.Status.ClusterTemplate).Should(Equal("aws-small"))
+		Expect(view.Status.Phase).Should(Equal(v1alpha1.ClusterInstallingPhase))
+
+		cti.Status.Phase = v1alpha1.ReadyPhase
+		reconciler.reconcileInstanceView(context.TODO(), cti)
+		Expect(k8sClient.Get(context.TODO(), key, view)).Should(Succeed())
+		Expect(view.Status.Phase).Should(Equal(v1alpha1.ReadyPhase))
+		Expect(view.Labels[v1alpha1.CTIPhaseLabel]).Should(Equal("Ready"))
+		Expect(view.Labels[v1alpha1.CTITemplateLabel]).Should(Equal("aws-small"))
+
+		Expect(reconciler.deleteInstanceView(context.TODO(), cti)).Should(Succeed())
+		err := k8sClient.Get(context.TODO(), key, view)
+		Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+		Expect(reconciler.deleteInstanceView(context.TODO(), cti)).Should(Succeed())
+	})
+
+	It("Projects status onto labels of the instance", func() {
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, cti)
+		reconciler := &ClusterTemplateInstanceReconciler{Client: k8sClient}
+		cti.Status.Phase = v1alpha1.ClusterInstallFailedPhase
+		reconciler.reconcileStatusLabels(context.TODO(), cti)
+
+		instances := &v1alpha1.ClusterTemplateInstanceList{}
+		Expect(k8sClient.List(context.TODO(), instances, client.MatchingLabels{
+			v1alpha1.CTIPhaseLabel:    string(v1alpha1.ClusterInstallFailedPhase),
+			v1alpha1.CTITemplateLabel: "aws-small",
+		})).Should(Succeed())
+		Expect(instances.Items).Should(HaveLen(1))
+	})
+})
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+)
+
+// mapTrackedResourceToInstance maps the cluster resource (ie HostedCluster) to the instance whose
+// cluster definition application tracks it. Unlike matching the resources reported in the
+// application status, it does not wait for ArgoCD to refresh the status, so the instance is
+// reconciled as soon as the resource changes (ie the control plane becomes available). Returns
+// nil if the resource is not tracked by a cluster definition application.
+func (r *ClusterTemplateInstanceReconciler) mapTrackedResourceToInstance(
+	res client.Object,
+) []reconcile.Request {
+	appName := argocd.GetTrackingApplication(res)
+	if appName == "" {
+		return nil
+	}
+	app := &argo.Application{}
+	if err := r.Client.Get(
+		context.TODO(),
+		client.ObjectKey{Name: appName, Namespace: ArgoCDNamespace},
+		app,
+	); err != nil {
+		return nil
+	}
+	name := app.Labels[v1alpha1.CTINameLabel]
+	namespace := app.Labels[v1alpha1.CTINamespaceLabel]
+	if _, setup := app.Labels[v1alpha1.CTISetupLabel]; setup || name == "" || namespace == "" {
+		return nil
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}},
+	}
+}
+
+// mapHostedClusterSecretToInstance maps the secrets owned by a HostedCluster (ie the admin
+// kubeconfig which hypershift rotates) to the instance of the HostedCluster, so the credentials
+// copied to the instance namespace are refreshed without waiting for the next resync.
+func (r *ClusterTemplateInstanceReconciler) mapHostedClusterSecretToInstance(
+	mapHostedCluster func(res client.Object) []reconcile.Request,
+) func(res client.Object) []reconcile.Request {
+	return func(res client.Object) []reconcile.Request {
+		for _, ref := range res.GetOwnerReferences() {
+			if ref.Kind != v1alpha1.HostedClusterGVK.Resource ||
+				!strings.HasPrefix(ref.APIVersion, v1alpha1.HostedClusterGVK.Group+"/") {
+				continue
+			}
+			hostedCluster := &hypershiftv1alpha1.HostedCluster{}
+			if err := r.Client.Get(
+				context.TODO(),
+				client.ObjectKey{Name: ref.Name, Namespace: res.GetNamespace()},
+				hostedCluster,
+			); err != nil {
+				return nil
+			}
+			return mapHostedCluster(hostedCluster)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	hypershiftv1alpha1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/argocd"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Instance watches", func() {
+	It("Maps resources tracked by cluster definition application to the instance", func() {
+		app := &argo.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar-foo",
+				Namespace: ArgoCDNamespace,
+				Labels: map[string]string{
+					v1alpha1.CTINameLabel:      "foo",
+					v1alpha1.CTINamespaceLabel: "bar",
+				},
+			},
+		}
+		setupApp := &argo.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar-foo-setup",
+				Namespace: ArgoCDNamespace,
+				Labels: map[string]string{
+					v1alpha1.CTINameLabel:      "foo",
+					v1alpha1.CTINamespaceLabel: "bar",
+					v1alpha1.CTISetupLabel:     "setup",
+				},
+			},
+		}
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme, app, setupApp),
+		}
+		hostedCluster := &hypershiftv1alpha1.HostedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "clusters",
+				Labels:    map[string]string{argocd.TrackingLabel: "bar-foo"},
+			},
+		}
+		Expect(reconciler.mapTrackedResourceToInstance(hostedCluster)).Should(Equal(
+			[]reconcile.Request{{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "bar"}}},
+		))
+
+		hostedCluster.Labels[argocd.TrackingLabel] = "bar-foo-setup"
+		Expect(reconciler.mapTrackedResourceToInstance(hostedCluster)).Should(BeNil())
+		hostedCluster.Labels[argocd.TrackingLabel] = "unknown"
+		Expect(reconciler.mapTrackedResourceToInstance(hostedCluster)).Should(BeNil())
+		hostedCluster.Labels = nil
+		Expect(reconciler.mapTrackedResourceToInstance(hostedCluster)).Should(BeNil())
+	})
+
+	It("Maps secrets owned by HostedCluster to the instance", func() {
+		hostedCluster := &hypershiftv1alpha1.HostedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "clusters",
+			},
+		}
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme, hostedCluster),
+		}
+		mapped := []client.Object{}
+		mapSecret := reconciler.mapHostedClusterSecretToInstance(
+			func(res client.Object) []reconcile.Request {
+				mapped = append(mapped, res)
+				return []reconcile.Request{
+					{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "bar"}},
+				}
+			},
+		)
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-admin-kubeconfig",
+				Namespace: "clusters",
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: hypershiftv1alpha1.GroupVersion.String(),
+						Kind:       "HostedCluster",
+						Name:       "foo",
+					},
+				},
+			},
+		}
+		Expect(mapSecret(secret)).Should(Equal(
+			[]reconcile.Request{{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "bar"}}},
+		))
+		Expect(mapped).Should(HaveLen(1))
+		Expect(mapped[0].GetName()).Should(Equal("foo"))
+
+		secret.OwnerReferences[0].Name = "unknown"
+		Expect(mapSecret(secret)).Should(BeNil())
+		secret.OwnerReferences = nil
+		Expect(mapSecret(secret)).Should(BeNil())
+		Expect(mapped).Should(HaveLen(1))
+	})
+})
@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"reflect"
+
+	applicationset "github.com/argoproj/applicationset/pkg/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	operatorDeploymentName = "cluster-aas-operator-controller-manager"
+	operatorContainerName  = "manager"
+	operatorPDBName        = "cluster-aas-operator-controller-manager"
+	// label set by OLM on the resources of a ClusterServiceVersion
+	olmOwnerLabel = "olm.owner"
+)
+
+var OperatorDeploymentLog = logf.Log.WithName("operator-deployment-controller")
+
+var operatorLabels = map[string]string{
+	"control-plane": "caas-controller-manager",
+}
+
+// OperatorDeploymentReconciler applies the operational settings of the runtime config to the
+// Deployment of the operator and maintains its PodDisruptionBudget
+type OperatorDeploymentReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+func getOperatorDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      operatorDeploymentName,
+			Namespace: configNamespace,
+		},
+	}
+}
+
+func getOperatorPDB() *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      operatorPDBName,
+			Namespace: configNamespace,
+			Labels:    operatorLabels,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: OperatorPDBMinAvailable,
+			Selector:     &metav1.LabelSelector{MatchLabels: operatorLabels},
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OperatorDeploymentReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(
+			&appsv1.Deployment{},
+			builder.WithPredicates(predicate.NewPredicateFuncs(selectOperatorDeployment)),
+		).
+		Watches(&source.Channel{Source: OperatorConfigSync}, &handler.EnqueueRequestForObject{}).
+		Complete(r); err != nil {
+		return fmt.Errorf("failed to construct controller: %w", err)
+	}
+	return nil
+}
+
+func selectOperatorDeployment(obj client.Object) bool {
+	return obj.GetName() == operatorDeploymentName && obj.GetNamespace() == configNamespace
+}
+
+// GetOperatorCacheSelectors restricts the cache of Deployments and PodDisruptionBudgets to the
+// namespace of the operator, the operator manages only its own ones
+func GetOperatorCacheSelectors() cache.SelectorsByObject {
+	inOperatorNamespace := cache.ObjectSelector{
+		Field: fields.OneTermEqualSelector("metadata.namespace", configNamespace),
+	}
+	return cache.SelectorsByObject{
+		&appsv1.Deployment{}:            inOperatorNamespace,
+		&policyv1.PodDisruptionBudget{}: inOperatorNamespace,
+	}
+}
+
+// isOLMManaged returns true if the Deployment is installed by OLM. OLM reverts changes of the
+// Deployments of a ClusterServiceVersion, the settings have to be set by the Subscription config.
+func isOLMManaged(deployment *appsv1.Deployment) bool {
+	if _, ok := deployment.Labels[olmOwnerLabel]; ok {
+		return true
+	}
+	for _, ref := range deployment.OwnerReferences {
+		if ref.Kind == "ClusterServiceVersion" {
+			return true
+		}
+	}
+	return false
+}
+
+// +kubebuilder:rbac:groups=apps,namespace=cluster-aas-operator,resources=deployments,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=policy,namespace=cluster-aas-operator,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;delete
+
+func (r *OperatorDeploymentReconciler) Reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+) (ctrl.Result, error) {
+	if OperatorPDBMinAvailable != nil {
+		operatorPDB := getOperatorPDB()
+		pdb := &policyv1.PodDisruptionBudget{ObjectMeta: operatorPDB.ObjectMeta}
+		if _, err := applicationset.CreateOrUpdate(ctx, r.Client, pdb, func() error {
+			if !reflect.DeepEqual(pdb.Spec, operatorPDB.Spec) {
+				pdb.Spec = operatorPDB.Spec
+			}
+			return nil
+		}); err != nil {
+			return reconcile.Result{}, err
+		}
+	} else if err := r.Client.Delete(ctx, getOperatorPDB()); err != nil &&
+		!apierrors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+
+	// settings which are not configured are left as set by the manifests of the operator
+	if OperatorPriorityClassName == "" && OperatorResources == nil {
+		return reconcile.Result{}, nil
+	}
+	deployment := getOperatorDeployment()
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		// the operator runs outside of the cluster, ie during development
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if isOLMManaged(deployment) {
+		OperatorDeploymentLog.Info(
+			"operator deployment is managed by OLM, set priority class and resources in the "+
+				"Subscription config",
+			"name",
+			deployment.Name,
+		)
+		return reconcile.Result{}, nil
+	}
+	if applyOperatorSettings(&deployment.Spec.Template.Spec) {
+		// the pods of the operator are replaced by a rolling update
+		return reconcile.Result{}, r.Client.Update(ctx, deployment)
+	}
+	return reconcile.Result{}, nil
+}
+
+// applyOperatorSettings sets the configured priority class and resources on the pod of the
+// operator, returns true if the pod changed
+func applyOperatorSettings(podSpec *v1.PodSpec) bool {
+	changed := false
+	if OperatorPriorityClassName != "" && podSpec.PriorityClassName != OperatorPriorityClassName {
+		podSpec.PriorityClassName = OperatorPriorityClassName
+		changed = true
+	}
+	if OperatorResources == nil {
+		return changed
+	}
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		if container.Name == operatorContainerName &&
+			!reflect.DeepEqual(container.Resources, *OperatorResources) {
+			container.Resources = *OperatorResources
+			changed = true
+		}
+	}
+	return changed
+}
+
+// GetProfilingHandler returns handler of the pprof endpoints, served by the metrics server at
+// /debug/pprof/ while profiling is enabled by the runtime config
+func GetProfilingHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !EnableProfiling {
+			http.NotFound(w, req)
+			return
+		}
+		mux.ServeHTTP(w, req)
+	})
+}
+
+// syncOperatorConfig requests reconcile of the operator Deployment, does nothing if a reconcile
+// is already pending - it reads the current config anyway
+func syncOperatorConfig() {
+	select {
+	case OperatorConfigSync <- event.GenericEvent{Object: getOperatorDeployment()}:
+	default:
+	}
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Operator deployment", func() {
+	AfterEach(func() {
+		OperatorPriorityClassName = ""
+		OperatorResources = nil
+		OperatorPDBMinAvailable = nil
+		EnableProfiling = false
+	})
+
+	It("Loads operator config", func() {
+		config := &v1.ConfigMap{
+			Data: map[string]string{
+				operatorPriorityClassConfig:   "system-cluster-critical",
+				operatorResourcesConfig:       "limits:\n  memory: 2Gi\n",
+				operatorPDBMinAvailableConfig: "50%",
+				enableProfilingConfig:         "true",
+			},
+		}
+		Expect(loadOperatorConfig(config)).Should(Succeed())
+		Expect(OperatorPriorityClassName).Should(Equal("system-cluster-critical"))
+		Expect(OperatorResources.Limits.Memory().String()).Should(Equal("2Gi"))
+		Expect(*OperatorPDBMinAvailable).Should(Equal(intstr.FromString("50%")))
+		Expect(EnableProfiling).Should(BeTrue())
+
+		config.Data[operatorPDBMinAvailableConfig] = "half"
+		Expect(loadOperatorConfig(config)).ShouldNot(Succeed())
+		config.Data[operatorPDBMinAvailableConfig] = ""
+		config.Data[operatorResourcesConfig] = "memory: 2Gi"
+		Expect(loadOperatorConfig(config)).ShouldNot(Succeed())
+	})
+
+	It("Applies operator config to the deployment", func() {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      operatorDeploymentName,
+				Namespace: configNamespace,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Template: v1.PodTemplateSpec{
+					Spec: v1.PodSpec{
+						Containers: []v1.Container{
+							{Name: "kube-rbac-proxy"},
+							{Name: operatorContainerName},
+						},
+					},
+				},
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, deployment)
+		reconciler := &OperatorDeploymentReconciler{Client: k8sClient}
+		req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(deployment)}
+
+		OperatorPriorityClassName = "system-cluster-critical"
+		OperatorResources = &v1.ResourceRequirements{
+			Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("2Gi")},
+		}
+		minAvailable := intstr.FromInt(1)
+		OperatorPDBMinAvailable = &minAvailable
+		_, err := reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(k8sClient.Get(context.TODO(), req.NamespacedName, deployment)).Should(Succeed())
+		podSpec := deployment.Spec.Template.Spec
+		Expect(podSpec.PriorityClassName).Should(Equal("system-cluster-critical"))
+		Expect(podSpec.Containers[0].Resources).Should(Equal(v1.ResourceRequirements{}))
+		Expect(podSpec.Containers[1].Resources).Should(Equal(*OperatorResources))
+		pdb := &policyv1.PodDisruptionBudget{}
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: operatorPDBName, Namespace: configNamespace},
+			pdb,
+		)).Should(Succeed())
+		Expect(*pdb.Spec.MinAvailable).Should(Equal(minAvailable))
+		Expect(pdb.Spec.Selector.MatchLabels).Should(Equal(operatorLabels))
+
+		OperatorPDBMinAvailable = nil
+		_, err = reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(k8sClient.Get(
+			context.TODO(),
+			client.ObjectKey{Name: operatorPDBName, Namespace: configNamespace},
+			pdb,
+		)).ShouldNot(Succeed())
+	})
+
+	It("Leaves deployment installed by OLM", func() {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      operatorDeploymentName,
+				Namespace: configNamespace,
+				Labels:    map[string]string{olmOwnerLabel: "cluster-aas-operator.v0.0.1"},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Template: v1.PodTemplateSpec{
+					Spec: v1.PodSpec{
+						Containers: []v1.Container{{Name: operatorContainerName}},
+					},
+				},
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, deployment)
+		reconciler := &OperatorDeploymentReconciler{Client: k8sClient}
+		req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(deployment)}
+
+		OperatorPriorityClassName = "system-cluster-critical"
+		_, err := reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(k8sClient.Get(context.TODO(), req.NamespacedName, deployment)).Should(Succeed())
+		Expect(deployment.Spec.Template.Spec.PriorityClassName).Should(BeEmpty())
+	})
+})
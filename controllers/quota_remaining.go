@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// remainingQuota is published in the remaining quota ConfigMap for every quota of the
+// namespace, unlimited amounts are omitted
+type remainingQuota struct {
+	Budget    *int                `json:"budget,omitempty"`
+	Nodes     *int                `json:"nodes,omitempty"`
+	VCPU      *int                `json:"vcpu,omitempty"`
+	Templates []remainingTemplate `json:"templates"`
+}
+
+type remainingTemplate struct {
+	Name string `json:"name"`
+	// number of instances which can still be created, limited by the count and the budget
+	Instances *int `json:"instances,omitempty"`
+}
+
+func remaining(limit int, spent int) *int {
+	if limit == 0 {
+		return nil
+	}
+	left := limit - spent
+	if left < 0 {
+		left = 0
+	}
+	return &left
+}
+
+// getRemainingQuota computes what is left of the quota from its status
+func getRemainingQuota(
+	quota v1alpha1.ClusterTemplateQuota,
+	templateCosts map[string]int,
+) remainingQuota {
+	result := remainingQuota{
+		Budget:    remaining(quota.Spec.Budget, quota.Status.BudgetSpent),
+		Nodes:     remaining(quota.Spec.MaxNodes, quota.Status.NodesSpent),
+		VCPU:      remaining(quota.Spec.MaxVCPU, quota.Status.VCPUSpent),
+		Templates: []remainingTemplate{},
+	}
+	for _, template := range quota.Spec.AllowedTemplates {
+		count := 0
+		for _, instances := range quota.Status.TemplateInstances {
+			if instances.Name == template.Name {
+				count = instances.Count
+			}
+		}
+		left := remaining(template.Count, count)
+		if cost := templateCosts[template.Name]; result.Budget != nil && cost > 0 {
+			affordable := *result.Budget / cost
+			if left == nil || affordable < *left {
+				left = &affordable
+			}
+		}
+		result.Templates = append(result.Templates, remainingTemplate{
+			Name:      template.Name,
+			Instances: left,
+		})
+	}
+	return result
+}
+
+// reconcileRemainingQuota publishes the remaining quotas of the namespace in a ConfigMap, so it
+// can be displayed without aggregating the quotas and instances. The ConfigMap is deleted with
+// the last quota of the namespace.
+func (r *ClusterTemplateQuotaReconciler) reconcileRemainingQuota(
+	ctx context.Context,
+	namespace string,
+	templates []v1alpha1.ClusterTemplate,
+) error {
+	quotas := &v1alpha1.ClusterTemplateQuotaList{}
+	if err := r.List(ctx, quotas, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      v1alpha1.RemainingQuotaConfigMapName,
+			Namespace: namespace,
+		},
+	}
+	if len(quotas.Items) == 0 {
+		if err := r.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	templateCosts := map[string]int{}
+	for _, template := range templates {
+		templateCosts[template.Name] = template.Spec.Cost
+	}
+	data := map[string]string{}
+	for _, quota := range quotas.Items {
+		value, err := json.Marshal(getRemainingQuota(quota, templateCosts))
+		if err != nil {
+			return err
+		}
+		data[quota.Name] = string(value)
+	}
+	sort.Slice(quotas.Items, func(i, j int) bool {
+		return quotas.Items[i].Name < quotas.Items[j].Name
+	})
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		configMap.Data = data
+		configMap.OwnerReferences = []metav1.OwnerReference{}
+		for _, quota := range quotas.Items {
+			configMap.OwnerReferences = append(configMap.OwnerReferences, metav1.OwnerReference{
+				APIVersion: v1alpha1.GroupVersion.String(),
+				Kind:       "ClusterTemplateQuota",
+				Name:       quota.Name,
+				UID:        quota.UID,
+			})
+		}
+		return nil
+	})
+	return err
+}
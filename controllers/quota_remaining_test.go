@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Remaining quota", func() {
+	It("Publishes remaining quota of the namespace", func() {
+		quota := &v1alpha1.ClusterTemplateQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "quota",
+				Namespace: "foo",
+			},
+			Spec: v1alpha1.ClusterTemplateQuotaSpec{
+				Budget: 50,
+				AllowedTemplates: []v1alpha1.AllowedTemplate{
+					{Name: "small", Count: 5},
+					{Name: "large"},
+					{Name: "free"},
+				},
+			},
+		}
+		templates := []runtime.Object{
+			&v1alpha1.ClusterTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "small"},
+				Spec:       v1alpha1.ClusterTemplateSpec{Cost: 5},
+			},
+			&v1alpha1.ClusterTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "large"},
+				Spec:       v1alpha1.ClusterTemplateSpec{Cost: 25},
+			},
+			&v1alpha1.ClusterTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "free"},
+			},
+		}
+		instance := &v1alpha1.ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster",
+				Namespace: "foo",
+			},
+			Spec: v1alpha1.ClusterTemplateInstanceSpec{ClusterTemplateRef: "large"},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			append(templates, quota, instance)...,
+		)
+		reconciler := &ClusterTemplateQuotaReconciler{Client: k8sClient}
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "quota", Namespace: "foo"}}
+		_, err := reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		configMap := &corev1.ConfigMap{}
+		key := client.ObjectKey{Name: v1alpha1.RemainingQuotaConfigMapName, Namespace: "foo"}
+		Expect(k8sClient.Get(context.TODO(), key, configMap)).Should(Succeed())
+		Expect(configMap.Data["quota"]).Should(MatchJSON(`{
+			"budget": 25,
+			"templates": [
+				{"name": "small", "instances": 5},
+				{"name": "large", "instances": 1},
+				{"name": "free"}
+			]
+		}`))
+
+		Expect(k8sClient.Delete(context.TODO(), quota)).Should(Succeed())
+		_, err = reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(k8sClient.Get(context.TODO(), key, configMap)).ShouldNot(Succeed())
+	})
+	It("Weights cost of instances by their size class", func() {
+		quota := &v1alpha1.ClusterTemplateQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "quota",
+				Namespace: "foo",
+			},
+			Spec: v1alpha1.ClusterTemplateQuotaSpec{
+				Budget:           50,
+				AllowedTemplates: []v1alpha1.AllowedTemplate{{Name: "ocp"}},
+			},
+		}
+		template := &v1alpha1.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "ocp"},
+			Spec:       v1alpha1.ClusterTemplateSpec{Cost: 5},
+		}
+		large := &v1alpha1.ClusterSizeClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "large"},
+			Spec:       v1alpha1.ClusterSizeClassSpec{Weight: 4},
+		}
+		newInstance := func(name string, sizeClass string) *v1alpha1.ClusterTemplateInstance {
+			return &v1alpha1.ClusterTemplateInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "foo",
+				},
+				Spec: v1alpha1.ClusterTemplateInstanceSpec{
+					ClusterTemplateRef: "ocp",
+					SizeClass:          sizeClass,
+				},
+			}
+		}
+		k8sClient := fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			quota,
+			template,
+			large,
+			newInstance("default", ""),
+			newInstance("large", "large"),
+		)
+		reconciler := &ClusterTemplateQuotaReconciler{Client: k8sClient}
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "quota", Namespace: "foo"}}
+		_, err := reconciler.Reconcile(context.TODO(), req)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(k8sClient.Get(context.TODO(), req.NamespacedName, quota)).Should(Succeed())
+		Expect(quota.Status.BudgetSpent).Should(Equal(25))
+	})
+})
@@ -0,0 +1,67 @@
+package helm
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	argoCommon "github.com/argoproj/argo-cd/v2/common"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetBootstrapSecretName returns the name of the repository secret created for the repository URL
+func GetBootstrapSecretName(repoURL string) string {
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(repoURL)))[:8]
+	return "claas-helm-repo-" + hash
+}
+
+// EnsureRepositories registers Helm repositories in ArgoCD. A repository is skipped if a
+// repository with the same URL is already registered, so repositories configured by the admin
+// (ie with credentials) are never overwritten. Returns URLs of the created repositories.
+func EnsureRepositories(
+	ctx context.Context,
+	k8sClient client.Client,
+	argoCDNamespace string,
+	repoURLs []string,
+) ([]string, error) {
+	secrets, err := GetRepoSecrets(ctx, k8sClient, argoCDNamespace)
+	if err != nil {
+		return nil, err
+	}
+	registered := map[string]bool{}
+	for _, secret := range secrets {
+		registered[strings.TrimSuffix(string(secret.Data["url"]), "/")] = true
+	}
+
+	created := []string{}
+	for _, repoURL := range repoURLs {
+		if registered[strings.TrimSuffix(repoURL, "/")] {
+			continue
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      GetBootstrapSecretName(repoURL),
+				Namespace: argoCDNamespace,
+				Labels: map[string]string{
+					argoCommon.LabelKeySecretType: argoCommon.LabelValueSecretTypeRepository,
+				},
+			},
+			Data: map[string][]byte{
+				"type": []byte("helm"),
+				"url":  []byte(repoURL),
+			},
+		}
+		if err := k8sClient.Create(ctx, secret); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				continue
+			}
+			return created, err
+		}
+		created = append(created, repoURL)
+	}
+	return created, nil
+}
@@ -0,0 +1,64 @@
+package helm
+
+import (
+	"context"
+
+	argoCommon "github.com/argoproj/argo-cd/v2/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Bootstrap helm repositories", func() {
+	It("Creates missing repositories", func() {
+		existing := &corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "argocd",
+				Labels: map[string]string{
+					argoCommon.LabelKeySecretType: argoCommon.LabelValueSecretTypeRepository,
+				},
+			},
+			Data: map[string][]byte{
+				"type":     []byte("helm"),
+				"url":      []byte("https://foo.io/charts/"),
+				"password": []byte("secret"),
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, existing)
+
+		created, err := EnsureRepositories(
+			context.TODO(),
+			k8sClient,
+			"argocd",
+			[]string{"https://foo.io/charts", "https://bar.io/charts"},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(created).Should(Equal([]string{"https://bar.io/charts"}))
+
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKey{
+			Name:      GetBootstrapSecretName("https://bar.io/charts"),
+			Namespace: "argocd",
+		}, secret)).Should(Succeed())
+		Expect(string(secret.Data["url"])).Should(Equal("https://bar.io/charts"))
+		Expect(string(secret.Data["type"])).Should(Equal("helm"))
+
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(existing), secret)).
+			Should(Succeed())
+		Expect(string(secret.Data["password"])).Should(Equal("secret"))
+
+		created, err = EnsureRepositories(
+			context.TODO(),
+			k8sClient,
+			"argocd",
+			[]string{"https://foo.io/charts", "https://bar.io/charts"},
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(created).Should(BeEmpty())
+	})
+})
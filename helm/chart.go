@@ -1,10 +1,12 @@
 package helm
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
-	"os"
+	"net/http"
+	"strings"
 
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
@@ -18,6 +20,7 @@ func (h *HelmClient) GetChart(
 	chartName string,
 	version string,
 	argoCDNamespace string,
+	caBundle []byte,
 ) (*chart.Chart, error) {
 
 	secrets, err := GetRepoSecrets(ctx, k8sClient, argoCDNamespace)
@@ -28,7 +31,7 @@ func (h *HelmClient) GetChart(
 	if err != nil {
 		return nil, err
 	}
-	httpClient, err := GetRepoHTTPClient(ctx, repoURL, secrets, cm)
+	httpClient, err := GetRepoHTTPClient(ctx, repoURL, secrets, cm, caBundle)
 
 	if err != nil {
 		return nil, err
@@ -36,6 +39,7 @@ func (h *HelmClient) GetChart(
 
 	chartURL, err := getChartURL(
 		httpClient,
+		h.IndexCache,
 		repoURL,
 		chartName,
 		version,
@@ -45,34 +49,129 @@ func (h *HelmClient) GetChart(
 		return nil, err
 	}
 
-	resp, err := httpClient.Get(chartURL)
+	helmChart, err := h.Downloader.Download(httpClient, chartURL)
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf(
-			"response for %v returned %v with status code %v",
-			chartURL,
-			resp,
-			resp.StatusCode,
+	if err = h.resolveDependencies(ctx, helmChart, secrets, cm, caBundle, 0); err != nil {
+		return nil, err
+	}
+	return helmChart, nil
+}
+
+// GetChartFromRepositories returns the chart from the first of the repositories which serves it.
+// Repositories are tried in order, so mirrors following the primary repository are used only when
+// the index or the chart can not be fetched from the preceding ones. URL of the repository which
+// served the chart is returned as well.
+func (h *HelmClient) GetChartFromRepositories(
+	ctx context.Context,
+	k8sClient client.Client,
+	repoURLs []string,
+	chartName string,
+	version string,
+	argoCDNamespace string,
+	caBundle []byte,
+) (*chart.Chart, string, error) {
+	if len(repoURLs) == 0 {
+		return nil, "", fmt.Errorf("no repository of chart %s", chartName)
+	}
+	errs := []string{}
+	var lastErr error
+	for _, repoURL := range repoURLs {
+		helmChart, err := h.GetChart(
+			ctx,
+			k8sClient,
+			repoURL,
+			chartName,
+			version,
+			argoCDNamespace,
+			caBundle,
 		)
+		if err == nil {
+			return helmChart, repoURL, nil
+		}
+		lastErr = err
+		errs = append(errs, fmt.Sprintf("%s: %s", repoURL, err))
 	}
-	defer resp.Body.Close()
+	if len(errs) == 1 {
+		return nil, "", lastErr
+	}
+	return nil, "", fmt.Errorf(
+		"failed to fetch chart %s from all repositories - %s",
+		chartName,
+		strings.Join(errs, "; "),
+	)
+}
+
+// GetChartFromURL downloads the chart tarball directly, without looking it up in the
+// index of a Helm repository. The downloaded tarball is returned together with the loaded chart.
+func (h *HelmClient) GetChartFromURL(
+	ctx context.Context,
+	k8sClient client.Client,
+	chartURL string,
+	argoCDNamespace string,
+	caBundle []byte,
+) (*chart.Chart, []byte, error) {
+	secrets, err := GetRepoSecrets(ctx, k8sClient, argoCDNamespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	cm, err := GetRepoCM(ctx, k8sClient, argoCDNamespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	httpClient, err := GetRepoHTTPClient(ctx, chartURL, secrets, cm, caBundle)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := h.Downloader.DownloadArchive(httpClient, chartURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	helmChart, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = h.resolveDependencies(ctx, helmChart, secrets, cm, caBundle, 0); err != nil {
+		return nil, nil, err
+	}
+	return helmChart, data, nil
+}
 
-	f, err := os.CreateTemp("", "chart-*")
+// downloadChart downloads and loads the chart tarball, transient errors are retried with
+// RetryBackoff
+func downloadChart(httpClient *http.Client, chartURL string) (*chart.Chart, error) {
+	data, err := downloadChartData(httpClient, chartURL)
 	if err != nil {
 		return nil, err
 	}
+	return loader.LoadArchive(bytes.NewReader(data))
+}
 
-	defer f.Close()
-	defer os.Remove(f.Name())
-
-	_, err = io.Copy(f, resp.Body)
+// downloadChartData returns content of the chart tarball, transient errors are retried with
+// RetryBackoff
+func downloadChartData(httpClient *http.Client, chartURL string) ([]byte, error) {
+	var data []byte
+	err := withRetry(func() error {
+		var downloadErr error
+		data, downloadErr = downloadChartDataOnce(httpClient, chartURL)
+		return downloadErr
+	})
+	return data, err
+}
 
+func downloadChartDataOnce(httpClient *http.Client, chartURL string) ([]byte, error) {
+	resp, err := httpClient.Get(chartURL)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
-
-	return loader.Load(f.Name())
+	if resp.StatusCode != 200 {
+		return nil, &statusCodeError{
+			url:        chartURL,
+			resp:       resp,
+			statusCode: resp.StatusCode,
+		}
+	}
+	return io.ReadAll(resp.Body)
 }
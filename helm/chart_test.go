@@ -2,6 +2,7 @@ package helm
 
 import (
 	"context"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 
@@ -37,12 +38,12 @@ var _ = Describe("Helm client", func() {
 	})
 	It("GetChart", func() {
 		helmClient := CreateHelmClient(k8sManager, cfg)
-		chart, err := helmClient.GetChart(context.TODO(), k8sClient, "", "", "", "argocd")
+		chart, err := helmClient.GetChart(context.TODO(), k8sClient, "", "", "", "argocd", nil)
 		Expect(chart).Should(BeNil())
 		Expect(err).ShouldNot(BeNil())
 		server := helmserver.StartHelmRepoServer()
 
-		chart, err = helmClient.GetChart(context.TODO(), k8sClient, server.URL, "", "", "argocd")
+		chart, err = helmClient.GetChart(context.TODO(), k8sClient, server.URL, "", "", "argocd", nil)
 		Expect(chart).Should(BeNil())
 		Expect(err).ShouldNot(BeNil())
 
@@ -53,9 +54,93 @@ var _ = Describe("Helm client", func() {
 			"hypershift-template",
 			"0.0.2",
 			"argocd",
+			nil,
 		)
 		Expect(chart).ShouldNot(BeNil())
 		Expect(err).Should(BeNil())
+
+		chart, err = helmClient.GetChart(
+			context.TODO(),
+			k8sClient,
+			server.URL,
+			"hypershift-template",
+			">=0.0.1 <1.0.0",
+			"argocd",
+			nil,
+		)
+		Expect(err).Should(BeNil())
+		Expect(chart.Metadata.Version).Should(Equal("0.0.2"))
+	})
+	It("GetChartFromURL", func() {
+		helmClient := CreateHelmClient(k8sManager, cfg)
+		chart, data, err := helmClient.GetChartFromURL(
+			context.TODO(),
+			k8sClient,
+			server.URL+"/hypershift-template-0.0.2.tgz",
+			"argocd",
+			nil,
+		)
+		Expect(err).Should(BeNil())
+		Expect(chart.Metadata.Name).Should(Equal("hypershift-template"))
+		Expect(chart.Metadata.Version).Should(Equal("0.0.2"))
+		expectedData, err := os.ReadFile("../testutils/helm/hypershift-template-0.0.2.tgz")
+		Expect(err).Should(BeNil())
+		Expect(data).Should(Equal(expectedData))
+
+		chart, _, err = helmClient.GetChartFromURL(
+			context.TODO(),
+			k8sClient,
+			server.URL+"/missing-0.0.1.tgz",
+			"argocd",
+			nil,
+		)
+		Expect(chart).Should(BeNil())
+		Expect(err).ShouldNot(BeNil())
+	})
+	It("GetChartFromRepositories", func() {
+		helmClient := CreateHelmClient(k8sManager, cfg)
+		unavailable := httptest.NewServer(http.NotFoundHandler())
+		defer unavailable.Close()
+
+		chart, repoURL, err := helmClient.GetChartFromRepositories(
+			context.TODO(),
+			k8sClient,
+			[]string{unavailable.URL, server.URL},
+			"hypershift-template",
+			"0.0.2",
+			"argocd",
+			nil,
+		)
+		Expect(err).Should(BeNil())
+		Expect(repoURL).Should(Equal(server.URL))
+		Expect(chart.Metadata.Version).Should(Equal("0.0.2"))
+
+		chart, repoURL, err = helmClient.GetChartFromRepositories(
+			context.TODO(),
+			k8sClient,
+			[]string{server.URL, unavailable.URL},
+			"hypershift-template",
+			"0.0.2",
+			"argocd",
+			nil,
+		)
+		Expect(err).Should(BeNil())
+		Expect(repoURL).Should(Equal(server.URL))
+		Expect(chart).ShouldNot(BeNil())
+
+		_, _, err = helmClient.GetChartFromRepositories(
+			context.TODO(),
+			k8sClient,
+			[]string{unavailable.URL, unavailable.URL + "/mirror"},
+			"hypershift-template",
+			"0.0.2",
+			"argocd",
+			nil,
+		)
+		Expect(err).ShouldNot(BeNil())
+		Expect(err.Error()).Should(HavePrefix(
+			"failed to fetch chart hypershift-template from all repositories - " + unavailable.URL,
+		))
 	})
 	It("GetChart with repo secret", func() {
 		helmClient := CreateHelmClient(k8sManager, cfg)
@@ -81,6 +166,7 @@ var _ = Describe("Helm client", func() {
 			"hypershift-template",
 			"0.0.2",
 			"argocd",
+			nil,
 		)
 		Expect(chart).ShouldNot(BeNil())
 		Expect(err).Should(BeNil())
@@ -110,6 +196,7 @@ var _ = Describe("Helm client", func() {
 			"hypershift-template",
 			"0.0.2",
 			"argocd",
+			nil,
 		)
 		Expect(chart).ShouldNot(BeNil())
 		Expect(err).Should(BeNil())
@@ -157,9 +244,67 @@ var _ = Describe("Helm client", func() {
 			"hypershift-template",
 			"0.0.2",
 			"argocd",
+			nil,
+		)
+		Expect(chart).ShouldNot(BeNil())
+		Expect(err).Should(BeNil())
+	})
+	It("GetChart https with ca bundle", func() {
+		helmClient := CreateHelmClient(k8sManager, cfg)
+		secret := &corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "argocd",
+				Labels: map[string]string{
+					argoCommon.LabelKeySecretType: argoCommon.LabelValueSecretTypeRepository,
+				},
+			},
+			Data: map[string][]byte{
+				"type": []byte("helm"),
+				"url":  []byte(httpsServer.URL),
+			},
+		}
+		data, err := os.ReadFile("../testutils/helm/ca.crt")
+		if err != nil {
+			Fail(err.Error())
+		}
+		bundleCM := &corev1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "trusted-ca",
+				Namespace: "argocd",
+			},
+			Data: map[string]string{
+				CABundleKey: string(data),
+			},
+		}
+
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, secret, bundleCM)
+		caBundle, err := GetCABundle(context.TODO(), client, "trusted-ca", "argocd")
+		Expect(err).Should(BeNil())
+
+		chart, err := helmClient.GetChart(
+			context.TODO(),
+			client,
+			httpsServer.URL,
+			"hypershift-template",
+			"0.0.2",
+			"argocd",
+			caBundle,
 		)
 		Expect(chart).ShouldNot(BeNil())
 		Expect(err).Should(BeNil())
+
+		chart, err = helmClient.GetChart(
+			context.TODO(),
+			client,
+			httpsServer.URL,
+			"hypershift-template",
+			"0.0.2",
+			"argocd",
+			[]byte("foo"),
+		)
+		Expect(chart).Should(BeNil())
+		Expect(err).ShouldNot(BeNil())
 	})
 })
 
@@ -26,6 +26,9 @@ const (
 	HelmSecretTLSClientKey  = "tlsClientCertKey"
 	HelmSecretTLSClientCert = "tlsClientCertData"
 	HelmSecretTLSInsecure   = "insecure"
+
+	// CABundleKey is the key of the CA bundle ConfigMap holding PEM encoded certificates
+	CABundleKey = "ca-bundle.crt"
 )
 
 func initSettings() *cli.EnvSettings {
@@ -39,6 +42,12 @@ type HelmClient struct {
 	config       *rest.Config
 	actionConfig *action.Configuration
 	k8sClient    client.Client
+	// IndexCache caches repository index files, caching is disabled if nil
+	IndexCache *IndexCache
+	// Downloader bounds and deduplicates chart downloads, charts are downloaded directly if nil
+	Downloader *Downloader
+	// access to the remote cluster the releases are installed to, the hub if nil
+	restClientGetter genericclioptions.RESTClientGetter
 }
 
 func NewHelmClient(
@@ -131,11 +140,33 @@ func GetRepoSecrets(
 	return helmRepoSecrets, nil
 }
 
+// GetCABundle returns the content of the CA bundle ConfigMap. Returns nil if the name is empty
+// or the ConfigMap does not exist.
+func GetCABundle(
+	ctx context.Context,
+	k8sClient client.Client,
+	name string,
+	namespace string,
+) ([]byte, error) {
+	if name == "" {
+		return nil, nil
+	}
+	cm := &corev1.ConfigMap{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return []byte(cm.Data[CABundleKey]), nil
+}
+
 func GetRepoHTTPClient(
 	ctx context.Context,
 	repoURL string,
 	repoSecrets []corev1.Secret,
 	tlsCM *corev1.ConfigMap,
+	caBundle []byte,
 ) (*http.Client, error) {
 
 	var repoSecret *corev1.Secret
@@ -170,10 +201,18 @@ func GetRepoHTTPClient(
 		return nil, err
 	}
 	var rootCAs *x509.CertPool
+	if len(caBundle) > 0 {
+		rootCAs = getSystemCertPool()
+		if !rootCAs.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("failed to parse CA bundle")
+		}
+	}
 	if tlsCM != nil {
 		for key, cert := range tlsCM.Data {
 			if parsedUrl.Hostname() == key {
-				rootCAs = x509.NewCertPool()
+				if rootCAs == nil {
+					rootCAs = x509.NewCertPool()
+				}
 				rootCAs.AppendCertsFromPEM([]byte(cert))
 				break
 			}
@@ -202,9 +241,17 @@ func GetRepoHTTPClient(
 	}
 
 	httpClient := &http.Client{Transport: &http.Transport{
-		Proxy:           http.ProxyFromEnvironment,
+		Proxy:           getProxyURL,
 		TLSClientConfig: tlsConfig,
 	}}
 
 	return httpClient, nil
 }
+
+func getSystemCertPool() *x509.CertPool {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		return x509.NewCertPool()
+	}
+	return pool
+}
@@ -0,0 +1,93 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maximum depth of nested subcharts, guards against dependency cycles
+const maxDependencyDepth = 5
+
+// resolveDependencies downloads dependencies declared in Chart.yaml which are not packaged in
+// the charts/ directory of the chart (ie chart was packaged without 'helm dependency update')
+// and adds them to the chart
+func (h *HelmClient) resolveDependencies(
+	ctx context.Context,
+	helmChart *chart.Chart,
+	repoSecrets []corev1.Secret,
+	tlsCM *corev1.ConfigMap,
+	caBundle []byte,
+	depth int,
+) error {
+	if action.CheckDependencies(helmChart, helmChart.Metadata.Dependencies) == nil {
+		return nil
+	}
+	if depth >= maxDependencyDepth {
+		return fmt.Errorf(
+			"dependencies of chart %s are nested more than %d levels",
+			helmChart.Name(),
+			maxDependencyDepth,
+		)
+	}
+
+	packaged := map[string]bool{}
+	for _, dependency := range helmChart.Dependencies() {
+		packaged[dependency.Name()] = true
+	}
+	for _, dependency := range helmChart.Metadata.Dependencies {
+		if packaged[dependency.Name] {
+			continue
+		}
+		if !strings.HasPrefix(dependency.Repository, "http://") &&
+			!strings.HasPrefix(dependency.Repository, "https://") {
+			return fmt.Errorf(
+				"dependency %s of chart %s is not packaged and repository %q is not supported",
+				dependency.Name,
+				helmChart.Name(),
+				dependency.Repository,
+			)
+		}
+		httpClient, err := GetRepoHTTPClient(
+			ctx,
+			dependency.Repository,
+			repoSecrets,
+			tlsCM,
+			caBundle,
+		)
+		if err != nil {
+			return err
+		}
+		chartURL, err := getChartURL(
+			httpClient,
+			h.IndexCache,
+			dependency.Repository,
+			dependency.Name,
+			dependency.Version,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to resolve dependency %s - %q", dependency.Name, err)
+		}
+		subchart, err := h.Downloader.Download(httpClient, chartURL)
+		if err != nil {
+			return fmt.Errorf("failed to download dependency %s - %q", dependency.Name, err)
+		}
+		if err = h.resolveDependencies(
+			ctx,
+			subchart,
+			repoSecrets,
+			tlsCM,
+			caBundle,
+			depth+1,
+		); err != nil {
+			return err
+		}
+		helmChart.AddDependency(subchart)
+		packaged[dependency.Name] = true
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package helm
+
+import (
+	"context"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+
+	helmserver "github.com/stolostron/cluster-templates-operator/testutils/helm"
+)
+
+var _ = Describe("Chart dependencies", func() {
+	var server *httptest.Server
+	var helmChart *chart.Chart
+
+	BeforeEach(func() {
+		server = helmserver.StartHelmRepoServer()
+		helmChart = &chart.Chart{
+			Metadata: &chart.Metadata{
+				APIVersion: chart.APIVersionV2,
+				Name:       "composite",
+				Version:    "0.1.0",
+				Dependencies: []*chart.Dependency{{
+					Name:       "hypershift-template",
+					Version:    ">=0.0.1",
+					Repository: server.URL,
+				}},
+			},
+		}
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Downloads dependencies which are not packaged", func() {
+		helmClient := &HelmClient{}
+		Expect(helmClient.resolveDependencies(context.TODO(), helmChart, nil, nil, nil, 0)).
+			Should(Succeed())
+		Expect(helmChart.Dependencies()).Should(HaveLen(1))
+		Expect(helmChart.Dependencies()[0].Metadata.Version).Should(Equal("0.0.2"))
+	})
+
+	It("Keeps packaged dependencies", func() {
+		helmChart.AddDependency(&chart.Chart{
+			Metadata: &chart.Metadata{
+				APIVersion: chart.APIVersionV2,
+				Name:       "hypershift-template",
+				Version:    "0.0.1",
+			},
+		})
+		helmClient := &HelmClient{}
+		Expect(helmClient.resolveDependencies(context.TODO(), helmChart, nil, nil, nil, 0)).
+			Should(Succeed())
+		Expect(helmChart.Dependencies()).Should(HaveLen(1))
+		Expect(helmChart.Dependencies()[0].Metadata.Version).Should(Equal("0.0.1"))
+	})
+
+	It("Fails for unsupported repository", func() {
+		helmChart.Metadata.Dependencies[0].Repository = "file://../hypershift-template"
+		helmClient := &HelmClient{}
+		Expect(helmClient.resolveDependencies(context.TODO(), helmChart, nil, nil, nil, 0)).
+			ShouldNot(Succeed())
+	})
+
+	It("Fails for missing dependency", func() {
+		helmChart.Metadata.Dependencies[0].Name = "missing"
+		helmClient := &HelmClient{}
+		Expect(helmClient.resolveDependencies(context.TODO(), helmChart, nil, nil, nil, 0)).
+			ShouldNot(Succeed())
+	})
+})
@@ -0,0 +1,73 @@
+package helm
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+type chartDownload struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// Downloader is a queue of chart downloads shared by all reconciles. Concurrent requests of the
+// same chart URL are served by a single download and at most maxConcurrent downloads run at
+// once, so creating many instances at the same time does not flood the repositories.
+type Downloader struct {
+	slots    chan struct{}
+	lock     sync.Mutex
+	inFlight map[string]*chartDownload
+}
+
+func NewDownloader(maxConcurrent int) *Downloader {
+	return &Downloader{
+		slots:    make(chan struct{}, maxConcurrent),
+		inFlight: map[string]*chartDownload{},
+	}
+}
+
+// Download downloads and loads the chart tarball. Requests of a chart which is being downloaded
+// wait for the running download, each caller gets its own copy of the chart as loaded charts are
+// modified when resolving dependencies. Nil downloader downloads the chart immediately.
+func (d *Downloader) Download(httpClient *http.Client, chartURL string) (*chart.Chart, error) {
+	data, err := d.DownloadArchive(httpClient, chartURL)
+	if err != nil {
+		return nil, err
+	}
+	return loader.LoadArchive(bytes.NewReader(data))
+}
+
+// DownloadArchive downloads the chart tarball, sharing the running download of the same chart URL
+// as Download does. The returned archive must not be modified.
+func (d *Downloader) DownloadArchive(httpClient *http.Client, chartURL string) ([]byte, error) {
+	if d == nil {
+		return downloadChartData(httpClient, chartURL)
+	}
+
+	d.lock.Lock()
+	download, ok := d.inFlight[chartURL]
+	if !ok {
+		download = &chartDownload{done: make(chan struct{})}
+		d.inFlight[chartURL] = download
+	}
+	d.lock.Unlock()
+
+	if !ok {
+		d.slots <- struct{}{}
+		download.data, download.err = downloadChartData(httpClient, chartURL)
+		<-d.slots
+
+		d.lock.Lock()
+		delete(d.inFlight, chartURL)
+		d.lock.Unlock()
+		close(download.done)
+	}
+
+	<-download.done
+	return download.data, download.err
+}
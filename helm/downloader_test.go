@@ -0,0 +1,130 @@
+package helm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+var _ = Describe("Downloader", func() {
+	var server *httptest.Server
+	var lock sync.Mutex
+	var requests int
+	var running int
+	var maxRunning int
+	var release chan struct{}
+
+	BeforeEach(func() {
+		requests = 0
+		running = 0
+		maxRunning = 0
+		release = make(chan struct{})
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			requests++
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			lock.Unlock()
+
+			<-release
+			data, err := os.ReadFile("../testutils/helm/" + r.URL.Path)
+
+			lock.Lock()
+			running--
+			lock.Unlock()
+
+			if err != nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write(data)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	getRequests := func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return requests
+	}
+
+	downloadAll := func(downloader *Downloader, chartURLs []string) []*chart.Chart {
+		charts := make([]*chart.Chart, len(chartURLs))
+		var wg sync.WaitGroup
+		for i, chartURL := range chartURLs {
+			wg.Add(1)
+			go func(i int, chartURL string) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				helmChart, err := downloader.Download(server.Client(), chartURL)
+				Expect(err).Should(BeNil())
+				charts[i] = helmChart
+			}(i, chartURL)
+		}
+		wg.Wait()
+		return charts
+	}
+
+	It("Shares concurrent downloads of the same chart", func() {
+		downloader := NewDownloader(10)
+		chartURLs := []string{}
+		for i := 0; i < 5; i++ {
+			chartURLs = append(chartURLs, server.URL+"/hypershift-template-0.0.2.tgz")
+		}
+		go func() {
+			defer GinkgoRecover()
+			Eventually(getRequests).Should(Equal(1))
+			Consistently(getRequests).Should(Equal(1))
+			close(release)
+		}()
+		charts := downloadAll(downloader, chartURLs)
+		Expect(getRequests()).Should(Equal(1))
+		for _, helmChart := range charts {
+			Expect(helmChart.Name()).Should(Equal("hypershift-template"))
+		}
+		Expect(charts[0]).ShouldNot(BeIdenticalTo(charts[1]))
+	})
+
+	It("Limits concurrent downloads", func() {
+		downloader := NewDownloader(2)
+		chartURLs := []string{}
+		for i := 0; i < 6; i++ {
+			chartURLs = append(
+				chartURLs,
+				fmt.Sprintf("%s/hypershift-template-0.0.2.tgz?copy=%d", server.URL, i),
+			)
+		}
+		go func() {
+			defer GinkgoRecover()
+			Eventually(getRequests).Should(Equal(2))
+			Consistently(getRequests).Should(Equal(2))
+			close(release)
+		}()
+		downloadAll(downloader, chartURLs)
+		Expect(getRequests()).Should(Equal(6))
+		Expect(maxRunning).Should(Equal(2))
+	})
+
+	It("Downloads directly when nil", func() {
+		var downloader *Downloader
+		close(release)
+		helmChart, err := downloader.Download(
+			server.Client(),
+			server.URL+"/hypershift-template-0.0.2.tgz",
+		)
+		Expect(err).Should(BeNil())
+		Expect(helmChart.Name()).Should(Equal("hypershift-template"))
+	})
+})
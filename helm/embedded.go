@@ -0,0 +1,135 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/provenance"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// EmbeddedChartLabel marks ConfigMaps and Secrets holding packaged charts, only labeled
+	// resources are served by the operator
+	EmbeddedChartLabel = "clustertemplate.openshift.io/embedded-chart"
+	// EmbeddedChartKey is the key of the chart tarball in the ConfigMap or Secret
+	EmbeddedChartKey = "chart.tgz"
+
+	EmbeddedChartConfigMap = "ConfigMap"
+	EmbeddedChartSecret    = "Secret"
+)
+
+// path segments of the embedded chart repository URLs per kind of the resource
+var embeddedChartPaths = map[string]string{
+	EmbeddedChartConfigMap: "configmaps",
+	EmbeddedChartSecret:    "secrets",
+}
+
+// GetEmbeddedChartRepoURL returns URL of the Helm repository serving the chart of the ConfigMap
+// or Secret. baseURL is the URL the operator serves embedded charts at.
+func GetEmbeddedChartRepoURL(baseURL string, kind string, name string) string {
+	return fmt.Sprintf("%s/%s/%s", baseURL, embeddedChartPaths[kind], name)
+}
+
+// GetEmbeddedChartKind returns kind of the resource for the path segment of the embedded chart
+// repository URL. Returns false if the path segment is not known.
+func GetEmbeddedChartKind(path string) (string, bool) {
+	for kind, kindPath := range embeddedChartPaths {
+		if kindPath == path {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+// GetEmbeddedChartData returns the chart tarball of the ConfigMap or Secret
+func GetEmbeddedChartData(obj client.Object) ([]byte, error) {
+	if obj.GetLabels()[EmbeddedChartLabel] != "true" {
+		return nil, fmt.Errorf(
+			"%s is not labeled '%s=true'",
+			obj.GetName(),
+			EmbeddedChartLabel,
+		)
+	}
+	var data []byte
+	switch o := obj.(type) {
+	case *corev1.ConfigMap:
+		data = o.BinaryData[EmbeddedChartKey]
+	case *corev1.Secret:
+		data = o.Data[EmbeddedChartKey]
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf(
+			"%s does not contain chart under key '%s'",
+			obj.GetName(),
+			EmbeddedChartKey,
+		)
+	}
+	return data, nil
+}
+
+// GetEmbeddedChart loads the chart of the ConfigMap or Secret. Dependencies of the chart are not
+// resolved, they have to be packaged within the chart.
+func (h *HelmClient) GetEmbeddedChart(
+	ctx context.Context,
+	k8sClient client.Client,
+	kind string,
+	name string,
+	namespace string,
+) (*chart.Chart, error) {
+	var obj client.Object
+	switch kind {
+	case EmbeddedChartConfigMap:
+		obj = &corev1.ConfigMap{}
+	case EmbeddedChartSecret:
+		obj = &corev1.Secret{}
+	default:
+		return nil, fmt.Errorf("unsupported kind of embedded chart '%s'", kind)
+	}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, obj); err != nil {
+		return nil, err
+	}
+	data, err := GetEmbeddedChartData(obj)
+	if err != nil {
+		return nil, err
+	}
+	return loader.LoadArchive(bytes.NewReader(data))
+}
+
+// GetEmbeddedChartIndex returns index file of a repository serving only the chart tarball
+func GetEmbeddedChartIndex(data []byte) (*repo.IndexFile, error) {
+	helmChart, err := loader.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	digest, err := provenance.Digest(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	indexFile := repo.NewIndexFile()
+	if err := indexFile.MustAdd(helmChart.Metadata, EmbeddedChartKey, "", digest); err != nil {
+		return nil, err
+	}
+	return indexFile, nil
+}
+
+// PackageChart returns the chart tarball, ie to be stored as an embedded chart
+func PackageChart(helmChart *chart.Chart) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "chart-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	path, err := chartutil.Save(helmChart, dir)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,109 @@
+package helm
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Embedded charts", func() {
+	var chartData []byte
+	BeforeEach(func() {
+		var err error
+		chartData, err = os.ReadFile("../testutils/helm/hypershift-template-0.0.2.tgz")
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	getConfigMap := func(labels map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hypershift-template",
+				Namespace: "argocd",
+				Labels:    labels,
+			},
+			BinaryData: map[string][]byte{EmbeddedChartKey: chartData},
+		}
+	}
+
+	It("Loads chart from ConfigMap", func() {
+		fakeClient := fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			getConfigMap(map[string]string{EmbeddedChartLabel: "true"}),
+		)
+		helmClient := NewHelmClient(cfg, fakeClient, nil, nil, nil)
+		helmChart, err := helmClient.GetEmbeddedChart(
+			context.TODO(),
+			fakeClient,
+			EmbeddedChartConfigMap,
+			"hypershift-template",
+			"argocd",
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(helmChart.Metadata.Name).Should(Equal("hypershift-template"))
+		Expect(helmChart.Metadata.Version).Should(Equal("0.0.2"))
+	})
+
+	It("Loads chart from Secret", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hypershift-template",
+				Namespace: "argocd",
+				Labels:    map[string]string{EmbeddedChartLabel: "true"},
+			},
+			Data: map[string][]byte{EmbeddedChartKey: chartData},
+		}
+		fakeClient := fake.NewFakeClientWithScheme(scheme.Scheme, secret)
+		helmClient := NewHelmClient(cfg, fakeClient, nil, nil, nil)
+		helmChart, err := helmClient.GetEmbeddedChart(
+			context.TODO(),
+			fakeClient,
+			EmbeddedChartSecret,
+			"hypershift-template",
+			"argocd",
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(helmChart.Metadata.Name).Should(Equal("hypershift-template"))
+	})
+
+	It("Rejects ConfigMap without label", func() {
+		fakeClient := fake.NewFakeClientWithScheme(scheme.Scheme, getConfigMap(nil))
+		helmClient := NewHelmClient(cfg, fakeClient, nil, nil, nil)
+		_, err := helmClient.GetEmbeddedChart(
+			context.TODO(),
+			fakeClient,
+			EmbeddedChartConfigMap,
+			"hypershift-template",
+			"argocd",
+		)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal(
+			"hypershift-template is not labeled 'clustertemplate.openshift.io/embedded-chart=true'",
+		))
+	})
+
+	It("Creates index of the chart", func() {
+		indexFile, err := GetEmbeddedChartIndex(chartData)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(indexFile.Entries["hypershift-template"]).Should(HaveLen(1))
+		entry := indexFile.Entries["hypershift-template"][0]
+		Expect(entry.Version).Should(Equal("0.0.2"))
+		Expect(entry.URLs).Should(Equal([]string{EmbeddedChartKey}))
+		Expect(entry.Digest).ShouldNot(BeEmpty())
+	})
+
+	It("Returns repository URL", func() {
+		repoURL := GetEmbeddedChartRepoURL("https://foo.svc:8001/charts", EmbeddedChartSecret, "bar")
+		Expect(repoURL).Should(Equal("https://foo.svc:8001/charts/secrets/bar"))
+		kind, ok := GetEmbeddedChartKind("secrets")
+		Expect(ok).Should(BeTrue())
+		Expect(kind).Should(Equal(EmbeddedChartSecret))
+		_, ok = GetEmbeddedChartKind("pods")
+		Expect(ok).Should(BeFalse())
+	})
+})
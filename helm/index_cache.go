@@ -0,0 +1,74 @@
+package helm
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+type indexCacheEntry struct {
+	indexFile *repo.IndexFile
+	etag      string
+	fetchedAt time.Time
+}
+
+// IndexCache is an in-memory cache of repository index files keyed by index URL.
+// Entries older than TTL are revalidated with a conditional GET if the repository
+// returned an ETag, otherwise they are downloaded again.
+type IndexCache struct {
+	ttl     time.Duration
+	lock    sync.Mutex
+	entries map[string]indexCacheEntry
+	now     func() time.Time
+}
+
+func NewIndexCache(ttl time.Duration) *IndexCache {
+	return &IndexCache{
+		ttl:     ttl,
+		entries: map[string]indexCacheEntry{},
+		now:     time.Now,
+	}
+}
+
+// GetIndexFile returns the index file of the repository. Nil cache downloads the index file
+// on every call.
+func (c *IndexCache) GetIndexFile(
+	httpClient *http.Client,
+	indexURL string,
+) (*repo.IndexFile, error) {
+	if c == nil {
+		return GetIndexFile(httpClient, indexURL)
+	}
+	indexURL = getIndexURL(indexURL)
+
+	c.lock.Lock()
+	entry, ok := c.entries[indexURL]
+	c.lock.Unlock()
+
+	if ok && c.now().Sub(entry.fetchedAt) < c.ttl {
+		return entry.indexFile, nil
+	}
+
+	etag := ""
+	if ok {
+		etag = entry.etag
+	}
+	indexFile, newEtag, notModified, err := fetchIndexFile(httpClient, indexURL, etag)
+	if err != nil {
+		return nil, err
+	}
+	if notModified {
+		indexFile = entry.indexFile
+	}
+
+	c.lock.Lock()
+	c.entries[indexURL] = indexCacheEntry{
+		indexFile: indexFile,
+		etag:      newEtag,
+		fetchedAt: c.now(),
+	}
+	c.lock.Unlock()
+	return indexFile, nil
+}
@@ -0,0 +1,80 @@
+package helm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Index cache", func() {
+	var server *httptest.Server
+	var downloads int
+	var notModified int
+
+	BeforeEach(func() {
+		downloads = 0
+		notModified = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == "foo" {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			data, err := os.ReadFile("../testutils/helm/index.yaml")
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			downloads++
+			w.Header().Set("ETag", "foo")
+			w.WriteHeader(http.StatusOK)
+			w.Write(data)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Downloads index file on every call when nil", func() {
+		var cache *IndexCache
+		_, err := cache.GetIndexFile(server.Client(), server.URL)
+		Expect(err).Should(BeNil())
+		_, err = cache.GetIndexFile(server.Client(), server.URL)
+		Expect(err).Should(BeNil())
+		Expect(downloads).Should(Equal(2))
+	})
+
+	It("Returns cached index file within TTL", func() {
+		cache := NewIndexCache(time.Minute)
+		indexFile, err := cache.GetIndexFile(server.Client(), server.URL)
+		Expect(err).Should(BeNil())
+		Expect(indexFile.Entries).Should(HaveKey("hypershift-template"))
+
+		cachedIndexFile, err := cache.GetIndexFile(server.Client(), server.URL+"/index.yaml")
+		Expect(err).Should(BeNil())
+		Expect(cachedIndexFile).Should(BeIdenticalTo(indexFile))
+		Expect(downloads).Should(Equal(1))
+		Expect(notModified).Should(Equal(0))
+	})
+
+	It("Revalidates expired index file with ETag", func() {
+		now := time.Now()
+		cache := NewIndexCache(time.Minute)
+		cache.now = func() time.Time { return now }
+
+		indexFile, err := cache.GetIndexFile(server.Client(), server.URL)
+		Expect(err).Should(BeNil())
+
+		now = now.Add(2 * time.Minute)
+		cachedIndexFile, err := cache.GetIndexFile(server.Client(), server.URL)
+		Expect(err).Should(BeNil())
+		Expect(cachedIndexFile).Should(BeIdenticalTo(indexFile))
+		Expect(downloads).Should(Equal(1))
+		Expect(notModified).Should(Equal(1))
+	})
+})
@@ -0,0 +1,104 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+
+	"golang.org/x/crypto/openpgp" //nolint
+	"helm.sh/helm/v3/pkg/provenance"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// VerifyChart checks the chart was signed by one of the ASCII armored PGP public keys and returns
+// the verified chart archive with its digest ('sha256:<hex>'). The chart and its provenance file
+// (chart URL with '.prov' suffix) are downloaded from the repository.
+func (h *HelmClient) VerifyChart(
+	ctx context.Context,
+	k8sClient client.Client,
+	repoURL string,
+	chartName string,
+	version string,
+	argoCDNamespace string,
+	caBundle []byte,
+	publicKeys []byte,
+) ([]byte, string, error) {
+	keyRing, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(publicKeys))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read public keys - %q", err)
+	}
+
+	secrets, err := GetRepoSecrets(ctx, k8sClient, argoCDNamespace)
+	if err != nil {
+		return nil, "", err
+	}
+	cm, err := GetRepoCM(ctx, k8sClient, argoCDNamespace)
+	if err != nil {
+		return nil, "", err
+	}
+	httpClient, err := GetRepoHTTPClient(ctx, repoURL, secrets, cm, caBundle)
+	if err != nil {
+		return nil, "", err
+	}
+	chartURL, err := getChartURL(httpClient, h.IndexCache, repoURL, chartName, version)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dir, err := os.MkdirTemp("", "chart-verify-*")
+	if err != nil {
+		return nil, "", err
+	}
+	defer os.RemoveAll(dir)
+	// provenance file refers to the chart by its file name
+	chartPath := filepath.Join(dir, path.Base(chartURL))
+	if err = downloadFile(httpClient, chartURL, chartPath); err != nil {
+		return nil, "", err
+	}
+	provPath := chartPath + ".prov"
+	if err = downloadFile(httpClient, chartURL+".prov", provPath); err != nil {
+		return nil, "", fmt.Errorf("failed to download provenance file - %q", err)
+	}
+
+	signatory := &provenance.Signatory{KeyRing: keyRing}
+	verification, err := signatory.Verify(chartPath, provPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("chart %s-%s is not verified - %q", chartName, version, err)
+	}
+	data, err := os.ReadFile(chartPath)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, verification.FileHash, nil
+}
+
+// downloadFile saves the content of the URL to the file, transient errors are retried with
+// RetryBackoff
+func downloadFile(httpClient *http.Client, fileURL string, filePath string) error {
+	return withRetry(func() error {
+		resp, err := httpClient.Get(fileURL)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != 200 {
+			return &statusCodeError{
+				url:        fileURL,
+				resp:       resp,
+				statusCode: resp.StatusCode,
+			}
+		}
+		f, err := os.Create(filePath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(f, resp.Body)
+		return err
+	})
+}
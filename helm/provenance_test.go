@@ -0,0 +1,97 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/provenance"
+
+	helmserver "github.com/stolostron/cluster-templates-operator/testutils/helm"
+)
+
+var _ = Describe("Chart verification", func() {
+	var server *httptest.Server
+	var publicKeys []byte
+	BeforeEach(func() {
+		server = helmserver.StartHelmRepoServer()
+		var err error
+		publicKeys, err = os.ReadFile("../testutils/helm/provenance-public-keys.asc")
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Verifies signed chart", func() {
+		helmClient := NewHelmClient(cfg, k8sClient, nil, nil, nil)
+		data, digest, err := helmClient.VerifyChart(
+			context.TODO(),
+			k8sClient,
+			server.URL,
+			"hypershift-template",
+			"0.0.2",
+			"argocd",
+			nil,
+			publicKeys,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(digest).Should(HavePrefix("sha256:"))
+		verifiedDigest, err := provenance.Digest(bytes.NewReader(data))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect("sha256:" + verifiedDigest).Should(Equal(digest))
+	})
+
+	It("Fails for chart signed by untrusted key", func() {
+		untrustedKeys, err := os.ReadFile("../testutils/helm/provenance-untrusted-keys.asc")
+		Expect(err).ShouldNot(HaveOccurred())
+		helmClient := NewHelmClient(cfg, k8sClient, nil, nil, nil)
+		_, _, err = helmClient.VerifyChart(
+			context.TODO(),
+			k8sClient,
+			server.URL,
+			"hypershift-template",
+			"0.0.2",
+			"argocd",
+			nil,
+			untrustedKeys,
+		)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("chart hypershift-template-0.0.2 is not verified"))
+	})
+
+	It("Fails for chart without provenance file", func() {
+		helmClient := NewHelmClient(cfg, k8sClient, nil, nil, nil)
+		_, _, err := helmClient.VerifyChart(
+			context.TODO(),
+			k8sClient,
+			server.URL,
+			"hypershift-template-no-schema",
+			"0.0.2",
+			"argocd",
+			nil,
+			publicKeys,
+		)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("failed to download provenance file"))
+	})
+
+	It("Fails for invalid public keys", func() {
+		helmClient := NewHelmClient(cfg, k8sClient, nil, nil, nil)
+		_, _, err := helmClient.VerifyChart(
+			context.TODO(),
+			k8sClient,
+			server.URL,
+			"hypershift-template",
+			"0.0.2",
+			"argocd",
+			nil,
+			[]byte("foo"),
+		)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("failed to read public keys"))
+	})
+})
@@ -0,0 +1,50 @@
+package helm
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+var (
+	proxyLock sync.RWMutex
+	// proxy settings of the operator config, nil when the environment should be used
+	proxyConfig *httpproxy.Config
+	proxyFunc   func(*url.URL) (*url.URL, error)
+)
+
+// SetProxy overrides HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables for
+// requests to Helm repositories. The environment is used again when all values are empty
+func SetProxy(httpProxy string, httpsProxy string, noProxy string) {
+	proxyLock.Lock()
+	defer proxyLock.Unlock()
+	if httpProxy == "" && httpsProxy == "" && noProxy == "" {
+		proxyConfig = nil
+		proxyFunc = nil
+		return
+	}
+	proxyConfig = &httpproxy.Config{
+		HTTPProxy:  httpProxy,
+		HTTPSProxy: httpsProxy,
+		NoProxy:    noProxy,
+	}
+	proxyFunc = proxyConfig.ProxyFunc()
+}
+
+// GetProxy returns the proxy settings of the operator config, nil if the environment is used
+func GetProxy() *httpproxy.Config {
+	proxyLock.RLock()
+	defer proxyLock.RUnlock()
+	return proxyConfig
+}
+
+func getProxyURL(req *http.Request) (*url.URL, error) {
+	proxyLock.RLock()
+	defer proxyLock.RUnlock()
+	if proxyFunc == nil {
+		return http.ProxyFromEnvironment(req)
+	}
+	return proxyFunc(req.URL)
+}
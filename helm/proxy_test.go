@@ -0,0 +1,42 @@
+package helm
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Helm proxy", func() {
+	AfterEach(func() {
+		SetProxy("", "", "")
+	})
+
+	getProxy := func(repoURL string) string {
+		httpClient, err := GetRepoHTTPClient(context.TODO(), repoURL, nil, nil, nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		req, err := http.NewRequest(http.MethodGet, repoURL+"/index.yaml", nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		proxyURL, err := httpClient.Transport.(*http.Transport).Proxy(req)
+		Expect(err).ShouldNot(HaveOccurred())
+		if proxyURL == nil {
+			return ""
+		}
+		return proxyURL.String()
+	}
+
+	It("Uses proxy of the operator config", func() {
+		SetProxy("http://proxy.example.com:3128", "http://secure-proxy.example.com:3128", "internal.example.com")
+		Expect(GetProxy()).ShouldNot(BeNil())
+		Expect(getProxy("https://charts.example.com")).Should(Equal("http://secure-proxy.example.com:3128"))
+		Expect(getProxy("http://charts.example.com")).Should(Equal("http://proxy.example.com:3128"))
+		Expect(getProxy("https://internal.example.com")).Should(Equal(""))
+	})
+
+	It("Falls back to environment", func() {
+		SetProxy("http://proxy.example.com:3128", "", "")
+		SetProxy("", "", "")
+		Expect(GetProxy()).Should(BeNil())
+	})
+})
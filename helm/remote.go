@@ -0,0 +1,113 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KubeconfigSecretKey is the key of the Secret holding kubeconfig of a remote cluster
+const KubeconfigSecretKey = "kubeconfig"
+
+// kubeconfigGetter gives Helm access to a cluster described by a kubeconfig
+type kubeconfigGetter struct {
+	clientConfig clientcmd.ClientConfig
+}
+
+var _ genericclioptions.RESTClientGetter = &kubeconfigGetter{}
+
+func (k *kubeconfigGetter) ToRESTConfig() (*rest.Config, error) {
+	return k.clientConfig.ClientConfig()
+}
+
+func (k *kubeconfigGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	config, err := k.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return memory.NewMemCacheClient(discoveryClient), nil
+}
+
+func (k *kubeconfigGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	discoveryClient, err := k.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient)
+	return restmapper.NewShortcutExpander(mapper, discoveryClient), nil
+}
+
+func (k *kubeconfigGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	return k.clientConfig
+}
+
+// ForKubeconfigSecret returns a copy of the client which installs releases to the remote cluster
+// whose kubeconfig is stored in the Secret under key 'kubeconfig'. Charts are still fetched
+// using the repositories and credentials configured on the hub.
+func (h *HelmClient) ForKubeconfigSecret(
+	ctx context.Context,
+	name string,
+	namespace string,
+) (*HelmClient, error) {
+	secret := &corev1.Secret{}
+	if err := h.k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: name, Namespace: namespace},
+		secret,
+	); err != nil {
+		return nil, err
+	}
+	kubeconfig, ok := secret.Data[KubeconfigSecretKey]
+	if !ok {
+		return nil, fmt.Errorf(
+			"secret %s/%s does not contain key '%s'",
+			namespace,
+			name,
+			KubeconfigSecretKey,
+		)
+	}
+	clientConfig, err := clientcmd.NewClientConfigFromBytes(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig in secret %s/%s - %q", namespace, name, err)
+	}
+	remote := *h
+	remote.config = config
+	remote.restClientGetter = &kubeconfigGetter{clientConfig: clientConfig}
+	return &remote, nil
+}
+
+// IsRemote returns true if the client is configured for a remote cluster
+func (h *HelmClient) IsRemote() bool {
+	return h.restClientGetter != nil
+}
+
+// GetConfig returns the REST config of the cluster the client is configured for
+func (h *HelmClient) GetConfig() *rest.Config {
+	return h.config
+}
+
+// GetClient returns client of the cluster the Helm client is configured for, with the scheme of
+// the hub client
+func (h *HelmClient) GetClient() (client.Client, error) {
+	if !h.IsRemote() {
+		return h.k8sClient, nil
+	}
+	return client.New(h.config, client.Options{Scheme: h.k8sClient.Scheme()})
+}
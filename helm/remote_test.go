@@ -0,0 +1,66 @@
+package helm
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+var _ = Describe("Remote cluster", func() {
+	getKubeconfig := func() []byte {
+		kubeconfig := clientcmdapi.NewConfig()
+		kubeconfig.Clusters["hosting"] = &clientcmdapi.Cluster{
+			Server:                   cfg.Host,
+			CertificateAuthorityData: cfg.CAData,
+		}
+		kubeconfig.AuthInfos["admin"] = &clientcmdapi.AuthInfo{
+			ClientCertificateData: cfg.CertData,
+			ClientKeyData:         cfg.KeyData,
+		}
+		kubeconfig.Contexts["hosting"] = &clientcmdapi.Context{
+			Cluster:  "hosting",
+			AuthInfo: "admin",
+		}
+		kubeconfig.CurrentContext = "hosting"
+		data, err := clientcmd.Write(*kubeconfig)
+		Expect(err).ShouldNot(HaveOccurred())
+		return data
+	}
+
+	It("Configures client by kubeconfig secret", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "hosting-kubeconfig", Namespace: "default"},
+			Data:       map[string][]byte{KubeconfigSecretKey: getKubeconfig()},
+		}
+		Expect(k8sClient.Create(context.TODO(), secret)).Should(Succeed())
+
+		helmClient := NewHelmClient(cfg, k8sClient, nil, nil, nil)
+		Expect(helmClient.IsRemote()).Should(BeFalse())
+		remote, err := helmClient.ForKubeconfigSecret(context.TODO(), secret.Name, secret.Namespace)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(remote.IsRemote()).Should(BeTrue())
+		Expect(remote.GetConfig().Host).Should(Equal(cfg.Host))
+
+		remoteClient, err := remote.GetClient()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(remoteClient.List(context.TODO(), &corev1.NamespaceList{})).Should(Succeed())
+	})
+
+	It("Fails for secret without kubeconfig", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "empty-kubeconfig", Namespace: "default"},
+		}
+		Expect(k8sClient.Create(context.TODO(), secret)).Should(Succeed())
+
+		helmClient := NewHelmClient(cfg, k8sClient, nil, nil, nil)
+		_, err := helmClient.ForKubeconfigSecret(context.TODO(), secret.Name, secret.Namespace)
+		Expect(err).Should(MatchError(
+			"secret default/empty-kubeconfig does not contain key 'kubeconfig'",
+		))
+	})
+})
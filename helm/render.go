@@ -0,0 +1,70 @@
+package helm
+
+import (
+	"sort"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/klog"
+)
+
+// RenderChart renders manifests of the chart like 'helm template' does, nothing is installed.
+// CRDs of the chart are rendered too, unless skipCRDs is true.
+func RenderChart(
+	helmChart *chart.Chart,
+	releaseName string,
+	namespace string,
+	values map[string]interface{},
+	skipCRDs bool,
+) (string, error) {
+	rel, err := renderRelease(helmChart, releaseName, namespace, values, !skipCRDs)
+	if err != nil {
+		return "", err
+	}
+	return rel.Manifest, nil
+}
+
+// RenderTestHooks renders test hooks of the chart ('helm.sh/hook: test') in the order
+// of their weights, like 'helm test' runs them
+func RenderTestHooks(
+	helmChart *chart.Chart,
+	releaseName string,
+	namespace string,
+	values map[string]interface{},
+) ([]*release.Hook, error) {
+	rel, err := renderRelease(helmChart, releaseName, namespace, values, false)
+	if err != nil {
+		return nil, err
+	}
+	hooks := []*release.Hook{}
+	for _, hook := range rel.Hooks {
+		for _, event := range hook.Events {
+			if event == release.HookTest {
+				hooks = append(hooks, hook)
+				break
+			}
+		}
+	}
+	sort.SliceStable(hooks, func(i, j int) bool {
+		return hooks[i].Weight < hooks[j].Weight
+	})
+	return hooks, nil
+}
+
+func renderRelease(
+	helmChart *chart.Chart,
+	releaseName string,
+	namespace string,
+	values map[string]interface{},
+	includeCRDs bool,
+) (*release.Release, error) {
+	install := action.NewInstall(&action.Configuration{Log: klog.Infof})
+	install.DryRun = true
+	install.ClientOnly = true
+	install.Replace = true
+	install.IncludeCRDs = includeCRDs
+	install.ReleaseName = releaseName
+	install.Namespace = namespace
+	return install.Run(helmChart, values)
+}
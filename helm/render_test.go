@@ -0,0 +1,136 @@
+package helm
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+var _ = Describe("Render chart", func() {
+	helmChart := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       "foo",
+			Version:    "0.1.0",
+		},
+		Values: map[string]interface{}{
+			"replicas": 1,
+		},
+		Templates: []*chart.File{{
+			Name: "templates/cm.yaml",
+			Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+  namespace: {{ .Release.Namespace }}
+data:
+  replicas: "{{ .Values.replicas }}"
+`),
+		}},
+	}
+
+	It("Renders chart with default values", func() {
+		manifest, err := RenderChart(helmChart, "bar", "baz", nil, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(manifest).Should(ContainSubstring("name: bar"))
+		Expect(manifest).Should(ContainSubstring("namespace: baz"))
+		Expect(manifest).Should(ContainSubstring(`replicas: "1"`))
+	})
+
+	It("Renders chart with values", func() {
+		manifest, err := RenderChart(
+			helmChart,
+			"bar",
+			"baz",
+			map[string]interface{}{"replicas": 3},
+			false,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(manifest).Should(ContainSubstring(`replicas: "3"`))
+	})
+
+	It("Renders CRDs unless skipped", func() {
+		crdChart := &chart.Chart{
+			Metadata:  helmChart.Metadata,
+			Values:    helmChart.Values,
+			Templates: helmChart.Templates,
+			Files: []*chart.File{{
+				Name: "crds/foo.yaml",
+				Data: []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: foos.example.com
+`),
+			}},
+		}
+		manifest, err := RenderChart(crdChart, "bar", "baz", nil, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(manifest).Should(ContainSubstring("name: foos.example.com"))
+
+		manifest, err = RenderChart(crdChart, "bar", "baz", nil, true)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(manifest).ShouldNot(ContainSubstring("name: foos.example.com"))
+		Expect(manifest).Should(ContainSubstring("name: bar"))
+	})
+
+	It("Renders test hooks ordered by weight", func() {
+		testChart := &chart.Chart{
+			Metadata: helmChart.Metadata,
+			Templates: append([]*chart.File{
+				{
+					Name: "templates/tests/second.yaml",
+					Data: []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: {{ .Release.Name }}-second
+  annotations:
+    helm.sh/hook: test
+    helm.sh/hook-weight: "2"
+`),
+				},
+				{
+					Name: "templates/tests/first.yaml",
+					Data: []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: {{ .Release.Name }}-first
+  annotations:
+    helm.sh/hook: test-success
+    helm.sh/hook-weight: "1"
+`),
+				},
+				{
+					Name: "templates/install-hook.yaml",
+					Data: []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: {{ .Release.Name }}-install
+  annotations:
+    helm.sh/hook: post-install
+`),
+				},
+			}, helmChart.Templates...),
+		}
+		hooks, err := RenderTestHooks(testChart, "bar", "baz", nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hooks).Should(HaveLen(2))
+		Expect(hooks[0].Name).Should(Equal("bar-first"))
+		Expect(hooks[1].Name).Should(Equal("bar-second"))
+
+		hooks, err = RenderTestHooks(helmChart, "bar", "baz", nil)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(hooks).Should(BeEmpty())
+	})
+
+	It("Fails for invalid template", func() {
+		invalidChart := &chart.Chart{
+			Metadata: helmChart.Metadata,
+			Templates: []*chart.File{{
+				Name: "templates/cm.yaml",
+				Data: []byte("{{ .Values.foo | required \"foo is required\" }}"),
+			}},
+		}
+		_, err := RenderChart(invalidChart, "bar", "baz", nil, false)
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -6,60 +6,93 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/ghodss/yaml"
 	"helm.sh/helm/v3/pkg/repo"
 )
 
 func GetIndexFile(httpClient *http.Client, indexURL string) (*repo.IndexFile, error) {
-	indexFile := &repo.IndexFile{}
+	indexFile, _, _, err := fetchIndexFile(httpClient, indexURL, "")
+	return indexFile, err
+}
 
-	if !strings.HasSuffix(indexURL, "/index.yaml") {
-		indexURL += "/index.yaml"
+// fetchIndexFile downloads the index file. If etag is set, the request is conditional and
+// notModified is returned when the server reports that the index file did not change.
+// Transient errors are retried with RetryBackoff.
+func fetchIndexFile(
+	httpClient *http.Client,
+	indexURL string,
+	etag string,
+) (indexFile *repo.IndexFile, newEtag string, notModified bool, err error) {
+	err = withRetry(func() error {
+		var fetchErr error
+		indexFile, newEtag, notModified, fetchErr = fetchIndexFileOnce(httpClient, indexURL, etag)
+		return fetchErr
+	})
+	return indexFile, newEtag, notModified, err
+}
+
+func fetchIndexFileOnce(
+	httpClient *http.Client,
+	indexURL string,
+	etag string,
+) (indexFile *repo.IndexFile, newEtag string, notModified bool, err error) {
+	indexURL = getIndexURL(indexURL)
+	req, err := http.NewRequest(http.MethodGet, indexURL, nil)
+	if err != nil {
+		return nil, "", false, err
 	}
-	resp, err := httpClient.Get(indexURL)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, "", false, err
+	}
+	defer resp.Body.Close()
+	if etag != "" && resp.StatusCode == http.StatusNotModified {
+		return nil, etag, true, nil
 	}
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf(
-			"response for %v returned %v with status code %v",
-			indexURL,
-			resp,
-			resp.StatusCode,
-		)
+		return nil, "", false, &statusCodeError{
+			url:        indexURL,
+			resp:       resp,
+			statusCode: resp.StatusCode,
+		}
 	}
-	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, "", false, err
 	}
+	indexFile = &repo.IndexFile{}
 	err = yaml.Unmarshal(body, indexFile)
-	return indexFile, err
+	return indexFile, resp.Header.Get("ETag"), false, err
+}
+
+func getIndexURL(repoURL string) string {
+	if !strings.HasSuffix(repoURL, "/index.yaml") {
+		repoURL += "/index.yaml"
+	}
+	return repoURL
 }
 
 func getChartURL(
 	httpClient *http.Client,
+	indexCache *IndexCache,
 	indexURL string,
 	chartName string,
 	chartVersion string,
 ) (string, error) {
-	indexFile, err := GetIndexFile(httpClient, indexURL)
+	indexFile, err := indexCache.GetIndexFile(httpClient, indexURL)
 	if err != nil {
 		return "", err
 	}
 
-	helmChartURL := ""
-	entry := indexFile.Entries[chartName]
-	for _, e := range entry {
-		if e.Version == chartVersion {
-			helmChartURL = e.URLs[0]
-			break
-		}
-	}
-
-	if helmChartURL == "" {
-		return "", fmt.Errorf("could not find helm chart")
+	entry, err := resolveChartVersion(indexFile.Entries[chartName], chartVersion)
+	if err != nil {
+		return "", err
 	}
+	helmChartURL := entry.URLs[0]
 
 	if strings.HasSuffix(indexURL, "/index.yaml") {
 		indexURL = strings.TrimSuffix(indexURL, "index.yaml")
@@ -71,3 +104,37 @@ func getChartURL(
 	}
 	return helmChartURL, nil
 }
+
+// resolveChartVersion returns the chart entry of given version. If the version is a semver
+// constraint (ie '>=1.2.0 <2.0.0'), the newest matching entry is returned
+func resolveChartVersion(
+	entries repo.ChartVersions,
+	chartVersion string,
+) (*repo.ChartVersion, error) {
+	for _, e := range entries {
+		if e.Version == chartVersion && len(e.URLs) > 0 {
+			return e, nil
+		}
+	}
+
+	constraint, err := semver.NewConstraint(chartVersion)
+	if err != nil {
+		return nil, fmt.Errorf("could not find helm chart")
+	}
+	var newest *repo.ChartVersion
+	var newestVersion *semver.Version
+	for _, e := range entries {
+		version, parseErr := semver.NewVersion(e.Version)
+		if parseErr != nil || len(e.URLs) == 0 || !constraint.Check(version) {
+			continue
+		}
+		if newestVersion == nil || version.GreaterThan(newestVersion) {
+			newest = e
+			newestVersion = version
+		}
+	}
+	if newest == nil {
+		return nil, fmt.Errorf("could not find helm chart matching version %s", chartVersion)
+	}
+	return newest, nil
+}
@@ -0,0 +1,50 @@
+package helm
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+func getChartVersion(version string) *repo.ChartVersion {
+	return &repo.ChartVersion{
+		Metadata: &chart.Metadata{
+			Version: version,
+		},
+		URLs: []string{"foo-" + version + ".tgz"},
+	}
+}
+
+var _ = Describe("Helm repo", func() {
+	entries := repo.ChartVersions{
+		getChartVersion("2.0.0"),
+		getChartVersion("1.3.1"),
+		getChartVersion("1.2.0"),
+		getChartVersion("0.9.0"),
+	}
+
+	It("Resolves exact version", func() {
+		entry, err := resolveChartVersion(entries, "1.2.0")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(entry.Version).Should(Equal("1.2.0"))
+	})
+
+	It("Resolves newest version matching constraint", func() {
+		entry, err := resolveChartVersion(entries, ">=1.2.0 <2.0.0")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(entry.Version).Should(Equal("1.3.1"))
+
+		entry, err = resolveChartVersion(entries, "~0.9")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(entry.Version).Should(Equal("0.9.0"))
+	})
+
+	It("Fails when no version matches", func() {
+		_, err := resolveChartVersion(entries, ">=3.0.0")
+		Expect(err).Should(HaveOccurred())
+
+		_, err = resolveChartVersion(entries, "foo")
+		Expect(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,63 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+)
+
+// RetryBackoff is used for requests to Helm repositories which failed with a transient error
+// (network error, 429 or 5xx response), so a single failure does not fail the whole reconcile
+var RetryBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    4,
+}
+
+// statusCodeError is returned when a Helm repository responds with unexpected status code
+type statusCodeError struct {
+	url        string
+	resp       *http.Response
+	statusCode int
+}
+
+func (e *statusCodeError) Error() string {
+	return fmt.Sprintf(
+		"response for %v returned %v with status code %v",
+		e.url,
+		e.resp,
+		e.statusCode,
+	)
+}
+
+// withRetry calls fn until it succeeds, fails with an error which is not transient or
+// RetryBackoff is exhausted
+func withRetry(fn func() error) error {
+	return retry.OnError(RetryBackoff, isTransientError, fn)
+}
+
+// isTransientError returns true for errors which may disappear when the request is repeated -
+// 429 and 5xx responses, timeouts and refused or reset connections
+func isTransientError(err error) bool {
+	var statusErr *statusCodeError
+	if errors.As(err, &statusErr) {
+		return statusErr.statusCode == http.StatusTooManyRequests ||
+			statusErr.statusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
@@ -0,0 +1,73 @@
+package helm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retries", func() {
+	var server *httptest.Server
+	var requests int
+	var failures int
+	var failureCode int
+
+	BeforeEach(func() {
+		requests = 0
+		failures = 2
+		failureCode = http.StatusBadGateway
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests <= failures {
+				w.WriteHeader(failureCode)
+				return
+			}
+			data, err := os.ReadFile("../testutils/helm/" + r.URL.Path)
+			if err != nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write(data)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Retries index download on transient error", func() {
+		indexFile, err := GetIndexFile(server.Client(), server.URL)
+		Expect(err).Should(BeNil())
+		Expect(indexFile.Entries).Should(HaveKey("hypershift-template"))
+		Expect(requests).Should(Equal(3))
+	})
+
+	It("Retries chart download on transient error", func() {
+		chart, err := downloadChart(
+			server.Client(),
+			server.URL+"/hypershift-template-0.0.2.tgz",
+		)
+		Expect(err).Should(BeNil())
+		Expect(chart.Name()).Should(Equal("hypershift-template"))
+		Expect(requests).Should(Equal(3))
+	})
+
+	It("Gives up when retries are exhausted", func() {
+		failures = RetryBackoff.Steps
+		_, err := GetIndexFile(server.Client(), server.URL)
+		Expect(err).ShouldNot(BeNil())
+		Expect(err.Error()).Should(ContainSubstring("status code 502"))
+		Expect(requests).Should(Equal(RetryBackoff.Steps))
+	})
+
+	It("Does not retry client errors", func() {
+		failureCode = http.StatusUnauthorized
+		_, err := GetIndexFile(server.Client(), server.URL)
+		Expect(err).ShouldNot(BeNil())
+		Expect(requests).Should(Equal(1))
+	})
+})
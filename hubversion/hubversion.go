@@ -0,0 +1,161 @@
+package hubversion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	ClusterVersionGVK = schema.GroupVersionKind{
+		Group:   "config.openshift.io",
+		Version: "v1",
+		Kind:    "ClusterVersion",
+	}
+	IngressConfigGVK = schema.GroupVersionKind{
+		Group:   "config.openshift.io",
+		Version: "v1",
+		Kind:    "Ingress",
+	}
+	ProxyConfigGVK = schema.GroupVersionKind{
+		Group:   "config.openshift.io",
+		Version: "v1",
+		Kind:    "Proxy",
+	}
+	MultiClusterEngineListGVK = schema.GroupVersionKind{
+		Group:   "multicluster.openshift.io",
+		Version: "v1",
+		Kind:    "MultiClusterEngineList",
+	}
+)
+
+type HubVersions struct {
+	// OpenShift version of the hub, empty if the hub is not an OpenShift cluster
+	OpenShift string
+	// Version of the multicluster engine, empty if it is not installed
+	MCE string
+}
+
+// GetHubVersions detects versions of OpenShift and multicluster engine running on the hub
+func GetHubVersions(ctx context.Context, k8sClient client.Client) (HubVersions, error) {
+	versions := HubVersions{}
+
+	clusterVersion := &unstructured.Unstructured{}
+	clusterVersion.SetGroupVersionKind(ClusterVersionGVK)
+	err := k8sClient.Get(ctx, client.ObjectKey{Name: "version"}, clusterVersion)
+	if err == nil {
+		versions.OpenShift, _, _ = unstructured.NestedString(
+			clusterVersion.Object,
+			"status",
+			"desired",
+			"version",
+		)
+	} else if !isMissing(err) {
+		return versions, err
+	}
+
+	mces := &unstructured.UnstructuredList{}
+	mces.SetGroupVersionKind(MultiClusterEngineListGVK)
+	err = k8sClient.List(ctx, mces)
+	if err == nil {
+		if len(mces.Items) > 0 {
+			versions.MCE, _, _ = unstructured.NestedString(
+				mces.Items[0].Object,
+				"status",
+				"currentVersion",
+			)
+		}
+	} else if !isMissing(err) {
+		return versions, err
+	}
+	return versions, nil
+}
+
+// GetIngressDomain returns the default domain of routes on the hub (ie apps.hub.example.com),
+// empty if the hub is not an OpenShift cluster
+func GetIngressDomain(ctx context.Context, k8sClient client.Client) (string, error) {
+	ingress := &unstructured.Unstructured{}
+	ingress.SetGroupVersionKind(IngressConfigGVK)
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: "cluster"}, ingress); err != nil {
+		if isMissing(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	domain, _, _ := unstructured.NestedString(ingress.Object, "spec", "domain")
+	return domain, nil
+}
+
+// namespace of the ConfigMap referenced by trustedCA of the cluster-wide proxy
+const openShiftConfigNamespace = "openshift-config"
+
+// GetTrustedCABundle returns the additional CA bundle trusted by the hub - content of the
+// ConfigMap referenced by trustedCA of the cluster-wide proxy. Returns empty bundle if the hub
+// does not trust additional CAs or is not an OpenShift cluster.
+func GetTrustedCABundle(ctx context.Context, k8sClient client.Client) (string, error) {
+	proxy := &unstructured.Unstructured{}
+	proxy.SetGroupVersionKind(ProxyConfigGVK)
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: "cluster"}, proxy); err != nil {
+		if isMissing(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	name, _, _ := unstructured.NestedString(proxy.Object, "spec", "trustedCA", "name")
+	if name == "" {
+		return "", nil
+	}
+	cm := &corev1.ConfigMap{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: name, Namespace: openShiftConfigNamespace},
+		cm,
+	); err != nil {
+		return "", fmt.Errorf("failed to get trusted CA bundle ConfigMap %s - %w", name, err)
+	}
+	return cm.Data[v1alpha1.TrustedCABundleKey], nil
+}
+
+// CheckRequirements returns an error describing why the hub does not satisfy the requirements
+func CheckRequirements(requirements *v1alpha1.HubRequirements, versions HubVersions) error {
+	if requirements == nil {
+		return nil
+	}
+	if err := checkVersion("OpenShift", requirements.OpenShiftVersion, versions.OpenShift); err != nil {
+		return err
+	}
+	return checkVersion("multicluster engine", requirements.MCEVersion, versions.MCE)
+}
+
+func checkVersion(name string, constraint string, version string) error {
+	if constraint == "" {
+		return nil
+	}
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return fmt.Errorf("invalid %s version constraint '%s' - %w", name, constraint, err)
+	}
+	if version == "" {
+		return fmt.Errorf("%s version %s is required, but it was not detected on the hub", name, constraint)
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s version '%s' - %w", name, version, err)
+	}
+	if !c.Check(v) {
+		return fmt.Errorf("%s version %s is required, hub runs %s", name, constraint, version)
+	}
+	return nil
+}
+
+func isMissing(err error) bool {
+	return apierrors.IsNotFound(err) || meta.IsNoMatchError(err)
+}
@@ -0,0 +1,102 @@
+package hubversion
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Hub version", func() {
+	versions := HubVersions{
+		OpenShift: "4.12.3",
+		MCE:       "2.2.0",
+	}
+	It("Accepts missing requirements", func() {
+		Expect(CheckRequirements(nil, HubVersions{})).Should(Succeed())
+		Expect(CheckRequirements(&v1alpha1.HubRequirements{}, HubVersions{})).Should(Succeed())
+	})
+	It("Accepts satisfied requirements", func() {
+		requirements := &v1alpha1.HubRequirements{
+			OpenShiftVersion: ">= 4.12",
+			MCEVersion:       ">= 2.2",
+		}
+		Expect(CheckRequirements(requirements, versions)).Should(Succeed())
+	})
+	It("Rejects older hub", func() {
+		requirements := &v1alpha1.HubRequirements{
+			OpenShiftVersion: ">= 4.13",
+		}
+		err := CheckRequirements(requirements, versions)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("OpenShift version >= 4.13 is required, hub runs 4.12.3"))
+	})
+	It("Rejects hub without multicluster engine", func() {
+		requirements := &v1alpha1.HubRequirements{
+			MCEVersion: ">= 2.2",
+		}
+		err := CheckRequirements(requirements, HubVersions{OpenShift: "4.12.3"})
+		Expect(err).Should(HaveOccurred())
+	})
+	It("Rejects invalid constraint", func() {
+		requirements := &v1alpha1.HubRequirements{
+			OpenShiftVersion: "foo",
+		}
+		Expect(CheckRequirements(requirements, versions)).ShouldNot(Succeed())
+	})
+	It("Detects ingress domain", func() {
+		domain, err := GetIngressDomain(context.TODO(), fake.NewFakeClientWithScheme(scheme.Scheme))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(domain).Should(BeEmpty())
+
+		ingress := &unstructured.Unstructured{}
+		ingress.SetGroupVersionKind(IngressConfigGVK)
+		ingress.SetName("cluster")
+		Expect(unstructured.SetNestedField(
+			ingress.Object,
+			"apps.hub.example.com",
+			"spec",
+			"domain",
+		)).Should(Succeed())
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, ingress)
+		domain, err = GetIngressDomain(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(domain).Should(Equal("apps.hub.example.com"))
+	})
+	It("Detects trusted CA bundle", func() {
+		bundle, err := GetTrustedCABundle(context.TODO(), fake.NewFakeClientWithScheme(scheme.Scheme))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(bundle).Should(BeEmpty())
+
+		proxy := &unstructured.Unstructured{}
+		proxy.SetGroupVersionKind(ProxyConfigGVK)
+		proxy.SetName("cluster")
+		Expect(unstructured.SetNestedField(
+			proxy.Object,
+			"user-ca-bundle",
+			"spec",
+			"trustedCA",
+			"name",
+		)).Should(Succeed())
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, proxy)
+		_, err = GetTrustedCABundle(context.TODO(), k8sClient)
+		Expect(err).Should(HaveOccurred())
+
+		Expect(k8sClient.Create(context.TODO(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "user-ca-bundle",
+				Namespace: "openshift-config",
+			},
+			Data: map[string]string{v1alpha1.TrustedCABundleKey: "-----BEGIN CERTIFICATE-----"},
+		})).Should(Succeed())
+		bundle, err = GetTrustedCABundle(context.TODO(), k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(bundle).Should(Equal("-----BEGIN CERTIFICATE-----"))
+	})
+})
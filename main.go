@@ -19,6 +19,7 @@ package main
 import (
 	"flag"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -28,6 +29,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
@@ -67,6 +69,11 @@ func main() {
 	var tlsCertFile string
 	var tlsKeyFile string
 	var probeAddr string
+	var helmIndexCacheTTL time.Duration
+	var helmMaxConcurrentDownloads int
+	var leaseDuration time.Duration
+	var renewDeadline time.Duration
+	var retryPeriod time.Duration
 	flag.StringVar(
 		&metricsAddr,
 		"metrics-bind-address",
@@ -82,8 +89,41 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.DurationVar(
+		&leaseDuration,
+		"leader-elect-lease-duration",
+		15*time.Second,
+		"How long non-leader candidates wait before acquiring leadership of a lease which was "+
+			"not renewed. Shorter duration speeds up failover of a crashed leader.",
+	)
+	flag.DurationVar(
+		&renewDeadline,
+		"leader-elect-renew-deadline",
+		10*time.Second,
+		"How long the leader retries to renew the lease before giving up the leadership. "+
+			"Has to be shorter than the lease duration.",
+	)
+	flag.DurationVar(
+		&retryPeriod,
+		"leader-elect-retry-period",
+		2*time.Second,
+		"How long candidates wait between attempts to acquire or renew the lease.",
+	)
 	flag.StringVar(&tlsCertFile, "tls-cert-file", "", "TLS certificate for repo proxy")
 	flag.StringVar(&tlsKeyFile, "tls-private-key-file", "", "TLS private key for repo proxy")
+	flag.DurationVar(
+		&helmIndexCacheTTL,
+		"helm-index-cache-ttl",
+		5*time.Minute,
+		"How long are helm repository index files cached. Set to 0 to disable the cache.",
+	)
+	flag.IntVar(
+		&helmMaxConcurrentDownloads,
+		"helm-max-concurrent-downloads",
+		10,
+		"How many helm charts are downloaded at once. Concurrent downloads of the same chart are "+
+			"shared. Set to 0 to download charts directly within each reconcile.",
+	)
 	opts := zap.Options{
 		Development: true,
 	}
@@ -101,17 +141,17 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "135184d5.openshift.io",
-		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
-		// when the Manager ends. This requires the binary to immediately end when the
-		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
-		// speeds up voluntary leader transitions as the new leader don't have to wait
-		// LeaseDuration time first.
-		//
-		// In the default scaffold provided, the program ends immediately after
-		// the manager stops, so would be fine to enable this option. However,
-		// if you are doing or is intended to do any operation such as perform cleanups
-		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
+		LeaseDuration:          &leaseDuration,
+		RenewDeadline:          &renewDeadline,
+		RetryPeriod:            &retryPeriod,
+		// The leader steps down when the manager ends (ie the pod is terminated during a rolling
+		// update), so the other replica does not wait for the lease to expire. Safe as the
+		// program ends right after the manager stops.
+		LeaderElectionReleaseOnCancel: true,
+		// Deployments and PodDisruptionBudgets are managed in the namespace of the operator only
+		NewCache: cache.BuilderWithOptions(cache.Options{
+			SelectorsByObject: controllers.GetOperatorCacheSelectors(),
+		}),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -119,6 +159,12 @@ func main() {
 	}
 
 	helmClient := helm.NewHelmClient(config, mgr.GetClient(), nil, nil, nil)
+	if helmIndexCacheTTL > 0 {
+		helmClient.IndexCache = helm.NewIndexCache(helmIndexCacheTTL)
+	}
+	if helmMaxConcurrentDownloads > 0 {
+		helmClient.Downloader = helm.NewDownloader(helmMaxConcurrentDownloads)
+	}
 
 	if err = (&controllers.ClusterTemplateQuotaReconciler{
 		Client: mgr.GetClient(),
@@ -128,6 +174,40 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&controllers.ClusterTemplateInstanceCleanupReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(
+			err,
+			"unable to create controller",
+			"controller",
+			"ClusterTemplateInstanceCleanup",
+		)
+		os.Exit(1)
+	}
+
+	if err = (&controllers.ClusterTemplateInstanceSupportBundleReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(
+			err,
+			"unable to create controller",
+			"controller",
+			"ClusterTemplateInstanceSupportBundle",
+		)
+		os.Exit(1)
+	}
+
+	if err = (&controllers.ClusterSetupDefinitionReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterSetupDefinition")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.ClusterTemplateReconciler{
 		Client:     mgr.GetClient(),
 		Scheme:     mgr.GetScheme(),
@@ -138,8 +218,9 @@ func main() {
 	}
 
 	if err = (&controllers.CLaaSReconciler{
-		Client:  mgr.GetClient(),
-		Manager: mgr,
+		Client:     mgr.GetClient(),
+		Manager:    mgr,
+		HelmClient: helmClient,
 	}).SetupWithManager(); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "CLaaS")
 		os.Exit(1)
@@ -153,7 +234,42 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&controllers.OperatorDeploymentReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OperatorDeployment")
+		os.Exit(1)
+	}
+	if err := mgr.AddMetricsExtraHandler(
+		"/debug/pprof/",
+		controllers.GetProfilingHandler(),
+	); err != nil {
+		setupLog.Error(err, "unable to set up profiling endpoint")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.ClusterCredentialRequestReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterCredentialRequest")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.HelmRepositoryReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HelmRepository")
+		os.Exit(1)
+	}
+
 	if os.Getenv("DISABLE_WEBHOOKS") == "" {
+		if err = (&v1alpha1.ClusterTemplate{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterTemplate")
+			os.Exit(1)
+		}
 		if err = (&v1alpha1.ClusterTemplateQuota{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterTemplateQuota")
 			os.Exit(1)
@@ -162,6 +278,14 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterTemplateInstance")
 			os.Exit(1)
 		}
+		if err = (&v1alpha1.ClusterCredentialRequest{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterCredentialRequest")
+			os.Exit(1)
+		}
+		if err = (&v1alpha1.ClusterTemplateInstanceCleanup{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterTemplateInstanceCleanup")
+			os.Exit(1)
+		}
 	}
 
 	//+kubebuilder:scaffold:builder
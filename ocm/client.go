@@ -0,0 +1,254 @@
+package ocm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultURL is the URL of the OCM API of console.redhat.com
+	DefaultURL = "https://api.openshift.com"
+	// DefaultTokenURL is the token endpoint of the Red Hat SSO
+	DefaultTokenURL = "https://sso.redhat.com/auth/realms/redhat-external/protocol/openid-connect/token"
+
+	// keys of the credentials Secret - either the client ID and secret of a service account or an
+	// offline token of a user
+	ClientIDKey     = "clientID"
+	ClientSecretKey = "clientSecret"
+	OfflineTokenKey = "offlineToken"
+
+	// SSO client of offline tokens issued by console.redhat.com
+	offlineTokenClientID = "cloud-services"
+	clustersPath         = "/api/clusters_mgmt/v1/clusters"
+)
+
+// states of OCM clusters
+const (
+	ClusterStateReady        = "ready"
+	ClusterStateError        = "error"
+	ClusterStateUninstalling = "uninstalling"
+)
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// ErrNotFound is returned when the OCM API responds by 404
+var ErrNotFound = errors.New("not found")
+
+// Client of the clusters management API of OCM
+type Client struct {
+	url      string
+	tokenURL string
+	form     url.Values
+}
+
+type Cluster struct {
+	ID               string        `json:"id"`
+	Name             string        `json:"name"`
+	State            string        `json:"state"`
+	OpenShiftVersion string        `json:"openshift_version,omitempty"`
+	API              ClusterURL    `json:"api,omitempty"`
+	Console          ClusterURL    `json:"console,omitempty"`
+	Status           ClusterStatus `json:"status,omitempty"`
+}
+
+type ClusterURL struct {
+	URL string `json:"url,omitempty"`
+}
+
+type ClusterStatus struct {
+	Description           string `json:"description,omitempty"`
+	ProvisionErrorMessage string `json:"provision_error_message,omitempty"`
+}
+
+// ClusterCredentials are the admin credentials of the cluster, OCM provides them only for some
+// clusters (ie not for ROSA clusters using AWS STS)
+type ClusterCredentials struct {
+	Kubeconfig string     `json:"kubeconfig,omitempty"`
+	Admin      AdminCreds `json:"admin,omitempty"`
+}
+
+type AdminCreds struct {
+	User     string `json:"user,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+type clusterList struct {
+	Items []Cluster `json:"items"`
+}
+
+type apiError struct {
+	Reason string `json:"reason"`
+}
+
+// NewClient returns client of the OCM API authenticated by the credentials read from a Secret.
+// Empty URLs default to the OCM API and the SSO of console.redhat.com.
+func NewClient(apiURL string, tokenURL string, credentials map[string][]byte) (*Client, error) {
+	if apiURL == "" {
+		apiURL = DefaultURL
+	}
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+	form := url.Values{}
+	if offlineToken := credentials[OfflineTokenKey]; len(offlineToken) > 0 {
+		form.Set("grant_type", "refresh_token")
+		form.Set("client_id", offlineTokenClientID)
+		form.Set("refresh_token", string(offlineToken))
+	} else if len(credentials[ClientIDKey]) > 0 && len(credentials[ClientSecretKey]) > 0 {
+		form.Set("grant_type", "client_credentials")
+		form.Set("client_id", string(credentials[ClientIDKey]))
+		form.Set("client_secret", string(credentials[ClientSecretKey]))
+	} else {
+		return nil, fmt.Errorf(
+			"credentials have neither '%s' nor '%s' and '%s'",
+			OfflineTokenKey,
+			ClientIDKey,
+			ClientSecretKey,
+		)
+	}
+	return &Client{
+		url:      strings.TrimSuffix(apiURL, "/"),
+		tokenURL: tokenURL,
+		form:     form,
+	}, nil
+}
+
+// FindCluster returns the cluster of the name, nil if it does not exist. Names of clusters are
+// unique within the OCM organization.
+func (c *Client) FindCluster(ctx context.Context, name string) (*Cluster, error) {
+	query := url.Values{}
+	query.Set("search", fmt.Sprintf("name = '%s'", name))
+	list := clusterList{}
+	if err := c.do(ctx, http.MethodGet, clustersPath+"?"+query.Encode(), nil, &list); err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+	return &list.Items[0], nil
+}
+
+// CreateCluster creates cluster of the properties, which are passed to OCM as they are
+func (c *Client) CreateCluster(
+	ctx context.Context,
+	properties map[string]interface{},
+) (*Cluster, error) {
+	cluster := &Cluster{}
+	if err := c.do(ctx, http.MethodPost, clustersPath, properties, cluster); err != nil {
+		return nil, err
+	}
+	return cluster, nil
+}
+
+// DeleteCluster starts uninstallation of the cluster, deleted clusters are ignored
+func (c *Client) DeleteCluster(ctx context.Context, id string) error {
+	err := c.do(ctx, http.MethodDelete, clustersPath+"/"+id, nil, nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// GetCredentials returns admin credentials of the cluster
+func (c *Client) GetCredentials(ctx context.Context, id string) (*ClusterCredentials, error) {
+	credentials := &ClusterCredentials{}
+	path := clustersPath + "/" + id + "/credentials"
+	if err := c.do(ctx, http.MethodGet, path, nil, credentials); err != nil {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+func (c *Client) do(
+	ctx context.Context,
+	method string,
+	path string,
+	body interface{},
+	result interface{},
+) error {
+	token, err := c.getToken(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to authenticate to OCM - %w", err)
+	}
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %s - %w", method, path, ErrNotFound)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := apiError{}
+		err := fmt.Errorf("%s %s responded with status code %d", method, path, resp.StatusCode)
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Reason != "" {
+			return fmt.Errorf("%w - %s", err, apiErr.Reason)
+		}
+		return err
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+// getToken exchanges the credentials for an access token. Tokens are short-lived and the operator
+// talks to OCM only while clusters install, so a new token is requested for every call.
+func (c *Client) getToken(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		c.tokenURL,
+		strings.NewReader(c.form.Encode()),
+	)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s responded with status code %d", c.tokenURL, resp.StatusCode)
+	}
+	token := struct {
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("%s returned no access token", c.tokenURL)
+	}
+	return token.AccessToken, nil
+}
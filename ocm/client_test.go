@@ -0,0 +1,135 @@
+package ocm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OCM client", func() {
+	var server *httptest.Server
+	var clusters map[string]Cluster
+	var created map[string]interface{}
+
+	BeforeEach(func() {
+		clusters = map[string]Cluster{}
+		created = nil
+		mux := http.NewServeMux()
+		mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.ParseForm()).Should(Succeed())
+			if r.PostForm.Get("client_secret") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"token"}`))
+		})
+		mux.HandleFunc(clustersPath, func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Authorization")).Should(Equal("Bearer token"))
+			switch r.Method {
+			case http.MethodGet:
+				list := clusterList{Items: []Cluster{}}
+				for _, cluster := range clusters {
+					if r.URL.Query().Get("search") == "name = '"+cluster.Name+"'" {
+						list.Items = append(list.Items, cluster)
+					}
+				}
+				Expect(json.NewEncoder(w).Encode(list)).Should(Succeed())
+			case http.MethodPost:
+				Expect(json.NewDecoder(r.Body).Decode(&created)).Should(Succeed())
+				if created["name"] == "invalid" {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"kind":"Error","reason":"Region is not supported"}`))
+					return
+				}
+				cluster := Cluster{ID: "id-1", Name: created["name"].(string), State: "pending"}
+				clusters[cluster.ID] = cluster
+				w.WriteHeader(http.StatusCreated)
+				Expect(json.NewEncoder(w).Encode(cluster)).Should(Succeed())
+			}
+		})
+		mux.HandleFunc(clustersPath+"/id-1", func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).Should(Equal(http.MethodDelete))
+			if _, ok := clusters["id-1"]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(clusters, "id-1")
+			w.WriteHeader(http.StatusNoContent)
+		})
+		mux.HandleFunc(clustersPath+"/id-1/credentials", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"kubeconfig":"kubeconfig","admin":{"user":"kubeadmin","password":"pass"}}`))
+		})
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newClient := func(secret string) *Client {
+		client, err := NewClient(server.URL, server.URL+"/token", map[string][]byte{
+			ClientIDKey:     []byte("claas"),
+			ClientSecretKey: []byte(secret),
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		return client
+	}
+
+	It("Requires credentials", func() {
+		_, err := NewClient("", "", map[string][]byte{ClientIDKey: []byte("claas")})
+		Expect(err).Should(MatchError(
+			"credentials have neither 'offlineToken' nor 'clientID' and 'clientSecret'",
+		))
+		client, err := NewClient("", "", map[string][]byte{OfflineTokenKey: []byte("offline")})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(client.url).Should(Equal(DefaultURL))
+		Expect(client.form.Get("grant_type")).Should(Equal("refresh_token"))
+	})
+
+	It("Manages clusters", func() {
+		client := newClient("secret")
+		cluster, err := client.FindCluster(context.TODO(), "claas-1")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(cluster).Should(BeNil())
+
+		cluster, err = client.CreateCluster(context.TODO(), map[string]interface{}{"name": "claas-1"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(cluster.ID).Should(Equal("id-1"))
+		Expect(created).Should(HaveKeyWithValue("name", "claas-1"))
+
+		cluster, err = client.FindCluster(context.TODO(), "claas-1")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(cluster.State).Should(Equal("pending"))
+
+		credentials, err := client.GetCredentials(context.TODO(), "id-1")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(credentials.Kubeconfig).Should(Equal("kubeconfig"))
+		Expect(credentials.Admin.Password).Should(Equal("pass"))
+
+		Expect(client.DeleteCluster(context.TODO(), "id-1")).Should(Succeed())
+		// already deleted
+		Expect(client.DeleteCluster(context.TODO(), "id-1")).Should(Succeed())
+		_, err = client.GetCredentials(context.TODO(), "id-2")
+		Expect(errors.Is(err, ErrNotFound)).Should(BeTrue())
+	})
+
+	It("Reports errors", func() {
+		_, err := newClient("secret").CreateCluster(
+			context.TODO(),
+			map[string]interface{}{"name": "invalid"},
+		)
+		Expect(err).Should(MatchError(
+			"POST " + clustersPath + " responded with status code 400 - Region is not supported",
+		))
+
+		_, err = newClient("wrong").FindCluster(context.TODO(), "claas-1")
+		Expect(err).Should(MatchError(
+			"failed to authenticate to OCM - " + server.URL + "/token responded with status code 401",
+		))
+	})
+})
@@ -32,7 +32,7 @@ func handlerFunc(w http.ResponseWriter, r *http.Request) {
 	} else {
 		data, err := os.ReadFile("../testutils/helm/" + r.URL.Path)
 		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(http.StatusNotFound)
 			return
 		}
 		w.WriteHeader(http.StatusOK)